@@ -17,7 +17,7 @@ import (
 // XORing a src byte-slice with cryptographic random bits to yield dst bytes,
 // and concurrently absorbing bytes from a key byte-slice into its state:
 //
-//     cipher.Message(dst, src, key) Cipher
+//	cipher.Message(dst, src, key) Cipher
 //
 // A call always processes exactly max(len(dst),len(dst),len(key)) bytes.
 // All slice arguments may be nil or of varying lengths.
@@ -29,8 +29,8 @@ import (
 // so that the following sequence of two calls yields a result
 // that is always cryptographically distinct from the above single call.
 //
-//     cipher.Message(dst[:div], src[:div], key[:div])
-//     cipher.Message(dst[div:], src[div:], key[div:])
+//	cipher.Message(dst[:div], src[:div], key[:div])
+//	cipher.Message(dst[div:], src[div:], key[div:])
 //
 // The cipher guarantees that any key material absorbed during a given call
 // will cryptographically affect every bit of all future messages processed,
@@ -102,7 +102,6 @@ import (
 // only on the Cipher's initial state in the following fashion:
 //
 //	cipher.Partial(dst, nil, nil)
-//
 type CipherState interface {
 
 	// Transform a message (or the final portion of one) from src to dst,
@@ -167,7 +166,6 @@ var RandomKey []byte = nil
 // whose interpretation is specific to the particular cipher.
 // (XXX may reconsider the wisdom of this options convention;
 // its lack of type-checking has led to accidental confusion at least once.)
-//
 type Cipher struct {
 	CipherState // underlying message cipher implementation
 }
@@ -196,7 +194,6 @@ type Cipher struct {
 // but makes no guarantees about whether key material absorbed in this call
 // will affect some, all, or none of the cryptographic pseudorandom bits
 // produced concurrently in the same call.
-//
 func (c Cipher) Message(dst, src, key []byte) Cipher {
 	c.CipherState.Message(dst, src, key)
 	return c
@@ -219,7 +216,6 @@ func (c Cipher) Message(dst, src, key []byte) Cipher {
 // Key material absorbed in a given Partial call may, or may not,
 // affect the pseudorandom bits generated in subsequent Partial calls
 // if there are no intervening calls to Message.
-//
 func (c Cipher) Partial(dst, src, key []byte) Cipher {
 	c.CipherState.Partial(dst, src, key)
 	return c
@@ -230,7 +226,6 @@ func (c Cipher) Partial(dst, src, key []byte) Cipher {
 // Consistent with the streaming semantics of the io.Reader interface,
 // two consecutive reads of length l1 and l2 produce the same bytes
 // as a single read of length l1+l2.
-//
 func (c Cipher) Read(dst []byte) (n int, err error) {
 	c.CipherState.Partial(dst, nil, nil)
 	return len(dst), nil
@@ -245,7 +240,6 @@ func (c Cipher) Read(dst []byte) (n int, err error) {
 // The caller should invoke EndMessage after a series of Write calls
 // to ensure that all written data is fully absorbed into the Cipher,
 // before reading Cipher output that is supposed to depend on the written data.
-//
 func (c Cipher) Write(key []byte) (n int, err error) {
 	c.CipherState.Partial(nil, nil, key)
 	return len(key), nil
@@ -255,7 +249,6 @@ func (c Cipher) Write(key []byte) (n int, err error) {
 // finalizing the message currently being processed and starting a new one.
 // The client should typically call EndMessage after a series of
 // calls to streaming methods such as Partial, Read, or Write.
-//
 func (c Cipher) EndMessage() {
 	c.CipherState.Message(nil, nil, nil) // finalize the current message
 }
@@ -274,7 +267,6 @@ func (c Cipher) EndMessage() {
 // For this reason, stream cipher operation is not recommended
 // in common-case situations in which authenticated encryption methods
 // (e.g., via Seal and Open) are applicable.
-//
 func (c Cipher) XORKeyStream(dst, src []byte) {
 	c.CipherState.Partial(dst[:len(src)], src, nil)
 }
@@ -288,7 +280,6 @@ func (c Cipher) XORKeyStream(dst, src []byte) {
 // Unlike the hash.Hash interface, this Sum method affects the Cipher's state:
 // two consecutive calls to Sum on the same Cipher
 // will produce two different hashes, not the same one.
-//
 func (c Cipher) Sum(dst []byte) []byte {
 	c.EndMessage() // finalize any message in progress
 
@@ -305,7 +296,6 @@ func (c Cipher) Sum(dst []byte) []byte {
 // Seal also absorbs the produced ciphertext into the Cipher's state,
 // then uses that state to append a message authentication check (MAC)
 // to the sealed message, to be verified by Open.
-//
 func (c Cipher) Seal(dst, src []byte) []byte {
 	l := len(src)    // message length
 	m := c.KeySize() // MAC length
@@ -324,7 +314,6 @@ func (c Cipher) Seal(dst, src []byte) []byte {
 // It decrypts sealed message src and appends it onto plaintext buffer dst,
 // growing the dst buffer if it is too small (or nil),
 // and returns the resulting destination buffer or an error.
-//
 func (c Cipher) Open(dst, src []byte) ([]byte, error) {
 	m := c.KeySize()
 	l := len(src) - m
@@ -124,7 +124,6 @@ type Constructor interface {
 // (the encoding supports no transmission of length metadata).
 //
 // XXX move this and Constructor to some other, more generic package
-//
 type BinaryEncoding struct {
 	Constructor // Constructor for instantiating abstract types
 
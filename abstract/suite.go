@@ -22,6 +22,14 @@ import (
 // While stream ciphers can be and often are constructed from block ciphers,
 // we treat block ciphers as an implementation detail
 // hidden below the abstraction level of this ciphersuite interface.
+//
+// A Suite is immutable once constructed and safe for concurrent use by
+// multiple goroutines: every call to Cipher, Hash, Point or Scalar returns a
+// fresh object, and implementations must not keep mutable state shared
+// between these objects. The returned Ciphers, Hashes, Points and Scalars
+// themselves are not safe for concurrent use; each goroutine should create
+// its own. The test package provides TestSuiteConcurrent to check a suite
+// against this guarantee.
 type Suite interface {
 
 	// Create a cryptographic Cipher with a given key and configuration.
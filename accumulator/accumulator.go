@@ -0,0 +1,306 @@
+// Package accumulator implements a dynamic RSA accumulator with membership
+// and non-membership witnesses. The accumulator commits to a set of byte
+// strings in a single group element, e.g. to a revocation list of credentials
+// or keys, and anybody can check with a constant-size witness that an element
+// is, or is not, in the committed set.
+//
+// Elements are hashed to primes e and the accumulator value is G^(prod e) mod
+// N. A membership witness for e is W with W^e = Value, and a non-membership
+// witness is a pair (A, D) with 0 <= A < e and Value^A = D^e G. The Manager
+// knows the factorization of N, which lets it remove elements and create
+// witnesses efficiently. Every change of the set produces an Update from
+// which witness holders update their witnesses without the trapdoor.
+//
+// Security relies on the strong RSA assumption, so only the manager may know
+// the factorization of N.
+//
+// For further background see:
+//
+//	"Dynamic Accumulators and Application to Efficient Revocation of Anonymous
+//	Credentials" by Jan Camenisch and Anna Lysyanskaya
+//	"Universal Accumulators with Efficient Nonmembership Proofs" by Jiangtao
+//	Li, Ninghui Li and Rui Xue
+package accumulator
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math/big"
+)
+
+// Some error definitions
+var errorMember = errors.New("element is a member")
+var errorNotMember = errors.New("element is not a member")
+var errorInvalidWitness = errors.New("invalid witness")
+var errorUpdate = errors.New("update does not apply to witness")
+
+// primeBits is the bit length of the primes representing elements.
+const primeBits = 256
+
+var one = big.NewInt(1)
+
+// Accumulator is the public state of an accumulator: the modulus N, the
+// generator G and the current value.
+type Accumulator struct {
+	N     *big.Int // RSA modulus of unknown factorization
+	G     *big.Int // Generator, a quadratic residue modulo N
+	Value *big.Int // Current accumulator value
+}
+
+// Update describes a change of the accumulated set.
+type Update struct {
+	Element []byte   // Added or deleted element
+	Deleted bool     // Whether the element was deleted
+	Prev    *big.Int // Accumulator value before the change
+	Value   *big.Int // Accumulator value after the change
+}
+
+// MemberWitness proves that Element is accumulated.
+type MemberWitness struct {
+	Element []byte
+	W       *big.Int
+}
+
+// NonMemberWitness proves that Element is not accumulated.
+type NonMemberWitness struct {
+	Element []byte
+	A       *big.Int
+	D       *big.Int
+}
+
+// Manager maintains an accumulator using the factorization of its modulus.
+type Manager struct {
+	acc     Accumulator
+	phi     *big.Int            // Euler's totient of N
+	members map[string]*big.Int // Primes of the accumulated elements
+}
+
+// NewManager creates the manager of an empty accumulator with a fresh
+// modulus of the given bit length, whose primes are read from r, which
+// defaults to crypto/rand.Reader if nil.
+func NewManager(r io.Reader, bits int) (*Manager, error) {
+	if r == nil {
+		r = rand.Reader
+	}
+	p, err := rand.Prime(r, bits/2)
+	if err != nil {
+		return nil, err
+	}
+	q, err := rand.Prime(r, bits-bits/2)
+	if err != nil {
+		return nil, err
+	}
+	N := new(big.Int).Mul(p, q)
+	phi := new(big.Int).Mul(new(big.Int).Sub(p, one), new(big.Int).Sub(q, one))
+
+	// Squaring a random unit yields a quadratic residue
+	var G *big.Int
+	for {
+		G, err = rand.Int(r, N)
+		if err != nil {
+			return nil, err
+		}
+		if G.Sign() > 0 && new(big.Int).GCD(nil, nil, G, N).Cmp(one) == 0 {
+			break
+		}
+	}
+	G.Mul(G, G).Mod(G, N)
+	return &Manager{
+		acc:     Accumulator{N: N, G: G, Value: new(big.Int).Set(G)},
+		phi:     phi,
+		members: make(map[string]*big.Int),
+	}, nil
+}
+
+// Accumulator returns a copy of the current public state.
+func (m *Manager) Accumulator() *Accumulator {
+	return &Accumulator{N: m.acc.N, G: m.acc.G, Value: new(big.Int).Set(m.acc.Value)}
+}
+
+// Add adds x to the accumulated set.
+func (m *Manager) Add(x []byte) (*Update, error) {
+	key := string(x)
+	if _, ok := m.members[key]; ok {
+		return nil, errorMember
+	}
+	e := hashPrime(x)
+	prev := m.acc.Value
+	m.acc.Value = new(big.Int).Exp(prev, e, m.acc.N)
+	m.members[key] = e
+	return &Update{Element: x, Prev: prev, Value: m.acc.Value}, nil
+}
+
+// Delete removes x from the accumulated set.
+func (m *Manager) Delete(x []byte) (*Update, error) {
+	key := string(x)
+	e, ok := m.members[key]
+	if !ok {
+		return nil, errorNotMember
+	}
+	prev := m.acc.Value
+	m.acc.Value = m.root(prev, e)
+	delete(m.members, key)
+	return &Update{Element: x, Deleted: true, Prev: prev, Value: m.acc.Value}, nil
+}
+
+// MemberWitness creates a membership witness for x.
+func (m *Manager) MemberWitness(x []byte) (*MemberWitness, error) {
+	e, ok := m.members[string(x)]
+	if !ok {
+		return nil, errorNotMember
+	}
+	return &MemberWitness{Element: x, W: m.root(m.acc.Value, e)}, nil
+}
+
+// NonMemberWitness creates a non-membership witness for x.
+func (m *Manager) NonMemberWitness(x []byte) (*NonMemberWitness, error) {
+	if _, ok := m.members[string(x)]; ok {
+		return nil, errorMember
+	}
+	e := hashPrime(x)
+	// u = prod of the member primes modulo phi, A = u^-1 mod e, and
+	// D = G^((A u - 1) / e), where the division is exact over the integers
+	// and hence can be computed modulo phi via the inverse of e.
+	u := big.NewInt(1)
+	uModE := big.NewInt(1)
+	for _, p := range m.members {
+		u.Mul(u, p).Mod(u, m.phi)
+		uModE.Mul(uModE, p).Mod(uModE, e)
+	}
+	A := new(big.Int).ModInverse(uModE, e)
+	if A == nil {
+		return nil, errorMember
+	}
+	exp := new(big.Int).Mul(A, u)
+	exp.Sub(exp, one)
+	exp.Mul(exp, new(big.Int).ModInverse(e, m.phi)).Mod(exp, m.phi)
+	return &NonMemberWitness{Element: x, A: A, D: new(big.Int).Exp(m.acc.G, exp, m.acc.N)}, nil
+}
+
+// root returns the e-th root of v.
+func (m *Manager) root(v, e *big.Int) *big.Int {
+	d := new(big.Int).ModInverse(e, m.phi)
+	return new(big.Int).Exp(v, d, m.acc.N)
+}
+
+// VerifyMember checks that the witness proves membership of its element.
+func (a *Accumulator) VerifyMember(w *MemberWitness) error {
+	if w == nil || !a.inGroup(w.W) {
+		return errorInvalidWitness
+	}
+	e := hashPrime(w.Element)
+	if new(big.Int).Exp(w.W, e, a.N).Cmp(a.Value) != 0 {
+		return errorInvalidWitness
+	}
+	return nil
+}
+
+// VerifyNonMember checks that the witness proves non-membership of its
+// element.
+func (a *Accumulator) VerifyNonMember(w *NonMemberWitness) error {
+	if w == nil || w.A == nil || !a.inGroup(w.D) {
+		return errorInvalidWitness
+	}
+	e := hashPrime(w.Element)
+	if w.A.Sign() < 0 || w.A.Cmp(e) >= 0 {
+		return errorInvalidWitness
+	}
+	lhs := new(big.Int).Exp(a.Value, w.A, a.N)
+	rhs := new(big.Int).Exp(w.D, e, a.N)
+	rhs.Mul(rhs, a.G).Mod(rhs, a.N)
+	if lhs.Cmp(rhs) != 0 {
+		return errorInvalidWitness
+	}
+	return nil
+}
+
+// Apply updates the membership witness w of an accumulator with modulus N
+// to the change u. It fails if the witness' own element is deleted.
+func (w *MemberWitness) Apply(N *big.Int, u *Update) error {
+	ex := hashPrime(w.Element)
+	ey := hashPrime(u.Element)
+	if ex.Cmp(ey) == 0 {
+		return errorUpdate
+	}
+	if !u.Deleted {
+		// W' = W^ey
+		w.W = new(big.Int).Exp(w.W, ey, N)
+		return nil
+	}
+	// With a ex + b ey = 1, W' = W^b Value'^a, since W'^ex = Value'^(b ey +
+	// a ex) for W = Value'^(ey / ex)
+	a, b := new(big.Int), new(big.Int)
+	new(big.Int).GCD(a, b, ex, ey)
+	W := expSigned(w.W, b, N)
+	W.Mul(W, expSigned(u.Value, a, N)).Mod(W, N)
+	w.W = W
+	return nil
+}
+
+// Apply updates the non-membership witness w of an accumulator with modulus
+// N to the change u. It fails if the witness' own element is added.
+func (w *NonMemberWitness) Apply(N *big.Int, u *Update) error {
+	ex := hashPrime(w.Element)
+	ey := hashPrime(u.Element)
+	if ex.Cmp(ey) == 0 {
+		return errorUpdate
+	}
+	// Value^A = D^ex G holds for the previous value. Find A' = A c - m ex
+	// with Value'^A' = Value^(A c') such that D' = D Prev^(A k) Value'^-m.
+	var c, k *big.Int
+	if !u.Deleted {
+		// Value' = Value^ey and c ey = 1 + k ex
+		c = new(big.Int).ModInverse(ey, ex)
+		k = new(big.Int).Mul(c, ey)
+		k.Sub(k, one).Div(k, ex)
+	} else {
+		// Value = Value'^ey, so Value^A = Value'^(A ey)
+		c = ey
+		k = new(big.Int)
+	}
+	Ac := new(big.Int).Mul(w.A, c)
+	m, A := new(big.Int).DivMod(Ac, ex, new(big.Int))
+	D := new(big.Int).Exp(u.Prev, new(big.Int).Mul(w.A, k), N)
+	D.Mul(D, w.D).Mod(D, N)
+	D.Mul(D, expSigned(u.Value, new(big.Int).Neg(m), N)).Mod(D, N)
+	w.A, w.D = A, D
+	return nil
+}
+
+// expSigned computes b^e mod N for a possibly negative exponent e.
+func expSigned(b, e, N *big.Int) *big.Int {
+	if e.Sign() >= 0 {
+		return new(big.Int).Exp(b, e, N)
+	}
+	inv := new(big.Int).ModInverse(b, N)
+	return inv.Exp(inv, new(big.Int).Neg(e), N)
+}
+
+func (a *Accumulator) inGroup(v *big.Int) bool {
+	if v == nil || v.Sign() <= 0 || v.Cmp(a.N) >= 0 {
+		return false
+	}
+	return new(big.Int).GCD(nil, nil, v, a.N).Cmp(one) == 0
+}
+
+// hashPrime maps an element to a prime of primeBits bits.
+func hashPrime(x []byte) *big.Int {
+	e := new(big.Int)
+	var ctr [8]byte
+	for i := uint64(0); ; i++ {
+		binary.BigEndian.PutUint64(ctr[:], i)
+		h := sha256.New()
+		h.Write([]byte("accumulator-prime"))
+		h.Write(ctr[:])
+		h.Write(x)
+		buf := h.Sum(nil)
+		buf[0] |= 0x80
+		e.SetBytes(buf)
+		if e.ProbablyPrime(20) {
+			return e
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package accumulator
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+)
+
+func newTestManager(t *testing.T) *Manager {
+	m, err := NewManager(nil, 512)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func TestAccumulator(t *testing.T) {
+	m := newTestManager(t)
+	for i := 0; i < 5; i++ {
+		if _, err := m.Add([]byte(fmt.Sprintf("key-%d", i))); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := m.Add([]byte("key-0")); err != errorMember {
+		t.Fatal("added element twice")
+	}
+	acc := m.Accumulator()
+
+	w, err := m.MemberWitness([]byte("key-3"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := acc.VerifyMember(w); err != nil {
+		t.Fatal(err)
+	}
+	nw, err := m.NonMemberWitness([]byte("revoked"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := acc.VerifyNonMember(nw); err != nil {
+		t.Fatal(err)
+	}
+
+	// Witnesses for the wrong statement
+	if _, err := m.MemberWitness([]byte("revoked")); err != errorNotMember {
+		t.Fatal("membership witness for non-member")
+	}
+	if _, err := m.NonMemberWitness([]byte("key-3")); err != errorMember {
+		t.Fatal("non-membership witness for member")
+	}
+	forged := &MemberWitness{Element: []byte("revoked"), W: w.W}
+	if err := acc.VerifyMember(forged); err == nil {
+		t.Fatal("forged membership witness verified")
+	}
+	forgedNon := &NonMemberWitness{Element: []byte("key-3"), A: nw.A, D: nw.D}
+	if err := acc.VerifyNonMember(forgedNon); err == nil {
+		t.Fatal("forged non-membership witness verified")
+	}
+	if err := acc.VerifyMember(&MemberWitness{Element: w.Element, W: new(big.Int).Add(w.W, one)}); err == nil {
+		t.Fatal("modified membership witness verified")
+	}
+}
+
+func TestAccumulatorUpdates(t *testing.T) {
+	m := newTestManager(t)
+	for _, x := range []string{"a", "b", "c"} {
+		if _, err := m.Add([]byte(x)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	w, err := m.MemberWitness([]byte("b"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	nw, err := m.NonMemberWitness([]byte("z"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ops := []struct {
+		x   string
+		del bool
+	}{{"d", false}, {"a", true}, {"e", false}, {"c", true}, {"a", false}}
+	for _, op := range ops {
+		var u *Update
+		if op.del {
+			u, err = m.Delete([]byte(op.x))
+		} else {
+			u, err = m.Add([]byte(op.x))
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := w.Apply(m.acc.N, u); err != nil {
+			t.Fatal(err)
+		}
+		if err := nw.Apply(m.acc.N, u); err != nil {
+			t.Fatal(err)
+		}
+		acc := m.Accumulator()
+		if err := acc.VerifyMember(w); err != nil {
+			t.Fatalf("membership witness after %v: %v", op, err)
+		}
+		if err := acc.VerifyNonMember(nw); err != nil {
+			t.Fatalf("non-membership witness after %v: %v", op, err)
+		}
+	}
+
+	// Deleting the witness' own element invalidates it
+	u, err := m.Delete([]byte("b"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Apply(m.acc.N, u); err != errorUpdate {
+		t.Fatal("applied deletion of own element")
+	}
+	if _, err := m.Delete([]byte("b")); err != errorNotMember {
+		t.Fatal("deleted element twice")
+	}
+	u, err = m.Add([]byte("z"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := nw.Apply(m.acc.N, u); err != errorUpdate {
+		t.Fatal("applied addition of own element")
+	}
+}
@@ -143,7 +143,6 @@ func decryptKey(suite abstract.Suite, ciphertext []byte, anonymitySet Set,
 // to anyone unable to decrypt the message.
 // The provided abstract.Suite must support
 // uniform-representation encoding of public keys for this to work.
-//
 func Encrypt(suite abstract.Suite, rand cipher.Stream, message []byte,
 	anonymitySet Set, hide bool) []byte {
 
@@ -179,7 +178,6 @@ func Encrypt(suite abstract.Suite, rand cipher.Stream, message []byte,
 // As a side-effect, this verification also ensures plaintext-awareness:
 // that is, it is infeasible for a sender to construct any ciphertext
 // that will be accepted by the receiver without knowing the plaintext.
-//
 func Decrypt(suite abstract.Suite, ciphertext []byte, anonymitySet Set,
 	mine int, privateKey abstract.Scalar, hide bool) ([]byte, error) {
 
@@ -10,7 +10,7 @@ import (
 	"github.com/dedis/crypto/nist"
 )
 
-func ExampleEncrypt_1() {
+func ExampleEncrypt_oneKey() {
 
 	// Crypto setup
 	suite := nist.NewAES128SHA256P256()
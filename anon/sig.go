@@ -106,7 +106,6 @@ func signH1(suite abstract.Suite, H1pre abstract.Cipher, PG, PH abstract.Point)
 // that members' private keys may later be compromised,
 // or that members may be persuaded or coerced into revealing whether or not
 // they produced a signature of interest.
-//
 func Sign(suite abstract.Suite, random cipher.Stream, message []byte,
 	anonymitySet Set, linkScope []byte, mine int, privateKey abstract.Scalar) []byte {
 
@@ -18,7 +18,7 @@ import (
 // producing traditional ElGamal signatures:
 // the resulting signatures are exactly the same length
 // and represent essentially the same computational cost.
-func ExampleSign_1() {
+func ExampleSign_oneKey() {
 
 	// Crypto setup
 	suite := nist.NewAES128SHA256P256()
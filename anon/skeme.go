@@ -19,7 +19,6 @@ import (
 // Once we have performed this key agreement, we can use more efficient
 // pairwise cryptographic primitives such as GCM authenticators,
 // which are not directly usable in multiparty contexts.
-//
 type SKEME struct {
 	suite    abstract.Suite
 	hide     bool
@@ -0,0 +1,239 @@
+// Package ca lets a committee holding Shamir shares of an Ed25519 signing key
+// run an X.509 certificate authority. Certificates are signed with the Ed25519
+// variant of FROST, so the committee key never exists in one place and the
+// issued certificates verify with any standard X.509 implementation.
+//
+// Issuing a certificate runs as follows:
+//
+//	csr, err := VerifyCSR(der)                        // check the request
+//	tbs, err := Prepare(template, parent, csr.PublicKey, Y)
+//	sess := NewSession(suite, pubPoly, tbs)           // coordinator
+//	// each signer: cert, err := ParseTBS(tbs) to review the certificate,
+//	// then frost.NewNonce and sess.AddCommitment
+//	// each signer: frost.SignEdDSA(suite, Y, share, nonce, tbs, sess.Commitments())
+//	// coordinator: sess.AddPartial for every partial signature
+//	sig, err := sess.Signature()
+//	cert, err := Assemble(tbs, sig, Y)
+//
+// If the committee can sign synchronously, Signer adapts a signing function
+// to crypto.Signer for use with x509.CreateCertificate instead.
+package ca
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"errors"
+	"io"
+	"sort"
+
+	"github.com/dedis/crypto/abstract"
+	"github.com/dedis/crypto/share"
+	"github.com/dedis/crypto/sign/frost"
+)
+
+// Some error definitions
+var errorKey = errors.New("committee key is not an Ed25519 key")
+var errorHash = errors.New("Ed25519 signs the message without prehashing")
+var errorPrepare = errors.New("no certificate to be signed was produced")
+var errorSession = errors.New("signing set already fixed")
+var errorCommitment = errors.New("duplicate or unknown signer")
+var errorIncomplete = errors.New("missing partial signatures")
+var errorSignature = errors.New("invalid committee signature")
+
+// oidEd25519 identifies Ed25519 signatures and keys, see RFC 8410.
+var oidEd25519 = asn1.ObjectIdentifier{1, 3, 101, 112}
+
+// PublicKey returns the committee key Y as an Ed25519 public key.
+func PublicKey(Y abstract.Point) (ed25519.PublicKey, error) {
+	buf, err := Y.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	if len(buf) != ed25519.PublicKeySize {
+		return nil, errorKey
+	}
+	return ed25519.PublicKey(buf), nil
+}
+
+// VerifyCSR decodes a DER-encoded certificate signing request and checks
+// that it is signed by the key it requests a certificate for.
+func VerifyCSR(der []byte) (*x509.CertificateRequest, error) {
+	csr, err := x509.ParseCertificateRequest(der)
+	if err != nil {
+		return nil, err
+	}
+	if err := csr.CheckSignature(); err != nil {
+		return nil, err
+	}
+	return csr, nil
+}
+
+// Signer adapts a function computing Ed25519 signatures under the committee
+// key, e.g. by running FROST among the committee, to crypto.Signer.
+type Signer struct {
+	Key      ed25519.PublicKey                // Committee key
+	SignFunc func(msg []byte) ([]byte, error) // Signing function
+}
+
+// Public returns the committee key.
+func (s *Signer) Public() crypto.PublicKey {
+	return s.Key
+}
+
+// Sign signs msg with the signing function. As for ed25519.PrivateKey, the
+// message must not be hashed, i.e., opts.HashFunc() must be zero.
+func (s *Signer) Sign(rand io.Reader, msg []byte, opts crypto.SignerOpts) ([]byte, error) {
+	if opts.HashFunc() != 0 {
+		return nil, errorHash
+	}
+	return s.SignFunc(msg)
+}
+
+// Prepare returns the DER encoding of the to-be-signed part of the
+// certificate for the subject key pub described by template, issued by
+// parent whose key must be the committee key Y. If parent is nil, the
+// certificate is a self-signed certificate of the committee key.
+func Prepare(template, parent *x509.Certificate, pub crypto.PublicKey, Y abstract.Point) ([]byte, error) {
+	key, err := PublicKey(Y)
+	if err != nil {
+		return nil, err
+	}
+	if parent == nil {
+		parent = template
+	}
+	// Let x509 encode the certificate and capture the bytes it signs
+	var tbs []byte
+	capture := &Signer{Key: key, SignFunc: func(msg []byte) ([]byte, error) {
+		tbs = append([]byte(nil), msg...)
+		return nil, errorPrepare
+	}}
+	if _, err := x509.CreateCertificate(rand.Reader, template, parent, pub, capture); tbs == nil {
+		return nil, err
+	}
+	return tbs, nil
+}
+
+// certificate is the ASN.1 structure of an X.509 certificate.
+type certificate struct {
+	TBSCertificate     asn1.RawValue
+	SignatureAlgorithm pkix.AlgorithmIdentifier
+	SignatureValue     asn1.BitString
+}
+
+func assemble(tbs, sig []byte) ([]byte, error) {
+	return asn1.Marshal(certificate{
+		TBSCertificate:     asn1.RawValue{FullBytes: tbs},
+		SignatureAlgorithm: pkix.AlgorithmIdentifier{Algorithm: oidEd25519},
+		SignatureValue:     asn1.BitString{Bytes: sig, BitLength: 8 * len(sig)},
+	})
+}
+
+// ParseTBS decodes the to-be-signed part of a certificate, so that signers
+// can review the certificate before signing it. The signature of the
+// returned certificate is empty.
+func ParseTBS(tbs []byte) (*x509.Certificate, error) {
+	der, err := assemble(tbs, make([]byte, ed25519.SignatureSize))
+	if err != nil {
+		return nil, err
+	}
+	return x509.ParseCertificate(der)
+}
+
+// Assemble combines the to-be-signed part of a certificate with the
+// committee's signature into the final certificate and checks the signature
+// against the committee key Y.
+func Assemble(tbs, sig []byte, Y abstract.Point) (*x509.Certificate, error) {
+	key, err := PublicKey(Y)
+	if err != nil {
+		return nil, err
+	}
+	if !ed25519.Verify(key, tbs, sig) {
+		return nil, errorSignature
+	}
+	der, err := assemble(tbs, sig)
+	if err != nil {
+		return nil, err
+	}
+	return x509.ParseCertificate(der)
+}
+
+// Session collects the nonce commitments and partial signatures of the
+// committee for one certificate.
+type Session struct {
+	suite    abstract.Suite
+	pubPoly  *share.PubPoly
+	tbs      []byte
+	commits  map[int]*frost.Commitment
+	fixed    []*frost.Commitment
+	partials map[int]*frost.Partial
+}
+
+// NewSession creates the signing session of the to-be-signed certificate tbs
+// for the committee with public polynomial pubPoly.
+func NewSession(suite abstract.Suite, pubPoly *share.PubPoly, tbs []byte) *Session {
+	return &Session{
+		suite:    suite,
+		pubPoly:  pubPoly,
+		tbs:      tbs,
+		commits:  make(map[int]*frost.Commitment),
+		partials: make(map[int]*frost.Partial),
+	}
+}
+
+// AddCommitment adds a signer's nonce commitment to the signing set.
+func (s *Session) AddCommitment(c *frost.Commitment) error {
+	if s.fixed != nil {
+		return errorSession
+	}
+	if c == nil || c.I < 0 || s.commits[c.I] != nil {
+		return errorCommitment
+	}
+	s.commits[c.I] = c
+	return nil
+}
+
+// Commitments fixes the signing set and returns the commitments the signers
+// sign with. No commitments can be added afterwards.
+func (s *Session) Commitments() []*frost.Commitment {
+	if s.fixed == nil {
+		signers := make([]int, 0, len(s.commits))
+		for i := range s.commits {
+			signers = append(signers, i)
+		}
+		sort.Ints(signers)
+		for _, i := range signers {
+			s.fixed = append(s.fixed, s.commits[i])
+		}
+	}
+	return s.fixed
+}
+
+// AddPartial verifies the partial signature of a signer of the signing set
+// and keeps it. Invalid partial signatures are reported as frost.BlameError.
+func (s *Session) AddPartial(p *frost.Partial) error {
+	if s.fixed == nil || p == nil || s.commits[p.I] == nil {
+		return errorCommitment
+	}
+	if err := frost.VerifyPartialEdDSA(s.suite, s.pubPoly, s.tbs, s.fixed, p); err != nil {
+		return err
+	}
+	s.partials[p.I] = p
+	return nil
+}
+
+// Signature returns the committee's signature once all signers of the
+// signing set have contributed valid partial signatures.
+func (s *Session) Signature() ([]byte, error) {
+	if s.fixed == nil || len(s.partials) != len(s.fixed) {
+		return nil, errorIncomplete
+	}
+	partials := make([]*frost.Partial, 0, len(s.partials))
+	for _, p := range s.partials {
+		partials = append(partials, p)
+	}
+	return frost.AggregateEdDSA(s.suite, s.pubPoly, s.tbs, s.fixed, partials)
+}
@@ -0,0 +1,157 @@
+package ca
+
+import (
+	stded25519 "crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/dedis/crypto/ed25519"
+	"github.com/dedis/crypto/random"
+	"github.com/dedis/crypto/share"
+	"github.com/dedis/crypto/sign/frost"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var suite = ed25519.NewAES128SHA256Ed25519(false)
+
+// issue runs the signing session of tbs among the given signers.
+func issue(t *testing.T, shares []*share.PriShare, pubPoly *share.PubPoly, signers []int, tbs []byte) []byte {
+	_, err := ParseTBS(tbs)
+	require.Nil(t, err)
+	sess := NewSession(suite, pubPoly, tbs)
+	nonces := make(map[int]*frost.Nonce)
+	for _, i := range signers {
+		var c *frost.Commitment
+		nonces[i], c = frost.NewNonce(suite, i, random.Stream)
+		require.Nil(t, sess.AddCommitment(c))
+	}
+	commits := sess.Commitments()
+	for _, i := range signers {
+		p, err := frost.SignEdDSA(suite, pubPoly.Commit(), shares[i], nonces[i], tbs, commits)
+		require.Nil(t, err)
+		require.Nil(t, sess.AddPartial(p))
+	}
+	sig, err := sess.Signature()
+	require.Nil(t, err)
+	return sig
+}
+
+func TestCA(t *testing.T) {
+	n, th := 5, 3
+	priPoly := share.NewPriPoly(suite, th, nil, random.Stream)
+	shares, pubPoly := priPoly.Shares(n), priPoly.Commit(nil)
+	Y := pubPoly.Commit()
+	key, err := PublicKey(Y)
+	require.Nil(t, err)
+
+	// Self-signed root of the committee
+	now := time.Now()
+	rootTmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "committee root"},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	tbs, err := Prepare(rootTmpl, nil, key, Y)
+	require.Nil(t, err)
+	root, err := Assemble(tbs, issue(t, shares, pubPoly, []int{0, 2, 4}, tbs), Y)
+	require.Nil(t, err)
+	require.Nil(t, root.CheckSignatureFrom(root))
+
+	// Leaf certificate from a CSR
+	leafPub, leafPriv, err := stded25519.GenerateKey(rand.Reader)
+	require.Nil(t, err)
+	der, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+		Subject:  pkix.Name{CommonName: "node.example.org"},
+		DNSNames: []string{"node.example.org"},
+	}, leafPriv)
+	require.Nil(t, err)
+	csr, err := VerifyCSR(der)
+	require.Nil(t, err)
+	leafTmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      csr.Subject,
+		DNSNames:     csr.DNSNames,
+		NotBefore:    now.Add(-time.Hour),
+		NotAfter:     now.Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	tbs, err = Prepare(leafTmpl, root, csr.PublicKey, Y)
+	require.Nil(t, err)
+	reviewed, err := ParseTBS(tbs)
+	require.Nil(t, err)
+	assert.Equal(t, []string{"node.example.org"}, reviewed.DNSNames)
+	sig := issue(t, shares, pubPoly, []int{1, 3, 4}, tbs)
+	leaf, err := Assemble(tbs, sig, Y)
+	require.Nil(t, err)
+	assert.True(t, leafPub.Equal(leaf.PublicKey))
+
+	roots := x509.NewCertPool()
+	roots.AddCert(root)
+	_, err = leaf.Verify(x509.VerifyOptions{Roots: roots, DNSName: "node.example.org"})
+	require.Nil(t, err)
+
+	// Wrong signatures and tampered requests are rejected
+	_, err = Assemble(tbs, make([]byte, 64), Y)
+	assert.Equal(t, errorSignature, err)
+	der[len(der)-1] ^= 1
+	_, err = VerifyCSR(der)
+	assert.NotNil(t, err)
+}
+
+func TestSigner(t *testing.T) {
+	n, th := 3, 2
+	priPoly := share.NewPriPoly(suite, th, nil, random.Stream)
+	shares, pubPoly := priPoly.Shares(n), priPoly.Commit(nil)
+	key, err := PublicKey(pubPoly.Commit())
+	require.Nil(t, err)
+
+	signer := &Signer{Key: key, SignFunc: func(msg []byte) ([]byte, error) {
+		return issue(t, shares, pubPoly, []int{0, 2}, msg), nil
+	}}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(3),
+		Subject:      pkix.Name{CommonName: "synchronous"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, key, signer)
+	require.Nil(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.Nil(t, err)
+	require.Nil(t, cert.CheckSignature(x509.PureEd25519, cert.RawTBSCertificate, cert.Signature))
+}
+
+func TestSession(t *testing.T) {
+	n, th := 4, 2
+	priPoly := share.NewPriPoly(suite, th, nil, random.Stream)
+	shares, pubPoly := priPoly.Shares(n), priPoly.Commit(nil)
+	tbs := []byte("not a certificate")
+	sess := NewSession(suite, pubPoly, tbs)
+
+	n0, c0 := frost.NewNonce(suite, 0, random.Stream)
+	_, c1 := frost.NewNonce(suite, 1, random.Stream)
+	require.Nil(t, sess.AddCommitment(c0))
+	assert.Equal(t, errorCommitment, sess.AddCommitment(c0))
+	require.Nil(t, sess.AddCommitment(c1))
+	commits := sess.Commitments()
+	_, c2 := frost.NewNonce(suite, 2, random.Stream)
+	assert.Equal(t, errorSession, sess.AddCommitment(c2))
+
+	p, err := frost.SignEdDSA(suite, pubPoly.Commit(), shares[0], n0, tbs, commits)
+	require.Nil(t, err)
+	bad := &frost.Partial{I: 1, Z: p.Z}
+	assert.NotNil(t, sess.AddPartial(bad))
+	require.Nil(t, sess.AddPartial(p))
+	_, err = sess.Signature()
+	assert.Equal(t, errorIncomplete, err)
+}
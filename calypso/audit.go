@@ -0,0 +1,113 @@
+package calypso
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+
+	"github.com/dedis/crypto/abstract"
+	"github.com/dedis/crypto/proof"
+	"github.com/dedis/crypto/share"
+	"github.com/dedis/crypto/translog"
+)
+
+// Some error definitions
+var errorNotLogged = errors.New("re-encryption share not bound to audit log")
+
+// LogEntry returns the audit log entry of a read request. Deployments that
+// require accountable decryptions append it to a translog.Log before the
+// trustees serve the request with ReencryptLogged.
+func LogEntry(req *ReadRequest) ([]byte, error) {
+	reader, err := req.Reader.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	var b bytes.Buffer
+	b.WriteString("calypso-read-log")
+	for _, field := range [][]byte{req.Write, reader, req.Signature} {
+		binary.Write(&b, binary.BigEndian, uint32(len(field)))
+		b.Write(field)
+	}
+	return req.session(b.Bytes()), nil
+}
+
+// LogRef references the entry of a read request in an audit log by a tree
+// head signed by the log operator and the inclusion proof of the entry.
+type LogRef struct {
+	Index uint64             // Index of the entry in the log
+	Head  *translog.TreeHead // Signed tree head including the entry
+	Proof [][]byte           // Inclusion proof of the entry in the tree head
+}
+
+// verify checks that the reference proves the inclusion of the read request
+// in the log of the operator with public key logKey.
+func (ref *LogRef) verify(suite abstract.Suite, logKey abstract.Point, req *ReadRequest) error {
+	if ref.Head == nil {
+		return errorNotLogged
+	}
+	if err := translog.VerifyTreeHead(suite, logKey, ref.Head); err != nil {
+		return err
+	}
+	entry, err := LogEntry(req)
+	if err != nil {
+		return err
+	}
+	return translog.VerifyInclusion(suite, entry, ref.Index, ref.Head.Size, ref.Proof, ref.Head.Root)
+}
+
+// bind extends the tag of a re-encryption proof by the log reference.
+func (ref *LogRef) bind(tag []byte) []byte {
+	var b bytes.Buffer
+	b.Write(tag)
+	b.WriteString("calypso-log")
+	binary.Write(&b, binary.BigEndian, ref.Index)
+	if ref.Head != nil {
+		binary.Write(&b, binary.BigEndian, ref.Head.Size)
+		b.Write(ref.Head.Root)
+	}
+	return b.Bytes()
+}
+
+// ReencryptLogged is like Reencrypt but only serves read requests whose entry
+// is included in the audit log of the operator with public key logKey, as
+// proven by ref. The re-encryption proof is bound to the log entry, so the
+// share cannot be presented for another entry or as unlogged share.
+func ReencryptLogged(suite abstract.Suite, w *Write, req *ReadRequest, xi *share.PriShare, logKey abstract.Point, ref *LogRef) (*ReencShare, error) {
+	if err := Check(suite, w, req); err != nil {
+		return nil, err
+	}
+	if err := ref.verify(suite, logKey, req); err != nil {
+		return nil, err
+	}
+	tag, err := req.tag()
+	if err != nil {
+		return nil, err
+	}
+	UXc := suite.Point().Add(w.U, req.Reader)
+	P, _, V, err := proof.NewDLEQProofTagged(suite, suite.Point().Base(), UXc, xi.V, ref.bind(tag))
+	if err != nil {
+		return nil, err
+	}
+	return &ReencShare{S: share.PubShare{I: xi.I, V: V}, P: *P, Log: ref}, nil
+}
+
+// VerifyReencShareLogged is like VerifyReencShare but additionally requires
+// the share to be bound to the entry of the read request in the audit log of
+// the operator with public key logKey.
+func VerifyReencShareLogged(suite abstract.Suite, pubPoly *share.PubPoly, w *Write, req *ReadRequest, logKey abstract.Point, rs *ReencShare) error {
+	if rs.Log == nil {
+		return errorNotLogged
+	}
+	if err := rs.Log.verify(suite, logKey, req); err != nil {
+		return err
+	}
+	return VerifyReencShare(suite, pubPoly, w, req, rs)
+}
+
+// RecoverLogged is like Recover but only combines re-encryption shares that
+// pass VerifyReencShareLogged.
+func RecoverLogged(suite abstract.Suite, pubPoly *share.PubPoly, w *Write, req *ReadRequest, xc abstract.Scalar, logKey abstract.Point, shares []*ReencShare, t, n int) ([]byte, error) {
+	return recoverKey(suite, pubPoly, w, req, xc, shares, t, n, func(rs *ReencShare) error {
+		return VerifyReencShareLogged(suite, pubPoly, w, req, logKey, rs)
+	})
+}
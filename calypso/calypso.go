@@ -0,0 +1,357 @@
+// Package calypso implements the core of a threshold key-escrow service in
+// the style of CALYPSO's on-chain secrets. A writer encrypts a symmetric key
+// towards the collective public key X of a committee whose members hold Shamir
+// shares of the corresponding private key (e.g. the output of a DKG) and
+// attaches an access policy. When an authorized reader asks for the key, each
+// trustee checks the policy and re-encrypts its share of the key towards the
+// reader's public key, together with a NIZK proof of correct re-encryption.
+// The reader verifies the re-encryption shares and, given a threshold of
+// them, recovers the key without the committee ever seeing it in the clear.
+//
+// Besides the authorized readers, a policy may state the number of trustees
+// that should take part in a decryption and fixes an expiry after which
+// trustees refuse to serve the secret. The stated threshold is advisory: the
+// reader combines the shares itself and needs only as many as the threshold
+// of the committee's sharing. Every re-encryption proof is bound to the write, and thus
+// to its policy, to the requesting reader and to the session of the request,
+// if any, so a combiner cannot assemble shares issued under one policy, for
+// one reader or in one session into a decryption outside of it.
+//
+// For accountability, a deployment may require every read request to be
+// appended to a transparency log (see package translog) before it is served.
+// ReencryptLogged binds a re-encryption share to the request's log entry, so
+// every decryption can be traced back to a logged request.
+package calypso
+
+import (
+	"bytes"
+	"crypto/cipher"
+	"encoding/binary"
+	"errors"
+	"time"
+
+	"github.com/dedis/crypto/abstract"
+	"github.com/dedis/crypto/proof"
+	"github.com/dedis/crypto/share"
+	"github.com/dedis/crypto/sign"
+)
+
+// Some error definitions
+var errorKeyTooLong = errors.New("key too long to embed into a point")
+var errorWriteProof = errors.New("invalid write proof")
+var errorUnauthorized = errors.New("reader not authorized by policy")
+var errorWriteMismatch = errors.New("read request does not refer to this write")
+var errorReencVerification = errors.New("verification of re-encryption share failed")
+var errorTooFewShares = errors.New("not enough valid re-encryption shares")
+var errorShareIndex = errors.New("re-encryption share index out of range")
+var errorExpired = errors.New("policy expired")
+var errorPolicyThreshold = errors.New("negative policy threshold")
+
+// Policy describes who may read a secret, how many trustees should take part
+// in a decryption and until when. Threshold is advisory and bound into the
+// write for the record; it is not enforced, since the reader can always
+// recover the key from a threshold of the committee's sharing.
+type Policy struct {
+	Readers   []abstract.Point // Public keys of the authorized readers
+	Threshold int              // Advisory number of re-encryption shares, or 0
+	Expiry    time.Time        // End of the secret's lifetime, or zero for none
+}
+
+// MarshalBinary returns the canonical encoding of the policy which is bound
+// into the write proof.
+func (p *Policy) MarshalBinary() ([]byte, error) {
+	var buf bytes.Buffer
+	var expiry int64
+	if !p.Expiry.IsZero() {
+		expiry = p.Expiry.UnixNano()
+	}
+	binary.Write(&buf, binary.BigEndian, uint32(p.Threshold))
+	binary.Write(&buf, binary.BigEndian, expiry)
+	binary.Write(&buf, binary.BigEndian, uint32(len(p.Readers)))
+	for _, r := range p.Readers {
+		if _, err := r.MarshalTo(&buf); err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// Authorized returns true if the reader's public key is listed in the policy.
+func (p *Policy) Authorized(reader abstract.Point) bool {
+	for _, r := range p.Readers {
+		if r.Equal(reader) {
+			return true
+		}
+	}
+	return false
+}
+
+// Expired returns true if the policy has an expiry that lies before now.
+func (p *Policy) Expired(now time.Time) bool {
+	return !p.Expiry.IsZero() && now.After(p.Expiry)
+}
+
+// Write is an ElGamal encryption (U, C) = (rG, K + rX) of the embedded key K
+// towards the collective public key X, together with the access policy and a
+// proof of knowledge of r bound to that policy.
+type Write struct {
+	U      abstract.Point  // Ephemeral public key rG
+	C      abstract.Point  // Encrypted key K + rX
+	E      abstract.Scalar // Challenge of the proof of knowledge of r
+	F      abstract.Scalar // Response of the proof of knowledge of r
+	Policy *Policy         // Access policy
+}
+
+// NewWrite encrypts the key towards the collective public key X under the
+// given policy. The key must fit into a single point, see Point.PickLen.
+func NewWrite(suite abstract.Suite, X abstract.Point, policy *Policy, key []byte, rand cipher.Stream) (*Write, error) {
+	if policy.Threshold < 0 {
+		return nil, errorPolicyThreshold
+	}
+	K, rem := suite.Point().Pick(key, rand)
+	if len(rem) > 0 {
+		return nil, errorKeyTooLong
+	}
+	r := suite.Scalar().Pick(rand)
+	w := &Write{
+		U:      suite.Point().Mul(nil, r),
+		C:      suite.Point().Add(K, suite.Point().Mul(X, r)),
+		Policy: policy,
+	}
+	s := suite.Scalar().Pick(rand)
+	W := suite.Point().Mul(nil, s)
+	e, err := w.challenge(suite, W)
+	if err != nil {
+		return nil, err
+	}
+	w.E = e
+	w.F = suite.Scalar().Add(s, suite.Scalar().Mul(e, r))
+	return w, nil
+}
+
+func (w *Write) challenge(suite abstract.Suite, W abstract.Point) (abstract.Scalar, error) {
+	pb, err := w.Policy.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	h := suite.Hash()
+	for _, P := range []abstract.Point{w.U, w.C, W} {
+		if _, err := P.MarshalTo(h); err != nil {
+			return nil, err
+		}
+	}
+	h.Write(pb)
+	return suite.Scalar().Pick(suite.Cipher(h.Sum(nil))), nil
+}
+
+// Verify checks the proof of knowledge attached to the write, which prevents
+// the ciphertext from being replayed under a different policy.
+func (w *Write) Verify(suite abstract.Suite) error {
+	// W = fG - eU
+	W := suite.Point().Sub(suite.Point().Mul(nil, w.F), suite.Point().Mul(w.U, w.E))
+	e, err := w.challenge(suite, W)
+	if err != nil {
+		return err
+	}
+	if !e.Equal(w.E) {
+		return errorWriteProof
+	}
+	return nil
+}
+
+// Hash returns a digest identifying the write.
+func (w *Write) Hash(suite abstract.Suite) ([]byte, error) {
+	pb, err := w.Policy.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	h := suite.Hash()
+	for _, m := range []abstract.Marshaling{w.U, w.C, w.E, w.F} {
+		if _, err := m.MarshalTo(h); err != nil {
+			return nil, err
+		}
+	}
+	h.Write(pb)
+	return h.Sum(nil), nil
+}
+
+// ReadRequest is a reader's signed request to obtain the key of a write. The
+// optional session identifies one request among several requests of the same
+// reader for the same write; it is covered by the signature and bound into
+// the re-encryption proofs, so shares issued for one session cannot be
+// replayed in another.
+type ReadRequest struct {
+	Write     []byte         // Hash of the requested write
+	Reader    abstract.Point // Public key of the reader
+	Session   []byte         // Identifier of the request, e.g. a random nonce, or nil
+	Signature []byte         // Schnorr signature of the reader on the request
+}
+
+func (r *ReadRequest) message() []byte {
+	return r.session(append([]byte("calypso-read"), r.Write...))
+}
+
+// session appends the session of the request, if any, to buf.
+func (r *ReadRequest) session(buf []byte) []byte {
+	if len(r.Session) == 0 {
+		return buf
+	}
+	buf = append(buf, "calypso-session"...)
+	buf = binary.BigEndian.AppendUint32(buf, uint32(len(r.Session)))
+	return append(buf, r.Session...)
+}
+
+// NewReadRequest creates a read request for w signed with the reader's
+// private key xc.
+func NewReadRequest(suite abstract.Suite, w *Write, xc abstract.Scalar) (*ReadRequest, error) {
+	return NewReadRequestSession(suite, w, xc, nil)
+}
+
+// NewReadRequestSession is like NewReadRequest but binds the request, and
+// thus the re-encryption shares issued for it, to the given session.
+func NewReadRequestSession(suite abstract.Suite, w *Write, xc abstract.Scalar, session []byte) (*ReadRequest, error) {
+	id, err := w.Hash(suite)
+	if err != nil {
+		return nil, err
+	}
+	req := &ReadRequest{Write: id, Reader: suite.Point().Mul(nil, xc), Session: session}
+	req.Signature, err = sign.Schnorr(suite, xc, req.message())
+	if err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+// tag returns the tag bound into the re-encryption proofs, which ties them to
+// the write, including its policy, to the reader and to the session.
+func (r *ReadRequest) tag() ([]byte, error) {
+	buf, err := r.Reader.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	tag := append([]byte("calypso-reenc"), r.Write...)
+	return r.session(append(tag, buf...)), nil
+}
+
+// Check verifies that the write is well-formed and that the read request is
+// signed by a reader authorized by the write's policy before its expiry.
+func Check(suite abstract.Suite, w *Write, req *ReadRequest) error {
+	return CheckAt(suite, w, req, time.Now())
+}
+
+// CheckAt is like Check but evaluates the policy's expiry at the given time.
+func CheckAt(suite abstract.Suite, w *Write, req *ReadRequest, now time.Time) error {
+	if w.Policy.Expired(now) {
+		return errorExpired
+	}
+	if err := w.Verify(suite); err != nil {
+		return err
+	}
+	id, err := w.Hash(suite)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(id, req.Write) {
+		return errorWriteMismatch
+	}
+	if !w.Policy.Authorized(req.Reader) {
+		return errorUnauthorized
+	}
+	return sign.VerifySchnorr(suite, req.Reader, req.message(), req.Signature)
+}
+
+// ReencShare is a trustee's share of the key re-encrypted towards a reader,
+// together with a proof of correct re-encryption.
+type ReencShare struct {
+	S   share.PubShare  // Re-encryption share x_i(U + Xc)
+	P   proof.DLEQProof // Proof that log_G(X_i) == log_{U+Xc}(S.V)
+	Log *LogRef         // Audit log entry of the request, see ReencryptLogged
+}
+
+// Reencrypt checks the read request against the write and, if it is
+// authorized, re-encrypts the trustee's share of the key towards the reader.
+func Reencrypt(suite abstract.Suite, w *Write, req *ReadRequest, xi *share.PriShare) (*ReencShare, error) {
+	if err := Check(suite, w, req); err != nil {
+		return nil, err
+	}
+	tag, err := req.tag()
+	if err != nil {
+		return nil, err
+	}
+	UXc := suite.Point().Add(w.U, req.Reader)
+	P, _, V, err := proof.NewDLEQProofTagged(suite, suite.Point().Base(), UXc, xi.V, tag)
+	if err != nil {
+		return nil, err
+	}
+	return &ReencShare{S: share.PubShare{I: xi.I, V: V}, P: *P}, nil
+}
+
+// VerifyReencShare checks a re-encryption share against the public
+// commitment polynomial of the committee's key and verifies that it was
+// issued for the write and the read request. For a share bound to an audit
+// log entry, it checks the binding but not the entry itself; use
+// VerifyReencShareLogged for that.
+func VerifyReencShare(suite abstract.Suite, pubPoly *share.PubPoly, w *Write, req *ReadRequest, rs *ReencShare) error {
+	id, err := w.Hash(suite)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(id, req.Write) {
+		return errorWriteMismatch
+	}
+	tag, err := req.tag()
+	if err != nil {
+		return err
+	}
+	if rs.Log != nil {
+		tag = rs.Log.bind(tag)
+	}
+	Xi := pubPoly.Eval(rs.S.I).V
+	UXc := suite.Point().Add(w.U, req.Reader)
+	if err := rs.P.VerifyTagged(suite, suite.Point().Base(), UXc, Xi, rs.S.V, tag); err != nil {
+		return errorReencVerification
+	}
+	return nil
+}
+
+// Recover verifies the given re-encryption shares, combines a threshold t of
+// the valid ones and decrypts the key using the reader's private key xc. The
+// collective public key is taken from pubPoly.
+func Recover(suite abstract.Suite, pubPoly *share.PubPoly, w *Write, req *ReadRequest, xc abstract.Scalar, shares []*ReencShare, t, n int) ([]byte, error) {
+	return recoverKey(suite, pubPoly, w, req, xc, shares, t, n, func(rs *ReencShare) error {
+		return VerifyReencShare(suite, pubPoly, w, req, rs)
+	})
+}
+
+func recoverKey(suite abstract.Suite, pubPoly *share.PubPoly, w *Write, req *ReadRequest, xc abstract.Scalar, shares []*ReencShare, t, n int, verify func(*ReencShare) error) ([]byte, error) {
+	// Keep the first valid share of every index; a repeated or out-of-range
+	// index would make the interpolation yield a wrong key
+	var good []*share.PubShare
+	seen := make(map[int]bool)
+	for _, rs := range shares {
+		if rs == nil || seen[rs.S.I] {
+			continue
+		}
+		if err := verify(rs); err != nil {
+			continue
+		}
+		if rs.S.I < 0 || rs.S.I >= n {
+			return nil, errorShareIndex
+		}
+		seen[rs.S.I] = true
+		good = append(good, &rs.S)
+	}
+	if len(good) < t {
+		return nil, errorTooFewShares
+	}
+	// xU + xXc
+	XhatEnc, err := share.RecoverCommit(suite, good, t, n)
+	if err != nil {
+		return nil, err
+	}
+	// xU = XhatEnc - xc X
+	xcX := suite.Point().Mul(pubPoly.Commit(), xc)
+	xU := suite.Point().Sub(XhatEnc, xcX)
+	K := suite.Point().Sub(w.C, xU)
+	return K.Data()
+}
@@ -0,0 +1,269 @@
+package calypso
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/dedis/crypto/abstract"
+	"github.com/dedis/crypto/edwards"
+	"github.com/dedis/crypto/random"
+	"github.com/dedis/crypto/share"
+	"github.com/dedis/crypto/translog"
+)
+
+var suite = edwards.NewAES128SHA256Ed25519(false)
+
+type committee struct {
+	t, n    int
+	shares  []*share.PriShare
+	pubPoly *share.PubPoly
+}
+
+func newCommittee(t, n int) *committee {
+	priPoly := share.NewPriPoly(suite, t, nil, random.Stream)
+	return &committee{t, n, priPoly.Shares(n), priPoly.Commit(nil)}
+}
+
+func setup(t *testing.T, c *committee, key []byte) (*Write, abstract.Scalar, *ReadRequest) {
+	xc := suite.Scalar().Pick(random.Stream)
+	Xc := suite.Point().Mul(nil, xc)
+	policy := &Policy{Readers: []abstract.Point{Xc}}
+	w, err := NewWrite(suite, c.pubPoly.Commit(), policy, key, random.Stream)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req, err := NewReadRequest(suite, w, xc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return w, xc, req
+}
+
+func TestCalypso(t *testing.T) {
+	c := newCommittee(4, 7)
+	key := []byte("symmetric key")
+	w, xc, req := setup(t, c, key)
+
+	shares := make([]*ReencShare, c.n)
+	for i, xi := range c.shares {
+		rs, err := Reencrypt(suite, w, req, xi)
+		if err != nil {
+			t.Fatal(err)
+		}
+		shares[i] = rs
+	}
+	// Corrupt a few shares
+	shares[1].S.V = suite.Point().Base()
+	shares[3] = nil
+
+	recovered, err := Recover(suite, c.pubPoly, w, req, xc, shares, c.t, c.n)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(recovered, key) {
+		t.Fatal("recovered key does not match")
+	}
+
+	// Replayed shares count once
+	replayed := []*ReencShare{shares[0], shares[0], shares[0], shares[2], shares[4]}
+	if _, err := Recover(suite, c.pubPoly, w, req, xc, replayed, c.t, c.n); err != errorTooFewShares {
+		t.Fatal("replayed shares counted more than once")
+	}
+	recovered, err = Recover(suite, c.pubPoly, w, req, xc, append(replayed, shares[5]), c.t, c.n)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(recovered, key) {
+		t.Fatal("recovered key does not match")
+	}
+
+	// A valid share with an index outside of the committee is rejected
+	if _, err := Recover(suite, c.pubPoly, w, req, xc, shares, c.t, c.n-1); err != errorShareIndex {
+		t.Fatal("share with out-of-range index accepted")
+	}
+}
+
+func TestCalypsoUnauthorized(t *testing.T) {
+	c := newCommittee(3, 5)
+	w, _, _ := setup(t, c, []byte("key"))
+
+	// A reader outside the policy
+	req, err := NewReadRequest(suite, w, suite.Scalar().Pick(random.Stream))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Reencrypt(suite, w, req, c.shares[0]); err != errorUnauthorized {
+		t.Fatal("unauthorized reader obtained a re-encryption share")
+	}
+}
+
+func TestCalypsoPolicyBinding(t *testing.T) {
+	c := newCommittee(3, 5)
+	w, _, _ := setup(t, c, []byte("key"))
+
+	// Replay the ciphertext under an attacker's policy
+	xa := suite.Scalar().Pick(random.Stream)
+	w.Policy = &Policy{Readers: []abstract.Point{suite.Point().Mul(nil, xa)}}
+	if err := w.Verify(suite); err == nil {
+		t.Fatal("write verified under a different policy")
+	}
+	req, err := NewReadRequest(suite, w, xa)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Reencrypt(suite, w, req, c.shares[0]); err == nil {
+		t.Fatal("replayed write was re-encrypted")
+	}
+}
+
+func TestCalypsoPolicy(t *testing.T) {
+	c := newCommittee(3, 5)
+	xa := suite.Scalar().Pick(random.Stream)
+	xb := suite.Scalar().Pick(random.Stream)
+	policy := &Policy{
+		Readers:   []abstract.Point{suite.Point().Mul(nil, xa), suite.Point().Mul(nil, xb)},
+		Threshold: 4,
+		Expiry:    time.Now().Add(time.Hour),
+	}
+	key := []byte("key")
+	w, err := NewWrite(suite, c.pubPoly.Commit(), policy, key, random.Stream)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reqA, err := NewReadRequest(suite, w, xa)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reqB, err := NewReadRequest(suite, w, xb)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := CheckAt(suite, w, reqA, policy.Expiry.Add(time.Second)); err != errorExpired {
+		t.Fatal("expired policy accepted")
+	}
+
+	shares := make([]*ReencShare, c.n)
+	for i, xi := range c.shares {
+		if shares[i], err = Reencrypt(suite, w, reqA, xi); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Shares issued to one reader do not count for another one
+	if err := VerifyReencShare(suite, c.pubPoly, w, reqB, shares[0]); err == nil {
+		t.Fatal("re-encryption share verified for a different reader")
+	}
+	// Nor do they count for another session of the same reader
+	reqS, err := NewReadRequestSession(suite, w, xa, []byte("session 1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Check(suite, w, reqS); err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyReencShare(suite, c.pubPoly, w, reqS, shares[0]); err == nil {
+		t.Fatal("re-encryption share verified for a different session")
+	}
+	rs, err := Reencrypt(suite, w, reqS, c.shares[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyReencShare(suite, c.pubPoly, w, reqS, rs); err != nil {
+		t.Fatal(err)
+	}
+	replayed := *reqS
+	replayed.Session = []byte("session 2")
+	if err := VerifyReencShare(suite, c.pubPoly, w, &replayed, rs); err == nil {
+		t.Fatal("re-encryption share verified for a replayed session")
+	}
+	if err := Check(suite, w, &replayed); err == nil {
+		t.Fatal("read request with altered session accepted")
+	}
+	recovered, err := Recover(suite, c.pubPoly, w, reqA, xa, shares, c.t, c.n)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(recovered, key) {
+		t.Fatal("recovered key does not match")
+	}
+}
+
+func TestCalypsoLogged(t *testing.T) {
+	c := newCommittee(3, 5)
+	key := []byte("audited key")
+	w, xc, req := setup(t, c, key)
+
+	logKey := suite.Scalar().Pick(random.Stream)
+	logPub := suite.Point().Mul(nil, logKey)
+	log := translog.NewLog(suite)
+	log.Append([]byte("other entry"))
+	entry, err := LogEntry(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	index := log.Append(entry)
+	head, err := log.SignTreeHead(logKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path, err := log.InclusionProof(index, head.Size)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ref := &LogRef{Index: index, Head: head, Proof: path}
+
+	shares := make([]*ReencShare, c.n)
+	for i, xi := range c.shares {
+		rs, err := ReencryptLogged(suite, w, req, xi, logPub, ref)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := VerifyReencShareLogged(suite, c.pubPoly, w, req, logPub, rs); err != nil {
+			t.Fatal(err)
+		}
+		shares[i] = rs
+	}
+	recovered, err := RecoverLogged(suite, c.pubPoly, w, req, xc, logPub, shares, c.t, c.n)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(recovered, key) {
+		t.Fatal("recovered key does not match")
+	}
+
+	// Unlogged shares are rejected
+	plain, err := Reencrypt(suite, w, req, c.shares[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyReencShareLogged(suite, c.pubPoly, w, req, logPub, plain); err != errorNotLogged {
+		t.Fatal("unlogged share accepted")
+	}
+
+	// A reference to another entry is refused by the trustees
+	other := &LogRef{Index: 0, Head: head, Proof: path}
+	if _, err := ReencryptLogged(suite, w, req, c.shares[0], logPub, other); err == nil {
+		t.Fatal("re-encryption for entry not in the log")
+	}
+
+	// Rebinding a share to another log reference breaks its proof
+	rebound := *shares[0]
+	rebound.Log = &LogRef{Index: index, Head: head, Proof: path}
+	rebound.Log.Index++
+	if err := VerifyReencShare(suite, c.pubPoly, w, req, &rebound); err == nil {
+		t.Fatal("share rebound to another log entry")
+	}
+	rebound.Log = nil
+	if err := VerifyReencShare(suite, c.pubPoly, w, req, &rebound); err == nil {
+		t.Fatal("logged share stripped of its log reference")
+	}
+
+	// Tree heads not signed by the log operator are rejected
+	forged := *head
+	forged.Signature = append([]byte{}, head.Signature...)
+	forged.Signature[0] ^= 1
+	if _, err := ReencryptLogged(suite, w, req, c.shares[0], logPub, &LogRef{index, &forged, path}); err == nil {
+		t.Fatal("re-encryption under forged tree head")
+	}
+}
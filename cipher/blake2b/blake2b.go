@@ -0,0 +1,144 @@
+// Package blake2b implements the BLAKE2b hash function of RFC 7693 in pure
+// Go, for digests of 1 to 64 bytes without a key.
+package blake2b
+
+import (
+	"encoding/binary"
+	"hash"
+	"math/bits"
+)
+
+// BlockSize is the block size of BLAKE2b in bytes.
+const BlockSize = 128
+
+// Size is the maximal digest size of BLAKE2b in bytes.
+const Size = 64
+
+var iv = [8]uint64{
+	0x6a09e667f3bcc908, 0xbb67ae8584caa73b, 0x3c6ef372fe94f82b, 0xa54ff53a5f1d36f1,
+	0x510e527fade682d1, 0x9b05688c2b3e6c1f, 0x1f83d9abfb41bd6b, 0x5be0cd19137e2179,
+}
+
+var sigma = [12][16]byte{
+	{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15},
+	{14, 10, 4, 8, 9, 15, 13, 6, 1, 12, 0, 2, 11, 7, 5, 3},
+	{11, 8, 12, 0, 5, 2, 15, 13, 10, 14, 3, 6, 7, 1, 9, 4},
+	{7, 9, 3, 1, 13, 12, 11, 14, 2, 6, 5, 10, 4, 0, 15, 8},
+	{9, 0, 5, 7, 2, 4, 10, 15, 14, 1, 11, 12, 6, 8, 3, 13},
+	{2, 12, 6, 10, 0, 11, 8, 3, 4, 13, 7, 5, 15, 14, 1, 9},
+	{12, 5, 1, 15, 14, 13, 4, 10, 0, 7, 6, 3, 9, 2, 8, 11},
+	{13, 11, 7, 14, 12, 1, 3, 9, 5, 0, 15, 4, 8, 6, 2, 10},
+	{6, 15, 14, 9, 11, 3, 0, 8, 12, 2, 13, 7, 1, 4, 10, 5},
+	{10, 2, 8, 4, 7, 6, 1, 5, 15, 11, 9, 14, 3, 12, 13, 0},
+	{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15},
+	{14, 10, 4, 8, 9, 15, 13, 6, 1, 12, 0, 2, 11, 7, 5, 3},
+}
+
+type digest struct {
+	h    [8]uint64
+	t    [2]uint64 // Byte counter
+	buf  [BlockSize]byte
+	n    int // Bytes in buf
+	size int
+}
+
+// New512 returns a new BLAKE2b-512 hash.
+func New512() hash.Hash {
+	return New(64)
+}
+
+// New256 returns a new BLAKE2b-256 hash.
+func New256() hash.Hash {
+	return New(32)
+}
+
+// New returns a new BLAKE2b hash with a digest of size bytes, which must be
+// between 1 and 64.
+func New(size int) hash.Hash {
+	if size < 1 || size > Size {
+		panic("blake2b: invalid digest size")
+	}
+	d := &digest{size: size}
+	d.Reset()
+	return d
+}
+
+func (d *digest) Size() int      { return d.size }
+func (d *digest) BlockSize() int { return BlockSize }
+
+func (d *digest) Reset() {
+	d.h = iv
+	d.h[0] ^= 0x01010000 ^ uint64(d.size)
+	d.t = [2]uint64{}
+	d.n = 0
+}
+
+func (d *digest) Write(p []byte) (int, error) {
+	l := len(p)
+	for len(p) > 0 {
+		// The last block is only compressed in Sum, with the final flag set
+		if d.n == BlockSize {
+			d.compress(false)
+			d.n = 0
+		}
+		c := copy(d.buf[d.n:], p)
+		d.n += c
+		p = p[c:]
+	}
+	return l, nil
+}
+
+func (d *digest) Sum(b []byte) []byte {
+	dd := *d
+	for i := dd.n; i < BlockSize; i++ {
+		dd.buf[i] = 0
+	}
+	dd.compress(true)
+	var out [Size]byte
+	for i, h := range dd.h {
+		binary.LittleEndian.PutUint64(out[8*i:], h)
+	}
+	return append(b, out[:d.size]...)
+}
+
+func (d *digest) compress(last bool) {
+	d.t[0] += uint64(d.n)
+	if d.t[0] < uint64(d.n) {
+		d.t[1]++
+	}
+	var m [16]uint64
+	for i := range m {
+		m[i] = binary.LittleEndian.Uint64(d.buf[8*i:])
+	}
+	var v [16]uint64
+	copy(v[:8], d.h[:])
+	copy(v[8:], iv[:])
+	v[12] ^= d.t[0]
+	v[13] ^= d.t[1]
+	if last {
+		v[14] = ^v[14]
+	}
+	g := func(a, b, c, e int, x, y uint64) {
+		v[a] = v[a] + v[b] + x
+		v[e] = bits.RotateLeft64(v[e]^v[a], -32)
+		v[c] = v[c] + v[e]
+		v[b] = bits.RotateLeft64(v[b]^v[c], -24)
+		v[a] = v[a] + v[b] + y
+		v[e] = bits.RotateLeft64(v[e]^v[a], -16)
+		v[c] = v[c] + v[e]
+		v[b] = bits.RotateLeft64(v[b]^v[c], -63)
+	}
+	for _, s := range sigma {
+		g(0, 4, 8, 12, m[s[0]], m[s[1]])
+		g(1, 5, 9, 13, m[s[2]], m[s[3]])
+		g(2, 6, 10, 14, m[s[4]], m[s[5]])
+		g(3, 7, 11, 15, m[s[6]], m[s[7]])
+		g(0, 5, 10, 15, m[s[8]], m[s[9]])
+		g(1, 6, 11, 12, m[s[10]], m[s[11]])
+		g(2, 7, 8, 13, m[s[12]], m[s[13]])
+		g(3, 4, 9, 14, m[s[14]], m[s[15]])
+	}
+	for i := range d.h {
+		d.h[i] ^= v[i] ^ v[i+8]
+	}
+}
@@ -0,0 +1,48 @@
+package blake2b
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+)
+
+func TestVectors(t *testing.T) {
+	vectors := []struct {
+		size int
+		in   string
+		out  string
+	}{
+		// RFC 7693, Appendix A
+		{64, "abc", "ba80a53f981c4d0d6a2797b69f12f6e94c212f14685ac4b74b12bb6fdbffa2d17d87c5392aab792dc252d5de4533cc9518d38aa8dbf1925ab92386edd4009923"},
+		{64, "", "786a02f742015903c6c6fd852552d272912f4740e15847618a86e217f71f5419d25e1031afee585313896444934eb04b903a685b1448b755d56f701afe9be2ce"},
+		{32, "", "0e5751c026e543b2e8ab2eb06099daa1d1e5df47778f7787faab45cdf12fe3a8"},
+		{32, "The quick brown fox jumps over the lazy dog", "01718cec35cd3d796dd00020e0bfecb473ad23457d063b75eff29c0ffa2e58a9"},
+	}
+	for _, v := range vectors {
+		h := New(v.size)
+		h.Write([]byte(v.in))
+		if got := hex.EncodeToString(h.Sum(nil)); got != v.out {
+			t.Fatalf("BLAKE2b-%d(%q) = %s, want %s", 8*v.size, v.in, got, v.out)
+		}
+	}
+}
+
+func TestIncremental(t *testing.T) {
+	msg := bytes.Repeat([]byte("0123456789"), 100)
+	h := New512()
+	h.Write(msg)
+	want := h.Sum(nil)
+	for _, step := range []int{1, 7, 128, 129, 333} {
+		h.Reset()
+		for i := 0; i < len(msg); i += step {
+			end := i + step
+			if end > len(msg) {
+				end = len(msg)
+			}
+			h.Write(msg[i:end])
+		}
+		if !bytes.Equal(h.Sum(nil), want) {
+			t.Fatalf("incremental hashing with step %d differs", step)
+		}
+	}
+}
@@ -8,8 +8,7 @@
 // Both types of hash function use the "sponge" construction and the Keccak
 // permutation. For a detailed specification see http://keccak.noekeon.org/
 //
-//
-// Guidance
+// # Guidance
 //
 // If you aren't sure what function you need, use SHAKE256 with at least 64
 // bytes of output.
@@ -18,8 +17,7 @@
 // secret key to the input, hash with SHAKE256 and read at least 32 bytes of
 // output.
 //
-//
-// Security strengths
+// # Security strengths
 //
 // The SHA3-x functions have a security strength against preimage attacks of x
 // bits. Since they only produce x bits of output, their collision-resistance
@@ -30,8 +28,7 @@
 // Requesting more than 2x bits of output does not increase the collision-
 // resistance of the SHAKE functions.
 //
-//
-// The sponge construction
+// # The sponge construction
 //
 // A sponge builds a pseudo-random function from a pseudo-random permutation,
 // by applying the permutation to a state of "rate + capacity" bytes, but
@@ -50,8 +47,7 @@
 // Since the KeccakF-1600 permutation is 1600 bits (200 bytes) wide, this means
 // that security_strength == (1600 - bitrate) / 2.
 //
-//
-// Recommendations, detailed
+// # Recommendations, detailed
 //
 // The SHAKE functions are recommended for most new uses. They can produce
 // output of arbitrary length. SHAKE256, with an output length of at least
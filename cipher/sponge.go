@@ -86,7 +86,7 @@ func FromSponge(sponge Sponge, key []byte, options ...interface{}) abstract.Ciph
 	// Setup normal-case domain-separation byte used for message payloads
 	sc.setDomain(domainPayload, 0)
 
-	return abstract.Cipher{&sc}
+	return abstract.Cipher{CipherState: &sc}
 }
 
 func (sc *spongeCipher) parseOptions(options []interface{}) bool {
@@ -52,7 +52,7 @@ func FromStream(newStream func(key []byte) cipher.Stream,
 		panic("no FromStream options supported yet")
 	}
 
-	return abstract.Cipher{&sc}
+	return abstract.Cipher{CipherState: &sc}
 }
 
 func (sc *streamCipher) Partial(dst, src, key []byte) {
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/dedis/crypto/abstract"
+	"github.com/dedis/crypto/group"
+	"github.com/dedis/crypto/pvss"
+)
+
+func inspect(suite abstract.Suite, args []string, in io.Reader, out io.Writer) error {
+	flags := flag.NewFlagSet("inspect", flag.ContinueOnError)
+	typ := flags.String("type", "point", "type of the blob: point, scalar or share")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return fmt.Errorf("expected exactly one hex-encoded blob")
+	}
+	blob := flags.Arg(0)
+	buf, err := hex.DecodeString(blob)
+	if err != nil {
+		return err
+	}
+
+	switch *typ {
+	case "point":
+		P := suite.Point()
+		if err := P.UnmarshalBinary(buf); err != nil {
+			return err
+		}
+		canonical := group.UnmarshalPoint(suite.Point(), buf, group.Strict) == nil
+		fmt.Fprintf(out, "point %s\ncanonical %t\n", P, canonical)
+	case "scalar":
+		s := suite.Scalar()
+		if err := s.UnmarshalBinary(buf); err != nil {
+			return err
+		}
+		canonical := group.UnmarshalScalar(suite.Scalar(), buf, group.Strict) == nil
+		fmt.Fprintf(out, "scalar %s\ncanonical %t\n", s, canonical)
+	case "share":
+		ps := new(pvss.PubVerShare)
+		if err := decode(suite, blob, ps); err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "index %d\nvalue %s\nchallenge %s\nresponse %s\ncommitG %s\ncommitH %s\n",
+			ps.S.I, ps.S.V, ps.P.C, ps.P.R, ps.P.VG, ps.P.VH)
+	default:
+		return fmt.Errorf("unknown type %q", *typ)
+	}
+	return nil
+}
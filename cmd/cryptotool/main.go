@@ -0,0 +1,235 @@
+// Command cryptotool exposes key, signature and PVSS operations of this
+// library on the command line. It is meant for operating and debugging
+// deployments: every value it reads or writes is the hex-encoded binary
+// encoding of the corresponding object, so blobs can be copied from and to
+// logs or configuration files. Private keys are read from files as written by
+// keygen, e.g. "cryptotool keygen > key", and never from the command line.
+//
+// Usage:
+//
+//	cryptotool [-suite name] command [flags]
+//
+// The commands are:
+//
+//	keygen        generate a key pair
+//	sign          sign the message read from stdin with a Schnorr signature
+//	verify        verify a Schnorr signature on the message read from stdin
+//	pvss-deal     deal PVSS shares of a random secret to a list of public keys
+//	pvss-verify   verify the encrypted shares of a deal
+//	pvss-decrypt  decrypt one's encrypted share of a deal
+//	pvss-recover  recover the secret from a deal and decrypted shares
+//	inspect       decode a blob and print its contents
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/dedis/crypto/abstract"
+	"github.com/dedis/crypto/random"
+	"github.com/dedis/crypto/sign"
+	"github.com/dedis/crypto/suites"
+)
+
+// command runs a subcommand with its arguments.
+type command func(suite abstract.Suite, args []string, in io.Reader, out io.Writer) error
+
+var commands = map[string]command{
+	"keygen":       keygen,
+	"sign":         signCmd,
+	"verify":       verifyCmd,
+	"pvss-deal":    pvssDeal,
+	"pvss-verify":  pvssVerify,
+	"pvss-decrypt": pvssDecrypt,
+	"pvss-recover": pvssRecover,
+	"inspect":      inspect,
+}
+
+func main() {
+	if err := run(os.Args[1:], os.Stdin, os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, "cryptotool:", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, in io.Reader, out io.Writer) error {
+	flags := flag.NewFlagSet("cryptotool", flag.ContinueOnError)
+	name := flags.String("suite", "Ed25519", "name of the ciphersuite")
+	flags.Usage = func() {
+		var names []string
+		for n := range commands {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		fmt.Fprintf(flags.Output(), "usage: cryptotool [-suite name] %s [flags]\n", strings.Join(names, "|"))
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return errors.New("no command given")
+	}
+	suite, err := suites.StringToSuite(*name)
+	if err != nil {
+		return err
+	}
+	cmd, ok := commands[flags.Arg(0)]
+	if !ok {
+		return fmt.Errorf("unknown command %q", flags.Arg(0))
+	}
+	return cmd(suite, flags.Args()[1:], in, out)
+}
+
+func keygen(suite abstract.Suite, args []string, in io.Reader, out io.Writer) error {
+	flags := flag.NewFlagSet("keygen", flag.ContinueOnError)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	x := suite.Scalar().Pick(random.Stream)
+	X := suite.Point().Mul(nil, x)
+	if err := writeLine(suite, out, "private", x); err != nil {
+		return err
+	}
+	return writeLine(suite, out, "public", X)
+}
+
+func signCmd(suite abstract.Suite, args []string, in io.Reader, out io.Writer) error {
+	flags := flag.NewFlagSet("sign", flag.ContinueOnError)
+	key := flags.String("key", "", "file with the private key, as written by keygen")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	x, err := readPrivate(suite, *key)
+	if err != nil {
+		return err
+	}
+	msg, err := ioutil.ReadAll(in)
+	if err != nil {
+		return err
+	}
+	sig, err := sign.Schnorr(suite, x, msg)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(out, "signature", hex.EncodeToString(sig))
+	return err
+}
+
+func verifyCmd(suite abstract.Suite, args []string, in io.Reader, out io.Writer) error {
+	flags := flag.NewFlagSet("verify", flag.ContinueOnError)
+	pub := flags.String("pub", "", "hex-encoded public key")
+	sigHex := flags.String("sig", "", "hex-encoded signature")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	X := suite.Point()
+	if err := decode(suite, *pub, X); err != nil {
+		return fmt.Errorf("public key: %s", err)
+	}
+	sig, err := hex.DecodeString(*sigHex)
+	if err != nil {
+		return fmt.Errorf("signature: %s", err)
+	}
+	msg, err := ioutil.ReadAll(in)
+	if err != nil {
+		return err
+	}
+	if err := sign.VerifySchnorr(suite, X, msg, sig); err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(out, "ok")
+	return err
+}
+
+// readPrivate reads the private key from the "private" line of a file as
+// written by keygen. Private keys are never passed on the command line, where
+// other users and the shell history could see them.
+func readPrivate(suite abstract.Suite, file string) (abstract.Scalar, error) {
+	if file == "" {
+		return nil, errors.New("no private key file given")
+	}
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	lines, err := readLines(f)
+	if err != nil {
+		return nil, err
+	}
+	if len(lines["private"]) != 1 {
+		return nil, fmt.Errorf("%d private keys found, want one", len(lines["private"]))
+	}
+	x := suite.Scalar()
+	if err := decode(suite, lines["private"][0], x); err != nil {
+		return nil, fmt.Errorf("private key: %s", err)
+	}
+	return x, nil
+}
+
+// encode returns the hex encoding of obj under the suite's binary encoding.
+func encode(suite abstract.Suite, obj interface{}) (string, error) {
+	var b bytes.Buffer
+	if err := suite.Write(&b, obj); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b.Bytes()), nil
+}
+
+// decode decodes the hex string s into obj, which must be a pointer or an
+// abstract type, and fails if s contains trailing data.
+func decode(suite abstract.Suite, s string, obj interface{}) error {
+	buf, err := hex.DecodeString(s)
+	if err != nil {
+		return err
+	}
+	r := bytes.NewReader(buf)
+	if err := suite.Read(r, obj); err != nil {
+		return err
+	}
+	if r.Len() != 0 {
+		return fmt.Errorf("%d bytes of trailing data", r.Len())
+	}
+	return nil
+}
+
+func writeLine(suite abstract.Suite, out io.Writer, tag string, obj interface{}) error {
+	s, err := encode(suite, obj)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(out, tag, s)
+	return err
+}
+
+// readLines reads lines of the form "tag value" and groups the values by tag
+// in the order they appear. Empty lines and lines starting with '#' are
+// ignored.
+func readLines(in io.Reader) (map[string][]string, error) {
+	lines := make(map[string][]string)
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(nil, 1<<20)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("malformed line %q", line)
+		}
+		lines[fields[0]] = append(lines[fields[0]], fields[1])
+	}
+	return lines, scanner.Err()
+}
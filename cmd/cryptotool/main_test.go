@@ -0,0 +1,94 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func exec(t *testing.T, in string, args ...string) string {
+	var out bytes.Buffer
+	require.Nil(t, run(args, strings.NewReader(in), &out))
+	return out.String()
+}
+
+// field returns the value of the first line tagged with tag.
+func field(out, tag string) string {
+	for _, line := range strings.Split(out, "\n") {
+		if strings.HasPrefix(line, tag+" ") {
+			return strings.TrimPrefix(line, tag+" ")
+		}
+	}
+	return ""
+}
+
+// keyFile writes the output of keygen to a file in dir and returns its name.
+func keyFile(t *testing.T, dir, keys string) string {
+	f, err := ioutil.TempFile(dir, "key")
+	require.Nil(t, err)
+	defer f.Close()
+	_, err = f.WriteString(keys)
+	require.Nil(t, err)
+	return f.Name()
+}
+
+func TestSign(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cryptotool")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	keys := exec(t, "", "keygen")
+	sig := field(exec(t, "hello", "sign", "-key", keyFile(t, dir, keys)), "signature")
+	assert.Equal(t, "ok\n", exec(t, "hello", "verify", "-pub", field(keys, "public"), "-sig", sig))
+
+	var out bytes.Buffer
+	err = run([]string{"verify", "-pub", field(keys, "public"), "-sig", sig}, strings.NewReader("bye"), &out)
+	assert.NotNil(t, err)
+	assert.NotNil(t, run([]string{"sign"}, strings.NewReader("hello"), &out))
+
+	info := exec(t, "", "inspect", "-type", "scalar", field(keys, "private"))
+	assert.Contains(t, info, "canonical true")
+}
+
+func TestPVSS(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cryptotool")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	var privFiles []string
+	var pubs bytes.Buffer
+	for i := 0; i < 3; i++ {
+		k := exec(t, "", "keygen")
+		privFiles = append(privFiles, keyFile(t, dir, k))
+		pubs.WriteString("public " + field(k, "public") + "\n")
+	}
+	pubFile := filepath.Join(dir, "keys")
+	require.Nil(t, ioutil.WriteFile(pubFile, pubs.Bytes(), 0600))
+
+	secretFile := filepath.Join(dir, "secret")
+	deal := exec(t, "", "pvss-deal", "-keys", pubFile, "-t", "2", "-secret-out", secretFile)
+	assert.NotContains(t, deal, "secret")
+	dealt, err := ioutil.ReadFile(secretFile)
+	require.Nil(t, err)
+	assert.Equal(t, "0 ok\n1 ok\n2 ok\n", exec(t, deal, "pvss-verify", "-keys", pubFile))
+	info := exec(t, "", "inspect", "-type", "share", field(deal, "share"))
+	assert.Contains(t, info, "index 0")
+
+	input := deal
+	for _, i := range []int{0, 2} {
+		input += exec(t, deal, "pvss-decrypt", "-keys", pubFile, "-key", privFiles[i])
+	}
+	secret := field(exec(t, input, "pvss-recover", "-keys", pubFile), "secret")
+	assert.Equal(t, field(string(dealt), "secret"), secret)
+
+	// A single decrypted share is not enough
+	one := deal + exec(t, deal, "pvss-decrypt", "-keys", pubFile, "-key", privFiles[1])
+	var out bytes.Buffer
+	assert.NotNil(t, run([]string{"pvss-recover", "-keys", pubFile}, strings.NewReader(one), &out))
+}
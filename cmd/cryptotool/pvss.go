@@ -0,0 +1,258 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+
+	"github.com/dedis/crypto/abstract"
+	"github.com/dedis/crypto/pvss"
+	"github.com/dedis/crypto/random"
+	"github.com/dedis/crypto/share"
+)
+
+// A deal is written as t "commit" lines holding the coefficient commitments
+// of the public polynomial followed by n "share" lines holding the encrypted
+// shares. Decrypted shares are written as "decshare" lines. All of them use
+// the second base point derived from the -base flag.
+
+// pvssFlags returns a flag set with the flags common to all PVSS commands.
+func pvssFlags(name string) (*flag.FlagSet, *string, *string) {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	keys := flags.String("keys", "", "file with one hex-encoded public key per line, in share order")
+	base := flags.String("base", "H", "seed from which the second base point H is derived")
+	return flags, keys, base
+}
+
+func basePoint(suite abstract.Suite, seed string) abstract.Point {
+	H, _ := suite.Point().Pick(nil, suite.Cipher([]byte(seed)))
+	return H
+}
+
+func readKeys(suite abstract.Suite, file string) ([]abstract.Point, error) {
+	if file == "" {
+		return nil, errors.New("no key file given")
+	}
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	lines, err := readLines(f)
+	if err != nil {
+		return nil, err
+	}
+	var keys []abstract.Point
+	for _, s := range lines["public"] {
+		X := suite.Point()
+		if err := decode(suite, s, X); err != nil {
+			return nil, fmt.Errorf("public key %d: %s", len(keys), err)
+		}
+		keys = append(keys, X)
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("no public keys found")
+	}
+	return keys, nil
+}
+
+type deal struct {
+	poly   *share.PubPoly
+	shares []*pvss.PubVerShare
+	dec    []*pvss.PubVerShare
+}
+
+func readDeal(suite abstract.Suite, H abstract.Point, n int, in io.Reader) (*deal, error) {
+	lines, err := readLines(in)
+	if err != nil {
+		return nil, err
+	}
+	var commits []abstract.Point
+	for i, s := range lines["commit"] {
+		C := suite.Point()
+		if err := decode(suite, s, C); err != nil {
+			return nil, fmt.Errorf("commit %d: %s", i, err)
+		}
+		commits = append(commits, C)
+	}
+	if len(commits) == 0 {
+		return nil, errors.New("no commitments found")
+	}
+	if len(lines["share"]) != n {
+		return nil, fmt.Errorf("%d encrypted shares for %d public keys", len(lines["share"]), n)
+	}
+	d := &deal{poly: share.NewPubPoly(suite, H, commits)}
+	for i, s := range lines["share"] {
+		ps := new(pvss.PubVerShare)
+		if err := decode(suite, s, ps); err != nil {
+			return nil, fmt.Errorf("encrypted share %d: %s", i, err)
+		}
+		if ps.S.I != i {
+			return nil, fmt.Errorf("encrypted share %d has index %d", i, ps.S.I)
+		}
+		d.shares = append(d.shares, ps)
+	}
+	for i, s := range lines["decshare"] {
+		ps := new(pvss.PubVerShare)
+		if err := decode(suite, s, ps); err != nil {
+			return nil, fmt.Errorf("decrypted share %d: %s", i, err)
+		}
+		if ps.S.I < 0 || ps.S.I >= n {
+			return nil, fmt.Errorf("decrypted share %d has invalid index %d", i, ps.S.I)
+		}
+		d.dec = append(d.dec, ps)
+	}
+	return d, nil
+}
+
+func pvssDeal(suite abstract.Suite, args []string, in io.Reader, out io.Writer) error {
+	flags, keys, base := pvssFlags("pvss-deal")
+	t := flags.Int("t", 0, "threshold, defaults to a majority of the keys")
+	secretOut := flags.String("secret-out", "", "file to write the secret recovered by the trustees to, if any")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	X, err := readKeys(suite, *keys)
+	if err != nil {
+		return err
+	}
+	if *t == 0 {
+		*t = len(X)/2 + 1
+	}
+	if *t < 1 || *t > len(X) {
+		return fmt.Errorf("threshold %d out of range for %d keys", *t, len(X))
+	}
+	H := basePoint(suite, *base)
+	secret := suite.Scalar().Pick(random.Stream)
+	encShares, pubPoly, err := pvss.EncShares(suite, H, X, secret, *t)
+	if err != nil {
+		return err
+	}
+	_, commits := pubPoly.Info()
+	for _, C := range commits {
+		if err := writeLine(suite, out, "commit", C); err != nil {
+			return err
+		}
+	}
+	for _, s := range encShares {
+		if err := writeLine(suite, out, "share", s); err != nil {
+			return err
+		}
+	}
+	// The secret recovered by the trustees is sG. The deal goes to the
+	// trustees, so the secret is only written to its own file.
+	if *secretOut == "" {
+		return nil
+	}
+	var b bytes.Buffer
+	if err := writeLine(suite, &b, "secret", suite.Point().Mul(nil, secret)); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(*secretOut, b.Bytes(), 0600)
+}
+
+func pvssVerify(suite abstract.Suite, args []string, in io.Reader, out io.Writer) error {
+	flags, keys, base := pvssFlags("pvss-verify")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	X, err := readKeys(suite, *keys)
+	if err != nil {
+		return err
+	}
+	H := basePoint(suite, *base)
+	d, err := readDeal(suite, H, len(X), in)
+	if err != nil {
+		return err
+	}
+	bad := 0
+	for i, s := range d.shares {
+		sH := d.poly.Eval(i).V
+		status := "ok"
+		if err := pvss.VerifyEncShare(suite, H, X[i], sH, s); err != nil {
+			status = "invalid"
+			bad++
+		}
+		if _, err := fmt.Fprintln(out, i, status); err != nil {
+			return err
+		}
+	}
+	if bad > len(X)-d.poly.Threshold() {
+		return fmt.Errorf("%d invalid shares, secret cannot be recovered", bad)
+	}
+	return nil
+}
+
+func pvssDecrypt(suite abstract.Suite, args []string, in io.Reader, out io.Writer) error {
+	flags, keys, base := pvssFlags("pvss-decrypt")
+	key := flags.String("key", "", "file with the private key, as written by keygen")
+	index := flags.Int("index", -1, "index of the share to decrypt, defaults to the index of the key")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	X, err := readKeys(suite, *keys)
+	if err != nil {
+		return err
+	}
+	x, err := readPrivate(suite, *key)
+	if err != nil {
+		return err
+	}
+	if *index < 0 {
+		pub := suite.Point().Mul(nil, x)
+		for i := range X {
+			if X[i].Equal(pub) {
+				*index = i
+			}
+		}
+		if *index < 0 {
+			return errors.New("private key matches none of the public keys")
+		}
+	}
+	if *index >= len(X) {
+		return fmt.Errorf("index %d out of range for %d keys", *index, len(X))
+	}
+	H := basePoint(suite, *base)
+	d, err := readDeal(suite, H, len(X), in)
+	if err != nil {
+		return err
+	}
+	i := *index
+	ds, err := pvss.DecShare(suite, H, X[i], d.poly.Eval(i).V, x, d.shares[i])
+	if err != nil {
+		return err
+	}
+	return writeLine(suite, out, "decshare", ds)
+}
+
+func pvssRecover(suite abstract.Suite, args []string, in io.Reader, out io.Writer) error {
+	flags, keys, base := pvssFlags("pvss-recover")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	X, err := readKeys(suite, *keys)
+	if err != nil {
+		return err
+	}
+	H := basePoint(suite, *base)
+	d, err := readDeal(suite, H, len(X), in)
+	if err != nil {
+		return err
+	}
+	var K []abstract.Point
+	var E []*pvss.PubVerShare
+	for _, ds := range d.dec {
+		K = append(K, X[ds.S.I])
+		E = append(E, d.shares[ds.S.I])
+	}
+	G := suite.Point().Base()
+	secret, err := pvss.RecoverSecret(suite, G, K, E, d.dec, d.poly.Threshold(), len(X))
+	if err != nil {
+		return err
+	}
+	return writeLine(suite, out, "secret", secret)
+}
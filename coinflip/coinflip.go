@@ -0,0 +1,300 @@
+// Package coinflip implements a fair multiparty coin-flipping protocol in the
+// commit-then-reveal paradigm. Plain commit-then-reveal is biasable: the last
+// party to reveal sees everyone else's contribution and may decide to abort.
+// Here every participant instead commits to its random contribution s by
+// dealing it with PVSS to all participants. The protocol runs in three phases
+// over a broadcast channel:
+//
+// 1. Commit: each participant broadcasts a Deal, i.e., the PVSS encrypted
+// shares of its secret s together with the commitments to the sharing
+// polynomial. Every participant verifies the deals and, once the commit phase
+// is closed, the valid deals define the set of qualified dealers.
+//
+// 2. Reveal: each participant broadcasts its secret s, which is checked
+// against the commitment sH of its deal.
+//
+// 3. Recovery: for every qualified dealer that stays silent, the participants
+// broadcast their decrypted shares of the dealer's secret. Any threshold of
+// valid shares suffices to rebuild the dealer's contribution sG.
+//
+// The output is the hash of the sum of all contributions sG. As long as a
+// threshold of participants is honest, the contributions of all qualified
+// dealers are fixed at the end of the commit phase and can always be
+// recovered, so aborting after seeing other contributions does not bias the
+// result.
+package coinflip
+
+import (
+	"crypto/cipher"
+	"errors"
+
+	"github.com/dedis/crypto/abstract"
+	"github.com/dedis/crypto/pvss"
+	"github.com/dedis/crypto/share"
+)
+
+// Some error definitions
+var errorIndex = errors.New("participant index out of range")
+var errorKeyPair = errors.New("private key does not match public key")
+var errorDuplicate = errors.New("duplicate message")
+var errorDeal = errors.New("invalid deal")
+var errorCommitOpen = errors.New("commit phase is still open")
+var errorCommitClosed = errors.New("commit phase is already closed")
+var errorUnqualified = errors.New("dealer is not qualified")
+var errorReveal = errors.New("revealed secret does not match commitment")
+var errorRecovery = errors.New("invalid recovery share")
+var errorIncomplete = errors.New("missing contributions")
+
+// Deal is a participant's commitment to its contribution.
+type Deal struct {
+	Dealer  int                 // Index of the dealer
+	Shares  []*pvss.PubVerShare // Encrypted shares, one per participant
+	Commits []abstract.Point    // Commitments to the sharing polynomial
+}
+
+// Reveal opens a participant's commitment.
+type Reveal struct {
+	Dealer int             // Index of the dealer
+	Secret abstract.Scalar // Contribution of the dealer
+}
+
+// Recovery is a participant's decrypted share of a silent dealer's
+// contribution.
+type Recovery struct {
+	Dealer int               // Index of the silent dealer
+	Share  *pvss.PubVerShare // Decrypted share of the participant
+}
+
+// Participant holds the state of one party of a coin-flipping run.
+type Participant struct {
+	suite  abstract.Suite
+	H      abstract.Point   // Base point of the PVSS commitments
+	X      []abstract.Point // Public keys of all participants
+	index  int              // Own index
+	x      abstract.Scalar  // Own private key
+	t      int              // Recovery threshold
+	secret abstract.Scalar  // Own contribution
+	closed bool             // Whether the commit phase is closed
+
+	deals    map[int]*Deal
+	pubPolys map[int]*share.PubPoly
+	contribs map[int]abstract.Point
+	shares   map[int]map[int]*pvss.PubVerShare
+}
+
+// NewParticipant creates the participant with the given index among the
+// participants with public keys X. The private key x must correspond to
+// X[index], H is the base point of the commitments which must be the same for
+// all participants, and t is the number of participants required to recover
+// the contribution of a silent dealer.
+func NewParticipant(suite abstract.Suite, H abstract.Point, X []abstract.Point, index int, x abstract.Scalar, t int, rand cipher.Stream) (*Participant, error) {
+	if index < 0 || index >= len(X) {
+		return nil, errorIndex
+	}
+	if !suite.Point().Mul(nil, x).Equal(X[index]) {
+		return nil, errorKeyPair
+	}
+	return &Participant{
+		suite:    suite,
+		H:        H,
+		X:        X,
+		index:    index,
+		x:        x,
+		t:        t,
+		secret:   suite.Scalar().Pick(rand),
+		deals:    make(map[int]*Deal),
+		pubPolys: make(map[int]*share.PubPoly),
+		contribs: make(map[int]abstract.Point),
+		shares:   make(map[int]map[int]*pvss.PubVerShare),
+	}, nil
+}
+
+// Deal creates the participant's deal, which is to be broadcast to all other
+// participants during the commit phase.
+func (p *Participant) Deal() (*Deal, error) {
+	encShares, pubPoly, err := pvss.EncShares(p.suite, p.H, p.X, p.secret, p.t)
+	if err != nil {
+		return nil, err
+	}
+	_, commits := pubPoly.Info()
+	d := &Deal{Dealer: p.index, Shares: encShares, Commits: commits}
+	if err := p.ProcessDeal(d); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// ProcessDeal verifies the deal of another participant and, if valid, adds
+// the dealer to the set of qualified dealers.
+func (p *Participant) ProcessDeal(d *Deal) error {
+	if p.closed {
+		return errorCommitClosed
+	}
+	if d.Dealer < 0 || d.Dealer >= len(p.X) {
+		return errorIndex
+	}
+	if _, ok := p.deals[d.Dealer]; ok {
+		return errorDuplicate
+	}
+	n := len(p.X)
+	if len(d.Shares) != n || len(d.Commits) != p.t {
+		return errorDeal
+	}
+	pubPoly := share.NewPubPoly(p.suite, p.H, d.Commits)
+	sH := make([]abstract.Point, n)
+	for i, s := range d.Shares {
+		if s == nil || s.S.I != i {
+			return errorDeal
+		}
+		sH[i] = pubPoly.Eval(i).V
+	}
+	if _, good, err := pvss.VerifyEncShareBatch(p.suite, p.H, p.X, sH, d.Shares); err != nil || len(good) != n {
+		return errorDeal
+	}
+	p.deals[d.Dealer] = d
+	p.pubPolys[d.Dealer] = pubPoly
+	return nil
+}
+
+// Reveal closes the commit phase and returns the participant's opening, which
+// is to be broadcast to all other participants. No further deals are accepted
+// afterwards.
+func (p *Participant) Reveal() *Reveal {
+	p.closed = true
+	if _, ok := p.deals[p.index]; ok {
+		p.contribs[p.index] = p.suite.Point().Mul(nil, p.secret)
+	}
+	return &Reveal{Dealer: p.index, Secret: p.secret}
+}
+
+// ProcessReveal checks the opening of a qualified dealer against its
+// commitment.
+func (p *Participant) ProcessReveal(r *Reveal) error {
+	if !p.closed {
+		return errorCommitOpen
+	}
+	pubPoly, ok := p.pubPolys[r.Dealer]
+	if !ok {
+		return errorUnqualified
+	}
+	if _, ok := p.contribs[r.Dealer]; ok {
+		return errorDuplicate
+	}
+	if !p.suite.Point().Mul(p.H, r.Secret).Equal(pubPoly.Commit()) {
+		return errorReveal
+	}
+	p.contribs[r.Dealer] = p.suite.Point().Mul(nil, r.Secret)
+	return nil
+}
+
+// Missing returns the indices of the qualified dealers whose contributions
+// are not yet known.
+func (p *Participant) Missing() []int {
+	var missing []int
+	for i := range p.X {
+		if _, ok := p.deals[i]; !ok {
+			continue
+		}
+		if _, ok := p.contribs[i]; !ok {
+			missing = append(missing, i)
+		}
+	}
+	return missing
+}
+
+// Recover decrypts the participant's share of the contribution of the given
+// silent dealer. The result is to be broadcast to all other participants.
+func (p *Participant) Recover(dealer int) (*Recovery, error) {
+	if !p.closed {
+		return nil, errorCommitOpen
+	}
+	d, ok := p.deals[dealer]
+	if !ok {
+		return nil, errorUnqualified
+	}
+	sH := p.pubPolys[dealer].Eval(p.index).V
+	ds, err := pvss.DecShare(p.suite, p.H, p.X[p.index], sH, p.x, d.Shares[p.index])
+	if err != nil {
+		return nil, err
+	}
+	r := &Recovery{Dealer: dealer, Share: ds}
+	if err := p.ProcessRecovery(r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// ProcessRecovery verifies a decrypted share of a silent dealer's
+// contribution and rebuilds the contribution once a threshold of valid shares
+// has been collected.
+func (p *Participant) ProcessRecovery(r *Recovery) error {
+	if !p.closed {
+		return errorCommitOpen
+	}
+	d, ok := p.deals[r.Dealer]
+	if !ok {
+		return errorUnqualified
+	}
+	if _, ok := p.contribs[r.Dealer]; ok {
+		// Contribution already known, nothing left to do
+		return nil
+	}
+	if r.Share == nil {
+		return errorRecovery
+	}
+	i := r.Share.S.I
+	if i < 0 || i >= len(p.X) {
+		return errorIndex
+	}
+	G := p.suite.Point().Base()
+	if err := pvss.VerifyDecShare(p.suite, G, p.X[i], d.Shares[i], r.Share); err != nil {
+		return errorRecovery
+	}
+	if p.shares[r.Dealer] == nil {
+		p.shares[r.Dealer] = make(map[int]*pvss.PubVerShare)
+	}
+	if _, ok := p.shares[r.Dealer][i]; ok {
+		return errorDuplicate
+	}
+	p.shares[r.Dealer][i] = r.Share
+	if len(p.shares[r.Dealer]) < p.t {
+		return nil
+	}
+
+	var X []abstract.Point
+	var E, D []*pvss.PubVerShare
+	for j, ds := range p.shares[r.Dealer] {
+		X = append(X, p.X[j])
+		E = append(E, d.Shares[j])
+		D = append(D, ds)
+	}
+	S, err := pvss.RecoverSecret(p.suite, G, X, E, D, p.t, len(p.X))
+	if err != nil {
+		return err
+	}
+	p.contribs[r.Dealer] = S
+	delete(p.shares, r.Dealer)
+	return nil
+}
+
+// Result returns the outcome of the coin flip, i.e., the hash of the sum of
+// the contributions of all qualified dealers. It fails as long as some of the
+// contributions are missing.
+func (p *Participant) Result() ([]byte, error) {
+	if !p.closed {
+		return nil, errorCommitOpen
+	}
+	if len(p.Missing()) > 0 {
+		return nil, errorIncomplete
+	}
+	S := p.suite.Point().Null()
+	for _, C := range p.contribs {
+		S.Add(S, C)
+	}
+	h := p.suite.Hash()
+	h.Write([]byte("coinflip"))
+	if _, err := S.MarshalTo(h); err != nil {
+		return nil, err
+	}
+	return h.Sum(nil), nil
+}
@@ -0,0 +1,150 @@
+package coinflip
+
+import (
+	"testing"
+
+	"github.com/dedis/crypto/abstract"
+	"github.com/dedis/crypto/ed25519"
+	"github.com/dedis/crypto/random"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var suite = ed25519.NewAES128SHA256Ed25519(false)
+
+func setup(t *testing.T, n, th int) []*Participant {
+	H, _ := suite.Point().Pick(nil, suite.Cipher([]byte("coinflip-H")))
+	x := make([]abstract.Scalar, n)
+	X := make([]abstract.Point, n)
+	for i := range x {
+		x[i] = suite.Scalar().Pick(random.Stream)
+		X[i] = suite.Point().Mul(nil, x[i])
+	}
+	parts := make([]*Participant, n)
+	for i := range parts {
+		p, err := NewParticipant(suite, H, X, i, x[i], th, random.Stream)
+		require.Nil(t, err)
+		parts[i] = p
+	}
+	return parts
+}
+
+func broadcastDeals(t *testing.T, parts []*Participant) {
+	for i, p := range parts {
+		d, err := p.Deal()
+		require.Nil(t, err)
+		for j, q := range parts {
+			if j != i {
+				require.Nil(t, q.ProcessDeal(d))
+			}
+		}
+	}
+}
+
+func TestCoinFlip(t *testing.T) {
+	n, th := 5, 3
+	parts := setup(t, n, th)
+	broadcastDeals(t, parts)
+
+	// Participants 1 and 3 go silent after the commit phase
+	silent := map[int]bool{1: true, 3: true}
+	reveals := make([]*Reveal, n)
+	for i, p := range parts {
+		reveals[i] = p.Reveal()
+	}
+	for i, r := range reveals {
+		if silent[i] {
+			continue
+		}
+		for j, q := range parts {
+			if j != i && !silent[j] {
+				require.Nil(t, q.ProcessReveal(r))
+			}
+		}
+	}
+
+	honest := []*Participant{parts[0], parts[2], parts[4]}
+	for _, p := range honest {
+		_, err := p.Result()
+		assert.Equal(t, errorIncomplete, err)
+		assert.Equal(t, []int{1, 3}, p.Missing())
+	}
+
+	// Recovery of the silent participants' contributions
+	for _, p := range honest {
+		for _, dealer := range p.Missing() {
+			r, err := p.Recover(dealer)
+			require.Nil(t, err)
+			for _, q := range honest {
+				if q != p {
+					require.Nil(t, q.ProcessRecovery(r))
+				}
+			}
+		}
+	}
+
+	// Expected output from the contributions of all participants
+	S := suite.Point().Null()
+	for _, r := range reveals {
+		S.Add(S, suite.Point().Mul(nil, r.Secret))
+	}
+	h := suite.Hash()
+	h.Write([]byte("coinflip"))
+	_, err := S.MarshalTo(h)
+	require.Nil(t, err)
+	expected := h.Sum(nil)
+
+	for _, p := range honest {
+		assert.Empty(t, p.Missing())
+		out, err := p.Result()
+		require.Nil(t, err)
+		assert.Equal(t, expected, out)
+	}
+}
+
+func TestCoinFlipInvalid(t *testing.T) {
+	n, th := 4, 3
+	parts := setup(t, n, th)
+	p, q := parts[0], parts[1]
+
+	d, err := q.Deal()
+	require.Nil(t, err)
+	assert.Equal(t, errorDuplicate, q.ProcessDeal(d))
+
+	// A deal with a tampered encrypted share is rejected
+	bad := *d
+	bad.Shares = append(bad.Shares[:0:0], d.Shares...)
+	s := *d.Shares[2]
+	s.S.V = suite.Point().Base()
+	bad.Shares[2] = &s
+	assert.Equal(t, errorDeal, p.ProcessDeal(&bad))
+	require.Nil(t, p.ProcessDeal(d))
+	require.Nil(t, parts[2].ProcessDeal(d))
+	require.Nil(t, parts[3].ProcessDeal(d))
+
+	// Reveals are only accepted after the commit phase and must match
+	assert.Equal(t, errorCommitOpen, p.ProcessReveal(q.Reveal()))
+	p.Reveal()
+	assert.Equal(t, errorCommitClosed, p.ProcessDeal(d))
+	assert.Equal(t, errorReveal, p.ProcessReveal(&Reveal{Dealer: 1, Secret: suite.Scalar().One()}))
+	assert.Equal(t, errorUnqualified, p.ProcessReveal(parts[2].Reveal()))
+	require.Nil(t, p.ProcessReveal(q.Reveal()))
+	assert.Equal(t, errorDuplicate, p.ProcessReveal(q.Reveal()))
+
+	// Participant 0 never dealt and is therefore excluded from the result
+	assert.Empty(t, p.Missing())
+	_, err = p.Result()
+	assert.Nil(t, err)
+
+	// Recovery shares must carry a valid decryption proof
+	parts[2].Reveal()
+	parts[3].Reveal()
+	r, err := parts[3].Recover(1)
+	require.Nil(t, err)
+	require.Nil(t, parts[2].ProcessRecovery(r))
+	assert.Equal(t, errorDuplicate, parts[2].ProcessRecovery(r))
+	r.Share.S.I = 0
+	assert.Equal(t, errorRecovery, parts[2].ProcessRecovery(r))
+	_, err = NewParticipant(suite, p.H, p.X, 0, suite.Scalar().One(), th, random.Stream)
+	assert.Equal(t, errorKeyPair, err)
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/dedis/crypto/abstract"
 	"github.com/dedis/crypto/base64"
+	"github.com/dedis/crypto/group"
 	"github.com/dedis/crypto/random"
 	"github.com/dedis/crypto/util"
 )
@@ -35,6 +36,17 @@ func (p *KeyPair) Gen(suite abstract.Suite, random cipher.Stream) {
 	p.Public = suite.Point().Mul(nil, p.Secret)
 }
 
+// Equal checks whether p and q hold the same keys of the same ciphersuite.
+func (p *KeyPair) Equal(q *KeyPair) bool {
+	if p == nil || q == nil {
+		return p == q
+	}
+	if (p.Suite == nil) != (q.Suite == nil) || p.Suite != nil && p.Suite.String() != q.Suite.String() {
+		return false
+	}
+	return group.PointEqual(p.Public, q.Public) && group.ScalarEqual(p.Secret, q.Secret)
+}
+
 // PubId returns the base64-encoded HashId for this KeyPair's public key.
 func (p *KeyPair) PubId() string {
 	buf, _ := p.Public.MarshalBinary()
@@ -67,7 +79,6 @@ type KeyInfo struct {
 // If any of the configured public keys cannot be loaded for whatever reason,
 // such as a key's ciphersuite becoming no-longer-supported for example,
 // logs a warning but continues to load any other configured keys.
-//
 func (f *File) Keys(keys *Keys, suites map[string]abstract.Suite,
 	defaultSuite abstract.Suite) ([]KeyPair, error) {
 
@@ -2,7 +2,7 @@
 Package cosi is the Collective Signing implementation according to the paper of
 Bryan Ford: http://arxiv.org/pdf/1503.08768v1.pdf .
 
-Stages of CoSi
+# Stages of CoSi
 
 The CoSi-protocol has 4 stages:
 
@@ -52,13 +52,14 @@ import (
 // list of all co-signer's public keys involved in the round.
 // To use CoSi, call three different functions on it which corresponds to the last
 // three phases of the protocols:
-//  - (Create)Commitment: creates a new secret and its commitment. The output has to
-//  be passed up to the parent in the tree.
-//  - CreateChallenge: the root creates the challenge from receiving all the
-//  commitments. This output must be sent down the tree using Challenge()
-//  function.
-//  - (Create)Response: creates and possibly aggregates all responses and the
-//  output must be sent up into the tree.
+//   - (Create)Commitment: creates a new secret and its commitment. The output has to
+//     be passed up to the parent in the tree.
+//   - CreateChallenge: the root creates the challenge from receiving all the
+//     commitments. This output must be sent down the tree using Challenge()
+//     function.
+//   - (Create)Response: creates and possibly aggregates all responses and the
+//     output must be sent up into the tree.
+//
 // The root can then issue `Signature()` to get the final signature that can be
 // verified using `VerifySignature()`.
 // To handle missing signers, the signature generation will append a bitmask at
@@ -0,0 +1,266 @@
+// Package dkg implements Pedersen's distributed key generation. Each of the n
+// participants deals a Feldman VSS of a random secret to all participants
+// (see package feldman). The participants verify the deals, broadcast their
+// responses and the dealers justify complaints. The dealers whose sharings
+// are certified form the qualified set QUAL, and every participant's share of
+// the collective private key is the sum of the shares it received from QUAL:
+//
+//	dkg, err := NewDistKeyGenerator(suite, longterm, participants, t)
+//	c := dkg.Commitment()                 // broadcast c
+//	err = dkg.ProcessCommitment(c)        // for every c
+//	deals := dkg.Deals()                  // deals[i] privately to participant i
+//	resp, err := dkg.ProcessDeal(deal)    // for every deal, broadcast resp
+//	resp, err := dkg.Complain(i)          // if dealer i's deal is missing
+//	j, err := dkg.ProcessResponse(resp)   // for every response, broadcast j
+//	err = dkg.ProcessJustification(j)     // for every justification
+//	js, err := dkg.SetTimeout()           // once the rounds are over, broadcast js
+//	err = dkg.ProcessJustification(j)     // for every j in the others' js
+//	dks, err := dkg.DistKeyShare()
+//
+// The protocol assumes a synchronous network with a reliable broadcast
+// channel, so that all honest participants see the same responses and
+// justifications and agree on QUAL, and private channels for the deals. The
+// collective key is exactly random only in the absence of an adaptive
+// adversary that influences QUAL; applications that need a uniformly
+// distributed key should use a robust variant.
+//
+// A Resharer hands the collective key over to a new set of nodes with a new
+// threshold, see NewResharer.
+package dkg
+
+import (
+	"errors"
+
+	"github.com/dedis/crypto/abstract"
+	"github.com/dedis/crypto/share"
+	"github.com/dedis/crypto/share/feldman"
+)
+
+// Some error definitions
+var errorParticipant = errors.New("key not among the participants")
+var errorIndex = errors.New("invalid dealer index")
+var errorNotCertified = errors.New("not enough qualified dealers")
+
+// DistKeyShare is a participant's output of the DKG: its share of the
+// collective private key and the public commitment polynomial of the
+// collective key.
+type DistKeyShare struct {
+	Commits *share.PubPoly
+	Share   *share.PriShare
+}
+
+// Public returns the collective public key.
+func (d *DistKeyShare) Public() abstract.Point {
+	return d.Commits.Commit()
+}
+
+// Commitment is the broadcast commitment of dealer Index.
+type Commitment struct {
+	Index      int
+	Commitment *feldman.Commitment
+}
+
+// Deal is the deal of dealer Index to one participant.
+type Deal struct {
+	Index int
+	Deal  *feldman.Deal
+}
+
+// Response is a participant's response to the deal of dealer Index.
+type Response struct {
+	Index    int
+	Response *feldman.Response
+}
+
+// Justification is dealer Index's answer to a complaint against its deal.
+type Justification struct {
+	Index         int
+	Justification *feldman.Justification
+}
+
+// DistKeyGenerator runs the DKG for one participant.
+type DistKeyGenerator struct {
+	suite     abstract.Suite
+	index     int
+	dealer    *feldman.Dealer
+	verifiers []*feldman.Verifier // One per dealer
+}
+
+// NewDistKeyGenerator creates the DKG state of the participant with the
+// given long-term private key among the participants with the given
+// long-term public keys, for the threshold t.
+func NewDistKeyGenerator(suite abstract.Suite, longterm abstract.Scalar, participants []abstract.Point, t int) (*DistKeyGenerator, error) {
+	pub := suite.Point().Mul(nil, longterm)
+	index := -1
+	for i, P := range participants {
+		if P.Equal(pub) {
+			index = i
+		}
+	}
+	if index < 0 {
+		return nil, errorParticipant
+	}
+	dealer, err := feldman.NewDealer(suite, longterm, nil, participants, t)
+	if err != nil {
+		return nil, err
+	}
+	d := &DistKeyGenerator{suite: suite, index: index, dealer: dealer}
+	for _, P := range participants {
+		v, err := feldman.NewVerifier(suite, longterm, P, participants, t)
+		if err != nil {
+			return nil, err
+		}
+		d.verifiers = append(d.verifiers, v)
+	}
+	return d, nil
+}
+
+// Index returns the participant's index.
+func (d *DistKeyGenerator) Index() int {
+	return d.index
+}
+
+// Commitment returns the participant's commitment to broadcast to all
+// participants, including itself.
+func (d *DistKeyGenerator) Commitment() *Commitment {
+	return &Commitment{d.index, d.dealer.Commitment()}
+}
+
+// ProcessCommitment records the commitment of a dealer, which must precede
+// the dealer's deal and the responses to it.
+func (d *DistKeyGenerator) ProcessCommitment(c *Commitment) error {
+	if c.Index < 0 || c.Index >= len(d.verifiers) {
+		return errorIndex
+	}
+	return d.verifiers[c.Index].ProcessCommitment(c.Commitment)
+}
+
+// Deals returns the participant's deals, in participant order, including the
+// deal to itself, which it processes like any other.
+func (d *DistKeyGenerator) Deals() []*Deal {
+	deals := make([]*Deal, len(d.verifiers))
+	for i, fd := range d.dealer.Deals() {
+		deals[i] = &Deal{d.index, fd}
+	}
+	return deals
+}
+
+// ProcessDeal verifies the deal and returns the response to broadcast.
+func (d *DistKeyGenerator) ProcessDeal(deal *Deal) (*Response, error) {
+	if deal.Index < 0 || deal.Index >= len(d.verifiers) {
+		return nil, errorIndex
+	}
+	r, err := d.verifiers[deal.Index].ProcessDeal(deal.Deal)
+	if err != nil {
+		return nil, err
+	}
+	if deal.Index == d.index {
+		if _, err := d.dealer.ProcessResponse(r); err != nil {
+			return nil, err
+		}
+	}
+	return &Response{deal.Index, r}, nil
+}
+
+// Complain returns the complaint to broadcast if the deal of the given dealer
+// did not arrive in time, or nil if the participant has already responded.
+func (d *DistKeyGenerator) Complain(dealer int) (*Response, error) {
+	if dealer < 0 || dealer >= len(d.verifiers) {
+		return nil, errorIndex
+	}
+	r, err := d.verifiers[dealer].Complain()
+	if err != nil || r == nil {
+		return nil, err
+	}
+	return &Response{dealer, r}, nil
+}
+
+// ProcessResponse records the response of another participant. If the
+// response complains about this participant's deal, it returns the
+// justification to broadcast.
+func (d *DistKeyGenerator) ProcessResponse(r *Response) (*Justification, error) {
+	if r.Index < 0 || r.Index >= len(d.verifiers) {
+		return nil, errorIndex
+	}
+	if err := d.verifiers[r.Index].ProcessResponse(r.Response); err != nil {
+		return nil, err
+	}
+	if r.Index != d.index {
+		return nil, nil
+	}
+	j, err := d.dealer.ProcessResponse(r.Response)
+	if err != nil || j == nil {
+		return nil, err
+	}
+	if err := d.verifiers[d.index].ProcessJustification(j); err != nil {
+		return nil, err
+	}
+	return &Justification{d.index, j}, nil
+}
+
+// ProcessJustification checks the justification of another dealer.
+func (d *DistKeyGenerator) ProcessJustification(j *Justification) error {
+	if j.Index < 0 || j.Index >= len(d.verifiers) {
+		return errorIndex
+	}
+	return d.verifiers[j.Index].ProcessJustification(j.Justification)
+}
+
+// SetTimeout ends the response round. It returns the justifications of the
+// participants that did not respond to this participant's deal, which it
+// must broadcast.
+func (d *DistKeyGenerator) SetTimeout() ([]*Justification, error) {
+	fjs := d.dealer.SetTimeout()
+	for _, v := range d.verifiers {
+		v.SetTimeout()
+	}
+	js := make([]*Justification, len(fjs))
+	for i, j := range fjs {
+		if err := d.verifiers[d.index].ProcessJustification(j); err != nil {
+			return nil, err
+		}
+		js[i] = &Justification{d.index, j}
+	}
+	return js, nil
+}
+
+// QUAL returns the indices of the qualified dealers, whose sharings are
+// certified.
+func (d *DistKeyGenerator) QUAL() []int {
+	var qual []int
+	for i, v := range d.verifiers {
+		if v.Certified() && v.Deal() != nil {
+			qual = append(qual, i)
+		}
+	}
+	return qual
+}
+
+// Certified reports whether the participant can compute its share, i.e.,
+// whether at least a threshold of dealers is qualified.
+func (d *DistKeyGenerator) Certified() bool {
+	return len(d.QUAL()) >= d.dealer.Commits().Threshold()
+}
+
+// DistKeyShare returns the participant's share of the collective key, the
+// sum of the shares of the qualified dealers.
+func (d *DistKeyGenerator) DistKeyShare() (*DistKeyShare, error) {
+	if !d.Certified() {
+		return nil, errorNotCertified
+	}
+	var commits *share.PubPoly
+	v := d.suite.Scalar().Zero()
+	for _, i := range d.QUAL() {
+		deal := d.verifiers[i].Deal()
+		v.Add(v, deal.Share.V)
+		if commits == nil {
+			commits = d.verifiers[i].Commits()
+			continue
+		}
+		var err error
+		if commits, err = commits.Add(d.verifiers[i].Commits()); err != nil {
+			return nil, err
+		}
+	}
+	return &DistKeyShare{commits, &share.PriShare{I: d.index, V: v}}, nil
+}
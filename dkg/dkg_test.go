@@ -0,0 +1,168 @@
+package dkg
+
+import (
+	"testing"
+
+	"github.com/dedis/crypto/abstract"
+	"github.com/dedis/crypto/edwards"
+	"github.com/dedis/crypto/random"
+	"github.com/dedis/crypto/share"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var suite = edwards.NewAES128SHA256Ed25519(false)
+
+func setup(t *testing.T, n, th int) []*DistKeyGenerator {
+	keys := make([]abstract.Scalar, n)
+	pubs := make([]abstract.Point, n)
+	for i := range keys {
+		keys[i] = suite.Scalar().Pick(random.Stream)
+		pubs[i] = suite.Point().Mul(nil, keys[i])
+	}
+	dkgs := make([]*DistKeyGenerator, n)
+	for i := range dkgs {
+		var err error
+		dkgs[i], err = NewDistKeyGenerator(suite, keys[i], pubs, th)
+		require.Nil(t, err)
+		assert.Equal(t, i, dkgs[i].Index())
+	}
+	return dkgs
+}
+
+// run executes the DKG; tamper may modify or withhold deals in transit and the
+// justifications of the silent dealer are dropped.
+func run(t *testing.T, dkgs []*DistKeyGenerator, tamper func(dealer, to int, d *Deal) *Deal, silent int) {
+	for _, d := range dkgs {
+		c := d.Commitment()
+		for _, o := range dkgs {
+			require.Nil(t, o.ProcessCommitment(c))
+		}
+	}
+	var responses []*Response
+	for i, d := range dkgs {
+		for j, deal := range d.Deals() {
+			if tamper != nil {
+				deal = tamper(i, j, deal)
+			}
+			var r *Response
+			var err error
+			if deal != nil {
+				r, err = dkgs[j].ProcessDeal(deal)
+			} else {
+				r, err = dkgs[j].Complain(i)
+			}
+			require.Nil(t, err)
+			responses = append(responses, r)
+		}
+	}
+	var justs []*Justification
+	for _, r := range responses {
+		for _, d := range dkgs {
+			if d.Index() == r.Response.Index {
+				continue
+			}
+			j, err := d.ProcessResponse(r)
+			require.Nil(t, err)
+			if j != nil && j.Index != silent {
+				justs = append(justs, j)
+			}
+		}
+	}
+	for _, j := range justs {
+		for _, d := range dkgs {
+			if d.Index() != j.Index {
+				require.Nil(t, d.ProcessJustification(j))
+			}
+		}
+	}
+	for _, d := range dkgs {
+		js, err := d.SetTimeout()
+		require.Nil(t, err)
+		assert.Empty(t, js)
+	}
+}
+
+func check(t *testing.T, dkgs []*DistKeyGenerator, th, n int) {
+	var shares []*share.PriShare
+	var public abstract.Point
+	for _, d := range dkgs {
+		require.True(t, d.Certified())
+		dks, err := d.DistKeyShare()
+		require.Nil(t, err)
+		require.True(t, dks.Commits.Check(dks.Share))
+		if public == nil {
+			public = dks.Public()
+		}
+		assert.True(t, public.Equal(dks.Public()))
+		shares = append(shares, dks.Share)
+	}
+	secret, err := share.RecoverSecret(suite, shares, th, n)
+	require.Nil(t, err)
+	assert.True(t, public.Equal(suite.Point().Mul(nil, secret)))
+}
+
+func TestDKG(t *testing.T) {
+	n, th := 5, 3
+	dkgs := setup(t, n, th)
+	run(t, dkgs, nil, -1)
+	for _, d := range dkgs {
+		assert.Equal(t, []int{0, 1, 2, 3, 4}, d.QUAL())
+	}
+	check(t, dkgs, th, n)
+}
+
+func TestDKGComplaint(t *testing.T) {
+	n, th := 5, 3
+	dkgs := setup(t, n, th)
+	run(t, dkgs, func(dealer, to int, d *Deal) *Deal {
+		if dealer != 1 || to != 3 {
+			return d
+		}
+		bad := *d.Deal
+		bad.Share = &share.PriShare{I: to, V: suite.Scalar().One()}
+		return &Deal{d.Index, &bad}
+	}, -1)
+	for _, d := range dkgs {
+		assert.Equal(t, []int{0, 1, 2, 3, 4}, d.QUAL())
+	}
+	check(t, dkgs, th, n)
+}
+
+func TestDKGMissingDeal(t *testing.T) {
+	n, th := 5, 3
+	dkgs := setup(t, n, th)
+	// Dealer 1 withholds its deal to participant 3, which complains and gets
+	// its share from the justification
+	run(t, dkgs, func(dealer, to int, d *Deal) *Deal {
+		if dealer == 1 && to == 3 {
+			return nil
+		}
+		return d
+	}, -1)
+	for _, d := range dkgs {
+		assert.Equal(t, []int{0, 1, 2, 3, 4}, d.QUAL())
+	}
+	check(t, dkgs, th, n)
+}
+
+func TestDKGUnjustified(t *testing.T) {
+	n, th := 5, 3
+	dkgs := setup(t, n, th)
+	// Dealer 2 sends a bad share and never answers the complaint
+	run(t, dkgs, func(dealer, to int, d *Deal) *Deal {
+		if dealer != 2 || to != 4 {
+			return d
+		}
+		bad := *d.Deal
+		bad.Share = &share.PriShare{I: to, V: suite.Scalar().One()}
+		return &Deal{d.Index, &bad}
+	}, 2)
+	for _, d := range dkgs {
+		if d.Index() == 2 {
+			continue
+		}
+		assert.Equal(t, []int{0, 1, 3, 4}, d.QUAL())
+	}
+	check(t, append(dkgs[:2:2], dkgs[3:]...), th, n)
+}
@@ -0,0 +1,152 @@
+package dkg
+
+import (
+	"errors"
+
+	"github.com/dedis/crypto/abstract"
+	"github.com/dedis/crypto/protocol"
+)
+
+// Some error definitions
+var errorPayload = errors.New("unexpected message payload")
+var errorSender = errors.New("message index does not match its sender")
+
+// Party runs the DKG of one participant as a protocol.ProtocolState. On
+// creation it broadcasts its commitment and sends its deals privately; it
+// broadcasts a response to every deal and a justification to every complaint
+// against its own deal. Messages about a dealer whose commitment has not
+// arrived yet are kept until it does. The party is done once the sharings of
+// all dealers are certified or, if some participants fail, once the caller
+// ended the rounds with SetTimeout. Payloads are *Commitment, *Deal,
+// *Response and *Justification.
+type Party struct {
+	protocol.Outbox
+	dkg     *DistKeyGenerator
+	pending []*protocol.Message // Messages waiting for a dealer's commitment
+	timeout bool
+}
+
+// NewParty creates the DKG party with the given long-term private key among
+// the participants with the given long-term public keys, for the threshold
+// t.
+func NewParty(suite abstract.Suite, longterm abstract.Scalar, participants []abstract.Point, t int) (*Party, error) {
+	d, err := NewDistKeyGenerator(suite, longterm, participants, t)
+	if err != nil {
+		return nil, err
+	}
+	p := &Party{dkg: d}
+	c := d.Commitment()
+	if err := d.ProcessCommitment(c); err != nil {
+		return nil, err
+	}
+	p.Send(&protocol.Message{From: d.index, To: protocol.Broadcast, Payload: c})
+	for i, deal := range d.Deals() {
+		if i != d.index {
+			p.Send(&protocol.Message{From: d.index, To: i, Payload: deal})
+			continue
+		}
+		r, err := d.ProcessDeal(deal)
+		if err != nil {
+			return nil, err
+		}
+		p.Send(&protocol.Message{From: d.index, To: protocol.Broadcast, Payload: r})
+	}
+	return p, nil
+}
+
+// ProcessMessage handles a commitment, a deal, a response or a
+// justification.
+func (p *Party) ProcessMessage(msg *protocol.Message) error {
+	var dealer, sender int
+	switch payload := msg.Payload.(type) {
+	case *Commitment:
+		dealer, sender = payload.Index, payload.Index
+	case *Deal:
+		dealer, sender = payload.Index, payload.Index
+	case *Response:
+		dealer, sender = payload.Index, payload.Response.Index
+	case *Justification:
+		dealer, sender = payload.Index, payload.Index
+	default:
+		return errorPayload
+	}
+	if sender != msg.From {
+		return errorSender
+	}
+	if dealer < 0 || dealer >= len(p.dkg.verifiers) {
+		return errorIndex
+	}
+	if _, ok := msg.Payload.(*Commitment); !ok && p.dkg.verifiers[dealer].Commits() == nil {
+		p.pending = append(p.pending, msg)
+		return nil
+	}
+	return p.process(msg)
+}
+
+func (p *Party) process(msg *protocol.Message) error {
+	d := p.dkg
+	switch payload := msg.Payload.(type) {
+	case *Commitment:
+		if err := d.ProcessCommitment(payload); err != nil {
+			return err
+		}
+		pending := p.pending
+		p.pending = nil
+		for _, m := range pending {
+			// Errors of early messages are not attributable to this one
+			p.ProcessMessage(m)
+		}
+	case *Deal:
+		r, err := d.ProcessDeal(payload)
+		if err != nil {
+			return err
+		}
+		p.Send(&protocol.Message{From: d.index, To: protocol.Broadcast, Payload: r})
+	case *Response:
+		j, err := d.ProcessResponse(payload)
+		if err != nil {
+			return err
+		}
+		if j != nil {
+			p.Send(&protocol.Message{From: d.index, To: protocol.Broadcast, Payload: j})
+		}
+	case *Justification:
+		return d.ProcessJustification(payload)
+	}
+	return nil
+}
+
+// SetTimeout ends the rounds, e.g., once the synchrony bound has passed
+// without the party being done. It broadcasts the justifications of the
+// participants that did not respond to this party's deal.
+func (p *Party) SetTimeout() error {
+	js, err := p.dkg.SetTimeout()
+	if err != nil {
+		return err
+	}
+	for _, j := range js {
+		p.Send(&protocol.Message{From: p.dkg.index, To: protocol.Broadcast, Payload: j})
+	}
+	p.timeout = true
+	return nil
+}
+
+// Done returns true once the sharings of all dealers are certified or the
+// rounds were ended with SetTimeout.
+func (p *Party) Done() bool {
+	if p.timeout {
+		return true
+	}
+	for _, v := range p.dkg.verifiers {
+		if !v.Certified() {
+			return false
+		}
+	}
+	return true
+}
+
+// DistKeyGenerator returns the underlying DKG state, e.g., to read QUAL and
+// the participant's DistKeyShare once the party is done.
+func (p *Party) DistKeyGenerator() *DistKeyGenerator {
+	return p.dkg
+}
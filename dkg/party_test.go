@@ -0,0 +1,71 @@
+package dkg
+
+import (
+	"testing"
+
+	"github.com/dedis/crypto/abstract"
+	"github.com/dedis/crypto/protocol"
+	"github.com/dedis/crypto/protocol/protocoltest"
+	"github.com/dedis/crypto/random"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func partySetup(t *testing.T, n, th int) ([]*Party, []protocol.ProtocolState) {
+	keys := make([]abstract.Scalar, n)
+	pubs := make([]abstract.Point, n)
+	for i := range keys {
+		keys[i] = suite.Scalar().Pick(random.Stream)
+		pubs[i] = suite.Point().Mul(nil, keys[i])
+	}
+	parties := make([]*Party, n)
+	states := make([]protocol.ProtocolState, n)
+	for i := range parties {
+		var err error
+		parties[i], err = NewParty(suite, keys[i], pubs, th)
+		require.Nil(t, err)
+		states[i] = parties[i]
+	}
+	return parties, states
+}
+
+func TestParty(t *testing.T) {
+	n, th := 5, 3
+	parties, states := partySetup(t, n, th)
+	rejected, err := protocol.Run(states)
+	require.Nil(t, err)
+	assert.Empty(t, rejected)
+	dkgs := make([]*DistKeyGenerator, n)
+	for i, p := range parties {
+		dkgs[i] = p.DistKeyGenerator()
+		assert.Equal(t, []int{0, 1, 2, 3, 4}, dkgs[i].QUAL())
+	}
+	check(t, dkgs, th, n)
+}
+
+func TestPartyTimeout(t *testing.T) {
+	// A crashed participant stalls the rounds until they time out
+	n, th := 5, 3
+	parties, states := partySetup(t, n, th)
+	net := &protocoltest.Network{States: states, Behaviors: []protocoltest.Behavior{protocoltest.Drop(3)}}
+	_, err := net.Run()
+	require.NotNil(t, err)
+	for _, p := range parties {
+		require.Nil(t, p.SetTimeout())
+	}
+	res, err := net.Run()
+	require.Nil(t, err)
+	for _, r := range res.Rejected {
+		// Only the crashed participant sees the justifications of its own
+		// approvals, which nobody else received
+		assert.Equal(t, 3, r.To)
+	}
+	var dkgs []*DistKeyGenerator
+	for i, p := range parties {
+		if i != 3 {
+			dkgs = append(dkgs, p.DistKeyGenerator())
+			assert.Equal(t, []int{0, 1, 2, 4}, p.DistKeyGenerator().QUAL())
+		}
+	}
+	check(t, dkgs, th, n)
+}
@@ -0,0 +1,295 @@
+package dkg
+
+import (
+	"errors"
+
+	"github.com/dedis/crypto/abstract"
+	"github.com/dedis/crypto/share"
+	"github.com/dedis/crypto/share/feldman"
+)
+
+// Some error definitions of the resharing
+var errorNotOld = errors.New("key not among the old nodes")
+var errorNotNew = errors.New("key not among the new nodes")
+var errorOldShare = errors.New("share does not belong to the node")
+var errorPublic = errors.New("reshared key differs from the collective key")
+
+// Resharing hands the collective key from the old nodes, which hold
+// DistKeyShares with threshold t, over to a new set of nodes with a new
+// threshold, without reconstructing the key. Every old node deals its share
+// with Feldman VSS to the new nodes, using the same messages as the DKG. A
+// new node accepts an old node's sharing only if it is certified and the
+// constant term of its commitments is the old node's public share of the
+// collective key. The new share is the Lagrange interpolation at 0 of the
+// sub-shares of t accepted old nodes, so the new shares are shares of the
+// same collective key:
+//
+//	r, err := NewResharer(suite, longterm, oldNodes, oldCommits, dks, newNodes, newT)
+//	c := r.Commitment()                 // old nodes: broadcast c
+//	err = r.ProcessCommitment(c)        // new nodes, for every c
+//	deals := r.Deals()                  // old nodes: deals[i] to new node i
+//	resp, err := r.ProcessDeal(deal)    // new nodes: broadcast resp
+//	resp, err := r.Complain(i)          // new nodes, if old node i's deal is missing
+//	j, err := r.ProcessResponse(resp)   // all nodes: broadcast j
+//	err = r.ProcessJustification(j)     // new nodes
+//	js, err := r.SetTimeout()           // old nodes: broadcast js
+//	err = r.ProcessJustification(j)     // new nodes, for every j in the others' js
+//	dks, err := r.DistKeyShare()        // new nodes
+//
+// A node may belong to both sets; dks is nil for nodes that only join.
+
+// Resharer runs the resharing for one node.
+type Resharer struct {
+	suite      abstract.Suite
+	oldIndex   int // -1 if not an old node
+	newIndex   int // -1 if not a new node
+	oldCommits *share.PubPoly
+	dealer     *feldman.Dealer     // Old nodes
+	verifiers  []*feldman.Verifier // New nodes, one per old node
+}
+
+func indexOf(participants []abstract.Point, pub abstract.Point) int {
+	for i, P := range participants {
+		if P.Equal(pub) {
+			return i
+		}
+	}
+	return -1
+}
+
+// NewResharer creates the resharing state of the node with the given
+// long-term private key. oldNodes and newNodes are the long-term public keys
+// of both sets, oldCommits the public commitment polynomial of the
+// collective key and newT the new threshold. An old node passes its
+// DistKeyShare, a node that only joins passes nil.
+func NewResharer(suite abstract.Suite, longterm abstract.Scalar, oldNodes []abstract.Point, oldCommits *share.PubPoly, dks *DistKeyShare, newNodes []abstract.Point, newT int) (*Resharer, error) {
+	pub := suite.Point().Mul(nil, longterm)
+	r := &Resharer{
+		suite:      suite,
+		oldIndex:   indexOf(oldNodes, pub),
+		newIndex:   indexOf(newNodes, pub),
+		oldCommits: oldCommits,
+	}
+	if dks != nil {
+		if r.oldIndex < 0 {
+			return nil, errorNotOld
+		}
+		if dks.Share.I != r.oldIndex || !oldCommits.Check(dks.Share) {
+			return nil, errorOldShare
+		}
+		var err error
+		if r.dealer, err = feldman.NewDealer(suite, longterm, dks.Share.V, newNodes, newT); err != nil {
+			return nil, err
+		}
+	} else if r.newIndex < 0 {
+		return nil, errorNotNew
+	}
+	if r.newIndex >= 0 {
+		for _, P := range oldNodes {
+			v, err := feldman.NewVerifier(suite, longterm, P, newNodes, newT)
+			if err != nil {
+				return nil, err
+			}
+			r.verifiers = append(r.verifiers, v)
+		}
+	}
+	return r, nil
+}
+
+// OldIndex returns the node's index among the old nodes, or -1.
+func (r *Resharer) OldIndex() int {
+	return r.oldIndex
+}
+
+// NewIndex returns the node's index among the new nodes, or -1.
+func (r *Resharer) NewIndex() int {
+	return r.newIndex
+}
+
+// Commitment returns an old node's commitment to broadcast, or nil for a node
+// that only joins.
+func (r *Resharer) Commitment() *Commitment {
+	if r.dealer == nil {
+		return nil
+	}
+	return &Commitment{r.oldIndex, r.dealer.Commitment()}
+}
+
+// ProcessCommitment records the commitment of an old node, which must precede
+// its deal and the responses to it.
+func (r *Resharer) ProcessCommitment(c *Commitment) error {
+	if r.newIndex < 0 {
+		return errorNotNew
+	}
+	if c.Index < 0 || c.Index >= len(r.verifiers) {
+		return errorIndex
+	}
+	return r.verifiers[c.Index].ProcessCommitment(c.Commitment)
+}
+
+// Deals returns an old node's deals, in the order of the new nodes, or nil
+// for a node that only joins.
+func (r *Resharer) Deals() []*Deal {
+	if r.dealer == nil {
+		return nil
+	}
+	fds := r.dealer.Deals()
+	deals := make([]*Deal, len(fds))
+	for i, fd := range fds {
+		deals[i] = &Deal{r.oldIndex, fd}
+	}
+	return deals
+}
+
+// ProcessDeal verifies the deal of an old node to this new node and returns
+// the response to broadcast.
+func (r *Resharer) ProcessDeal(deal *Deal) (*Response, error) {
+	if r.newIndex < 0 {
+		return nil, errorNotNew
+	}
+	if deal.Index < 0 || deal.Index >= len(r.verifiers) {
+		return nil, errorIndex
+	}
+	resp, err := r.verifiers[deal.Index].ProcessDeal(deal.Deal)
+	if err != nil {
+		return nil, err
+	}
+	if deal.Index == r.oldIndex && r.dealer != nil {
+		if _, err := r.dealer.ProcessResponse(resp); err != nil {
+			return nil, err
+		}
+	}
+	return &Response{deal.Index, resp}, nil
+}
+
+// Complain returns the complaint of this new node to broadcast if the deal of
+// the given old node did not arrive in time, or nil if it has already
+// responded.
+func (r *Resharer) Complain(old int) (*Response, error) {
+	if r.newIndex < 0 {
+		return nil, errorNotNew
+	}
+	if old < 0 || old >= len(r.verifiers) {
+		return nil, errorIndex
+	}
+	resp, err := r.verifiers[old].Complain()
+	if err != nil || resp == nil {
+		return nil, err
+	}
+	return &Response{old, resp}, nil
+}
+
+// ProcessResponse records the response of a new node. If the response
+// complains about this old node's deal, it returns the justification to
+// broadcast.
+func (r *Resharer) ProcessResponse(resp *Response) (*Justification, error) {
+	if resp.Index < 0 || (r.newIndex >= 0 && resp.Index >= len(r.verifiers)) {
+		return nil, errorIndex
+	}
+	if r.newIndex >= 0 {
+		if err := r.verifiers[resp.Index].ProcessResponse(resp.Response); err != nil {
+			return nil, err
+		}
+	}
+	if resp.Index != r.oldIndex || r.dealer == nil {
+		return nil, nil
+	}
+	j, err := r.dealer.ProcessResponse(resp.Response)
+	if err != nil || j == nil {
+		return nil, err
+	}
+	if r.newIndex >= 0 {
+		if err := r.verifiers[r.oldIndex].ProcessJustification(j); err != nil {
+			return nil, err
+		}
+	}
+	return &Justification{r.oldIndex, j}, nil
+}
+
+// ProcessJustification checks the justification of an old node.
+func (r *Resharer) ProcessJustification(j *Justification) error {
+	if r.newIndex < 0 {
+		return errorNotNew
+	}
+	if j.Index < 0 || j.Index >= len(r.verifiers) {
+		return errorIndex
+	}
+	return r.verifiers[j.Index].ProcessJustification(j.Justification)
+}
+
+// SetTimeout ends the response round. For an old node it returns the
+// justifications of the new nodes that did not respond to its deal, which it
+// must broadcast.
+func (r *Resharer) SetTimeout() ([]*Justification, error) {
+	for _, v := range r.verifiers {
+		v.SetTimeout()
+	}
+	if r.dealer == nil {
+		return nil, nil
+	}
+	fjs := r.dealer.SetTimeout()
+	js := make([]*Justification, len(fjs))
+	for i, j := range fjs {
+		if r.newIndex >= 0 {
+			if err := r.verifiers[r.oldIndex].ProcessJustification(j); err != nil {
+				return nil, err
+			}
+		}
+		js[i] = &Justification{r.oldIndex, j}
+	}
+	return js, nil
+}
+
+// QUAL returns the indices of the old nodes whose sharings are certified and
+// commit to their public shares of the collective key.
+func (r *Resharer) QUAL() []int {
+	var qual []int
+	for i, v := range r.verifiers {
+		if v.Certified() && v.Deal() != nil && v.Commits().Commit().Equal(r.oldCommits.Eval(i).V) {
+			qual = append(qual, i)
+		}
+	}
+	return qual
+}
+
+// Certified reports whether this new node can compute its share, i.e.,
+// whether at least the old threshold of old nodes is qualified.
+func (r *Resharer) Certified() bool {
+	return r.newIndex >= 0 && len(r.QUAL()) >= r.oldCommits.Threshold()
+}
+
+// DistKeyShare returns the new node's share of the collective key, together
+// with the new public commitment polynomial, whose constant term is the
+// unchanged collective public key.
+func (r *Resharer) DistKeyShare() (*DistKeyShare, error) {
+	if !r.Certified() {
+		return nil, errorNotCertified
+	}
+	qual := r.QUAL()[:r.oldCommits.Threshold()]
+	ip, err := share.NewInterpolator(r.suite, qual, len(r.verifiers))
+	if err != nil {
+		return nil, err
+	}
+	v := r.suite.Scalar().Zero()
+	var points []abstract.Point
+	for _, i := range qual {
+		l := ip.Coefficient(i)
+		deal := r.verifiers[i].Deal()
+		v.Add(v, r.suite.Scalar().Mul(l, deal.Share.V))
+		_, commits := r.verifiers[i].Commits().Info()
+		if points == nil {
+			points = make([]abstract.Point, len(commits))
+			for m := range points {
+				points[m] = r.suite.Point().Null()
+			}
+		}
+		for m, C := range commits {
+			points[m].Add(points[m], r.suite.Point().Mul(C, l))
+		}
+	}
+	commits := share.NewPubPoly(r.suite, nil, points)
+	if !commits.Commit().Equal(r.oldCommits.Commit()) {
+		return nil, errorPublic
+	}
+	return &DistKeyShare{commits, &share.PriShare{I: r.newIndex, V: v}}, nil
+}
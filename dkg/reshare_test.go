@@ -0,0 +1,150 @@
+package dkg
+
+import (
+	"testing"
+
+	"github.com/dedis/crypto/abstract"
+	"github.com/dedis/crypto/random"
+	"github.com/dedis/crypto/share"
+	"github.com/dedis/crypto/share/feldman"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// reshareSetup runs a DKG among n old nodes with threshold th and prepares
+// the resharing to the last keep old nodes and joins fresh nodes. It returns
+// the resharers, the old commitments and the long-term keys of all nodes.
+func reshareSetup(t *testing.T, n, th, keep, joins, newT int) ([]*Resharer, *share.PubPoly, []abstract.Scalar) {
+	keys := make([]abstract.Scalar, n+joins)
+	pubs := make([]abstract.Point, n+joins)
+	for i := range keys {
+		keys[i] = suite.Scalar().Pick(random.Stream)
+		pubs[i] = suite.Point().Mul(nil, keys[i])
+	}
+	dkgs := make([]*DistKeyGenerator, n)
+	for i := range dkgs {
+		var err error
+		dkgs[i], err = NewDistKeyGenerator(suite, keys[i], pubs[:n], th)
+		require.Nil(t, err)
+	}
+	run(t, dkgs, nil, -1)
+	var oldCommits *share.PubPoly
+	rs := make([]*Resharer, n+joins)
+	newNodes := pubs[n-keep:]
+	for i := range rs {
+		var dks *DistKeyShare
+		if i < n {
+			var err error
+			dks, err = dkgs[i].DistKeyShare()
+			require.Nil(t, err)
+			oldCommits = dks.Commits
+		}
+		var err error
+		rs[i], err = NewResharer(suite, keys[i], pubs[:n], oldCommits, dks, newNodes, newT)
+		require.Nil(t, err)
+	}
+	return rs, oldCommits, keys
+}
+
+func reshareRun(t *testing.T, rs []*Resharer) {
+	byNew := make(map[int]*Resharer)
+	for _, r := range rs {
+		if r.NewIndex() >= 0 {
+			byNew[r.NewIndex()] = r
+		}
+	}
+	for _, r := range rs {
+		if c := r.Commitment(); c != nil {
+			for _, o := range byNew {
+				require.Nil(t, o.ProcessCommitment(c))
+			}
+		}
+	}
+	var responses []*Response
+	for _, r := range rs {
+		for j, deal := range r.Deals() {
+			resp, err := byNew[j].ProcessDeal(deal)
+			require.Nil(t, err)
+			responses = append(responses, resp)
+		}
+	}
+	var justs []*Justification
+	for _, resp := range responses {
+		for _, r := range rs {
+			if r.NewIndex() == resp.Response.Index {
+				continue
+			}
+			j, err := r.ProcessResponse(resp)
+			require.Nil(t, err)
+			if j != nil {
+				justs = append(justs, j)
+			}
+		}
+	}
+	for _, j := range justs {
+		for _, r := range rs {
+			if r.NewIndex() >= 0 && r.OldIndex() != j.Index {
+				require.Nil(t, r.ProcessJustification(j))
+			}
+		}
+	}
+	for _, r := range rs {
+		js, err := r.SetTimeout()
+		require.Nil(t, err)
+		assert.Empty(t, js)
+	}
+}
+
+func reshareCheck(t *testing.T, rs []*Resharer, oldCommits *share.PubPoly, newT int) {
+	var shares []*share.PriShare
+	for _, r := range rs {
+		if r.NewIndex() < 0 {
+			_, err := r.DistKeyShare()
+			assert.Equal(t, errorNotCertified, err)
+			continue
+		}
+		dks, err := r.DistKeyShare()
+		require.Nil(t, err)
+		assert.Equal(t, newT, dks.Commits.Threshold())
+		assert.True(t, dks.Public().Equal(oldCommits.Commit()))
+		require.True(t, dks.Commits.Check(dks.Share))
+		shares = append(shares, dks.Share)
+	}
+	secret, err := share.RecoverSecret(suite, shares, newT, len(shares))
+	require.Nil(t, err)
+	assert.True(t, oldCommits.Commit().Equal(suite.Point().Mul(nil, secret)))
+	_, err = share.RecoverSecret(suite, shares[:newT-1], newT, len(shares))
+	assert.NotNil(t, err)
+}
+
+func TestReshare(t *testing.T) {
+	n, th, keep, joins, newT := 4, 3, 2, 3, 4
+	rs, oldCommits, _ := reshareSetup(t, n, th, keep, joins, newT)
+	reshareRun(t, rs)
+	for _, r := range rs {
+		if r.NewIndex() >= 0 {
+			assert.Equal(t, []int{0, 1, 2, 3}, r.QUAL())
+		}
+	}
+	reshareCheck(t, rs, oldCommits, newT)
+}
+
+func TestReshareWrongSecret(t *testing.T) {
+	n, th, keep, joins, newT := 4, 3, 2, 3, 4
+	rs, oldCommits, keys := reshareSetup(t, n, th, keep, joins, newT)
+	// Old node 1 deals a random secret instead of its share
+	newNodes := make([]abstract.Point, keep+joins)
+	for i := range newNodes {
+		newNodes[i] = suite.Point().Mul(nil, keys[n-keep+i])
+	}
+	var err error
+	rs[1].dealer, err = feldman.NewDealer(suite, keys[1], nil, newNodes, newT)
+	require.Nil(t, err)
+	reshareRun(t, rs)
+	for _, r := range rs {
+		if r.NewIndex() >= 0 {
+			assert.Equal(t, []int{0, 2, 3}, r.QUAL())
+		}
+	}
+	reshareCheck(t, rs, oldCommits, newT)
+}
@@ -0,0 +1,655 @@
+package dkg
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+
+	"github.com/dedis/crypto/abstract"
+	"github.com/dedis/crypto/random"
+	"github.com/dedis/crypto/share"
+	"github.com/dedis/crypto/share/pedersen"
+	"github.com/dedis/crypto/sign"
+)
+
+// Some error definitions of the robust DKG
+var errorDealt = errors.New("deal already processed")
+var errorNoDeal = errors.New("no deal processed")
+var errorSignature = errors.New("invalid signature")
+var errorDuplicate = errors.New("duplicate message")
+var errorNoComplaint = errors.New("no matching complaint")
+var errorJustification = errors.New("invalid justification")
+var errorThreshold = errors.New("invalid threshold")
+var errorNoCommits = errors.New("no Feldman commitments")
+var errorComplaint = errors.New("invalid complaint")
+var errorMissingDeal = errors.New("no valid deal from qualified dealer")
+var errorReconstruct = errors.New("not enough shares to reconstruct dealer")
+var errorNoCommitment = errors.New("no commitment processed")
+
+// The robust DKG of Gennaro, Jarecki, Krawczyk and Rabin prevents the bias of
+// the collective key that Pedersen's DKG permits: there, a dealer learns
+// the public keys of the others' secrets from their Feldman commitments
+// before QUAL is fixed and can make honest dealers disqualified, or get
+// itself disqualified, to steer the collective key. In the robust DKG, the
+// dealers share their secrets with Pedersen VSS in the sharing phase, whose
+// commitments reveal nothing, and publish the Feldman commitments only in the
+// extraction phase, after QUAL is fixed. A dealer of QUAL whose Feldman
+// commitments do not match the shares, or who does not publish them, has its
+// secret reconstructed publicly by the other participants:
+//
+//	dkg, err := NewRobustDistKeyGenerator(suite, longterm, participants, t)
+//	sharing phase, with the same message flow as NewDistKeyGenerator:
+//	  c := dkg.Commitment()                   // broadcast c
+//	  err = dkg.ProcessCommitment(c)          // for every c
+//	  deals := dkg.Deals()                    // deals[i] privately to participant i
+//	  resp, err := dkg.ProcessDeal(deal)      // for every deal, broadcast resp
+//	  resp, err := dkg.Complain(i)            // if dealer i's deal is missing
+//	  j, err := dkg.ProcessResponse(resp)     // for every response, broadcast j
+//	  err = dkg.ProcessJustification(j)       // for every justification
+//	  js := dkg.SetTimeout()                  // once the rounds are over, broadcast js
+//	  err = dkg.ProcessJustification(j)       // for every j in the others' js
+//	extraction phase:
+//	  c, err := dkg.Commits()                 // broadcast c
+//	  complaint, err := dkg.ProcessCommits(c) // for every c, broadcast complaint
+//	  err = dkg.ProcessComplaint(complaint)   // for every complaint
+//	  for _, r := range dkg.Reconstructions() // broadcast r
+//	  err = dkg.ProcessReconstruct(r)         // for every r
+//	dks, err := dkg.DistKeyShare()
+//
+// The second base point H of the Pedersen commitments is derived by hashing,
+// so nobody knows its discrete logarithm.
+
+// RobustCommitment is the broadcast Pedersen commitment of dealer Index in
+// the sharing phase of the robust DKG, signed by the dealer. It fixes the
+// dealer's session, so that every participant can check its deal and
+// complain if it is missing.
+type RobustCommitment struct {
+	Index     int
+	Commits   []abstract.Point
+	Signature []byte
+}
+
+// RobustDeal is the deal of dealer Index to participant Share.I in the
+// sharing phase of the robust DKG: the participant's share, signed by the
+// dealer for its session.
+type RobustDeal struct {
+	Index     int
+	Share     *pedersen.Share
+	Signature []byte
+}
+
+// RobustResponse is participant Verifier's approval of, or complaint about,
+// its deal from dealer Index.
+type RobustResponse struct {
+	Index     int
+	Verifier  int
+	Approved  bool
+	Signature []byte
+}
+
+// RobustCommits are the Feldman commitments of dealer Index, published in the
+// extraction phase.
+type RobustCommits struct {
+	Index     int
+	Commits   *share.PubPoly
+	Signature []byte
+}
+
+// Reveal publishes a deal: a dealer's justification of a complaint or of a
+// missing response in the sharing phase, a participant's complaint against the Feldman commitments
+// of a dealer, or a participant's share of a dealer whose secret is
+// reconstructed. The deal is authenticated by the dealer's signature.
+type Reveal struct {
+	Deal *RobustDeal
+}
+
+// robustState is what a participant knows about one dealer.
+type robustState struct {
+	commits   *pedersen.Commits
+	sid       []byte
+	deal      *RobustDeal       // Valid deal to this participant
+	responses map[int]bool      // Approvals and complaints by verifier
+	justified map[int]bool      // Complaints answered with a valid deal
+	bad       bool              // Failed to justify a complaint or equivocated
+	feldman   *share.PubPoly    // Feldman commitments
+	accused   bool              // Valid complaint against the Feldman commitments
+	revealed  []*share.PriShare // Shares revealed for reconstruction
+}
+
+// RobustDistKeyGenerator runs the robust DKG for one participant.
+type RobustDistKeyGenerator struct {
+	suite        abstract.Suite
+	longterm     abstract.Scalar
+	index        int
+	participants []abstract.Point
+	t            int
+	H            abstract.Point
+	dealer       *pedersen.Dealer
+	commitment   *RobustCommitment
+	deals        []*RobustDeal
+	states       []*robustState
+	timeout      bool
+}
+
+// robustBase returns the second base point of the Pedersen commitments.
+func robustBase(suite abstract.Suite) abstract.Point {
+	H, _ := suite.Point().Pick(nil, suite.Cipher([]byte("dkg-robust-H")))
+	return H
+}
+
+// NewRobustDistKeyGenerator creates the state of a participant of the robust
+// DKG, whose collective key cannot be biased. It is not a drop-in
+// replacement for NewDistKeyGenerator: the sharing phase has the same message
+// flow, but with the message types of the robust DKG, and it is followed by
+// the extraction phase.
+func NewRobustDistKeyGenerator(suite abstract.Suite, longterm abstract.Scalar, participants []abstract.Point, t int) (*RobustDistKeyGenerator, error) {
+	if t < 1 || t > len(participants) {
+		return nil, errorThreshold
+	}
+	pub := suite.Point().Mul(nil, longterm)
+	index := -1
+	for i, P := range participants {
+		if P.Equal(pub) {
+			index = i
+		}
+	}
+	if index < 0 {
+		return nil, errorParticipant
+	}
+	d := &RobustDistKeyGenerator{
+		suite:        suite,
+		longterm:     longterm,
+		index:        index,
+		participants: participants,
+		t:            t,
+		H:            robustBase(suite),
+	}
+	for range participants {
+		d.states = append(d.states, &robustState{responses: make(map[int]bool), justified: make(map[int]bool)})
+	}
+	d.dealer = pedersen.NewDealer(suite, d.H, t, nil, random.Stream)
+	_, commits := d.dealer.Commits().Info()
+	sid, err := d.sessionID(index, commits)
+	if err != nil {
+		return nil, err
+	}
+	d.commitment = &RobustCommitment{Index: index, Commits: commits}
+	if d.commitment.Signature, err = sign.Schnorr(suite, longterm, commitmentMessage(sid)); err != nil {
+		return nil, err
+	}
+	for _, s := range d.dealer.Shares(len(participants)) {
+		deal := &RobustDeal{Index: index, Share: s}
+		msg, err := dealMessage(sid, deal)
+		if err != nil {
+			return nil, err
+		}
+		if deal.Signature, err = sign.Schnorr(suite, longterm, msg); err != nil {
+			return nil, err
+		}
+		d.deals = append(d.deals, deal)
+	}
+	return d, nil
+}
+
+// Index returns the participant's index.
+func (d *RobustDistKeyGenerator) Index() int {
+	return d.index
+}
+
+// sessionID binds the dealer's key, the participants and the dealer's
+// Pedersen commitments.
+func (d *RobustDistKeyGenerator) sessionID(dealer int, commits []abstract.Point) ([]byte, error) {
+	h := d.suite.Hash()
+	h.Write([]byte("dkg-robust-session"))
+	binary.Write(h, binary.BigEndian, uint32(dealer))
+	binary.Write(h, binary.BigEndian, uint32(len(d.participants)))
+	binary.Write(h, binary.BigEndian, uint32(len(commits)))
+	for _, P := range append(append([]abstract.Point{}, d.participants...), commits...) {
+		if _, err := P.MarshalTo(h); err != nil {
+			return nil, err
+		}
+	}
+	return h.Sum(nil), nil
+}
+
+func commitmentMessage(sid []byte) []byte {
+	return append([]byte("dkg-robust-commitment"), sid...)
+}
+
+func dealMessage(sid []byte, deal *RobustDeal) ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteString("dkg-robust-deal")
+	b.Write(sid)
+	binary.Write(&b, binary.BigEndian, uint32(deal.Share.I))
+	for _, s := range []abstract.Scalar{deal.Share.V, deal.Share.R} {
+		if _, err := s.MarshalTo(&b); err != nil {
+			return nil, err
+		}
+	}
+	return b.Bytes(), nil
+}
+
+func responseMessage(sid []byte, r *RobustResponse) []byte {
+	var b bytes.Buffer
+	b.WriteString("dkg-robust-response")
+	b.Write(sid)
+	binary.Write(&b, binary.BigEndian, uint32(r.Verifier))
+	if r.Approved {
+		b.WriteByte(1)
+	} else {
+		b.WriteByte(0)
+	}
+	return b.Bytes()
+}
+
+func commitsMessage(sid []byte, c *RobustCommits) ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteString("dkg-robust-commits")
+	b.Write(sid)
+	_, commits := c.Commits.Info()
+	for _, P := range commits {
+		if _, err := P.MarshalTo(&b); err != nil {
+			return nil, err
+		}
+	}
+	return b.Bytes(), nil
+}
+
+// verifyDeal checks that the deal is signed by its dealer, belongs to the
+// dealer's session and matches the dealer's Pedersen commitments.
+func (d *RobustDistKeyGenerator) verifyDeal(deal *RobustDeal) error {
+	if err := d.authenticateDeal(deal); err != nil {
+		return err
+	}
+	return d.states[deal.Index].commits.Verify(deal.Share)
+}
+
+// authenticateDeal checks that the deal is signed by its dealer and belongs
+// to the dealer's session.
+func (d *RobustDistKeyGenerator) authenticateDeal(deal *RobustDeal) error {
+	if deal == nil || deal.Share == nil || deal.Index < 0 || deal.Index >= len(d.participants) {
+		return errorIndex
+	}
+	if deal.Share.I < 0 || deal.Share.I >= len(d.participants) {
+		return errorIndex
+	}
+	st := d.states[deal.Index]
+	if st.sid == nil {
+		return errorNoCommitment
+	}
+	msg, err := dealMessage(st.sid, deal)
+	if err != nil {
+		return err
+	}
+	if err := sign.VerifySchnorr(d.suite, d.participants[deal.Index], msg, deal.Signature); err != nil {
+		return errorSignature
+	}
+	return nil
+}
+
+// Commitment returns the participant's Pedersen commitment to broadcast to
+// all participants, including itself.
+func (d *RobustDistKeyGenerator) Commitment() *RobustCommitment {
+	return d.commitment
+}
+
+// ProcessCommitment records the Pedersen commitment of a dealer, which must
+// precede the dealer's deal and the responses to it. A second, different
+// commitment disqualifies the dealer.
+func (d *RobustDistKeyGenerator) ProcessCommitment(c *RobustCommitment) error {
+	if c == nil || c.Index < 0 || c.Index >= len(d.participants) {
+		return errorIndex
+	}
+	if len(c.Commits) != d.t {
+		return errorThreshold
+	}
+	sid, err := d.sessionID(c.Index, c.Commits)
+	if err != nil {
+		return err
+	}
+	if err := sign.VerifySchnorr(d.suite, d.participants[c.Index], commitmentMessage(sid), c.Signature); err != nil {
+		return errorSignature
+	}
+	st := d.states[c.Index]
+	if st.sid != nil {
+		if !bytes.Equal(st.sid, sid) {
+			st.bad = true
+		}
+		return errorDuplicate
+	}
+	st.sid = sid
+	st.commits = pedersen.NewCommits(d.suite, d.H, c.Commits)
+	return nil
+}
+
+// Deals returns the participant's deals, in participant order, including the
+// deal to itself, which it processes like any other.
+func (d *RobustDistKeyGenerator) Deals() []*RobustDeal {
+	return d.deals
+}
+
+// ProcessDeal verifies the deal against the dealer's commitment and returns
+// the response to broadcast.
+func (d *RobustDistKeyGenerator) ProcessDeal(deal *RobustDeal) (*RobustResponse, error) {
+	if deal == nil || deal.Index < 0 || deal.Index >= len(d.participants) {
+		return nil, errorIndex
+	}
+	st := d.states[deal.Index]
+	if st.sid == nil {
+		return nil, errorNoCommitment
+	}
+	if _, ok := st.responses[d.index]; ok {
+		return nil, errorDealt
+	}
+	ok := deal.Share != nil && deal.Share.I == d.index && d.verifyDeal(deal) == nil
+	return d.respond(deal.Index, ok, deal)
+}
+
+// Complain returns the complaint to broadcast if the deal of the given dealer
+// did not arrive in time, or nil if the participant has already responded.
+// The dealer answers it by revealing the deal.
+func (d *RobustDistKeyGenerator) Complain(dealer int) (*RobustResponse, error) {
+	if dealer < 0 || dealer >= len(d.participants) {
+		return nil, errorIndex
+	}
+	st := d.states[dealer]
+	if st.sid == nil {
+		return nil, errorNoCommitment
+	}
+	if _, ok := st.responses[d.index]; ok {
+		return nil, nil
+	}
+	return d.respond(dealer, false, nil)
+}
+
+func (d *RobustDistKeyGenerator) respond(dealer int, approved bool, deal *RobustDeal) (*RobustResponse, error) {
+	st := d.states[dealer]
+	r := &RobustResponse{Index: dealer, Verifier: d.index, Approved: approved}
+	var err error
+	if r.Signature, err = sign.Schnorr(d.suite, d.longterm, responseMessage(st.sid, r)); err != nil {
+		return nil, err
+	}
+	st.responses[d.index] = approved
+	if approved {
+		st.deal = deal
+	}
+	return r, nil
+}
+
+// ProcessResponse records the response of another participant. If the
+// response complains about this participant's deal, it returns the
+// justification to broadcast.
+func (d *RobustDistKeyGenerator) ProcessResponse(r *RobustResponse) (*Reveal, error) {
+	if r.Index < 0 || r.Index >= len(d.participants) || r.Verifier < 0 || r.Verifier >= len(d.participants) {
+		return nil, errorIndex
+	}
+	st := d.states[r.Index]
+	if st.sid == nil {
+		return nil, errorNoCommitment
+	}
+	if err := sign.VerifySchnorr(d.suite, d.participants[r.Verifier], responseMessage(st.sid, r), r.Signature); err != nil {
+		return nil, errorSignature
+	}
+	if _, ok := st.responses[r.Verifier]; ok {
+		return nil, errorDuplicate
+	}
+	st.responses[r.Verifier] = r.Approved
+	if r.Index != d.index || r.Approved || st.justified[r.Verifier] {
+		return nil, nil
+	}
+	st.justified[r.Verifier] = true
+	return &Reveal{d.deals[r.Verifier]}, nil
+}
+
+// ProcessJustification checks a dealer's justification of a complaint or of
+// a missing response. A valid justification of this participant's complaint
+// gives it its share. A deal signed by the dealer that does not match its
+// commitments disqualifies the dealer; a deal not signed by the dealer for
+// this session is dropped.
+func (d *RobustDistKeyGenerator) ProcessJustification(j *Reveal) error {
+	deal := j.Deal
+	if deal == nil || deal.Share == nil || deal.Index < 0 || deal.Index >= len(d.participants) ||
+		deal.Share.I < 0 || deal.Share.I >= len(d.participants) {
+		return errorIndex
+	}
+	st := d.states[deal.Index]
+	if st.sid == nil {
+		return errorNoCommitment
+	}
+	if approved := st.responses[deal.Share.I]; approved {
+		return errorNoComplaint
+	}
+	if st.justified[deal.Share.I] {
+		return errorDuplicate
+	}
+	if err := d.authenticateDeal(deal); err != nil {
+		return err
+	}
+	if err := st.commits.Verify(deal.Share); err != nil {
+		st.bad = true
+		return errorJustification
+	}
+	st.justified[deal.Share.I] = true
+	if deal.Share.I == d.index {
+		st.deal = deal
+	}
+	return nil
+}
+
+// SetTimeout ends the response round of the sharing phase. It returns the
+// justifications of the participants that did not respond to this
+// participant's deal, which it must broadcast; once they are processed, QUAL
+// is fixed.
+func (d *RobustDistKeyGenerator) SetTimeout() []*Reveal {
+	d.timeout = true
+	st := d.states[d.index]
+	var js []*Reveal
+	for v, deal := range d.deals {
+		if _, ok := st.responses[v]; !ok && !st.justified[v] {
+			st.justified[v] = true
+			js = append(js, &Reveal{deal})
+		}
+	}
+	return js
+}
+
+// qualified reports whether every participant approved its deal from the
+// dealer or the dealer revealed a valid deal for it. A missing response
+// counts as a complaint.
+func (d *RobustDistKeyGenerator) qualified(st *robustState) bool {
+	if st.bad || st.sid == nil {
+		return false
+	}
+	if !d.timeout && len(st.responses) < len(d.participants) {
+		return false
+	}
+	for v := range d.participants {
+		if !st.responses[v] && !st.justified[v] {
+			return false
+		}
+	}
+	return true
+}
+
+// QUAL returns the indices of the qualified dealers.
+func (d *RobustDistKeyGenerator) QUAL() []int {
+	var qual []int
+	for i, st := range d.states {
+		if d.qualified(st) {
+			qual = append(qual, i)
+		}
+	}
+	return qual
+}
+
+// Certified reports whether at least a threshold of dealers is qualified.
+func (d *RobustDistKeyGenerator) Certified() bool {
+	return len(d.QUAL()) >= d.t
+}
+
+// Commits returns the participant's Feldman commitments to broadcast in the
+// extraction phase.
+func (d *RobustDistKeyGenerator) Commits() (*RobustCommits, error) {
+	c := &RobustCommits{Index: d.index, Commits: d.dealer.FeldmanCommits()}
+	msg, err := commitsMessage(d.states[d.index].sid, c)
+	if err != nil {
+		return nil, err
+	}
+	if c.Signature, err = sign.Schnorr(d.suite, d.longterm, msg); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// ProcessCommits records the Feldman commitments of a qualified dealer. If
+// the participant's share does not match them, it returns the complaint to
+// broadcast.
+func (d *RobustDistKeyGenerator) ProcessCommits(c *RobustCommits) (*Reveal, error) {
+	if c.Index < 0 || c.Index >= len(d.participants) || c.Commits == nil {
+		return nil, errorIndex
+	}
+	st := d.states[c.Index]
+	if !d.qualified(st) {
+		return nil, errorNoDeal
+	}
+	if st.feldman != nil {
+		return nil, errorDuplicate
+	}
+	if c.Commits.Threshold() != d.t {
+		return nil, errorThreshold
+	}
+	msg, err := commitsMessage(st.sid, c)
+	if err != nil {
+		return nil, err
+	}
+	if err := sign.VerifySchnorr(d.suite, d.participants[c.Index], msg, c.Signature); err != nil {
+		return nil, errorSignature
+	}
+	st.feldman = c.Commits
+	if st.deal == nil || c.Commits.Check(&share.PriShare{I: d.index, V: st.deal.Share.V}) {
+		return nil, nil
+	}
+	st.accused = true
+	return &Reveal{st.deal}, nil
+}
+
+// ProcessComplaint checks a complaint against the Feldman commitments of a
+// dealer. A valid complaint reveals a share that matches the dealer's
+// Pedersen commitments but not its Feldman commitments; the dealer's secret
+// is then reconstructed.
+func (d *RobustDistKeyGenerator) ProcessComplaint(c *Reveal) error {
+	if err := d.verifyDeal(c.Deal); err != nil {
+		return err
+	}
+	st := d.states[c.Deal.Index]
+	if st.feldman == nil {
+		return errorNoCommits
+	}
+	if st.feldman.Check(&share.PriShare{I: c.Deal.Share.I, V: c.Deal.Share.V}) {
+		return errorComplaint
+	}
+	st.accused = true
+	return nil
+}
+
+// needsReconstruction reports whether the qualified dealer's secret must be
+// reconstructed.
+func (d *RobustDistKeyGenerator) needsReconstruction(st *robustState) bool {
+	return d.qualified(st) && (st.accused || st.feldman == nil)
+}
+
+// Reconstructions returns the participant's shares of the qualified dealers
+// that were accused or did not publish their Feldman commitments, to be
+// broadcast at the end of the extraction phase.
+func (d *RobustDistKeyGenerator) Reconstructions() []*Reveal {
+	var reveals []*Reveal
+	for _, st := range d.states {
+		if d.needsReconstruction(st) && st.deal != nil {
+			reveals = append(reveals, &Reveal{st.deal})
+		}
+	}
+	return reveals
+}
+
+// ProcessReconstruct records a share of a dealer whose secret is
+// reconstructed.
+func (d *RobustDistKeyGenerator) ProcessReconstruct(r *Reveal) error {
+	if err := d.verifyDeal(r.Deal); err != nil {
+		return err
+	}
+	st := d.states[r.Deal.Index]
+	if !d.needsReconstruction(st) {
+		return errorComplaint
+	}
+	for _, s := range st.revealed {
+		if s.I == r.Deal.Share.I {
+			return errorDuplicate
+		}
+	}
+	st.revealed = append(st.revealed, &share.PriShare{I: r.Deal.Share.I, V: r.Deal.Share.V})
+	return nil
+}
+
+// DistKeyShare returns the participant's share of the collective key.
+func (d *RobustDistKeyGenerator) DistKeyShare() (*DistKeyShare, error) {
+	qual := d.QUAL()
+	if len(qual) < d.t {
+		return nil, errorNotCertified
+	}
+	var commits *share.PubPoly
+	v := d.suite.Scalar().Zero()
+	for _, i := range qual {
+		st := d.states[i]
+		if st.deal == nil {
+			return nil, errorMissingDeal
+		}
+		v.Add(v, st.deal.Share.V)
+		pub := st.feldman
+		if d.needsReconstruction(st) {
+			if len(st.revealed) < d.t {
+				return nil, errorReconstruct
+			}
+			coeffs, err := interpolate(d.suite, st.revealed[:d.t], len(d.participants))
+			if err != nil {
+				return nil, err
+			}
+			points := make([]abstract.Point, len(coeffs))
+			for k, a := range coeffs {
+				points[k] = d.suite.Point().Mul(nil, a)
+			}
+			pub = share.NewPubPoly(d.suite, nil, points)
+		}
+		if commits == nil {
+			commits = pub
+			continue
+		}
+		var err error
+		if commits, err = commits.Add(pub); err != nil {
+			return nil, err
+		}
+	}
+	return &DistKeyShare{commits, &share.PriShare{I: d.index, V: v}}, nil
+}
+
+// interpolate returns the coefficients of the polynomial through the shares
+// among n participants.
+func interpolate(g abstract.Group, shares []*share.PriShare, n int) ([]abstract.Scalar, error) {
+	indices := make([]int, len(shares))
+	for j, s := range shares {
+		indices[j] = s.I
+	}
+	ip, err := share.NewInterpolator(g, indices, n)
+	if err != nil {
+		return nil, err
+	}
+	coeffs := make([]abstract.Scalar, len(shares))
+	for k := range coeffs {
+		coeffs[k] = g.Scalar().Zero()
+	}
+	for _, s := range shares {
+		for k, c := range ip.Basis(s.I) {
+			coeffs[k].Add(coeffs[k], g.Scalar().Mul(c, s.V))
+		}
+	}
+	return coeffs, nil
+}
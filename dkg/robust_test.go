@@ -0,0 +1,328 @@
+package dkg
+
+import (
+	"testing"
+
+	"github.com/dedis/crypto/abstract"
+	"github.com/dedis/crypto/random"
+	"github.com/dedis/crypto/share"
+	"github.com/dedis/crypto/sign"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func robustSetup(t *testing.T, n, th int) []*RobustDistKeyGenerator {
+	keys := make([]abstract.Scalar, n)
+	pubs := make([]abstract.Point, n)
+	for i := range keys {
+		keys[i] = suite.Scalar().Pick(random.Stream)
+		pubs[i] = suite.Point().Mul(nil, keys[i])
+	}
+	dkgs := make([]*RobustDistKeyGenerator, n)
+	for i := range dkgs {
+		var err error
+		dkgs[i], err = NewRobustDistKeyGenerator(suite, keys[i], pubs, th)
+		require.Nil(t, err)
+		assert.Equal(t, i, dkgs[i].Index())
+	}
+	return dkgs
+}
+
+// robustCommitments broadcasts the Pedersen commitments of all dealers.
+func robustCommitments(t *testing.T, dkgs []*RobustDistKeyGenerator) {
+	for _, d := range dkgs {
+		for _, e := range dkgs {
+			require.Nil(t, e.ProcessCommitment(d.Commitment()))
+		}
+	}
+}
+
+// robustRun executes the robust DKG. In the sharing phase, tamper may replace
+// the deal of a dealer to a participant, or drop it if it returns nil, in
+// which case the participant complains. In the extraction phase, commits may
+// replace the Feldman commitments of a dealer, or drop them if it returns nil.
+func robustRun(t *testing.T, dkgs []*RobustDistKeyGenerator, tamper func(dealer, to int, d *RobustDeal) *RobustDeal, commits func(c *RobustCommits) *RobustCommits) {
+	robustCommitments(t, dkgs)
+	var responses []*RobustResponse
+	for i, d := range dkgs {
+		for j, deal := range d.Deals() {
+			if tamper != nil {
+				deal = tamper(i, j, deal)
+			}
+			var r *RobustResponse
+			var err error
+			if deal != nil {
+				r, err = dkgs[j].ProcessDeal(deal)
+			} else {
+				r, err = dkgs[j].Complain(i)
+			}
+			require.Nil(t, err)
+			responses = append(responses, r)
+		}
+	}
+	var justs []*Reveal
+	for _, r := range responses {
+		for _, d := range dkgs {
+			if d.Index() == r.Verifier {
+				continue
+			}
+			j, err := d.ProcessResponse(r)
+			require.Nil(t, err)
+			if j != nil {
+				justs = append(justs, j)
+			}
+		}
+	}
+	for _, d := range dkgs {
+		justs = append(justs, d.SetTimeout()...)
+	}
+	for _, j := range justs {
+		for _, d := range dkgs {
+			if d.Index() != j.Deal.Index {
+				require.Nil(t, d.ProcessJustification(j))
+			}
+		}
+	}
+	for _, d := range dkgs {
+		require.True(t, d.Certified())
+	}
+
+	var complaints []*Reveal
+	for _, d := range dkgs {
+		c, err := d.Commits()
+		require.Nil(t, err)
+		if commits != nil {
+			if c = commits(c); c == nil {
+				continue
+			}
+		}
+		for _, e := range dkgs {
+			complaint, err := e.ProcessCommits(c)
+			require.Nil(t, err)
+			if complaint != nil {
+				complaints = append(complaints, complaint)
+			}
+		}
+	}
+	for _, c := range complaints {
+		for _, d := range dkgs {
+			require.Nil(t, d.ProcessComplaint(c))
+		}
+	}
+	for _, d := range dkgs {
+		for _, r := range d.Reconstructions() {
+			for _, e := range dkgs {
+				require.Nil(t, e.ProcessReconstruct(r))
+			}
+		}
+	}
+}
+
+func robustCheck(t *testing.T, dkgs []*RobustDistKeyGenerator, th, n int) {
+	var shares []*share.PriShare
+	var public abstract.Point
+	for _, d := range dkgs {
+		dks, err := d.DistKeyShare()
+		require.Nil(t, err)
+		require.True(t, dks.Commits.Check(dks.Share))
+		if public == nil {
+			public = dks.Public()
+		}
+		assert.True(t, public.Equal(dks.Public()))
+		shares = append(shares, dks.Share)
+	}
+	secret, err := share.RecoverSecret(suite, shares, th, n)
+	require.Nil(t, err)
+	assert.True(t, public.Equal(suite.Point().Mul(nil, secret)))
+}
+
+func TestRobustDKG(t *testing.T) {
+	n, th := 5, 3
+	dkgs := robustSetup(t, n, th)
+	robustRun(t, dkgs, nil, nil)
+	for _, d := range dkgs {
+		assert.Equal(t, []int{0, 1, 2, 3, 4}, d.QUAL())
+		assert.Nil(t, d.Reconstructions())
+	}
+	robustCheck(t, dkgs, th, n)
+}
+
+func TestRobustDKGComplaint(t *testing.T) {
+	n, th := 5, 3
+	dkgs := robustSetup(t, n, th)
+	robustRun(t, dkgs, func(dealer, to int, d *RobustDeal) *RobustDeal {
+		if dealer != 1 || to != 3 {
+			return d
+		}
+		bad := *d
+		s := *d.Share
+		s.V = suite.Scalar().One()
+		bad.Share = &s
+		return &bad
+	}, nil)
+	for _, d := range dkgs {
+		assert.Equal(t, []int{0, 1, 2, 3, 4}, d.QUAL())
+	}
+	robustCheck(t, dkgs, th, n)
+}
+
+func TestRobustDKGMissingDeal(t *testing.T) {
+	n, th := 5, 3
+	dkgs := robustSetup(t, n, th)
+	// Dealer 0 withholds its deal to participant 3, which complains; the
+	// dealer reveals the deal and stays qualified for everybody
+	robustRun(t, dkgs, func(dealer, to int, d *RobustDeal) *RobustDeal {
+		if dealer == 0 && to == 3 {
+			return nil
+		}
+		return d
+	}, nil)
+	for _, d := range dkgs {
+		assert.Equal(t, []int{0, 1, 2, 3, 4}, d.QUAL())
+	}
+	robustCheck(t, dkgs, th, n)
+}
+
+func TestRobustDKGMissingResponse(t *testing.T) {
+	n, th := 5, 3
+	dkgs := robustSetup(t, n, th)
+	robustCommitments(t, dkgs)
+	// Participant 3 never responds to dealer 0; the dealer justifies the
+	// missing response at its timeout
+	var responses []*RobustResponse
+	for _, d := range dkgs {
+		for j, deal := range d.Deals() {
+			r, err := dkgs[j].ProcessDeal(deal)
+			require.Nil(t, err)
+			if d.Index() != 0 || j != 3 {
+				responses = append(responses, r)
+			}
+		}
+	}
+	for _, r := range responses {
+		for _, d := range dkgs {
+			if d.Index() != r.Verifier {
+				j, err := d.ProcessResponse(r)
+				require.Nil(t, err)
+				require.Nil(t, j)
+			}
+		}
+	}
+	for _, d := range dkgs {
+		if d.Index() != 3 {
+			assert.Equal(t, []int{1, 2, 3, 4}, d.QUAL())
+		}
+	}
+	var justs []*Reveal
+	for _, d := range dkgs {
+		justs = append(justs, d.SetTimeout()...)
+	}
+	require.Len(t, justs, 1)
+	for _, d := range dkgs[1:] {
+		if d.Index() == 3 {
+			assert.Equal(t, errorNoComplaint, d.ProcessJustification(justs[0]))
+			continue
+		}
+		require.Nil(t, d.ProcessJustification(justs[0]))
+	}
+	for _, d := range dkgs {
+		assert.Equal(t, []int{0, 1, 2, 3, 4}, d.QUAL())
+	}
+}
+
+func TestRobustDKGEquivocation(t *testing.T) {
+	n, th := 5, 3
+	dkgs := robustSetup(t, n, th)
+	robustCommitments(t, dkgs)
+	// A second, different commitment of dealer 2 disqualifies it
+	_, commits := share.NewPriPoly(suite, th, nil, random.Stream).Commit(dkgs[2].H).Info()
+	bad := &RobustCommitment{Index: 2, Commits: commits}
+	sid, err := dkgs[2].sessionID(2, commits)
+	require.Nil(t, err)
+	bad.Signature, err = sign.Schnorr(suite, dkgs[2].longterm, commitmentMessage(sid))
+	require.Nil(t, err)
+	for _, d := range dkgs {
+		assert.Equal(t, errorDuplicate, d.ProcessCommitment(bad))
+		assert.Equal(t, errorDuplicate, d.ProcessCommitment(dkgs[2].Commitment()))
+		assert.True(t, d.states[2].bad)
+	}
+}
+
+func TestRobustDKGForgedJustification(t *testing.T) {
+	n, th := 5, 3
+	dkgs := robustSetup(t, n, th)
+	robustCommitments(t, dkgs)
+	// Participant 3 complains about dealer 1's deal
+	var responses []*RobustResponse
+	for _, d := range dkgs {
+		for j, deal := range d.Deals() {
+			if d.Index() == 1 && j == 3 {
+				bad := *deal
+				s := *deal.Share
+				s.V = suite.Scalar().One()
+				bad.Share = &s
+				deal = &bad
+			}
+			r, err := dkgs[j].ProcessDeal(deal)
+			require.Nil(t, err)
+			responses = append(responses, r)
+		}
+	}
+	var just *Reveal
+	for _, r := range responses {
+		for _, d := range dkgs {
+			if d.Index() == r.Verifier {
+				continue
+			}
+			j, err := d.ProcessResponse(r)
+			require.Nil(t, err)
+			if j != nil {
+				just = j
+			}
+		}
+	}
+	require.NotNil(t, just)
+
+	// A justification not signed by the dealer is dropped and the dealer's
+	// genuine one still qualifies it
+	forged := *just.Deal
+	s := *just.Deal.Share
+	s.V = suite.Scalar().One()
+	forged.Share = &s
+	for _, d := range dkgs {
+		if d.Index() == 1 {
+			continue
+		}
+		assert.Equal(t, errorSignature, d.ProcessJustification(&Reveal{&forged}))
+		require.Nil(t, d.ProcessJustification(just))
+		assert.Empty(t, d.SetTimeout())
+		assert.Equal(t, []int{0, 1, 2, 3, 4}, d.QUAL())
+	}
+}
+
+func TestRobustDKGReconstruct(t *testing.T) {
+	n, th := 5, 3
+	dkgs := robustSetup(t, n, th)
+	// Dealer 2 publishes Feldman commitments of another polynomial and
+	// dealer 4 publishes none; both secrets are reconstructed.
+	robustRun(t, dkgs, nil, func(c *RobustCommits) *RobustCommits {
+		switch c.Index {
+		case 2:
+			other := share.NewPriPoly(suite, th, nil, random.Stream).Commit(nil)
+			bad := &RobustCommits{Index: 2, Commits: other}
+			msg, err := commitsMessage(dkgs[2].states[2].sid, bad)
+			require.Nil(t, err)
+			bad.Signature, err = sign.Schnorr(suite, dkgs[2].longterm, msg)
+			require.Nil(t, err)
+			return bad
+		case 4:
+			return nil
+		}
+		return c
+	})
+	for _, d := range dkgs {
+		assert.Equal(t, []int{0, 1, 2, 3, 4}, d.QUAL())
+		assert.Equal(t, 2, len(d.Reconstructions()))
+	}
+	robustCheck(t, dkgs, th, n)
+}
@@ -18,7 +18,7 @@ import (
 // BE AWARE : if you use edwards suite, do NOT use the extended full group
 // version as it breaks the computation when verifying a signture.
 // Always set to false.
-//var suite = edwards.NewAES128SHA256Ed25519(false)
+// var suite = edwards.NewAES128SHA256Ed25519(false)
 var suite = nist.NewAES128SHA256P256()
 
 // how much peers
@@ -94,7 +94,7 @@ func generateDealerReceiver(info poly.Threshold, ndeals, nreceivers int) ([]*pol
 	return dealers, receivers
 }
 
-func ExampleJoint() {
+func Example_joint() {
 	deals, receivers := generateDealerReceiver(threshold, threshold.T, threshold.N)
 	// make the exchange of shares by giving each deals to each receivers
 	// for each receivers
@@ -162,8 +162,8 @@ func generateSharedSecrets(info poly.Threshold) []*poly.SharedSecret {
 	return secrets
 }
 
-// ExampleDistributedSchnorr shows a simple example of how to distributively sign something
-func ExampleDistributedSchnorr() {
+// Example_distributedSchnorr shows a simple example of how to distributively sign something
+func Example_distributedSchnorr() {
 	var msg = suite.Hash()
 	msg.Write([]byte("Hello Distributed World\n"))
 	// This will be the longterm distributed key used during the schnorr
@@ -0,0 +1,117 @@
+// Package ecies implements the elliptic curve integrated encryption scheme
+// for one or many recipients. A message for n recipients is encrypted only
+// once under a fresh payload key, which is wrapped for every recipient with
+// a key derived from a Diffie-Hellman exchange between a single ephemeral key
+// and the recipient's public key. A ciphertext for n recipients therefore
+// carries one ephemeral point, n wrapped keys and a single copy of the
+// encrypted message:
+//
+//	R | n (uint32) | wrapped key_1 | ... | wrapped key_n | sealed message
+//
+// The header consisting of R and the wrapped keys is authenticated together
+// with the message under the payload key, so an outsider cannot add, remove
+// or swap recipients. Every recipient learns the payload key, though, and can
+// thus produce a ciphertext with the same message and another set of
+// recipients, or with another message, that the remaining recipients accept.
+// The scheme does not authenticate the sender; sign the ciphertext where
+// this matters.
+package ecies
+
+import (
+	"bytes"
+	"crypto/cipher"
+	"encoding/binary"
+	"errors"
+
+	"github.com/dedis/crypto/abstract"
+	"github.com/dedis/crypto/random"
+)
+
+// Some error definitions
+var errorCiphertext = errors.New("malformed ciphertext")
+var errorNotRecipient = errors.New("not a recipient of the ciphertext")
+var errorRecipients = errors.New("no recipients")
+
+// Encrypt encrypts msg for the holders of the private keys of the given
+// public keys.
+func Encrypt(suite abstract.Suite, recipients []abstract.Point, msg []byte, rand cipher.Stream) ([]byte, error) {
+	if len(recipients) == 0 {
+		return nil, errorRecipients
+	}
+	r := suite.Scalar().Pick(rand)
+	R := suite.Point().Mul(nil, r)
+	key := random.Bytes(suite.Cipher(abstract.NoKey).KeySize(), rand)
+
+	var hdr bytes.Buffer
+	if _, err := R.MarshalTo(&hdr); err != nil {
+		return nil, err
+	}
+	binary.Write(&hdr, binary.BigEndian, uint32(len(recipients)))
+	for _, X := range recipients {
+		kek, err := wrapKey(suite, R, X, suite.Point().Mul(X, r))
+		if err != nil {
+			return nil, err
+		}
+		hdr.Write(suite.Cipher(kek).Seal(nil, key))
+	}
+	c := suite.Cipher(key)
+	c.Message(nil, nil, hdr.Bytes())
+	return c.Seal(hdr.Bytes(), msg), nil
+}
+
+// Decrypt decrypts a ciphertext with the private key of one of its
+// recipients.
+func Decrypt(suite abstract.Suite, private abstract.Scalar, ctx []byte) ([]byte, error) {
+	R := suite.Point()
+	r := bytes.NewReader(ctx)
+	if _, err := R.UnmarshalFrom(r); err != nil {
+		return nil, errorCiphertext
+	}
+	var n uint32
+	if err := binary.Read(r, binary.BigEndian, &n); err != nil {
+		return nil, errorCiphertext
+	}
+	keyLen := suite.Cipher(abstract.NoKey).KeySize()
+	wrapLen := 2 * keyLen // wrapped key and its authenticator
+	start := len(ctx) - r.Len()
+	if uint64(n)*uint64(wrapLen) > uint64(r.Len()) {
+		return nil, errorCiphertext
+	}
+	end := start + int(n)*wrapLen
+
+	X := suite.Point().Mul(nil, private)
+	kek, err := wrapKey(suite, R, X, suite.Point().Mul(R, private))
+	if err != nil {
+		return nil, err
+	}
+	for i := start; i < end; i += wrapLen {
+		// Open checks the authenticator in place, so work on a copy
+		wrapped := append([]byte{}, ctx[i:i+wrapLen]...)
+		key, err := suite.Cipher(kek).Open(nil, wrapped)
+		if err != nil {
+			continue
+		}
+		c := suite.Cipher(key)
+		c.Message(nil, nil, ctx[:end])
+		sealed := append([]byte{}, ctx[end:]...)
+		msg, err := c.Open(nil, sealed)
+		if err != nil {
+			return nil, errorCiphertext
+		}
+		return msg, nil
+	}
+	return nil, errorNotRecipient
+}
+
+// wrapKey derives the key wrapping the payload key for the recipient with
+// public key X from the ephemeral key R and the shared secret S.
+func wrapKey(suite abstract.Suite, R, X, S abstract.Point) ([]byte, error) {
+	h := suite.Hash()
+	h.Write([]byte("ecies-wrap"))
+	for _, P := range []abstract.Point{R, X, S} {
+		if _, err := P.MarshalTo(h); err != nil {
+			return nil, err
+		}
+	}
+	return h.Sum(nil), nil
+}
@@ -0,0 +1,53 @@
+package ecies
+
+import (
+	"testing"
+
+	"github.com/dedis/crypto/abstract"
+	"github.com/dedis/crypto/edwards"
+	"github.com/dedis/crypto/random"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var suite = edwards.NewAES128SHA256Ed25519(false)
+
+func TestECIES(t *testing.T) {
+	n := 5
+	x := make([]abstract.Scalar, n)
+	X := make([]abstract.Point, n)
+	for i := range x {
+		x[i] = suite.Scalar().Pick(random.Stream)
+		X[i] = suite.Point().Mul(nil, x[i])
+	}
+	msg := []byte("committee broadcast")
+
+	ctx, err := Encrypt(suite, X, msg, random.Stream)
+	require.Nil(t, err)
+	for i := range x {
+		dec, err := Decrypt(suite, x[i], ctx)
+		require.Nil(t, err)
+		assert.Equal(t, msg, dec)
+	}
+
+	// The message is encrypted only once
+	single, err := Encrypt(suite, X[:1], msg, random.Stream)
+	require.Nil(t, err)
+	assert.True(t, len(ctx) < n*len(single))
+
+	_, err = Decrypt(suite, suite.Scalar().Pick(random.Stream), ctx)
+	assert.Equal(t, errorNotRecipient, err)
+
+	// Tampering with the header or the payload is detected
+	for _, i := range []int{0, len(ctx) - len(msg) - 1, len(ctx) - 1} {
+		bad := append([]byte{}, ctx...)
+		bad[i] ^= 1
+		_, err := Decrypt(suite, x[2], bad)
+		assert.Error(t, err)
+	}
+	_, err = Decrypt(suite, x[0], ctx[:40])
+	assert.Equal(t, errorCiphertext, err)
+
+	_, err = Encrypt(suite, nil, msg, random.Stream)
+	assert.Equal(t, errorRecipients, err)
+}
@@ -247,6 +247,14 @@ func (r *completedGroupElement) MixedSub(p *extendedGroupElement, q *preComputed
 	feAdd(&r.T, &t0, &r.T)
 }
 
+// Set to u conditionally based on b
+func (t *extendedGroupElement) CMove(u *extendedGroupElement, b int32) {
+	feCMove(&t.X, &u.X, b)
+	feCMove(&t.Y, &u.Y, b)
+	feCMove(&t.Z, &u.Z, b)
+	feCMove(&t.T, &u.T, b)
+}
+
 // preComputedGroupElement methods
 
 // Set to u conditionally based on b
@@ -418,11 +426,13 @@ func selectPreComputed(t *preComputedGroupElement, pos int32, b int32) {
 }
 
 // geScalarMultBase computes h = a*B, where
-//   a = a[0]+256*a[1]+...+256^31 a[31]
-//   B is the Ed25519 base point (x,4/5) with x positive.
+//
+//	a = a[0]+256*a[1]+...+256^31 a[31]
+//	B is the Ed25519 base point (x,4/5) with x positive.
 //
 // Preconditions:
-//   a[31] <= 127
+//
+//	a[31] <= 127
 func geScalarMultBase(h *extendedGroupElement, a *[32]byte) {
 	var e [64]int8
 
@@ -486,11 +496,13 @@ func selectCached(c *cachedGroupElement, Ai *[8]cachedGroupElement, b int32) {
 }
 
 // geScalarMult computes h = a*B, where
-//   a = a[0]+256*a[1]+...+256^31 a[31]
-//   B is the Ed25519 base point (x,4/5) with x positive.
+//
+//	a = a[0]+256*a[1]+...+256^31 a[31]
+//	B is the Ed25519 base point (x,4/5) with x positive.
 //
 // Preconditions:
-//   a[31] <= 127
+//
+//	a[31] <= 127
 func geScalarMult(h *extendedGroupElement, a *[32]byte,
 	A *extendedGroupElement) {
 
@@ -553,11 +565,13 @@ func geScalarMult(h *extendedGroupElement, a *[32]byte,
 }
 
 // geScalarMultVartime computes h = a*B, where
-//   a = a[0]+256*a[1]+...+256^31 a[31]
-//   B is the Ed25519 base point (x,4/5) with x positive.
+//
+//	a = a[0]+256*a[1]+...+256^31 a[31]
+//	B is the Ed25519 base point (x,4/5) with x positive.
 //
 // Preconditions:
-//   a[31] <= 127
+//
+//	a[31] <= 127
 func geScalarMultVartime(h *extendedGroupElement, a *[32]byte,
 	A *extendedGroupElement) {
 
@@ -11,7 +11,6 @@
 // described in the Ed25519 paper, this implementation generally performs
 // extremely well, typically comparable to native C implementations.
 // The tradeoff is that this code is completely specialized to a single curve.
-//
 package ed25519
 
 import (
@@ -82,6 +81,12 @@ func (P *point) Set(P2 abstract.Point) abstract.Point {
 	return P
 }
 
+// CMov sets P to P2 if cond == 1 and leaves P unchanged if cond == 0, in
+// constant time.
+func (P *point) CMov(P2 abstract.Point, cond int) {
+	P.ge.CMove(&P2.(*point).ge, int32(cond))
+}
+
 // Set point to be equal to P2.
 func (P *point) Clone() abstract.Point {
 	return &point{ge: P.ge}
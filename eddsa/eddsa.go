@@ -8,6 +8,7 @@ import (
 
 	"github.com/dedis/crypto/abstract"
 	"github.com/dedis/crypto/ed25519"
+	"github.com/dedis/crypto/group"
 	"github.com/dedis/crypto/random"
 )
 
@@ -135,21 +136,36 @@ func (e *EdDSA) Sign(msg []byte) ([]byte, error) {
 // Verify takes a signature issued by EdDSA.Sign and
 // return nil if it is a valid signature, or an error otherwise
 // Takes:
-//  - public key used in signing
-//  - msg is the message to sign
-//  - sig is the signature return by EdDSA.Sign
+//   - public key used in signing
+//   - msg is the message to sign
+//   - sig is the signature return by EdDSA.Sign
 func Verify(public abstract.Point, msg, sig []byte) error {
+	return VerifyMode(public, msg, sig, group.Lenient)
+}
+
+// VerifyMode is like Verify but decodes the signature according to mode. In
+// both modes a scalar s that is not reduced modulo the group order is
+// rejected. In Strict mode VerifyMode also rejects non-canonical encodings of
+// R as well as a small-order R or public key, so that every valid signature has
+// exactly one accepted encoding.
+func VerifyMode(public abstract.Point, msg, sig []byte, mode group.Strictness) error {
 	if len(sig) != 64 {
 		return errors.New("signature length invalid")
 	}
 
 	R := suite.Point()
-	if err := R.UnmarshalBinary(sig[:32]); err != nil {
+	if err := group.UnmarshalPoint(R, sig[:32], mode); err != nil {
 		return fmt.Errorf("got R invalid point: %s", err)
 	}
 
 	s := suite.Scalar()
-	s.UnmarshalBinary(sig[32:])
+	if err := s.UnmarshalBinary(sig[32:]); err != nil {
+		return fmt.Errorf("got s invalid scalar: %s", err)
+	}
+
+	if mode == group.Strict && group.DefaultPolicy(suite).Check(suite, R, public) != nil {
+		return errors.New("small order point in signature or public key")
+	}
 
 	// reconstruct h = H(R || Public || Msg)
 	Pbuff, err := public.MarshalBinary()
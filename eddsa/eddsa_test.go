@@ -5,6 +5,7 @@ import (
 	"encoding/hex"
 	"testing"
 
+	"github.com/dedis/crypto/group"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -76,3 +77,42 @@ func ConstantStream(buff []byte) cipher.Stream {
 func (cs *constantStream) XORKeyStream(dst, src []byte) {
 	copy(dst, cs.seed)
 }
+
+func TestEdDSAMalleability(t *testing.T) {
+	ed := NewEdDSA(nil)
+	msg := []byte("malleable")
+	sig, err := ed.Sign(msg)
+	assert.Nil(t, err)
+	assert.Nil(t, VerifyMode(ed.Public, msg, sig, group.Strict))
+
+	// s + L is rejected in both modes
+	L := []byte{0xed, 0xd3, 0xf5, 0x5c, 0x1a, 0x63, 0x12, 0x58, 0xd6, 0x9c, 0xf7, 0xa2, 0xde, 0xf9, 0xde, 0x14,
+		0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0x10}
+	mal := append([]byte{}, sig...)
+	var carry uint16
+	for i := range L {
+		carry += uint16(mal[32+i]) + uint16(L[i])
+		mal[32+i] = byte(carry)
+		carry >>= 8
+	}
+	assert.NotNil(t, Verify(ed.Public, msg, mal))
+
+	// The identity as public key and R with s = 0 verifies for any message
+	// unless small-order points are rejected.
+	id := suite.Point().Null()
+	forged := make([]byte, 64)
+	forged[0] = 1
+	assert.Nil(t, Verify(id, msg, forged))
+	assert.NotNil(t, VerifyMode(id, msg, forged, group.Strict))
+
+	// y = p + 1 is a non-canonical encoding of the identity
+	nc := make([]byte, 32)
+	for i := range nc {
+		nc[i] = 0xff
+	}
+	nc[0], nc[31] = 0xee, 0x7f
+	P := suite.Point()
+	if P.UnmarshalBinary(nc) == nil {
+		assert.Equal(t, group.ErrNonCanonical, group.UnmarshalPoint(P, nc, group.Strict))
+	}
+}
@@ -1,3 +1,4 @@
+//go:build experimental
 // +build experimental
 
 package edwards
@@ -86,6 +87,14 @@ func (P *basicPoint) Set(P2 abstract.Point) abstract.Point {
 	return P
 }
 
+// CMov sets P to P2 if cond == 1 and leaves P unchanged if cond == 0,
+// without branching on cond.
+func (P *basicPoint) CMov(P2 abstract.Point, cond int) {
+	E2 := P2.(*basicPoint)
+	P.x.CMov(&E2.x, cond)
+	P.y.CMov(&E2.y, cond)
+}
+
 // Set to the neutral element, which is (0,1) for twisted Edwards curves.
 func (P *basicPoint) Null() abstract.Point {
 	P.Set(&P.c.null)
@@ -115,7 +124,6 @@ func (P *basicPoint) Data() ([]byte, error) {
 //
 //	x' = ((x1*y2 + x2*y1) / (1 + d*x1*x2*y1*y2))
 //	y' = ((y1*y2 - a*x1*x2) / (1 - d*x1*x2*y1*y2))
-//
 func (P *basicPoint) Add(P1, P2 abstract.Point) abstract.Point {
 	E1 := P1.(*basicPoint)
 	E2 := P2.(*basicPoint)
@@ -191,7 +199,6 @@ func (P *basicPoint) Mul(G abstract.Point, s abstract.Scalar) abstract.Point {
 // and instructional uses, and not for production use.
 // The projective coordinates implementation (ProjectiveCurve)
 // is just as general and much faster.
-//
 type BasicCurve struct {
 	curve            // generic Edwards curve functionality
 	null  basicPoint // Neutral/identity point (0,1)
@@ -242,7 +242,6 @@ func (c *curve) decodePoint(bb []byte, x, y *nist.Int) error {
 //
 // Returns true on success,
 // false if there is no x-coordinate corresponding to the chosen y-coordinate.
-//
 func (c *curve) solveForX(x, y *nist.Int) bool {
 	var yy, t1, t2 nist.Int
 
@@ -257,7 +256,6 @@ func (c *curve) solveForX(x, y *nist.Int) bool {
 // by checking the characteristic equation for Edwards curves:
 //
 //	a*x^2 + y^2 = 1 + d*x^2*y^2
-//
 func (c *curve) onCurve(x, y *nist.Int) bool {
 	var xx, yy, l, r nist.Int
 
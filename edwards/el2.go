@@ -70,7 +70,6 @@ func (el *el2param) padmask() byte {
 //
 // Beware: the Twisted Edwards Curves paper uses B as a factor for v^2,
 // whereas the Elligator 2 paper uses B as a factor for the last u term.
-//
 func (el *el2param) ed2mont(u, v, x, y *nist.Int) {
 	ec := el.ec
 	var t1, t2 nist.Int
@@ -82,7 +81,6 @@ func (el *el2param) ed2mont(u, v, x, y *nist.Int) {
 //
 //	x = sqrt(B)u/v
 //	y = (u-1)/(u+1)
-//
 func (el *el2param) mont2ed(x, y, u, v *nist.Int) {
 	ec := el.ec
 	var t1, t2 nist.Int
@@ -80,7 +80,6 @@ func (P *extPoint) HideDecode(rep []byte) {
 //	(X1/Z1,Y1/Z1) == (X2/Z2,Y2/Z2)
 //		iff
 //	(X1*Z2,Y1*Z2) == (X2*Z1,Y2*Z1)
-//
 func (P1 *extPoint) Equal(CP2 abstract.Point) bool {
 	P2 := CP2.(*extPoint)
 	var t1, t2 nist.Int
@@ -99,14 +98,20 @@ func (P *extPoint) Set(CP2 abstract.Point) abstract.Point {
 	return P
 }
 
+// CMov sets P to P2 if cond == 1 and leaves P unchanged if cond == 0,
+// without branching on cond.
+func (P *extPoint) CMov(P2 abstract.Point, cond int) {
+	E2 := P2.(*extPoint)
+	P.X.CMov(&E2.X, cond)
+	P.Y.CMov(&E2.Y, cond)
+	P.Z.CMov(&E2.Z, cond)
+	P.T.CMov(&E2.T, cond)
+}
+
 func (P *extPoint) Clone() abstract.Point {
-	return &extPoint{
-		c: P.c,
-		X: P.X,
-		Y: P.Y,
-		Z: P.Z,
-		T: P.T,
-	}
+	P2 := new(extPoint)
+	P2.Set(P)
+	return P2
 }
 
 func (P *extPoint) Null() abstract.Point {
@@ -237,7 +242,6 @@ func (P *extPoint) double() {
 // Currently doesn't implement the optimization of
 // switching between projective and extended coordinates during
 // scalar multiplication.
-//
 func (P *extPoint) Mul(G abstract.Point, s abstract.Scalar) abstract.Point {
 	v := s.(*nist.Int).V
 	if G == nil {
@@ -279,7 +283,6 @@ func (P *extPoint) Mul(G abstract.Point, s abstract.Scalar) abstract.Point {
 // special case with curve parameter a=-1.
 // We leave the task of hyperoptimization to curve-specific implementations
 // such as the ed25519 package.
-//
 type ExtendedCurve struct {
 	curve          // generic Edwards curve functionality
 	null  extPoint // Constant identity/null point (0,1)
@@ -11,7 +11,6 @@
 // are isomorphic to curves having c == 1.
 // For details see Bernstein et al, "Twisted Edwards Curves",
 // http://eprint.iacr.org/2008/013.pdf
-//
 package edwards
 
 import (
@@ -46,7 +45,6 @@ func (p *Param) String() string {
 // Bernstein et al, "Elligator: Elliptic-curve points indistinguishable
 // from uniform random strings"
 // http://elligator.cr.yp.to/elligator-20130828.pdf
-//
 func Param1174() *Param {
 	var p Param
 	var mi nist.Int
@@ -74,7 +72,6 @@ func Param1174() *Param {
 // Parameters defining the Edwards version of Curve25519, as specified in:
 // Bernstein et al, "High-speed high-security signatures",
 // http://ed25519.cr.yp.to/ed25519-20110926.pdf
-//
 func Param25519() *Param {
 	var p Param
 	var qs big.Int
@@ -102,7 +99,6 @@ func Param25519() *Param {
 // and more recently in:
 // "Additional Elliptic Curves for IETF protocols"
 // http://tools.ietf.org/html/draft-ladd-safecurves-02
-//
 func ParamE382() *Param {
 	var p Param
 	var qs big.Int
@@ -143,7 +139,6 @@ func Param41417() *Param {
 // and more recently included in:
 // "Additional Elliptic Curves for IETF protocols"
 // http://tools.ietf.org/html/draft-ladd-safecurves-02
-//
 func ParamE521() *Param {
 	var p Param
 	var qs big.Int
@@ -72,7 +72,6 @@ func (P *projPoint) HideDecode(rep []byte) {
 //	(X1/Z1,Y1/Z1) == (X2/Z2,Y2/Z2)
 //		iff
 //	(X1*Z2,Y1*Z2) == (X2*Z1,Y2*Z1)
-//
 func (P1 *projPoint) Equal(CP2 abstract.Point) bool {
 	P2 := CP2.(*projPoint)
 	var t1, t2 nist.Int
@@ -90,13 +89,19 @@ func (P *projPoint) Set(CP2 abstract.Point) abstract.Point {
 	return P
 }
 
+// CMov sets P to P2 if cond == 1 and leaves P unchanged if cond == 0,
+// without branching on cond.
+func (P *projPoint) CMov(P2 abstract.Point, cond int) {
+	E2 := P2.(*projPoint)
+	P.X.CMov(&E2.X, cond)
+	P.Y.CMov(&E2.Y, cond)
+	P.Z.CMov(&E2.Z, cond)
+}
+
 func (P *projPoint) Clone() abstract.Point {
-	return &projPoint{
-		c: P.c,
-		X: P.X,
-		Y: P.Y,
-		Z: P.Z,
-	}
+	P2 := new(projPoint)
+	P2.Set(P)
+	return P2
 }
 
 func (P *projPoint) Null() abstract.Point {
@@ -136,7 +141,6 @@ func (P *projPoint) Data() ([]byte, error) {
 //
 //	http://eprint.iacr.org/2008/013.pdf
 //	https://hyperelliptic.org/EFD/g1p/auto-twisted-projective.html
-//
 func (P *projPoint) Add(CP1, CP2 abstract.Point) abstract.Point {
 	P1 := CP1.(*projPoint)
 	P2 := CP2.(*projPoint)
@@ -239,7 +243,6 @@ func (P *projPoint) Mul(G abstract.Point, s abstract.Scalar) abstract.Point {
 // and avoids expensive modular inversions on the critical paths.
 // Uses the projective arithmetic formulas in:
 // http://cr.yp.to/newelliptic/newelliptic-20070906.pdf
-//
 type ProjectiveCurve struct {
 	curve           // generic Edwards curve functionality
 	null  projPoint // Constant identity/null point (0,1)
@@ -3,6 +3,7 @@ package edwards
 import (
 	"crypto/cipher"
 	"crypto/sha256"
+	"crypto/sha512"
 	"hash"
 	"io"
 	"reflect"
@@ -54,3 +55,54 @@ func NewAES128SHA256Ed25519(fullGroup bool) abstract.Suite {
 	suite.Init(Param25519(), fullGroup)
 	return suite
 }
+
+// suiteHigh is a ciphersuite for the curves with higher security levels,
+// which pair the curve with a matching hash function and SHAKE256.
+type suiteHigh struct {
+	ProjectiveCurve
+	hash func() hash.Hash
+}
+
+func (s *suiteHigh) Hash() hash.Hash {
+	return s.hash()
+}
+
+// SHA3/SHAKE256 Sponge Cipher
+func (s *suiteHigh) Cipher(key []byte, options ...interface{}) abstract.Cipher {
+	return sha3.NewShakeCipher256(key, options...)
+}
+
+func (s *suiteHigh) Read(r io.Reader, objs ...interface{}) error {
+	return abstract.SuiteRead(s, r, objs)
+}
+
+func (s *suiteHigh) Write(w io.Writer, objs ...interface{}) error {
+	return abstract.SuiteWrite(s, w, objs)
+}
+
+func (s *suiteHigh) New(t reflect.Type) interface{} {
+	return abstract.SuiteNew(s, t)
+}
+
+func (s *suiteHigh) NewKey(rand cipher.Stream) abstract.Scalar {
+	if rand == nil {
+		rand = random.Stream
+	}
+	return s.Scalar().Pick(rand)
+}
+
+// Ciphersuite at the 192-bit security level based on SHAKE256, SHA-384, and
+// the E-382 curve.
+func NewSHAKE256SHA384E382(fullGroup bool) abstract.Suite {
+	suite := &suiteHigh{hash: sha512.New384}
+	suite.Init(ParamE382(), fullGroup)
+	return suite
+}
+
+// Ciphersuite at the 256-bit security level based on SHAKE256, SHA-512, and
+// the E-521 curve.
+func NewSHAKE256SHA512E521(fullGroup bool) abstract.Suite {
+	suite := &suiteHigh{hash: sha512.New}
+	suite.Init(ParamE521(), fullGroup)
+	return suite
+}
@@ -0,0 +1,255 @@
+// Package enroll implements the enrollment phase that precedes a PVSS or DKG
+// ceremony: participants generate key pairs, prove possession of their
+// private keys and register their public keys with the ceremony. The
+// registry orders the keys deterministically, independent of the order of
+// registration, so that every participant derives the same list of trustee
+// keys and the same trustee indices from the same set of enrollments:
+//
+//	participant: kps, enrollments := GenerateBatch(suite, ceremony, n, rand)
+//	coordinator: reg := NewRegistry(suite, ceremony)
+//	             reg.Register(enrollments...)
+//	             X := reg.Keys()  // trustee keys for pvss.EncShares etc.
+//
+// The proof of possession is a Schnorr signature on the public key and the
+// ceremony identifier, so an enrollment cannot be replayed into another
+// ceremony and nobody can register a key derived from the keys of others.
+package enroll
+
+import (
+	"bytes"
+	"crypto/cipher"
+	"encoding/binary"
+	"errors"
+	"io"
+	"sort"
+
+	"github.com/dedis/crypto/abstract"
+	"github.com/dedis/crypto/config"
+	"github.com/dedis/crypto/group"
+	"github.com/dedis/crypto/sign"
+)
+
+// Some error definitions
+var errorPossession = errors.New("invalid proof of possession")
+var errorDuplicate = errors.New("key already registered")
+var errorEncoding = errors.New("invalid registry encoding")
+
+// Enrollment is a participant's public key together with a proof of
+// possession of the private key.
+type Enrollment struct {
+	Public abstract.Point
+	Proof  []byte // Schnorr signature by the key on the ceremony
+}
+
+func possessionMessage(ceremony []byte, X abstract.Point) ([]byte, error) {
+	buf, err := X.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	msg := []byte("enroll-possession")
+	msg = binary.BigEndian.AppendUint32(msg, uint32(len(ceremony)))
+	msg = append(msg, ceremony...)
+	return append(msg, buf...), nil
+}
+
+// NewEnrollment creates the enrollment of the key pair kp for the ceremony
+// with the given identifier.
+func NewEnrollment(kp *config.KeyPair, ceremony []byte) (*Enrollment, error) {
+	msg, err := possessionMessage(ceremony, kp.Public)
+	if err != nil {
+		return nil, err
+	}
+	pop, err := sign.Schnorr(kp.Suite, kp.Secret, msg)
+	if err != nil {
+		return nil, err
+	}
+	return &Enrollment{kp.Public, pop}, nil
+}
+
+// Verify checks that the enrollment is for the ceremony and that its public
+// key is acceptable under the default group policy, see group.DefaultPolicy.
+func (e *Enrollment) Verify(suite abstract.Suite, ceremony []byte) error {
+	if err := group.DefaultPolicy(suite).Check(suite, e.Public); err != nil {
+		return err
+	}
+	msg, err := possessionMessage(ceremony, e.Public)
+	if err != nil {
+		return err
+	}
+	if err := sign.VerifySchnorr(suite, e.Public, msg, e.Proof); err != nil {
+		return errorPossession
+	}
+	return nil
+}
+
+// GenerateBatch generates n key pairs from rand together with their
+// enrollments for the ceremony.
+func GenerateBatch(suite abstract.Suite, ceremony []byte, n int, rand cipher.Stream) ([]*config.KeyPair, []*Enrollment, error) {
+	kps := make([]*config.KeyPair, n)
+	enrollments := make([]*Enrollment, n)
+	for i := range kps {
+		kps[i] = new(config.KeyPair)
+		kps[i].Gen(suite, rand)
+		var err error
+		if enrollments[i], err = NewEnrollment(kps[i], ceremony); err != nil {
+			return nil, nil, err
+		}
+	}
+	return kps, enrollments, nil
+}
+
+// VerifyBatch verifies the enrollments for the ceremony. It returns the
+// errors of the invalid enrollments by position, or nil if all are valid.
+func VerifyBatch(suite abstract.Suite, ceremony []byte, enrollments []*Enrollment) []error {
+	var errs []error
+	for i, e := range enrollments {
+		if err := e.Verify(suite, ceremony); err != nil {
+			if errs == nil {
+				errs = make([]error, len(enrollments))
+			}
+			errs[i] = err
+		}
+	}
+	return errs
+}
+
+// Registry is the set of verified enrollments of a ceremony, ordered by the
+// binary encoding of their public keys.
+type Registry struct {
+	suite    abstract.Suite
+	ceremony []byte
+	entries  []*Enrollment
+	keys     [][]byte // Encodings of the public keys, sorted
+}
+
+// NewRegistry creates an empty registry for the ceremony.
+func NewRegistry(suite abstract.Suite, ceremony []byte) *Registry {
+	return &Registry{suite: suite, ceremony: ceremony}
+}
+
+// Register verifies the enrollments and adds the valid ones that are not yet
+// registered. It returns the errors of the rejected enrollments by position,
+// or nil if all were added.
+func (r *Registry) Register(enrollments ...*Enrollment) []error {
+	errs := VerifyBatch(r.suite, r.ceremony, enrollments)
+	fail := func(i int, err error) {
+		if errs == nil {
+			errs = make([]error, len(enrollments))
+		}
+		errs[i] = err
+	}
+	for i, e := range enrollments {
+		if errs != nil && errs[i] != nil {
+			continue
+		}
+		key, err := e.Public.MarshalBinary()
+		if err != nil {
+			fail(i, err)
+			continue
+		}
+		pos := sort.Search(len(r.keys), func(j int) bool { return bytes.Compare(r.keys[j], key) >= 0 })
+		if pos < len(r.keys) && bytes.Equal(r.keys[pos], key) {
+			fail(i, errorDuplicate)
+			continue
+		}
+		r.keys = append(r.keys, nil)
+		copy(r.keys[pos+1:], r.keys[pos:])
+		r.keys[pos] = key
+		r.entries = append(r.entries, nil)
+		copy(r.entries[pos+1:], r.entries[pos:])
+		r.entries[pos] = e
+	}
+	return errs
+}
+
+// Len returns the number of registered keys.
+func (r *Registry) Len() int {
+	return len(r.entries)
+}
+
+// Keys returns the registered public keys in registry order. The position of
+// a key is the participant's index in the ceremony.
+func (r *Registry) Keys() []abstract.Point {
+	keys := make([]abstract.Point, len(r.entries))
+	for i, e := range r.entries {
+		keys[i] = e.Public
+	}
+	return keys
+}
+
+// Index returns the index of the public key in the registry, or -1 if it is
+// not registered.
+func (r *Registry) Index(public abstract.Point) int {
+	key, err := public.MarshalBinary()
+	if err != nil {
+		return -1
+	}
+	pos := sort.Search(len(r.keys), func(j int) bool { return bytes.Compare(r.keys[j], key) >= 0 })
+	if pos < len(r.keys) && bytes.Equal(r.keys[pos], key) {
+		return pos
+	}
+	return -1
+}
+
+// MarshalBinary encodes the registry as the length-prefixed ceremony
+// identifier, the number of enrollments and, in registry order, every public
+// key followed by its length-prefixed proof of possession. All lengths are
+// 32-bit big-endian integers.
+func (r *Registry) MarshalBinary() ([]byte, error) {
+	var b bytes.Buffer
+	binary.Write(&b, binary.BigEndian, uint32(len(r.ceremony)))
+	b.Write(r.ceremony)
+	binary.Write(&b, binary.BigEndian, uint32(len(r.entries)))
+	for _, e := range r.entries {
+		if _, err := e.Public.MarshalTo(&b); err != nil {
+			return nil, err
+		}
+		binary.Write(&b, binary.BigEndian, uint32(len(e.Proof)))
+		b.Write(e.Proof)
+	}
+	return b.Bytes(), nil
+}
+
+// UnmarshalRegistry decodes a registry over suite encoded with MarshalBinary
+// and verifies all its enrollments.
+func UnmarshalRegistry(suite abstract.Suite, buf []byte) (*Registry, error) {
+	rd := bytes.NewReader(buf)
+	readBytes := func() ([]byte, error) {
+		var size uint32
+		if err := binary.Read(rd, binary.BigEndian, &size); err != nil || int64(size) > int64(rd.Len()) {
+			return nil, errorEncoding
+		}
+		data := make([]byte, size)
+		io.ReadFull(rd, data)
+		return data, nil
+	}
+	ceremony, err := readBytes()
+	if err != nil {
+		return nil, err
+	}
+	var n uint32
+	if err := binary.Read(rd, binary.BigEndian, &n); err != nil || uint64(n) > uint64(rd.Len())/uint64(suite.PointLen()+4) {
+		return nil, errorEncoding
+	}
+	enrollments := make([]*Enrollment, n)
+	for i := range enrollments {
+		e := &Enrollment{Public: suite.Point()}
+		if _, err := e.Public.UnmarshalFrom(rd); err != nil {
+			return nil, errorEncoding
+		}
+		if e.Proof, err = readBytes(); err != nil {
+			return nil, err
+		}
+		enrollments[i] = e
+	}
+	if rd.Len() != 0 {
+		return nil, errorEncoding
+	}
+	r := NewRegistry(suite, ceremony)
+	for _, err := range r.Register(enrollments...) {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
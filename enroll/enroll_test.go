@@ -0,0 +1,81 @@
+package enroll
+
+import (
+	"testing"
+
+	"github.com/dedis/crypto/config"
+	"github.com/dedis/crypto/edwards"
+	"github.com/dedis/crypto/group"
+	"github.com/dedis/crypto/random"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var suite = edwards.NewAES128SHA256Ed25519(false)
+
+func TestEnrollment(t *testing.T) {
+	ceremony := []byte("ceremony 1")
+	kps, enrollments, err := GenerateBatch(suite, ceremony, 5, random.Stream)
+	require.Nil(t, err)
+	require.Len(t, kps, 5)
+	assert.Nil(t, VerifyBatch(suite, ceremony, enrollments))
+
+	// Enrollments do not carry over to other ceremonies
+	errs := VerifyBatch(suite, []byte("ceremony 2"), enrollments)
+	require.Len(t, errs, 5)
+	assert.Equal(t, errorPossession, errs[0])
+
+	// Rogue keys without a proof of possession are rejected
+	rogue := &Enrollment{suite.Point().Sub(kps[1].Public, kps[0].Public), enrollments[0].Proof}
+	assert.Equal(t, errorPossession, rogue.Verify(suite, ceremony))
+	null := &config.KeyPair{Suite: suite, Public: suite.Point().Null(), Secret: suite.Scalar().Zero()}
+	e, err := NewEnrollment(null, ceremony)
+	require.Nil(t, err)
+	assert.Equal(t, group.ErrIdentity, e.Verify(suite, ceremony))
+}
+
+func TestRegistry(t *testing.T) {
+	ceremony := []byte("ceremony")
+	kps, enrollments, err := GenerateBatch(suite, ceremony, 6, random.Stream)
+	require.Nil(t, err)
+
+	r1 := NewRegistry(suite, ceremony)
+	assert.Nil(t, r1.Register(enrollments[:3]...))
+	assert.Nil(t, r1.Register(enrollments[3:]...))
+	r2 := NewRegistry(suite, ceremony)
+	for i := len(enrollments) - 1; i >= 0; i-- {
+		assert.Nil(t, r2.Register(enrollments[i]))
+	}
+
+	// The order does not depend on the order of registration
+	k1, k2 := r1.Keys(), r2.Keys()
+	require.Len(t, k1, 6)
+	for i := range k1 {
+		assert.True(t, k1[i].Equal(k2[i]))
+		assert.Equal(t, i, r1.Index(k1[i]))
+	}
+	for _, kp := range kps {
+		assert.True(t, r1.Index(kp.Public) >= 0)
+	}
+	assert.Equal(t, -1, r1.Index(suite.Point().Base()))
+
+	errs := r1.Register(enrollments[2], &Enrollment{suite.Point().Base(), enrollments[0].Proof})
+	require.Len(t, errs, 2)
+	assert.Equal(t, errorDuplicate, errs[0])
+	assert.Equal(t, errorPossession, errs[1])
+	assert.Equal(t, 6, r1.Len())
+
+	buf, err := r1.MarshalBinary()
+	require.Nil(t, err)
+	r3, err := UnmarshalRegistry(suite, buf)
+	require.Nil(t, err)
+	buf3, err := r3.MarshalBinary()
+	require.Nil(t, err)
+	assert.Equal(t, buf, buf3)
+
+	_, err = UnmarshalRegistry(suite, buf[:len(buf)-1])
+	assert.NotNil(t, err)
+	buf[len(buf)-1] ^= 1
+	_, err = UnmarshalRegistry(suite, buf)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,168 @@
+// Package exchange implements the fair exchange of a secret scalar, such as a
+// Shamir share or a private key, against a payment. The secret t is
+// identified by the public statement T = tG, e.g. the public commitment of a
+// share or a public key, so the buyer knows beforehand what it pays for. The
+// buyer locks its signature on the payment to T with an adaptor signature;
+// the seller can only complete the signature, and thereby claim the payment,
+// by using t, and the buyer extracts t from the published signature:
+//
+//	seller: offer := seller.Offer()
+//	buyer:  payment := buyer.Accept(offer, want, msg, rand)
+//	seller: sig := seller.Claim(payment)   // publish sig to get paid
+//	buyer:  t := buyer.Receive(sig)        // once sig is published
+//
+// For a share, the buyer uses AcceptShare and ReceiveShare instead, which
+// fix the index of the share to the one the buyer asked for.
+//
+// The payment system is expected to accept sig, a sign.Schnorr signature of
+// the buyer on msg, as authorization of the payment, and to make it public.
+package exchange
+
+import (
+	"crypto/cipher"
+	"errors"
+
+	"github.com/dedis/crypto/abstract"
+	"github.com/dedis/crypto/share"
+	"github.com/dedis/crypto/sign"
+	"github.com/dedis/crypto/sign/adaptor"
+)
+
+// Some error definitions
+var errorStatement = errors.New("offered statement differs from expected one")
+var errorState = errors.New("protocol step called out of order")
+var errorIndex = errors.New("offered share index differs from expected one")
+
+// Offer announces the sale of the secret with statement T. For a share, I is
+// its index.
+type Offer struct {
+	I         int
+	Statement abstract.Point
+}
+
+// Payment is the buyer's signature on the payment message, locked to the
+// statement of the offer.
+type Payment struct {
+	Msg    []byte // Payment message, e.g. a transaction
+	PreSig []byte // Adaptor pre-signature of the buyer on Msg
+}
+
+// ShareStatement returns the statement of the share with index i of a
+// sharing with public commitment polynomial pubPoly, which must be committed
+// with respect to the standard base point.
+func ShareStatement(pubPoly *share.PubPoly, i int) abstract.Point {
+	return pubPoly.Eval(i).V
+}
+
+// Seller is the party selling a secret.
+type Seller struct {
+	suite  abstract.Suite
+	secret abstract.Scalar
+	buyer  abstract.Point
+	offer  *Offer
+}
+
+// NewSeller creates the seller of the secret with the given index, for a
+// share, or -1 otherwise, to the buyer with public key buyer.
+func NewSeller(suite abstract.Suite, index int, secret abstract.Scalar, buyer abstract.Point) *Seller {
+	return &Seller{
+		suite:  suite,
+		secret: secret,
+		buyer:  buyer,
+		offer:  &Offer{index, suite.Point().Mul(nil, secret)},
+	}
+}
+
+// NewShareSeller creates the seller of a share.
+func NewShareSeller(suite abstract.Suite, priShare *share.PriShare, buyer abstract.Point) *Seller {
+	return NewSeller(suite, priShare.I, priShare.V, buyer)
+}
+
+// Offer returns the offer to send to the buyer.
+func (s *Seller) Offer() *Offer {
+	return s.offer
+}
+
+// Claim checks the buyer's payment and completes its signature, which the
+// seller publishes to claim the payment. Publishing the signature reveals the
+// secret to the buyer.
+func (s *Seller) Claim(p *Payment) ([]byte, error) {
+	if err := adaptor.PreVerify(s.suite, s.buyer, s.offer.Statement, p.Msg, p.PreSig); err != nil {
+		return nil, err
+	}
+	return adaptor.Adapt(s.suite, p.PreSig, s.secret)
+}
+
+// Buyer is the party buying a secret.
+type Buyer struct {
+	suite     abstract.Suite
+	private   abstract.Scalar
+	statement abstract.Point
+	index     int // Index of the share bought with AcceptShare, or -1
+	payment   *Payment
+}
+
+// NewBuyer creates a buyer with the given private key, whose public key must
+// be known to the seller.
+func NewBuyer(suite abstract.Suite, private abstract.Scalar) *Buyer {
+	return &Buyer{suite: suite, private: private, index: -1}
+}
+
+// Accept checks that the offer is for the statement want, e.g. the
+// ShareStatement of the share to buy, and returns the payment locked to it.
+func (b *Buyer) Accept(offer *Offer, want abstract.Point, msg []byte, rand cipher.Stream) (*Payment, error) {
+	if b.payment != nil {
+		return nil, errorState
+	}
+	if !offer.Statement.Equal(want) {
+		return nil, errorStatement
+	}
+	pre, err := adaptor.PreSign(b.suite, b.private, want, msg, rand)
+	if err != nil {
+		return nil, err
+	}
+	b.statement = want
+	b.payment = &Payment{Msg: msg, PreSig: pre}
+	return b.payment, nil
+}
+
+// AcceptShare is like Accept for the purchase of the share with index i of a
+// sharing with public commitment polynomial pubPoly. It also checks the index
+// of the offer, and ReceiveShare labels the share with i.
+func (b *Buyer) AcceptShare(offer *Offer, pubPoly *share.PubPoly, i int, msg []byte, rand cipher.Stream) (*Payment, error) {
+	if offer.I != i {
+		return nil, errorIndex
+	}
+	p, err := b.Accept(offer, ShareStatement(pubPoly, i), msg, rand)
+	if err != nil {
+		return nil, err
+	}
+	b.index = i
+	return p, nil
+}
+
+// Receive extracts the secret from the signature the seller published to
+// claim the payment.
+func (b *Buyer) Receive(sig []byte) (abstract.Scalar, error) {
+	if b.payment == nil {
+		return nil, errorState
+	}
+	public := b.suite.Point().Mul(nil, b.private)
+	if err := sign.VerifySchnorr(b.suite, public, b.payment.Msg, sig); err != nil {
+		return nil, err
+	}
+	return adaptor.Extract(b.suite, b.statement, b.payment.PreSig, sig)
+}
+
+// ReceiveShare is like Receive for the purchase of a share with AcceptShare.
+// The share has the index the buyer asked for, whatever the offer claimed.
+func (b *Buyer) ReceiveShare(sig []byte) (*share.PriShare, error) {
+	if b.index < 0 {
+		return nil, errorState
+	}
+	v, err := b.Receive(sig)
+	if err != nil {
+		return nil, err
+	}
+	return &share.PriShare{I: b.index, V: v}, nil
+}
@@ -0,0 +1,90 @@
+package exchange
+
+import (
+	"testing"
+
+	"github.com/dedis/crypto/edwards"
+	"github.com/dedis/crypto/random"
+	"github.com/dedis/crypto/share"
+	"github.com/dedis/crypto/sign"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var suite = edwards.NewAES128SHA256Ed25519(false)
+
+func TestExchangeShare(t *testing.T) {
+	priPoly := share.NewPriPoly(suite, 3, nil, random.Stream)
+	pubPoly := priPoly.Commit(nil)
+	shares := priPoly.Shares(5)
+
+	x := suite.Scalar().Pick(random.Stream)
+	X := suite.Point().Mul(nil, x)
+	seller := NewShareSeller(suite, shares[2], X)
+	buyer := NewBuyer(suite, x)
+	msg := []byte("transfer 10 coins to seller")
+
+	// The buyer refuses an offer for another share, also if the seller
+	// labels it with the wanted index
+	_, err := buyer.AcceptShare(seller.Offer(), pubPoly, 1, msg, random.Stream)
+	assert.Equal(t, errorIndex, err)
+	mislabeled := &Offer{1, seller.Offer().Statement}
+	_, err = buyer.AcceptShare(mislabeled, pubPoly, 1, msg, random.Stream)
+	assert.Equal(t, errorStatement, err)
+
+	payment, err := buyer.AcceptShare(seller.Offer(), pubPoly, 2, msg, random.Stream)
+	require.Nil(t, err)
+	// Relabeling the offer afterwards does not change the bought share
+	seller.Offer().I = 4
+	sig, err := seller.Claim(payment)
+	require.Nil(t, err)
+	require.Nil(t, sign.VerifySchnorr(suite, X, msg, sig))
+
+	got, err := buyer.ReceiveShare(sig)
+	require.Nil(t, err)
+	assert.True(t, got.Equal(shares[2]))
+	assert.True(t, pubPoly.Check(got))
+}
+
+func TestExchangeErrors(t *testing.T) {
+	key := suite.Scalar().Pick(random.Stream)
+	x := suite.Scalar().Pick(random.Stream)
+	X := suite.Point().Mul(nil, x)
+	seller := NewSeller(suite, -1, key, X)
+	buyer := NewBuyer(suite, x)
+
+	_, err := buyer.Receive(nil)
+	assert.Equal(t, errorState, err)
+	_, err = buyer.ReceiveShare(nil)
+	assert.Equal(t, errorState, err)
+
+	msg := []byte("payment")
+	payment, err := buyer.Accept(seller.Offer(), suite.Point().Mul(nil, key), msg, random.Stream)
+	require.Nil(t, err)
+	_, err = buyer.Accept(seller.Offer(), suite.Point().Mul(nil, key), msg, random.Stream)
+	assert.Equal(t, errorState, err)
+
+	// The seller rejects a payment for another message or from another key
+	forged := &Payment{Msg: []byte("no payment"), PreSig: payment.PreSig}
+	_, err = seller.Claim(forged)
+	assert.NotNil(t, err)
+	other := NewSeller(suite, -1, key, suite.Point().Mul(nil, suite.Scalar().Pick(random.Stream)))
+	_, err = other.Claim(payment)
+	assert.NotNil(t, err)
+
+	// A signature that is not adapted from the payment reveals nothing
+	sig, err := sign.Schnorr(suite, x, msg)
+	require.Nil(t, err)
+	_, err = buyer.Receive(sig)
+	assert.NotNil(t, err)
+
+	sig, err = seller.Claim(payment)
+	require.Nil(t, err)
+	got, err := buyer.Receive(sig)
+	require.Nil(t, err)
+	assert.True(t, got.Equal(key))
+
+	// A secret bought with Accept is not a share
+	_, err = buyer.ReceiveShare(sig)
+	assert.Equal(t, errorState, err)
+}
@@ -0,0 +1,188 @@
+// Package fuzz provides entry points for fuzzing the decoders of the wire
+// formats exposed by this library. Every entry point has the signature
+// expected by go-fuzz and libFuzzer drivers: it decodes the input and, if the
+// input is accepted, checks that re-encoding and decoding again round-trips
+// to an equal value with an identical encoding. A violated invariant panics,
+// which the fuzzing engine reports as a crash. The return value is 1 for
+// accepted inputs, which the engine should prioritize, and 0 otherwise.
+//
+// The entry points can also be driven by the native Go fuzzer, see the tests
+// of this package, and Corpus returns valid seed inputs for each of them.
+package fuzz
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/dedis/crypto/abstract"
+	"github.com/dedis/crypto/edwards"
+	"github.com/dedis/crypto/proof"
+	"github.com/dedis/crypto/pvss"
+	"github.com/dedis/crypto/random"
+	"github.com/dedis/crypto/share"
+)
+
+// The entry points decode encodings of this suite.
+var suite = edwards.NewAES128SHA256Ed25519(false)
+
+// Target is a fuzzing entry point.
+type Target func(data []byte) int
+
+// Targets lists the entry points of this package by name.
+var Targets = map[string]Target{
+	"point":      DecodePoint,
+	"proof":      DecodeProof,
+	"transcript": DecodeTranscript,
+	"pubpoly":    DecodePubPoly,
+}
+
+// DecodePoint fuzzes the decoding of a point.
+func DecodePoint(data []byte) int {
+	p := suite.Point()
+	if err := p.UnmarshalBinary(data); err != nil {
+		return 0
+	}
+	enc := mustMarshal(p)
+	q := suite.Point()
+	if err := q.UnmarshalBinary(enc); err != nil {
+		panic(fmt.Sprintf("fuzz: re-decoding point: %v", err))
+	}
+	if !q.Equal(p) || !bytes.Equal(mustMarshal(q), enc) {
+		panic("fuzz: point does not round-trip")
+	}
+	return 1
+}
+
+// DecodeProof fuzzes the decoding of a DLEQ proof in the suite's reflective
+// encoding.
+func DecodeProof(data []byte) int {
+	p, err := readProof(data)
+	if err != nil {
+		return 0
+	}
+	var b bytes.Buffer
+	if err := suite.Write(&b, p); err != nil {
+		panic(fmt.Sprintf("fuzz: encoding proof: %v", err))
+	}
+	q, err := readProof(b.Bytes())
+	if err != nil {
+		panic(fmt.Sprintf("fuzz: re-decoding proof: %v", err))
+	}
+	var c bytes.Buffer
+	if err := suite.Write(&c, q); err != nil {
+		panic(fmt.Sprintf("fuzz: encoding proof: %v", err))
+	}
+	if !q.Equal(p) || !bytes.Equal(c.Bytes(), b.Bytes()) {
+		panic("fuzz: proof does not round-trip")
+	}
+	return 1
+}
+
+func readProof(data []byte) (*proof.DLEQProof, error) {
+	r := bytes.NewReader(data)
+	p := new(proof.DLEQProof)
+	if err := suite.Read(r, p); err != nil {
+		return nil, err
+	}
+	if r.Len() != 0 {
+		return nil, fmt.Errorf("fuzz: %d trailing bytes", r.Len())
+	}
+	return p, nil
+}
+
+// DecodeTranscript fuzzes the decoding of a PVSS share together with its
+// proof, as published by a dealer or a decrypting participant.
+func DecodeTranscript(data []byte) int {
+	s := pvss.NewPubVerShare(suite)
+	if err := s.UnmarshalBinary(data); err != nil {
+		return 0
+	}
+	enc := mustMarshal(s)
+	t := pvss.NewPubVerShare(suite)
+	if err := t.UnmarshalBinary(enc); err != nil {
+		panic(fmt.Sprintf("fuzz: re-decoding transcript: %v", err))
+	}
+	if t.S.I != s.S.I || !t.S.V.Equal(s.S.V) || !t.P.Equal(&s.P) || !bytes.Equal(mustMarshal(t), enc) {
+		panic("fuzz: transcript does not round-trip")
+	}
+	return 1
+}
+
+// DecodePubPoly fuzzes the decoding of a public commitment polynomial.
+func DecodePubPoly(data []byte) int {
+	p, err := share.UnmarshalPubPoly(suite, data)
+	if err != nil {
+		return 0
+	}
+	enc := mustMarshal(p)
+	q, err := share.UnmarshalPubPoly(suite, enc)
+	if err != nil {
+		panic(fmt.Sprintf("fuzz: re-decoding polynomial: %v", err))
+	}
+	if !q.Equal(p) || !bytes.Equal(mustMarshal(q), enc) {
+		panic("fuzz: polynomial does not round-trip")
+	}
+	return 1
+}
+
+type binaryMarshaler interface {
+	MarshalBinary() ([]byte, error)
+}
+
+func mustMarshal(m binaryMarshaler) []byte {
+	enc, err := m.MarshalBinary()
+	if err != nil {
+		panic(fmt.Sprintf("fuzz: encoding decoded value: %v", err))
+	}
+	return enc
+}
+
+// Corpus returns freshly generated valid seed inputs for the entry point with
+// the given name, or nil for an unknown name.
+func Corpus(name string) [][]byte {
+	rand := random.Stream
+	var seeds [][]byte
+	switch name {
+	case "point":
+		seeds = append(seeds, mustMarshal(suite.Point().Null()), mustMarshal(suite.Point().Base()))
+		for i := 0; i < 4; i++ {
+			p, _ := suite.Point().Pick(nil, rand)
+			seeds = append(seeds, mustMarshal(p))
+		}
+	case "proof":
+		for i := 0; i < 4; i++ {
+			x := suite.Scalar().Pick(rand)
+			H, _ := suite.Point().Pick(nil, rand)
+			p, _, _, err := proof.NewDLEQProof(suite, suite.Point().Base(), H, x)
+			if err != nil {
+				panic(err)
+			}
+			var b bytes.Buffer
+			suite.Write(&b, p)
+			seeds = append(seeds, b.Bytes())
+		}
+	case "transcript":
+		H, _ := suite.Point().Pick(nil, rand)
+		n := 4
+		X := make([]abstract.Point, n)
+		for i := range X {
+			X[i] = suite.Point().Mul(nil, suite.Scalar().Pick(rand))
+		}
+		shares, _, err := pvss.EncShares(suite, H, X, suite.Scalar().Pick(rand), 3)
+		if err != nil {
+			panic(err)
+		}
+		for _, s := range shares {
+			seeds = append(seeds, mustMarshal(s))
+		}
+	case "pubpoly":
+		for t := 1; t <= 4; t++ {
+			poly := share.NewPriPoly(suite, t, nil, rand)
+			seeds = append(seeds, mustMarshal(poly.Commit(nil)))
+		}
+		poly := share.NewPriPoly(suite, 2, nil, rand)
+		H, _ := suite.Point().Pick(nil, rand)
+		seeds = append(seeds, mustMarshal(poly.Commit(H)))
+	}
+	return seeds
+}
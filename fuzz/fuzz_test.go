@@ -0,0 +1,47 @@
+package fuzz
+
+import (
+	"testing"
+
+	"github.com/dedis/crypto/random"
+)
+
+func TestCorpus(test *testing.T) {
+	for name, target := range Targets {
+		seeds := Corpus(name)
+		if len(seeds) == 0 {
+			test.Fatalf("no seeds for %s", name)
+		}
+		for _, seed := range seeds {
+			if target(seed) != 1 {
+				test.Fatalf("%s rejects seed %x", name, seed)
+			}
+			// Mutated and truncated inputs must not crash
+			for i := 0; i < 32; i++ {
+				m := append([]byte(nil), seed...)
+				m[int(random.Uint32(random.Stream))%len(m)] ^= byte(1 + i)
+				target(m)
+			}
+			target(seed[:len(seed)/2])
+			target(append(seed, 0))
+		}
+	}
+	if Corpus("unknown") != nil {
+		test.Fatal("seeds for unknown target")
+	}
+}
+
+func fuzzTarget(f *testing.F, name string) {
+	for _, seed := range Corpus(name) {
+		f.Add(seed)
+	}
+	target := Targets[name]
+	f.Fuzz(func(t *testing.T, data []byte) {
+		target(data)
+	})
+}
+
+func FuzzDecodePoint(f *testing.F)      { fuzzTarget(f, "point") }
+func FuzzDecodeProof(f *testing.F)      { fuzzTarget(f, "proof") }
+func FuzzDecodeTranscript(f *testing.F) { fuzzTarget(f, "transcript") }
+func FuzzDecodePubPoly(f *testing.F)    { fuzzTarget(f, "pubpoly") }
@@ -0,0 +1,82 @@
+package group
+
+import (
+	"crypto/subtle"
+
+	"github.com/dedis/crypto/abstract"
+)
+
+// PointCMover is implemented by points supporting a constant-time conditional
+// move. CMov sets the point to src if cond == 1 and leaves it unchanged if
+// cond == 0; any other value of cond yields undefined results.
+type PointCMover interface {
+	CMov(src abstract.Point, cond int)
+}
+
+// ScalarCMover is implemented by scalars supporting a constant-time
+// conditional move, with the same semantics as PointCMover.
+type ScalarCMover interface {
+	CMov(src abstract.Scalar, cond int)
+}
+
+// PointCMov sets dst to src if cond == 1 and leaves dst unchanged if
+// cond == 0, without branching on cond. It uses the point's native CMov if
+// available and otherwise selects between the binary encodings of both
+// points, in which case decoding the selected encoding may take time
+// depending on its value.
+func PointCMov(dst, src abstract.Point, cond int) error {
+	if m, ok := dst.(PointCMover); ok {
+		m.CMov(src, cond)
+		return nil
+	}
+	x, err := dst.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	y, err := src.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	subtle.ConstantTimeCopy(cond, x, y)
+	return dst.UnmarshalBinary(x)
+}
+
+// PointCSwap swaps a and b if cond == 1 and leaves them unchanged if
+// cond == 0, without branching on cond.
+func PointCSwap(a, b abstract.Point, cond int) error {
+	t := a.Clone()
+	if err := PointCMov(a, b, cond); err != nil {
+		return err
+	}
+	return PointCMov(b, t, cond)
+}
+
+// ScalarCMov sets dst to src if cond == 1 and leaves dst unchanged if
+// cond == 0, without branching on cond. Like PointCMov, it falls back to
+// selecting between binary encodings for scalars without a native CMov.
+func ScalarCMov(dst, src abstract.Scalar, cond int) error {
+	if m, ok := dst.(ScalarCMover); ok {
+		m.CMov(src, cond)
+		return nil
+	}
+	x, err := dst.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	y, err := src.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	subtle.ConstantTimeCopy(cond, x, y)
+	return dst.UnmarshalBinary(x)
+}
+
+// ScalarCSwap swaps a and b if cond == 1 and leaves them unchanged if
+// cond == 0, without branching on cond.
+func ScalarCSwap(a, b abstract.Scalar, cond int) error {
+	t := a.Clone()
+	if err := ScalarCMov(a, b, cond); err != nil {
+		return err
+	}
+	return ScalarCMov(b, t, cond)
+}
@@ -0,0 +1,52 @@
+package group_test
+
+import (
+	"testing"
+
+	"github.com/dedis/crypto/abstract"
+	"github.com/dedis/crypto/ed25519"
+	"github.com/dedis/crypto/edwards"
+	"github.com/dedis/crypto/group"
+	"github.com/dedis/crypto/nist"
+	"github.com/dedis/crypto/random"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCMov(t *testing.T) {
+	suites := []abstract.Suite{
+		ed25519.NewAES128SHA256Ed25519(false),
+		edwards.NewAES128SHA256Ed25519(false),
+		nist.NewAES128SHA256P256(), // no native CMov on points
+	}
+	for _, suite := range suites {
+		a := suite.Scalar().Pick(random.Stream)
+		b := suite.Scalar().Pick(random.Stream)
+		A := suite.Point().Mul(nil, a)
+		B := suite.Point().Mul(nil, b)
+
+		x, X := a.Clone(), A.Clone()
+		require.Nil(t, group.ScalarCMov(x, b, 0))
+		require.Nil(t, group.PointCMov(X, B, 0))
+		assert.True(t, x.Equal(a))
+		assert.True(t, X.Equal(A))
+		require.Nil(t, group.ScalarCMov(x, b, 1))
+		require.Nil(t, group.PointCMov(X, B, 1))
+		assert.True(t, x.Equal(b))
+		assert.True(t, X.Equal(B))
+
+		x, y := a.Clone(), b.Clone()
+		X, Y := A.Clone(), B.Clone()
+		require.Nil(t, group.ScalarCSwap(x, y, 0))
+		require.Nil(t, group.PointCSwap(X, Y, 0))
+		assert.True(t, x.Equal(a) && y.Equal(b))
+		assert.True(t, X.Equal(A) && Y.Equal(B))
+		require.Nil(t, group.ScalarCSwap(x, y, 1))
+		require.Nil(t, group.PointCSwap(X, Y, 1))
+		assert.True(t, x.Equal(b) && y.Equal(a))
+		assert.True(t, X.Equal(B) && Y.Equal(A))
+
+		// The moved point is fully usable
+		assert.True(t, X.Add(X, Y).Equal(suite.Point().Mul(nil, suite.Scalar().Add(a, b))))
+	}
+}
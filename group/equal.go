@@ -0,0 +1,22 @@
+package group
+
+import "github.com/dedis/crypto/abstract"
+
+// PointEqual reports whether a and b are equal points, treating two nil points
+// as equal. It can serve as a comparer for the go-cmp package, e.g.
+// cmp.Comparer(group.PointEqual), for structures holding points.
+func PointEqual(a, b abstract.Point) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return a.Equal(b)
+}
+
+// ScalarEqual reports whether a and b are equal scalars, treating two nil
+// scalars as equal.
+func ScalarEqual(a, b abstract.Scalar) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return a.Equal(b)
+}
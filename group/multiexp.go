@@ -0,0 +1,147 @@
+package group
+
+import (
+	"crypto/cipher"
+	"errors"
+
+	"github.com/dedis/crypto/abstract"
+)
+
+// Some error definitions
+var errorMultiMul = errors.New("number of scalars and points differ")
+
+// multiMulWindow is the window size in bits of MultiMul.
+const multiMulWindow = 4
+
+// MultiMul computes the multi-exponentiation sum_i scalars[i]*points[i], where
+// a nil point stands for the standard base point, with the interleaved
+// windowed method of Straus. All terms share the doublings, so it is much
+// faster than computing the products one by one. MultiMul branches on the
+// scalars and must only be used with public values, such as in verification
+// equations.
+func MultiMul(g abstract.Group, scalars []abstract.Scalar, points []abstract.Point) (abstract.Point, error) {
+	if len(scalars) != len(points) {
+		return nil, errorMultiMul
+	}
+	one, err := g.Scalar().One().MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	littleEndian := len(one) > 1 && one[0] == 1
+
+	size := 1 << multiMulWindow
+	digits := make([][]byte, len(scalars))
+	tables := make([][]abstract.Point, len(scalars))
+	for i, s := range scalars {
+		buf, err := s.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		// Most significant window first
+		d := make([]byte, 0, 2*len(buf))
+		for k := range buf {
+			b := buf[k]
+			if littleEndian {
+				b = buf[len(buf)-1-k]
+			}
+			d = append(d, b>>4, b&0x0f)
+		}
+		digits[i] = d
+
+		P := points[i]
+		if P == nil {
+			P = g.Point().Base()
+		}
+		t := make([]abstract.Point, size)
+		t[1] = P.Clone()
+		for k := 2; k < size; k++ {
+			t[k] = g.Point().Add(t[k-1], P)
+		}
+		tables[i] = t
+	}
+
+	acc := g.Point().Null()
+	tmp := g.Point()
+	for pos := 0; pos < 2*len(one); pos++ {
+		for k := 0; k < multiMulWindow; k++ {
+			// Some implementations do not support aliased doubling
+			tmp.Set(acc)
+			acc.Add(tmp, tmp)
+		}
+		for i := range digits {
+			if pos < len(digits[i]) && digits[i][pos] != 0 {
+				acc.Add(acc, tables[i][digits[i][pos]])
+			}
+		}
+	}
+	return acc, nil
+}
+
+// Equation is a verification equation of the form
+//
+//	a_1P_1 + ... + a_nP_n == b_1Q_1 + ... + b_mQ_m
+//
+// over the points of a group, which is checked with a single
+// multi-exponentiation. For example, aG + bX == cH + D reads
+//
+//	group.NewEquation(g).Left(a, nil).Left(b, X).Right(c, H).Right(nil, D).Holds()
+type Equation struct {
+	g       abstract.Group
+	scalars []abstract.Scalar
+	points  []abstract.Point
+}
+
+// NewEquation creates an empty equation over the group g.
+func NewEquation(g abstract.Group) *Equation {
+	return &Equation{g: g}
+}
+
+// Left adds the term s*P to the left-hand side of the equation. A nil scalar
+// stands for one and a nil point for the standard base point.
+func (e *Equation) Left(s abstract.Scalar, P abstract.Point) *Equation {
+	if s == nil {
+		s = e.g.Scalar().One()
+	}
+	e.scalars = append(e.scalars, s.Clone())
+	e.points = append(e.points, P)
+	return e
+}
+
+// Right adds the term s*P to the right-hand side of the equation. A nil
+// scalar stands for one and a nil point for the standard base point.
+func (e *Equation) Right(s abstract.Scalar, P abstract.Point) *Equation {
+	if s == nil {
+		s = e.g.Scalar().One()
+	}
+	e.scalars = append(e.scalars, e.g.Scalar().Neg(s))
+	e.points = append(e.points, P)
+	return e
+}
+
+// Holds reports whether the equation holds.
+func (e *Equation) Holds() bool {
+	R, err := MultiMul(e.g, e.scalars, e.points)
+	return err == nil && R.Equal(e.g.Point().Null())
+}
+
+// BatchHolds reports whether all the equations, which must be over the same
+// group, hold. It combines them with random weights drawn from rand into a
+// single multi-exponentiation, so a false equation is only missed with
+// negligible probability.
+func BatchHolds(rand cipher.Stream, eqs ...*Equation) bool {
+	if len(eqs) == 0 {
+		return true
+	}
+	g := eqs[0].g
+	var scalars []abstract.Scalar
+	var points []abstract.Point
+	for _, e := range eqs {
+		w := g.Scalar().Pick(rand)
+		for i, s := range e.scalars {
+			scalars = append(scalars, g.Scalar().Mul(w, s))
+			points = append(points, e.points[i])
+		}
+	}
+	R, err := MultiMul(g, scalars, points)
+	return err == nil && R.Equal(g.Point().Null())
+}
@@ -0,0 +1,90 @@
+package group_test
+
+import (
+	"testing"
+
+	"github.com/dedis/crypto/abstract"
+	"github.com/dedis/crypto/ed25519"
+	"github.com/dedis/crypto/edwards"
+	"github.com/dedis/crypto/group"
+	"github.com/dedis/crypto/nist"
+	"github.com/dedis/crypto/random"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMultiMul(t *testing.T) {
+	suites := []abstract.Suite{
+		ed25519.NewAES128SHA256Ed25519(false),
+		edwards.NewAES128SHA256Ed25519(false),
+		nist.NewAES128SHA256P256(),
+	}
+	for _, suite := range suites {
+		n := 7
+		scalars := make([]abstract.Scalar, n)
+		points := make([]abstract.Point, n)
+		for i := range scalars {
+			scalars[i] = suite.Scalar().Pick(random.Stream)
+			if i > 0 {
+				// points[0] stands for the base point
+				points[i] = suite.Point().Mul(nil, suite.Scalar().Pick(random.Stream))
+			}
+		}
+		scalars[3].Zero()
+		scalars[4].SetInt64(1)
+		got, err := group.MultiMul(suite, scalars, points)
+		require.Nil(t, err)
+		want := suite.Point().Null()
+		for i := range scalars {
+			want.Add(want, suite.Point().Mul(points[i], scalars[i]))
+		}
+		assert.True(t, want.Equal(got), suite.String())
+
+		_, err = group.MultiMul(suite, scalars[1:], points)
+		assert.Error(t, err)
+	}
+}
+
+func TestEquation(t *testing.T) {
+	suite := edwards.NewAES128SHA256Ed25519(false)
+	a := suite.Scalar().Pick(random.Stream)
+	b := suite.Scalar().Pick(random.Stream)
+	c := suite.Scalar().Pick(random.Stream)
+	X := suite.Point().Mul(nil, suite.Scalar().Pick(random.Stream))
+	H := suite.Point().Mul(nil, suite.Scalar().Pick(random.Stream))
+	// D = aG + bX - cH
+	D := suite.Point().Add(suite.Point().Mul(nil, a), suite.Point().Mul(X, b))
+	D.Sub(D, suite.Point().Mul(H, c))
+
+	good := group.NewEquation(suite).Left(a, nil).Left(b, X).Right(c, H).Right(nil, D)
+	bad := group.NewEquation(suite).Left(a, nil).Left(b, X).Right(c, H).Right(nil, X)
+	assert.True(t, good.Holds())
+	assert.False(t, bad.Holds())
+	assert.True(t, group.BatchHolds(random.Stream, good, good))
+	assert.False(t, group.BatchHolds(random.Stream, good, bad))
+	assert.True(t, group.BatchHolds(random.Stream))
+}
+
+func BenchmarkMultiMul(b *testing.B) {
+	suite := edwards.NewAES128SHA256Ed25519(false)
+	n := 64
+	scalars := make([]abstract.Scalar, n)
+	points := make([]abstract.Point, n)
+	for i := range scalars {
+		scalars[i] = suite.Scalar().Pick(random.Stream)
+		points[i] = suite.Point().Mul(nil, suite.Scalar().Pick(random.Stream))
+	}
+	b.Run("Naive", func(b *testing.B) {
+		for k := 0; k < b.N; k++ {
+			acc := suite.Point().Null()
+			for i := range scalars {
+				acc.Add(acc, suite.Point().Mul(points[i], scalars[i]))
+			}
+		}
+	})
+	b.Run("Straus", func(b *testing.B) {
+		for k := 0; k < b.N; k++ {
+			group.MultiMul(suite, scalars, points)
+		}
+	})
+}
@@ -0,0 +1,82 @@
+package group
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/dedis/crypto/abstract"
+)
+
+// ErrIdentity is returned by Policy.Check for the neutral element.
+var ErrIdentity = errors.New("neutral element not allowed")
+
+// ErrSmallOrder is returned by Policy.Check for a point of small order other
+// than the neutral element.
+var ErrSmallOrder = errors.New("small-order point not allowed")
+
+// Policy controls which points a protocol accepts in public keys, shares and
+// proof commitments. The neutral element and the points of small order, i.e.,
+// the points P with hP = 0 for the group's cofactor h, make many protocols
+// insecure, e.g., a public key 0 lets anyone sign and small-order shares leak
+// information in cofactor groups. Some protocols however use them on purpose,
+// e.g., the neutral element as commitment to a zero coefficient.
+type Policy struct {
+	Cofactor        int64 // Cofactor of the group, 1 for prime-order groups
+	AllowIdentity   bool  // Accept the neutral element
+	AllowSmallOrder bool  // Accept small-order points other than the neutral element
+}
+
+// DefaultPolicy returns the policy for group g that rejects the neutral
+// element and all small-order points.
+func DefaultPolicy(g abstract.Group) *Policy {
+	return &Policy{Cofactor: Cofactor(g)}
+}
+
+// LenientPolicy returns the policy for group g that accepts every point.
+func LenientPolicy(g abstract.Group) *Policy {
+	return &Policy{Cofactor: Cofactor(g), AllowIdentity: true, AllowSmallOrder: true}
+}
+
+// Cofactor returns the cofactor of the group g: 8 for the groups of
+// Curve25519 and 1 for the prime-order groups of the other suites.
+func Cofactor(g abstract.Group) int64 {
+	if strings.Contains(g.String(), "25519") {
+		return 8
+	}
+	return 1
+}
+
+// Check checks the points of group g against the policy. It returns
+// ErrIdentity or ErrSmallOrder for the first point that is not accepted.
+func (p *Policy) Check(g abstract.Group, points ...abstract.Point) error {
+	null := g.Point().Null()
+	var h abstract.Scalar
+	if p.Cofactor > 1 {
+		h = g.Scalar().SetInt64(p.Cofactor)
+	}
+	for _, P := range points {
+		if P.Equal(null) {
+			if !p.AllowIdentity {
+				return ErrIdentity
+			}
+			continue
+		}
+		if h != nil && !p.AllowSmallOrder && g.Point().Mul(P, h).Equal(null) {
+			return ErrSmallOrder
+		}
+	}
+	return nil
+}
+
+// InSubgroup reports whether the point P of group g lies in the prime-order
+// subgroup, i.e., whether lP = 0 for the order l of the group's base point.
+// Every point does in prime-order groups; in cofactor groups the points with
+// a small-order component do not, even if they are not of small order.
+func InSubgroup(g abstract.Group, P abstract.Point) bool {
+	if Cofactor(g) == 1 {
+		return true
+	}
+	// lP = (l-1)P + P, where l-1 is the scalar -1
+	lP := g.Point().Mul(P, g.Scalar().SetInt64(-1))
+	return lP.Add(lP, P).Equal(g.Point().Null())
+}
@@ -0,0 +1,55 @@
+package group_test
+
+import (
+	"testing"
+
+	"github.com/dedis/crypto/ed25519"
+	"github.com/dedis/crypto/group"
+	"github.com/dedis/crypto/nist"
+	"github.com/dedis/crypto/random"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPolicy(t *testing.T) {
+	suite := ed25519.NewAES128SHA256Ed25519(false)
+	assert.Equal(t, int64(8), group.Cofactor(suite))
+	assert.Equal(t, int64(1), group.Cofactor(nist.NewAES128SHA256P256()))
+
+	P := suite.Point().Mul(nil, suite.Scalar().Pick(random.Stream))
+	null := suite.Point().Null()
+	// (0, -1) has order 2
+	enc := make([]byte, 32)
+	for i := range enc {
+		enc[i] = 0xff
+	}
+	enc[0], enc[31] = 0xec, 0x7f
+	small := suite.Point()
+	require.Nil(t, small.UnmarshalBinary(enc))
+	require.False(t, small.Equal(null))
+
+	strict := group.DefaultPolicy(suite)
+	assert.Nil(t, strict.Check(suite, P))
+	assert.Equal(t, group.ErrIdentity, strict.Check(suite, P, null))
+	assert.Equal(t, group.ErrSmallOrder, strict.Check(suite, small, P))
+	// A point with a small-order component is not of small order
+	assert.Nil(t, strict.Check(suite, suite.Point().Add(P, small)))
+
+	// but it is not in the prime-order subgroup
+	assert.True(t, group.InSubgroup(suite, P))
+	assert.True(t, group.InSubgroup(suite, null))
+	assert.False(t, group.InSubgroup(suite, small))
+	assert.False(t, group.InSubgroup(suite, suite.Point().Add(P, small)))
+
+	lenient := group.LenientPolicy(suite)
+	assert.Nil(t, lenient.Check(suite, P, null, small))
+
+	custom := &group.Policy{Cofactor: 8, AllowIdentity: true}
+	assert.Nil(t, custom.Check(suite, null))
+	assert.Equal(t, group.ErrSmallOrder, custom.Check(suite, small))
+
+	p256 := nist.NewAES128SHA256P256()
+	assert.Equal(t, group.ErrIdentity, group.DefaultPolicy(p256).Check(p256, p256.Point().Null()))
+	assert.Nil(t, group.DefaultPolicy(p256).Check(p256, p256.Point().Base()))
+	assert.True(t, group.InSubgroup(p256, p256.Point().Base()))
+}
@@ -0,0 +1,63 @@
+package group
+
+import (
+	"bytes"
+	"errors"
+
+	"github.com/dedis/crypto/abstract"
+)
+
+// Strictness selects how strictly encoded group elements are checked when
+// decoding them. Systems that must agree on the acceptance of every encoding,
+// e.g. for consensus, should decode with Strict.
+type Strictness int
+
+const (
+	// Lenient accepts every encoding the group's decoder accepts.
+	Lenient Strictness = iota
+	// Strict additionally requires the encoding to be the canonical one,
+	// i.e., the one MarshalBinary produces for the decoded value. This
+	// rejects scalars not reduced modulo the group order as well as
+	// alternative encodings of the same point.
+	Strict
+)
+
+// ErrNonCanonical is returned by UnmarshalScalar and UnmarshalPoint in Strict
+// mode for encodings that decode but are not canonical.
+var ErrNonCanonical = errors.New("non-canonical encoding")
+
+// UnmarshalScalar decodes buf into s according to mode.
+func UnmarshalScalar(s abstract.Scalar, buf []byte, mode Strictness) error {
+	if err := s.UnmarshalBinary(buf); err != nil {
+		return err
+	}
+	if mode == Lenient {
+		return nil
+	}
+	enc, err := s.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(enc, buf) {
+		return ErrNonCanonical
+	}
+	return nil
+}
+
+// UnmarshalPoint decodes buf into p according to mode.
+func UnmarshalPoint(p abstract.Point, buf []byte, mode Strictness) error {
+	if err := p.UnmarshalBinary(buf); err != nil {
+		return err
+	}
+	if mode == Lenient {
+		return nil
+	}
+	enc, err := p.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(enc, buf) {
+		return ErrNonCanonical
+	}
+	return nil
+}
@@ -0,0 +1,180 @@
+// Package handshake implements a secret handshake, i.e., a private mutual
+// authentication protocol in which two parties learn whether they both hold
+// credentials issued by the same authority, without revealing anything about
+// their affiliation to a party that does not.
+//
+// The construction follows the CA-oblivious approach of Castelluccia, Jarecki
+// and Tsudik. A credential for the pseudonym id is a Schnorr signature (R, s)
+// with s = r + xH(id, R) under the authority's key X = xG. Anybody can compute
+// the credential's public key P = R + H(id, R)X = sG with respect to a given
+// authority, but only the credential holder knows s. Two parties exchange
+// (id, R) together with ephemeral Diffie-Hellman keys and derive the session
+// key from sP' and the ephemeral keys. The keys agree if and only if both
+// credentials were issued by the same authority; otherwise the key
+// confirmation fails and neither party learns anything else. Since (id, R)
+// look random, credentials should be used once to prevent linking sessions.
+package handshake
+
+import (
+	"crypto/cipher"
+	"crypto/hmac"
+	"encoding/binary"
+	"errors"
+
+	"github.com/dedis/crypto/abstract"
+)
+
+// Some error definitions
+var errorHandshake = errors.New("secret handshake failed")
+var errorState = errors.New("protocol step called out of order")
+
+// Authority issues credentials to its members.
+type Authority struct {
+	suite  abstract.Suite
+	secret abstract.Scalar
+	Public abstract.Point
+}
+
+// NewAuthority creates an authority with a fresh key pair.
+func NewAuthority(suite abstract.Suite, rand cipher.Stream) *Authority {
+	x := suite.Scalar().Pick(rand)
+	return &Authority{suite, x, suite.Point().Mul(nil, x)}
+}
+
+// Credential is a membership credential bound to a pseudonym.
+type Credential struct {
+	ID []byte          // Pseudonym of the member
+	R  abstract.Point  // Commitment of the authority's signature
+	S  abstract.Scalar // Secret response of the authority's signature
+}
+
+// Issue creates a credential for the given pseudonym.
+func (a *Authority) Issue(id []byte, rand cipher.Stream) (*Credential, error) {
+	r := a.suite.Scalar().Pick(rand)
+	R := a.suite.Point().Mul(nil, r)
+	e, err := challenge(a.suite, id, R)
+	if err != nil {
+		return nil, err
+	}
+	s := a.suite.Scalar().Mul(a.secret, e)
+	s.Add(s, r)
+	return &Credential{id, R, s}, nil
+}
+
+// Hello is the message each party sends to open the handshake.
+type Hello struct {
+	ID        []byte         // Pseudonym of the sender
+	R         abstract.Point // Credential commitment of the sender
+	Ephemeral abstract.Point // Ephemeral Diffie-Hellman key of the sender
+}
+
+// Confirm is the key confirmation each party sends once it received the
+// other party's Hello.
+type Confirm struct {
+	MAC []byte
+}
+
+// Handshake holds the state of one party during a secret handshake.
+type Handshake struct {
+	suite     abstract.Suite
+	X         abstract.Point // Public key of the own authority
+	cred      *Credential
+	initiator bool
+	eph       abstract.Scalar
+	hello     *Hello
+	keys      [3][]byte // session key, own MAC key, peer MAC key
+	peerMAC   []byte
+}
+
+// New starts a handshake for the holder of cred, issued by the authority with
+// public key X. Exactly one of the two parties must be the initiator. It
+// returns the handshake state and the Hello message to send.
+func New(suite abstract.Suite, X abstract.Point, cred *Credential, initiator bool, rand cipher.Stream) (*Handshake, *Hello) {
+	eph := suite.Scalar().Pick(rand)
+	hello := &Hello{cred.ID, cred.R, suite.Point().Mul(nil, eph)}
+	return &Handshake{suite: suite, X: X, cred: cred, initiator: initiator, eph: eph, hello: hello}, hello
+}
+
+// Finish processes the peer's Hello and returns the own key confirmation.
+func (h *Handshake) Finish(peer *Hello) (*Confirm, error) {
+	// Peer's credential key with respect to our authority
+	e, err := challenge(h.suite, peer.ID, peer.R)
+	if err != nil {
+		return nil, err
+	}
+	P := h.suite.Point().Add(peer.R, h.suite.Point().Mul(h.X, e))
+	dh1 := h.suite.Point().Mul(P, h.cred.S)
+	dh2 := h.suite.Point().Mul(peer.Ephemeral, h.eph)
+
+	first, second := h.hello, peer
+	if !h.initiator {
+		first, second = peer, h.hello
+	}
+	// Length-prefix every field so that the transcript encodes the messages
+	// unambiguously
+	var tr []byte
+	for _, m := range []*Hello{first, second} {
+		R, err := m.R.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		E, err := m.Ephemeral.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range [][]byte{m.ID, R, E} {
+			tr = binary.BigEndian.AppendUint32(tr, uint32(len(f)))
+			tr = append(tr, f...)
+		}
+	}
+
+	hash := h.suite.Hash()
+	hash.Write([]byte("secret-handshake"))
+	hash.Write(tr)
+	for _, D := range []abstract.Point{dh1, dh2} {
+		if _, err := D.MarshalTo(hash); err != nil {
+			return nil, err
+		}
+	}
+	c := h.suite.Cipher(hash.Sum(nil))
+	var k [3][]byte
+	for i := range k {
+		k[i] = make([]byte, c.KeySize())
+		c.Partial(k[i], nil, nil)
+	}
+	h.keys[0] = k[0]
+	if h.initiator {
+		h.keys[1], h.keys[2] = k[1], k[2]
+	} else {
+		h.keys[1], h.keys[2] = k[2], k[1]
+	}
+	h.peerMAC = mac(h.suite, h.keys[2], tr)
+	return &Confirm{mac(h.suite, h.keys[1], tr)}, nil
+}
+
+// Verify checks the peer's key confirmation. On success both parties are
+// members of the same authority and share the returned session key.
+func (h *Handshake) Verify(c *Confirm) ([]byte, error) {
+	if h.peerMAC == nil {
+		return nil, errorState
+	}
+	if !hmac.Equal(h.peerMAC, c.MAC) {
+		return nil, errorHandshake
+	}
+	return h.keys[0], nil
+}
+
+func challenge(suite abstract.Suite, id []byte, R abstract.Point) (abstract.Scalar, error) {
+	h := suite.Hash()
+	h.Write(id)
+	if _, err := R.MarshalTo(h); err != nil {
+		return nil, err
+	}
+	return suite.Scalar().Pick(suite.Cipher(h.Sum(nil))), nil
+}
+
+func mac(suite abstract.Suite, key, data []byte) []byte {
+	m := hmac.New(suite.Hash, key)
+	m.Write(data)
+	return m.Sum(nil)
+}
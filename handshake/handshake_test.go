@@ -0,0 +1,63 @@
+package handshake
+
+import (
+	"testing"
+
+	"github.com/dedis/crypto/edwards"
+	"github.com/dedis/crypto/random"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var suite = edwards.NewAES128SHA256Ed25519(false)
+
+func run(t *testing.T, ca, cb *Authority) ([]byte, []byte, error, error) {
+	credA, err := ca.Issue([]byte("alice"), random.Stream)
+	require.Nil(t, err)
+	credB, err := cb.Issue([]byte("bob"), random.Stream)
+	require.Nil(t, err)
+
+	a, helloA := New(suite, ca.Public, credA, true, random.Stream)
+	b, helloB := New(suite, cb.Public, credB, false, random.Stream)
+	confA, err := a.Finish(helloB)
+	require.Nil(t, err)
+	confB, err := b.Finish(helloA)
+	require.Nil(t, err)
+	keyA, errA := a.Verify(confB)
+	keyB, errB := b.Verify(confA)
+	return keyA, keyB, errA, errB
+}
+
+func TestHandshakeSameAuthority(t *testing.T) {
+	ca := NewAuthority(suite, random.Stream)
+	keyA, keyB, errA, errB := run(t, ca, ca)
+	require.Nil(t, errA)
+	require.Nil(t, errB)
+	assert.Equal(t, keyA, keyB)
+}
+
+func TestHandshakeDifferentAuthority(t *testing.T) {
+	ca := NewAuthority(suite, random.Stream)
+	cb := NewAuthority(suite, random.Stream)
+	_, _, errA, errB := run(t, ca, cb)
+	assert.Error(t, errA)
+	assert.Error(t, errB)
+}
+
+func TestHandshakeForgedCredential(t *testing.T) {
+	ca := NewAuthority(suite, random.Stream)
+	credA, err := ca.Issue([]byte("alice"), random.Stream)
+	require.Nil(t, err)
+	forged := &Credential{[]byte("mallory"), credA.R, credA.S}
+	credB, err := ca.Issue([]byte("bob"), random.Stream)
+	require.Nil(t, err)
+
+	m, helloM := New(suite, ca.Public, forged, true, random.Stream)
+	b, helloB := New(suite, ca.Public, credB, false, random.Stream)
+	confM, err := m.Finish(helloB)
+	require.Nil(t, err)
+	_, err = b.Finish(helloM)
+	require.Nil(t, err)
+	_, err = b.Verify(confM)
+	assert.Error(t, err)
+}
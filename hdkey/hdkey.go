@@ -0,0 +1,168 @@
+// Package hdkey implements hierarchical deterministic key derivation in the
+// style of BIP32, generalized to any Suite. A master key derived from a seed
+// forms the root of a tree of keys, in which every key carries a chain code
+// used to derive its children. Hardened children (index >= Hardened) can only
+// be derived from the parent private key, whereas non-hardened children can
+// also be derived from the parent public key, e.g., to let a server generate
+// fresh receiving keys without access to any private key. Keys are addressed
+// by paths such as "m/7'/1/42", where ' or h marks a hardened index.
+package hdkey
+
+import (
+	"crypto/hmac"
+	"encoding/binary"
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/dedis/crypto/abstract"
+)
+
+// Hardened is the first index of hardened children.
+const Hardened uint32 = 1 << 31
+
+// ChainCodeSize is the length of chain codes in bytes.
+const ChainCodeSize = 32
+
+// Some error definitions
+var errorPath = errors.New("invalid derivation path")
+var errorHardened = errors.New("cannot derive hardened child from public key")
+
+// PrivateKey is an extended private key.
+type PrivateKey struct {
+	Key       abstract.Scalar
+	ChainCode []byte
+	Depth     int
+}
+
+// PublicKey is an extended public key.
+type PublicKey struct {
+	Key       abstract.Point
+	ChainCode []byte
+	Depth     int
+}
+
+// NewMaster derives the master key from a seed.
+func NewMaster(suite abstract.Suite, seed []byte) *PrivateKey {
+	k, c := expand(suite, []byte("hdkey seed"), seed)
+	return &PrivateKey{Key: k, ChainCode: c}
+}
+
+// Public returns the extended public key of the extended private key.
+func (k *PrivateKey) Public(suite abstract.Suite) *PublicKey {
+	return &PublicKey{
+		Key:       suite.Point().Mul(nil, k.Key),
+		ChainCode: k.ChainCode,
+		Depth:     k.Depth,
+	}
+}
+
+// Child derives the child private key with the given index.
+func (k *PrivateKey) Child(suite abstract.Suite, index uint32) (*PrivateKey, error) {
+	var data []byte
+	var err error
+	if index >= Hardened {
+		data, err = k.Key.MarshalBinary()
+		data = append([]byte{0}, data...)
+	} else {
+		data, err = suite.Point().Mul(nil, k.Key).MarshalBinary()
+	}
+	if err != nil {
+		return nil, err
+	}
+	t, c := expand(suite, k.ChainCode, appendIndex(data, index))
+	return &PrivateKey{
+		Key:       suite.Scalar().Add(t, k.Key),
+		ChainCode: c,
+		Depth:     k.Depth + 1,
+	}, nil
+}
+
+// Derive derives the private key at the given path below the key.
+func (k *PrivateKey) Derive(suite abstract.Suite, path string) (*PrivateKey, error) {
+	indices, err := ParsePath(path)
+	if err != nil {
+		return nil, err
+	}
+	for _, i := range indices {
+		if k, err = k.Child(suite, i); err != nil {
+			return nil, err
+		}
+	}
+	return k, nil
+}
+
+// Child derives the non-hardened child public key with the given index.
+func (k *PublicKey) Child(suite abstract.Suite, index uint32) (*PublicKey, error) {
+	if index >= Hardened {
+		return nil, errorHardened
+	}
+	data, err := k.Key.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	t, c := expand(suite, k.ChainCode, appendIndex(data, index))
+	return &PublicKey{
+		Key:       suite.Point().Add(suite.Point().Mul(nil, t), k.Key),
+		ChainCode: c,
+		Depth:     k.Depth + 1,
+	}, nil
+}
+
+// Derive derives the public key at the given path below the key. The path
+// must not contain hardened indices.
+func (k *PublicKey) Derive(suite abstract.Suite, path string) (*PublicKey, error) {
+	indices, err := ParsePath(path)
+	if err != nil {
+		return nil, err
+	}
+	for _, i := range indices {
+		if k, err = k.Child(suite, i); err != nil {
+			return nil, err
+		}
+	}
+	return k, nil
+}
+
+// ParsePath parses a derivation path of the form "m/1'/2h/3" into the list of
+// child indices. The leading "m" is optional.
+func ParsePath(path string) ([]uint32, error) {
+	if path == "" || path == "m" {
+		return nil, nil
+	}
+	parts := strings.Split(strings.TrimPrefix(path, "m/"), "/")
+	indices := make([]uint32, len(parts))
+	for j, p := range parts {
+		hardened := strings.HasSuffix(p, "'") || strings.HasSuffix(p, "h")
+		if hardened {
+			p = p[:len(p)-1]
+		}
+		i, err := strconv.ParseUint(p, 10, 32)
+		if err != nil || uint32(i) >= Hardened {
+			return nil, errorPath
+		}
+		indices[j] = uint32(i)
+		if hardened {
+			indices[j] += Hardened
+		}
+	}
+	return indices, nil
+}
+
+func appendIndex(data []byte, index uint32) []byte {
+	var b [4]byte
+	binary.BigEndian.PutUint32(b[:], index)
+	return append(data, b[:]...)
+}
+
+// expand computes HMAC(key, data) and stretches it into a scalar and a chain
+// code with the suite's cipher.
+func expand(suite abstract.Suite, key, data []byte) (abstract.Scalar, []byte) {
+	mac := hmac.New(suite.Hash, key)
+	mac.Write(data)
+	stream := suite.Cipher(mac.Sum(nil))
+	s := suite.Scalar().Pick(stream)
+	c := make([]byte, ChainCodeSize)
+	stream.XORKeyStream(c, c)
+	return s, c
+}
@@ -0,0 +1,54 @@
+package hdkey
+
+import (
+	"testing"
+
+	"github.com/dedis/crypto/edwards"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var suite = edwards.NewAES128SHA256Ed25519(false)
+
+func TestParsePath(t *testing.T) {
+	indices, err := ParsePath("m/0'/1/2h/3")
+	require.Nil(t, err)
+	assert.Equal(t, []uint32{Hardened, 1, Hardened + 2, 3}, indices)
+
+	indices, err = ParsePath("m")
+	require.Nil(t, err)
+	assert.Empty(t, indices)
+
+	for _, p := range []string{"m/", "m/x", "m/1//2", "m/2147483648", "m/-1"} {
+		_, err = ParsePath(p)
+		assert.Equal(t, errorPath, err, p)
+	}
+}
+
+func TestDerive(t *testing.T) {
+	master := NewMaster(suite, []byte("seed"))
+	assert.True(t, master.Key.Equal(NewMaster(suite, []byte("seed")).Key))
+	assert.False(t, master.Key.Equal(NewMaster(suite, []byte("other")).Key))
+
+	k, err := master.Derive(suite, "m/7'/1/42")
+	require.Nil(t, err)
+	assert.Equal(t, 3, k.Depth)
+
+	// Non-hardened children can be derived from the public key
+	parent, err := master.Derive(suite, "m/7'")
+	require.Nil(t, err)
+	pub, err := parent.Public(suite).Derive(suite, "1/42")
+	require.Nil(t, err)
+	assert.True(t, pub.Key.Equal(k.Public(suite).Key))
+	assert.Equal(t, pub.ChainCode, k.ChainCode)
+
+	_, err = master.Public(suite).Derive(suite, "m/7'")
+	assert.Equal(t, errorHardened, err)
+
+	// Hardened and non-hardened children differ
+	h, err := master.Child(suite, Hardened+1)
+	require.Nil(t, err)
+	n, err := master.Child(suite, 1)
+	require.Nil(t, err)
+	assert.False(t, h.Key.Equal(n.Key))
+}
@@ -33,7 +33,6 @@ func Abs(x int) int {
 //	-1 if x <  0
 //	 0 if x == 0
 //	+1 if x >  0
-//
 func Sign(x int) int {
 	if x < 0 {
 		return -1
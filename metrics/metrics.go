@@ -0,0 +1,70 @@
+// Package metrics provides instrumentation hooks for the share, proof and pvss
+// packages. By default nothing is recorded; operators install a Recorder with
+// SetRecorder to forward operation counts, durations and verification failures
+// to a monitoring system such as Prometheus or OpenTelemetry.
+//
+// Operations are named after the package and function that performed them,
+// e.g. "pvss.EncShares" or "proof.DLEQProof.Verify". A verification failure is
+// reported as a completed verification operation with a non-nil error.
+package metrics
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Recorder receives instrumentation events. Implementations must be safe for
+// concurrent use.
+type Recorder interface {
+	// Observe is called once an operation has completed with its duration
+	// and the error it returned, nil on success.
+	Observe(op string, d time.Duration, err error)
+}
+
+type holder struct {
+	r Recorder
+}
+
+var recorder atomic.Value
+
+func init() {
+	recorder.Store(holder{})
+}
+
+// SetRecorder installs r as the global recorder. A nil r disables
+// instrumentation again.
+func SetRecorder(r Recorder) {
+	recorder.Store(holder{r})
+}
+
+// Span measures a single operation.
+type Span struct {
+	op    string
+	r     Recorder
+	start time.Time
+}
+
+// Start begins measuring the operation op. The returned span is meant to be
+// ended in a defer statement:
+//
+//	defer metrics.Start("pkg.Op").End(&err)
+func Start(op string) Span {
+	r := recorder.Load().(holder).r
+	if r == nil {
+		return Span{}
+	}
+	return Span{op, r, time.Now()}
+}
+
+// End reports the operation to the recorder that was installed when the span
+// started. err points to the operation's result and may be nil.
+func (s Span) End(err *error) {
+	if s.r == nil {
+		return
+	}
+	var e error
+	if err != nil {
+		e = *err
+	}
+	s.r.Observe(s.op, time.Since(s.start), e)
+}
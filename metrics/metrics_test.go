@@ -0,0 +1,59 @@
+package metrics_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dedis/crypto/abstract"
+	"github.com/dedis/crypto/edwards"
+	"github.com/dedis/crypto/metrics"
+	"github.com/dedis/crypto/pvss"
+	"github.com/dedis/crypto/random"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type counter struct {
+	sync.Mutex
+	calls    map[string]int
+	failures map[string]int
+}
+
+func (c *counter) Observe(op string, d time.Duration, err error) {
+	c.Lock()
+	defer c.Unlock()
+	c.calls[op]++
+	if err != nil {
+		c.failures[op]++
+	}
+}
+
+func TestRecorder(t *testing.T) {
+	suite := edwards.NewAES128SHA256Ed25519(false)
+	c := &counter{calls: make(map[string]int), failures: make(map[string]int)}
+	metrics.SetRecorder(c)
+	defer metrics.SetRecorder(nil)
+
+	n := 3
+	H := suite.Point().Base()
+	X := make([]abstract.Point, n)
+	for i := range X {
+		X[i] = suite.Point().Mul(nil, suite.Scalar().Pick(random.Stream))
+	}
+	enc, poly, err := pvss.EncShares(suite, H, X, suite.Scalar().Pick(random.Stream), 2)
+	require.Nil(t, err)
+	assert.Equal(t, 1, c.calls["pvss.EncShares"])
+	assert.Equal(t, n, c.calls["proof.NewDLEQProof"])
+
+	sH := poly.Eval(0).V
+	require.Nil(t, pvss.VerifyEncShare(suite, H, X[0], sH, enc[0]))
+	require.NotNil(t, pvss.VerifyEncShare(suite, H, X[1], sH, enc[0]))
+	assert.Equal(t, 2, c.calls["pvss.VerifyEncShare"])
+	assert.Equal(t, 1, c.failures["pvss.VerifyEncShare"])
+	assert.Equal(t, 1, c.failures["proof.DLEQProof.VerifyTagged"])
+
+	metrics.SetRecorder(nil)
+	require.Nil(t, pvss.VerifyEncShare(suite, H, X[0], sH, enc[0]))
+	assert.Equal(t, 2, c.calls["pvss.VerifyEncShare"])
+}
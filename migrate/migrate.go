@@ -0,0 +1,247 @@
+// Package migrate moves a Shamir-shared secret from the group of one suite to
+// the group of another suite of the same prime order, e.g., from Ed25519 to
+// Ristretto255 or between two implementations of the same curve, without
+// reconstructing the secret. Every trustee converts its share to the new group
+// and publishes its new verification key together with a cross-group proof
+// that it has the same discrete logarithm as its old verification key:
+//
+//	trustee: tr, newShare := NewTransfer(from, to, oldShare)
+//	anyone:  m := NewMigration(from, to, oldPubPoly, n)
+//	         m.Add(tr) for the transfers of the trustees
+//	         newPubPoly := m.PubPoly()  // once t transfers are valid
+//
+// The new public commitment polynomial commits, in the new group, to the same
+// polynomial as the old one, so the new collective public key is xG' for the
+// old collective secret x. Shamir shares only carry over between groups of the
+// same order; migrating to a group of a different order requires a fresh
+// sharing and is refused.
+package migrate
+
+import (
+	"errors"
+
+	"github.com/dedis/crypto/abstract"
+	"github.com/dedis/crypto/hash"
+	"github.com/dedis/crypto/nist"
+	"github.com/dedis/crypto/random"
+	"github.com/dedis/crypto/share"
+)
+
+// Some error definitions
+var errorOrder = errors.New("groups of different order")
+var errorScalar = errors.New("scalar not convertible between groups")
+var errorProof = errors.New("invalid cross-group proof")
+var errorIndex = errors.New("share index out of range")
+var errorDuplicate = errors.New("duplicate transfer")
+var errorTooFew = errors.New("not enough valid transfers")
+
+// order returns the order of the group g, if its scalars expose it.
+func order(g abstract.Group) (*nist.Int, bool) {
+	s, ok := g.Scalar().(*nist.Int)
+	return s, ok && s.M != nil
+}
+
+// SameOrder reports whether the groups from and to have the same prime order,
+// i.e., whether their scalars can be converted into each other.
+func SameOrder(from, to abstract.Group) bool {
+	a, ok := order(from)
+	if !ok {
+		return false
+	}
+	b, ok := order(to)
+	return ok && a.M.Cmp(b.M) == 0 && from.PrimeOrder() && to.PrimeOrder()
+}
+
+// ConvertScalar returns the scalar of the group to with the same value as s.
+// Both groups must have the same order.
+func ConvertScalar(to abstract.Group, s abstract.Scalar) (abstract.Scalar, error) {
+	src, ok := s.(*nist.Int)
+	if !ok {
+		return nil, errorScalar
+	}
+	dst, ok := order(to)
+	if !ok || dst.M.Cmp(src.M) != 0 {
+		return nil, errorScalar
+	}
+	dst.V.Set(&src.V)
+	return dst, nil
+}
+
+// Proof is a non-interactive proof that log_G(X) == log_G'(Y) for the point X
+// of one group and the point Y of another group of the same order, where G
+// and G' are the groups' standard base points. It is a Chaum-Pedersen proof
+// whose challenge and response are shared by both groups.
+type Proof struct {
+	C  abstract.Scalar // Challenge, a scalar of the first group
+	R  abstract.Scalar // Response, a scalar of the first group
+	VG abstract.Point  // Commitment in the first group
+	VH abstract.Point  // Commitment in the second group
+}
+
+// NewProof proves that X = xG in the group of from and Y = xG' in the group
+// of to have the same discrete logarithm x, a scalar of from. It returns the
+// proof, X and Y.
+func NewProof(from, to abstract.Suite, x abstract.Scalar) (*Proof, abstract.Point, abstract.Point, error) {
+	if !SameOrder(from, to) {
+		return nil, nil, nil, errorOrder
+	}
+	x2, err := ConvertScalar(to, x)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	v := from.Scalar().Pick(random.Stream)
+	v2, err := ConvertScalar(to, v)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	X := from.Point().Mul(nil, x)
+	Y := to.Point().Mul(nil, x2)
+	p := &Proof{VG: from.Point().Mul(nil, v), VH: to.Point().Mul(nil, v2)}
+	if p.C, err = p.challenge(from, X, Y); err != nil {
+		return nil, nil, nil, err
+	}
+	// r = v - cx
+	p.R = from.Scalar().Sub(v, from.Scalar().Mul(p.C, x))
+	return p, X, Y, nil
+}
+
+func (p *Proof) challenge(from abstract.Suite, X, Y abstract.Point) (abstract.Scalar, error) {
+	h := from.Hash()
+	h.Write([]byte("migrate"))
+	cb, err := hash.Structures(h, X, Y, p.VG, p.VH)
+	if err != nil {
+		return nil, err
+	}
+	return from.Scalar().Pick(from.Cipher(cb)), nil
+}
+
+// Verify checks the proof that the point X of from and the point Y of to have
+// the same discrete logarithm.
+func (p *Proof) Verify(from, to abstract.Suite, X, Y abstract.Point) error {
+	if !SameOrder(from, to) {
+		return errorOrder
+	}
+	c, err := p.challenge(from, X, Y)
+	if err != nil {
+		return err
+	}
+	if !c.Equal(p.C) {
+		return errorProof
+	}
+	c2, err := ConvertScalar(to, p.C)
+	if err != nil {
+		return err
+	}
+	r2, err := ConvertScalar(to, p.R)
+	if err != nil {
+		return err
+	}
+	// vG == rG + cX and vG' == rG' + cY
+	VG := from.Point().Add(from.Point().Mul(nil, p.R), from.Point().Mul(X, p.C))
+	VH := to.Point().Add(to.Point().Mul(nil, r2), to.Point().Mul(Y, c2))
+	if !VG.Equal(p.VG) || !VH.Equal(p.VH) {
+		return errorProof
+	}
+	return nil
+}
+
+// Transfer is a trustee's public contribution to a migration: the
+// verification key of its share in the new group and the proof that it
+// belongs to the same share as the old verification key.
+type Transfer struct {
+	I     int            // Index of the share
+	Y     abstract.Point // Verification key in the new group
+	Proof *Proof         // Proof of equality with the old verification key
+}
+
+// NewTransfer converts the trustee's share of the group of from into a share
+// of the group of to and returns it together with the public transfer.
+func NewTransfer(from, to abstract.Suite, priShare *share.PriShare) (*Transfer, *share.PriShare, error) {
+	proof, _, Y, err := NewProof(from, to, priShare.V)
+	if err != nil {
+		return nil, nil, err
+	}
+	v, err := ConvertScalar(to, priShare.V)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &Transfer{priShare.I, Y, proof}, &share.PriShare{I: priShare.I, V: v}, nil
+}
+
+// Migration collects and verifies the transfers of the trustees of a sharing
+// with public commitment polynomial pubPoly in the group of from.
+type Migration struct {
+	from, to  abstract.Suite
+	pubPoly   *share.PubPoly
+	n         int
+	transfers map[int]*Transfer
+}
+
+// NewMigration starts the migration of the sharing among n trustees with
+// public commitment polynomial pubPoly, committed with respect to the
+// standard base point of from, to the group of to.
+func NewMigration(from, to abstract.Suite, pubPoly *share.PubPoly, n int) (*Migration, error) {
+	if !SameOrder(from, to) {
+		return nil, errorOrder
+	}
+	return &Migration{from, to, pubPoly, n, make(map[int]*Transfer)}, nil
+}
+
+// Add verifies the transfer against the trustee's old verification key and
+// keeps it if valid. It returns whether the new public commitment polynomial
+// can be computed.
+func (m *Migration) Add(tr *Transfer) (bool, error) {
+	if tr.I < 0 || tr.I >= m.n {
+		return false, errorIndex
+	}
+	if m.transfers[tr.I] != nil {
+		return false, errorDuplicate
+	}
+	if err := tr.Proof.Verify(m.from, m.to, m.pubPoly.Eval(tr.I).V, tr.Y); err != nil {
+		return false, err
+	}
+	m.transfers[tr.I] = tr
+	return m.Ready(), nil
+}
+
+// Ready reports whether a threshold of valid transfers is available.
+func (m *Migration) Ready() bool {
+	return len(m.transfers) >= m.pubPoly.Threshold()
+}
+
+// PubPoly returns the public commitment polynomial of the migrated sharing in
+// the new group. Its coefficients are interpolated in the exponent from a
+// threshold of the new verification keys; since each of them is proven equal
+// to an old one, it commits to the same polynomial as the old public
+// commitment polynomial.
+func (m *Migration) PubPoly() (*share.PubPoly, error) {
+	t := m.pubPoly.Threshold()
+	if !m.Ready() {
+		return nil, errorTooFew
+	}
+	var used []*Transfer
+	for i := 0; i < m.n && len(used) < t; i++ {
+		if tr := m.transfers[i]; tr != nil {
+			used = append(used, tr)
+		}
+	}
+	g := m.to
+	indices := make([]int, t)
+	for j, tr := range used {
+		indices[j] = tr.I
+	}
+	ip, err := share.NewInterpolator(g, indices, m.n)
+	if err != nil {
+		return nil, err
+	}
+	commits := make([]abstract.Point, t)
+	for k := range commits {
+		commits[k] = g.Point().Null()
+	}
+	for _, tr := range used {
+		for k, b := range ip.Basis(tr.I) {
+			commits[k].Add(commits[k], g.Point().Mul(tr.Y, b))
+		}
+	}
+	return share.NewPubPoly(g, nil, commits), nil
+}
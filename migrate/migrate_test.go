@@ -0,0 +1,75 @@
+package migrate
+
+import (
+	"testing"
+
+	"github.com/dedis/crypto/ed25519"
+	"github.com/dedis/crypto/edwards"
+	"github.com/dedis/crypto/nist"
+	"github.com/dedis/crypto/random"
+	"github.com/dedis/crypto/share"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var from = edwards.NewAES128SHA256Ed25519(false)
+var to = ed25519.NewAES128SHA256Ed25519(false)
+
+func TestProof(t *testing.T) {
+	x := from.Scalar().Pick(random.Stream)
+	p, X, Y, err := NewProof(from, to, x)
+	require.Nil(t, err)
+	require.Nil(t, p.Verify(from, to, X, Y))
+
+	x2, err := ConvertScalar(to, x)
+	require.Nil(t, err)
+	assert.True(t, to.Point().Mul(nil, x2).Equal(Y))
+
+	Y2 := to.Point().Add(Y, to.Point().Base())
+	assert.Equal(t, errorProof, p.Verify(from, to, X, Y2))
+
+	p256 := nist.NewAES128SHA256P256()
+	_, _, _, err = NewProof(from, p256, x)
+	assert.Equal(t, errorOrder, err)
+}
+
+func TestMigration(t *testing.T) {
+	n, th := 7, 4
+	secret := from.Scalar().Pick(random.Stream)
+	priPoly := share.NewPriPoly(from, th, secret, random.Stream)
+	pubPoly := priPoly.Commit(nil)
+
+	m, err := NewMigration(from, to, pubPoly, n)
+	require.Nil(t, err)
+	newShares := make([]*share.PriShare, 0, n)
+	for i, s := range priPoly.Shares(n) {
+		tr, ns, err := NewTransfer(from, to, s)
+		require.Nil(t, err)
+		if i == 1 {
+			// A transfer for a different share is rejected
+			bad := *tr
+			bad.I = 2
+			_, err := m.Add(&bad)
+			assert.Equal(t, errorProof, err)
+			continue
+		}
+		ready, err := m.Add(tr)
+		require.Nil(t, err)
+		assert.Equal(t, len(newShares)+1 >= th, ready)
+		_, err = m.Add(tr)
+		assert.Equal(t, errorDuplicate, err)
+		newShares = append(newShares, ns)
+	}
+
+	newPoly, err := m.PubPoly()
+	require.Nil(t, err)
+	secret2, err := ConvertScalar(to, secret)
+	require.Nil(t, err)
+	assert.True(t, newPoly.Commit().Equal(to.Point().Mul(nil, secret2)))
+	for _, s := range newShares {
+		assert.True(t, newPoly.Check(s))
+	}
+	recovered, err := share.RecoverSecret(to, newShares, th, n)
+	require.Nil(t, err)
+	assert.True(t, recovered.Equal(secret2))
+}
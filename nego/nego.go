@@ -150,7 +150,6 @@ func (s *suiteList) Swap(i, j int) {
 // This "real" content is typically located after the negotiation header
 // and encrypted with a symmetric key included in the entrypoint data,
 // which can be (but doesn't have to be) shared by many or all entrypoints.
-//
 type Writer struct {
 	suites  suiteList                     // Sorted list of ciphersuites used
 	simap   map[abstract.Suite]*suiteInfo // suiteInfo for each Suite
@@ -192,7 +191,6 @@ func (w *Writer) SetMaxLen(max int) {
 //
 // XXX if multiple entrypoints are improperly passed for the same keyholder,
 // bad things happen to security - we should harden the API against that.
-//
 func (w *Writer) Layout(suiteLevel map[abstract.Suite]int,
 	entrypoints []Entry,
 	rand cipher.Stream) (int, error) {
@@ -27,13 +27,13 @@ func (p *curvePoint) Equal(p2 abstract.Point) bool {
 
 	// Make sure both coordinates are normalized.
 	// Apparently Go's elliptic curve code doesn't always ensure this.
+	// Normalize copies: the coordinates may be shared, e.g. with the
+	// curve's base point, and Equal must be safe for concurrent use.
 	M := p.c.p.P
-	p.x.Mod(p.x, M)
-	p.y.Mod(p.y, M)
-	cp2.x.Mod(cp2.x, M)
-	cp2.y.Mod(cp2.y, M)
-
-	return p.x.Cmp(cp2.x) == 0 && p.y.Cmp(cp2.y) == 0
+	mod := func(v *big.Int) *big.Int {
+		return new(big.Int).Mod(v, M)
+	}
+	return mod(p.x).Cmp(mod(cp2.x)) == 0 && mod(p.y).Cmp(mod(cp2.y)) == 0
 }
 
 func (p *curvePoint) Null() abstract.Point {
@@ -10,6 +10,10 @@ var testQR512 = NewAES128SHA256QR512()
 
 func TestQR512(t *testing.T) { test.TestSuite(testQR512) }
 
+var testQR64 = NewInsecureQR64()
+
+func TestQR64(t *testing.T) { test.TestSuite(testQR64) }
+
 var testP256 = NewAES128SHA256P256()
 var benchP256 = test.NewGroupBench(testP256)
 
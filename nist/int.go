@@ -2,6 +2,7 @@ package nist
 
 import (
 	"crypto/cipher"
+	"crypto/subtle"
 	"encoding/hex"
 	"errors"
 	"io"
@@ -42,7 +43,6 @@ const (
 // target objects, and receive the modulus of the first operand.
 // For efficiency the modulus field M is a pointer,
 // whose target is assumed never to change.
-//
 type Int struct {
 	V  big.Int   // Integer value from 0 through M-1
 	M  *big.Int  // Modulus for finite field arithmetic
@@ -156,6 +156,19 @@ func (i *Int) Set(a abstract.Scalar) abstract.Scalar {
 	return i
 }
 
+// CMov sets i to a if cond == 1 and leaves i unchanged if cond == 0. The
+// selection does not branch on cond; both values are handled as fixed-length
+// byte strings, although math/big gives no guarantee that the final
+// conversion runs in constant time. Both Ints must have the same modulus.
+func (i *Int) CMov(a abstract.Scalar, cond int) {
+	ai := a.(*Int)
+	l := (i.M.BitLen() + 7) / 8
+	x := i.V.FillBytes(make([]byte, l))
+	y := ai.V.FillBytes(make([]byte, l))
+	subtle.ConstantTimeCopy(cond, x, y)
+	i.V.SetBytes(x)
+}
+
 func (i *Int) Clone() abstract.Scalar {
 	ni := new(Int).Init(&i.V, i.M)
 	ni.BO = i.BO
@@ -61,6 +61,22 @@ func NewAES128SHA256QR512() abstract.Suite {
 	return suite
 }
 
+// Ciphersuite based on SHA-256 and a residue group of quadratic residues
+// modulo a 64-bit safe prime. Discrete logarithms in this group can be
+// computed in seconds, so this suite is deliberately insecure. It only exists
+// to speed up tests of protocols running many dealings, and must never be used
+// to protect real data.
+func NewInsecureQR64() abstract.Suite {
+	p, _ := new(big.Int).SetString("18446744073709550147", 10)
+	q, _ := new(big.Int).SetString("9223372036854775073", 10)
+	r := new(big.Int).SetInt64(2)
+	g := new(big.Int).SetInt64(4)
+
+	suite := new(qrsuite)
+	suite.SetParams(p, q, r, g)
+	return suite
+}
+
 // Ciphersuite based on AES-128, SHA-256,
 // and a residue group of quadratic residues modulo a 1024-bit prime.
 // 1024-bit DSA-style groups may no longer be secure.
@@ -0,0 +1,337 @@
+package opaque
+
+import (
+	"bytes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"errors"
+
+	"github.com/dedis/crypto/abstract"
+	"github.com/dedis/crypto/random"
+)
+
+// Some error definitions
+var errorEnvelope = errors.New("envelope could not be opened")
+var errorServerAuth = errors.New("server authentication failed")
+var errorClientAuth = errors.New("client authentication failed")
+var errorState = errors.New("protocol step called out of order")
+
+const nonceLen = 32
+
+// RegistrationRequest is the first registration message sent by the client.
+type RegistrationRequest struct {
+	Alpha abstract.Point // Blinded password
+}
+
+// RegistrationResponse is the server's answer to a RegistrationRequest.
+type RegistrationResponse struct {
+	Beta         abstract.Point // Evaluated blinded password
+	ServerPublic abstract.Point // Long-term public key of the server
+}
+
+// RegistrationUpload is the final registration message sent by the client.
+type RegistrationUpload struct {
+	ClientPublic abstract.Point // Long-term public key of the client
+	Envelope     []byte         // Client secret and server public key sealed under the OPRF output
+}
+
+// Record is the per-user state the server stores after registration.
+type Record struct {
+	OPRFKey      abstract.Scalar // Per-user OPRF key
+	ClientPublic abstract.Point  // Long-term public key of the client
+	Envelope     []byte          // Envelope uploaded by the client
+}
+
+// LoginRequest is the first login message sent by the client.
+type LoginRequest struct {
+	Alpha     abstract.Point // Blinded password
+	Ephemeral abstract.Point // Ephemeral Diffie-Hellman key of the client
+}
+
+// LoginResponse is the server's answer to a LoginRequest.
+type LoginResponse struct {
+	Beta      abstract.Point // Evaluated blinded password
+	Envelope  []byte         // Envelope stored at registration
+	Ephemeral abstract.Point // Ephemeral Diffie-Hellman key of the server
+	MAC       []byte         // Server key confirmation
+}
+
+// LoginFinish is the final login message sent by the client.
+type LoginFinish struct {
+	MAC []byte // Client key confirmation
+}
+
+// Server represents an OPAQUE server with its long-term key pair.
+type Server struct {
+	suite  abstract.Suite
+	secret abstract.Scalar
+	Public abstract.Point
+}
+
+// NewServer creates a new OPAQUE server from its long-term private key.
+func NewServer(suite abstract.Suite, secret abstract.Scalar) *Server {
+	return &Server{suite, secret, suite.Point().Mul(nil, secret)}
+}
+
+// RegistrationRespond evaluates the client's blinded password under a fresh
+// per-user OPRF key. The key is returned so that it can be passed to
+// RegistrationFinalize once the client's upload arrives.
+func (s *Server) RegistrationRespond(req *RegistrationRequest, rand cipher.Stream) (*RegistrationResponse, abstract.Scalar, error) {
+	k := s.suite.Scalar().Pick(rand)
+	beta, err := Evaluate(s.suite, k, req.Alpha)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &RegistrationResponse{beta, s.Public}, k, nil
+}
+
+// RegistrationFinalize builds the record to be stored for the user.
+func (s *Server) RegistrationFinalize(k abstract.Scalar, up *RegistrationUpload) *Record {
+	return &Record{k, up.ClientPublic, up.Envelope}
+}
+
+// ServerSession holds the server state of a login between LoginRespond and
+// Finish.
+type ServerSession struct {
+	clientMAC  []byte
+	sessionKey []byte
+}
+
+// LoginRespond processes a login request for the user record rec. It returns
+// the response for the client and the session state needed to check the
+// client's key confirmation.
+func (s *Server) LoginRespond(rec *Record, req *LoginRequest, rand cipher.Stream) (*LoginResponse, *ServerSession, error) {
+	beta, err := Evaluate(s.suite, rec.OPRFKey, req.Alpha)
+	if err != nil {
+		return nil, nil, err
+	}
+	es := s.suite.Scalar().Pick(rand)
+	ES := s.suite.Point().Mul(nil, es)
+
+	// 3DH: ee, client ephemeral with server static, client static with server ephemeral
+	dh1 := s.suite.Point().Mul(req.Ephemeral, es)
+	dh2 := s.suite.Point().Mul(req.Ephemeral, s.secret)
+	dh3 := s.suite.Point().Mul(rec.ClientPublic, es)
+
+	tr, err := transcript(req.Alpha, req.Ephemeral, beta, ES, s.Public, rec.ClientPublic)
+	if err != nil {
+		return nil, nil, err
+	}
+	tr = append(tr, rec.Envelope...)
+	keys, err := deriveKeys(s.suite, tr, dh1, dh2, dh3)
+	if err != nil {
+		return nil, nil, err
+	}
+	resp := &LoginResponse{
+		Beta:      beta,
+		Envelope:  rec.Envelope,
+		Ephemeral: ES,
+		MAC:       mac(s.suite, keys.server, tr),
+	}
+	sess := &ServerSession{
+		clientMAC:  mac(s.suite, keys.client, append(tr, resp.MAC...)),
+		sessionKey: keys.session,
+	}
+	return resp, sess, nil
+}
+
+// Finish checks the client's key confirmation and returns the session key.
+func (ss *ServerSession) Finish(fin *LoginFinish) ([]byte, error) {
+	if !hmac.Equal(ss.clientMAC, fin.MAC) {
+		return nil, errorClientAuth
+	}
+	return ss.sessionKey, nil
+}
+
+// Client represents the client side of an OPAQUE registration or login.
+type Client struct {
+	suite    abstract.Suite
+	password []byte
+	input    abstract.Point // Hardened OPRF input, or nil for HashToPoint
+	blind    abstract.Scalar
+	alpha    abstract.Point
+	eph      abstract.Scalar
+	ephPub   abstract.Point
+}
+
+// NewClient creates a new OPAQUE client for the given password.
+func NewClient(suite abstract.Suite, password []byte) *Client {
+	return &Client{suite: suite, password: password}
+}
+
+// NewClientHardened is like NewClient but maps the password to the OPRF input
+// with HashPasswordToPoint under the salt and params instead of HashToPoint,
+// so that an attacker who obtains the server's OPRF key must still pay the
+// Argon2id cost for every password guess. The salt must be unique per user,
+// e.g. a hash of the user's name and the server's identity, and registration
+// and all logins must use the same salt and params.
+func NewClientHardened(suite abstract.Suite, password, salt []byte, params PasswordParams) (*Client, error) {
+	input, err := HashPasswordToPoint(suite, password, salt, params)
+	if err != nil {
+		return nil, err
+	}
+	return &Client{suite: suite, password: password, input: input}, nil
+}
+
+// blindPassword blinds the OPRF input of the password.
+func (c *Client) blindPassword(rand cipher.Stream) {
+	if c.input == nil {
+		c.blind, c.alpha = Blind(c.suite, c.password, rand)
+		return
+	}
+	c.blind = c.suite.Scalar().Pick(rand)
+	c.alpha = c.suite.Point().Mul(c.input, c.blind)
+}
+
+// RegistrationStart blinds the password and returns the first registration
+// message.
+func (c *Client) RegistrationStart(rand cipher.Stream) *RegistrationRequest {
+	c.blindPassword(rand)
+	return &RegistrationRequest{c.alpha}
+}
+
+// RegistrationFinalize derives the password key from the server's response,
+// creates the client's long-term key pair and seals it into the envelope.
+func (c *Client) RegistrationFinalize(resp *RegistrationResponse, rand cipher.Stream) (*RegistrationUpload, error) {
+	if c.blind == nil {
+		return nil, errorState
+	}
+	rwd, err := Finalize(c.suite, c.password, c.blind, resp.Beta)
+	if err != nil {
+		return nil, err
+	}
+	c.blind = nil
+
+	pc := c.suite.Scalar().Pick(rand)
+	PC := c.suite.Point().Mul(nil, pc)
+
+	var buf bytes.Buffer
+	if _, err := pc.MarshalTo(&buf); err != nil {
+		return nil, err
+	}
+	if _, err := resp.ServerPublic.MarshalTo(&buf); err != nil {
+		return nil, err
+	}
+	nonce := random.Bytes(nonceLen, rand)
+	env := envelopeCipher(c.suite, rwd, nonce).Seal(nonce, buf.Bytes())
+	return &RegistrationUpload{PC, env}, nil
+}
+
+// LoginStart blinds the password, picks an ephemeral key and returns the first
+// login message.
+func (c *Client) LoginStart(rand cipher.Stream) *LoginRequest {
+	c.blindPassword(rand)
+	c.eph = c.suite.Scalar().Pick(rand)
+	c.ephPub = c.suite.Point().Mul(nil, c.eph)
+	return &LoginRequest{c.alpha, c.ephPub}
+}
+
+// LoginFinalize opens the envelope, authenticates the server and returns the
+// final login message together with the session key.
+func (c *Client) LoginFinalize(resp *LoginResponse) (*LoginFinish, []byte, error) {
+	if c.blind == nil || c.eph == nil {
+		return nil, nil, errorState
+	}
+	rwd, err := Finalize(c.suite, c.password, c.blind, resp.Beta)
+	if err != nil {
+		return nil, nil, err
+	}
+	c.blind = nil
+
+	pc, KS, err := openEnvelope(c.suite, rwd, resp.Envelope)
+	if err != nil {
+		return nil, nil, err
+	}
+	PC := c.suite.Point().Mul(nil, pc)
+
+	dh1 := c.suite.Point().Mul(resp.Ephemeral, c.eph)
+	dh2 := c.suite.Point().Mul(KS, c.eph)
+	dh3 := c.suite.Point().Mul(resp.Ephemeral, pc)
+
+	tr, err := transcript(c.alpha, c.ephPub, resp.Beta, resp.Ephemeral, KS, PC)
+	if err != nil {
+		return nil, nil, err
+	}
+	tr = append(tr, resp.Envelope...)
+	keys, err := deriveKeys(c.suite, tr, dh1, dh2, dh3)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !hmac.Equal(mac(c.suite, keys.server, tr), resp.MAC) {
+		return nil, nil, errorServerAuth
+	}
+	fin := &LoginFinish{mac(c.suite, keys.client, append(tr, resp.MAC...))}
+	return fin, keys.session, nil
+}
+
+func envelopeCipher(suite abstract.Suite, rwd, nonce []byte) abstract.Cipher {
+	c := suite.Cipher(rwd)
+	c.Message(nil, nil, nonce)
+	return c
+}
+
+func openEnvelope(suite abstract.Suite, rwd, env []byte) (abstract.Scalar, abstract.Point, error) {
+	if len(env) < nonceLen {
+		return nil, nil, errorEnvelope
+	}
+	// Open checks the authenticator in place, so work on a copy
+	sealed := append([]byte{}, env[nonceLen:]...)
+	pt, err := envelopeCipher(suite, rwd, env[:nonceLen]).Open(nil, sealed)
+	if err != nil {
+		return nil, nil, errorEnvelope
+	}
+	pc := suite.Scalar()
+	KS := suite.Point()
+	r := bytes.NewReader(pt)
+	if _, err := pc.UnmarshalFrom(r); err != nil {
+		return nil, nil, errorEnvelope
+	}
+	if _, err := KS.UnmarshalFrom(r); err != nil {
+		return nil, nil, errorEnvelope
+	}
+	return pc, KS, nil
+}
+
+func transcript(points ...abstract.Point) ([]byte, error) {
+	var buf bytes.Buffer
+	for _, p := range points {
+		if _, err := p.MarshalTo(&buf); err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+type sessionKeys struct {
+	session []byte
+	server  []byte
+	client  []byte
+}
+
+func deriveKeys(suite abstract.Suite, tr []byte, dh ...abstract.Point) (*sessionKeys, error) {
+	h := suite.Hash()
+	h.Write([]byte("opaque-3dh"))
+	h.Write(tr)
+	for _, p := range dh {
+		if _, err := p.MarshalTo(h); err != nil {
+			return nil, err
+		}
+	}
+	c := suite.Cipher(h.Sum(nil))
+	size := c.KeySize()
+	keys := &sessionKeys{
+		session: make([]byte, size),
+		server:  make([]byte, size),
+		client:  make([]byte, size),
+	}
+	c.Partial(keys.session, nil, nil)
+	c.Partial(keys.server, nil, nil)
+	c.Partial(keys.client, nil, nil)
+	return keys, nil
+}
+
+func mac(suite abstract.Suite, key, data []byte) []byte {
+	m := hmac.New(suite.Hash, key)
+	m.Write(data)
+	return m.Sum(nil)
+}
@@ -0,0 +1,178 @@
+package opaque
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/dedis/crypto/edwards"
+	"github.com/dedis/crypto/random"
+)
+
+var suite = edwards.NewAES128SHA256Ed25519(false)
+
+func register(t *testing.T, server *Server, password []byte) *Record {
+	return registerClient(t, server, NewClient(suite, password))
+}
+
+func registerClient(t *testing.T, server *Server, client *Client) *Record {
+	req := client.RegistrationStart(random.Stream)
+	resp, k, err := server.RegistrationRespond(req, random.Stream)
+	if err != nil {
+		t.Fatal(err)
+	}
+	up, err := client.RegistrationFinalize(resp, random.Stream)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return server.RegistrationFinalize(k, up)
+}
+
+func TestOPRF(t *testing.T) {
+	k := suite.Scalar().Pick(random.Stream)
+	input := []byte("input")
+	out := make([][]byte, 2)
+	for i := range out {
+		r, alpha := Blind(suite, input, random.Stream)
+		beta, err := Evaluate(suite, k, alpha)
+		if err != nil {
+			t.Fatal(err)
+		}
+		out[i], err = Finalize(suite, input, r, beta)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if !bytes.Equal(out[0], out[1]) {
+		t.Fatal("OPRF output is not deterministic")
+	}
+	if _, err := Evaluate(suite, k, suite.Point().Null()); err == nil {
+		t.Fatal("OPRF evaluated the neutral element")
+	}
+}
+
+func TestLogin(t *testing.T) {
+	server := NewServer(suite, suite.Scalar().Pick(random.Stream))
+	password := []byte("correct horse battery staple")
+	rec := register(t, server, password)
+
+	client := NewClient(suite, password)
+	req := client.LoginStart(random.Stream)
+	resp, sess, err := server.LoginRespond(rec, req, random.Stream)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fin, ckey, err := client.LoginFinalize(resp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	skey, err := sess.Finish(fin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(ckey, skey) {
+		t.Fatal("session keys differ")
+	}
+}
+
+func TestLoginWrongPassword(t *testing.T) {
+	server := NewServer(suite, suite.Scalar().Pick(random.Stream))
+	rec := register(t, server, []byte("password"))
+
+	client := NewClient(suite, []byte("passw0rd"))
+	req := client.LoginStart(random.Stream)
+	resp, _, err := server.LoginRespond(rec, req, random.Stream)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := client.LoginFinalize(resp); err == nil {
+		t.Fatal("login succeeded with wrong password")
+	}
+}
+
+func TestLoginWrongServer(t *testing.T) {
+	server := NewServer(suite, suite.Scalar().Pick(random.Stream))
+	password := []byte("password")
+	rec := register(t, server, password)
+
+	// An impostor who stole the record but not the server key
+	impostor := NewServer(suite, suite.Scalar().Pick(random.Stream))
+	client := NewClient(suite, password)
+	req := client.LoginStart(random.Stream)
+	resp, _, err := impostor.LoginRespond(rec, req, random.Stream)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := client.LoginFinalize(resp); err == nil {
+		t.Fatal("client accepted an impostor server")
+	}
+}
+
+func TestHashPasswordToPoint(t *testing.T) {
+	params := PasswordParams{Time: 1, Memory: 64, Threads: 1}
+	salt := []byte("0123456789abcdef")
+	P, err := HashPasswordToPoint(suite, []byte("password"), salt, params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	Q, err := HashPasswordToPoint(suite, []byte("password"), salt, params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !P.Equal(Q) {
+		t.Fatal("hash-to-point not deterministic")
+	}
+	R, err := HashPasswordToPoint(suite, []byte("password"), []byte("fedcba9876543210"), params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if P.Equal(R) || P.Equal(HashToPoint(suite, []byte("password"))) {
+		t.Fatal("hash-to-point ignores the salt or the stretching")
+	}
+	if _, err := HashPasswordToPoint(suite, []byte("password"), salt[:15], params); err != errorSalt {
+		t.Fatal("accepted short salt")
+	}
+	if _, err := HashPasswordToPoint(suite, []byte("password"), salt, PasswordParams{}); err != errorCost {
+		t.Fatal("accepted zero cost")
+	}
+}
+
+func TestLoginHardened(t *testing.T) {
+	server := NewServer(suite, suite.Scalar().Pick(random.Stream))
+	password := []byte("correct horse battery staple")
+	params := PasswordParams{Time: 1, Memory: 64, Threads: 1}
+	salt := []byte("alice@example.org")
+	client, err := NewClientHardened(suite, password, salt, params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec := registerClient(t, server, client)
+
+	login := func(client *Client) error {
+		req := client.LoginStart(random.Stream)
+		resp, sess, err := server.LoginRespond(rec, req, random.Stream)
+		if err != nil {
+			return err
+		}
+		fin, _, err := client.LoginFinalize(resp)
+		if err != nil {
+			return err
+		}
+		_, err = sess.Finish(fin)
+		return err
+	}
+	client, _ = NewClientHardened(suite, password, salt, params)
+	if err := login(client); err != nil {
+		t.Fatal(err)
+	}
+	// The hardened input differs from the plain one and depends on the salt
+	if err := login(NewClient(suite, password)); err == nil {
+		t.Fatal("login succeeded without hardening")
+	}
+	client, _ = NewClientHardened(suite, password, []byte("bob@example.org!"), params)
+	if err := login(client); err == nil {
+		t.Fatal("login succeeded with another salt")
+	}
+	if _, err := NewClientHardened(suite, password, salt[:15], params); err != errorSalt {
+		t.Fatal("accepted short salt")
+	}
+}
@@ -0,0 +1,64 @@
+// Package opaque implements the OPAQUE asymmetric password-authenticated key
+// exchange. OPAQUE combines an oblivious pseudo-random function (OPRF), which
+// lets a client derive a strong key from its password with the help of the
+// server without the server ever learning the password, with an authenticated
+// key exchange based on long-term and ephemeral Diffie-Hellman keys. The server
+// only stores a per-user OPRF key, the client's public key and an envelope
+// that can only be opened with the password-derived key.
+package opaque
+
+import (
+	"crypto/cipher"
+	"errors"
+
+	"github.com/dedis/crypto/abstract"
+)
+
+// Some error definitions
+var errorInvalidPoint = errors.New("invalid OPRF group element")
+
+// HashToPoint maps the given data to a group element whose discrete logarithm
+// with respect to the standard base point is unknown.
+func HashToPoint(suite abstract.Suite, data []byte) abstract.Point {
+	h := suite.Hash()
+	h.Write([]byte("opaque-h2p"))
+	h.Write(data)
+	P, _ := suite.Point().Pick(nil, suite.Cipher(h.Sum(nil)))
+	return P
+}
+
+// Blind computes the blinded OPRF input alpha = r*H(input) for a freshly
+// chosen blinding factor r, which is returned together with alpha.
+func Blind(suite abstract.Suite, input []byte, rand cipher.Stream) (abstract.Scalar, abstract.Point) {
+	r := suite.Scalar().Pick(rand)
+	alpha := suite.Point().Mul(HashToPoint(suite, input), r)
+	return r, alpha
+}
+
+// Evaluate computes the server side of the OPRF, beta = k*alpha, for the OPRF
+// key k. It refuses to operate on the neutral element.
+func Evaluate(suite abstract.Suite, k abstract.Scalar, alpha abstract.Point) (abstract.Point, error) {
+	if alpha == nil || alpha.Equal(suite.Point().Null()) {
+		return nil, errorInvalidPoint
+	}
+	return suite.Point().Mul(alpha, k), nil
+}
+
+// Finalize removes the blinding factor r from the server's answer beta and
+// hashes the input together with the unblinded value k*H(input) into the OPRF
+// output.
+func Finalize(suite abstract.Suite, input []byte, r abstract.Scalar, beta abstract.Point) ([]byte, error) {
+	if beta == nil || beta.Equal(suite.Point().Null()) {
+		return nil, errorInvalidPoint
+	}
+	N := suite.Point().Mul(beta, suite.Scalar().Inv(r))
+	nb, err := N.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	h := suite.Hash()
+	h.Write([]byte("opaque-oprf"))
+	h.Write(input)
+	h.Write(nb)
+	return h.Sum(nil), nil
+}
@@ -0,0 +1,47 @@
+package opaque
+
+import (
+	"errors"
+
+	"golang.org/x/crypto/argon2"
+
+	"github.com/dedis/crypto/abstract"
+)
+
+// Some error definitions of the password hashing
+var errorSalt = errors.New("salt shorter than 16 bytes")
+var errorCost = errors.New("invalid Argon2 cost parameters")
+
+// PasswordParams are the Argon2id cost parameters of HashPasswordToPoint.
+// They fix the work of every password guess, which rate-limits offline
+// dictionary attacks.
+type PasswordParams struct {
+	Time    uint32 // Number of passes over the memory
+	Memory  uint32 // Memory in KiB
+	Threads uint8  // Degree of parallelism
+}
+
+// DefaultPasswordParams are the parameters recommended by RFC 9106 for
+// memory-constrained environments.
+var DefaultPasswordParams = PasswordParams{Time: 3, Memory: 64 * 1024, Threads: 4}
+
+// HashPasswordToPoint maps a password to a group element like HashToPoint,
+// but first stretches it with the memory-hard function Argon2id under the
+// salt. Unlike a plain hash, the mapping cannot be precomputed for a
+// dictionary of passwords before the salt is known, and every guess costs
+// the memory and time fixed by params. The salt must be at least 16 bytes
+// and unique per user. NewClientHardened uses it as the client's OPRF input.
+func HashPasswordToPoint(suite abstract.Suite, password, salt []byte, params PasswordParams) (abstract.Point, error) {
+	if len(salt) < 16 {
+		return nil, errorSalt
+	}
+	if params.Time == 0 || params.Memory < 8*uint32(params.Threads) || params.Threads == 0 {
+		return nil, errorCost
+	}
+	key := argon2.IDKey(password, salt, params.Time, params.Memory, params.Threads, 32)
+	h := suite.Hash()
+	h.Write([]byte("opaque-h2p-argon2id"))
+	h.Write(key)
+	P, _ := suite.Point().Pick(nil, suite.Cipher(h.Sum(nil)))
+	return P, nil
+}
@@ -1,3 +1,4 @@
+//go:build experimental
 // +build experimental
 
 package openssl
@@ -24,6 +25,7 @@ import (
 	"io"
 	"math/big"
 	"runtime"
+	"sync"
 	"unsafe"
 
 	"github.com/dedis/crypto/abstract"
@@ -37,7 +39,7 @@ type point struct {
 }
 
 type curve struct {
-	ctx          *_Ctype_struct_bignum_ctx
+	ctxs         sync.Pool // Pool of *bnCtx, see getCtx
 	g            *_Ctype_struct_ec_group_st
 	p, n, cofact *bignum
 	plen, nlen   int
@@ -45,6 +47,35 @@ type curve struct {
 	null         *point
 }
 
+// bnCtx holds a BN_CTX scratch space. OpenSSL does not allow a BN_CTX to be
+// used by several threads at once, so each operation borrows one from the
+// curve's pool, which makes a curve safe for concurrent use.
+type bnCtx struct {
+	ctx *_Ctype_struct_bignum_ctx
+}
+
+func newBnCtx() interface{} {
+	c := &bnCtx{C.BN_CTX_new()}
+	if c.ctx == nil {
+		panic("C.BN_CTX_new: " + getErrString())
+	}
+	runtime.SetFinalizer(c, freeBnCtx)
+	return c
+}
+
+func freeBnCtx(c *bnCtx) {
+	C.BN_CTX_free(c.ctx)
+	c.ctx = nil
+}
+
+func (c *curve) getCtx() *bnCtx {
+	return c.ctxs.Get().(*bnCtx)
+}
+
+func (c *curve) putCtx(ctx *bnCtx) {
+	c.ctxs.Put(ctx)
+}
+
 func newPoint(c *curve) *point {
 	p := new(point)
 	p.c = c
@@ -64,23 +95,31 @@ func (p *point) String() string {
 	return hex.EncodeToString(buf)
 }
 func (p *point) Valid() bool {
-	return C.EC_POINT_is_on_curve(p.g, p.p, p.c.ctx) != 0
+	ctx := p.c.getCtx()
+	defer p.c.putCtx(ctx)
+	return C.EC_POINT_is_on_curve(p.g, p.p, ctx.ctx) != 0
 }
 func (p *point) Equal(p2 abstract.Point) bool {
-	return C.EC_POINT_cmp(p.g, p.p, p2.(*point).p, p.c.ctx) == 0
+	ctx := p.c.getCtx()
+	defer p.c.putCtx(ctx)
+	return C.EC_POINT_cmp(p.g, p.p, p2.(*point).p, ctx.ctx) == 0
 }
 func (p *point) GetX() *bignum {
+	ctx := p.c.getCtx()
+	defer p.c.putCtx(ctx)
 	x := newBigNum()
 	if C.EC_POINT_get_affine_coordinates_GFp(p.c.g, p.p, x.bn, nil,
-		p.c.ctx) == 0 {
+		ctx.ctx) == 0 {
 		panic("EC_POINT_get_affine_coordinates_GFp: " + getErrString())
 	}
 	return x
 }
 func (p *point) GetY() *bignum {
+	ctx := p.c.getCtx()
+	defer p.c.putCtx(ctx)
 	y := newBigNum()
 	if C.EC_POINT_get_affine_coordinates_GFp(p.c.g, p.p, nil, y.bn,
-		p.c.ctx) == 0 {
+		ctx.ctx) == 0 {
 		panic("EC_POINT_get_affine_coordinates_GFp: " + getErrString())
 	}
 	return y
@@ -153,15 +192,19 @@ func (p *point) Data() ([]byte, error) {
 }
 
 func (p *point) Add(ca, cb abstract.Point) abstract.Point {
+	ctx := p.c.getCtx()
+	defer p.c.putCtx(ctx)
 	a := ca.(*point)
 	b := cb.(*point)
-	if C.EC_POINT_add(p.c.g, p.p, a.p, b.p, p.c.ctx) == 0 {
+	if C.EC_POINT_add(p.c.g, p.p, a.p, b.p, ctx.ctx) == 0 {
 		panic("EC_POINT_add: " + getErrString())
 	}
 	return p
 }
 
 func (p *point) Sub(ca, cb abstract.Point) abstract.Point {
+	ctx := p.c.getCtx()
+	defer p.c.putCtx(ctx)
 	a := ca.(*point)
 	b := cb.(*point)
 	// Add the point inverse.  Must use temporary if p == a.
@@ -172,39 +215,43 @@ func (p *point) Sub(ca, cb abstract.Point) abstract.Point {
 	if C.EC_POINT_copy(t.p, b.p) == 0 {
 		panic("EC_POINT_copy: " + getErrString())
 	}
-	if C.EC_POINT_invert(p.c.g, t.p, p.c.ctx) == 0 {
+	if C.EC_POINT_invert(p.c.g, t.p, ctx.ctx) == 0 {
 		panic("EC_POINT_invert: " + getErrString())
 	}
-	if C.EC_POINT_add(p.c.g, p.p, a.p, t.p, p.c.ctx) == 0 {
+	if C.EC_POINT_add(p.c.g, p.p, a.p, t.p, ctx.ctx) == 0 {
 		panic("EC_POINT_add: " + getErrString())
 	}
 	return p
 }
 
 func (p *point) Neg(ca abstract.Point) abstract.Point {
+	ctx := p.c.getCtx()
+	defer p.c.putCtx(ctx)
 	if ca != p {
 		a := ca.(*point)
 		if C.EC_POINT_copy(p.p, a.p) == 0 {
 			panic("EC_POINT_copy: " + getErrString())
 		}
 	}
-	if C.EC_POINT_invert(p.c.g, p.p, p.c.ctx) == 0 {
+	if C.EC_POINT_invert(p.c.g, p.p, ctx.ctx) == 0 {
 		panic("EC_POINT_invert: " + getErrString())
 	}
 	return p
 }
 
 func (p *point) Mul(cb abstract.Point, cs abstract.Scalar) abstract.Point {
+	ctx := p.c.getCtx()
+	defer p.c.putCtx(ctx)
 	s := cs.(*scalar)
 	if cb == nil { // multiply standard generator
 		if C.EC_POINT_mul(p.c.g, p.p, s.bignum.bn, nil, nil,
-			p.c.ctx) == 0 {
+			ctx.ctx) == 0 {
 			panic("EC_POINT_mul: " + getErrString())
 		}
 	} else { // multiply arbitrary point b
 		b := cb.(*point)
 		if C.EC_POINT_mul(p.c.g, p.p, nil, b.p, s.bignum.bn,
-			p.c.ctx) == 0 {
+			ctx.ctx) == 0 {
 			panic("EC_POINT_mul: " + getErrString())
 		}
 	}
@@ -216,6 +263,8 @@ func (p *point) MarshalSize() int {
 }
 
 func (p *point) MarshalBinary() ([]byte, error) {
+	ctx := p.c.getCtx()
+	defer p.c.putCtx(ctx)
 	l := 1 + p.c.plen
 	b := make([]byte, l)
 
@@ -223,7 +272,7 @@ func (p *point) MarshalBinary() ([]byte, error) {
 	// as a single 0 byte, hence returning a length of 1.
 	if C.EC_POINT_point2oct(p.c.g, p.p, C.POINT_CONVERSION_COMPRESSED,
 		(*_Ctype_unsignedchar)(unsafe.Pointer(&b[0])),
-		C.size_t(l), p.c.ctx) == 0 {
+		C.size_t(l), ctx.ctx) == 0 {
 		panic("EC_POINT_point2oct: " + getErrString())
 	}
 
@@ -231,6 +280,8 @@ func (p *point) MarshalBinary() ([]byte, error) {
 }
 
 func (p *point) UnmarshalBinary(buf []byte) error {
+	ctx := p.c.getCtx()
+	defer p.c.putCtx(ctx)
 	l := len(buf)
 	if buf[0] == 0 { // Special case: point at infinity
 		l = 1 // single 0 byte
@@ -238,7 +289,7 @@ func (p *point) UnmarshalBinary(buf []byte) error {
 
 	if C.EC_POINT_oct2point(p.g, p.p,
 		(*_Ctype_unsignedchar)(unsafe.Pointer(&buf[0])),
-		C.size_t(l), p.c.ctx) == 0 {
+		C.size_t(l), ctx.ctx) == 0 {
 		return errors.New(getErrString())
 	}
 	return nil
@@ -285,10 +336,9 @@ func (c *curve) Order() *big.Int {
 func (c *curve) initNamedCurve(name string, nid C.int) *curve {
 	c.name = name
 
-	c.ctx = C.BN_CTX_new()
-	if c.ctx == nil {
-		panic("C.BN_CTX_new: " + getErrString())
-	}
+	c.ctxs.New = newBnCtx
+	ctx := c.getCtx()
+	defer c.putCtx(ctx)
 
 	c.g = C.EC_GROUP_new_by_curve_name(nid)
 	if c.g == nil {
@@ -297,21 +347,21 @@ func (c *curve) initNamedCurve(name string, nid C.int) *curve {
 
 	// Get this curve's prime field
 	c.p = newBigNum()
-	if C.EC_GROUP_get_curve_GFp(c.g, c.p.bn, nil, nil, c.ctx) == 0 {
+	if C.EC_GROUP_get_curve_GFp(c.g, c.p.bn, nil, nil, ctx.ctx) == 0 {
 		panic("EC_GROUP_get_curve_GFp: " + getErrString())
 	}
 	c.plen = (c.p.BitLen() + 7) / 8
 
 	// Get the curve's group order
 	c.n = newBigNum()
-	if C.EC_GROUP_get_order(c.g, c.n.bn, c.ctx) == 0 {
+	if C.EC_GROUP_get_order(c.g, c.n.bn, ctx.ctx) == 0 {
 		panic("EC_GROUP_get_order: " + getErrString())
 	}
 	c.nlen = (c.n.BitLen() + 7) / 8
 
 	// Get the curve's cofactor
 	c.cofact = newBigNum()
-	if C.EC_GROUP_get_cofactor(c.g, c.cofact.bn, c.ctx) == 0 {
+	if C.EC_GROUP_get_cofactor(c.g, c.cofact.bn, ctx.ctx) == 0 {
 		panic("EC_GROUP_get_cofactor: " + getErrString())
 	}
 
@@ -1,3 +1,4 @@
+//go:build experimental
 // +build experimental
 
 package openssl
@@ -12,6 +13,7 @@ import "C"
 
 import (
 	"crypto/cipher"
+	"errors"
 	"io"
 
 	"github.com/dedis/crypto/abstract"
@@ -85,45 +87,55 @@ func (s *scalar) SetInt64(v int64) abstract.Scalar {
 }
 
 func (s *scalar) Add(x, y abstract.Scalar) abstract.Scalar {
+	ctx := s.c.getCtx()
+	defer s.c.putCtx(ctx)
 	xs := x.(*scalar)
 	ys := y.(*scalar)
 	if C.BN_mod_add(s.bignum.bn, xs.bignum.bn, ys.bignum.bn, s.c.n.bn,
-		s.c.ctx) == 0 {
+		ctx.ctx) == 0 {
 		panic("BN_mod_add: " + getErrString())
 	}
 	return s
 }
 
 func (s *scalar) Sub(x, y abstract.Scalar) abstract.Scalar {
+	ctx := s.c.getCtx()
+	defer s.c.putCtx(ctx)
 	xs := x.(*scalar)
 	ys := y.(*scalar)
 	if C.BN_mod_sub(s.bignum.bn, xs.bignum.bn, ys.bignum.bn, s.c.n.bn,
-		s.c.ctx) == 0 {
+		ctx.ctx) == 0 {
 		panic("BN_mod_sub: " + getErrString())
 	}
 	return s
 }
 
 func (s *scalar) Neg(x abstract.Scalar) abstract.Scalar {
+	ctx := s.c.getCtx()
+	defer s.c.putCtx(ctx)
 	xs := x.(*scalar)
 	if C.BN_mod_sub(s.bignum.bn, s.c.n.bn, xs.bignum.bn, s.c.n.bn,
-		s.c.ctx) == 0 {
+		ctx.ctx) == 0 {
 		panic("BN_mod_sub: " + getErrString())
 	}
 	return s
 }
 
 func (s *scalar) Mul(x, y abstract.Scalar) abstract.Scalar {
+	ctx := s.c.getCtx()
+	defer s.c.putCtx(ctx)
 	xs := x.(*scalar)
 	ys := y.(*scalar)
 	if C.BN_mod_mul(s.bignum.bn, xs.bignum.bn, ys.bignum.bn, s.c.n.bn,
-		s.c.ctx) == 0 {
+		ctx.ctx) == 0 {
 		panic("BN_mod_mul: " + getErrString())
 	}
 	return s
 }
 
 func (s *scalar) Div(x, y abstract.Scalar) abstract.Scalar {
+	ctx := s.c.getCtx()
+	defer s.c.putCtx(ctx)
 	xs := x.(*scalar)
 	ys := y.(*scalar)
 
@@ -134,20 +146,22 @@ func (s *scalar) Div(x, y abstract.Scalar) abstract.Scalar {
 		t = newBigNum()
 	}
 	if C.BN_mod_inverse(t.bn, ys.bignum.bn, s.c.n.bn,
-		s.c.ctx) == nil {
+		ctx.ctx) == nil {
 		panic("BN_mod_inverse: " + getErrString())
 	}
 	if C.BN_mod_mul(s.bignum.bn, xs.bignum.bn, t.bn, s.c.n.bn,
-		s.c.ctx) == 0 {
+		ctx.ctx) == 0 {
 		panic("BN_mod_mul: " + getErrString())
 	}
 	return s
 }
 
 func (s *scalar) Inv(x abstract.Scalar) abstract.Scalar {
+	ctx := s.c.getCtx()
+	defer s.c.putCtx(ctx)
 	xs := x.(*scalar)
 	if C.BN_mod_inverse(s.bignum.bn, xs.bignum.bn, s.c.n.bn,
-		s.c.ctx) == nil {
+		ctx.ctx) == nil {
 		panic("BN_mod_inverse: " + getErrString())
 	}
 	return s
@@ -167,7 +181,13 @@ func (s *scalar) MarshalBinary() ([]byte, error) {
 }
 
 func (s *scalar) UnmarshalBinary(buf []byte) error {
+	if len(buf) != s.c.nlen {
+		return errors.New("scalar.UnmarshalBinary: wrong size buffer")
+	}
 	s.SetBytes(buf)
+	if s.Cmp(s.c.n) >= 0 {
+		return errors.New("scalar.UnmarshalBinary: value out of range")
+	}
 	return nil
 }
 
@@ -1,3 +1,4 @@
+//go:build experimental
 // +build experimental
 
 // Package openssl implements a ciphersuite
@@ -0,0 +1,162 @@
+package ot
+
+import (
+	"crypto/cipher"
+
+	"github.com/dedis/crypto/abstract"
+	"github.com/dedis/crypto/random"
+)
+
+// Kappa is the computational security parameter of the OT extension, i.e.,
+// the number of base OTs.
+const Kappa = 128
+
+// ExtReceiver is the receiver of m extended 1-out-of-2 OTs. During the base
+// OT phase it acts as the base OT sender.
+type ExtReceiver struct {
+	suite   abstract.Suite
+	choices []bool
+	base    []*Sender
+	t       [][]byte // columns t_i of the extension matrix
+}
+
+// ExtSender is the sender of m extended 1-out-of-2 OTs. During the base OT
+// phase it acts as the base OT receiver with the random choice bits s.
+type ExtSender struct {
+	suite abstract.Suite
+	s     []bool
+	base  []*Receiver
+}
+
+// NewExtReceiver creates the receiver for the given choice bits and returns
+// it together with the first base OT messages.
+func NewExtReceiver(suite abstract.Suite, choices []bool, rand cipher.Stream) (*ExtReceiver, []abstract.Point) {
+	r := &ExtReceiver{suite: suite, choices: choices, base: make([]*Sender, Kappa)}
+	A := make([]abstract.Point, Kappa)
+	for i := range r.base {
+		r.base[i] = NewSender(suite, rand)
+		A[i] = r.base[i].A
+	}
+	return r, A
+}
+
+// NewExtSender creates the sender, picks the random vector s and answers the
+// base OT messages.
+func NewExtSender(suite abstract.Suite, A []abstract.Point, rand cipher.Stream) (*ExtSender, []abstract.Point, error) {
+	if len(A) != Kappa {
+		return nil, nil, errorLength
+	}
+	s := &ExtSender{suite: suite, s: make([]bool, Kappa), base: make([]*Receiver, Kappa)}
+	B := make([]abstract.Point, Kappa)
+	for i := range s.base {
+		s.s[i] = random.Bool(rand)
+		choice := 0
+		if s.s[i] {
+			choice = 1
+		}
+		var err error
+		if s.base[i], err = NewReceiver(suite, A[i], choice, 2, rand); err != nil {
+			return nil, nil, err
+		}
+		B[i] = s.base[i].B
+	}
+	return s, B, nil
+}
+
+// Extend completes the base OTs and computes the correction columns
+// u_i = G(k_i^0) ^ G(k_i^1) ^ r that are sent to the sender.
+func (r *ExtReceiver) Extend(B []abstract.Point) ([][]byte, error) {
+	if len(B) != Kappa {
+		return nil, errorLength
+	}
+	m := len(r.choices)
+	rbits := packBits(r.choices)
+	r.t = make([][]byte, Kappa)
+	U := make([][]byte, Kappa)
+	for i := range r.base {
+		keys, err := r.base[i].Keys(B[i], 2)
+		if err != nil {
+			return nil, err
+		}
+		r.t[i] = prg(r.suite, keys[0], m)
+		U[i] = prg(r.suite, keys[1], m)
+		xorBytes(U[i], r.t[i])
+		xorBytes(U[i], rbits)
+	}
+	return U, nil
+}
+
+// Transfer computes the extension matrix q_i = t_i ^ s_i*r from the
+// correction columns and encrypts each message pair (x_j^0, x_j^1) under
+// H(j, q_j) and H(j, q_j ^ s), respectively.
+func (s *ExtSender) Transfer(U [][]byte, msgs [][2][]byte) ([][2][]byte, error) {
+	if len(U) != Kappa {
+		return nil, errorLength
+	}
+	m := len(msgs)
+	Q := make([][]byte, Kappa)
+	for i := range Q {
+		if len(U[i]) != (m+7)/8 {
+			return nil, errorLength
+		}
+		Q[i] = prg(s.suite, s.base[i].Key(), m)
+		if s.s[i] {
+			xorBytes(Q[i], U[i])
+		}
+	}
+	sbits := packBits(s.s)
+	cts := make([][2][]byte, m)
+	for j := range msgs {
+		q := row(Q, j)
+		cts[j][0] = xorStream(s.suite, hashRow(s.suite, j, q), msgs[j][0])
+		xorBytes(q, sbits)
+		cts[j][1] = xorStream(s.suite, hashRow(s.suite, j, q), msgs[j][1])
+	}
+	return cts, nil
+}
+
+// Receive decrypts the chosen message of every pair.
+func (r *ExtReceiver) Receive(cts [][2][]byte) ([][]byte, error) {
+	if len(cts) != len(r.choices) || r.t == nil {
+		return nil, errorLength
+	}
+	out := make([][]byte, len(cts))
+	for j, c := range r.choices {
+		ct := cts[j][0]
+		if c {
+			ct = cts[j][1]
+		}
+		out[j] = xorStream(r.suite, hashRow(r.suite, j, row(r.t, j)), ct)
+	}
+	return out, nil
+}
+
+// prg expands a seed into m bits.
+func prg(suite abstract.Suite, seed []byte, m int) []byte {
+	return random.Bytes((m+7)/8, suite.Cipher(seed))
+}
+
+func packBits(bits []bool) []byte {
+	out := make([]byte, (len(bits)+7)/8)
+	for i, b := range bits {
+		if b {
+			out[i/8] |= 1 << uint(i%8)
+		}
+	}
+	return out
+}
+
+// row extracts row j of a matrix stored as columns of packed bits.
+func row(cols [][]byte, j int) []byte {
+	bits := make([]bool, len(cols))
+	for i, c := range cols {
+		bits[i] = c[j/8]&(1<<uint(j%8)) != 0
+	}
+	return packBits(bits)
+}
+
+func xorBytes(dst, src []byte) {
+	for i := range dst {
+		dst[i] ^= src[i]
+	}
+}
@@ -0,0 +1,155 @@
+// Package ot implements oblivious transfer. In a 1-out-of-n oblivious transfer
+// a sender holds n messages and a receiver learns exactly one of them, of its
+// choice, without the sender learning which one was chosen.
+//
+// The base protocol is the "simplest OT" of Chou and Orlandi over any
+// abstract.Group:
+//
+//	sender:   A = aG
+//	receiver: B = cA + bG     for the choice c
+//	sender:   k_j = H(A, B, a(B - jA))  for j = 0, ..., n-1
+//	receiver: k_c = H(A, B, bA)
+//
+// after which the sender encrypts message j under key k_j. Base OTs require
+// public-key operations, so the package also provides IKNP-style OT extension
+// (see ExtSender and ExtReceiver), which turns Kappa base OTs into an
+// arbitrary number of 1-out-of-2 OTs using only symmetric-key operations.
+//
+// The protocols are secure against semi-honest adversaries. For further
+// background see:
+//
+//	"The Simplest Protocol for Oblivious Transfer" by Chou and Orlandi
+//	https://eprint.iacr.org/2015/267.pdf
+//	"Extending Oblivious Transfers Efficiently" by Ishai, Kilian, Nissim and Petrank
+package ot
+
+import (
+	"crypto/cipher"
+	"encoding/binary"
+	"errors"
+
+	"github.com/dedis/crypto/abstract"
+)
+
+// Some error definitions
+var errorInvalidPoint = errors.New("invalid OT message")
+var errorChoice = errors.New("choice out of range")
+var errorLength = errors.New("inputs of different lengths")
+
+// Sender is the sender side of a base oblivious transfer.
+type Sender struct {
+	suite abstract.Suite
+	a     abstract.Scalar
+	A     abstract.Point // First message, to be sent to the receiver
+}
+
+// NewSender creates a sender with a fresh key A = aG.
+func NewSender(suite abstract.Suite, rand cipher.Stream) *Sender {
+	a := suite.Scalar().Pick(rand)
+	return &Sender{suite, a, suite.Point().Mul(nil, a)}
+}
+
+// Keys derives the n transfer keys from the receiver's message B. The
+// receiver can compute exactly one of them.
+func (s *Sender) Keys(B abstract.Point, n int) ([][]byte, error) {
+	if B == nil || B.Equal(s.suite.Point().Null()) {
+		return nil, errorInvalidPoint
+	}
+	keys := make([][]byte, n)
+	jA := s.suite.Point().Null()
+	P := s.suite.Point()
+	for j := 0; j < n; j++ {
+		P.Sub(B, jA)
+		P.Mul(P, s.a)
+		k, err := deriveKey(s.suite, s.A, B, P)
+		if err != nil {
+			return nil, err
+		}
+		keys[j] = k
+		jA.Add(jA, s.A)
+	}
+	return keys, nil
+}
+
+// Encrypt encrypts each message under its transfer key.
+func (s *Sender) Encrypt(B abstract.Point, msgs [][]byte) ([][]byte, error) {
+	keys, err := s.Keys(B, len(msgs))
+	if err != nil {
+		return nil, err
+	}
+	cts := make([][]byte, len(msgs))
+	for j, m := range msgs {
+		cts[j] = xorStream(s.suite, keys[j], m)
+	}
+	return cts, nil
+}
+
+// Receiver is the receiver side of a base oblivious transfer.
+type Receiver struct {
+	suite  abstract.Suite
+	choice int
+	key    []byte
+	B      abstract.Point // Second message, to be sent to the sender
+}
+
+// NewReceiver computes the receiver's message for the sender's message A and
+// the given choice among n messages.
+func NewReceiver(suite abstract.Suite, A abstract.Point, choice, n int, rand cipher.Stream) (*Receiver, error) {
+	if choice < 0 || choice >= n {
+		return nil, errorChoice
+	}
+	if A == nil || A.Equal(suite.Point().Null()) {
+		return nil, errorInvalidPoint
+	}
+	b := suite.Scalar().Pick(rand)
+	c := suite.Scalar().SetInt64(int64(choice))
+	B := suite.Point().Add(suite.Point().Mul(A, c), suite.Point().Mul(nil, b))
+	key, err := deriveKey(suite, A, B, suite.Point().Mul(A, b))
+	if err != nil {
+		return nil, err
+	}
+	return &Receiver{suite, choice, key, B}, nil
+}
+
+// Key returns the transfer key of the chosen message.
+func (r *Receiver) Key() []byte {
+	return r.key
+}
+
+// Decrypt decrypts the chosen message out of the sender's ciphertexts.
+func (r *Receiver) Decrypt(cts [][]byte) ([]byte, error) {
+	if r.choice >= len(cts) {
+		return nil, errorChoice
+	}
+	return xorStream(r.suite, r.key, cts[r.choice]), nil
+}
+
+func deriveKey(suite abstract.Suite, A, B, P abstract.Point) ([]byte, error) {
+	h := suite.Hash()
+	h.Write([]byte("ot-key"))
+	for _, X := range []abstract.Point{A, B, P} {
+		if _, err := X.MarshalTo(h); err != nil {
+			return nil, err
+		}
+	}
+	return h.Sum(nil), nil
+}
+
+// xorStream XORs data with the key stream of a cipher seeded with key.
+func xorStream(suite abstract.Suite, key, data []byte) []byte {
+	out := make([]byte, len(data))
+	suite.Cipher(key).XORKeyStream(out, data)
+	return out
+}
+
+// hashRow hashes a row of the extension matrix together with its index into
+// the seed of a message pad.
+func hashRow(suite abstract.Suite, j int, row []byte) []byte {
+	var idx [8]byte
+	binary.BigEndian.PutUint64(idx[:], uint64(j))
+	h := suite.Hash()
+	h.Write([]byte("ot-ext"))
+	h.Write(idx[:])
+	h.Write(row)
+	return h.Sum(nil)
+}
@@ -0,0 +1,74 @@
+package ot
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/dedis/crypto/edwards"
+	"github.com/dedis/crypto/random"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var suite = edwards.NewAES128SHA256Ed25519(false)
+
+func TestOT(t *testing.T) {
+	n := 5
+	msgs := make([][]byte, n)
+	for i := range msgs {
+		msgs[i] = []byte(fmt.Sprintf("message %d", i))
+	}
+	for c := 0; c < n; c++ {
+		s := NewSender(suite, random.Stream)
+		r, err := NewReceiver(suite, s.A, c, n, random.Stream)
+		require.Nil(t, err)
+		cts, err := s.Encrypt(r.B, msgs)
+		require.Nil(t, err)
+		m, err := r.Decrypt(cts)
+		require.Nil(t, err)
+		assert.Equal(t, msgs[c], m)
+
+		// Only the chosen key is known to the receiver
+		keys, err := s.Keys(r.B, n)
+		require.Nil(t, err)
+		for j := range keys {
+			assert.Equal(t, j == c, bytes.Equal(keys[j], r.Key()))
+		}
+	}
+
+	s := NewSender(suite, random.Stream)
+	_, err := NewReceiver(suite, s.A, n, n, random.Stream)
+	assert.Error(t, err)
+	_, err = s.Keys(suite.Point().Null(), n)
+	assert.Error(t, err)
+}
+
+func TestOTExtension(t *testing.T) {
+	m := 300
+	choices := make([]bool, m)
+	msgs := make([][2][]byte, m)
+	for j := range msgs {
+		choices[j] = random.Bool(random.Stream)
+		msgs[j][0] = []byte(fmt.Sprintf("zero %d", j))
+		msgs[j][1] = []byte(fmt.Sprintf("one %d", j))
+	}
+
+	r, A := NewExtReceiver(suite, choices, random.Stream)
+	s, B, err := NewExtSender(suite, A, random.Stream)
+	require.Nil(t, err)
+	U, err := r.Extend(B)
+	require.Nil(t, err)
+	cts, err := s.Transfer(U, msgs)
+	require.Nil(t, err)
+	out, err := r.Receive(cts)
+	require.Nil(t, err)
+	for j, c := range choices {
+		want, other := msgs[j][0], msgs[j][1]
+		if c {
+			want, other = other, want
+		}
+		assert.Equal(t, want, out[j])
+		assert.NotEqual(t, other, out[j])
+	}
+}
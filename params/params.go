@@ -0,0 +1,103 @@
+// Package params defines named security levels and the consistent sets of
+// primitives that achieve them, so that an integrator picks a single level
+// instead of combining a group, a hash function and key lengths that do not
+// match:
+//
+//	p, err := params.Lookup("192")
+//	suite := p.Suite          // for pvss, dkg, sign, proof, ...
+//	key := make([]byte, p.KeyLen)
+//
+// Every preset pairs a group whose scalars have at least twice as many bits
+// as the security level, a hash function whose output has at least twice as
+// many bits, which also sizes the Fiat-Shamir challenges of package proof,
+// and symmetric key, salt and nonce lengths of the security level. Check
+// tests whether a suite assembled elsewhere meets a preset.
+package params
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/dedis/crypto/abstract"
+	"github.com/dedis/crypto/edwards"
+)
+
+// Some error definitions
+var errorLevel = errors.New("unknown security level")
+var errorWeak = errors.New("suite below security level")
+
+// Level is a security level in bits.
+type Level int
+
+// The supported security levels.
+const (
+	Level128 Level = 128
+	Level192 Level = 192
+	Level256 Level = 256
+)
+
+// Preset is the set of primitives and lengths of a security level.
+type Preset struct {
+	Level    Level
+	Suite    abstract.Suite // Group, hash function and stream cipher
+	KeyLen   int            // Length of symmetric keys and MACs in bytes
+	SaltLen  int            // Length of salts in bytes
+	NonceLen int            // Length of random nonces in bytes
+}
+
+// Name returns the name of the preset, the decimal security level.
+func (p *Preset) Name() string {
+	return strconv.Itoa(int(p.Level))
+}
+
+func newPreset(level Level, suite abstract.Suite) *Preset {
+	n := int(level) / 8
+	return &Preset{Level: level, Suite: suite, KeyLen: n, SaltLen: n, NonceLen: n}
+}
+
+// Get returns the preset of the security level.
+func Get(level Level) (*Preset, error) {
+	switch level {
+	case Level128:
+		return newPreset(level, edwards.NewAES128SHA256Ed25519(false)), nil
+	case Level192:
+		return newPreset(level, edwards.NewSHAKE256SHA384E382(false)), nil
+	case Level256:
+		return newPreset(level, edwards.NewSHAKE256SHA512E521(false)), nil
+	}
+	return nil, fmt.Errorf("%w: %d", errorLevel, level)
+}
+
+// Lookup returns the preset with the given name, see Preset.Name.
+func Lookup(name string) (*Preset, error) {
+	level, err := strconv.Atoi(name)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", errorLevel, name)
+	}
+	return Get(Level(level))
+}
+
+// All returns the presets of all security levels, in increasing order.
+func All() []*Preset {
+	var presets []*Preset
+	for _, level := range []Level{Level128, Level192, Level256} {
+		p, _ := Get(level)
+		presets = append(presets, p)
+	}
+	return presets
+}
+
+// Check reports whether the suite meets the security level of the preset:
+// its scalars and its hash values must have at least twice as many bits as
+// the level. The scalar size is a bound on the group order, which suffices
+// for the elliptic curve groups of this library.
+func (p *Preset) Check(suite abstract.Suite) error {
+	if bits := 8 * suite.Scalar().MarshalSize(); bits < 2*int(p.Level) {
+		return fmt.Errorf("%w: %d-bit scalars of %s for level %d", errorWeak, bits, suite.String(), p.Level)
+	}
+	if bits := 8 * suite.Hash().Size(); bits < 2*int(p.Level) {
+		return fmt.Errorf("%w: %d-bit hash of %s for level %d", errorWeak, bits, suite.String(), p.Level)
+	}
+	return nil
+}
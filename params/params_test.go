@@ -0,0 +1,39 @@
+package params
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/dedis/crypto/nist"
+	"github.com/dedis/crypto/test"
+)
+
+func TestPresets(t *testing.T) {
+	for _, p := range All() {
+		if err := p.Check(p.Suite); err != nil {
+			t.Fatal(err)
+		}
+		q, err := Lookup(p.Name())
+		if err != nil || q.Level != p.Level || q.Suite.String() != p.Suite.String() {
+			t.Fatal("lookup of preset", p.Name(), "failed")
+		}
+		if p.KeyLen*8 != int(p.Level) {
+			t.Fatal("key length does not match level", p.Level)
+		}
+		test.TestSuite(p.Suite)
+	}
+	p, _ := Get(Level128)
+	if err := p.Check(nist.NewAES128SHA256P256()); err != nil {
+		t.Fatal(err)
+	}
+	p, _ = Get(Level256)
+	if err := p.Check(nist.NewAES128SHA256P256()); !errors.Is(err, errorWeak) {
+		t.Fatal("accepted P-256 for level 256")
+	}
+	if _, err := Lookup("100"); !errors.Is(err, errorLevel) {
+		t.Fatal("accepted unknown level")
+	}
+	if _, err := Lookup("high"); !errors.Is(err, errorLevel) {
+		t.Fatal("accepted unknown name")
+	}
+}
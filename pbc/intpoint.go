@@ -1,3 +1,4 @@
+//go:build pbc
 // +build pbc
 
 package pbc
@@ -15,6 +16,7 @@ import (
 
 	"github.com/dedis/crypto/abstract"
 	"github.com/dedis/crypto/group"
+	"github.com/dedis/crypto/random"
 )
 
 // Integer finite field point for GT group.
@@ -25,6 +27,7 @@ import (
 // generalizes or abstracts its compressed-encoding methods.
 type intPoint struct {
 	e C.element_t
+	p *Pairing
 }
 
 func clearIntPoint(p *intPoint) {
@@ -32,8 +35,8 @@ func clearIntPoint(p *intPoint) {
 	C.element_clear(&p.e[0])
 }
 
-func newIntPoint() *intPoint {
-	p := new(intPoint)
+func newIntPoint(pairing *Pairing) *intPoint {
+	p := &intPoint{p: pairing}
 	runtime.SetFinalizer(p, clearIntPoint)
 	return p
 }
@@ -57,20 +60,38 @@ func (p *intPoint) Null() abstract.Point {
 	return p
 }
 
+// Base sets the point to the pairing of the base points of G1 and G2.
 func (p *intPoint) Base() abstract.Point {
-	panic("XXX")
+	return p.Pairing(p.p.G1().Point().Base(), p.p.G2().Point().Base())
 }
 
+// PickLen returns 0 since the PBC library has no data embedding.
 func (p *intPoint) PickLen() int {
-	panic("XXX")
+	return 0
 }
 
+// Pick sets the point to a random element, hashed from random bytes. It
+// embeds no data and returns all of it as remainder.
 func (p *intPoint) Pick(data []byte, rand cipher.Stream) (abstract.Point, []byte) {
-	panic("XXX")
+	b := random.Bytes(32, rand)
+	C.element_from_hash(&p.e[0], unsafe.Pointer(&b[0]), C.int(len(b)))
+	return p, data
 }
 
 func (p *intPoint) Data() ([]byte, error) {
-	panic("XXX")
+	return nil, errors.New("pbc: no data embedded in pairing elements")
+}
+
+func (p *intPoint) Set(a abstract.Point) abstract.Point {
+	C.element_set(&p.e[0], &a.(*intPoint).e[0])
+	return p
+}
+
+func (p *intPoint) Clone() abstract.Point {
+	p2 := newIntPoint(p.p)
+	C.element_init_same_as(&p2.e[0], &p.e[0])
+	C.element_set(&p2.e[0], &p.e[0])
+	return p2
 }
 
 func (p *intPoint) Add(a, b abstract.Point) abstract.Point {
@@ -107,7 +128,7 @@ func (p *intPoint) MarshalSize() int {
 }
 
 func (p *intPoint) MarshalBinary() ([]byte, error) {
-	l := p.Len()
+	l := p.MarshalSize()
 	b := make([]byte, l)
 	a := C.element_to_bytes((*C.uchar)(unsafe.Pointer(&b[0])), &p.e[0])
 	if int(a) != l {
@@ -117,7 +138,7 @@ func (p *intPoint) MarshalBinary() ([]byte, error) {
 }
 
 func (p *intPoint) UnmarshalBinary(buf []byte) error {
-	l := p.Len()
+	l := p.MarshalSize()
 	if len(buf) != l {
 		return errors.New("Encoded element wrong length")
 	}
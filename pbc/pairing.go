@@ -1,3 +1,4 @@
+//go:build pbc
 // +build pbc
 
 // Package pbc provides a Go wrapper for
@@ -242,7 +243,7 @@ func (g *gtgroup) Point() abstract.Point {
 }
 
 func (g *gtgroup) PairingPoint() PairingPoint {
-	p := newIntPoint()
+	p := newIntPoint(g.p)
 	C.element_init_GT(&p.e[0], &g.p.p[0])
 	return p
 }
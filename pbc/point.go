@@ -1,3 +1,4 @@
+//go:build pbc
 // +build pbc
 
 package pbc
@@ -15,6 +16,7 @@ import (
 
 	"github.com/dedis/crypto/abstract"
 	"github.com/dedis/crypto/group"
+	"github.com/dedis/crypto/random"
 )
 
 // Elliptic curve point for G1,G2 groups
@@ -52,20 +54,41 @@ func (p *point) Null() abstract.Point {
 	return p
 }
 
+// Base sets the point to the standard base point of its group, which is
+// hashed from a fixed string since the PBC library defines none.
 func (p *point) Base() abstract.Point {
-	panic("XXX")
+	b := []byte("dedis-pbc-base")
+	C.element_from_hash(&p.e[0], unsafe.Pointer(&b[0]), C.int(len(b)))
+	return p
 }
 
+// PickLen returns 0 since the PBC library has no data embedding.
 func (p *point) PickLen() int {
-	panic("XXX")
+	return 0
 }
 
+// Pick sets the point to a random element, hashed from random bytes. It
+// embeds no data and returns all of it as remainder.
 func (p *point) Pick(data []byte, rand cipher.Stream) (abstract.Point, []byte) {
-	panic("XXX")
+	b := random.Bytes(32, rand)
+	C.element_from_hash(&p.e[0], unsafe.Pointer(&b[0]), C.int(len(b)))
+	return p, data
 }
 
 func (p *point) Data() ([]byte, error) {
-	panic("XXX")
+	return nil, errors.New("pbc: no data embedded in pairing elements")
+}
+
+func (p *point) Set(a abstract.Point) abstract.Point {
+	C.element_set(&p.e[0], &a.(*point).e[0])
+	return p
+}
+
+func (p *point) Clone() abstract.Point {
+	p2 := newCurvePoint()
+	C.element_init_same_as(&p2.e[0], &p.e[0])
+	C.element_set(&p2.e[0], &p.e[0])
+	return p2
 }
 
 func (p *point) Add(a, b abstract.Point) abstract.Point {
@@ -96,7 +119,7 @@ func (p *point) MarshalSize() int {
 }
 
 func (p *point) MarshalBinary() ([]byte, error) {
-	l := p.Len()
+	l := p.MarshalSize()
 	b := make([]byte, l)
 	a := C.element_to_bytes_compressed((*C.uchar)(unsafe.Pointer(&b[0])),
 		&p.e[0])
@@ -107,7 +130,7 @@ func (p *point) MarshalBinary() ([]byte, error) {
 }
 
 func (p *point) UnmarshalBinary(buf []byte) error {
-	l := p.Len()
+	l := p.MarshalSize()
 	if len(buf) != l {
 		return errors.New("Encoded element wrong length")
 	}
@@ -1,3 +1,4 @@
+//go:build pbc
 // +build pbc
 
 package pbc
@@ -15,6 +16,7 @@ import (
 
 	"github.com/dedis/crypto/abstract"
 	"github.com/dedis/crypto/group"
+	"github.com/dedis/crypto/random"
 )
 
 type scalar struct {
@@ -51,13 +53,20 @@ func (s *scalar) Set(x abstract.Scalar) abstract.Scalar {
 	return s
 }
 
+func (s *scalar) Clone() abstract.Scalar {
+	s2 := newScalar()
+	C.element_init_same_as(&s2.e[0], &s.e[0])
+	C.element_set(&s2.e[0], &s.e[0])
+	return s2
+}
+
 func (s *scalar) Zero() abstract.Scalar {
 	C.element_set0(&s.e[0])
 	return s
 }
 
 func (s *scalar) One() abstract.Scalar {
-	C.element_set0(&s.e[0])
+	C.element_set1(&s.e[0])
 	return s
 }
 
@@ -74,8 +83,28 @@ func (s *scalar) SetInt64(v int64) abstract.Scalar {
 	return s
 }
 
+// Pick reduces random bytes beyond the length of the order modulo the order,
+// which makes the bias negligible.
 func (s *scalar) Pick(rand cipher.Stream) abstract.Scalar {
-	panic("XXX")
+	return s.SetBytes(random.Bytes(s.MarshalSize()+16, rand))
+}
+
+// SetBytes sets the scalar to the big-endian integer a modulo the order.
+func (s *scalar) SetBytes(a []byte) abstract.Scalar {
+	var z C.mpz_t
+	C.mpz_init(&z[0])
+	if len(a) > 0 {
+		C.mpz_import(&z[0], C.size_t(len(a)), 1, 1, 1, 0, unsafe.Pointer(&a[0]))
+	}
+	C.element_set_mpz(&s.e[0], &z[0])
+	C.mpz_clear(&z[0])
+	return s
+}
+
+// Bytes returns the big-endian encoding of the scalar.
+func (s *scalar) Bytes() []byte {
+	b, _ := s.MarshalBinary()
+	return b
 }
 
 func (s *scalar) Add(a, b abstract.Scalar) abstract.Scalar {
@@ -113,7 +142,7 @@ func (s *scalar) MarshalSize() int {
 }
 
 func (s *scalar) MarshalBinary() ([]byte, error) {
-	l := s.Len()
+	l := s.MarshalSize()
 	b := make([]byte, l)
 	a := C.element_to_bytes((*C.uchar)(unsafe.Pointer(&b[0])),
 		&s.e[0])
@@ -124,7 +153,7 @@ func (s *scalar) MarshalBinary() ([]byte, error) {
 }
 
 func (s *scalar) UnmarshalBinary(buf []byte) error {
-	l := s.Len()
+	l := s.MarshalSize()
 	if len(buf) != l {
 		return errors.New("Encoded element wrong length")
 	}
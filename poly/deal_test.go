@@ -1125,18 +1125,18 @@ func TestStateRevealShare(t *testing.T) {
 // Tests all the string functions. Simply calls them to make sure they return.
 func TestString(t *testing.T) {
 	sig := basicDeal.sign(0, insurerKeys[0], sigMsg)
-	sig.String()
+	_ = sig.String()
 
 	bp, _ := basicDeal.blame(0, insurerKeys[0])
-	bp.String()
+	_ = bp.String()
 
-	basicDeal.String()
+	_ = basicDeal.String()
 
 	response := new(Response).constructSignatureResponse(sig)
-	response.String()
+	_ = response.String()
 
 	response = new(Response).constructBlameProofResponse(bp)
-	response.String()
+	_ = response.String()
 }
 
 func TestDealAbstractEncoding(t *testing.T) {
@@ -112,9 +112,9 @@ func (r *Receiver) AddDeal(index int, deal *Deal) (*Response, error) {
 // it will throw an error if something is wrong such as not enough Dealers received
 // The shared secret can be computed when all deals have been sent and
 // basically consists of a
-// 1. Public Polynomial which is basically the sums of all Dealers's polynomial
-// 2. Share of the global Private Polynomial (which is to never be computed directly), which is
-// 		basically SUM of fj(i) for a receiver i
+//  1. Public Polynomial which is basically the sums of all Dealers's polynomial
+//  2. Share of the global Private Polynomial (which is to never be computed directly), which is
+//     basically SUM of fj(i) for a receiver i
 func (r *Receiver) ProduceSharedSecret() (*SharedSecret, error) {
 	if len(r.deals) < 1 {
 		return nil, errors.New("Receiver has 0 Dealers in its data.Can't produce SharedSecret.")
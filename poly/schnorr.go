@@ -14,19 +14,21 @@ import (
 // and will implement the necessary methods.
 // You can setup a schnorr struct with a LongTerm shared secret
 // and when you want to sign something, you will have to:
-//  - Start a new round specifying the random shared secret chosen and the message to sign
-//  - Generate the partial signature of the current node
-//  - Collect every others partial signature
-//  - Generate the signature
-//  - Do whatever you want to do with
-//  - Start a new round with the same schnorr struct
+//   - Start a new round specifying the random shared secret chosen and the message to sign
+//   - Generate the partial signature of the current node
+//   - Collect every others partial signature
+//   - Generate the signature
+//   - Do whatever you want to do with
+//   - Start a new round with the same schnorr struct
+//
 // If you want to verify a given signature, use
 // schnorr.VerifySignature(SchnorrSig, msg)
 // CAREFUL: your schnorr signature is a LONG TERM signature, you must keep the same during
 // all rounds, else you won't be able to verify any signatures. The following have to stay
 // the same:
-//  - LongTerm sharedSecret
-//  - PolyInfo
+//   - LongTerm sharedSecret
+//   - PolyInfo
+//
 // If you know these are the same throughout differents rounds, you can create many schnorr structs. This is
 // definitly NOT the way it is intented to be used, so use it at your own risks.
 type Schnorr struct {
@@ -70,8 +72,8 @@ type SchnorrPartialSig struct {
 // SchnorrSig represents the final signature of a distribtued threshold schnorr signature
 // which can be verified against a message
 // This struct is not intended to be constructed manually but can be:
-//  - produced by the Schnorr struct
-//  - verified against a Schnorr struct
+//   - produced by the Schnorr struct
+//   - verified against a Schnorr struct
 type SchnorrSig struct {
 
 	// the signature itself
@@ -164,9 +166,10 @@ func (s *Schnorr) index() int {
 // Reveals the partial signature for this peer
 // Si = Ri + H(m || V) * Pi
 // with :
-//  - Ri = share of the random secret for peer i
-//  - V  = public commitment of the random secret (i.e. Public random poly evaluated at point 0 )
-//  - Pi = share of the longterm secret for peer i
+//   - Ri = share of the random secret for peer i
+//   - V  = public commitment of the random secret (i.e. Public random poly evaluated at point 0 )
+//   - Pi = share of the longterm secret for peer i
+//
 // This signature is to be sent to each other peer
 func (s *Schnorr) RevealPartialSig() *SchnorrPartialSig {
 	hash := s.suite.Scalar().Set(*s.hash)
@@ -187,15 +190,16 @@ func (s *Schnorr) RevealPartialSig() *SchnorrPartialSig {
 // Receives a signature from other peers,
 // adds it to its list of partial signatures and verifies it
 // It returns an error if
-// - it can not validate this given partial signature
-//   against the longterm and random shared secret
-// - there is already a partial signature added for this index
+//   - it can not validate this given partial signature
+//     against the longterm and random shared secret
+//   - there is already a partial signature added for this index
+//
 // NOTE : let s = RevealPartialSig(), s is NOT added automatically to the
 // set of partial signature, for now you have to do it yourself by calling
 // AddPartialSig(s)
 func (s *Schnorr) AddPartialSig(ps *SchnorrPartialSig) error {
 	if ps.Index >= s.info.N {
-		return errors.New(fmt.Sprintf("Cannot add signature with index %d whereas schnorr could have max %s partial signatures", ps.Index, s.info.N))
+		return errors.New(fmt.Sprintf("Cannot add signature with index %d whereas schnorr could have max %d partial signatures", ps.Index, s.info.N))
 	}
 	if s.partials[ps.Index] != nil {
 		return errors.New(fmt.Sprintf("A Partial Signature has already been added for this index %d", ps.Index))
@@ -241,8 +245,8 @@ func (s *Schnorr) Sig() (*SchnorrSig, error) {
 // NOTE: This belongs to the schnorr structs however it can be called at any time you want.
 // This check is static, meaning it only needs the longterm shared secret, and the signature to
 // check. Think of the schnorr signature as a black box having two inputs:
-//  - a message to be signed + a random secret ==> NewRound
-//  - a message + a signature to check on ==> VerifySchnorrSig
+//   - a message to be signed + a random secret ==> NewRound
+//   - a message + a signature to check on ==> VerifySchnorrSig
 func (s *Schnorr) VerifySchnorrSig(sig *SchnorrSig, h hash.Hash) error {
 	// gamma * G
 	left := s.suite.Point().Mul(s.suite.Point().Base(), *sig.Signature)
@@ -233,7 +233,7 @@ func TestPriSharesxCoord(t *testing.T) {
 		}
 	}
 	if c < k {
-		t.Error("Expected %v points to be made.", k)
+		t.Errorf("Expected %v points to be made.", k)
 	}
 
 	// Error handling
@@ -494,7 +494,7 @@ func TestPubSharesxCoord(t *testing.T) {
 		}
 	}
 	if c < testPubSharesGl.k {
-		t.Error("Expected %v points to be made.", k)
+		t.Errorf("Expected %v points to be made.", k)
 	}
 
 	// Error handling
@@ -1,20 +1,33 @@
 // This package provides functionality to create and verify non-interactive
 // zero-knowledge (NIZK) proofs for the equality (EQ) of discrete logarithms (DL).
 // This means, for two values xG and xH one can check that
-//   log_{G}(xG) == log_{H}(xH)
+//
+//	log_{G}(xG) == log_{H}(xH)
+//
 // without revealing the secret value x.
 package proof
 
 import (
+	"context"
+	"crypto/cipher"
 	"errors"
+	"fmt"
 
 	"github.com/dedis/crypto/abstract"
+	"github.com/dedis/crypto/group"
 	"github.com/dedis/crypto/hash"
+	"github.com/dedis/crypto/metrics"
 	"github.com/dedis/crypto/random"
 )
 
-var errorDifferentLengths = errors.New("inputs of different lengths")
-var errorInvalidProof = errors.New("invalid proof")
+// Errors returned by this package, possibly wrapped with additional context.
+// Use errors.Is to test for them.
+var (
+	// ErrDifferentLengths is returned when batch inputs do not match in size.
+	ErrDifferentLengths = errors.New("inputs of different lengths")
+	// ErrInvalidProof is returned when a proof fails to verify.
+	ErrInvalidProof = errors.New("invalid proof")
+)
 
 // DLEQProof represents a NIZK dlog-equality proof.
 type DLEQProof struct {
@@ -24,27 +37,65 @@ type DLEQProof struct {
 	VH abstract.Point  // public commitment with respect to base point H
 }
 
+// Equal checks equality of two proofs p and q.
+func (p *DLEQProof) Equal(q *DLEQProof) bool {
+	if p == nil || q == nil {
+		return p == q
+	}
+	return group.ScalarEqual(p.C, q.C) && group.ScalarEqual(p.R, q.R) &&
+		group.PointEqual(p.VG, q.VG) && group.PointEqual(p.VH, q.VH)
+}
+
 // NewDLEQProof computes a new NIZK dlog-equality proof for the scalar x with
 // respect to base points G and H. It therefore randomly selects a commitment v
 // and then computes the challenge c = H(xG,xH,vG,vH) and response r = v - cx.
 // Besides the proof, this function also returns the encrypted base points xG
 // and xH.
 func NewDLEQProof(suite abstract.Suite, G abstract.Point, H abstract.Point, x abstract.Scalar) (proof *DLEQProof, xG abstract.Point, xH abstract.Point, err error) {
+	return NewDLEQProofTagged(suite, G, H, x, nil)
+}
+
+// NewDLEQProofTagged is like NewDLEQProof but binds the proof to tag by
+// computing the challenge as c = H(tag,xG,xH,vG,vH). Such a proof is only
+// accepted by VerifyTagged with the same tag.
+func NewDLEQProofTagged(suite abstract.Suite, G abstract.Point, H abstract.Point, x abstract.Scalar, tag []byte) (proof *DLEQProof, xG abstract.Point, xH abstract.Point, err error) {
+	return newDLEQProof(suite, G, H, x, tag, random.Stream)
+}
+
+// NewDLEQProofTaggedWith is like NewDLEQProofTagged but picks the commitment
+// from rand, e.g., a seeded stream for reproducible runs.
+func NewDLEQProofTaggedWith(suite abstract.Suite, G abstract.Point, H abstract.Point, x abstract.Scalar, tag []byte, rand cipher.Stream) (proof *DLEQProof, xG abstract.Point, xH abstract.Point, err error) {
+	return newDLEQProof(suite, G, H, x, tag, rand)
+}
+
+// NewDLEQProofDerandomized is like NewDLEQProofTagged but derives the
+// commitment v from the device key, the witness x and the statement
+// (G, H, tag) with NonceStream instead of picking it at random.
+func NewDLEQProofDerandomized(suite abstract.Suite, key []byte, G abstract.Point, H abstract.Point, x abstract.Scalar, tag []byte) (proof *DLEQProof, xG abstract.Point, xH abstract.Point, err error) {
+	rand, err := NonceStream(suite, key, x, G, H, tag)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return newDLEQProof(suite, G, H, x, tag, rand)
+}
+
+func newDLEQProof(suite abstract.Suite, G abstract.Point, H abstract.Point, x abstract.Scalar, tag []byte, rand cipher.Stream) (proof *DLEQProof, xG abstract.Point, xH abstract.Point, err error) {
+	defer metrics.Start("proof.NewDLEQProof").End(&err)
+
 	// Encrypt base points with secret
 	xG = suite.Point().Mul(G, x)
 	xH = suite.Point().Mul(H, x)
 
 	// Commitment
-	v := suite.Scalar().Pick(random.Stream)
+	v := suite.Scalar().Pick(rand)
 	vG := suite.Point().Mul(G, v)
 	vH := suite.Point().Mul(H, v)
 
 	// Challenge
-	cb, err := hash.Structures(suite.Hash(), xG, xH, vG, vH)
+	c, err := challenge(suite, tag, xG, xH, vG, vH)
 	if err != nil {
 		return nil, nil, nil, err
 	}
-	c := suite.Scalar().Pick(suite.Cipher(cb))
 
 	// Response
 	r := suite.Scalar()
@@ -53,12 +104,37 @@ func NewDLEQProof(suite abstract.Suite, G abstract.Point, H abstract.Point, x ab
 	return &DLEQProof{c, r, vG, vH}, xG, xH, nil
 }
 
+// challenge computes the Fiat-Shamir challenge H(tag,xG,xH,vG,vH).
+func challenge(suite abstract.Suite, tag []byte, xG, xH, vG, vH interface{}) (abstract.Scalar, error) {
+	h := suite.Hash()
+	h.Write(tag)
+	cb, err := hash.Structures(h, xG, xH, vG, vH)
+	if err != nil {
+		return nil, err
+	}
+	return suite.Scalar().Pick(suite.Cipher(cb)), nil
+}
+
 // NewDLEQProofBatch computes lists of NIZK dlog-equality proofs and of
 // encrypted base points xG and xH. Note that the challenge is computed over all
 // input values.
 func NewDLEQProofBatch(suite abstract.Suite, G []abstract.Point, H []abstract.Point, secrets []abstract.Scalar) (proof []*DLEQProof, xG []abstract.Point, xH []abstract.Point, err error) {
+	return NewDLEQProofBatchContext(context.Background(), suite, G, H, secrets)
+}
+
+// NewDLEQProofBatchContext is like NewDLEQProofBatch but aborts with the
+// context's error once the context is done.
+func NewDLEQProofBatchContext(ctx context.Context, suite abstract.Suite, G []abstract.Point, H []abstract.Point, secrets []abstract.Scalar) (proof []*DLEQProof, xG []abstract.Point, xH []abstract.Point, err error) {
+	return NewDLEQProofBatchWith(ctx, suite, G, H, secrets, random.Stream)
+}
+
+// NewDLEQProofBatchWith is like NewDLEQProofBatchContext but picks the
+// commitments from rand, e.g., a seeded stream for reproducible runs.
+func NewDLEQProofBatchWith(ctx context.Context, suite abstract.Suite, G []abstract.Point, H []abstract.Point, secrets []abstract.Scalar, rand cipher.Stream) (proof []*DLEQProof, xG []abstract.Point, xH []abstract.Point, err error) {
+	defer metrics.Start("proof.NewDLEQProofBatch").End(&err)
+
 	if len(G) != len(H) || len(H) != len(secrets) {
-		return nil, nil, nil, errorDifferentLengths
+		return nil, nil, nil, fmt.Errorf("proof: %d base points G, %d base points H and %d secrets: %w", len(G), len(H), len(secrets), ErrDifferentLengths)
 	}
 
 	n := len(secrets)
@@ -70,22 +146,25 @@ func NewDLEQProofBatch(suite abstract.Suite, G []abstract.Point, H []abstract.Po
 	vH := make([]abstract.Point, n)
 
 	for i, x := range secrets {
+		if err := ctx.Err(); err != nil {
+			return nil, nil, nil, err
+		}
+
 		// Encrypt base points with secrets
 		xG[i] = suite.Point().Mul(G[i], x)
 		xH[i] = suite.Point().Mul(H[i], x)
 
 		// Commitments
-		v[i] = suite.Scalar().Pick(random.Stream)
+		v[i] = suite.Scalar().Pick(rand)
 		vG[i] = suite.Point().Mul(G[i], v[i])
 		vH[i] = suite.Point().Mul(H[i], v[i])
 	}
 
 	// Collective challenge
-	cb, err := hash.Structures(suite.Hash(), xG, xH, vG, vH)
+	c, err := challenge(suite, nil, xG, xH, vG, vH)
 	if err != nil {
 		return nil, nil, nil, err
 	}
-	c := suite.Scalar().Pick(suite.Cipher(cb))
 
 	// Responses
 	for i, x := range secrets {
@@ -99,9 +178,12 @@ func NewDLEQProofBatch(suite abstract.Suite, G []abstract.Point, H []abstract.Po
 
 // Verify examines the validity of the NIZK dlog-equality proof.
 // The proof is valid if the following two conditions hold:
-//   vG == rG + c(xG)
-//   vH == rH + c(xH)
-func (p *DLEQProof) Verify(suite abstract.Suite, G abstract.Point, H abstract.Point, xG abstract.Point, xH abstract.Point) error {
+//
+//	vG == rG + c(xG)
+//	vH == rH + c(xH)
+func (p *DLEQProof) Verify(suite abstract.Suite, G abstract.Point, H abstract.Point, xG abstract.Point, xH abstract.Point) (err error) {
+	defer metrics.Start("proof.DLEQProof.Verify").End(&err)
+
 	rG := suite.Point().Mul(G, p.R)
 	rH := suite.Point().Mul(H, p.R)
 	cxG := suite.Point().Mul(xG, p.C)
@@ -109,7 +191,24 @@ func (p *DLEQProof) Verify(suite abstract.Suite, G abstract.Point, H abstract.Po
 	a := suite.Point().Add(rG, cxG)
 	b := suite.Point().Add(rH, cxH)
 	if !(p.VG.Equal(a) && p.VH.Equal(b)) {
-		return errorInvalidProof
+		return ErrInvalidProof
 	}
 	return nil
 }
+
+// VerifyTagged examines the validity of a NIZK dlog-equality proof created by
+// NewDLEQProofTagged. In addition to the conditions checked by Verify, it
+// recomputes the challenge and requires c == H(tag,xG,xH,vG,vH), so a proof
+// made for a different tag is rejected.
+func (p *DLEQProof) VerifyTagged(suite abstract.Suite, G abstract.Point, H abstract.Point, xG abstract.Point, xH abstract.Point, tag []byte) (err error) {
+	defer metrics.Start("proof.DLEQProof.VerifyTagged").End(&err)
+
+	c, err := challenge(suite, tag, xG, xH, p.VG, p.VH)
+	if err != nil {
+		return err
+	}
+	if !c.Equal(p.C) {
+		return ErrInvalidProof
+	}
+	return p.Verify(suite, G, H, xG, xH)
+}
@@ -1,6 +1,7 @@
 package proof
 
 import (
+	"errors"
 	"fmt"
 	"testing"
 
@@ -24,6 +25,23 @@ func TestDLEQProof(t *testing.T) {
 	}
 }
 
+func TestDLEQProofTagged(t *testing.T) {
+	suite := edwards.NewAES128SHA256Ed25519(false)
+	x := suite.Scalar().Pick(random.Stream)
+	g, _ := suite.Point().Pick([]byte("G"), random.Stream)
+	h, _ := suite.Point().Pick([]byte("H"), random.Stream)
+	proof, xG, xH, err := NewDLEQProofTagged(suite, g, h, x, []byte("epoch 1"))
+	require.Nil(t, err)
+	require.Nil(t, proof.VerifyTagged(suite, g, h, xG, xH, []byte("epoch 1")))
+	require.Equal(t, ErrInvalidProof, proof.VerifyTagged(suite, g, h, xG, xH, []byte("epoch 2")))
+	require.Equal(t, ErrInvalidProof, proof.VerifyTagged(suite, g, h, xG, xH, nil))
+
+	// Untagged proofs verify with an empty tag
+	proof, xG, xH, err = NewDLEQProof(suite, g, h, x)
+	require.Nil(t, err)
+	require.Nil(t, proof.VerifyTagged(suite, g, h, xG, xH, nil))
+}
+
 func TestDLEQProofBatch(t *testing.T) {
 	suite := edwards.NewAES128SHA256Ed25519(false)
 	n := 10
@@ -56,5 +74,28 @@ func TestDLEQLengths(t *testing.T) {
 	// Remove an element to make the test fail
 	x = append(x[:5], x[6:]...)
 	_, _, _, err := NewDLEQProofBatch(suite, g, h, x)
-	require.Equal(t, err, errorDifferentLengths)
+	require.True(t, errors.Is(err, ErrDifferentLengths))
+}
+
+func TestDLEQProofDerandomized(t *testing.T) {
+	suite := edwards.NewAES128SHA256Ed25519(false)
+	key := []byte("device key")
+	x := suite.Scalar().Pick(random.Stream)
+	g, _ := suite.Point().Pick([]byte("G"), random.Stream)
+	h, _ := suite.Point().Pick([]byte("H"), random.Stream)
+
+	p1, xG, xH, err := NewDLEQProofDerandomized(suite, key, g, h, x, []byte("tag"))
+	require.Nil(t, err)
+	require.Nil(t, p1.VerifyTagged(suite, g, h, xG, xH, []byte("tag")))
+	p2, _, _, err := NewDLEQProofDerandomized(suite, key, g, h, x, []byte("tag"))
+	require.Nil(t, err)
+	require.True(t, p1.Equal(p2))
+
+	// A different statement yields an independent nonce
+	p3, _, _, err := NewDLEQProofDerandomized(suite, key, g, h, x, []byte("other"))
+	require.Nil(t, err)
+	require.False(t, p1.VG.Equal(p3.VG))
+
+	_, err = NonceStream(suite, key, x, 42)
+	require.Equal(t, errorStatement, err)
 }
@@ -115,7 +115,6 @@ func (c *hashVerifier) PubRand(data ...interface{}) error {
 // this can be random.Stream to use fresh random bits,
 // or a pseudorandom stream based on a secret seed
 // to create deterministically reproducible proofs.
-//
 func HashProve(suite abstract.Suite, protocolName string,
 	random abstract.Cipher, prover Prover) ([]byte, error) {
 	ctx := newHashProver(suite, protocolName, random)
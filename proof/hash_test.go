@@ -10,7 +10,7 @@ import (
 
 // This example shows how to build classic ElGamal-style digital signatures
 // using the Camenisch/Stadler proof framework and HashProver.
-func ExampleHashProve_1() {
+func ExampleHashProve_elGamal() {
 
 	// Crypto setup
 	suite := nist.NewAES128SHA256P256()
@@ -76,8 +76,7 @@ func ExampleHashProve_1() {
 // This example implementation is less space-efficient, however,
 // because it uses the generic HashProver for Fiat-Shamir noninteractivity
 // instead of Liu/Wei/Wong's customized hash-ring structure.
-//
-func ExampleHashProve_2() {
+func ExampleHashProve_linkableRing() {
 
 	// Crypto setup
 	suite := nist.NewAES128SHA256P256()
@@ -0,0 +1,50 @@
+package proof
+
+import (
+	"crypto/cipher"
+	"encoding"
+	"encoding/binary"
+	"errors"
+
+	"github.com/dedis/crypto/abstract"
+)
+
+var errorStatement = errors.New("statement element is neither []byte nor BinaryMarshaler")
+
+// NonceStream returns a stream for picking the nonces of a proof about the
+// given witness and statement. The stream is derived deterministically from a
+// per-device key, the witness and the statement, like the nonces of RFC 6979
+// and EdDSA, so that a broken random number generator cannot leak the witness
+// through nonce reuse: the same witness and statement always yield the same
+// nonces, whereas any change of the statement yields independent ones. The
+// device key must be kept secret. Elements of the statement are either
+// []byte or implement encoding.BinaryMarshaler.
+func NonceStream(suite abstract.Suite, key []byte, witness abstract.Scalar, statement ...interface{}) (cipher.Stream, error) {
+	h := suite.Hash()
+	h.Write([]byte("proof-nonce"))
+	write := func(b []byte) {
+		binary.Write(h, binary.BigEndian, uint64(len(b)))
+		h.Write(b)
+	}
+	write(key)
+	w, err := witness.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	write(w)
+	for _, s := range statement {
+		switch s := s.(type) {
+		case []byte:
+			write(s)
+		case encoding.BinaryMarshaler:
+			b, err := s.MarshalBinary()
+			if err != nil {
+				return nil, err
+			}
+			write(b)
+		default:
+			return nil, errorStatement
+		}
+	}
+	return suite.Cipher(h.Sum(nil)), nil
+}
@@ -6,7 +6,7 @@
 package proof
 
 import (
-	"errors"
+	"fmt"
 
 	"github.com/dedis/crypto/abstract"
 )
@@ -149,7 +149,6 @@ type repPred struct {
 // A Rep statement of the form Rep(P,x1,B1,...,xn,Bn)
 // indicates that the prover knows secrets x1,...,xn
 // such that point P is the sum x1*B1+...+xn*Bn.
-//
 func Rep(P string, SB ...string) Predicate {
 	if len(SB)&1 != 0 {
 		panic("mismatched Scalar")
@@ -302,7 +301,7 @@ func (rp *repPred) verify(prf *proof, c abstract.Scalar, pr []abstract.Scalar) e
 		V.Add(V, P)
 	}
 	if !V.Equal(vp.V) {
-		return errors.New("invalid proof: commit mismatch")
+		return fmt.Errorf("%w: commit mismatch", ErrInvalidProof)
 	}
 
 	return nil
@@ -585,7 +584,7 @@ func (op *orPred) verify(prf *proof, c abstract.Scalar, pr []abstract.Scalar) er
 			csum.Add(csum, ci[i])
 		}
 		if !csum.Equal(c) {
-			return errors.New("invalid proof: bad sub-challenges")
+			return fmt.Errorf("%w: bad sub-challenges", ErrInvalidProof)
 		}
 
 	} else { // trivial single-sub OR
@@ -0,0 +1,131 @@
+// Package protocol provides a small, transport-agnostic execution model for
+// multi-round protocols such as distributed key generation, resharing or
+// randomness beacons. A protocol participant is a ProtocolState: it consumes
+// incoming messages through ProcessMessage, exposes the messages it wants to
+// send through PendingMessages and signals termination through Done. The
+// caller owns the transport and simply shuttles messages between the
+// participants' states, which keeps protocol implementations free of any
+// networking code and makes them easy to test in memory, see Run.
+//
+// The multi-round protocols of this library provide protocol states in
+// their packages: dkg.Party, feldman.DealerParty and feldman.VerifierParty,
+// beacon.Party and rotation.Party. Under faults their rounds end with a
+// SetTimeout method, which the caller invokes once the synchrony bound has
+// passed.
+package protocol
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Broadcast is the recipient of messages meant for every other participant.
+const Broadcast = -1
+
+// Some error definitions
+var errorNoProgress = errors.New("protocol stalled before all participants were done")
+var errorRecipient = errors.New("message for unknown recipient")
+
+// Message is a protocol message exchanged between participants, identified by
+// their index. The payload is a protocol-specific message type which the
+// transport is responsible for encoding.
+type Message struct {
+	From    int         // Index of the sender
+	To      int         // Index of the recipient or Broadcast
+	Payload interface{} // Protocol-specific content
+}
+
+// ProtocolState is the interface implemented by one participant of a
+// multi-round protocol.
+type ProtocolState interface {
+	// ProcessMessage handles a message received from another participant.
+	// An error signals an invalid message; the protocol state must remain
+	// usable so that the caller can continue with other messages.
+	ProcessMessage(msg *Message) error
+
+	// PendingMessages returns and removes the messages the participant wants
+	// to send.
+	PendingMessages() []*Message
+
+	// Done returns true once the participant has terminated.
+	Done() bool
+}
+
+// Outbox is a helper for ProtocolState implementations that collects
+// outgoing messages until they are fetched through PendingMessages.
+type Outbox struct {
+	msgs []*Message
+}
+
+// Send queues a message.
+func (o *Outbox) Send(msg *Message) {
+	o.msgs = append(o.msgs, msg)
+}
+
+// PendingMessages returns and removes all queued messages.
+func (o *Outbox) PendingMessages() []*Message {
+	msgs := o.msgs
+	o.msgs = nil
+	return msgs
+}
+
+// ProcessError reports a message that a participant rejected.
+type ProcessError struct {
+	Msg *Message // Rejected message
+	To  int      // Index of the participant that rejected it
+	Err error    // Error returned by ProcessMessage
+}
+
+func (e *ProcessError) Error() string {
+	return fmt.Sprintf("participant %d rejected message from %d: %v", e.To, e.Msg.From, e.Err)
+}
+
+// Run executes a protocol in memory by delivering the pending messages of
+// all participants, given by index, until every participant is done.
+// Broadcast messages are delivered to all participants except the sender.
+// Messages rejected by their recipient are collected and returned but do not
+// stop the execution. Run fails if the participants stop producing messages
+// before all of them are done.
+func Run(states []ProtocolState) ([]*ProcessError, error) {
+	var rejected []*ProcessError
+	deliver := func(msg *Message, to int) {
+		if err := states[to].ProcessMessage(msg); err != nil {
+			rejected = append(rejected, &ProcessError{msg, to, err})
+		}
+	}
+	for {
+		var queue []*Message
+		for _, s := range states {
+			queue = append(queue, s.PendingMessages()...)
+		}
+		if len(queue) == 0 {
+			if allDone(states) {
+				return rejected, nil
+			}
+			return rejected, errorNoProgress
+		}
+		for _, msg := range queue {
+			switch {
+			case msg.To == Broadcast:
+				for i := range states {
+					if i != msg.From {
+						deliver(msg, i)
+					}
+				}
+			case msg.To >= 0 && msg.To < len(states):
+				deliver(msg, msg.To)
+			default:
+				return rejected, errorRecipient
+			}
+		}
+	}
+}
+
+func allDone(states []ProtocolState) bool {
+	for _, s := range states {
+		if !s.Done() {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,75 @@
+package protocol
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sum is a toy protocol in which every participant broadcasts a value and
+// terminates once it knows the sum of all values.
+type sum struct {
+	Outbox
+	index, n int
+	values   map[int]int
+	total    int
+}
+
+func newSum(index, n, value int) *sum {
+	s := &sum{index: index, n: n, values: map[int]int{index: value}}
+	s.Send(&Message{From: index, To: Broadcast, Payload: value})
+	return s
+}
+
+func (s *sum) ProcessMessage(msg *Message) error {
+	v, ok := msg.Payload.(int)
+	if !ok || v < 0 {
+		return errors.New("invalid value")
+	}
+	if _, ok := s.values[msg.From]; ok {
+		return errors.New("duplicate value")
+	}
+	s.values[msg.From] = v
+	if len(s.values) == s.n {
+		for _, v := range s.values {
+			s.total += v
+		}
+	}
+	return nil
+}
+
+func (s *sum) Done() bool {
+	return len(s.values) == s.n
+}
+
+func TestRun(t *testing.T) {
+	n := 5
+	states := make([]ProtocolState, n)
+	for i := range states {
+		states[i] = newSum(i, n, i+1)
+	}
+	rejected, err := Run(states)
+	require.Nil(t, err)
+	assert.Empty(t, rejected)
+	for _, s := range states {
+		assert.Equal(t, 15, s.(*sum).total)
+	}
+}
+
+func TestRunRejectAndStall(t *testing.T) {
+	n := 3
+	states := make([]ProtocolState, n)
+	for i := range states {
+		states[i] = newSum(i, n, i)
+	}
+	// Participant 2 sends garbage instead of its value
+	states[2].PendingMessages()
+	states[2].(*sum).Send(&Message{From: 2, To: Broadcast, Payload: "garbage"})
+
+	rejected, err := Run(states)
+	assert.Equal(t, errorNoProgress, err)
+	require.Len(t, rejected, 2)
+	assert.Equal(t, 2, rejected[0].Msg.From)
+}
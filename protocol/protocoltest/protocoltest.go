@@ -0,0 +1,176 @@
+// Package protocoltest runs multi-party protocols implemented as
+// protocol.ProtocolState in memory under configurable faults, so that
+// protocol drivers can be integration-tested against dropped, corrupted and
+// replayed messages:
+//
+//	net := &Network{States: states, Behaviors: []Behavior{Drop(2), Replay(0)}}
+//	res, err := net.Run()
+//
+// Besides the generic network, the package provides NewPVSS, which sets up
+// the dealer and the trustees of a PVSS run as protocol states.
+package protocoltest
+
+import (
+	"errors"
+
+	"github.com/dedis/crypto/protocol"
+)
+
+// Some error definitions
+var errorNoProgress = errors.New("network stalled before all participants were done")
+var errorRounds = errors.New("round limit exceeded")
+var errorRecipient = errors.New("message for unknown recipient")
+
+// Behavior models a fault of the network or a misbehaving participant.
+// Apply is called for every delivery of a message to a recipient and returns
+// the messages to deliver instead: none to drop it, a modified copy to
+// corrupt it or several to replay it.
+type Behavior interface {
+	Apply(msg *protocol.Message, to int) []*protocol.Message
+}
+
+// BehaviorFunc adapts a function to a Behavior.
+type BehaviorFunc func(msg *protocol.Message, to int) []*protocol.Message
+
+// Apply calls f(msg, to).
+func (f BehaviorFunc) Apply(msg *protocol.Message, to int) []*protocol.Message {
+	return f(msg, to)
+}
+
+// Drop drops all messages sent by the participant from, as if it crashed
+// after its initialization.
+func Drop(from int) Behavior {
+	return BehaviorFunc(func(msg *protocol.Message, to int) []*protocol.Message {
+		if msg.From == from {
+			return nil
+		}
+		return []*protocol.Message{msg}
+	})
+}
+
+// DropLink drops all messages sent by the participant from to the participant
+// to, as on a broken link.
+func DropLink(from, to int) Behavior {
+	return BehaviorFunc(func(msg *protocol.Message, recipient int) []*protocol.Message {
+		if msg.From == from && recipient == to {
+			return nil
+		}
+		return []*protocol.Message{msg}
+	})
+}
+
+// Corrupt replaces the payload of every message sent by the participant from
+// by corrupt(payload). The function must not modify the payload in place,
+// since the same payload is delivered to several recipients.
+func Corrupt(from int, corrupt func(payload interface{}) interface{}) Behavior {
+	return BehaviorFunc(func(msg *protocol.Message, to int) []*protocol.Message {
+		if msg.From != from {
+			return []*protocol.Message{msg}
+		}
+		return []*protocol.Message{{From: msg.From, To: msg.To, Payload: corrupt(msg.Payload)}}
+	})
+}
+
+// Replay delivers every message sent by the participant from twice.
+func Replay(from int) Behavior {
+	return BehaviorFunc(func(msg *protocol.Message, to int) []*protocol.Message {
+		if msg.From != from {
+			return []*protocol.Message{msg}
+		}
+		return []*protocol.Message{msg, msg}
+	})
+}
+
+// Network is an in-memory network of protocol participants, given by index.
+// The behaviors are applied in order to every delivery.
+type Network struct {
+	States    []protocol.ProtocolState
+	Behaviors []Behavior
+	MaxRounds int // Maximum number of delivery rounds, or 0 for 1000
+}
+
+// Result summarizes a run of the network.
+type Result struct {
+	Rounds    int                      // Number of delivery rounds
+	Delivered int                      // Number of delivered messages
+	Dropped   int                      // Number of deliveries dropped by the behaviors
+	Rejected  []*protocol.ProcessError // Messages rejected by their recipient
+	Done      []bool                   // Termination of the participants
+}
+
+// Run delivers the pending messages of the participants in rounds, applying
+// the behaviors, until no messages are left. Like protocol.Run, it delivers
+// broadcasts to all participants but the sender and collects rejected
+// messages without stopping. It fails if some participant is not done once
+// no messages are left, e.g., because of dropped messages, or if the round
+// limit is exceeded; the result is returned in any case.
+func (n *Network) Run() (*Result, error) {
+	maxRounds := n.MaxRounds
+	if maxRounds == 0 {
+		maxRounds = 1000
+	}
+	res := &Result{Done: make([]bool, len(n.States))}
+	deliver := func(msg *protocol.Message, to int) {
+		msgs := []*protocol.Message{msg}
+		for _, b := range n.Behaviors {
+			var next []*protocol.Message
+			for _, m := range msgs {
+				next = append(next, b.Apply(m, to)...)
+			}
+			msgs = next
+		}
+		if len(msgs) == 0 {
+			res.Dropped++
+		}
+		for _, m := range msgs {
+			res.Delivered++
+			if err := n.States[to].ProcessMessage(m); err != nil {
+				res.Rejected = append(res.Rejected, &protocol.ProcessError{Msg: m, To: to, Err: err})
+			}
+		}
+	}
+	for {
+		var queue []*protocol.Message
+		for _, s := range n.States {
+			queue = append(queue, s.PendingMessages()...)
+		}
+		if len(queue) == 0 {
+			break
+		}
+		if res.Rounds == maxRounds {
+			n.done(res)
+			return res, errorRounds
+		}
+		res.Rounds++
+		for _, msg := range queue {
+			switch {
+			case msg.To == protocol.Broadcast:
+				for i := range n.States {
+					if i != msg.From {
+						deliver(msg, i)
+					}
+				}
+			case msg.To >= 0 && msg.To < len(n.States):
+				deliver(msg, msg.To)
+			default:
+				n.done(res)
+				return res, errorRecipient
+			}
+		}
+	}
+	if !n.done(res) {
+		return res, errorNoProgress
+	}
+	return res, nil
+}
+
+// done records the termination of the participants and reports whether all
+// of them are done.
+func (n *Network) done(res *Result) bool {
+	all := true
+	for i, s := range n.States {
+		res.Done[i] = s.Done()
+		all = all && res.Done[i]
+	}
+	return all
+}
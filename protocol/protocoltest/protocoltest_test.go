@@ -0,0 +1,90 @@
+package protocoltest
+
+import (
+	"testing"
+
+	"github.com/dedis/crypto/abstract"
+	"github.com/dedis/crypto/edwards"
+	"github.com/dedis/crypto/protocol"
+	"github.com/dedis/crypto/pvss"
+	"github.com/dedis/crypto/random"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var suite = edwards.NewAES128SHA256Ed25519(false)
+
+func runPVSS(t *testing.T, n, th int, behaviors ...Behavior) ([]*PVSSParty, abstract.Point, *Result, error) {
+	H, _ := suite.Point().Pick(nil, suite.Cipher([]byte("H")))
+	secret := suite.Scalar().Pick(random.Stream)
+	parties, err := NewPVSS(suite, H, n, th, 0, secret)
+	require.Nil(t, err)
+	net := &Network{States: States(parties), Behaviors: behaviors}
+	res, err := net.Run()
+	return parties, suite.Point().Mul(nil, secret), res, err
+}
+
+func TestPVSSHonest(t *testing.T) {
+	parties, secret, res, err := runPVSS(t, 5, 3)
+	require.Nil(t, err)
+	assert.Empty(t, res.Rejected)
+	assert.Equal(t, 0, res.Dropped)
+	for _, p := range parties {
+		assert.True(t, p.Secret().Equal(secret))
+	}
+}
+
+func TestPVSSFaults(t *testing.T) {
+	// A crashed trustee does not prevent the others from recovering
+	parties, secret, res, err := runPVSS(t, 5, 3, Drop(3))
+	require.Nil(t, err)
+	assert.True(t, res.Dropped > 0)
+	for _, p := range parties {
+		assert.True(t, p.Secret().Equal(secret))
+	}
+
+	// Corrupted decrypted shares are rejected
+	corrupt := Corrupt(2, func(payload interface{}) interface{} {
+		ds, ok := payload.(*pvss.PubVerShare)
+		if !ok {
+			return payload
+		}
+		bad := *ds
+		bad.S.V = suite.Point().Base()
+		return &bad
+	})
+	parties, secret, res, err = runPVSS(t, 5, 3, corrupt)
+	require.Nil(t, err)
+	assert.Len(t, res.Rejected, 4)
+	for _, p := range parties {
+		assert.True(t, p.Secret().Equal(secret))
+	}
+
+	// Replayed deals are rejected but do no harm
+	parties, secret, res, err = runPVSS(t, 4, 3, Replay(0))
+	require.Nil(t, err)
+	for _, r := range res.Rejected {
+		assert.Equal(t, pvss.ErrState, r.Err)
+	}
+	for _, p := range parties {
+		assert.True(t, p.Secret().Equal(secret))
+	}
+
+	// Without the dealer nobody recovers
+	_, _, res, err = runPVSS(t, 4, 3, Drop(0))
+	assert.Equal(t, errorNoProgress, err)
+	assert.Equal(t, []bool{false, false, false, false}, res.Done)
+}
+
+func TestNetworkLink(t *testing.T) {
+	parties, secret, _, err := runPVSS(t, 4, 3, DropLink(0, 1))
+	assert.Equal(t, errorNoProgress, err)
+	assert.Nil(t, parties[1].Secret())
+	assert.True(t, parties[2].Secret().Equal(secret))
+
+	// Messages to unknown recipients abort the run
+	net := &Network{States: States(parties)}
+	parties[0].Send(&protocol.Message{From: 0, To: 7})
+	_, err = net.Run()
+	assert.Equal(t, errorRecipient, err)
+}
@@ -0,0 +1,130 @@
+package protocoltest
+
+import (
+	"errors"
+
+	"github.com/dedis/crypto/abstract"
+	"github.com/dedis/crypto/protocol"
+	"github.com/dedis/crypto/pvss"
+	"github.com/dedis/crypto/random"
+)
+
+var errorPayload = errors.New("unexpected message payload")
+var errorDealer = errors.New("deal not sent by the dealer")
+
+// PVSSParty is a trustee of a PVSS run as protocol state. The dealer
+// broadcasts its deal; every trustee verifies the deal, broadcasts its
+// decrypted share and is done once it recovered the secret from a threshold
+// of valid decrypted shares. Payloads are *pvss.Deal and *pvss.PubVerShare.
+type PVSSParty struct {
+	protocol.Outbox
+	p       *pvss.Participant
+	dealer  int
+	dealt   bool
+	pending []*pvss.PubVerShare // Decrypted shares received before the deal
+	secret  abstract.Point
+}
+
+// NewPVSS sets up a PVSS run among n trustees with fresh keys, threshold t
+// and commitment base H, in which the trustee with index dealer deals the
+// secret. It returns the trustees as protocol states.
+func NewPVSS(suite abstract.Suite, H abstract.Point, n, t, dealer int, secret abstract.Scalar) ([]*PVSSParty, error) {
+	x := make([]abstract.Scalar, n)
+	X := make([]abstract.Point, n)
+	for i := range x {
+		x[i] = suite.Scalar().Pick(random.Stream)
+		X[i] = suite.Point().Mul(nil, x[i])
+	}
+	parties := make([]*PVSSParty, n)
+	for i := range parties {
+		p, err := pvss.NewParticipant(suite, H, X, x[i], t)
+		if err != nil {
+			return nil, err
+		}
+		parties[i] = &PVSSParty{p: p, dealer: dealer}
+	}
+	deal, err := pvss.NewDealer(suite, H, X, t).Deal(secret)
+	if err != nil {
+		return nil, err
+	}
+	d := parties[dealer]
+	d.Send(&protocol.Message{From: dealer, To: protocol.Broadcast, Payload: deal})
+	if err := d.processDeal(deal); err != nil {
+		return nil, err
+	}
+	return parties, nil
+}
+
+// States returns the parties as protocol states, e.g., for a Network.
+func States(parties []*PVSSParty) []protocol.ProtocolState {
+	states := make([]protocol.ProtocolState, len(parties))
+	for i, p := range parties {
+		states[i] = p
+	}
+	return states
+}
+
+// ProcessMessage handles a deal or a decrypted share.
+func (p *PVSSParty) ProcessMessage(msg *protocol.Message) error {
+	switch payload := msg.Payload.(type) {
+	case *pvss.Deal:
+		if msg.From != p.dealer {
+			return errorDealer
+		}
+		return p.processDeal(payload)
+	case *pvss.PubVerShare:
+		if !p.dealt {
+			p.pending = append(p.pending, payload)
+			return nil
+		}
+		return p.processDecShare(payload)
+	}
+	return errorPayload
+}
+
+func (p *PVSSParty) processDeal(deal *pvss.Deal) error {
+	if _, err := p.p.ProcessDeal(deal); err != nil {
+		return err
+	}
+	p.dealt = true
+	ds, err := p.p.DecShare()
+	if err != nil {
+		return err
+	}
+	p.Send(&protocol.Message{From: p.p.Index(), To: protocol.Broadcast, Payload: ds})
+	pending := p.pending
+	p.pending = nil
+	for _, ds := range pending {
+		// Errors of early shares are not attributable to this message
+		p.processDecShare(ds)
+	}
+	p.recover()
+	return nil
+}
+
+func (p *PVSSParty) processDecShare(ds *pvss.PubVerShare) error {
+	if err := p.p.ProcessDecShare(ds); err != nil {
+		return err
+	}
+	p.recover()
+	return nil
+}
+
+func (p *PVSSParty) recover() {
+	if p.secret != nil {
+		return
+	}
+	if secret, err := p.p.RecoverSecret(); err == nil {
+		p.secret = secret
+	}
+}
+
+// Done returns true once the secret is recovered.
+func (p *PVSSParty) Done() bool {
+	return p.secret != nil
+}
+
+// Secret returns the recovered secret, or nil if the party is not done.
+func (p *PVSSParty) Secret() abstract.Point {
+	return p.secret
+}
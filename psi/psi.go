@@ -0,0 +1,251 @@
+// Package psi implements Diffie-Hellman based private set intersection
+// (ECDH-PSI). A client and a server each hold a set of items. Both parties
+// hash their items to group elements and blind them with a secret exponent;
+// since exponentiation commutes, an item held by both parties maps to the same
+// doubly blinded element, while all other elements look random. At the end the
+// client learns which of its items are also held by the server, and the server
+// only learns the size of the client's set.
+//
+// The package exposes the low-level building blocks (Party, Batch) as well as
+// a two-party driver (Client, Server) that runs the protocol over any
+// io.ReadWriter, e.g. a net.Conn. The driver streams the sets in batches so
+// that the server's set never has to be held in memory by the client.
+package psi
+
+import (
+	"crypto/cipher"
+	"encoding/binary"
+	"errors"
+	"io"
+
+	"github.com/dedis/crypto/abstract"
+)
+
+// BatchSize is the maximum number of elements the driver sends per batch.
+const BatchSize = 1024
+
+// Some error definitions
+var errorInvalidPoint = errors.New("invalid blinded element")
+var errorBatchSize = errors.New("invalid batch size")
+var errorBatchMismatch = errors.New("response batch does not match request")
+
+// Party holds the secret blinding exponent of one side of the protocol.
+type Party struct {
+	suite abstract.Suite
+	key   abstract.Scalar
+}
+
+// NewParty creates a party with a fresh blinding exponent.
+func NewParty(suite abstract.Suite, rand cipher.Stream) *Party {
+	return &Party{suite, suite.Scalar().Pick(rand)}
+}
+
+// hashItem maps an item to a group element of unknown discrete logarithm.
+func hashItem(suite abstract.Suite, item []byte) abstract.Point {
+	h := suite.Hash()
+	h.Write([]byte("psi-item"))
+	h.Write(item)
+	P, _ := suite.Point().Pick(nil, suite.Cipher(h.Sum(nil)))
+	return P
+}
+
+// Blind hashes the items to group elements and blinds them with the party's
+// exponent.
+func (p *Party) Blind(items [][]byte) []abstract.Point {
+	out := make([]abstract.Point, len(items))
+	for i, item := range items {
+		out[i] = p.suite.Point().Mul(hashItem(p.suite, item), p.key)
+	}
+	return out
+}
+
+// Reblind applies the party's exponent to elements blinded by the other
+// party. It rejects the neutral element, which would reveal the exponent's
+// effect on a known value.
+func (p *Party) Reblind(points []abstract.Point) ([]abstract.Point, error) {
+	null := p.suite.Point().Null()
+	out := make([]abstract.Point, len(points))
+	for i, P := range points {
+		if P == nil || P.Equal(null) {
+			return nil, errorInvalidPoint
+		}
+		out[i] = p.suite.Point().Mul(P, p.key)
+	}
+	return out, nil
+}
+
+// Matcher accumulates the client's doubly blinded items and tests the
+// server's doubly blinded items against them.
+type Matcher struct {
+	items [][]byte
+	index map[string]int
+	found map[int]bool
+}
+
+// NewMatcher creates a matcher for the client's items and their doubly
+// blinded counterparts, given in the same order.
+func NewMatcher(items [][]byte, double []abstract.Point) (*Matcher, error) {
+	if len(items) != len(double) {
+		return nil, errorBatchMismatch
+	}
+	m := &Matcher{items, make(map[string]int), make(map[int]bool)}
+	for i, P := range double {
+		b, err := P.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		m.index[string(b)] = i
+	}
+	return m, nil
+}
+
+// Match records which of the server's doubly blinded items correspond to
+// client items.
+func (m *Matcher) Match(double []abstract.Point) error {
+	for _, P := range double {
+		b, err := P.MarshalBinary()
+		if err != nil {
+			return err
+		}
+		if i, ok := m.index[string(b)]; ok {
+			m.found[i] = true
+		}
+	}
+	return nil
+}
+
+// Intersection returns the client items found so far, in their original
+// order.
+func (m *Matcher) Intersection() [][]byte {
+	var out [][]byte
+	for i, item := range m.items {
+		if m.found[i] {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+// WriteBatch writes a length-prefixed batch of elements to w. An empty batch
+// marks the end of a stream.
+func WriteBatch(w io.Writer, points []abstract.Point) error {
+	var l [4]byte
+	binary.BigEndian.PutUint32(l[:], uint32(len(points)))
+	if _, err := w.Write(l[:]); err != nil {
+		return err
+	}
+	for _, P := range points {
+		if _, err := P.MarshalTo(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReadBatch reads a batch written by WriteBatch.
+func ReadBatch(suite abstract.Suite, r io.Reader) ([]abstract.Point, error) {
+	var l [4]byte
+	if _, err := io.ReadFull(r, l[:]); err != nil {
+		return nil, err
+	}
+	n := binary.BigEndian.Uint32(l[:])
+	if n > BatchSize {
+		return nil, errorBatchSize
+	}
+	points := make([]abstract.Point, n)
+	for i := range points {
+		points[i] = suite.Point()
+		if _, err := points[i].UnmarshalFrom(r); err != nil {
+			return nil, err
+		}
+	}
+	return points, nil
+}
+
+// Client runs the client side of the protocol over rw and returns the items
+// of its set that the server holds as well.
+func Client(suite abstract.Suite, items [][]byte, rw io.ReadWriter, rand cipher.Stream) ([][]byte, error) {
+	p := NewParty(suite, rand)
+	blinded := p.Blind(items)
+
+	// Phase 1: have the server re-blind our items, batch by batch
+	double := make([]abstract.Point, 0, len(items))
+	for start := 0; start < len(blinded); start += BatchSize {
+		end := start + BatchSize
+		if end > len(blinded) {
+			end = len(blinded)
+		}
+		if err := WriteBatch(rw, blinded[start:end]); err != nil {
+			return nil, err
+		}
+		resp, err := ReadBatch(suite, rw)
+		if err != nil {
+			return nil, err
+		}
+		if len(resp) != end-start {
+			return nil, errorBatchMismatch
+		}
+		double = append(double, resp...)
+	}
+	if err := WriteBatch(rw, nil); err != nil {
+		return nil, err
+	}
+	m, err := NewMatcher(items, double)
+	if err != nil {
+		return nil, err
+	}
+
+	// Phase 2: re-blind and match the server's items as they stream in
+	for {
+		batch, err := ReadBatch(suite, rw)
+		if err != nil {
+			return nil, err
+		}
+		if len(batch) == 0 {
+			break
+		}
+		d, err := p.Reblind(batch)
+		if err != nil {
+			return nil, err
+		}
+		if err := m.Match(d); err != nil {
+			return nil, err
+		}
+	}
+	return m.Intersection(), nil
+}
+
+// Server runs the server side of the protocol over rw.
+func Server(suite abstract.Suite, items [][]byte, rw io.ReadWriter, rand cipher.Stream) error {
+	p := NewParty(suite, rand)
+
+	// Phase 1: re-blind the client's items
+	for {
+		batch, err := ReadBatch(suite, rw)
+		if err != nil {
+			return err
+		}
+		if len(batch) == 0 {
+			break
+		}
+		d, err := p.Reblind(batch)
+		if err != nil {
+			return err
+		}
+		if err := WriteBatch(rw, d); err != nil {
+			return err
+		}
+	}
+
+	// Phase 2: stream our own blinded items
+	for start := 0; start < len(items); start += BatchSize {
+		end := start + BatchSize
+		if end > len(items) {
+			end = len(items)
+		}
+		if err := WriteBatch(rw, p.Blind(items[start:end])); err != nil {
+			return err
+		}
+	}
+	return WriteBatch(rw, nil)
+}
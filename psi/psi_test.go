@@ -0,0 +1,58 @@
+package psi
+
+import (
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/dedis/crypto/ed25519"
+	"github.com/dedis/crypto/random"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var suite = ed25519.NewAES128SHA256Ed25519(false)
+
+func items(prefix string, from, to int) [][]byte {
+	var out [][]byte
+	for i := from; i < to; i++ {
+		out = append(out, []byte(fmt.Sprintf("%s%d", prefix, i)))
+	}
+	return out
+}
+
+func TestPSIParties(t *testing.T) {
+	a := NewParty(suite, random.Stream)
+	b := NewParty(suite, random.Stream)
+	ia := items("item", 0, 10)
+	ib := items("item", 5, 20)
+
+	da, err := b.Reblind(a.Blind(ia))
+	require.Nil(t, err)
+	db, err := a.Reblind(b.Blind(ib))
+	require.Nil(t, err)
+
+	m, err := NewMatcher(ia, da)
+	require.Nil(t, err)
+	require.Nil(t, m.Match(db))
+	assert.Equal(t, items("item", 5, 10), m.Intersection())
+
+	_, err = a.Reblind(append(db, suite.Point().Null()))
+	assert.Error(t, err)
+}
+
+func TestPSIDriver(t *testing.T) {
+	// Sets larger than one batch
+	client := items("user", 0, BatchSize+10)
+	server := items("user", BatchSize-5, 2*BatchSize+20)
+
+	c, s := net.Pipe()
+	errs := make(chan error, 1)
+	go func() {
+		errs <- Server(suite, server, s, random.Stream)
+	}()
+	res, err := Client(suite, client, c, random.Stream)
+	require.Nil(t, err)
+	require.Nil(t, <-errs)
+	assert.Equal(t, items("user", BatchSize-5, BatchSize+10), res)
+}
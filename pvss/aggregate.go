@@ -0,0 +1,85 @@
+package pvss
+
+import (
+	"context"
+
+	"github.com/dedis/crypto/abstract"
+	"github.com/dedis/crypto/random"
+	"github.com/dedis/crypto/share"
+)
+
+// VerifyEncShareAggregate verifies all encrypted shares of one dealer, given
+// the dealer's public commitment polynomial pubPoly with base H, in a single
+// check. The encryption consistency proof of share i determines the
+// commitment it was made for as
+//
+//	sH_i = c_i^{-1}(vG_i - r_iH),
+//
+// against which the proof is verified, including its challenge. The
+// equations sH_i == pubPoly(i) are then combined with random weights into one
+// equation, in which pubPoly(i) is replaced by the polynomial's coefficients.
+// This saves the evaluation of the polynomial for every share, so the check
+// costs about 7n + t instead of n(4 + t) point multiplications. Shares with
+// an invalid or duplicate index are rejected beforehand, as by
+// VerifyEncShareBatchReport. Only if some index is invalid or the combined
+// check fails are the shares verified individually to identify the invalid
+// ones, so the results are the same as for VerifyEncShareBatchReport.
+func VerifyEncShareAggregate(suite abstract.Suite, H abstract.Point, X []abstract.Point, pubPoly *share.PubPoly, encShares []*PubVerShare) ([]abstract.Point, []*PubVerShare, []*Failure, error) {
+	if len(X) != len(encShares) {
+		return nil, nil, nil, lengthError("verify encrypted shares", len(X), len(encShares))
+	}
+	if indexErrors("verify encrypted", suite, encShares, len(X)) == nil && aggregateEncShares(suite, H, X, pubPoly, encShares) {
+		E := make([]*PubVerShare, len(encShares))
+		copy(E, encShares)
+		K := make([]abstract.Point, len(X))
+		copy(K, X)
+		return K, E, nil, nil
+	}
+	sH := make([]abstract.Point, len(encShares))
+	for i, es := range encShares {
+		if es.S.I >= 0 {
+			sH[i] = pubPoly.Eval(es.S.I).V
+		}
+	}
+	return VerifyEncShareBatchReport(context.Background(), suite, H, X, sH, encShares)
+}
+
+// aggregateEncShares verifies the proof of every share against the commitment
+// sH_i it determines and checks
+//
+//	sum_i a_i sH_i == sum_k (sum_i a_i x_i^k) C_k
+//
+// for random weights a_i, which holds for sH_i == pubPoly(i).
+func aggregateEncShares(suite abstract.Suite, H abstract.Point, X []abstract.Point, pubPoly *share.PubPoly, encShares []*PubVerShare) bool {
+	_, commits := pubPoly.Info()
+	acc := suite.Point().Null()
+	coeffs := make([]abstract.Scalar, len(commits)) // sum_i a_i x_i^k
+	for k := range coeffs {
+		coeffs[k] = suite.Scalar().Zero()
+	}
+	zero := suite.Scalar().Zero()
+	for i, es := range encShares {
+		p := &es.P
+		if p.C.Equal(zero) {
+			return false
+		}
+		vG := suite.Point().Sub(p.VG, suite.Point().Mul(H, p.R))
+		sH := suite.Point().Mul(vG, suite.Scalar().Inv(p.C))
+		if verifyEncShare(suite, H, X[i], sH, es, nil) != nil {
+			return false
+		}
+
+		a := suite.Scalar().Pick(random.Stream)
+		acc.Add(acc, suite.Point().Mul(sH, a))
+		xi := suite.Scalar().SetInt64(1 + int64(es.S.I))
+		pow := a
+		for k := range coeffs {
+			coeffs[k].Add(coeffs[k], pow)
+			pow = suite.Scalar().Mul(pow, xi)
+		}
+	}
+	for k, C := range commits {
+		acc.Sub(acc, suite.Point().Mul(C, coeffs[k]))
+	}
+	return acc.Equal(suite.Point().Null())
+}
@@ -0,0 +1,349 @@
+// Package beacon implements a distributed randomness beacon on top of PVSS,
+// following the construction of SCRAPE. In every round each participant
+// deals a random secret to all participants with PVSS. Once the participants
+// agree on the qualified dealers, i.e., those whose deals verify, the
+// encrypted shares of all qualified deals are aggregated per participant, so
+// that each participant decrypts a single share of the sum of the secrets.
+// Any threshold of valid decrypted shares recovers the sum V = sum(s)G, and
+// the output of the round is the hash of the round number and V. As long as
+// one qualified dealer is honest the output is unpredictable, and as long as
+// a threshold of participants is honest it cannot be withheld.
+//
+// Every step is publicly verifiable: a Transcript of the round lets light
+// clients check the output with Verify without taking part in the protocol.
+package beacon
+
+import (
+	"bytes"
+	"crypto/cipher"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/dedis/crypto/abstract"
+	"github.com/dedis/crypto/proof"
+	"github.com/dedis/crypto/pvss"
+	"github.com/dedis/crypto/share"
+)
+
+// Errors returned by this package, possibly wrapped with additional context.
+// Use errors.Is to test for them.
+var (
+	// ErrInvalidDeal is returned for a deal that does not verify.
+	ErrInvalidDeal = errors.New("invalid deal")
+	// ErrInvalidShare is returned for a decrypted share that does not verify.
+	ErrInvalidShare = errors.New("invalid decrypted share")
+	// ErrState is returned when a step of a round is run out of order.
+	ErrState = errors.New("round step out of order")
+	// ErrInvalidOutput is returned when the output of a round does not match
+	// its transcript.
+	ErrInvalidOutput = errors.New("invalid round output")
+)
+
+// Output is the verifiable random value of a round.
+type Output struct {
+	Round      uint64         // Round number
+	Value      abstract.Point // Sum of the secrets of the qualified dealers
+	Randomness []byte         // Hash of the round number and the value
+}
+
+// Transcript contains everything needed to verify the output of a round.
+type Transcript struct {
+	Round     uint64              // Round number
+	Dealers   []int               // Indices of the qualified dealers, sorted
+	Deals     []*pvss.Deal        // Deals of the qualified dealers
+	DecShares []*pvss.PubVerShare // At least a threshold of decrypted shares
+	Output    *Output
+}
+
+// Round is the state of one participant, or of an observer, in a round of the
+// beacon among the participants with public keys X and threshold t. The base
+// point H of the commitments must be the same for all participants.
+type Round struct {
+	suite abstract.Suite
+	H     abstract.Point
+	X     []abstract.Point
+	t     int
+	round uint64
+
+	deals     map[int]*pvss.Deal
+	dealers   []int            // Qualified dealers, set by Close
+	aggShares []abstract.Point // Aggregated encrypted shares, set by Close
+	dec       map[int]*pvss.PubVerShare
+}
+
+// NewRound creates the state of the given round.
+func NewRound(suite abstract.Suite, H abstract.Point, X []abstract.Point, t int, round uint64) *Round {
+	return &Round{
+		suite: suite,
+		H:     H,
+		X:     X,
+		t:     t,
+		round: round,
+		deals: make(map[int]*pvss.Deal),
+		dec:   make(map[int]*pvss.PubVerShare),
+	}
+}
+
+// Deal creates a participant's deal of a fresh random secret for the round.
+// It must be processed with AddDeal like the deals of the other participants.
+func (r *Round) Deal(rand cipher.Stream) (*pvss.Deal, error) {
+	secret := r.suite.Scalar().Pick(rand)
+	encShares, pubPoly, err := pvss.EncShares(r.suite, r.H, r.X, secret, r.t)
+	if err != nil {
+		return nil, err
+	}
+	return &pvss.Deal{Commits: pubPoly, EncShares: encShares}, nil
+}
+
+// AddDeal verifies the deal of the participant with the given index. A deal
+// is only accepted if all its encrypted shares are valid, since every share
+// enters the aggregated shares.
+func (r *Round) AddDeal(dealer int, deal *pvss.Deal) error {
+	if r.dealers != nil {
+		return ErrState
+	}
+	if dealer < 0 || dealer >= len(r.X) {
+		return fmt.Errorf("beacon: dealer index %d out of range: %w", dealer, ErrInvalidDeal)
+	}
+	if _, ok := r.deals[dealer]; ok {
+		return fmt.Errorf("beacon: duplicate deal of dealer %d: %w", dealer, ErrInvalidDeal)
+	}
+	if err := verifyDeal(r.suite, r.H, r.X, r.t, deal); err != nil {
+		return fmt.Errorf("beacon: deal of dealer %d: %w", dealer, err)
+	}
+	r.deals[dealer] = deal
+	return nil
+}
+
+// Qualified returns the sorted indices of the dealers whose deals have been
+// accepted so far.
+func (r *Round) Qualified() []int {
+	dealers := make([]int, 0, len(r.deals))
+	for i := range r.deals {
+		dealers = append(dealers, i)
+	}
+	sort.Ints(dealers)
+	return dealers
+}
+
+// Close ends the dealing phase with the given qualified dealers, on which all
+// participants must agree, and aggregates their encrypted shares. Every
+// qualified deal must have been accepted by AddDeal.
+func (r *Round) Close(dealers []int) error {
+	if r.dealers != nil {
+		return ErrState
+	}
+	if len(dealers) == 0 {
+		return fmt.Errorf("beacon: no qualified dealers: %w", ErrInvalidDeal)
+	}
+	deals := make([]*pvss.Deal, len(dealers))
+	for k, i := range dealers {
+		if k > 0 && dealers[k-1] >= i {
+			return fmt.Errorf("beacon: qualified dealers not sorted: %w", ErrInvalidDeal)
+		}
+		if deals[k] = r.deals[i]; deals[k] == nil {
+			return fmt.Errorf("beacon: no accepted deal of dealer %d: %w", i, ErrInvalidDeal)
+		}
+	}
+	r.dealers = append([]int(nil), dealers...)
+	r.aggShares = aggregate(r.suite, len(r.X), deals)
+	return nil
+}
+
+// DecShare decrypts the aggregated share of the participant with private key
+// x and the given index. The decrypted share must be processed with
+// AddDecShare like the shares of the other participants.
+func (r *Round) DecShare(index int, x abstract.Scalar) (*pvss.PubVerShare, error) {
+	if r.dealers == nil {
+		return nil, ErrState
+	}
+	if index < 0 || index >= len(r.X) {
+		return nil, fmt.Errorf("beacon: participant index %d out of range: %w", index, ErrInvalidShare)
+	}
+	V := r.suite.Point().Mul(r.aggShares[index], r.suite.Scalar().Inv(x))
+	P, _, _, err := proof.NewDLEQProofTagged(r.suite, r.suite.Point().Base(), V, x, tag(r.round))
+	if err != nil {
+		return nil, err
+	}
+	return &pvss.PubVerShare{S: share.PubShare{I: index, V: V}, P: *P}, nil
+}
+
+// AddDecShare verifies a decrypted aggregated share and keeps it for the
+// recovery of the output.
+func (r *Round) AddDecShare(ds *pvss.PubVerShare) error {
+	if r.dealers == nil {
+		return ErrState
+	}
+	if err := verifyDecShare(r.suite, r.X, r.aggShares, r.round, ds); err != nil {
+		return err
+	}
+	r.dec[ds.S.I] = ds
+	return nil
+}
+
+// Output recovers the output of the round once a threshold of valid
+// decrypted shares has been added.
+func (r *Round) Output() (*Output, error) {
+	if r.dealers == nil {
+		return nil, ErrState
+	}
+	shares := make([]*pvss.PubVerShare, 0, len(r.dec))
+	for _, ds := range r.dec {
+		shares = append(shares, ds)
+	}
+	return recoverOutput(r.suite, r.round, r.t, len(r.X), shares)
+}
+
+// Transcript returns the transcript of the round, from which light clients
+// can verify the output.
+func (r *Round) Transcript() (*Transcript, error) {
+	out, err := r.Output()
+	if err != nil {
+		return nil, err
+	}
+	tr := &Transcript{Round: r.round, Dealers: r.dealers, Output: out}
+	for _, i := range r.dealers {
+		tr.Deals = append(tr.Deals, r.deals[i])
+	}
+	for i := range r.X {
+		if ds, ok := r.dec[i]; ok {
+			tr.DecShares = append(tr.DecShares, ds)
+		}
+	}
+	return tr, nil
+}
+
+// Verify checks the transcript of a round of the beacon among the
+// participants with public keys X and threshold t: all qualified deals and
+// decrypted shares must be valid, and the output must be recovered from the
+// decrypted shares.
+func Verify(suite abstract.Suite, H abstract.Point, X []abstract.Point, t int, tr *Transcript) error {
+	if tr.Output == nil || tr.Output.Round != tr.Round {
+		return ErrInvalidOutput
+	}
+	if len(tr.Dealers) == 0 || len(tr.Dealers) != len(tr.Deals) {
+		return fmt.Errorf("beacon: %d qualified dealers for %d deals: %w", len(tr.Dealers), len(tr.Deals), ErrInvalidDeal)
+	}
+	for k, i := range tr.Dealers {
+		if i < 0 || i >= len(X) || (k > 0 && tr.Dealers[k-1] >= i) {
+			return fmt.Errorf("beacon: invalid qualified dealers: %w", ErrInvalidDeal)
+		}
+		if err := verifyDeal(suite, H, X, t, tr.Deals[k]); err != nil {
+			return fmt.Errorf("beacon: deal of dealer %d: %w", i, err)
+		}
+	}
+	aggShares := aggregate(suite, len(X), tr.Deals)
+	seen := make(map[int]bool)
+	for _, ds := range tr.DecShares {
+		if err := verifyDecShare(suite, X, aggShares, tr.Round, ds); err != nil {
+			return err
+		}
+		if seen[ds.S.I] {
+			return fmt.Errorf("beacon: duplicate decrypted share %d: %w", ds.S.I, ErrInvalidShare)
+		}
+		seen[ds.S.I] = true
+	}
+	out, err := recoverOutput(suite, tr.Round, t, len(X), tr.DecShares)
+	if err != nil {
+		return err
+	}
+	if !out.Value.Equal(tr.Output.Value) {
+		return ErrInvalidOutput
+	}
+	return VerifyOutput(suite, tr.Output)
+}
+
+// VerifyOutput checks that the randomness of an output is derived from its
+// round number and value. It does not check that the value is the outcome of
+// the round, for which the transcript is needed.
+func VerifyOutput(suite abstract.Suite, out *Output) error {
+	randomness, err := hashOutput(suite, out.Round, out.Value)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(randomness, out.Randomness) {
+		return ErrInvalidOutput
+	}
+	return nil
+}
+
+func verifyDeal(suite abstract.Suite, H abstract.Point, X []abstract.Point, t int, deal *pvss.Deal) error {
+	n := len(X)
+	if deal == nil || deal.Commits == nil || deal.Commits.Threshold() != t || len(deal.EncShares) != n {
+		return ErrInvalidDeal
+	}
+	sH := make([]abstract.Point, n)
+	for i, es := range deal.EncShares {
+		if es == nil || es.S.I != i {
+			return ErrInvalidDeal
+		}
+		sH[i] = deal.Commits.Eval(i).V
+	}
+	if _, good, err := pvss.VerifyEncShareBatch(suite, H, X, sH, deal.EncShares); err != nil || len(good) != n {
+		return ErrInvalidDeal
+	}
+	return nil
+}
+
+// aggregate sums the encrypted shares of each participant over the deals.
+func aggregate(suite abstract.Suite, n int, deals []*pvss.Deal) []abstract.Point {
+	agg := make([]abstract.Point, n)
+	for i := range agg {
+		agg[i] = suite.Point().Null()
+		for _, d := range deals {
+			agg[i].Add(agg[i], d.EncShares[i].S.V)
+		}
+	}
+	return agg
+}
+
+func verifyDecShare(suite abstract.Suite, X []abstract.Point, aggShares []abstract.Point, round uint64, ds *pvss.PubVerShare) error {
+	if ds == nil {
+		return ErrInvalidShare
+	}
+	i := ds.S.I
+	if i < 0 || i >= len(X) {
+		return fmt.Errorf("beacon: participant index %d out of range: %w", i, ErrInvalidShare)
+	}
+	if err := ds.P.VerifyTagged(suite, suite.Point().Base(), ds.S.V, X[i], aggShares[i], tag(round)); err != nil {
+		return fmt.Errorf("beacon: decrypted share %d: %w", i, ErrInvalidShare)
+	}
+	return nil
+}
+
+func recoverOutput(suite abstract.Suite, round uint64, t, n int, decShares []*pvss.PubVerShare) (*Output, error) {
+	if len(decShares) < t {
+		return nil, fmt.Errorf("beacon: %d of %d required decrypted shares: %w", len(decShares), t, pvss.ErrTooFewShares)
+	}
+	shares := make([]*share.PubShare, len(decShares))
+	for i, ds := range decShares {
+		shares[i] = &ds.S
+	}
+	V, err := share.RecoverCommit(suite, shares, t, n)
+	if err != nil {
+		return nil, err
+	}
+	randomness, err := hashOutput(suite, round, V)
+	if err != nil {
+		return nil, err
+	}
+	return &Output{Round: round, Value: V, Randomness: randomness}, nil
+}
+
+// tag binds the decryption proofs to the round.
+func tag(round uint64) []byte {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, round)
+	return append([]byte("pvss-beacon"), b...)
+}
+
+func hashOutput(suite abstract.Suite, round uint64, V abstract.Point) ([]byte, error) {
+	h := suite.Hash()
+	h.Write(tag(round))
+	if _, err := V.MarshalTo(h); err != nil {
+		return nil, err
+	}
+	return h.Sum(nil), nil
+}
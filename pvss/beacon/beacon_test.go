@@ -0,0 +1,136 @@
+package beacon
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/dedis/crypto/abstract"
+	"github.com/dedis/crypto/edwards"
+	"github.com/dedis/crypto/pvss"
+	"github.com/dedis/crypto/random"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var suite = edwards.NewAES128SHA256Ed25519(false)
+
+func keys(n int) ([]abstract.Scalar, []abstract.Point) {
+	x := make([]abstract.Scalar, n)
+	X := make([]abstract.Point, n)
+	for i := range x {
+		x[i] = suite.Scalar().Pick(random.Stream)
+		X[i] = suite.Point().Mul(nil, x[i])
+	}
+	return x, X
+}
+
+func run(t *testing.T, H abstract.Point, x []abstract.Scalar, X []abstract.Point, th int, round uint64, dealers []int) []*Round {
+	n := len(X)
+	rounds := make([]*Round, n)
+	for i := range rounds {
+		rounds[i] = NewRound(suite, H, X, th, round)
+	}
+	for _, d := range dealers {
+		deal, err := rounds[d].Deal(random.Stream)
+		require.Nil(t, err)
+		for _, r := range rounds {
+			require.Nil(t, r.AddDeal(d, deal))
+		}
+	}
+	for _, r := range rounds {
+		require.Equal(t, dealers, r.Qualified())
+		require.Nil(t, r.Close(dealers))
+	}
+	// Only a threshold of participants decrypt
+	for i := 0; i < th; i++ {
+		ds, err := rounds[i].DecShare(i, x[i])
+		require.Nil(t, err)
+		for _, r := range rounds {
+			require.Nil(t, r.AddDecShare(ds))
+		}
+	}
+	return rounds
+}
+
+func TestBeacon(t *testing.T) {
+	n, th := 5, 3
+	H, _ := suite.Point().Pick(nil, suite.Cipher([]byte("beacon-H")))
+	x, X := keys(n)
+
+	rounds := run(t, H, x, X, th, 7, []int{0, 1, 3, 4})
+	out, err := rounds[0].Output()
+	require.Nil(t, err)
+	for _, r := range rounds[1:] {
+		o, err := r.Output()
+		require.Nil(t, err)
+		assert.True(t, o.Value.Equal(out.Value))
+		assert.Equal(t, out.Randomness, o.Randomness)
+	}
+	require.Nil(t, VerifyOutput(suite, out))
+
+	tr, err := rounds[2].Transcript()
+	require.Nil(t, err)
+	require.Nil(t, Verify(suite, H, X, th, tr))
+
+	// The output is bound to the round number
+	forged := *tr.Output
+	forged.Round = 8
+	assert.True(t, errors.Is(VerifyOutput(suite, &forged), ErrInvalidOutput))
+	trForged := *tr
+	trForged.Round = 8
+	trForged.Output = &forged
+	assert.NotNil(t, Verify(suite, H, X, th, &trForged))
+
+	// Dropping a qualified deal changes the aggregated shares
+	trDrop := *tr
+	trDrop.Dealers = tr.Dealers[1:]
+	trDrop.Deals = tr.Deals[1:]
+	assert.True(t, errors.Is(Verify(suite, H, X, th, &trDrop), ErrInvalidShare))
+
+	// Too few decrypted shares
+	trFew := *tr
+	trFew.DecShares = tr.DecShares[:th-1]
+	assert.True(t, errors.Is(Verify(suite, H, X, th, &trFew), pvss.ErrTooFewShares))
+
+	// Another round yields another output
+	other, err := run(t, H, x, X, th, 8, []int{0, 1, 3, 4})[0].Output()
+	require.Nil(t, err)
+	assert.NotEqual(t, out.Randomness, other.Randomness)
+}
+
+func TestBeaconErrors(t *testing.T) {
+	n, th := 4, 2
+	H, _ := suite.Point().Pick(nil, suite.Cipher([]byte("beacon-H")))
+	x, X := keys(n)
+	r := NewRound(suite, H, X, th, 1)
+
+	_, err := r.DecShare(0, x[0])
+	assert.Equal(t, ErrState, err)
+
+	deal, err := r.Deal(random.Stream)
+	require.Nil(t, err)
+	require.Nil(t, r.AddDeal(0, deal))
+	assert.True(t, errors.Is(r.AddDeal(0, deal), ErrInvalidDeal))
+	assert.True(t, errors.Is(r.AddDeal(n, deal), ErrInvalidDeal))
+
+	// A deal with a single bad encrypted share is not qualified
+	bad, err := r.Deal(random.Stream)
+	require.Nil(t, err)
+	bad.EncShares[2] = deal.EncShares[2]
+	assert.True(t, errors.Is(r.AddDeal(1, bad), ErrInvalidDeal))
+
+	assert.True(t, errors.Is(r.Close([]int{0, 1}), ErrInvalidDeal))
+	require.Nil(t, r.Close([]int{0}))
+	assert.Equal(t, ErrState, r.AddDeal(2, deal))
+
+	// A decrypted share made with the wrong key is rejected
+	ds, err := r.DecShare(1, x[0])
+	require.Nil(t, err)
+	assert.True(t, errors.Is(r.AddDecShare(ds), ErrInvalidShare))
+
+	ds, err = r.DecShare(1, x[1])
+	require.Nil(t, err)
+	require.Nil(t, r.AddDecShare(ds))
+	_, err = r.Output()
+	assert.True(t, errors.Is(err, pvss.ErrTooFewShares))
+}
@@ -0,0 +1,140 @@
+package beacon
+
+import (
+	"crypto/cipher"
+	"errors"
+	"fmt"
+
+	"github.com/dedis/crypto/abstract"
+	"github.com/dedis/crypto/protocol"
+	"github.com/dedis/crypto/pvss"
+)
+
+// ErrMessage is returned for a protocol message with an unexpected payload
+// or sender.
+var ErrMessage = errors.New("invalid protocol message")
+
+// Party runs one participant of a round as a protocol.ProtocolState. On
+// creation it broadcasts its deal. Once it has heard a deal from every
+// participant, valid or not, it closes the dealing phase with the accepted
+// deals and broadcasts its decrypted share; decrypted shares that arrive
+// earlier are kept until then. Since the qualified dealers must be the same
+// for all participants, this relies on a reliable broadcast channel. If some
+// participants fail, the caller closes the dealing phase with SetTimeout.
+// The party is done once the output is recovered. Payloads are *pvss.Deal
+// and *pvss.PubVerShare.
+type Party struct {
+	protocol.Outbox
+	round   *Round
+	index   int
+	x       abstract.Scalar
+	heard   map[int]bool        // Dealers whose deals arrived
+	pending []*pvss.PubVerShare // Decrypted shares received before Close
+	output  *Output
+}
+
+// NewParty creates the party with the given index and private key x for
+// the given round, see NewRound.
+func NewParty(suite abstract.Suite, H abstract.Point, X []abstract.Point, t int, round uint64, index int, x abstract.Scalar, rand cipher.Stream) (*Party, error) {
+	if index < 0 || index >= len(X) {
+		return nil, fmt.Errorf("beacon: participant index %d out of range: %w", index, ErrInvalidShare)
+	}
+	p := &Party{round: NewRound(suite, H, X, t, round), index: index, x: x, heard: make(map[int]bool)}
+	deal, err := p.round.Deal(rand)
+	if err != nil {
+		return nil, err
+	}
+	p.Send(&protocol.Message{From: index, To: protocol.Broadcast, Payload: deal})
+	if err := p.addDeal(index, deal); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// ProcessMessage handles a deal or a decrypted share.
+func (p *Party) ProcessMessage(msg *protocol.Message) error {
+	switch payload := msg.Payload.(type) {
+	case *pvss.Deal:
+		return p.addDeal(msg.From, payload)
+	case *pvss.PubVerShare:
+		if payload.S.I != msg.From {
+			return fmt.Errorf("beacon: decrypted share %d sent by %d: %w", payload.S.I, msg.From, ErrMessage)
+		}
+		if p.round.dealers == nil {
+			p.pending = append(p.pending, payload)
+			return nil
+		}
+		return p.addDecShare(payload)
+	}
+	return fmt.Errorf("beacon: payload %T: %w", msg.Payload, ErrMessage)
+}
+
+func (p *Party) addDeal(dealer int, deal *pvss.Deal) error {
+	err := p.round.AddDeal(dealer, deal)
+	if errors.Is(err, ErrState) || p.heard[dealer] {
+		return err
+	}
+	if dealer >= 0 && dealer < len(p.round.X) {
+		p.heard[dealer] = true
+	}
+	if len(p.heard) == len(p.round.X) {
+		if cerr := p.close(); cerr != nil {
+			return cerr
+		}
+	}
+	return err
+}
+
+// SetTimeout closes the dealing phase with the deals accepted so far, e.g.,
+// once the synchrony bound has passed without a deal of every participant.
+func (p *Party) SetTimeout() error {
+	if p.round.dealers != nil {
+		return nil
+	}
+	return p.close()
+}
+
+func (p *Party) close() error {
+	if err := p.round.Close(p.round.Qualified()); err != nil {
+		return err
+	}
+	ds, err := p.round.DecShare(p.index, p.x)
+	if err != nil {
+		return err
+	}
+	p.Send(&protocol.Message{From: p.index, To: protocol.Broadcast, Payload: ds})
+	pending := p.pending
+	p.pending = nil
+	for _, ds := range append(pending, ds) {
+		// Errors of early shares are not attributable to this message
+		p.addDecShare(ds)
+	}
+	return nil
+}
+
+func (p *Party) addDecShare(ds *pvss.PubVerShare) error {
+	if err := p.round.AddDecShare(ds); err != nil {
+		return err
+	}
+	if p.output == nil {
+		if out, err := p.round.Output(); err == nil {
+			p.output = out
+		}
+	}
+	return nil
+}
+
+// Done returns true once the output of the round is recovered.
+func (p *Party) Done() bool {
+	return p.output != nil
+}
+
+// Output returns the output of the round, or nil if the party is not done.
+func (p *Party) Output() *Output {
+	return p.output
+}
+
+// Round returns the underlying round, e.g., for its transcript.
+func (p *Party) Round() *Round {
+	return p.round
+}
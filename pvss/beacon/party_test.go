@@ -0,0 +1,95 @@
+package beacon
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/dedis/crypto/abstract"
+	"github.com/dedis/crypto/protocol"
+	"github.com/dedis/crypto/protocol/protocoltest"
+	"github.com/dedis/crypto/pvss"
+	"github.com/dedis/crypto/random"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func parties(t *testing.T, H abstract.Point, x []abstract.Scalar, X []abstract.Point, th int) ([]*Party, []protocol.ProtocolState) {
+	ps := make([]*Party, len(X))
+	states := make([]protocol.ProtocolState, len(X))
+	for i := range ps {
+		var err error
+		ps[i], err = NewParty(suite, H, X, th, 3, i, x[i], random.Stream)
+		require.Nil(t, err)
+		states[i] = ps[i]
+	}
+	return ps, states
+}
+
+func checkParties(t *testing.T, H abstract.Point, X []abstract.Point, th int, ps []*Party, dealers []int) {
+	out := ps[0].Output()
+	require.NotNil(t, out)
+	for _, p := range ps {
+		require.True(t, p.Done())
+		assert.Equal(t, dealers, p.Round().Qualified())
+		assert.True(t, p.Output().Value.Equal(out.Value))
+		tr, err := p.Round().Transcript()
+		require.Nil(t, err)
+		require.Nil(t, Verify(suite, H, X, th, tr))
+	}
+}
+
+func TestParty(t *testing.T) {
+	n, th := 5, 3
+	H, _ := suite.Point().Pick(nil, suite.Cipher([]byte("beacon-H")))
+	x, X := keys(n)
+	ps, states := parties(t, H, x, X, th)
+	rejected, err := protocol.Run(states)
+	require.Nil(t, err)
+	assert.Empty(t, rejected)
+	checkParties(t, H, X, th, ps, []int{0, 1, 2, 3, 4})
+
+	// An invalid deal is heard but not qualified
+	ps, states = parties(t, H, x, X, th)
+	corrupt := protocoltest.Corrupt(2, func(payload interface{}) interface{} {
+		deal, ok := payload.(*pvss.Deal)
+		if !ok {
+			return payload
+		}
+		bad := *deal
+		bad.EncShares = deal.EncShares[1:]
+		return &bad
+	})
+	net := &protocoltest.Network{States: states, Behaviors: []protocoltest.Behavior{corrupt}}
+	res, err := net.Run()
+	// The corrupt dealer qualified its own deal and cannot follow the others
+	require.NotNil(t, err)
+	assert.Equal(t, []bool{true, true, false, true, true}, res.Done)
+	var invalid int
+	for _, r := range res.Rejected {
+		if errors.Is(r.Err, ErrInvalidDeal) {
+			invalid++
+		}
+	}
+	assert.Equal(t, n-1, invalid)
+	checkParties(t, H, X, th, ps[:2], []int{0, 1, 3, 4})
+	checkParties(t, H, X, th, ps[3:], []int{0, 1, 3, 4})
+}
+
+func TestPartyTimeout(t *testing.T) {
+	// A crashed participant stalls the dealing phase until it times out
+	n, th := 5, 3
+	H, _ := suite.Point().Pick(nil, suite.Cipher([]byte("beacon-H")))
+	x, X := keys(n)
+	ps, states := parties(t, H, x, X, th)
+	net := &protocoltest.Network{States: states, Behaviors: []protocoltest.Behavior{protocoltest.Drop(4)}}
+	_, err := net.Run()
+	require.NotNil(t, err)
+	for _, p := range ps[:4] {
+		assert.False(t, p.Done())
+		require.Nil(t, p.SetTimeout())
+	}
+	res, err := net.Run()
+	require.NotNil(t, err)
+	assert.Equal(t, []bool{true, true, true, true, false}, res.Done)
+	checkParties(t, H, X, th, ps[:4], []int{0, 1, 2, 3})
+}
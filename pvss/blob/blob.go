@@ -0,0 +1,146 @@
+// Package blob shares arbitrary byte payloads t-of-n with PVSS. Deal encrypts
+// the payload under a key derived from a fresh secret sG and PVSS-shares sG
+// among the trustees. The resulting Blob bundles the ciphertext with the PVSS
+// transcript, so anyone can verify that the trustees received valid shares,
+// and any t decrypted shares recover the payload.
+package blob
+
+import (
+	"crypto/cipher"
+	"errors"
+
+	"github.com/dedis/crypto/abstract"
+	"github.com/dedis/crypto/pvss"
+	"github.com/dedis/crypto/share"
+)
+
+// Some error definitions
+var errorThreshold = errors.New("invalid threshold")
+var errorBlob = errors.New("invalid blob")
+var errorIndex = errors.New("trustee index out of range")
+var errorDecryption = errors.New("decryption of payload failed")
+
+// Blob is a payload shared among a set of trustees.
+type Blob struct {
+	H          abstract.Point      // Base point of the share commitments
+	Shares     []*pvss.PubVerShare // Encrypted shares, one per trustee
+	Commits    []abstract.Point    // Commitments to the sharing polynomial
+	Ciphertext []byte              // Authenticated encryption of the payload
+}
+
+// Deal encrypts payload and shares the key among the trustees with public
+// keys X such that t of them can recover it.
+func Deal(suite abstract.Suite, H abstract.Point, X []abstract.Point, t int, payload []byte, rand cipher.Stream) (*Blob, error) {
+	if t <= 0 || t > len(X) {
+		return nil, errorThreshold
+	}
+	s := suite.Scalar().Pick(rand)
+	encShares, pubPoly, err := pvss.EncShares(suite, H, X, s, t)
+	if err != nil {
+		return nil, err
+	}
+	_, commits := pubPoly.Info()
+	b := &Blob{H: H, Shares: encShares, Commits: commits}
+	key, err := b.key(suite, suite.Point().Mul(nil, s))
+	if err != nil {
+		return nil, err
+	}
+	b.Ciphertext = suite.Cipher(key).Seal(nil, payload)
+	return b, nil
+}
+
+// Threshold returns the number of decrypted shares needed for recovery.
+func (b *Blob) Threshold() int {
+	return len(b.Commits)
+}
+
+// Verify checks that every trustee received a valid encrypted share.
+func (b *Blob) Verify(suite abstract.Suite, X []abstract.Point) error {
+	if err := b.check(X); err != nil {
+		return err
+	}
+	pubPoly := share.NewPubPoly(suite, b.H, b.Commits)
+	for i, s := range b.Shares {
+		if err := pvss.VerifyEncShare(suite, b.H, X[i], pubPoly.Eval(i).V, s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DecShare verifies and decrypts the share of the trustee with the given
+// index and private key x.
+func (b *Blob) DecShare(suite abstract.Suite, X []abstract.Point, index int, x abstract.Scalar) (*pvss.PubVerShare, error) {
+	if err := b.check(X); err != nil {
+		return nil, err
+	}
+	if index < 0 || index >= len(X) {
+		return nil, errorIndex
+	}
+	pubPoly := share.NewPubPoly(suite, b.H, b.Commits)
+	return pvss.DecShare(suite, b.H, X[index], pubPoly.Eval(index).V, x, b.Shares[index])
+}
+
+// Recover verifies the decrypted shares, recovers the key from the valid ones
+// and decrypts the payload.
+func (b *Blob) Recover(suite abstract.Suite, X []abstract.Point, decShares []*pvss.PubVerShare) ([]byte, error) {
+	if err := b.check(X); err != nil {
+		return nil, err
+	}
+	var K []abstract.Point
+	var E, D []*pvss.PubVerShare
+	for _, ds := range decShares {
+		if ds == nil || ds.S.I < 0 || ds.S.I >= len(X) {
+			continue
+		}
+		K = append(K, X[ds.S.I])
+		E = append(E, b.Shares[ds.S.I])
+		D = append(D, ds)
+	}
+	sG, err := pvss.RecoverSecret(suite, suite.Point().Base(), K, E, D, b.Threshold(), len(X))
+	if err != nil {
+		return nil, err
+	}
+	key, err := b.key(suite, sG)
+	if err != nil {
+		return nil, err
+	}
+	// Open decrypts in place, so work on a copy of the ciphertext
+	ct := append([]byte{}, b.Ciphertext...)
+	payload, err := suite.Cipher(key).Open(nil, ct)
+	if err != nil {
+		return nil, errorDecryption
+	}
+	return payload, nil
+}
+
+// check verifies that the blob matches the trustees' keys in size.
+func (b *Blob) check(X []abstract.Point) error {
+	n := len(X)
+	if n == 0 || len(b.Shares) != n || b.Threshold() <= 0 || b.Threshold() > n {
+		return errorBlob
+	}
+	for i, s := range b.Shares {
+		if s == nil || s.S.I != i {
+			return errorBlob
+		}
+	}
+	return nil
+}
+
+// key derives the symmetric key from the shared secret sG and binds it to the
+// transcript, so that the ciphertext cannot be moved to another dealing.
+func (b *Blob) key(suite abstract.Suite, sG abstract.Point) ([]byte, error) {
+	h := suite.Hash()
+	h.Write([]byte("pvss-blob"))
+	points := append([]abstract.Point{sG, b.H}, b.Commits...)
+	for _, s := range b.Shares {
+		points = append(points, s.S.V)
+	}
+	for _, P := range points {
+		if _, err := P.MarshalTo(h); err != nil {
+			return nil, err
+		}
+	}
+	return h.Sum(nil), nil
+}
@@ -0,0 +1,85 @@
+package blob
+
+import (
+	"testing"
+
+	"github.com/dedis/crypto/abstract"
+	"github.com/dedis/crypto/edwards"
+	"github.com/dedis/crypto/pvss"
+	"github.com/dedis/crypto/random"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var suite = edwards.NewAES128SHA256Ed25519(false)
+
+func setup(n int) (abstract.Point, []abstract.Scalar, []abstract.Point) {
+	H, _ := suite.Point().Pick(nil, suite.Cipher([]byte("H")))
+	x := make([]abstract.Scalar, n)
+	X := make([]abstract.Point, n)
+	for i := range x {
+		x[i] = suite.Scalar().Pick(random.Stream)
+		X[i] = suite.Point().Mul(nil, x[i])
+	}
+	return H, x, X
+}
+
+func TestBlob(t *testing.T) {
+	n, th := 5, 3
+	H, x, X := setup(n)
+	payload := []byte("share this file t-of-n verifiably")
+
+	b, err := Deal(suite, H, X, th, payload, random.Stream)
+	require.Nil(t, err)
+	require.Nil(t, b.Verify(suite, X))
+
+	var D []*pvss.PubVerShare
+	for _, i := range []int{4, 1, 2} {
+		ds, err := b.DecShare(suite, X, i, x[i])
+		require.Nil(t, err)
+		D = append(D, ds)
+	}
+	recovered, err := b.Recover(suite, X, D)
+	require.Nil(t, err)
+	assert.Equal(t, payload, recovered)
+
+	// Recovery is repeatable and needs a threshold of valid shares
+	recovered, err = b.Recover(suite, X, D)
+	require.Nil(t, err)
+	assert.Equal(t, payload, recovered)
+	D[0].S.V = suite.Point().Null()
+	_, err = b.Recover(suite, X, D)
+	assert.NotNil(t, err)
+}
+
+func TestBlobInvalid(t *testing.T) {
+	n, th := 4, 2
+	H, x, X := setup(n)
+
+	_, err := Deal(suite, H, X, n+1, []byte("payload"), random.Stream)
+	assert.Equal(t, errorThreshold, err)
+
+	b, err := Deal(suite, H, X, th, []byte("payload"), random.Stream)
+	require.Nil(t, err)
+	assert.Equal(t, errorBlob, b.Verify(suite, X[1:]))
+	_, err = b.DecShare(suite, X, n, x[0])
+	assert.Equal(t, errorIndex, err)
+
+	// A share encrypted for the wrong trustee is detected
+	b.Shares[0].S.V, b.Shares[1].S.V = b.Shares[1].S.V, b.Shares[0].S.V
+	assert.NotNil(t, b.Verify(suite, X))
+	b.Shares[0].S.V, b.Shares[1].S.V = b.Shares[1].S.V, b.Shares[0].S.V
+
+	// A ciphertext moved to another dealing does not decrypt
+	c, err := Deal(suite, H, X, th, []byte("other"), random.Stream)
+	require.Nil(t, err)
+	c.Ciphertext = b.Ciphertext
+	var D []*pvss.PubVerShare
+	for i := 0; i < th; i++ {
+		ds, err := c.DecShare(suite, X, i, x[i])
+		require.Nil(t, err)
+		D = append(D, ds)
+	}
+	_, err = c.Recover(suite, X, D)
+	assert.Equal(t, errorDecryption, err)
+}
@@ -0,0 +1,142 @@
+package pvss
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"github.com/dedis/crypto/abstract"
+	"github.com/dedis/crypto/share"
+)
+
+// RecoveryCertificate lets a light client trust a recovered secret without
+// re-running the recovery: it bundles the dealer's commitments, the
+// encrypted and decrypted shares of a threshold of trustees with their
+// proofs, and the recovered secret sG for the standard base point G. Unlike a
+// Transcript, it contains only the shares the secret is recovered from.
+type RecoveryCertificate struct {
+	H       abstract.Point // Base point of the commitments
+	Commits *share.PubPoly // Commitments of the dealer
+	Proof   *RecoveryProof // Shares used for the recovery
+	Secret  abstract.Point // Recovered secret sG
+}
+
+// CertifyRecovery recovers the secret committed to by pubPoly from the
+// decrypted shares of the trustees with the public keys X, as
+// RecoverSecretWithProof does for the threshold of pubPoly, and returns the
+// certificate of the recovery.
+func CertifyRecovery(suite abstract.Suite, H abstract.Point, X []abstract.Point, pubPoly *share.PubPoly, encShares []*PubVerShare, decShares []*PubVerShare) (*RecoveryCertificate, error) {
+	G := suite.Point().Base()
+	secret, p, err := RecoverSecretWithProof(suite, G, X, encShares, decShares, pubPoly.Threshold(), len(X))
+	if err != nil {
+		return nil, err
+	}
+	return &RecoveryCertificate{H, pubPoly, p, secret}, nil
+}
+
+// Verify checks that the certified secret is the secret committed to by the
+// dealer with the commitments pubPoly over base point H in a sharing among
+// the trustees with the public keys X. The caller takes H and pubPoly from
+// the dealing it trusts, e.g., a verified Transcript, since the commitments
+// carried by the certificate are the prover's claim. It verifies the recovery
+// proof of the certificate, so a certificate whose shares carry forged
+// consistency proofs is rejected.
+func (c *RecoveryCertificate) Verify(suite abstract.Suite, H abstract.Point, X []abstract.Point, pubPoly *share.PubPoly) error {
+	if c.H == nil || c.Commits == nil || c.Proof == nil || c.Secret == nil {
+		return fmt.Errorf("pvss: incomplete recovery certificate: %w", ErrInvalidRecovery)
+	}
+	if !c.H.Equal(H) || !c.Commits.Equal(pubPoly) {
+		return fmt.Errorf("pvss: recovery certificate for another dealing: %w", ErrInvalidRecovery)
+	}
+	return c.Proof.Verify(suite, suite.Point().Base(), H, X, pubPoly, c.Secret)
+}
+
+// MarshalBinary encodes the certificate as H, the length-prefixed
+// commitments, the pairs of encrypted and decrypted shares, as many as the
+// threshold of the commitments, and the secret. The Lagrange coefficients are
+// not encoded since they follow from the share indices.
+func (c *RecoveryCertificate) MarshalBinary() ([]byte, error) {
+	t := c.Commits.Threshold()
+	if len(c.Proof.EncShares) != t || len(c.Proof.DecShares) != t {
+		return nil, lengthError("marshal recovery certificate", t, len(c.Proof.EncShares), len(c.Proof.DecShares))
+	}
+	var b bytes.Buffer
+	if _, err := c.H.MarshalTo(&b); err != nil {
+		return nil, err
+	}
+	commits, err := c.Commits.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	binary.Write(&b, binary.BigEndian, uint32(len(commits)))
+	b.Write(commits)
+	for k := range c.Proof.EncShares {
+		for _, s := range []*PubVerShare{c.Proof.EncShares[k], c.Proof.DecShares[k]} {
+			enc, err := s.MarshalBinary()
+			if err != nil {
+				return nil, err
+			}
+			b.Write(enc)
+		}
+	}
+	if _, err := c.Secret.MarshalTo(&b); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// UnmarshalRecoveryCertificate decodes a certificate over suite encoded with
+// MarshalBinary. The decoded certificate still has to be checked with Verify.
+func UnmarshalRecoveryCertificate(suite abstract.Suite, buf []byte) (*RecoveryCertificate, error) {
+	r := bytes.NewReader(buf)
+	c := &RecoveryCertificate{H: suite.Point(), Secret: suite.Point()}
+	if _, err := c.H.UnmarshalFrom(r); err != nil {
+		return nil, fmt.Errorf("pvss: decoding certificate base point: %w", ErrEncoding)
+	}
+	var size uint32
+	if err := binary.Read(r, binary.BigEndian, &size); err != nil || int64(size) > int64(r.Len()) {
+		return nil, fmt.Errorf("pvss: decoding certificate commitments: %w", ErrEncoding)
+	}
+	commits := make([]byte, size)
+	io.ReadFull(r, commits)
+	var err error
+	if c.Commits, err = share.UnmarshalPubPoly(suite, commits); err != nil {
+		return nil, err
+	}
+	t := c.Commits.Threshold()
+	shareSize := NewPubVerShare(suite).MarshalSize()
+	if uint64(t) > uint64(r.Len())/uint64(2*shareSize) {
+		return nil, fmt.Errorf("pvss: certificate of threshold %d exceeds input: %w", t, ErrEncoding)
+	}
+	c.Proof = &RecoveryProof{EncShares: make([]*PubVerShare, t), DecShares: make([]*PubVerShare, t)}
+	indices := make([]int, t)
+	n := 0
+	enc := make([]byte, shareSize)
+	for k := 0; k < t; k++ {
+		for _, s := range []**PubVerShare{&c.Proof.EncShares[k], &c.Proof.DecShares[k]} {
+			if _, err := io.ReadFull(r, enc); err != nil {
+				return nil, fmt.Errorf("pvss: decoding certificate share: %w", ErrEncoding)
+			}
+			*s = NewPubVerShare(suite)
+			if err := (*s).UnmarshalBinary(enc); err != nil {
+				return nil, err
+			}
+		}
+		indices[k] = c.Proof.DecShares[k].S.I
+		if indices[k] >= n {
+			n = indices[k] + 1
+		}
+	}
+	// Verify checks the indices against the number of trustees
+	if c.Proof.Coeffs, err = lagrange(suite, indices, n); err != nil {
+		return nil, fmt.Errorf("pvss: decoding certificate shares: %w", ErrEncoding)
+	}
+	if _, err := c.Secret.UnmarshalFrom(r); err != nil {
+		return nil, fmt.Errorf("pvss: decoding certificate secret: %w", ErrEncoding)
+	}
+	if r.Len() != 0 {
+		return nil, fmt.Errorf("pvss: %d trailing bytes after certificate: %w", r.Len(), ErrEncoding)
+	}
+	return c, nil
+}
@@ -0,0 +1,91 @@
+package pvss
+
+import (
+	"encoding/binary"
+	"errors"
+
+	"github.com/dedis/crypto/abstract"
+	"github.com/dedis/crypto/proof"
+	"github.com/dedis/crypto/share"
+)
+
+// ErrDelVerification is returned for deletion statements with an invalid
+// proof.
+var ErrDelVerification = errors.New("verification of deletion statement failed")
+
+// Deletion is a trustee's public statement that it has destroyed its
+// decrypted share of an encrypted share. The trustee re-encrypts its private
+// key x toward a null key N, a point with unknown discrete logarithm derived
+// from the encrypted share, and proves log_G(X) == log_N(xN). The statement
+// cannot prove that data was erased, but it is bound to the encrypted share
+// and can only be produced by the trustee, so it serves as a non-repudiable
+// record of the retirement of the share for compliance purposes.
+type Deletion struct {
+	S share.PubShare  // Index of the share and the null re-encryption xN
+	P proof.DLEQProof // Proof of log_G(X) == log_N(xN)
+}
+
+// DeleteShare creates the deletion statement for the encrypted share using
+// the trustee's key pair (x, X) and then overwrites the trustee's decrypted
+// share decShare, if not nil. On error, decShare is left untouched.
+func DeleteShare(suite abstract.Suite, X abstract.Point, x abstract.Scalar, encShare *PubVerShare, decShare *PubVerShare) (*Deletion, error) {
+	return DeleteShareMeta(suite, X, x, encShare, decShare, nil)
+}
+
+// DeleteShareMeta is like DeleteShare but also binds the deletion statement
+// to meta, e.g., to the session in which the share was retired.
+func DeleteShareMeta(suite abstract.Suite, X abstract.Point, x abstract.Scalar, encShare *PubVerShare, decShare *PubVerShare, meta *Meta) (*Deletion, error) {
+	G := suite.Point().Base()
+	if !X.Equal(suite.Point().Mul(G, x)) || decShare != nil && decShare.S.I != encShare.S.I {
+		return nil, &ShareError{"delete", encShare.S.I, suite.String(), X, ErrDelVerification}
+	}
+	N, tag, err := nullKey(suite, encShare, meta)
+	if err != nil {
+		return nil, err
+	}
+	P, _, xN, err := proof.NewDLEQProofTagged(suite, G, N, x, tag)
+	if err != nil {
+		return nil, &ShareError{"delete", encShare.S.I, suite.String(), X, err}
+	}
+	if decShare != nil {
+		decShare.S.V.Null()
+		decShare.P = proof.DLEQProof{}
+	}
+	return &Deletion{share.PubShare{I: encShare.S.I, V: xN}, *P}, nil
+}
+
+// VerifyDeletion checks that the deletion statement was made for the
+// encrypted share by the trustee with public key X.
+func VerifyDeletion(suite abstract.Suite, X abstract.Point, encShare *PubVerShare, del *Deletion) error {
+	return VerifyDeletionMeta(suite, X, encShare, del, nil)
+}
+
+// VerifyDeletionMeta is like VerifyDeletion for statements created by
+// DeleteShareMeta with the same metadata.
+func VerifyDeletionMeta(suite abstract.Suite, X abstract.Point, encShare *PubVerShare, del *Deletion, meta *Meta) error {
+	if del.S.I != encShare.S.I {
+		return &ShareError{"verify deletion", del.S.I, suite.String(), X, ErrDelVerification}
+	}
+	N, tag, err := nullKey(suite, encShare, meta)
+	if err != nil {
+		return err
+	}
+	if err := del.P.VerifyTagged(suite, suite.Point().Base(), N, X, del.S.V, tag); err != nil {
+		return &ShareError{"verify deletion", del.S.I, suite.String(), X, ErrDelVerification}
+	}
+	return nil
+}
+
+// nullKey derives the null key N of an encrypted share together with the tag
+// that binds the deletion proof to the share and the metadata.
+func nullKey(suite abstract.Suite, encShare *PubVerShare, meta *Meta) (abstract.Point, []byte, error) {
+	buf, err := encShare.S.V.MarshalBinary()
+	if err != nil {
+		return nil, nil, err
+	}
+	tag := binary.BigEndian.AppendUint32([]byte("pvss-deletion"), uint32(encShare.S.I))
+	tag = append(tag, buf...)
+	tag = append(tag, meta.tag("del", encShare.S.I)...)
+	N, _ := suite.Point().Pick(nil, suite.Cipher(tag))
+	return N, tag, nil
+}
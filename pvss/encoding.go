@@ -0,0 +1,72 @@
+package pvss
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/dedis/crypto/abstract"
+)
+
+// ErrEncoding is returned when decoding a malformed share.
+var ErrEncoding = errors.New("invalid share encoding")
+
+// NewPubVerShare returns an empty share whose points and scalars belong to
+// suite, ready to be decoded with UnmarshalBinary. As with abstract.Point, the
+// suite is not part of the encoding but given by the object being decoded.
+func NewPubVerShare(suite abstract.Suite) *PubVerShare {
+	s := new(PubVerShare)
+	s.S.V = suite.Point()
+	s.P.C = suite.Scalar()
+	s.P.R = suite.Scalar()
+	s.P.VG = suite.Point()
+	s.P.VH = suite.Point()
+	return s
+}
+
+// MarshalSize returns the length of the encoding of the share.
+func (s *PubVerShare) MarshalSize() int {
+	return 4 + s.S.V.MarshalSize() + s.P.C.MarshalSize() + s.P.R.MarshalSize() +
+		s.P.VG.MarshalSize() + s.P.VH.MarshalSize()
+}
+
+// MarshalBinary encodes the share as its index as a 32-bit big-endian integer
+// followed by the encodings of the share value and of the proof's challenge,
+// response and commitments. This is the same encoding as produced by the
+// suite's reflective Write.
+func (s *PubVerShare) MarshalBinary() ([]byte, error) {
+	if int(int32(s.S.I)) != s.S.I {
+		return nil, fmt.Errorf("pvss: share index %d does not fit 32 bits: %w", s.S.I, ErrEncoding)
+	}
+	var b bytes.Buffer
+	binary.Write(&b, binary.BigEndian, int32(s.S.I))
+	for _, m := range []abstract.Marshaling{s.S.V, s.P.C, s.P.R, s.P.VG, s.P.VH} {
+		if _, err := m.MarshalTo(&b); err != nil {
+			return nil, err
+		}
+	}
+	return b.Bytes(), nil
+}
+
+// UnmarshalBinary decodes a share encoded with MarshalBinary. The share must
+// have been created with NewPubVerShare, or its fields otherwise allocated,
+// for the suite of the encoding.
+func (s *PubVerShare) UnmarshalBinary(buf []byte) error {
+	if s.S.V == nil || s.P.C == nil || s.P.R == nil || s.P.VG == nil || s.P.VH == nil {
+		return fmt.Errorf("pvss: decoding into unallocated share: %w", ErrEncoding)
+	}
+	if len(buf) != s.MarshalSize() {
+		return fmt.Errorf("pvss: share of %d bytes instead of %d: %w", len(buf), s.MarshalSize(), ErrEncoding)
+	}
+	r := bytes.NewReader(buf)
+	var i int32
+	binary.Read(r, binary.BigEndian, &i)
+	for _, m := range []abstract.Marshaling{s.S.V, s.P.C, s.P.R, s.P.VG, s.P.VH} {
+		if _, err := m.UnmarshalFrom(r); err != nil {
+			return err
+		}
+	}
+	s.S.I = int(i)
+	return nil
+}
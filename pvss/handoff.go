@@ -0,0 +1,166 @@
+package pvss
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dedis/crypto/abstract"
+	"github.com/dedis/crypto/proof"
+	"github.com/dedis/crypto/random"
+	"github.com/dedis/crypto/share"
+)
+
+// ErrHandoffVerification is returned for hand-offs with an invalid
+// consistency proof.
+var ErrHandoffVerification = errors.New("verification of hand-off failed")
+
+// Handoff is a trustee's decrypted share S_i = s_iG released privately to a
+// designated reader instead of being published: it is the ElGamal encryption
+// (U, V) = (rG, S_i + rR) of the decrypted share under the reader's public key
+// R, together with a proof that it encrypts the decryption of the trustee's
+// encrypted share. Anybody can verify a hand-off, but only the reader can
+// recover the secret sG from a threshold of them with RecoverHandoff.
+type Handoff struct {
+	I     int            // Index of the share
+	U     abstract.Point // Ephemeral key rG
+	V     abstract.Point // Encrypted decrypted share S_i + rR
+	Proof []byte         // Proof of consistency with the encrypted share
+}
+
+// HandoffShare decrypts the encrypted share of the trustee with key pair
+// (x, X) and hands it off to the reader with public key R. The encrypted share
+// must have been verified with VerifyEncShare.
+func HandoffShare(suite abstract.Suite, X abstract.Point, x abstract.Scalar, encShare *PubVerShare, R abstract.Point) (*Handoff, error) {
+	return HandoffShareMeta(suite, X, x, encShare, R, nil)
+}
+
+// HandoffShareMeta is like HandoffShare but also binds the proof of the
+// hand-off to meta, so that it is only accepted by VerifyHandoffMeta with the
+// same metadata.
+func HandoffShareMeta(suite abstract.Suite, X abstract.Point, x abstract.Scalar, encShare *PubVerShare, R abstract.Point, meta *Meta) (*Handoff, error) {
+	G := suite.Point().Base()
+	if !X.Equal(suite.Point().Mul(G, x)) {
+		return nil, &ShareError{"hand off", encShare.S.I, suite.String(), X, ErrHandoffVerification}
+	}
+	S := suite.Point().Mul(encShare.S.V, suite.Scalar().Inv(x)) // decryption: x^{-1} * (xS)
+	r := suite.Scalar().Pick(random.Stream)
+	h := &Handoff{
+		I: encShare.S.I,
+		U: suite.Point().Mul(G, r),
+		V: suite.Point().Add(S, suite.Point().Mul(R, r)),
+	}
+	sval := map[string]abstract.Scalar{"x": x, "r": r, "w": suite.Scalar().Mul(x, r)}
+	pred, pval, name, err := handoffStatement(suite, X, encShare, R, h, meta)
+	if err != nil {
+		return nil, err
+	}
+	prover := pred.Prover(suite, sval, pval, nil)
+	h.Proof, err = proof.HashProve(suite, name, suite.Cipher(abstract.RandomKey), prover)
+	if err != nil {
+		return nil, &ShareError{"hand off", encShare.S.I, suite.String(), X, err}
+	}
+	return h, nil
+}
+
+// VerifyHandoff checks that the hand-off was created by the trustee with
+// public key X from the encrypted share encShare for the reader with public
+// key R.
+func VerifyHandoff(suite abstract.Suite, X abstract.Point, encShare *PubVerShare, R abstract.Point, h *Handoff) error {
+	return VerifyHandoffMeta(suite, X, encShare, R, h, nil)
+}
+
+// VerifyHandoffMeta is like VerifyHandoff for hand-offs created by
+// HandoffShareMeta. It rejects the hand-off if meta has expired or differs
+// from the metadata the hand-off was created with.
+func VerifyHandoffMeta(suite abstract.Suite, X abstract.Point, encShare *PubVerShare, R abstract.Point, h *Handoff, meta *Meta) error {
+	if meta.Expired(time.Now()) {
+		return &ShareError{"verify hand-off", h.I, suite.String(), X, ErrExpired}
+	}
+	if h.I != encShare.S.I {
+		return &ShareError{"verify hand-off", h.I, suite.String(), X, ErrInvalidIndex}
+	}
+	pred, pval, name, err := handoffStatement(suite, X, encShare, R, h, meta)
+	if err != nil {
+		return err
+	}
+	if err := proof.HashVerify(suite, name, pred.Verifier(suite, pval), h.Proof); err != nil {
+		return &ShareError{"verify hand-off", h.I, suite.String(), X, ErrHandoffVerification}
+	}
+	return nil
+}
+
+// handoffStatement returns the statement proven by a hand-off: the prover
+// knows x, r and w = x*r such that
+//
+//	X = xG, U = rG, 0 = xU - wG and Y = xV - wR
+//
+// where Y = xS_i is the encrypted share. The last equation holds iff V - rR
+// is the decrypted share S_i. All public points and the optional metadata are
+// bound into the protocol name, which seeds the Fiat-Shamir challenge.
+func handoffStatement(suite abstract.Suite, X abstract.Point, encShare *PubVerShare, R abstract.Point, h *Handoff, meta *Meta) (proof.Predicate, map[string]abstract.Point, string, error) {
+	G := suite.Point().Base()
+	pval := map[string]abstract.Point{
+		"G":    G,
+		"-G":   suite.Point().Neg(G),
+		"-R":   suite.Point().Neg(R),
+		"X":    X,
+		"Y":    encShare.S.V,
+		"U":    h.U,
+		"V":    h.V,
+		"Null": suite.Point().Null(),
+	}
+	pred := proof.And(
+		proof.Rep("X", "x", "G"),
+		proof.Rep("U", "r", "G"),
+		proof.Rep("Null", "x", "U", "w", "-G"),
+		proof.Rep("Y", "x", "V", "w", "-R"))
+
+	hash := suite.Hash()
+	hash.Write([]byte("pvss-handoff"))
+	for _, P := range []abstract.Point{X, encShare.S.V, R, h.U, h.V} {
+		if _, err := P.MarshalTo(hash); err != nil {
+			return nil, nil, "", err
+		}
+	}
+	hash.Write(meta.tag("handoff", h.I))
+	return pred, pval, fmt.Sprintf("pvss-handoff-%x", hash.Sum(nil)), nil
+}
+
+// RecoverHandoff verifies the hand-offs of the trustees with public keys X
+// and encrypted shares encShares to the reader with key pair (r, R), decrypts
+// the valid ones and recovers the secret sG from a threshold t of them out of
+// n. The inputs are aligned by position like those of RecoverSecret.
+func RecoverHandoff(suite abstract.Suite, X []abstract.Point, encShares []*PubVerShare, handoffs []*Handoff, R abstract.Point, r abstract.Scalar, t int, n int) (abstract.Point, error) {
+	return RecoverHandoffMeta(suite, X, encShares, handoffs, R, r, t, n, nil)
+}
+
+// RecoverHandoffMeta is like RecoverHandoff for hand-offs created by
+// HandoffShareMeta. Only hand-offs bound to meta are used.
+func RecoverHandoffMeta(suite abstract.Suite, X []abstract.Point, encShares []*PubVerShare, handoffs []*Handoff, R abstract.Point, r abstract.Scalar, t int, n int, meta *Meta) (abstract.Point, error) {
+	if len(X) != len(encShares) || len(encShares) != len(handoffs) {
+		return nil, lengthError("verify hand-offs", len(X), len(encShares), len(handoffs))
+	}
+	var D []*PubVerShare
+	for i := range X {
+		if handoffs[i] == nil || VerifyHandoffMeta(suite, X[i], encShares[i], R, handoffs[i], meta) != nil {
+			continue
+		}
+		h := handoffs[i]
+		S := suite.Point().Sub(h.V, suite.Point().Mul(h.U, r))
+		D = append(D, &PubVerShare{S: share.PubShare{I: h.I, V: S}})
+	}
+	if len(D) < t {
+		return nil, fmt.Errorf("pvss: %d of %d required hand-offs are valid: %w", len(D), t, ErrTooFewShares)
+	}
+	for _, err := range indexErrors("recover hand-off", suite, D, n) {
+		if err != nil {
+			return nil, err
+		}
+	}
+	var shares []*share.PubShare
+	for _, s := range D {
+		shares = append(shares, &s.S)
+	}
+	return share.RecoverCommit(suite, shares, t, n)
+}
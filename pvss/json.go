@@ -0,0 +1,212 @@
+package pvss
+
+import (
+	"encoding"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"github.com/dedis/crypto/abstract"
+	"github.com/dedis/crypto/share"
+)
+
+// The JSON encodings represent every point and scalar as the hex string of its
+// binary encoding. As with the binary encodings, the suite is not encoded.
+
+type proofJSON struct {
+	C  string `json:"c"`
+	R  string `json:"r"`
+	VG string `json:"vg"`
+	VH string `json:"vh"`
+}
+
+type shareJSON struct {
+	Index int       `json:"index"`
+	Value string    `json:"value"`
+	Proof proofJSON `json:"proof"`
+}
+
+type commitsJSON struct {
+	Base    string   `json:"base,omitempty"`
+	Commits []string `json:"commits"`
+}
+
+type dealJSON struct {
+	Commits *commitsJSON      `json:"commits"`
+	Shares  []json.RawMessage `json:"shares"`
+}
+
+func toHex(m encoding.BinaryMarshaler) (string, error) {
+	buf, err := m.MarshalBinary()
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+func fromHex(m encoding.BinaryUnmarshaler, s string) error {
+	buf, err := hex.DecodeString(s)
+	if err != nil {
+		return fmt.Errorf("pvss: decoding hex value: %w", ErrEncoding)
+	}
+	if err := m.UnmarshalBinary(buf); err != nil {
+		return fmt.Errorf("pvss: decoding value: %w", ErrEncoding)
+	}
+	return nil
+}
+
+// MarshalJSON encodes the share as a JSON object holding its index, the share
+// value and the proof's challenge, response and commitments.
+func (s *PubVerShare) MarshalJSON() ([]byte, error) {
+	var js shareJSON
+	js.Index = s.S.I
+	fields := []*string{&js.Value, &js.Proof.C, &js.Proof.R, &js.Proof.VG, &js.Proof.VH}
+	for i, m := range []abstract.Marshaling{s.S.V, s.P.C, s.P.R, s.P.VG, s.P.VH} {
+		var err error
+		if *fields[i], err = toHex(m); err != nil {
+			return nil, err
+		}
+	}
+	return json.Marshal(&js)
+}
+
+// UnmarshalJSON decodes a share encoded with MarshalJSON. Like UnmarshalBinary
+// it requires a share created with NewPubVerShare for the suite of the
+// encoding.
+func (s *PubVerShare) UnmarshalJSON(buf []byte) error {
+	if s.S.V == nil || s.P.C == nil || s.P.R == nil || s.P.VG == nil || s.P.VH == nil {
+		return fmt.Errorf("pvss: decoding into unallocated share: %w", ErrEncoding)
+	}
+	var js shareJSON
+	if err := json.Unmarshal(buf, &js); err != nil {
+		return fmt.Errorf("pvss: decoding share: %w", ErrEncoding)
+	}
+	fields := []string{js.Value, js.Proof.C, js.Proof.R, js.Proof.VG, js.Proof.VH}
+	for i, m := range []abstract.Marshaling{s.S.V, s.P.C, s.P.R, s.P.VG, s.P.VH} {
+		if err := fromHex(m, fields[i]); err != nil {
+			return err
+		}
+	}
+	s.S.I = js.Index
+	return nil
+}
+
+// MarshalSharesJSON encodes a list of shares as a JSON array.
+func MarshalSharesJSON(shares []*PubVerShare) ([]byte, error) {
+	return json.Marshal(shares)
+}
+
+// UnmarshalSharesJSON decodes a list of shares over suite encoded with
+// MarshalSharesJSON.
+func UnmarshalSharesJSON(suite abstract.Suite, buf []byte) ([]*PubVerShare, error) {
+	var raw []json.RawMessage
+	if err := json.Unmarshal(buf, &raw); err != nil {
+		return nil, fmt.Errorf("pvss: decoding share list: %w", ErrEncoding)
+	}
+	return unmarshalShares(suite, raw)
+}
+
+func unmarshalShares(suite abstract.Suite, raw []json.RawMessage) ([]*PubVerShare, error) {
+	shares := make([]*PubVerShare, len(raw))
+	for i, r := range raw {
+		shares[i] = NewPubVerShare(suite)
+		if err := shares[i].UnmarshalJSON(r); err != nil {
+			return nil, err
+		}
+	}
+	return shares, nil
+}
+
+func marshalCommits(pubPoly *share.PubPoly) (*commitsJSON, error) {
+	b, commits := pubPoly.Info()
+	js := &commitsJSON{Commits: make([]string, len(commits))}
+	var err error
+	if b != nil {
+		if js.Base, err = toHex(b); err != nil {
+			return nil, err
+		}
+	}
+	for i, c := range commits {
+		if js.Commits[i], err = toHex(c); err != nil {
+			return nil, err
+		}
+	}
+	return js, nil
+}
+
+func unmarshalCommits(suite abstract.Suite, js *commitsJSON) (*share.PubPoly, error) {
+	if js == nil || len(js.Commits) == 0 {
+		return nil, fmt.Errorf("pvss: decoding commitments without coefficients: %w", ErrEncoding)
+	}
+	var b abstract.Point
+	if js.Base != "" {
+		b = suite.Point()
+		if err := fromHex(b, js.Base); err != nil {
+			return nil, err
+		}
+	}
+	commits := make([]abstract.Point, len(js.Commits))
+	for i, c := range js.Commits {
+		commits[i] = suite.Point()
+		if err := fromHex(commits[i], c); err != nil {
+			return nil, err
+		}
+	}
+	return share.NewPubPoly(suite, b, commits), nil
+}
+
+// MarshalCommitsJSON encodes the public commitment polynomial of a dealer as
+// a JSON object holding the commitments to the coefficients and, unless it is
+// the standard base point, the base point.
+func MarshalCommitsJSON(pubPoly *share.PubPoly) ([]byte, error) {
+	js, err := marshalCommits(pubPoly)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(js)
+}
+
+// UnmarshalCommitsJSON decodes a commitment polynomial over suite encoded
+// with MarshalCommitsJSON.
+func UnmarshalCommitsJSON(suite abstract.Suite, buf []byte) (*share.PubPoly, error) {
+	var js commitsJSON
+	if err := json.Unmarshal(buf, &js); err != nil {
+		return nil, fmt.Errorf("pvss: decoding commitments: %w", ErrEncoding)
+	}
+	return unmarshalCommits(suite, &js)
+}
+
+// MarshalJSON encodes the deal as a JSON object holding the commitments as
+// encoded by MarshalCommitsJSON and the encrypted shares.
+func (d *Deal) MarshalJSON() ([]byte, error) {
+	commits, err := marshalCommits(d.Commits)
+	if err != nil {
+		return nil, err
+	}
+	js := dealJSON{Commits: commits, Shares: make([]json.RawMessage, len(d.EncShares))}
+	for i, s := range d.EncShares {
+		if js.Shares[i], err = s.MarshalJSON(); err != nil {
+			return nil, err
+		}
+	}
+	return json.Marshal(&js)
+}
+
+// UnmarshalDealJSON decodes a deal over suite encoded with its MarshalJSON.
+// The decoded deal still has to be verified, e.g., with
+// Participant.ProcessDeal.
+func UnmarshalDealJSON(suite abstract.Suite, buf []byte) (*Deal, error) {
+	var js dealJSON
+	if err := json.Unmarshal(buf, &js); err != nil {
+		return nil, fmt.Errorf("pvss: decoding deal: %w", ErrEncoding)
+	}
+	commits, err := unmarshalCommits(suite, js.Commits)
+	if err != nil {
+		return nil, err
+	}
+	shares, err := unmarshalShares(suite, js.Shares)
+	if err != nil {
+		return nil, err
+	}
+	return &Deal{commits, shares}, nil
+}
@@ -0,0 +1,130 @@
+package pvss
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dedis/crypto/abstract"
+	"github.com/dedis/crypto/metrics"
+	"github.com/dedis/crypto/random"
+	"github.com/dedis/crypto/share"
+)
+
+// ErrExpired is returned for shares whose metadata has expired.
+var ErrExpired = errors.New("share metadata expired")
+
+// Meta is optional metadata of a PVSS transcript. The *Meta functions bind it
+// into the challenges of the encryption and decryption consistency proofs and
+// of the proofs of hand-offs, re-sharings and deletions, and their verifiers
+// recompute these challenges, so a proof only verifies against the exact
+// metadata it was created with. This rejects shares replayed from a previous
+// epoch, from another dealer or from another protocol session. A nil Meta is
+// the zero Meta, which binds nothing beyond the share index and is what the
+// functions without metadata use.
+type Meta struct {
+	Epoch   uint64    // Epoch of the sharing
+	Expiry  time.Time // Time after which shares are rejected; zero for none
+	Dealer  []byte    // Identifier of the dealer
+	Session []byte    // Identifier of the protocol session, e.g. a random nonce
+	Context []byte    // Application context of the statement
+}
+
+// Expired reports whether the metadata has expired at time now.
+func (m *Meta) Expired(now time.Time) bool {
+	return m != nil && !m.Expiry.IsZero() && now.After(m.Expiry)
+}
+
+// tag returns the encoding of the metadata bound into the proof of the share
+// with the given index in operation op.
+func (m *Meta) tag(op string, index int) []byte {
+	if m == nil {
+		m = &Meta{}
+	}
+	var expiry int64
+	if !m.Expiry.IsZero() {
+		expiry = m.Expiry.UnixNano()
+	}
+	buf := []byte("pvss-meta-" + op)
+	buf = binary.BigEndian.AppendUint64(buf, m.Epoch)
+	buf = binary.BigEndian.AppendUint64(buf, uint64(expiry))
+	for _, b := range [][]byte{m.Dealer, m.Session, m.Context} {
+		buf = binary.BigEndian.AppendUint32(buf, uint32(len(b)))
+		buf = append(buf, b...)
+	}
+	return binary.BigEndian.AppendUint32(buf, uint32(index))
+}
+
+// EncSharesMeta is like EncShares but binds the encryption consistency proofs
+// to meta. Each share gets its own proof, so shares can be verified
+// individually with VerifyEncShareMeta.
+func EncSharesMeta(suite abstract.Suite, H abstract.Point, X []abstract.Point, secret abstract.Scalar, t int, meta *Meta) (_ []*PubVerShare, _ *share.PubPoly, err error) {
+	defer metrics.Start("pvss.EncSharesMeta").End(&err)
+	return encShares(context.Background(), suite, H, X, secret, t, random.Stream, meta)
+}
+
+// VerifyEncShareMeta is like VerifyEncShare for shares created by
+// EncSharesMeta. It rejects the share if meta has expired or differs from the
+// metadata the share was created with.
+func VerifyEncShareMeta(suite abstract.Suite, H abstract.Point, X abstract.Point, sH abstract.Point, encShare *PubVerShare, meta *Meta) (err error) {
+	defer metrics.Start("pvss.VerifyEncShareMeta").End(&err)
+
+	if meta.Expired(time.Now()) {
+		return &ShareError{"verify encrypted", encShare.S.I, suite.String(), X, ErrExpired}
+	}
+	return verifyEncShare(suite, H, X, sH, encShare, meta)
+}
+
+// DecShareMeta is like DecShare for shares created by EncSharesMeta. The
+// decryption consistency proof is bound to meta as well.
+func DecShareMeta(suite abstract.Suite, H abstract.Point, X abstract.Point, sH abstract.Point, x abstract.Scalar, encShare *PubVerShare, meta *Meta) (_ *PubVerShare, err error) {
+	defer metrics.Start("pvss.DecShareMeta").End(&err)
+
+	if err := VerifyEncShareMeta(suite, H, X, sH, encShare, meta); err != nil {
+		return nil, err
+	}
+	return decShare(suite, X, x, encShare, meta)
+}
+
+// VerifyDecShareMeta is like VerifyDecShare for shares decrypted by
+// DecShareMeta.
+func VerifyDecShareMeta(suite abstract.Suite, G abstract.Point, X abstract.Point, encShare *PubVerShare, decShare *PubVerShare, meta *Meta) (err error) {
+	defer metrics.Start("pvss.VerifyDecShareMeta").End(&err)
+
+	if meta.Expired(time.Now()) {
+		return &ShareError{"verify decrypted", decShare.S.I, suite.String(), X, ErrExpired}
+	}
+	return verifyDecShare(suite, G, X, encShare, decShare, meta)
+}
+
+// RecoverSecretMeta is like RecoverSecret for shares decrypted by
+// DecShareMeta. Only decrypted shares bound to meta are used, and their
+// indices must be unique and lie in [0, n).
+func RecoverSecretMeta(suite abstract.Suite, G abstract.Point, X []abstract.Point, encShares []*PubVerShare, decShares []*PubVerShare, t int, n int, meta *Meta) (_ abstract.Point, err error) {
+	defer metrics.Start("pvss.RecoverSecretMeta").End(&err)
+
+	if len(X) != len(encShares) || len(encShares) != len(decShares) {
+		return nil, lengthError("verify decrypted shares", len(X), len(encShares), len(decShares))
+	}
+	var D []*PubVerShare
+	for i := range X {
+		if err := VerifyDecShareMeta(suite, G, X[i], encShares[i], decShares[i], meta); err == nil {
+			D = append(D, decShares[i])
+		}
+	}
+	if len(D) < t {
+		return nil, fmt.Errorf("pvss: %d of %d required decrypted shares are valid: %w", len(D), t, ErrTooFewShares)
+	}
+	for _, err := range indexErrors("recover", suite, D, n) {
+		if err != nil {
+			return nil, err
+		}
+	}
+	var shares []*share.PubShare
+	for _, s := range D {
+		shares = append(shares, &s.S)
+	}
+	return share.RecoverCommit(suite, shares, t, n)
+}
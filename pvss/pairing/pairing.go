@@ -0,0 +1,154 @@
+//go:build pbc
+// +build pbc
+
+// Package pairing implements PVSS over a pairing-friendly curve, in the style
+// of the pairing-based variant of SCRAPE. The dealer commits to the sharing
+// polynomial in G1 with respect to the base point H and encrypts the share s_i
+// of trustee i, whose public key X_i = x_i G2 lies in G2, as s_i X_i. Anybody
+// can check an encrypted share with the single pairing equation
+//
+//	e(s_i H, X_i) == e(H, s_i X_i)
+//
+// where s_i H is the evaluation of the public commitment polynomial, so the
+// dealer attaches no proofs at all. Likewise, a decrypted share s_i G2 is
+// checked with e(s_i H, G2) == e(H, s_i G2) without a decryption proof. The
+// recovered secret is sG2.
+//
+// The functions mirror EncShares, VerifyEncShare, VerifyEncShareBatch,
+// DecShare, VerifyDecShare, VerifyDecShareBatch and RecoverSecret of package
+// pvss: they take a *pbc.Pairing instead of an abstract.Suite, handle
+// *pvss.PubVerShare, whose proofs stay empty, and return the same errors. The
+// decrypted shares are checked against the commitments rather than the
+// trustee keys, so VerifyDecShare and RecoverSecret take H and the
+// commitments where package pvss takes G and the trustee keys. The package
+// requires the pbc build tag, see package pbc.
+package pairing
+
+import (
+	"fmt"
+
+	"github.com/dedis/crypto/abstract"
+	"github.com/dedis/crypto/pbc"
+	"github.com/dedis/crypto/pvss"
+	"github.com/dedis/crypto/random"
+	"github.com/dedis/crypto/share"
+)
+
+func lengthError(op string, lengths ...int) error {
+	return fmt.Errorf("pairing: %s with input lengths %v: %w", op, lengths, pvss.ErrDifferentLengths)
+}
+
+// pair returns e(p1, p2).
+func pair(suite *pbc.Pairing, p1, p2 abstract.Point) abstract.Point {
+	return suite.GT().PairingPoint().Pairing(p1, p2)
+}
+
+// EncShares creates the encrypted shares of the secret for the trustees with
+// public keys X in G2 using the sharing threshold t and the base point H of
+// G1. The function returns the list of shares and the public commitment
+// polynomial.
+func EncShares(suite *pbc.Pairing, H abstract.Point, X []abstract.Point, secret abstract.Scalar, t int) ([]*pvss.PubVerShare, *share.PubPoly, error) {
+	priPoly := share.NewPriPoly(suite.G1(), t, secret, random.Stream)
+	priShares := priPoly.Shares(len(X))
+	encShares := make([]*pvss.PubVerShare, len(X))
+	for i, ps := range priShares {
+		encShares[i] = &pvss.PubVerShare{S: share.PubShare{I: ps.I, V: suite.G2().Point().Mul(X[i], ps.V)}}
+	}
+	return encShares, priPoly.Commit(H), nil
+}
+
+// VerifyEncShare checks that the encrypted share of the trustee with public
+// key X encrypts the share committed to by sH, i.e., e(sH, X) == e(H, sX).
+func VerifyEncShare(suite *pbc.Pairing, H abstract.Point, X abstract.Point, sH abstract.Point, encShare *pvss.PubVerShare) error {
+	if encShare.S.I < 0 {
+		return &pvss.ShareError{Op: "verify encrypted", Index: encShare.S.I, Suite: suite.G2().String(), Key: X, Err: pvss.ErrInvalidIndex}
+	}
+	if !pair(suite, sH, X).Equal(pair(suite, H, encShare.S.V)) {
+		return &pvss.ShareError{Op: "verify encrypted", Index: encShare.S.I, Suite: suite.G2().String(), Key: X, Err: pvss.ErrEncVerification}
+	}
+	return nil
+}
+
+// VerifyEncShareBatch provides the same functionality as VerifyEncShare but
+// for slices of encrypted shares. The function returns the valid encrypted
+// shares as well as the corresponding public keys.
+func VerifyEncShareBatch(suite *pbc.Pairing, H abstract.Point, X []abstract.Point, sH []abstract.Point, encShares []*pvss.PubVerShare) ([]abstract.Point, []*pvss.PubVerShare, error) {
+	if len(X) != len(sH) || len(sH) != len(encShares) {
+		return nil, nil, lengthError("verify encrypted shares", len(X), len(sH), len(encShares))
+	}
+	var K []abstract.Point
+	var E []*pvss.PubVerShare
+	for i := range X {
+		if err := VerifyEncShare(suite, H, X[i], sH[i], encShares[i]); err == nil {
+			K = append(K, X[i])
+			E = append(E, encShares[i])
+		}
+	}
+	return K, E, nil
+}
+
+// DecShare verifies the encrypted share and, if valid, decrypts it with the
+// trustee's private key x into the decrypted share sG2.
+func DecShare(suite *pbc.Pairing, H abstract.Point, X abstract.Point, sH abstract.Point, x abstract.Scalar, encShare *pvss.PubVerShare) (*pvss.PubVerShare, error) {
+	if err := VerifyEncShare(suite, H, X, sH, encShare); err != nil {
+		return nil, err
+	}
+	xinv := suite.G2().Scalar().Inv(x)
+	return &pvss.PubVerShare{S: share.PubShare{I: encShare.S.I, V: suite.G2().Point().Mul(encShare.S.V, xinv)}}, nil
+}
+
+// VerifyDecShare checks that the decrypted share carries the index of the
+// encrypted share and is the share committed to by sH, i.e.,
+// e(sH, G2) == e(H, sG2).
+func VerifyDecShare(suite *pbc.Pairing, H abstract.Point, sH abstract.Point, encShare *pvss.PubVerShare, decShare *pvss.PubVerShare) error {
+	if decShare.S.I != encShare.S.I {
+		return &pvss.ShareError{Op: "verify decrypted", Index: decShare.S.I, Suite: suite.G2().String(), Err: pvss.ErrInvalidIndex}
+	}
+	G2 := suite.G2().Point().Base()
+	if !pair(suite, sH, G2).Equal(pair(suite, H, decShare.S.V)) {
+		return &pvss.ShareError{Op: "verify decrypted", Index: decShare.S.I, Suite: suite.G2().String(), Err: pvss.ErrDecVerification}
+	}
+	return nil
+}
+
+// VerifyDecShareBatch provides the same functionality as VerifyDecShare but
+// for slices of decrypted shares. The function returns the valid decrypted
+// shares.
+func VerifyDecShareBatch(suite *pbc.Pairing, H abstract.Point, sH []abstract.Point, encShares []*pvss.PubVerShare, decShares []*pvss.PubVerShare) ([]*pvss.PubVerShare, error) {
+	if len(sH) != len(encShares) || len(encShares) != len(decShares) {
+		return nil, lengthError("verify decrypted shares", len(sH), len(encShares), len(decShares))
+	}
+	var D []*pvss.PubVerShare
+	for i := range sH {
+		if err := VerifyDecShare(suite, H, sH[i], encShares[i], decShares[i]); err == nil {
+			D = append(D, decShares[i])
+		}
+	}
+	return D, nil
+}
+
+// RecoverSecret verifies the decrypted shares against the public commitment
+// polynomial and recovers the secret sG2 from a threshold t of the valid ones
+// out of n. The indices of the valid decrypted shares must be unique and lie
+// in [0, n).
+func RecoverSecret(suite *pbc.Pairing, H abstract.Point, pubPoly *share.PubPoly, encShares []*pvss.PubVerShare, decShares []*pvss.PubVerShare, t int, n int) (abstract.Point, error) {
+	if len(encShares) != len(decShares) {
+		return nil, lengthError("recover", len(encShares), len(decShares))
+	}
+	var good []*share.PubShare
+	seen := make(map[int]bool)
+	for k, ds := range decShares {
+		if err := VerifyDecShare(suite, H, pubPoly.Eval(ds.S.I).V, encShares[k], ds); err != nil {
+			continue
+		}
+		if ds.S.I < 0 || ds.S.I >= n || seen[ds.S.I] {
+			return nil, &pvss.ShareError{Op: "recover", Index: ds.S.I, Suite: suite.G2().String(), Err: pvss.ErrInvalidIndex}
+		}
+		seen[ds.S.I] = true
+		good = append(good, &ds.S)
+	}
+	if len(good) < t {
+		return nil, fmt.Errorf("pairing: %d of %d required decrypted shares are valid: %w", len(good), t, pvss.ErrTooFewShares)
+	}
+	return share.RecoverCommit(suite.G2(), good, t, n)
+}
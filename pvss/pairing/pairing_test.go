@@ -0,0 +1,71 @@
+//go:build pbc
+// +build pbc
+
+package pairing
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/dedis/crypto/abstract"
+	"github.com/dedis/crypto/pbc"
+	"github.com/dedis/crypto/pvss"
+	"github.com/dedis/crypto/random"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var suite = new(pbc.Pairing).InitD224()
+
+func TestPairingPVSS(t *testing.T) {
+	n, th := 6, 4
+	H, _ := suite.G1().Point().Pick(nil, random.Stream)
+	x := make([]abstract.Scalar, n)
+	X := make([]abstract.Point, n)
+	for i := range x {
+		x[i] = suite.G2().Scalar().Pick(random.Stream)
+		X[i] = suite.G2().Point().Mul(nil, x[i])
+	}
+	secret := suite.G1().Scalar().Pick(random.Stream)
+	encShares, pubPoly, err := EncShares(suite, H, X, secret, th)
+	require.Nil(t, err)
+
+	// Corrupt one encrypted share
+	encShares[1].S.V = suite.G2().Point().Base()
+	sH := pubPoly.VerificationKeys(n)
+	K, E, err := VerifyEncShareBatch(suite, H, X, sH, encShares)
+	require.Nil(t, err)
+	assert.Len(t, K, n-1)
+	assert.Len(t, E, n-1)
+	err = VerifyEncShare(suite, H, X[1], sH[1], encShares[1])
+	assert.True(t, errors.Is(err, pvss.ErrEncVerification))
+
+	var encGood, decShares []*pvss.PubVerShare
+	for i := range X {
+		ds, err := DecShare(suite, H, X[i], sH[i], x[i], encShares[i])
+		if i == 1 {
+			assert.NotNil(t, err)
+			continue
+		}
+		require.Nil(t, err)
+		require.Nil(t, VerifyDecShare(suite, H, sH[i], encShares[i], ds))
+		encGood = append(encGood, encShares[i])
+		decShares = append(decShares, ds)
+	}
+	// Corrupt one decrypted share and mislabel another
+	decShares[0].S.V = suite.G2().Point().Base()
+	assert.True(t, errors.Is(VerifyDecShare(suite, H, sH[0], encGood[0], decShares[0]), pvss.ErrDecVerification))
+	assert.True(t, errors.Is(VerifyDecShare(suite, H, sH[2], encGood[0], decShares[1]), pvss.ErrInvalidIndex))
+	D, err := VerifyDecShareBatch(suite, H, append(sH[:1:1], sH[2:]...), encGood, decShares)
+	require.Nil(t, err)
+	assert.Len(t, D, n-2)
+
+	recovered, err := RecoverSecret(suite, H, pubPoly, encGood, decShares, th, n)
+	require.Nil(t, err)
+	assert.True(t, recovered.Equal(suite.G2().Point().Mul(nil, secret)))
+
+	_, err = RecoverSecret(suite, H, pubPoly, encGood[:th], decShares[:th], th, n)
+	assert.True(t, errors.Is(err, pvss.ErrTooFewShares))
+	_, err = RecoverSecret(suite, H, pubPoly, encGood[:1], decShares, th, n)
+	assert.True(t, errors.Is(err, pvss.ErrDifferentLengths))
+}
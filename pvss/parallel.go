@@ -0,0 +1,102 @@
+package pvss
+
+import (
+	"context"
+	"runtime"
+	"sync"
+
+	"github.com/dedis/crypto/abstract"
+)
+
+// VerifyEncShareBatchParallel is like VerifyEncShareBatchReport but verifies
+// the encrypted shares with the given number of goroutines, or with
+// runtime.NumCPU() goroutines if workers is not positive. The results are in
+// the same order as for the sequential version.
+func VerifyEncShareBatchParallel(ctx context.Context, suite abstract.Suite, H abstract.Point, X []abstract.Point, sH []abstract.Point, encShares []*PubVerShare, workers int) ([]abstract.Point, []*PubVerShare, []*Failure, error) {
+	if len(X) != len(sH) || len(sH) != len(encShares) {
+		return nil, nil, nil, lengthError("verify encrypted shares", len(X), len(sH), len(encShares))
+	}
+	idxErrs := indexErrors("verify encrypted", suite, encShares, len(X))
+	errs, done := parallel(ctx, len(X), workers, func(i int) error {
+		if idxErrs != nil && idxErrs[i] != nil {
+			return idxErrs[i]
+		}
+		return VerifyEncShare(suite, H, X[i], sH[i], encShares[i])
+	})
+	var K []abstract.Point // good public keys
+	var E []*PubVerShare   // good encrypted shares
+	var F []*Failure       // bad encrypted shares
+	for i := range X {
+		if !done[i] {
+			continue
+		}
+		if errs[i] != nil {
+			F = append(F, &Failure{i, errs[i]})
+			continue
+		}
+		K = append(K, X[i])
+		E = append(E, encShares[i])
+	}
+	return K, E, F, ctx.Err()
+}
+
+// VerifyDecShareBatchParallel is like VerifyDecShareBatchReport but verifies
+// the decrypted shares with the given number of goroutines, or with
+// runtime.NumCPU() goroutines if workers is not positive. The results are in
+// the same order as for the sequential version.
+func VerifyDecShareBatchParallel(ctx context.Context, suite abstract.Suite, G abstract.Point, X []abstract.Point, encShares []*PubVerShare, decShares []*PubVerShare, workers int) ([]*PubVerShare, []*Failure, error) {
+	if len(X) != len(encShares) || len(encShares) != len(decShares) {
+		return nil, nil, lengthError("verify decrypted shares", len(X), len(encShares), len(decShares))
+	}
+	errs, done := parallel(ctx, len(X), workers, func(i int) error {
+		return VerifyDecShare(suite, G, X[i], encShares[i], decShares[i])
+	})
+	var D []*PubVerShare // good decrypted shares
+	var F []*Failure     // bad decrypted shares
+	for i := range X {
+		if !done[i] {
+			continue
+		}
+		if errs[i] != nil {
+			F = append(F, &Failure{i, errs[i]})
+			continue
+		}
+		D = append(D, decShares[i])
+	}
+	return D, F, ctx.Err()
+}
+
+// parallel runs f on the indices 0, ..., n-1 with the given number of
+// goroutines and returns the errors of f together with the indices on which f
+// ran. Once the context is done, no further indices are started.
+func parallel(ctx context.Context, n, workers int, f func(i int) error) ([]error, []bool) {
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	if workers > n {
+		workers = n
+	}
+	errs := make([]error, n)
+	done := make([]bool, n)
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				errs[i] = f(i)
+				done[i] = true
+			}
+		}()
+	}
+	for i := 0; i < n && ctx.Err() == nil; i++ {
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+		}
+	}
+	close(jobs)
+	wg.Wait()
+	return errs, done
+}
@@ -0,0 +1,193 @@
+package pvss
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/dedis/crypto/abstract"
+	"github.com/dedis/crypto/share"
+)
+
+// Errors of the stateful protocol API.
+var (
+	// ErrState is returned when a protocol step is run out of order or twice.
+	ErrState = errors.New("protocol step out of order")
+	// ErrNotParticipant is returned when a key is not among the trustees.
+	ErrNotParticipant = errors.New("key not among the trustees")
+)
+
+// Deal is the public output of a dealer: the commitments to its sharing
+// polynomial and the encrypted shares of the trustees, in trustee order.
+type Deal struct {
+	Commits   *share.PubPoly
+	EncShares []*PubVerShare
+}
+
+// Dealer is the stateful dealer of one PVSS run among the trustees with
+// public keys X and threshold t.
+type Dealer struct {
+	suite abstract.Suite
+	H     abstract.Point
+	X     []abstract.Point
+	t     int
+	deal  *Deal
+}
+
+// NewDealer creates a dealer for the trustees with public keys X, threshold
+// t and commitment base H.
+func NewDealer(suite abstract.Suite, H abstract.Point, X []abstract.Point, t int) *Dealer {
+	return &Dealer{suite: suite, H: H, X: X, t: t}
+}
+
+// Deal shares the secret among the trustees. A dealer deals only once.
+func (d *Dealer) Deal(secret abstract.Scalar) (*Deal, error) {
+	if d.deal != nil {
+		return nil, ErrState
+	}
+	encShares, pubPoly, err := EncShares(d.suite, d.H, d.X, secret, d.t)
+	if err != nil {
+		return nil, err
+	}
+	d.deal = &Deal{pubPoly, encShares}
+	return d.deal, nil
+}
+
+// Participant is the state of a trustee, or of any third party that wants to
+// recover the secret, in one PVSS run. The protocol steps are ProcessDeal,
+// DecShare for trustees, ProcessDecShare for the decrypted shares of the
+// trustees and finally RecoverSecret.
+type Participant struct {
+	suite abstract.Suite
+	H     abstract.Point
+	X     []abstract.Point
+	t     int
+	index int             // Index of the trustee, or -1 for an observer
+	x     abstract.Scalar // Private key of the trustee
+	deal  *Deal
+	valid []bool // Valid encrypted shares of the deal
+	dec   map[int]*PubVerShare
+	own   *PubVerShare
+}
+
+// NewParticipant creates the state of the trustee with private key x among
+// the trustees with public keys X for a run with threshold t and commitment
+// base H. If x is nil, the participant is an observer that verifies the run
+// and recovers the secret, but has no share.
+func NewParticipant(suite abstract.Suite, H abstract.Point, X []abstract.Point, x abstract.Scalar, t int) (*Participant, error) {
+	p := &Participant{suite: suite, H: H, X: X, t: t, index: -1, x: x, dec: make(map[int]*PubVerShare)}
+	if x != nil {
+		pub := suite.Point().Mul(nil, x)
+		for i, Xi := range X {
+			if Xi.Equal(pub) {
+				p.index = i
+				break
+			}
+		}
+		if p.index < 0 {
+			return nil, ErrNotParticipant
+		}
+	}
+	return p, nil
+}
+
+// Index returns the index of the trustee, or -1 for an observer.
+func (p *Participant) Index() int {
+	return p.index
+}
+
+// ProcessDeal verifies the dealer's deal. It fails if fewer than a threshold
+// of the encrypted shares are valid or, for a trustee, if its own share is
+// invalid. It returns a Failure for every invalid encrypted share, so that the
+// dealer can be accused. The deal is only accepted once.
+func (p *Participant) ProcessDeal(deal *Deal) ([]*Failure, error) {
+	if p.deal != nil {
+		return nil, ErrState
+	}
+	n := len(p.X)
+	if deal.Commits == nil || deal.Commits.Threshold() != p.t {
+		return nil, fmt.Errorf("pvss: deal without commitments of threshold %d: %w", p.t, ErrEncVerification)
+	}
+	if len(deal.EncShares) != n {
+		return nil, lengthError("process deal", n, len(deal.EncShares))
+	}
+	sH := make([]abstract.Point, n)
+	for i, es := range deal.EncShares {
+		if es == nil || es.S.I != i {
+			return nil, &ShareError{"process deal", i, p.suite.String(), p.X[i], ErrEncVerification}
+		}
+		sH[i] = deal.Commits.Eval(i).V
+	}
+	_, _, failures, err := VerifyEncShareBatchReport(context.Background(), p.suite, p.H, p.X, sH, deal.EncShares)
+	if err != nil {
+		return nil, err
+	}
+	valid := make([]bool, n)
+	for i := range valid {
+		valid[i] = true
+	}
+	for _, f := range failures {
+		valid[f.Pos] = false
+	}
+	if p.index >= 0 && !valid[p.index] {
+		return failures, &ShareError{"process deal", p.index, p.suite.String(), p.X[p.index], ErrEncVerification}
+	}
+	if n-len(failures) < p.t {
+		return failures, fmt.Errorf("pvss: %d of %d required encrypted shares are valid: %w", n-len(failures), p.t, ErrTooFewShares)
+	}
+	p.deal = deal
+	p.valid = valid
+	return failures, nil
+}
+
+// DecShare decrypts the trustee's share of the accepted deal. The decrypted
+// share is also processed as if received with ProcessDecShare.
+func (p *Participant) DecShare() (*PubVerShare, error) {
+	if p.deal == nil || p.own != nil {
+		return nil, ErrState
+	}
+	if p.index < 0 {
+		return nil, ErrNotParticipant
+	}
+	i := p.index
+	ds, err := DecShare(p.suite, p.H, p.X[i], p.deal.Commits.Eval(i).V, p.x, p.deal.EncShares[i])
+	if err != nil {
+		return nil, err
+	}
+	p.own = ds
+	p.dec[i] = ds
+	return ds, nil
+}
+
+// ProcessDecShare verifies a decrypted share of another trustee against its
+// encrypted share in the accepted deal and keeps it for recovery.
+func (p *Participant) ProcessDecShare(ds *PubVerShare) error {
+	if p.deal == nil {
+		return ErrState
+	}
+	i := ds.S.I
+	if i < 0 || i >= len(p.X) || !p.valid[i] {
+		return &ShareError{"process decrypted", i, p.suite.String(), nil, ErrDecVerification}
+	}
+	if err := VerifyDecShare(p.suite, p.suite.Point().Base(), p.X[i], p.deal.EncShares[i], ds); err != nil {
+		return err
+	}
+	p.dec[i] = ds
+	return nil
+}
+
+// RecoverSecret recovers the secret sG from the decrypted shares processed so
+// far. It returns ErrTooFewShares until a threshold of them is available.
+func (p *Participant) RecoverSecret() (abstract.Point, error) {
+	if p.deal == nil {
+		return nil, ErrState
+	}
+	if len(p.dec) < p.t {
+		return nil, fmt.Errorf("pvss: %d of %d required decrypted shares are valid: %w", len(p.dec), p.t, ErrTooFewShares)
+	}
+	shares := make([]*share.PubShare, 0, len(p.dec))
+	for _, ds := range p.dec {
+		shares = append(shares, &ds.S)
+	}
+	return share.RecoverCommit(p.suite, shares, p.t, len(p.X))
+}
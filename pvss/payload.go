@@ -0,0 +1,43 @@
+package pvss
+
+import (
+	"errors"
+
+	"github.com/dedis/crypto/abstract"
+	"github.com/dedis/crypto/random"
+	"github.com/dedis/crypto/share"
+)
+
+// ErrPayloadTooLong is returned for payloads that do not fit into a single
+// point, see abstract.Point.PickLen.
+var ErrPayloadTooLong = errors.New("payload too long to embed into a point")
+
+// EncSharesPayload deals a fresh random secret s among the trustees with
+// public keys X like EncShares and uses the secret sG, which the trustees
+// release upon recovery, to mask the payload: it embeds data into a point P
+// and returns C = P + sG along with the shares and the public commitment
+// polynomial. Once the secret is recovered, RecoverData extracts the payload
+// from C. This turns PVSS into an escrow for small payloads such as symmetric
+// keys. The payload must fit into a single point. C is not bound to the
+// commitments, so it must be distributed together with them over an
+// authenticated channel.
+func EncSharesPayload(suite abstract.Suite, H abstract.Point, X []abstract.Point, data []byte, t int) ([]*PubVerShare, *share.PubPoly, abstract.Point, error) {
+	P, rem := suite.Point().Pick(data, random.Stream)
+	if len(rem) > 0 {
+		return nil, nil, nil, ErrPayloadTooLong
+	}
+	secret := suite.Scalar().Pick(random.Stream)
+	encShares, pubPoly, err := EncShares(suite, H, X, secret, t)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	C := suite.Point().Add(P, suite.Point().Mul(nil, secret))
+	return encShares, pubPoly, C, nil
+}
+
+// RecoverData extracts the payload from C given the secret recovered with
+// RecoverSecret, using the standard base point as G, from the shares of
+// EncSharesPayload.
+func RecoverData(suite abstract.Suite, secret abstract.Point, C abstract.Point) ([]byte, error) {
+	return suite.Point().Sub(C, secret).Data()
+}
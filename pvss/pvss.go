@@ -0,0 +1,466 @@
+// Package pvss implements public verifiable secret sharing as introduced in
+// "A Simple Publicly Verifiable Secret Sharing Scheme and its Application to
+// Electronic Voting" by Berry Schoenmakers. In comparison to regular verifiable
+// secret sharing schemes, PVSS enables any third party to verify shares
+// distributed by a dealer using zero-knowledge proofs. PVSS runs in three steps:
+//  1. The dealer creates a list of encrypted publicly verifiable shares using
+//     EncShares() and distributes them to the trustees.
+//  2. Upon the announcement that the secret should be released, each trustee
+//     uses DecShare() to first verify and, if valid, decrypt his share.
+//  3. Once a threshold of decrypted shares has been released, anyone can
+//     verify them and, if enough shares are valid, recover the shared secret
+//     using RecoverSecret().
+//
+// The Dealer and Participant types wrap these functions and keep track of the
+// polynomial commitments, share indices and verified shares of one run.
+//
+// Instead of publishing their decrypted shares in step 2, the trustees may
+// release them privately to a designated reader with HandoffShare, so that
+// only the reader can recover the secret using RecoverHandoff().
+//
+// For concrete applications of PVSS, refer to the paper "SCRAPE: Scalable
+// Randomness Attested by Public Entities" by Ignacio Cascudo and Bernardo David.
+package pvss
+
+import (
+	"context"
+	"crypto/cipher"
+	"errors"
+	"fmt"
+
+	"github.com/dedis/crypto/abstract"
+	"github.com/dedis/crypto/metrics"
+	"github.com/dedis/crypto/proof"
+	"github.com/dedis/crypto/random"
+	"github.com/dedis/crypto/share"
+)
+
+// Errors returned by this package, possibly wrapped with additional context.
+// Use errors.Is to test for them and errors.As to retrieve a ShareError.
+var (
+	// ErrTooFewShares is returned when fewer than a threshold of valid
+	// decrypted shares are available.
+	ErrTooFewShares = errors.New("not enough shares to recover secret")
+	// ErrDifferentLengths is returned when batch inputs do not match in size.
+	ErrDifferentLengths = errors.New("inputs of different lengths")
+	// ErrEncVerification is returned for encrypted shares with an invalid
+	// encryption consistency proof.
+	ErrEncVerification = errors.New("verification of encrypted share failed")
+	// ErrDecVerification is returned for decrypted shares with an invalid
+	// decryption consistency proof.
+	ErrDecVerification = errors.New("verification of decrypted share failed")
+	// ErrInvalidIndex is returned for shares whose index lies outside of
+	// the range of trustees or does not match the share it refers to.
+	ErrInvalidIndex = errors.New("invalid share index")
+)
+
+// ShareError records the operation and the share that caused an error.
+type ShareError struct {
+	Op    string         // Operation that failed
+	Index int            // Index of the share
+	Suite string         // Name of the suite
+	Key   abstract.Point // Public key of the share's trustee, or nil if unknown
+	Err   error          // Underlying error
+}
+
+func (e *ShareError) Error() string {
+	if e.Key != nil {
+		return fmt.Sprintf("pvss: %s share %d of trustee %s (%s): %v", e.Op, e.Index, e.Key, e.Suite, e.Err)
+	}
+	return fmt.Sprintf("pvss: %s share %d (%s): %v", e.Op, e.Index, e.Suite, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *ShareError) Unwrap() error {
+	return e.Err
+}
+
+// Failure records an input of a batch operation that failed verification.
+type Failure struct {
+	Pos int   // Position of the share in the batch input
+	Err error // Error of the share, usually a *ShareError
+}
+
+func lengthError(op string, lengths ...int) error {
+	return fmt.Errorf("pvss: %s with input lengths %v: %w", op, lengths, ErrDifferentLengths)
+}
+
+// indexErrors checks that the indices of the shares lie in [0, n) and are
+// unique. It returns the errors of the offending shares by position, or nil if
+// all indices are fine. All shares with a duplicated index are reported, since
+// it is not clear which one is the right one.
+func indexErrors(op string, suite abstract.Suite, shares []*PubVerShare, n int) []error {
+	var errs []error
+	fail := func(i int, err error) {
+		if errs == nil {
+			errs = make([]error, len(shares))
+		}
+		errs[i] = &ShareError{op, shares[i].S.I, suite.String(), nil, err}
+	}
+	first := make(map[int]int)
+	for i, s := range shares {
+		if s.S.I < 0 || s.S.I >= n {
+			fail(i, ErrInvalidIndex)
+			continue
+		}
+		if j, ok := first[s.S.I]; ok {
+			fail(j, ErrDuplicateShare)
+			fail(i, ErrDuplicateShare)
+			continue
+		}
+		first[s.S.I] = i
+	}
+	return errs
+}
+
+// PubVerShare is a public verifiable share.
+type PubVerShare struct {
+	S share.PubShare  // Share
+	P proof.DLEQProof // Proof
+}
+
+// Equal checks equality of two shares s and o including their proofs.
+func (s *PubVerShare) Equal(o *PubVerShare) bool {
+	if s == nil || o == nil {
+		return s == o
+	}
+	return s.S.Equal(&o.S) && s.P.Equal(&o.P)
+}
+
+// EncShares creates a list of encrypted publicly verifiable PVSS shares for
+// the given secret and the list of public keys X using the sharing threshold
+// t and the base point H. The function returns the list of shares and the
+// public commitment polynomial. Each share gets its own encryption consistency
+// proof, bound to the share's index as by EncSharesMeta with the zero Meta.
+// The proofs are not bound to a PVSS instance; use EncSharesMeta, e.g. with
+// the epoch or session as Meta.Context, to prevent replay across instances.
+func EncShares(suite abstract.Suite, H abstract.Point, X []abstract.Point, secret abstract.Scalar, t int) ([]*PubVerShare, *share.PubPoly, error) {
+	return EncSharesContext(context.Background(), suite, H, X, secret, t)
+}
+
+// EncSharesContext is like EncShares but aborts with the context's error once
+// the context is done.
+func EncSharesContext(ctx context.Context, suite abstract.Suite, H abstract.Point, X []abstract.Point, secret abstract.Scalar, t int) (_ []*PubVerShare, _ *share.PubPoly, err error) {
+	defer metrics.Start("pvss.EncShares").End(&err)
+	return encShares(ctx, suite, H, X, secret, t, random.Stream, nil)
+}
+
+// EncSharesWith is like EncShares but draws all randomness of the dealer, the
+// sharing polynomial and the proof commitments, from rand. With a seeded
+// stream the output is reproducible, which enables deterministic tests and
+// simulations, or dealer randomness derived from a VRF output. The stream
+// must be unpredictable to anyone but the dealer.
+func EncSharesWith(suite abstract.Suite, H abstract.Point, X []abstract.Point, secret abstract.Scalar, t int, rand cipher.Stream) (_ []*PubVerShare, _ *share.PubPoly, err error) {
+	defer metrics.Start("pvss.EncShares").End(&err)
+	return encShares(context.Background(), suite, H, X, secret, t, rand, nil)
+}
+
+func encShares(ctx context.Context, suite abstract.Suite, H abstract.Point, X []abstract.Point, secret abstract.Scalar, t int, rand cipher.Stream, meta *Meta) ([]*PubVerShare, *share.PubPoly, error) {
+	n := len(X)
+	encShares := make([]*PubVerShare, n)
+
+	// Create secret sharing polynomial
+	priPoly := share.NewPriPoly(suite, t, secret, rand)
+
+	// Create secret set of shares
+	priShares := priPoly.Shares(n)
+
+	// Create public polynomial commitments with respect to basis H
+	pubPoly := priPoly.Commit(H)
+
+	// Encrypt the shares and create NIZK discrete-logarithm equality proofs
+	for i, s := range priShares {
+		if err := ctx.Err(); err != nil {
+			return nil, nil, err
+		}
+		p, _, sX, err := proof.NewDLEQProofTaggedWith(suite, H, X[i], s.V, meta.tag("enc", s.I), rand)
+		if err != nil {
+			return nil, nil, err
+		}
+		encShares[i] = &PubVerShare{share.PubShare{I: s.I, V: sX}, *p}
+	}
+
+	return encShares, pubPoly, nil
+}
+
+// VerifyEncShare checks that the encrypted share sX satisfies
+// log_{H}(sH) == log_{X}(sX) where sH is the public commitment computed by
+// evaluating the public commitment polynomial at the encrypted share's index i.
+// The challenge of the proof is recomputed, so a proof made for another share
+// or another statement is rejected. The proof is not bound to a session,
+// though: a share created by EncShares for another PVSS instance with the
+// same commitment is accepted. Use VerifyEncShareMeta to require a session.
+func VerifyEncShare(suite abstract.Suite, H abstract.Point, X abstract.Point, sH abstract.Point, encShare *PubVerShare) (err error) {
+	defer metrics.Start("pvss.VerifyEncShare").End(&err)
+	return verifyEncShare(suite, H, X, sH, encShare, nil)
+}
+
+func verifyEncShare(suite abstract.Suite, H abstract.Point, X abstract.Point, sH abstract.Point, encShare *PubVerShare, meta *Meta) error {
+	if encShare.S.I < 0 {
+		return &ShareError{"verify encrypted", encShare.S.I, suite.String(), X, ErrInvalidIndex}
+	}
+	if err := encShare.P.VerifyTagged(suite, H, X, sH, encShare.S.V, meta.tag("enc", encShare.S.I)); err != nil {
+		return &ShareError{"verify encrypted", encShare.S.I, suite.String(), X, ErrEncVerification}
+	}
+	return nil
+}
+
+// VerifyEncShareBatch provides the same functionality as VerifyEncShare but for
+// slices of encrypted shares. The function returns the valid encrypted shares
+// together with the corresponding public keys. The encrypted shares must be
+// the shares of one dealer for the trustees X: shares with an index outside of
+// [0, len(X)) or with the index of another share are invalid.
+func VerifyEncShareBatch(suite abstract.Suite, H abstract.Point, X []abstract.Point, sH []abstract.Point, encShares []*PubVerShare) ([]abstract.Point, []*PubVerShare, error) {
+	return VerifyEncShareBatchContext(context.Background(), suite, H, X, sH, encShares)
+}
+
+// VerifyEncShareBatchContext is like VerifyEncShareBatch but stops once the
+// context is done and then returns the valid shares found so far together
+// with the context's error.
+func VerifyEncShareBatchContext(ctx context.Context, suite abstract.Suite, H abstract.Point, X []abstract.Point, sH []abstract.Point, encShares []*PubVerShare) ([]abstract.Point, []*PubVerShare, error) {
+	K, E, _, err := VerifyEncShareBatchReport(ctx, suite, H, X, sH, encShares)
+	return K, E, err
+}
+
+// VerifyEncShareBatchReport is like VerifyEncShareBatchContext but also
+// returns a Failure for every encrypted share that does not verify, so that
+// the dealer can be accused with the offending shares.
+func VerifyEncShareBatchReport(ctx context.Context, suite abstract.Suite, H abstract.Point, X []abstract.Point, sH []abstract.Point, encShares []*PubVerShare) ([]abstract.Point, []*PubVerShare, []*Failure, error) {
+	if len(X) != len(sH) || len(sH) != len(encShares) {
+		return nil, nil, nil, lengthError("verify encrypted shares", len(X), len(sH), len(encShares))
+	}
+	var K []abstract.Point // good public keys
+	var E []*PubVerShare   // good encrypted shares
+	var F []*Failure       // bad encrypted shares
+	idxErrs := indexErrors("verify encrypted", suite, encShares, len(X))
+	for i := 0; i < len(X); i++ {
+		if err := ctx.Err(); err != nil {
+			return K, E, F, err
+		}
+		if idxErrs != nil && idxErrs[i] != nil {
+			F = append(F, &Failure{i, idxErrs[i]})
+			continue
+		}
+		if err := VerifyEncShare(suite, H, X[i], sH[i], encShares[i]); err != nil {
+			F = append(F, &Failure{i, err})
+			continue
+		}
+		K = append(K, X[i])
+		E = append(E, encShares[i])
+	}
+	return K, E, F, nil
+}
+
+// DecShare first verifies the encrypted share against the encryption
+// consistency proof and, if valid, decrypts it and creates a decryption
+// consistency proof.
+func DecShare(suite abstract.Suite, H abstract.Point, X abstract.Point, sH abstract.Point, x abstract.Scalar, encShare *PubVerShare) (_ *PubVerShare, err error) {
+	defer metrics.Start("pvss.DecShare").End(&err)
+
+	if err := VerifyEncShare(suite, H, X, sH, encShare); err != nil {
+		return nil, err
+	}
+	return decShare(suite, X, x, encShare, nil)
+}
+
+// DecShareUnchecked is like DecShare but skips the verification of the
+// encrypted share. It is meant for trustees that already verified the share,
+// e.g., with VerifyEncShareBatch, and takes about half the time of DecShare.
+// Decrypting an unverified share may produce a decrypted share of garbage,
+// which VerifyDecShare accepts but which corrupts the recovered secret.
+func DecShareUnchecked(suite abstract.Suite, X abstract.Point, x abstract.Scalar, encShare *PubVerShare) (_ *PubVerShare, err error) {
+	defer metrics.Start("pvss.DecShareUnchecked").End(&err)
+	return decShare(suite, X, x, encShare, nil)
+}
+
+func decShare(suite abstract.Suite, X abstract.Point, x abstract.Scalar, encShare *PubVerShare, meta *Meta) (*PubVerShare, error) {
+	return decShareInv(suite, X, x, suite.Scalar().Inv(x), encShare, meta)
+}
+
+// decShareInv decrypts the encrypted share with the inverse xi of x and
+// creates the decryption consistency proof bound to meta.
+func decShareInv(suite abstract.Suite, X abstract.Point, x, xi abstract.Scalar, encShare *PubVerShare, meta *Meta) (*PubVerShare, error) {
+	G := suite.Point().Base()
+	V := suite.Point().Mul(encShare.S.V, xi) // decryption: x^{-1} * (xS)
+	ps := &share.PubShare{I: encShare.S.I, V: V}
+	P, _, _, err := proof.NewDLEQProofTagged(suite, G, V, x, meta.tag("dec", encShare.S.I))
+	if err != nil {
+		return nil, &ShareError{"decrypt", encShare.S.I, suite.String(), X, err}
+	}
+	return &PubVerShare{*ps, *P}, nil
+}
+
+// DecShareBatch provides the same functionality as DecShare but for slices of
+// encrypted shares. The function returns the valid encrypted and decrypted
+// shares as well as the corresponding public keys.
+func DecShareBatch(suite abstract.Suite, H abstract.Point, X []abstract.Point, sH []abstract.Point, x abstract.Scalar, encShares []*PubVerShare) ([]abstract.Point, []*PubVerShare, []*PubVerShare, error) {
+	return DecShareBatchContext(context.Background(), suite, H, X, sH, x, encShares)
+}
+
+// DecShareBatchContext is like DecShareBatch but stops once the context is
+// done and then returns the shares decrypted so far together with the
+// context's error.
+func DecShareBatchContext(ctx context.Context, suite abstract.Suite, H abstract.Point, X []abstract.Point, sH []abstract.Point, x abstract.Scalar, encShares []*PubVerShare) ([]abstract.Point, []*PubVerShare, []*PubVerShare, error) {
+	K, E, D, _, err := DecShareBatchReport(ctx, suite, H, X, sH, x, encShares)
+	return K, E, D, err
+}
+
+// DecShareBatchReport is like DecShareBatchContext but also returns a Failure
+// for every encrypted share that could not be decrypted.
+func DecShareBatchReport(ctx context.Context, suite abstract.Suite, H abstract.Point, X []abstract.Point, sH []abstract.Point, x abstract.Scalar, encShares []*PubVerShare) ([]abstract.Point, []*PubVerShare, []*PubVerShare, []*Failure, error) {
+	if len(X) != len(sH) || len(sH) != len(encShares) {
+		return nil, nil, nil, nil, lengthError("decrypt shares", len(X), len(sH), len(encShares))
+	}
+	var K []abstract.Point // good public keys
+	var E []*PubVerShare   // good encrypted shares
+	var D []*PubVerShare   // good decrypted shares
+	var F []*Failure       // bad encrypted shares
+	for i := 0; i < len(encShares); i++ {
+		if err := ctx.Err(); err != nil {
+			return K, E, D, F, err
+		}
+		ds, err := DecShare(suite, H, X[i], sH[i], x, encShares[i])
+		if err != nil {
+			F = append(F, &Failure{i, err})
+			continue
+		}
+		K = append(K, X[i])
+		E = append(E, encShares[i])
+		D = append(D, ds)
+	}
+	return K, E, D, F, nil
+}
+
+// DecShareBatchUnchecked is like DecShareBatch but skips the verification of
+// the encrypted shares, which the caller must have verified before, e.g.,
+// with VerifyEncShareBatch. It returns the decrypted shares in the order of
+// the encrypted shares.
+func DecShareBatchUnchecked(suite abstract.Suite, X []abstract.Point, x abstract.Scalar, encShares []*PubVerShare) ([]*PubVerShare, error) {
+	if len(X) != len(encShares) {
+		return nil, lengthError("decrypt shares", len(X), len(encShares))
+	}
+	D := make([]*PubVerShare, len(encShares))
+	for i, es := range encShares {
+		var err error
+		if D[i], err = decShare(suite, X[i], x, es, nil); err != nil {
+			return nil, err
+		}
+	}
+	return D, nil
+}
+
+// Dealing is the encrypted share of one trustee within a PVSS transcript
+// together with the values needed to verify it.
+type Dealing struct {
+	H        abstract.Point // Base point of the transcript's commitments
+	SH       abstract.Point // Public commitment at the share's index
+	EncShare *PubVerShare   // Encrypted share of the trustee
+}
+
+// DecShareDealings provides the same functionality as DecShare for the
+// encrypted shares of one trustee with key pair (x, X) across many
+// independent transcripts. It computes the inverse of x only once. The
+// returned decrypted shares are aligned with dealings and nil for encrypted
+// shares that fail to verify.
+func DecShareDealings(suite abstract.Suite, X abstract.Point, x abstract.Scalar, dealings []*Dealing) (_ []*PubVerShare, err error) {
+	defer metrics.Start("pvss.DecShareDealings").End(&err)
+
+	xi := suite.Scalar().Inv(x)
+	decShares := make([]*PubVerShare, len(dealings))
+	for i, d := range dealings {
+		if d == nil || VerifyEncShare(suite, d.H, X, d.SH, d.EncShare) != nil {
+			continue
+		}
+		if decShares[i], err = decShareInv(suite, X, x, xi, d.EncShare, nil); err != nil {
+			return nil, err
+		}
+	}
+	return decShares, nil
+}
+
+// VerifyDecShare checks that the decrypted share sG satisfies
+// log_{G}(X) == log_{sG}(sX). Note that X = xG and sX = s(xG) = x(sG). The
+// decrypted share must carry the index of the encrypted share. Like
+// VerifyEncShare, it recomputes the challenge of the proof but does not bind
+// it to a session; use VerifyDecShareMeta to require one.
+func VerifyDecShare(suite abstract.Suite, G abstract.Point, X abstract.Point, encShare *PubVerShare, decShare *PubVerShare) (err error) {
+	defer metrics.Start("pvss.VerifyDecShare").End(&err)
+	return verifyDecShare(suite, G, X, encShare, decShare, nil)
+}
+
+func verifyDecShare(suite abstract.Suite, G abstract.Point, X abstract.Point, encShare *PubVerShare, decShare *PubVerShare, meta *Meta) error {
+	if decShare.S.I != encShare.S.I {
+		return &ShareError{"verify decrypted", decShare.S.I, suite.String(), X, ErrInvalidIndex}
+	}
+	if err := decShare.P.VerifyTagged(suite, G, decShare.S.V, X, encShare.S.V, meta.tag("dec", decShare.S.I)); err != nil {
+		return &ShareError{"verify decrypted", decShare.S.I, suite.String(), X, ErrDecVerification}
+	}
+	return nil
+}
+
+// VerifyDecShareBatch provides the same functionality as VerifyDecShare but for
+// slices of decrypted shares. The function returns the the valid decrypted shares.
+func VerifyDecShareBatch(suite abstract.Suite, G abstract.Point, X []abstract.Point, encShares []*PubVerShare, decShares []*PubVerShare) ([]*PubVerShare, error) {
+	return VerifyDecShareBatchContext(context.Background(), suite, G, X, encShares, decShares)
+}
+
+// VerifyDecShareBatchContext is like VerifyDecShareBatch but stops once the
+// context is done and then returns the valid shares found so far together
+// with the context's error.
+func VerifyDecShareBatchContext(ctx context.Context, suite abstract.Suite, G abstract.Point, X []abstract.Point, encShares []*PubVerShare, decShares []*PubVerShare) ([]*PubVerShare, error) {
+	D, _, err := VerifyDecShareBatchReport(ctx, suite, G, X, encShares, decShares)
+	return D, err
+}
+
+// VerifyDecShareBatchReport is like VerifyDecShareBatchContext but also
+// returns a Failure for every decrypted share that does not verify, so that
+// the misbehaving trustees can be accused.
+func VerifyDecShareBatchReport(ctx context.Context, suite abstract.Suite, G abstract.Point, X []abstract.Point, encShares []*PubVerShare, decShares []*PubVerShare) ([]*PubVerShare, []*Failure, error) {
+	if len(X) != len(encShares) || len(encShares) != len(decShares) {
+		return nil, nil, lengthError("verify decrypted shares", len(X), len(encShares), len(decShares))
+	}
+	var D []*PubVerShare // good decrypted shares
+	var F []*Failure     // bad decrypted shares
+	for i := 0; i < len(X); i++ {
+		if err := ctx.Err(); err != nil {
+			return D, F, err
+		}
+		if err := VerifyDecShare(suite, G, X[i], encShares[i], decShares[i]); err != nil {
+			F = append(F, &Failure{i, err})
+			continue
+		}
+		D = append(D, decShares[i])
+	}
+	return D, F, nil
+}
+
+// RecoverSecret first verifies the given decrypted shares against their
+// decryption consistency proofs and then tries to recover the shared secret.
+// The indices of the valid decrypted shares must be unique and lie in [0, n).
+func RecoverSecret(suite abstract.Suite, G abstract.Point, X []abstract.Point, encShares []*PubVerShare, decShares []*PubVerShare, t int, n int) (abstract.Point, error) {
+	return RecoverSecretContext(context.Background(), suite, G, X, encShares, decShares, t, n)
+}
+
+// RecoverSecretContext is like RecoverSecret but aborts with the context's
+// error once the context is done.
+func RecoverSecretContext(ctx context.Context, suite abstract.Suite, G abstract.Point, X []abstract.Point, encShares []*PubVerShare, decShares []*PubVerShare, t int, n int) (_ abstract.Point, err error) {
+	defer metrics.Start("pvss.RecoverSecret").End(&err)
+
+	D, err := VerifyDecShareBatchContext(ctx, suite, G, X, encShares, decShares)
+	if err != nil {
+		return nil, err
+	}
+	if len(D) < t {
+		return nil, fmt.Errorf("pvss: %d of %d required decrypted shares are valid: %w", len(D), t, ErrTooFewShares)
+	}
+	for _, err := range indexErrors("recover", suite, D, n) {
+		if err != nil {
+			return nil, err
+		}
+	}
+	var shares []*share.PubShare
+	for _, s := range D {
+		shares = append(shares, &s.S)
+	}
+	return share.RecoverCommitContext(ctx, suite, shares, t, n)
+}
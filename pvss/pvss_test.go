@@ -0,0 +1,1406 @@
+package pvss
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dedis/crypto/abstract"
+	"github.com/dedis/crypto/edwards"
+	"github.com/dedis/crypto/proof"
+	"github.com/dedis/crypto/random"
+	"github.com/dedis/crypto/share"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var suite = edwards.NewAES128SHA256Ed25519(false)
+
+func setup(n int) (abstract.Point, []abstract.Scalar, []abstract.Point) {
+	G := suite.Point().Base()
+	x := make([]abstract.Scalar, n)
+	X := make([]abstract.Point, n)
+	for i := 0; i < n; i++ {
+		x[i] = suite.Scalar().Pick(random.Stream)
+		X[i] = suite.Point().Mul(G, x[i])
+	}
+	return G, x, X
+}
+
+// forgeProof returns a proof that xG and xH have the same discrete logarithm
+// with respect to G and H which passes Verify, whether they do or not, since
+// its challenge is chosen instead of computed.
+func forgeProof(G, H, xG, xH abstract.Point) *proof.DLEQProof {
+	c := suite.Scalar().Pick(random.Stream)
+	r := suite.Scalar().Pick(random.Stream)
+	vG := suite.Point().Add(suite.Point().Mul(G, r), suite.Point().Mul(xG, c))
+	vH := suite.Point().Add(suite.Point().Mul(H, r), suite.Point().Mul(xH, c))
+	return &proof.DLEQProof{C: c, R: r, VG: vG, VH: vH}
+}
+
+func TestPVSS(t *testing.T) {
+	n, th := 10, 6
+	G, x, X := setup(n)
+	H, _ := suite.Point().Pick(nil, suite.Cipher([]byte("H")))
+	secret := suite.Scalar().Pick(random.Stream)
+
+	// (1) Share distribution (dealer)
+	encShares, pubPoly, err := EncShares(suite, H, X, secret, th)
+	require.Nil(t, err)
+
+	// (2) Share decryption (trustees)
+	sH := make([]abstract.Point, n)
+	for i := 0; i < n; i++ {
+		sH[i] = pubPoly.Eval(encShares[i].S.I).V
+	}
+
+	var K []abstract.Point // good public keys
+	var E []*PubVerShare   // good encrypted shares
+	var D []*PubVerShare   // good decrypted shares
+	for i := 0; i < n; i++ {
+		ds, err := DecShare(suite, H, X[i], sH[i], x[i], encShares[i])
+		require.Nil(t, err)
+		K = append(K, X[i])
+		E = append(E, encShares[i])
+		D = append(D, ds)
+	}
+
+	// (3) Check decrypted shares and recover secret if possible (dealer/3rd party)
+	recovered, err := RecoverSecret(suite, G, K, E, D, th, n)
+	require.Nil(t, err)
+	assert.True(t, suite.Point().Mul(G, secret).Equal(recovered))
+}
+
+func TestPVSSDelete(t *testing.T) {
+	n, th := 10, 6
+	G, x, X := setup(n)
+	H, _ := suite.Point().Pick(nil, suite.Cipher([]byte("H")))
+	secret := suite.Scalar().Pick(random.Stream)
+
+	encShares, pubPoly, err := EncShares(suite, H, X, secret, th)
+	require.Nil(t, err)
+
+	// Corrupt some of the encrypted shares
+	encShares[0].S.V = suite.Point().Null()
+	encShares[5].S.V = suite.Point().Null()
+
+	sH := make([]abstract.Point, n)
+	for i := 0; i < n; i++ {
+		sH[i] = pubPoly.Eval(encShares[i].S.I).V
+	}
+
+	err = VerifyEncShare(suite, H, X[5], sH[5], encShares[5])
+	assert.True(t, errors.Is(err, ErrEncVerification))
+	var se *ShareError
+	require.True(t, errors.As(err, &se))
+	assert.Equal(t, 5, se.Index)
+	assert.True(t, X[5].Equal(se.Key))
+	assert.Contains(t, se.Error(), X[5].String())
+
+	K, E, err := VerifyEncShareBatch(suite, H, X, sH, encShares)
+	require.Nil(t, err)
+	assert.Len(t, E, n-2)
+
+	var D []*PubVerShare
+	for i, e := range E {
+		j := e.S.I
+		ds, err := DecShare(suite, H, K[i], sH[j], x[j], e)
+		require.Nil(t, err)
+		D = append(D, ds)
+	}
+
+	// Corrupt one of the decrypted shares
+	D[1].S.V = suite.Point().Null()
+
+	recovered, err := RecoverSecret(suite, G, K, E, D, th, n)
+	require.Nil(t, err)
+	assert.True(t, suite.Point().Mul(G, secret).Equal(recovered))
+}
+
+func TestPVSSForgedProof(t *testing.T) {
+	n, th := 5, 3
+	G, x, X := setup(n)
+	H, _ := suite.Point().Pick(nil, suite.Cipher([]byte("H")))
+	encShares, pubPoly, err := EncShares(suite, H, X, suite.Scalar().Pick(random.Stream), th)
+	require.Nil(t, err)
+
+	// An encrypted share of garbage with a forged proof
+	sH := pubPoly.Eval(0).V
+	sX, _ := suite.Point().Pick(nil, random.Stream)
+	forged := &PubVerShare{share.PubShare{I: 0, V: sX}, *forgeProof(H, X[0], sH, sX)}
+	require.Nil(t, forged.P.Verify(suite, H, X[0], sH, sX))
+	assert.True(t, errors.Is(VerifyEncShare(suite, H, X[0], sH, forged), ErrEncVerification))
+	bad := append([]*PubVerShare{forged}, encShares[1:]...)
+	_, E, F, err := VerifyEncShareAggregate(suite, H, X, pubPoly, bad)
+	require.Nil(t, err)
+	assert.Equal(t, n-1, len(E))
+	require.Equal(t, 1, len(F))
+	assert.Equal(t, 0, F[0].Pos)
+
+	// A decrypted share of garbage with a forged proof
+	ds, err := DecShare(suite, H, X[1], pubPoly.Eval(1).V, x[1], encShares[1])
+	require.Nil(t, err)
+	require.Nil(t, VerifyDecShare(suite, G, X[1], encShares[1], ds))
+	V, _ := suite.Point().Pick(nil, random.Stream)
+	forged = &PubVerShare{share.PubShare{I: 1, V: V}, *forgeProof(G, V, X[1], encShares[1].S.V)}
+	require.Nil(t, forged.P.Verify(suite, G, V, X[1], encShares[1].S.V))
+	assert.True(t, errors.Is(VerifyDecShare(suite, G, X[1], encShares[1], forged), ErrDecVerification))
+}
+
+func TestPVSSDeleteFail(t *testing.T) {
+	n, th := 10, 6
+	G, x, X := setup(n)
+	H, _ := suite.Point().Pick(nil, suite.Cipher([]byte("H")))
+	secret := suite.Scalar().Pick(random.Stream)
+
+	encShares, pubPoly, err := EncShares(suite, H, X, secret, th)
+	require.Nil(t, err)
+
+	sH := make([]abstract.Point, n)
+	for i := 0; i < n; i++ {
+		sH[i] = pubPoly.Eval(encShares[i].S.I).V
+	}
+
+	D := make([]*PubVerShare, n)
+	for i := 0; i < n; i++ {
+		D[i], err = DecShare(suite, H, X[i], sH[i], x[i], encShares[i])
+		require.Nil(t, err)
+	}
+
+	// Corrupt too many decrypted shares
+	for i := 0; i < n-th+1; i++ {
+		D[i].S.V = suite.Point().Null()
+	}
+
+	_, err = RecoverSecret(suite, G, X, encShares, D, th, n)
+	assert.True(t, errors.Is(err, ErrTooFewShares))
+}
+
+func TestPVSSBatch(t *testing.T) {
+	n, th := 5, 3
+	G, x, X := setup(n)
+	H, _ := suite.Point().Pick(nil, suite.Cipher([]byte("H")))
+
+	// Three dealers share three secrets
+	s := make([]abstract.Scalar, 3)
+	var sH [][]abstract.Point
+	var enc [][]*PubVerShare
+	for d := range s {
+		s[d] = suite.Scalar().Pick(random.Stream)
+		e, p, err := EncShares(suite, H, X, s[d], th)
+		require.Nil(t, err)
+		h := make([]abstract.Point, n)
+		for i := range h {
+			h[i] = p.Eval(e[i].S.I).V
+		}
+		sH = append(sH, h)
+		enc = append(enc, e)
+	}
+
+	// Trustee 0 decrypts its share of every secret in one go
+	XX := []abstract.Point{X[0], X[0], X[0]}
+	HH := []abstract.Point{sH[0][0], sH[1][0], sH[2][0]}
+	EE := []*PubVerShare{enc[0][0], enc[1][0], enc[2][0]}
+	K, E, D, err := DecShareBatch(suite, H, XX, HH, x[0], EE)
+	require.Nil(t, err)
+	assert.Len(t, K, 3)
+	assert.Len(t, E, 3)
+
+	good, err := VerifyDecShareBatch(suite, G, K, E, D)
+	require.Nil(t, err)
+	assert.Len(t, good, 3)
+
+	_, err = VerifyDecShareBatch(suite, G, K[:2], E, D)
+	assert.True(t, errors.Is(err, ErrDifferentLengths))
+
+	// Having verified the shares, the trustee may skip their re-verification
+	for i := range EE {
+		require.Nil(t, VerifyEncShare(suite, H, XX[i], HH[i], EE[i]))
+	}
+	D2, err := DecShareBatchUnchecked(suite, XX, x[0], EE)
+	require.Nil(t, err)
+	good, err = VerifyDecShareBatch(suite, G, XX, EE, D2)
+	require.Nil(t, err)
+	assert.Len(t, good, 3)
+	for i := range D {
+		assert.True(t, D[i].S.Equal(&D2[i].S))
+	}
+	ds, err := DecShareUnchecked(suite, X[0], x[0], enc[1][0])
+	require.Nil(t, err)
+	assert.Nil(t, VerifyDecShare(suite, G, X[0], enc[1][0], ds))
+	_, err = DecShareBatchUnchecked(suite, XX[:2], x[0], EE)
+	assert.True(t, errors.Is(err, ErrDifferentLengths))
+}
+
+func TestPVSSContext(t *testing.T) {
+	n, th := 5, 3
+	G, x, X := setup(n)
+	H, _ := suite.Point().Pick(nil, suite.Cipher([]byte("H")))
+	secret := suite.Scalar().Pick(random.Stream)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	encShares, pubPoly, err := EncSharesContext(ctx, suite, H, X, secret, th)
+	require.Nil(t, err)
+	sH := make([]abstract.Point, n)
+	for i := range sH {
+		sH[i] = pubPoly.Eval(i).V
+	}
+	D := make([]*PubVerShare, n)
+	for i := range D {
+		D[i], err = DecShare(suite, H, X[i], sH[i], x[i], encShares[i])
+		require.Nil(t, err)
+	}
+	cancel()
+
+	_, _, err = EncSharesContext(ctx, suite, H, X, secret, th)
+	assert.Equal(t, context.Canceled, err)
+	K, E, err := VerifyEncShareBatchContext(ctx, suite, H, X, sH, encShares)
+	assert.Equal(t, context.Canceled, err)
+	assert.Empty(t, K)
+	assert.Empty(t, E)
+	_, err = RecoverSecretContext(ctx, suite, G, X, encShares, D, th, n)
+	assert.Equal(t, context.Canceled, err)
+}
+
+func TestPVSSEqual(t *testing.T) {
+	n, th := 3, 2
+	_, _, X := setup(n)
+	H, _ := suite.Point().Pick(nil, suite.Cipher([]byte("H")))
+	secret := suite.Scalar().Pick(random.Stream)
+
+	encShares, _, err := EncShares(suite, H, X, secret, th)
+	require.Nil(t, err)
+	assert.True(t, encShares[0].Equal(encShares[0]))
+	assert.False(t, encShares[0].Equal(encShares[1]))
+
+	c := *encShares[0]
+	c.P.R = suite.Scalar().Zero()
+	assert.False(t, encShares[0].Equal(&c))
+	assert.False(t, encShares[0].Equal(nil))
+}
+
+func TestPVSSMeta(t *testing.T) {
+	n, th := 5, 3
+	G, x, X := setup(n)
+	H, _ := suite.Point().Pick(nil, suite.Cipher([]byte("H")))
+	secret := suite.Scalar().Pick(random.Stream)
+	meta := &Meta{Epoch: 7, Expiry: time.Now().Add(time.Hour), Dealer: []byte("dealer")}
+
+	encShares, pubPoly, err := EncSharesMeta(suite, H, X, secret, th, meta)
+	require.Nil(t, err)
+	var D []*PubVerShare
+	for i := 0; i < n; i++ {
+		sH := pubPoly.Eval(encShares[i].S.I).V
+		require.Nil(t, VerifyEncShareMeta(suite, H, X[i], sH, encShares[i], meta))
+
+		// Shares from another epoch or dealer, or without metadata, are rejected
+		for _, m := range []*Meta{{Epoch: 6, Expiry: meta.Expiry, Dealer: meta.Dealer}, {Epoch: 7, Expiry: meta.Expiry}, nil} {
+			err := VerifyEncShareMeta(suite, H, X[i], sH, encShares[i], m)
+			assert.True(t, errors.Is(err, ErrEncVerification))
+		}
+
+		ds, err := DecShareMeta(suite, H, X[i], sH, x[i], encShares[i], meta)
+		require.Nil(t, err)
+		D = append(D, ds)
+	}
+	recovered, err := RecoverSecretMeta(suite, G, X, encShares, D, th, n, meta)
+	require.Nil(t, err)
+	assert.True(t, suite.Point().Mul(G, secret).Equal(recovered))
+
+	stale := &Meta{Epoch: 8, Expiry: meta.Expiry, Dealer: meta.Dealer}
+	_, err = RecoverSecretMeta(suite, G, X, encShares, D, th, n, stale)
+	assert.True(t, errors.Is(err, ErrTooFewShares))
+
+	expired := &Meta{Epoch: 7, Expiry: time.Now().Add(-time.Hour), Dealer: meta.Dealer}
+	err = VerifyDecShareMeta(suite, G, X[0], encShares[0], D[0], expired)
+	assert.True(t, errors.Is(err, ErrExpired))
+
+	// Relabelled decrypted shares and negative indices are rejected
+	relabelled := *D[0]
+	relabelled.S.I = 1
+	err = VerifyDecShareMeta(suite, G, X[0], encShares[0], &relabelled, meta)
+	assert.True(t, errors.Is(err, ErrInvalidIndex))
+	negative := *encShares[0]
+	negative.S.I = -1
+	err = VerifyEncShareMeta(suite, H, X[0], pubPoly.Eval(0).V, &negative, meta)
+	assert.True(t, errors.Is(err, ErrInvalidIndex))
+	_, err = RecoverSecretMeta(suite, G, X, encShares, D, th, n-1, meta)
+	assert.True(t, errors.Is(err, ErrInvalidIndex))
+}
+
+func TestPVSSSession(t *testing.T) {
+	n, th := 4, 3
+	G, x, X := setup(n)
+	H, _ := suite.Point().Pick(nil, suite.Cipher([]byte("H")))
+	secret := suite.Scalar().Pick(random.Stream)
+	meta := &Meta{Session: random.Bytes(16, random.Stream), Context: []byte("lottery draw")}
+	other := &Meta{Session: random.Bytes(16, random.Stream), Context: meta.Context}
+
+	encShares, pubPoly, err := EncSharesMeta(suite, H, X, secret, th, meta)
+	require.Nil(t, err)
+	sH := pubPoly.Eval(0).V
+	err = VerifyEncShareMeta(suite, H, X[0], sH, encShares[0], other)
+	assert.True(t, errors.Is(err, ErrEncVerification))
+	err = VerifyEncShareMeta(suite, H, X[0], sH, encShares[0], &Meta{Session: meta.Session})
+	assert.True(t, errors.Is(err, ErrEncVerification))
+
+	// A decrypted share cannot be replayed into another session
+	ds, err := DecShareMeta(suite, H, X[0], sH, x[0], encShares[0], meta)
+	require.Nil(t, err)
+	require.Nil(t, VerifyDecShareMeta(suite, G, X[0], encShares[0], ds, meta))
+	err = VerifyDecShareMeta(suite, G, X[0], encShares[0], ds, other)
+	assert.True(t, errors.Is(err, ErrDecVerification))
+
+	// Hand-offs and re-sharings are bound to the session as well
+	r := suite.Scalar().Pick(random.Stream)
+	R := suite.Point().Mul(G, r)
+	h, err := HandoffShareMeta(suite, X[1], x[1], encShares[1], R, meta)
+	require.Nil(t, err)
+	require.Nil(t, VerifyHandoffMeta(suite, X[1], encShares[1], R, h, meta))
+	for _, m := range []*Meta{other, nil} {
+		err = VerifyHandoffMeta(suite, X[1], encShares[1], R, h, m)
+		assert.True(t, errors.Is(err, ErrHandoffVerification))
+	}
+
+	_, newx, newX := setup(n)
+	var reshares []*Reshare
+	for i := 0; i < th; i++ {
+		sH := pubPoly.Eval(i).V
+		rs, err := ReshareShareMeta(suite, H, X[i], x[i], sH, encShares[i], newX, th, meta)
+		require.Nil(t, err)
+		require.Nil(t, VerifyReshareMeta(suite, H, X[i], sH, encShares[i], newX, th, rs, meta))
+		err = VerifyReshareMeta(suite, H, X[i], sH, encShares[i], newX, th, rs, other)
+		assert.True(t, errors.Is(err, ErrReshareVerification))
+		assert.Error(t, VerifyReshare(suite, H, X[i], sH, encShares[i], newX, th, rs))
+		reshares = append(reshares, rs)
+	}
+	decShares := make([]*PubVerShare, n)
+	for j := range decShares {
+		decShares[j], err = DecReshareMeta(suite, newX[j], newx[j], j, reshares, th, n, meta)
+		require.Nil(t, err)
+		require.Nil(t, VerifyDecReshareMeta(suite, newX[j], reshares, th, n, decShares[j], meta))
+	}
+	err = VerifyDecReshareMeta(suite, newX[0], reshares, th, n, decShares[0], other)
+	assert.True(t, errors.Is(err, ErrDecVerification))
+	recovered, err := RecoverResharedSecretMeta(suite, newX, reshares, th, n, decShares, th, meta)
+	require.Nil(t, err)
+	assert.True(t, suite.Point().Mul(G, secret).Equal(recovered))
+	_, err = RecoverResharedSecretMeta(suite, newX, reshares, th, n, decShares, th, other)
+	assert.True(t, errors.Is(err, ErrTooFewShares))
+}
+
+func TestDeletion(t *testing.T) {
+	n, th := 4, 3
+	G, x, X := setup(n)
+	H, _ := suite.Point().Pick(nil, suite.Cipher([]byte("H")))
+	encShares, pubPoly, err := EncShares(suite, H, X, suite.Scalar().Pick(random.Stream), th)
+	require.Nil(t, err)
+	ds, err := DecShare(suite, H, X[0], pubPoly.Eval(0).V, x[0], encShares[0])
+	require.Nil(t, err)
+
+	// A failed deletion leaves the decrypted share untouched
+	_, err = DeleteShare(suite, X[0], x[1], encShares[0], ds)
+	assert.True(t, errors.Is(err, ErrDelVerification))
+	_, err = DeleteShare(suite, X[1], x[1], encShares[1], ds)
+	assert.True(t, errors.Is(err, ErrDelVerification))
+	require.Nil(t, VerifyDecShare(suite, G, X[0], encShares[0], ds))
+
+	del, err := DeleteShare(suite, X[0], x[0], encShares[0], ds)
+	require.Nil(t, err)
+	assert.True(t, ds.S.V.Equal(suite.Point().Null()))
+	require.Nil(t, VerifyDeletion(suite, X[0], encShares[0], del))
+
+	// The statement is bound to the trustee and the encrypted share
+	assert.True(t, errors.Is(VerifyDeletion(suite, X[1], encShares[0], del), ErrDelVerification))
+	assert.True(t, errors.Is(VerifyDeletion(suite, X[0], encShares[1], del), ErrDelVerification))
+
+	_, err = DeleteShare(suite, X[1], x[0], encShares[1], nil)
+	assert.True(t, errors.Is(err, ErrDelVerification))
+
+	// Session-bound statements only verify within their session
+	meta := &Meta{Session: []byte("session 1")}
+	del, err = DeleteShareMeta(suite, X[1], x[1], encShares[1], nil, meta)
+	require.Nil(t, err)
+	require.Nil(t, VerifyDeletionMeta(suite, X[1], encShares[1], del, meta))
+	assert.Error(t, VerifyDeletionMeta(suite, X[1], encShares[1], del, &Meta{Session: []byte("session 2")}))
+	assert.Error(t, VerifyDeletion(suite, X[1], encShares[1], del))
+}
+
+func TestDecShareDealings(t *testing.T) {
+	n, th, m := 4, 3, 5
+	G, x, X := setup(n)
+	var dealings []*Dealing
+	var encs [][]*PubVerShare
+	for j := 0; j < m; j++ {
+		H, _ := suite.Point().Pick(nil, random.Stream)
+		s := suite.Scalar().Pick(random.Stream)
+		encShares, pubPoly, err := EncShares(suite, H, X, s, th)
+		require.Nil(t, err)
+		dealings = append(dealings, &Dealing{H, pubPoly.Eval(0).V, encShares[0]})
+		encs = append(encs, encShares)
+	}
+	// A dealing with a commitment of another index is rejected
+	dealings[2].SH, _ = suite.Point().Pick(nil, random.Stream)
+
+	decShares, err := DecShareDealings(suite, X[0], x[0], dealings)
+	require.Nil(t, err)
+	require.Equal(t, m, len(decShares))
+	assert.Nil(t, decShares[2])
+	for j, ds := range decShares {
+		if j == 2 {
+			continue
+		}
+		require.NotNil(t, ds)
+		require.Nil(t, VerifyDecShare(suite, G, X[0], encs[j][0], ds))
+		expect := suite.Point().Mul(encs[j][0].S.V, suite.Scalar().Inv(x[0]))
+		assert.True(t, expect.Equal(ds.S.V))
+	}
+}
+
+func TestPubVerShareBinary(t *testing.T) {
+	n, th := 4, 3
+	_, x, X := setup(n)
+	H, _ := suite.Point().Pick(nil, suite.Cipher([]byte("H")))
+	encShares, pubPoly, err := EncShares(suite, H, X, suite.Scalar().Pick(random.Stream), th)
+	require.Nil(t, err)
+	ds, err := DecShare(suite, H, X[2], pubPoly.Eval(2).V, x[2], encShares[2])
+	require.Nil(t, err)
+
+	for _, s := range []*PubVerShare{encShares[2], ds} {
+		buf, err := s.MarshalBinary()
+		require.Nil(t, err)
+		assert.Equal(t, s.MarshalSize(), len(buf))
+
+		// The encoding matches the suite's reflective encoding
+		var b bytes.Buffer
+		require.Nil(t, suite.Write(&b, s))
+		assert.Equal(t, b.Bytes(), buf)
+
+		dec := NewPubVerShare(suite)
+		require.Nil(t, dec.UnmarshalBinary(buf))
+		assert.True(t, s.Equal(dec))
+		assert.True(t, errors.Is(dec.UnmarshalBinary(buf[1:]), ErrEncoding))
+	}
+	assert.True(t, errors.Is(new(PubVerShare).UnmarshalBinary(nil), ErrEncoding))
+}
+
+func TestPVSSBatchReport(t *testing.T) {
+	n, th := 6, 4
+	G, x, X := setup(n)
+	H, _ := suite.Point().Pick(nil, suite.Cipher([]byte("H")))
+	secret := suite.Scalar().Pick(random.Stream)
+
+	encShares, pubPoly, err := EncShares(suite, H, X, secret, th)
+	require.Nil(t, err)
+	sH := make([]abstract.Point, n)
+	for i := range sH {
+		sH[i] = pubPoly.Eval(encShares[i].S.I).V
+	}
+
+	// The dealer cheats on share 2
+	encShares[2].S.V = suite.Point().Base()
+	K, E, F, err := VerifyEncShareBatchReport(context.Background(), suite, H, X, sH, encShares)
+	require.Nil(t, err)
+	assert.Len(t, K, n-1)
+	assert.Len(t, E, n-1)
+	require.Len(t, F, 1)
+	assert.Equal(t, 2, F[0].Pos)
+	assert.True(t, errors.Is(F[0].Err, ErrEncVerification))
+
+	// Trustee 2 refuses to decrypt the bad share
+	_, _, D, F, err := DecShareBatchReport(context.Background(), suite, H, X[2:], sH[2:], x[2], encShares[2:])
+	require.Nil(t, err)
+	assert.Len(t, D, n-3)
+	require.Len(t, F, 1)
+	assert.Equal(t, 0, F[0].Pos)
+
+	// Trustee 4 publishes a bad decrypted share
+	D = make([]*PubVerShare, len(E))
+	for i, e := range E {
+		j := e.S.I
+		D[i], err = DecShare(suite, H, K[i], sH[j], x[j], e)
+		require.Nil(t, err)
+	}
+	D[3].S.V = suite.Point().Null()
+	good, F, err := VerifyDecShareBatchReport(context.Background(), suite, G, K, E, D)
+	require.Nil(t, err)
+	assert.Len(t, good, n-2)
+	require.Len(t, F, 1)
+	assert.Equal(t, 3, F[0].Pos)
+	var se *ShareError
+	require.True(t, errors.As(F[0].Err, &se))
+	assert.Equal(t, 4, se.Index)
+	assert.True(t, K[3].Equal(se.Key))
+	assert.True(t, errors.Is(se, ErrDecVerification))
+}
+
+func TestPVSSParallel(t *testing.T) {
+	n, th := 20, 11
+	G, x, X := setup(n)
+	H, _ := suite.Point().Pick(nil, suite.Cipher([]byte("H")))
+	secret := suite.Scalar().Pick(random.Stream)
+
+	encShares, pubPoly, err := EncShares(suite, H, X, secret, th)
+	require.Nil(t, err)
+	sH := make([]abstract.Point, n)
+	for i := range sH {
+		sH[i] = pubPoly.Eval(encShares[i].S.I).V
+	}
+	encShares[7].S.V = suite.Point().Base()
+
+	ctx := context.Background()
+	K, E, F, err := VerifyEncShareBatchParallel(ctx, suite, H, X, sH, encShares, 4)
+	require.Nil(t, err)
+	K2, E2, F2, err := VerifyEncShareBatchReport(ctx, suite, H, X, sH, encShares)
+	require.Nil(t, err)
+	assert.Equal(t, K2, K)
+	assert.Equal(t, E2, E)
+	require.Len(t, F, 1)
+	assert.Equal(t, F2[0].Pos, F[0].Pos)
+
+	D := make([]*PubVerShare, len(E))
+	for i, e := range E {
+		j := e.S.I
+		D[i], err = DecShare(suite, H, K[i], sH[j], x[j], e)
+		require.Nil(t, err)
+	}
+	D[0].S.V = suite.Point().Null()
+	good, F, err := VerifyDecShareBatchParallel(ctx, suite, G, K, E, D, 0)
+	require.Nil(t, err)
+	assert.Equal(t, D[1:], good)
+	require.Len(t, F, 1)
+	assert.Equal(t, 0, F[0].Pos)
+
+	cctx, cancel := context.WithCancel(ctx)
+	cancel()
+	good, _, err = VerifyDecShareBatchParallel(cctx, suite, G, K, E, D, 0)
+	assert.Equal(t, context.Canceled, err)
+	assert.Empty(t, good)
+}
+
+func benchmarkVerifyEncShareBatch(b *testing.B, workers int) {
+	n, th := 128, 65
+	_, _, X := setup(n)
+	H, _ := suite.Point().Pick(nil, suite.Cipher([]byte("H")))
+	encShares, pubPoly, err := EncShares(suite, H, X, suite.Scalar().Pick(random.Stream), th)
+	require.Nil(b, err)
+	sH := make([]abstract.Point, n)
+	for i := range sH {
+		sH[i] = pubPoly.Eval(encShares[i].S.I).V
+	}
+	ctx := context.Background()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if workers == 1 {
+			_, _, _, err = VerifyEncShareBatchReport(ctx, suite, H, X, sH, encShares)
+		} else {
+			_, _, _, err = VerifyEncShareBatchParallel(ctx, suite, H, X, sH, encShares, workers)
+		}
+		require.Nil(b, err)
+	}
+}
+
+func BenchmarkVerifyEncShareBatch(b *testing.B)          { benchmarkVerifyEncShareBatch(b, 1) }
+func BenchmarkVerifyEncShareBatchParallel2(b *testing.B) { benchmarkVerifyEncShareBatch(b, 2) }
+func BenchmarkVerifyEncShareBatchParallel4(b *testing.B) { benchmarkVerifyEncShareBatch(b, 4) }
+func BenchmarkVerifyEncShareBatchParallel(b *testing.B)  { benchmarkVerifyEncShareBatch(b, 0) }
+
+func TestReshare(t *testing.T) {
+	n, th := 5, 3
+	newN, newT := 7, 4
+	G, x, X := setup(n)
+	_, newx, newX := setup(newN)
+	H, _ := suite.Point().Pick(nil, suite.Cipher([]byte("H")))
+	secret := suite.Scalar().Pick(random.Stream)
+
+	encShares, pubPoly, err := EncShares(suite, H, X, secret, th)
+	require.Nil(t, err)
+
+	// Old trustees 0, 2 and 3 re-share their shares
+	var reshares []*Reshare
+	for _, i := range []int{0, 2, 3} {
+		require.Nil(t, VerifyEncShare(suite, H, X[i], pubPoly.Eval(i).V, encShares[i]))
+		r, err := ReshareShare(suite, H, X[i], x[i], pubPoly.Eval(i).V, encShares[i], newX, newT)
+		require.Nil(t, err)
+		require.Nil(t, VerifyReshare(suite, H, X[i], pubPoly.Eval(i).V, encShares[i], newX, newT, r))
+		reshares = append(reshares, r)
+	}
+
+	// A re-sharing does not verify for another encrypted share, commitment,
+	// base or threshold
+	sH0 := pubPoly.Eval(0).V
+	assert.Error(t, VerifyReshare(suite, H, X[1], pubPoly.Eval(1).V, encShares[1], newX, newT, reshares[0]))
+	assert.Error(t, VerifyReshare(suite, H, X[0], pubPoly.Eval(1).V, encShares[0], newX, newT, reshares[0]))
+	assert.Error(t, VerifyReshare(suite, G, X[0], sH0, encShares[0], newX, newT, reshares[0]))
+	assert.Error(t, VerifyReshare(suite, H, X[0], sH0, encShares[0], newX, newT-1, reshares[0]))
+	bad := *reshares[1]
+	bad.V = append([]abstract.Point{}, bad.V...)
+	bad.V[5] = suite.Point().Add(bad.V[5], G)
+	err = VerifyReshare(suite, H, X[2], pubPoly.Eval(2).V, encShares[2], newX, newT, &bad)
+	assert.True(t, errors.Is(err, ErrReshareVerification))
+
+	// New trustees decrypt their new shares
+	decShares := make([]*PubVerShare, newN)
+	for j := range decShares {
+		decShares[j], err = DecReshare(suite, newX[j], newx[j], j, reshares, th, n)
+		require.Nil(t, err)
+		require.Nil(t, VerifyDecReshare(suite, newX[j], reshares, th, n, decShares[j]))
+	}
+	// A single new share reveals nothing about the secret: unmasking it with
+	// the public commitments does not yield sG
+	sG := suite.Point().Mul(G, secret)
+	var us []*share.PubShare
+	for k, i := range []int{0, 2, 3} {
+		us = append(us, &share.PubShare{I: i, V: reshares[k].Commits.Eval(0).V})
+	}
+	unmasked, err := share.RecoverCommit(suite, us, th, n)
+	require.Nil(t, err)
+	assert.False(t, suite.Point().Sub(decShares[0].S.V, unmasked).Equal(sG))
+
+	// The combined commitments commit to the secret with respect to H
+	combined := make([]*share.PubShare, len(reshares))
+	for k, i := range []int{0, 2, 3} {
+		combined[k] = &share.PubShare{I: i, V: reshares[k].Commits.Commit()}
+	}
+	sH, err := share.RecoverCommit(suite, combined, th, n)
+	require.Nil(t, err)
+	assert.True(t, pubPoly.Commit().Equal(sH))
+
+	// Without metadata, the proofs are bound to the zero Meta
+	require.Nil(t, VerifyDecReshareMeta(suite, newX[0], reshares, th, n, decShares[0], &Meta{}))
+	err = VerifyDecReshareMeta(suite, newX[0], reshares, th, n, decShares[0], &Meta{Epoch: 1})
+	assert.True(t, errors.Is(err, ErrDecVerification))
+	decShares[1].S.V = G
+	decShares[4] = nil
+
+	recovered, err := RecoverResharedSecret(suite, newX, reshares, th, n, decShares, newT)
+	require.Nil(t, err)
+	assert.True(t, suite.Point().Mul(G, secret).Equal(recovered))
+
+	_, err = RecoverResharedSecret(suite, newX, reshares, th, n, decShares[:5], newT)
+	assert.True(t, errors.Is(err, ErrTooFewShares))
+
+	// A repeated decrypted share is rejected
+	dup := []*PubVerShare{decShares[0], decShares[0], decShares[2], decShares[3], decShares[5]}
+	_, err = RecoverResharedSecret(suite, newX, reshares, th, n, dup, newT)
+	assert.True(t, errors.Is(err, ErrDuplicateShare))
+}
+
+func TestDealerParticipant(t *testing.T) {
+	n, th := 5, 3
+	G, x, X := setup(n)
+	H, _ := suite.Point().Pick(nil, suite.Cipher([]byte("H")))
+	secret := suite.Scalar().Pick(random.Stream)
+
+	dealer := NewDealer(suite, H, X, th)
+	deal, err := dealer.Deal(secret)
+	require.Nil(t, err)
+	_, err = dealer.Deal(secret)
+	assert.Equal(t, ErrState, err)
+
+	_, err = NewParticipant(suite, H, X, suite.Scalar().Pick(random.Stream), th)
+	assert.Equal(t, ErrNotParticipant, err)
+	observer, err := NewParticipant(suite, H, X, nil, th)
+	require.Nil(t, err)
+	_, err = observer.RecoverSecret()
+	assert.Equal(t, ErrState, err)
+
+	// Trustee 4 gets a bad share
+	deal.EncShares[4].S.V = G
+	failures, err := observer.ProcessDeal(deal)
+	require.Nil(t, err)
+	require.Len(t, failures, 1)
+	assert.Equal(t, 4, failures[0].Pos)
+	_, err = observer.ProcessDeal(deal)
+	assert.Equal(t, ErrState, err)
+	_, err = observer.DecShare()
+	assert.Equal(t, ErrNotParticipant, err)
+
+	parts := make([]*Participant, n)
+	for i := range parts {
+		parts[i], err = NewParticipant(suite, H, X, x[i], th)
+		require.Nil(t, err)
+		assert.Equal(t, i, parts[i].Index())
+		_, err = parts[i].DecShare()
+		assert.Equal(t, ErrState, err)
+		_, err = parts[i].ProcessDeal(deal)
+		if i == 4 {
+			assert.True(t, errors.Is(err, ErrEncVerification))
+			continue
+		}
+		require.Nil(t, err)
+	}
+
+	for i := 0; i < th; i++ {
+		ds, err := parts[i].DecShare()
+		require.Nil(t, err)
+		_, err = parts[i].DecShare()
+		assert.Equal(t, ErrState, err)
+		require.Nil(t, observer.ProcessDecShare(ds))
+		_, err = observer.RecoverSecret()
+		if i < th-1 {
+			assert.True(t, errors.Is(err, ErrTooFewShares))
+		}
+	}
+	bad := &PubVerShare{share.PubShare{I: 3, V: G}, deal.EncShares[3].P}
+	assert.True(t, errors.Is(observer.ProcessDecShare(bad), ErrDecVerification))
+
+	recovered, err := observer.RecoverSecret()
+	require.Nil(t, err)
+	assert.True(t, suite.Point().Mul(G, secret).Equal(recovered))
+}
+
+func TestVerifyEncShareAggregate(t *testing.T) {
+	n, th := 10, 6
+	_, _, X := setup(n)
+	H, _ := suite.Point().Pick(nil, suite.Cipher([]byte("H")))
+	encShares, pubPoly, err := EncShares(suite, H, X, suite.Scalar().Pick(random.Stream), th)
+	require.Nil(t, err)
+
+	K, E, F, err := VerifyEncShareAggregate(suite, H, X, pubPoly, encShares)
+	require.Nil(t, err)
+	assert.Len(t, K, n)
+	assert.Len(t, E, n)
+	assert.Empty(t, F)
+
+	// A single bad share makes the aggregate check fail and is identified
+	encShares[3].P.R = suite.Scalar().Pick(random.Stream)
+	K, E, F, err = VerifyEncShareAggregate(suite, H, X, pubPoly, encShares)
+	require.Nil(t, err)
+	assert.Len(t, K, n-1)
+	assert.Len(t, E, n-1)
+	require.Len(t, F, 1)
+	assert.Equal(t, 3, F[0].Pos)
+
+	_, _, _, err = VerifyEncShareAggregate(suite, H, X[1:], pubPoly, encShares)
+	assert.True(t, errors.Is(err, ErrDifferentLengths))
+
+	// Invalid and duplicate indices are rejected like by VerifyEncShareBatchReport
+	encShares, pubPoly, err = EncShares(suite, H, X, suite.Scalar().Pick(random.Stream), th)
+	require.Nil(t, err)
+	bad := make([]*PubVerShare, n)
+	copy(bad, encShares)
+	neg := *encShares[0]
+	neg.S.I = -1
+	bad[0] = &neg
+	bad[2] = encShares[1]
+	_, E, F, err = VerifyEncShareAggregate(suite, H, X, pubPoly, bad)
+	require.Nil(t, err)
+	assert.Len(t, E, n-3)
+	require.Len(t, F, 3)
+	for _, f := range F {
+		assert.True(t, errors.Is(f.Err, ErrInvalidIndex) || errors.Is(f.Err, ErrDuplicateShare))
+	}
+}
+
+func BenchmarkVerifyEncShareAggregate(b *testing.B) {
+	n, th := 128, 65
+	_, _, X := setup(n)
+	H, _ := suite.Point().Pick(nil, suite.Cipher([]byte("H")))
+	encShares, pubPoly, err := EncShares(suite, H, X, suite.Scalar().Pick(random.Stream), th)
+	require.Nil(b, err)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _, F, err := VerifyEncShareAggregate(suite, H, X, pubPoly, encShares)
+		require.Nil(b, err)
+		require.Empty(b, F)
+	}
+}
+
+func TestVerifier(t *testing.T) {
+	n, th := 6, 3
+	G, x, X := setup(n)
+	H, _ := suite.Point().Pick(nil, suite.Cipher([]byte("H")))
+	secret := suite.Scalar().Pick(random.Stream)
+	encShares, pubPoly, err := EncShares(suite, H, X, secret, th)
+	require.Nil(t, err)
+	genuine := *encShares[1]
+	// A third party forges a share of garbage with a proof that passes
+	// DLEQProof.Verify
+	sH := pubPoly.Eval(1).V
+	encShares[1].S.V = G
+	encShares[1].P = *forgeProof(H, X[1], sH, G)
+	require.Nil(t, encShares[1].P.Verify(suite, H, X[1], sH, G))
+
+	v := NewVerifier(suite, H, X, pubPoly)
+	// Shares arrive out of order
+	for k, i := range []int{5, 1, 0, 3} {
+		ready, err := v.AddEncShare(encShares[i])
+		if i == 1 {
+			assert.True(t, errors.Is(err, ErrEncVerification))
+			continue
+		}
+		require.Nil(t, err)
+		assert.Equal(t, k == 3, ready)
+	}
+	_, err = v.AddEncShare(encShares[1])
+	assert.True(t, errors.Is(err, ErrEncVerification))
+	assert.Equal(t, []int{0, 3, 5}, v.Qualified())
+	assert.Equal(t, []int{1}, v.Disqualified())
+
+	// A forged share does not shut out the genuine one
+	_, err = v.AddEncShare(&genuine)
+	require.Nil(t, err)
+	_, err = v.AddEncShare(&genuine)
+	assert.True(t, errors.Is(err, ErrDuplicateShare))
+	assert.Equal(t, []int{0, 1, 3, 5}, v.Qualified())
+	assert.Empty(t, v.Disqualified())
+
+	// Decrypted shares need a valid encrypted share
+	ds, err := DecShare(suite, H, X[2], pubPoly.Eval(2).V, x[2], encShares[2])
+	require.Nil(t, err)
+	_, err = v.AddDecShare(ds)
+	assert.True(t, errors.Is(err, ErrDecVerification))
+
+	_, err = v.RecoverSecret()
+	assert.True(t, errors.Is(err, ErrTooFewShares))
+	for k, i := range []int{3, 0, 5} {
+		ds, err := DecShare(suite, H, X[i], pubPoly.Eval(i).V, x[i], encShares[i])
+		require.Nil(t, err)
+		ready, err := v.AddDecShare(ds)
+		require.Nil(t, err)
+		assert.Equal(t, k == 2, ready)
+		_, err = v.AddDecShare(ds)
+		assert.True(t, errors.Is(err, ErrDuplicateShare))
+	}
+	recovered, err := v.RecoverSecret()
+	require.Nil(t, err)
+	assert.True(t, suite.Point().Mul(G, secret).Equal(recovered))
+}
+
+func TestRecoverScalarSecret(t *testing.T) {
+	n, th := 5, 3
+	G, x, X := setup(n)
+	H, _ := suite.Point().Pick(nil, suite.Cipher([]byte("H")))
+	secret := suite.Scalar().Pick(random.Stream)
+
+	encShares, pubPoly, sealed, err := EncSharesScalar(suite, H, X, secret, th)
+	require.Nil(t, err)
+	D := make([]*PubVerShare, n)
+	for i := range D {
+		D[i], err = DecShare(suite, H, X[i], pubPoly.Eval(i).V, x[i], encShares[i])
+		require.Nil(t, err)
+	}
+	s, err := RecoverScalarSecret(suite, G, X, encShares, D, th, n, sealed)
+	require.Nil(t, err)
+	assert.True(t, s.Equal(secret))
+
+	// The sealed secret only opens with the right group element
+	_, err = OpenSecret(suite, G, sealed)
+	assert.True(t, errors.Is(err, ErrSealedSecret))
+	other, err := SealSecret(suite, suite.Scalar().Pick(random.Stream))
+	require.Nil(t, err)
+	_, err = RecoverScalarSecret(suite, G, X, encShares, D, th, n, other)
+	assert.True(t, errors.Is(err, ErrSealedSecret))
+}
+
+func TestVerifyEncSharesScrape(t *testing.T) {
+	n, th := 12, 5
+	_, _, X := setup(n)
+	H, _ := suite.Point().Pick(nil, suite.Cipher([]byte("H")))
+	encShares, pubPoly, err := EncShares(suite, H, X, suite.Scalar().Pick(random.Stream), th)
+	require.Nil(t, err)
+	sH := make([]abstract.Point, n)
+	for i, s := range pubPoly.Shares(n) {
+		sH[i] = s.V
+	}
+
+	K, E, F, err := VerifyEncSharesScrape(suite, H, X, sH, th, encShares)
+	require.Nil(t, err)
+	assert.Len(t, K, n)
+	assert.Len(t, E, n)
+	assert.Empty(t, F)
+
+	// Bad proofs are identified individually
+	encShares[2].P.R = suite.Scalar().Pick(random.Stream)
+	_, E, F, err = VerifyEncSharesScrape(suite, H, X, sH, th, encShares)
+	require.Nil(t, err)
+	assert.Len(t, E, n-1)
+	require.Len(t, F, 1)
+	assert.Equal(t, 2, F[0].Pos)
+
+	// Commitments of a polynomial of too high degree are rejected
+	higher := share.NewPriPoly(suite, th+1, nil, random.Stream).Commit(H)
+	for i, s := range higher.Shares(n) {
+		sH[i] = s.V
+	}
+	_, _, _, err = VerifyEncSharesScrape(suite, H, X, sH, th, encShares)
+	assert.True(t, errors.Is(err, ErrEncVerification))
+
+	// With t == n, the degree check is vacuous
+	_, _, _, err = VerifyEncSharesScrape(suite, H, X, sH, n, encShares)
+	assert.Nil(t, err)
+}
+
+func TestPVSSEpochContext(t *testing.T) {
+	n, th := 4, 3
+	G, x, X := setup(n)
+	H, _ := suite.Point().Pick(nil, suite.Cipher([]byte("H")))
+	secret := suite.Scalar().Pick(random.Stream)
+	epoch1, epoch2 := &Meta{Context: []byte("epoch 1")}, &Meta{Context: []byte("epoch 2")}
+
+	encShares, pubPoly, err := EncSharesMeta(suite, H, X, secret, th, epoch1)
+	require.Nil(t, err)
+	D := make([]*PubVerShare, n)
+	for i := range D {
+		sH := pubPoly.Eval(i).V
+		require.Nil(t, VerifyEncShareMeta(suite, H, X[i], sH, encShares[i], epoch1))
+		err := VerifyEncShareMeta(suite, H, X[i], sH, encShares[i], epoch2)
+		assert.True(t, errors.Is(err, ErrEncVerification))
+		D[i], err = DecShareMeta(suite, H, X[i], sH, x[i], encShares[i], epoch1)
+		require.Nil(t, err)
+		require.Nil(t, VerifyDecShareMeta(suite, G, X[i], encShares[i], D[i], epoch1))
+	}
+	recovered, err := RecoverSecretMeta(suite, G, X, encShares, D, th, n, epoch1)
+	require.Nil(t, err)
+	assert.True(t, suite.Point().Mul(G, secret).Equal(recovered))
+
+	// Replaying the decrypted shares in another epoch fails
+	_, err = RecoverSecretMeta(suite, G, X, encShares, D, th, n, epoch2)
+	assert.True(t, errors.Is(err, ErrTooFewShares))
+}
+
+func TestPVSSWeighted(t *testing.T) {
+	n := 4
+	weights := []int{3, 1, 0, 2}
+	th := 4
+	G, x, X := setup(n)
+	H, _ := suite.Point().Pick(nil, suite.Cipher([]byte("H")))
+	secret := suite.Scalar().Pick(random.Stream)
+
+	encShares, pubPoly, err := EncSharesWeighted(suite, H, X, weights, secret, th)
+	require.Nil(t, err)
+	next := 0
+	for i, es := range encShares {
+		require.Equal(t, weights[i], len(es))
+		for _, s := range es {
+			require.Equal(t, next, s.S.I)
+			require.Nil(t, VerifyEncShare(suite, H, X[i], pubPoly.Eval(s.S.I).V, s))
+			next++
+		}
+	}
+
+	decShares := make([][]*PubVerShare, n)
+	for _, i := range []int{0, 1} {
+		decShares[i], err = DecSharesWeighted(suite, H, X[i], pubPoly, x[i], encShares[i])
+		require.Nil(t, err)
+	}
+	recovered, err := RecoverSecretWeighted(suite, G, X, weights, encShares, decShares, th)
+	require.Nil(t, err)
+	require.True(t, suite.Point().Mul(G, secret).Equal(recovered))
+
+	// Participants 1 and 3 hold only 3 shares
+	decShares = make([][]*PubVerShare, n)
+	for _, i := range []int{1, 3} {
+		decShares[i], err = DecSharesWeighted(suite, H, X[i], pubPoly, x[i], encShares[i])
+		require.Nil(t, err)
+	}
+	_, err = RecoverSecretWeighted(suite, G, X, weights, encShares, decShares, th)
+	assert.True(t, errors.Is(err, ErrTooFewShares))
+
+	_, _, err = EncSharesWeighted(suite, H, X, []int{1, -1, 1, 1}, secret, 2)
+	assert.True(t, errors.Is(err, ErrWeights))
+	_, _, err = EncSharesWeighted(suite, H, X, weights, secret, 7)
+	assert.True(t, errors.Is(err, ErrWeights))
+	_, _, err = EncSharesWeighted(suite, H, X, weights[:3], secret, th)
+	assert.True(t, errors.Is(err, ErrDifferentLengths))
+}
+
+func TestTranscript(t *testing.T) {
+	n, th := 6, 4
+	G, x, X := setup(n)
+	H, _ := suite.Point().Pick(nil, suite.Cipher([]byte("H")))
+	dealer := suite.Point().Mul(G, suite.Scalar().Pick(random.Stream))
+	secret := suite.Scalar().Pick(random.Stream)
+
+	encShares, pubPoly, err := EncShares(suite, H, X, secret, th)
+	require.Nil(t, err)
+	var decShares []*PubVerShare
+	for _, i := range []int{5, 1, 2, 4} {
+		ds, err := DecShare(suite, H, X[i], pubPoly.Eval(i).V, x[i], encShares[i])
+		require.Nil(t, err)
+		decShares = append(decShares, ds)
+	}
+	tr := &Transcript{
+		Dealer:    dealer,
+		H:         H,
+		X:         X,
+		T:         th,
+		Commits:   pubPoly,
+		EncShares: encShares,
+		DecShares: decShares,
+		Secret:    suite.Point().Mul(G, secret),
+	}
+	require.Nil(t, tr.Verify(suite))
+
+	buf, err := tr.MarshalBinary()
+	require.Nil(t, err)
+	dec, err := UnmarshalTranscript(suite, buf)
+	require.Nil(t, err)
+	require.Nil(t, dec.Verify(suite))
+	assert.True(t, dec.Dealer.Equal(dealer))
+	assert.True(t, dec.Commits.Equal(pubPoly))
+	assert.True(t, dec.Secret.Equal(tr.Secret))
+	for i := range decShares {
+		assert.True(t, dec.DecShares[i].Equal(decShares[i]))
+	}
+
+	_, err = UnmarshalTranscript(suite, buf[:len(buf)-1])
+	assert.True(t, errors.Is(err, ErrEncoding))
+	_, err = UnmarshalTranscript(suite, append(buf, 0))
+	assert.True(t, errors.Is(err, ErrEncoding))
+
+	// A transcript without the secret verifies as well
+	tr.Secret = nil
+	require.Nil(t, tr.Verify(suite))
+	buf, err = tr.MarshalBinary()
+	require.Nil(t, err)
+	dec, err = UnmarshalTranscript(suite, buf)
+	require.Nil(t, err)
+	assert.Nil(t, dec.Secret)
+
+	// Tampering is detected
+	tr.Secret = suite.Point().Mul(G, suite.Scalar().Pick(random.Stream))
+	assert.True(t, errors.Is(tr.Verify(suite), ErrDecVerification))
+	tr.Secret = nil
+	tr.DecShares = []*PubVerShare{decShares[0], decShares[0]}
+	assert.True(t, errors.Is(tr.Verify(suite), ErrDecVerification))
+	tr.DecShares = decShares
+	tr.EncShares = append([]*PubVerShare(nil), encShares...)
+	tr.EncShares[3] = encShares[2]
+	assert.True(t, errors.Is(tr.Verify(suite), ErrEncVerification))
+}
+
+func TestPVSSIndexChecks(t *testing.T) {
+	n, th := 5, 3
+	G, x, X := setup(n)
+	H, _ := suite.Point().Pick(nil, suite.Cipher([]byte("H")))
+	secret := suite.Scalar().Pick(random.Stream)
+	encShares, pubPoly, err := EncShares(suite, H, X, secret, th)
+	require.Nil(t, err)
+
+	// The dealer relabels share 3 as out of range and share 4 as a duplicate
+	// of share 1
+	bad := append([]*PubVerShare(nil), encShares...)
+	relabel := func(pos, index int) {
+		s := *encShares[pos]
+		s.S.I = index
+		bad[pos] = &s
+	}
+	relabel(3, n+2)
+	relabel(4, 1)
+	sH := make([]abstract.Point, n)
+	for i, s := range bad {
+		sH[i] = pubPoly.Eval(s.S.I).V
+	}
+	_, E, F, err := VerifyEncShareBatchReport(context.Background(), suite, H, X, sH, bad)
+	require.Nil(t, err)
+	assert.Len(t, E, 2)
+	require.Len(t, F, 3)
+	var se *ShareError
+	for _, f := range F {
+		require.True(t, errors.As(f.Err, &se))
+		switch f.Pos {
+		case 1, 4:
+			assert.True(t, errors.Is(f.Err, ErrDuplicateShare))
+			assert.Equal(t, 1, se.Index)
+		case 3:
+			assert.True(t, errors.Is(f.Err, ErrInvalidIndex))
+			assert.Equal(t, n+2, se.Index)
+		default:
+			t.Fatalf("unexpected failure at %d", f.Pos)
+		}
+	}
+	_, _, F2, err := VerifyEncShareBatchParallel(context.Background(), suite, H, X, sH, bad, 2)
+	require.Nil(t, err)
+	assert.Len(t, F2, 3)
+
+	neg := *encShares[0]
+	neg.S.I = -1
+	assert.True(t, errors.Is(VerifyEncShare(suite, H, X[0], pubPoly.Eval(0).V, &neg), ErrInvalidIndex))
+
+	// A decrypted share must keep the index of its encrypted share
+	ds, err := DecShare(suite, H, X[0], pubPoly.Eval(0).V, x[0], encShares[0])
+	require.Nil(t, err)
+	moved := *ds
+	moved.S.I = 2
+	assert.True(t, errors.Is(VerifyDecShare(suite, G, X[0], encShares[0], &moved), ErrInvalidIndex))
+
+	// Recovery rejects indices beyond n
+	var K []abstract.Point
+	var D []*PubVerShare
+	for i := 0; i < th; i++ {
+		ds, err := DecShare(suite, H, X[i], pubPoly.Eval(i).V, x[i], encShares[i])
+		require.Nil(t, err)
+		K = append(K, X[i])
+		D = append(D, ds)
+	}
+	_, err = RecoverSecret(suite, G, K, encShares[:th], D, th, 2)
+	assert.True(t, errors.Is(err, ErrInvalidIndex))
+	_, err = RecoverSecret(suite, G, K, encShares[:th], D, th, n)
+	require.Nil(t, err)
+}
+
+func TestPVSSJSON(t *testing.T) {
+	n, th := 5, 3
+	_, _, X := setup(n)
+	H, _ := suite.Point().Pick(nil, suite.Cipher([]byte("H")))
+	dealer := NewDealer(suite, H, X, th)
+	deal, err := dealer.Deal(suite.Scalar().Pick(random.Stream))
+	require.Nil(t, err)
+
+	buf, err := json.Marshal(deal.EncShares[1])
+	require.Nil(t, err)
+	dec := NewPubVerShare(suite)
+	require.Nil(t, json.Unmarshal(buf, dec))
+	assert.True(t, deal.EncShares[1].Equal(dec))
+	assert.True(t, errors.Is(new(PubVerShare).UnmarshalJSON(buf), ErrEncoding))
+
+	buf, err = MarshalSharesJSON(deal.EncShares)
+	require.Nil(t, err)
+	shares, err := UnmarshalSharesJSON(suite, buf)
+	require.Nil(t, err)
+	require.Equal(t, n, len(shares))
+	for i := range shares {
+		assert.True(t, deal.EncShares[i].Equal(shares[i]))
+	}
+
+	buf, err = MarshalCommitsJSON(deal.Commits)
+	require.Nil(t, err)
+	commits, err := UnmarshalCommitsJSON(suite, buf)
+	require.Nil(t, err)
+	assert.True(t, deal.Commits.Equal(commits))
+
+	// A decoded deal passes verification
+	buf, err = json.Marshal(deal)
+	require.Nil(t, err)
+	decDeal, err := UnmarshalDealJSON(suite, buf)
+	require.Nil(t, err)
+	p, err := NewParticipant(suite, H, X, nil, th)
+	require.Nil(t, err)
+	failures, err := p.ProcessDeal(decDeal)
+	require.Nil(t, err)
+	assert.Empty(t, failures)
+
+	_, err = UnmarshalSharesJSON(suite, []byte(`[{"index":0,"value":"zz"}]`))
+	assert.True(t, errors.Is(err, ErrEncoding))
+	_, err = UnmarshalCommitsJSON(suite, []byte(`{"commits":[]}`))
+	assert.True(t, errors.Is(err, ErrEncoding))
+}
+
+func TestEncSharesWith(t *testing.T) {
+	n, th := 5, 3
+	_, x, X := setup(n)
+	H, _ := suite.Point().Pick(nil, suite.Cipher([]byte("H")))
+	seed := []byte("dealer seed")
+
+	encShares, pubPoly, err := EncSharesWith(suite, H, X, nil, th, suite.Cipher(seed))
+	require.Nil(t, err)
+	again, againPoly, err := EncSharesWith(suite, H, X, nil, th, suite.Cipher(seed))
+	require.Nil(t, err)
+	assert.True(t, pubPoly.Equal(againPoly))
+	for i := range encShares {
+		assert.True(t, encShares[i].Equal(again[i]))
+	}
+
+	other, _, err := EncSharesWith(suite, H, X, nil, th, suite.Cipher([]byte("other seed")))
+	require.Nil(t, err)
+	assert.False(t, encShares[0].Equal(other[0]))
+
+	// The shares are valid and recover the committed secret
+	sH := pubPoly.Shares(n)
+	var decShares []*PubVerShare
+	for i := range encShares {
+		require.Nil(t, VerifyEncShare(suite, H, X[i], sH[i].V, encShares[i]))
+		ds, err := DecShare(suite, H, X[i], sH[i].V, x[i], encShares[i])
+		require.Nil(t, err)
+		decShares = append(decShares, ds)
+	}
+	_, err = RecoverSecret(suite, suite.Point().Base(), X, encShares, decShares, th, n)
+	require.Nil(t, err)
+}
+
+// forgeRecovery returns a copy of the recovery proof p whose first decrypted
+// share is replaced, with a forged proof, such that the shares combine to S.
+func forgeRecovery(p *RecoveryProof, G abstract.Point, X []abstract.Point, S abstract.Point) *RecoveryProof {
+	f := *p
+	f.DecShares = append([]*PubVerShare(nil), p.DecShares...)
+	V := suite.Point().Sub(S, p.combine(suite))
+	V.Add(suite.Point().Mul(V, suite.Scalar().Inv(p.Coeffs[0])), p.DecShares[0].S.V)
+	i := p.DecShares[0].S.I
+	f.DecShares[0] = &PubVerShare{share.PubShare{I: i, V: V}, *forgeProof(G, V, X[i], p.EncShares[0].S.V)}
+	return &f
+}
+
+func TestRecoverSecretWithProof(t *testing.T) {
+	n, th := 7, 4
+	G, x, X := setup(n)
+	H, _ := suite.Point().Pick(nil, suite.Cipher([]byte("H")))
+	secret := suite.Scalar().Pick(random.Stream)
+
+	encShares, pubPoly, err := EncShares(suite, H, X, secret, th)
+	require.Nil(t, err)
+	sH := pubPoly.Shares(n)
+	decShares := make([]*PubVerShare, n)
+	for i := range encShares {
+		decShares[i], err = DecShare(suite, H, X[i], sH[i].V, x[i], encShares[i])
+		require.Nil(t, err)
+	}
+	// Invalidate the first decrypted share
+	decShares[0] = &PubVerShare{S: share.PubShare{I: 0, V: suite.Point().Base()}, P: decShares[0].P}
+
+	S, p, err := RecoverSecretWithProof(suite, G, X, encShares, decShares, th, n)
+	require.Nil(t, err)
+	assert.True(t, S.Equal(suite.Point().Mul(G, secret)))
+	require.Equal(t, th, len(p.DecShares))
+	assert.Equal(t, 1, p.DecShares[0].S.I)
+	require.Nil(t, p.Verify(suite, G, H, X, pubPoly, S))
+
+	// Wrong secret
+	err = p.Verify(suite, G, H, X, pubPoly, suite.Point().Base())
+	assert.True(t, errors.Is(err, ErrInvalidRecovery))
+
+	// Tampered coefficient
+	c := p.Coeffs[1]
+	p.Coeffs[1] = suite.Scalar().One()
+	err = p.Verify(suite, G, H, X, pubPoly, S)
+	assert.True(t, errors.Is(err, ErrInvalidRecovery))
+	p.Coeffs[1] = c
+
+	// Commitments of another dealing
+	_, otherPoly, err := EncShares(suite, H, X, secret, th)
+	require.Nil(t, err)
+	err = p.Verify(suite, G, H, X, otherPoly, S)
+	assert.True(t, errors.Is(err, ErrEncVerification))
+
+	// A forged decryption proof that makes the shares combine to another
+	// secret
+	other, _ := suite.Point().Pick(nil, random.Stream)
+	f := forgeRecovery(p, G, X, other)
+	require.True(t, f.combine(suite).Equal(other))
+	err = f.Verify(suite, G, H, X, pubPoly, other)
+	assert.True(t, errors.Is(err, ErrDecVerification))
+
+	// Too few shares
+	p.DecShares = p.DecShares[1:]
+	err = p.Verify(suite, G, H, X, pubPoly, S)
+	assert.True(t, errors.Is(err, ErrDifferentLengths))
+}
+
+func TestPVSSPayload(t *testing.T) {
+	n, th := 5, 3
+	G, x, X := setup(n)
+	H, _ := suite.Point().Pick(nil, suite.Cipher([]byte("H")))
+	key := []byte("0123456789abcdef")
+
+	encShares, pubPoly, C, err := EncSharesPayload(suite, H, X, key, th)
+	require.Nil(t, err)
+	sH := pubPoly.Shares(n)
+	decShares := make([]*PubVerShare, n)
+	for i := range encShares {
+		decShares[i], err = DecShare(suite, H, X[i], sH[i].V, x[i], encShares[i])
+		require.Nil(t, err)
+	}
+	S, err := RecoverSecret(suite, G, X, encShares, decShares, th, n)
+	require.Nil(t, err)
+	data, err := RecoverData(suite, S, C)
+	require.Nil(t, err)
+	assert.Equal(t, key, data)
+
+	// A wrong secret does not reveal the payload
+	data, err = RecoverData(suite, G, C)
+	assert.False(t, err == nil && bytes.Equal(data, key))
+
+	_, _, _, err = EncSharesPayload(suite, H, X, make([]byte, suite.Point().PickLen()+1), th)
+	assert.Equal(t, ErrPayloadTooLong, err)
+}
+
+func TestHandoff(t *testing.T) {
+	n, th := 5, 3
+	G, x, X := setup(n)
+	H, _ := suite.Point().Pick(nil, suite.Cipher([]byte("H")))
+	secret := suite.Scalar().Pick(random.Stream)
+	r := suite.Scalar().Pick(random.Stream)
+	R := suite.Point().Mul(G, r)
+
+	encShares, _, err := EncShares(suite, H, X, secret, th)
+	require.Nil(t, err)
+	handoffs := make([]*Handoff, n)
+	for i := range encShares {
+		handoffs[i], err = HandoffShare(suite, X[i], x[i], encShares[i], R)
+		require.Nil(t, err)
+		require.Nil(t, VerifyHandoff(suite, X[i], encShares[i], R, handoffs[i]))
+	}
+
+	// Only the designated reader recovers the secret
+	S, err := RecoverHandoff(suite, X, encShares, handoffs, R, r, th, n)
+	require.Nil(t, err)
+	assert.True(t, S.Equal(suite.Point().Mul(G, secret)))
+
+	// A hand-off is bound to its reader and its encrypted share
+	err = VerifyHandoff(suite, X[0], encShares[0], G, handoffs[0])
+	assert.True(t, errors.Is(err, ErrHandoffVerification))
+	err = VerifyHandoff(suite, X[1], encShares[1], R, &Handoff{1, handoffs[0].U, handoffs[0].V, handoffs[0].Proof})
+	assert.True(t, errors.Is(err, ErrHandoffVerification))
+
+	// Tampered hand-offs are ignored
+	handoffs[0].V = suite.Point().Add(handoffs[0].V, G)
+	handoffs[1].V = suite.Point().Add(handoffs[1].V, G)
+	S, err = RecoverHandoff(suite, X, encShares, handoffs, R, r, th, n)
+	require.Nil(t, err)
+	assert.True(t, S.Equal(suite.Point().Mul(G, secret)))
+	handoffs[2].V = suite.Point().Add(handoffs[2].V, G)
+	_, err = RecoverHandoff(suite, X, encShares, handoffs, R, r, th, n)
+	assert.True(t, errors.Is(err, ErrTooFewShares))
+}
+
+func TestRecoveryCertificate(t *testing.T) {
+	n, th := 7, 4
+	_, x, X := setup(n)
+	H, _ := suite.Point().Pick(nil, suite.Cipher([]byte("H")))
+	secret := suite.Scalar().Pick(random.Stream)
+
+	encShares, pubPoly, err := EncShares(suite, H, X, secret, th)
+	require.Nil(t, err)
+	sH := pubPoly.Shares(n)
+	decShares := make([]*PubVerShare, n)
+	for i := range encShares {
+		decShares[i], err = DecShare(suite, H, X[i], sH[i].V, x[i], encShares[i])
+		require.Nil(t, err)
+	}
+	c, err := CertifyRecovery(suite, H, X, pubPoly, encShares, decShares)
+	require.Nil(t, err)
+	assert.True(t, c.Secret.Equal(suite.Point().Mul(nil, secret)))
+	require.Nil(t, c.Verify(suite, H, X, pubPoly))
+
+	buf, err := c.MarshalBinary()
+	require.Nil(t, err)
+	d, err := UnmarshalRecoveryCertificate(suite, buf)
+	require.Nil(t, err)
+	require.Nil(t, d.Verify(suite, H, X, pubPoly))
+	assert.True(t, d.Secret.Equal(c.Secret))
+
+	_, err = UnmarshalRecoveryCertificate(suite, buf[:len(buf)-1])
+	assert.True(t, errors.Is(err, ErrEncoding))
+
+	d.Secret = suite.Point().Base()
+	assert.True(t, errors.Is(d.Verify(suite, H, X, pubPoly), ErrInvalidRecovery))
+	// A certificate for another secret with a forged decryption proof
+	d.Secret, _ = suite.Point().Pick(nil, random.Stream)
+	d.Proof = forgeRecovery(c.Proof, suite.Point().Base(), X, d.Secret)
+	assert.True(t, errors.Is(d.Verify(suite, H, X, pubPoly), ErrDecVerification))
+	// Another key for a trustee whose share is in the certificate
+	otherX := make([]abstract.Point, n)
+	copy(otherX, X)
+	otherX[c.Proof.DecShares[0].S.I] = suite.Point().Base()
+	assert.NotNil(t, c.Verify(suite, H, otherX, pubPoly))
+	// A certificate checked against another dealing
+	_, otherPoly, err := EncShares(suite, H, X, secret, th)
+	require.Nil(t, err)
+	assert.True(t, errors.Is(c.Verify(suite, H, X, otherPoly), ErrInvalidRecovery))
+	otherH, _ := suite.Point().Pick(nil, suite.Cipher([]byte("other H")))
+	assert.True(t, errors.Is(c.Verify(suite, otherH, X, pubPoly), ErrInvalidRecovery))
+}
@@ -0,0 +1,139 @@
+package pvss
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/dedis/crypto/abstract"
+	"github.com/dedis/crypto/metrics"
+	"github.com/dedis/crypto/share"
+)
+
+// ErrInvalidRecovery is returned for recovery proofs whose coefficients or
+// decrypted shares do not combine into the claimed secret.
+var ErrInvalidRecovery = errors.New("invalid recovery proof")
+
+// RecoveryProof shows that a recovered secret sG is the secret committed to by
+// the dealer. It consists of a threshold of encrypted and decrypted shares
+// together with their consistency proofs and the Lagrange coefficients that
+// combine the decrypted shares into sG. Checking it costs a threshold t of
+// share verifications, independent of the number of trustees and of the
+// number of decrypted shares released.
+type RecoveryProof struct {
+	EncShares []*PubVerShare    // Encrypted shares of the used trustees
+	DecShares []*PubVerShare    // Decrypted shares of the used trustees
+	Coeffs    []abstract.Scalar // Lagrange coefficients of the shares at 0
+}
+
+// lagrange returns the Lagrange coefficients at 0 of the shares with the
+// given indices, each in [0, n).
+func lagrange(suite abstract.Suite, indices []int, n int) ([]abstract.Scalar, error) {
+	ip, err := share.NewInterpolator(suite, indices, n)
+	if err != nil {
+		return nil, err
+	}
+	coeffs := make([]abstract.Scalar, len(indices))
+	for k, i := range indices {
+		coeffs[k] = ip.Coefficient(i)
+	}
+	return coeffs, nil
+}
+
+// RecoverSecretWithProof is like RecoverSecret but also returns a proof that
+// the secret is the one committed to by the dealer. The proof is built from
+// the first t valid decrypted shares, which are the ones the secret is
+// recovered from.
+func RecoverSecretWithProof(suite abstract.Suite, G abstract.Point, X []abstract.Point, encShares []*PubVerShare, decShares []*PubVerShare, t int, n int) (_ abstract.Point, _ *RecoveryProof, err error) {
+	defer metrics.Start("pvss.RecoverSecretWithProof").End(&err)
+
+	if len(X) != len(encShares) || len(encShares) != len(decShares) {
+		return nil, nil, lengthError("verify decrypted shares", len(X), len(encShares), len(decShares))
+	}
+	var pos []int
+	var D []*PubVerShare
+	for i := range X {
+		if err := VerifyDecShare(suite, G, X[i], encShares[i], decShares[i]); err == nil {
+			pos = append(pos, i)
+			D = append(D, decShares[i])
+		}
+	}
+	if len(D) < t {
+		return nil, nil, fmt.Errorf("pvss: %d of %d required decrypted shares are valid: %w", len(D), t, ErrTooFewShares)
+	}
+	for _, err := range indexErrors("recover", suite, D, n) {
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	p := &RecoveryProof{
+		EncShares: make([]*PubVerShare, t),
+		DecShares: make([]*PubVerShare, t),
+	}
+	indices := make([]int, t)
+	for k := 0; k < t; k++ {
+		p.EncShares[k] = encShares[pos[k]]
+		p.DecShares[k] = D[k]
+		indices[k] = D[k].S.I
+	}
+	if p.Coeffs, err = lagrange(suite, indices, n); err != nil {
+		return nil, nil, err
+	}
+	return p.combine(suite), p, nil
+}
+
+// combine returns the sum of the decrypted shares weighted by the
+// coefficients.
+func (p *RecoveryProof) combine(suite abstract.Suite) abstract.Point {
+	S := suite.Point().Null()
+	for k, ds := range p.DecShares {
+		S.Add(S, suite.Point().Mul(ds.S.V, p.Coeffs[k]))
+	}
+	return S
+}
+
+// Verify checks that secret is the secret committed to by the public
+// commitment polynomial pubPoly, with respect to the base points G and H, in
+// a sharing among the trustees with the public keys X. Only the shares
+// contained in the proof are verified, with VerifyEncShare and
+// VerifyDecShare, which recompute the challenges of their proofs. A decrypted
+// share with a forged proof thus cannot steer the result to another secret.
+func (p *RecoveryProof) Verify(suite abstract.Suite, G, H abstract.Point, X []abstract.Point, pubPoly *share.PubPoly, secret abstract.Point) (err error) {
+	defer metrics.Start("pvss.VerifyRecoveryProof").End(&err)
+
+	t := pubPoly.Threshold()
+	if len(p.EncShares) != t || len(p.DecShares) != t || len(p.Coeffs) != t {
+		return lengthError("verify recovery proof", t, len(p.EncShares), len(p.DecShares), len(p.Coeffs))
+	}
+	for _, err := range indexErrors("verify recovery proof", suite, p.DecShares, len(X)) {
+		if err != nil {
+			return err
+		}
+	}
+	indices := make([]int, t)
+	for k, ds := range p.DecShares {
+		i := ds.S.I
+		if p.EncShares[k] == nil || p.EncShares[k].S.I != i {
+			return &ShareError{"verify recovery proof", i, suite.String(), X[i], ErrInvalidIndex}
+		}
+		if err := VerifyEncShare(suite, H, X[i], pubPoly.Eval(i).V, p.EncShares[k]); err != nil {
+			return err
+		}
+		if err := VerifyDecShare(suite, G, X[i], p.EncShares[k], ds); err != nil {
+			return err
+		}
+		indices[k] = i
+	}
+	coeffs, err := lagrange(suite, indices, len(X))
+	if err != nil {
+		return err
+	}
+	for k, c := range coeffs {
+		if !c.Equal(p.Coeffs[k]) {
+			return fmt.Errorf("pvss: verify recovery proof: coefficient %d: %w", k, ErrInvalidRecovery)
+		}
+	}
+	if !p.combine(suite).Equal(secret) {
+		return fmt.Errorf("pvss: verify recovery proof: %w", ErrInvalidRecovery)
+	}
+	return nil
+}
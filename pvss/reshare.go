@@ -0,0 +1,304 @@
+package pvss
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dedis/crypto/abstract"
+	"github.com/dedis/crypto/proof"
+	"github.com/dedis/crypto/random"
+	"github.com/dedis/crypto/share"
+)
+
+// ErrReshareVerification is returned for re-sharings with an invalid
+// consistency proof or malformed commitments.
+var ErrReshareVerification = errors.New("verification of re-sharing failed")
+
+// Reshare is an old trustee's re-sharing of its share S_i = s_iG of the secret
+// sG to a new committee. The trustee shares s_i in the exponent with a fresh
+// polynomial p_i of the new threshold and constant term s_i, i.e., it picks
+// random coefficients a_1, ..., a_{t-1} and sends each new trustee j the
+// ElGamal encryption (U_j, V_j) = (r_jG, p_i(j)G + r_jX'_j) under its public
+// key X'_j, where p_i(j)G = S_i + sum_k a_k(j+1)^kG. The polynomial is
+// committed with respect to the base H of the original sharing, with the old
+// public share s_iH as constant term, so that the commitments do not reveal
+// any p_i(j)G. Combining the re-sharings of a threshold of old trustees with
+// Lagrange interpolation yields a fresh sharing of sG among the new
+// committee, without the secret or the old shares ever being revealed.
+type Reshare struct {
+	I       int              // Index of the old share
+	Commits *share.PubPoly   // Commitments to p_i with respect to H
+	U       []abstract.Point // Ephemeral keys r_jG, one per new trustee
+	V       []abstract.Point // Encrypted sub-shares, one per new trustee
+	Proof   []byte           // Proof of consistency with the old encrypted share
+}
+
+// ReshareShare re-shares the share of the trustee with key pair (x, X),
+// given its encrypted share encShare verified against the commitment sH with
+// respect to H, to the new trustees with public keys newX and new threshold
+// t.
+func ReshareShare(suite abstract.Suite, H abstract.Point, X abstract.Point, x abstract.Scalar, sH abstract.Point, encShare *PubVerShare, newX []abstract.Point, t int) (*Reshare, error) {
+	return ReshareShareMeta(suite, H, X, x, sH, encShare, newX, t, nil)
+}
+
+// ReshareShareMeta is like ReshareShare but also binds the proof of the
+// re-sharing to meta, so that it is only accepted by VerifyReshareMeta with
+// the same metadata.
+func ReshareShareMeta(suite abstract.Suite, H abstract.Point, X abstract.Point, x abstract.Scalar, sH abstract.Point, encShare *PubVerShare, newX []abstract.Point, t int, meta *Meta) (*Reshare, error) {
+	G := suite.Point().Base()
+	if !X.Equal(suite.Point().Mul(G, x)) {
+		return nil, &ShareError{"reshare", encShare.S.I, suite.String(), X, ErrReshareVerification}
+	}
+	if t < 1 || t > len(newX) {
+		return nil, &ShareError{"reshare", encShare.S.I, suite.String(), X, fmt.Errorf("threshold %d for %d trustees: %w", t, len(newX), ErrReshareVerification)}
+	}
+	S := suite.Point().Mul(encShare.S.V, suite.Scalar().Inv(x)) // decryption: x^{-1} * (xS)
+	// p_i - s_i, whose coefficients are the a_k
+	mask := share.NewPriPoly(suite, t, suite.Scalar().Zero(), random.Stream)
+	a := mask.Coefficients()
+	commits := make([]abstract.Point, t)
+	commits[0] = sH
+	sval := map[string]abstract.Scalar{"x": x}
+	for k := 1; k < t; k++ {
+		commits[k] = suite.Point().Mul(H, a[k])
+		sval[fmt.Sprintf("a%d", k)] = a[k]
+		sval[fmt.Sprintf("b%d", k)] = suite.Scalar().Mul(x, a[k])
+	}
+	r := &Reshare{
+		I:       encShare.S.I,
+		Commits: share.NewPubPoly(suite, H, commits),
+		U:       make([]abstract.Point, len(newX)),
+		V:       make([]abstract.Point, len(newX)),
+	}
+	for j, Xj := range newX {
+		rj := suite.Scalar().Pick(random.Stream)
+		r.U[j] = suite.Point().Mul(G, rj)
+		r.V[j] = suite.Point().Add(S, suite.Point().Mul(G, mask.Eval(j).V))
+		r.V[j].Add(r.V[j], suite.Point().Mul(Xj, rj))
+		sval[fmt.Sprintf("r%d", j)] = rj
+		sval[fmt.Sprintf("w%d", j)] = suite.Scalar().Mul(x, rj)
+	}
+	pred, pval, name, err := reshareStatement(suite, X, encShare, newX, r, meta)
+	if err != nil {
+		return nil, err
+	}
+	prover := pred.Prover(suite, sval, pval, nil)
+	r.Proof, err = proof.HashProve(suite, name, suite.Cipher(abstract.RandomKey), prover)
+	if err != nil {
+		return nil, &ShareError{"reshare", encShare.S.I, suite.String(), X, err}
+	}
+	return r, nil
+}
+
+// VerifyReshare checks that the re-sharing was created by the trustee with
+// public key X from the encrypted share encShare with commitment sH with
+// respect to H for the new trustees with public keys newX and new threshold
+// t. The encrypted share itself must have been verified against sH with
+// VerifyEncShare.
+func VerifyReshare(suite abstract.Suite, H abstract.Point, X abstract.Point, sH abstract.Point, encShare *PubVerShare, newX []abstract.Point, t int, r *Reshare) error {
+	return VerifyReshareMeta(suite, H, X, sH, encShare, newX, t, r, nil)
+}
+
+// VerifyReshareMeta is like VerifyReshare for re-sharings created by
+// ReshareShareMeta. It rejects the re-sharing if meta has expired or differs
+// from the metadata the re-sharing was created with.
+func VerifyReshareMeta(suite abstract.Suite, H abstract.Point, X abstract.Point, sH abstract.Point, encShare *PubVerShare, newX []abstract.Point, t int, r *Reshare, meta *Meta) error {
+	if meta.Expired(time.Now()) {
+		return &ShareError{"verify reshare", r.I, suite.String(), X, ErrExpired}
+	}
+	fail := &ShareError{"verify reshare", r.I, suite.String(), X, ErrReshareVerification}
+	if r.I != encShare.S.I || r.Commits == nil || r.Commits.Threshold() != t || len(r.U) != len(newX) || len(r.V) != len(newX) {
+		return fail
+	}
+	if b, _ := r.Commits.Info(); b == nil || !b.Equal(H) || !r.Commits.Commit().Equal(sH) {
+		return fail
+	}
+	pred, pval, name, err := reshareStatement(suite, X, encShare, newX, r, meta)
+	if err != nil {
+		return err
+	}
+	if err := proof.HashVerify(suite, name, pred.Verifier(suite, pval), r.Proof); err != nil {
+		return fail
+	}
+	return nil
+}
+
+// reshareStatement returns the statement proven by a re-sharing: with the
+// commitments C_k = a_kH of the polynomial, the prover knows x, the a_k,
+// b_k = x*a_k and, for every new trustee j, r_j and w_j = x*r_j such that
+//
+//	X = xG, C_k = a_kH, 0 = a_kX - b_kG,
+//	U_j = r_jG, 0 = xU_j - w_jG and Y = xV_j - w_jX'_j - sum_k b_k(j+1)^kG
+//
+// where Y = xS_i is the old encrypted share. The last equation holds iff
+// (U_j, V_j) is an encryption of S_i + sum_k a_k(j+1)^kG under X'_j. All
+// public points and the optional metadata are bound into the protocol name,
+// which seeds the Fiat-Shamir challenge.
+func reshareStatement(suite abstract.Suite, X abstract.Point, encShare *PubVerShare, newX []abstract.Point, r *Reshare, meta *Meta) (proof.Predicate, map[string]abstract.Point, string, error) {
+	G := suite.Point().Base()
+	pval := map[string]abstract.Point{
+		"G":    G,
+		"-G":   suite.Point().Neg(G),
+		"X":    X,
+		"Y":    encShare.S.V,
+		"Null": suite.Point().Null(),
+	}
+	H, commits := r.Commits.Info()
+	pval["H"] = H
+	preds := []proof.Predicate{proof.Rep("X", "x", "G")}
+	for k := 1; k < len(commits); k++ {
+		c, ak, bk := fmt.Sprintf("C%d", k), fmt.Sprintf("a%d", k), fmt.Sprintf("b%d", k)
+		pval[c] = commits[k]
+		preds = append(preds,
+			proof.Rep(c, ak, "H"),
+			proof.Rep("Null", ak, "X", bk, "-G"))
+	}
+	for j, Xj := range newX {
+		u, v, nx := fmt.Sprintf("U%d", j), fmt.Sprintf("V%d", j), fmt.Sprintf("-X%d", j)
+		rj, wj := fmt.Sprintf("r%d", j), fmt.Sprintf("w%d", j)
+		pval[u] = r.U[j]
+		pval[v] = r.V[j]
+		pval[nx] = suite.Point().Neg(Xj)
+		terms := []string{"x", v, wj, nx}
+		xj := suite.Scalar().SetInt64(1 + int64(j))
+		e := suite.Scalar().One()
+		for k := 1; k < len(commits); k++ {
+			e.Mul(e, xj)
+			jk := fmt.Sprintf("-J%d_%d", j, k)
+			pval[jk] = suite.Point().Mul(pval["-G"], e)
+			terms = append(terms, fmt.Sprintf("b%d", k), jk)
+		}
+		preds = append(preds,
+			proof.Rep(u, rj, "G"),
+			proof.Rep("Null", "x", u, wj, "-G"),
+			proof.Rep("Y", terms...))
+	}
+
+	h := suite.Hash()
+	h.Write([]byte("pvss-reshare"))
+	points := append([]abstract.Point{X, encShare.S.V, H}, newX...)
+	points = append(points, r.U...)
+	points = append(points, r.V...)
+	points = append(points, commits...)
+	for _, P := range points {
+		if _, err := P.MarshalTo(h); err != nil {
+			return nil, nil, "", err
+		}
+	}
+	h.Write(meta.tag("reshare", r.I))
+	return proof.And(preds...), pval, fmt.Sprintf("pvss-reshare-%x", h.Sum(nil)), nil
+}
+
+// DecReshare combines the verified re-sharings of at least t old trustees out
+// of n into the new share of the new trustee with index j and key pair
+// (x, X). It returns the new decrypted share S'_j together with a proof that
+// it is the decryption of the combined encrypted sub-shares, which anybody can
+// check with VerifyDecReshare. All new trustees must combine the same list of
+// re-sharings.
+func DecReshare(suite abstract.Suite, X abstract.Point, x abstract.Scalar, j int, reshares []*Reshare, t, n int) (*PubVerShare, error) {
+	return DecReshareMeta(suite, X, x, j, reshares, t, n, nil)
+}
+
+// DecReshareMeta is like DecReshare but also binds the decryption proof to
+// meta, so that it is only accepted by VerifyDecReshareMeta with the same
+// metadata.
+func DecReshareMeta(suite abstract.Suite, X abstract.Point, x abstract.Scalar, j int, reshares []*Reshare, t, n int, meta *Meta) (*PubVerShare, error) {
+	U, V, err := combineReshares(suite, j, reshares, t, n)
+	if err != nil {
+		return nil, err
+	}
+	xU := suite.Point().Mul(U, x)
+	S := suite.Point().Sub(V, xU)
+	P, _, _, err := proof.NewDLEQProofTagged(suite, suite.Point().Base(), U, x, meta.tag("decreshare", j))
+	if err != nil {
+		return nil, &ShareError{"decrypt reshare", j, suite.String(), X, err}
+	}
+	return &PubVerShare{share.PubShare{I: j, V: S}, *P}, nil
+}
+
+// VerifyDecReshare checks the new decrypted share of the new trustee with
+// public key X against the re-sharings it was combined from, i.e., it checks
+// log_G(X) == log_U(V - S'_j) for the combined encryption (U, V).
+func VerifyDecReshare(suite abstract.Suite, X abstract.Point, reshares []*Reshare, t, n int, decShare *PubVerShare) error {
+	return VerifyDecReshareMeta(suite, X, reshares, t, n, decShare, nil)
+}
+
+// VerifyDecReshareMeta is like VerifyDecReshare for shares decrypted by
+// DecReshareMeta. It rejects the share if meta has expired or differs from
+// the metadata the share was decrypted with.
+func VerifyDecReshareMeta(suite abstract.Suite, X abstract.Point, reshares []*Reshare, t, n int, decShare *PubVerShare, meta *Meta) error {
+	if meta.Expired(time.Now()) {
+		return &ShareError{"verify decrypted reshare", decShare.S.I, suite.String(), X, ErrExpired}
+	}
+	U, V, err := combineReshares(suite, decShare.S.I, reshares, t, n)
+	if err != nil {
+		return err
+	}
+	xU := suite.Point().Sub(V, decShare.S.V)
+	G := suite.Point().Base()
+	if err := decShare.P.VerifyTagged(suite, G, U, X, xU, meta.tag("decreshare", decShare.S.I)); err != nil {
+		return &ShareError{"verify decrypted reshare", decShare.S.I, suite.String(), X, ErrDecVerification}
+	}
+	return nil
+}
+
+// RecoverResharedSecret verifies the new decrypted shares of the new trustees
+// with public keys newX against the re-sharings of the old committee of
+// threshold t and size n and recovers the secret sG from a new threshold
+// newT of the valid ones.
+func RecoverResharedSecret(suite abstract.Suite, newX []abstract.Point, reshares []*Reshare, t, n int, decShares []*PubVerShare, newT int) (abstract.Point, error) {
+	return RecoverResharedSecretMeta(suite, newX, reshares, t, n, decShares, newT, nil)
+}
+
+// RecoverResharedSecretMeta is like RecoverResharedSecret for shares
+// decrypted by DecReshareMeta. Only decrypted shares bound to meta are used,
+// and their indices must be unique.
+func RecoverResharedSecretMeta(suite abstract.Suite, newX []abstract.Point, reshares []*Reshare, t, n int, decShares []*PubVerShare, newT int, meta *Meta) (abstract.Point, error) {
+	var D []*PubVerShare
+	for _, ds := range decShares {
+		if ds == nil || ds.S.I < 0 || ds.S.I >= len(newX) {
+			continue
+		}
+		if VerifyDecReshareMeta(suite, newX[ds.S.I], reshares, t, n, ds, meta) == nil {
+			D = append(D, ds)
+		}
+	}
+	if len(D) < newT {
+		return nil, fmt.Errorf("pvss: %d of %d required reshared shares are valid: %w", len(D), newT, ErrTooFewShares)
+	}
+	for _, err := range indexErrors("recover", suite, D, len(newX)) {
+		if err != nil {
+			return nil, err
+		}
+	}
+	var shares []*share.PubShare
+	for _, ds := range D {
+		shares = append(shares, &ds.S)
+	}
+	return share.RecoverCommit(suite, shares, newT, len(newX))
+}
+
+// combineReshares interpolates the encrypted sub-shares for new trustee j of
+// the re-sharings of the old trustees at zero.
+func combineReshares(suite abstract.Suite, j int, reshares []*Reshare, t, n int) (abstract.Point, abstract.Point, error) {
+	var us, vs []*share.PubShare
+	seen := make(map[int]bool)
+	for _, r := range reshares {
+		if r == nil || seen[r.I] || j < 0 || j >= len(r.U) || j >= len(r.V) {
+			return nil, nil, &ShareError{"combine reshares", j, suite.String(), nil, ErrReshareVerification}
+		}
+		seen[r.I] = true
+		us = append(us, &share.PubShare{I: r.I, V: r.U[j]})
+		vs = append(vs, &share.PubShare{I: r.I, V: r.V[j]})
+	}
+	U, err := share.RecoverCommit(suite, us, t, n)
+	if err != nil {
+		return nil, nil, err
+	}
+	V, err := share.RecoverCommit(suite, vs, t, n)
+	if err != nil {
+		return nil, nil, err
+	}
+	return U, V, nil
+}
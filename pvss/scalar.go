@@ -0,0 +1,83 @@
+package pvss
+
+import (
+	"errors"
+
+	"github.com/dedis/crypto/abstract"
+	"github.com/dedis/crypto/share"
+)
+
+// ErrSealedSecret is returned when a sealed secret does not open under the
+// recovered secret or does not match it.
+var ErrSealedSecret = errors.New("sealed secret does not match recovered secret")
+
+// EncSharesScalar is like EncShares but additionally returns the secret
+// scalar s sealed under a key derived from the group element sG, which is
+// what RecoverSecret yields. Publishing the sealed secret along with the
+// shares lets anybody who recovers sG also obtain s with OpenSecret, e.g., to
+// use it as a private key.
+func EncSharesScalar(suite abstract.Suite, H abstract.Point, X []abstract.Point, secret abstract.Scalar, t int) ([]*PubVerShare, *share.PubPoly, []byte, error) {
+	encShares, pubPoly, err := EncShares(suite, H, X, secret, t)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	sealed, err := SealSecret(suite, secret)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return encShares, pubPoly, sealed, nil
+}
+
+// SealSecret encrypts the secret scalar s under a key derived from sG.
+func SealSecret(suite abstract.Suite, secret abstract.Scalar) ([]byte, error) {
+	key, err := secretKey(suite, suite.Point().Mul(nil, secret))
+	if err != nil {
+		return nil, err
+	}
+	buf, err := secret.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return suite.Cipher(key).Seal(nil, buf), nil
+}
+
+// OpenSecret decrypts a secret sealed by SealSecret given the group element
+// S = sG and checks that the result matches S.
+func OpenSecret(suite abstract.Suite, S abstract.Point, sealed []byte) (abstract.Scalar, error) {
+	key, err := secretKey(suite, S)
+	if err != nil {
+		return nil, err
+	}
+	// Open checks the authenticator in place, so work on a copy
+	buf, err := suite.Cipher(key).Open(nil, append([]byte{}, sealed...))
+	if err != nil {
+		return nil, ErrSealedSecret
+	}
+	s := suite.Scalar()
+	if err := s.UnmarshalBinary(buf); err != nil {
+		return nil, ErrSealedSecret
+	}
+	if !suite.Point().Mul(nil, s).Equal(S) {
+		return nil, ErrSealedSecret
+	}
+	return s, nil
+}
+
+// RecoverScalarSecret is like RecoverSecret but returns the secret scalar s
+// sealed by EncSharesScalar.
+func RecoverScalarSecret(suite abstract.Suite, G abstract.Point, X []abstract.Point, encShares []*PubVerShare, decShares []*PubVerShare, t int, n int, sealed []byte) (abstract.Scalar, error) {
+	S, err := RecoverSecret(suite, G, X, encShares, decShares, t, n)
+	if err != nil {
+		return nil, err
+	}
+	return OpenSecret(suite, S, sealed)
+}
+
+func secretKey(suite abstract.Suite, S abstract.Point) ([]byte, error) {
+	h := suite.Hash()
+	h.Write([]byte("pvss-scalar"))
+	if _, err := S.MarshalTo(h); err != nil {
+		return nil, err
+	}
+	return h.Sum(nil), nil
+}
@@ -0,0 +1,70 @@
+package pvss
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dedis/crypto/abstract"
+	"github.com/dedis/crypto/random"
+	"github.com/dedis/crypto/share"
+)
+
+// VerifyEncSharesScrape verifies all encrypted shares of one dealer in O(n)
+// point multiplications using the technique of SCRAPE by Cascudo and David.
+// Instead of evaluating the dealer's commitment polynomial for every share,
+// which costs O(n*t), the verifier takes the share commitments
+// sH = (s_1H, ..., s_nH), e.g. the values of pubPoly.Shares(n) published by
+// the dealer, and checks that they lie on a polynomial of degree less than t
+// with a single random codeword of the dual Reed-Solomon code: for
+// c_i = u_i*f(x_i), with u_i = prod_{j != i} 1/(x_i - x_j) and f a random
+// polynomial of degree less than n - t,
+//
+//	sum_i c_i(s_iH) == 0.
+//
+// If the commitments fail this check, the whole dealing is invalid and
+// ErrEncVerification is returned. Otherwise, the encryption consistency proof
+// of every share is verified against its commitment and the results are the
+// same as for VerifyEncShareBatchReport.
+func VerifyEncSharesScrape(suite abstract.Suite, H abstract.Point, X []abstract.Point, sH []abstract.Point, t int, encShares []*PubVerShare) ([]abstract.Point, []*PubVerShare, []*Failure, error) {
+	n := len(X)
+	if n != len(sH) || n != len(encShares) {
+		return nil, nil, nil, lengthError("verify encrypted shares", len(X), len(sH), len(encShares))
+	}
+	if t < 1 || t > n {
+		return nil, nil, nil, fmt.Errorf("pvss: threshold %d for %d shares: %w", t, n, ErrEncVerification)
+	}
+	if !dualCodeCheck(suite, sH, t) {
+		return nil, nil, nil, fmt.Errorf("pvss: share commitments of degree %d or more: %w", t, ErrEncVerification)
+	}
+	return VerifyEncShareBatchReport(context.Background(), suite, H, X, sH, encShares)
+}
+
+// dualCodeCheck checks that the points v_i, the evaluations at x_i = i+1,
+// form a codeword of the Reed-Solomon code of polynomials of degree less than
+// t, using a random codeword of the dual code.
+func dualCodeCheck(suite abstract.Suite, v []abstract.Point, t int) bool {
+	n := len(v)
+	if t == n {
+		// Every vector is a codeword
+		return true
+	}
+	f := share.NewPriPoly(suite, n-t, nil, random.Stream)
+	acc := suite.Point().Null()
+	xi := suite.Scalar()
+	xj := suite.Scalar()
+	for i := range v {
+		// u_i = prod_{j != i} 1/(x_i - x_j)
+		u := suite.Scalar().One()
+		xi.SetInt64(int64(i + 1))
+		for j := range v {
+			if j == i {
+				continue
+			}
+			xj.SetInt64(int64(j + 1))
+			u.Mul(u, suite.Scalar().Sub(xi, xj))
+		}
+		c := suite.Scalar().Div(f.Eval(i).V, u)
+		acc.Add(acc, suite.Point().Mul(v[i], c))
+	}
+	return acc.Equal(suite.Point().Null())
+}
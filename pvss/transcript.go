@@ -0,0 +1,210 @@
+package pvss
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"github.com/dedis/crypto/abstract"
+	"github.com/dedis/crypto/share"
+)
+
+// Transcript records a complete PVSS run, so that it can be archived and
+// audited by any third party at a later point in time.
+type Transcript struct {
+	Dealer    abstract.Point   // Public key of the dealer
+	H         abstract.Point   // Base point of the commitments
+	X         []abstract.Point // Public keys of the trustees
+	T         int              // Threshold
+	Commits   *share.PubPoly   // Commitments to the sharing polynomial
+	EncShares []*PubVerShare   // Encrypted shares, in trustee order
+	DecShares []*PubVerShare   // Decrypted shares released by the trustees
+	Secret    abstract.Point   // Recovered secret sG, or nil
+}
+
+// Verify checks the transcript end-to-end: all encrypted shares must be valid
+// with respect to the commitments, all decrypted shares must be valid with
+// respect to their encrypted shares and, if the transcript contains the
+// recovered secret, it must be recovered from the decrypted shares.
+func (tr *Transcript) Verify(suite abstract.Suite) error {
+	n := len(tr.X)
+	if tr.Commits == nil || tr.Commits.Threshold() != tr.T || tr.T < 1 || tr.T > n {
+		return fmt.Errorf("pvss: transcript without commitments of threshold %d: %w", tr.T, ErrEncVerification)
+	}
+	if len(tr.EncShares) != n {
+		return lengthError("verify transcript", n, len(tr.EncShares))
+	}
+	sH := make([]abstract.Point, n)
+	for i, es := range tr.EncShares {
+		if es == nil || es.S.I != i {
+			return &ShareError{"verify transcript", i, suite.String(), tr.X[i], ErrEncVerification}
+		}
+		sH[i] = tr.Commits.Eval(i).V
+	}
+	if _, _, failures, err := VerifyEncShareBatchReport(context.Background(), suite, tr.H, tr.X, sH, tr.EncShares); err != nil {
+		return err
+	} else if len(failures) > 0 {
+		return failures[0].Err
+	}
+
+	seen := make(map[int]bool)
+	K := make([]abstract.Point, len(tr.DecShares))
+	E := make([]*PubVerShare, len(tr.DecShares))
+	for k, ds := range tr.DecShares {
+		if ds == nil || ds.S.I < 0 || ds.S.I >= n || seen[ds.S.I] {
+			return &ShareError{"verify transcript", k, suite.String(), nil, ErrDecVerification}
+		}
+		seen[ds.S.I] = true
+		K[k] = tr.X[ds.S.I]
+		E[k] = tr.EncShares[ds.S.I]
+	}
+	G := suite.Point().Base()
+	if _, failures, err := VerifyDecShareBatchReport(context.Background(), suite, G, K, E, tr.DecShares); err != nil {
+		return err
+	} else if len(failures) > 0 {
+		return failures[0].Err
+	}
+
+	if tr.Secret == nil {
+		return nil
+	}
+	secret, err := RecoverSecret(suite, G, K, E, tr.DecShares, tr.T, n)
+	if err != nil {
+		return err
+	}
+	if !secret.Equal(tr.Secret) {
+		return fmt.Errorf("pvss: recorded secret differs from recovered secret: %w", ErrDecVerification)
+	}
+	return nil
+}
+
+// MarshalBinary encodes the transcript as the number of trustees and the
+// threshold as 32-bit big-endian integers, the dealer's key, H and the
+// trustees' keys, the length-prefixed commitments, the encrypted shares, the
+// number of decrypted shares followed by the decrypted shares and finally a
+// flag for the presence of the secret followed by the secret.
+func (tr *Transcript) MarshalBinary() ([]byte, error) {
+	if len(tr.EncShares) != len(tr.X) {
+		return nil, lengthError("marshal transcript", len(tr.X), len(tr.EncShares))
+	}
+	var b bytes.Buffer
+	binary.Write(&b, binary.BigEndian, uint32(len(tr.X)))
+	binary.Write(&b, binary.BigEndian, uint32(tr.T))
+	points := append([]abstract.Point{tr.Dealer, tr.H}, tr.X...)
+	for _, p := range points {
+		if _, err := p.MarshalTo(&b); err != nil {
+			return nil, err
+		}
+	}
+	commits, err := tr.Commits.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	binary.Write(&b, binary.BigEndian, uint32(len(commits)))
+	b.Write(commits)
+	writeShares := func(shares []*PubVerShare) error {
+		for _, s := range shares {
+			enc, err := s.MarshalBinary()
+			if err != nil {
+				return err
+			}
+			b.Write(enc)
+		}
+		return nil
+	}
+	if err := writeShares(tr.EncShares); err != nil {
+		return nil, err
+	}
+	binary.Write(&b, binary.BigEndian, uint32(len(tr.DecShares)))
+	if err := writeShares(tr.DecShares); err != nil {
+		return nil, err
+	}
+	if tr.Secret == nil {
+		b.WriteByte(0)
+	} else {
+		b.WriteByte(1)
+		if _, err := tr.Secret.MarshalTo(&b); err != nil {
+			return nil, err
+		}
+	}
+	return b.Bytes(), nil
+}
+
+// UnmarshalTranscript decodes a transcript over suite encoded with
+// MarshalBinary. The decoded transcript still has to be checked with Verify.
+func UnmarshalTranscript(suite abstract.Suite, buf []byte) (*Transcript, error) {
+	r := bytes.NewReader(buf)
+	var n, t uint32
+	if err := binary.Read(r, binary.BigEndian, &n); err != nil {
+		return nil, fmt.Errorf("pvss: decoding transcript size: %w", ErrEncoding)
+	}
+	if err := binary.Read(r, binary.BigEndian, &t); err != nil {
+		return nil, fmt.Errorf("pvss: decoding transcript threshold: %w", ErrEncoding)
+	}
+	shareSize := NewPubVerShare(suite).MarshalSize()
+	if uint64(n) > uint64(r.Len())/uint64(suite.PointLen()+shareSize) {
+		return nil, fmt.Errorf("pvss: transcript of %d trustees exceeds input: %w", n, ErrEncoding)
+	}
+	tr := &Transcript{T: int(t), X: make([]abstract.Point, n)}
+	tr.Dealer = suite.Point()
+	tr.H = suite.Point()
+	for i := range tr.X {
+		tr.X[i] = suite.Point()
+	}
+	points := append([]abstract.Point{tr.Dealer, tr.H}, tr.X...)
+	for _, p := range points {
+		if _, err := p.UnmarshalFrom(r); err != nil {
+			return nil, fmt.Errorf("pvss: decoding transcript key: %w", ErrEncoding)
+		}
+	}
+	var size uint32
+	if err := binary.Read(r, binary.BigEndian, &size); err != nil || int64(size) > int64(r.Len()) {
+		return nil, fmt.Errorf("pvss: decoding transcript commitments: %w", ErrEncoding)
+	}
+	commits := make([]byte, size)
+	io.ReadFull(r, commits)
+	var err error
+	if tr.Commits, err = share.UnmarshalPubPoly(suite, commits); err != nil {
+		return nil, err
+	}
+	readShares := func(count int) ([]*PubVerShare, error) {
+		shares := make([]*PubVerShare, count)
+		enc := make([]byte, shareSize)
+		for i := range shares {
+			if _, err := io.ReadFull(r, enc); err != nil {
+				return nil, fmt.Errorf("pvss: decoding transcript share: %w", ErrEncoding)
+			}
+			shares[i] = NewPubVerShare(suite)
+			if err := shares[i].UnmarshalBinary(enc); err != nil {
+				return nil, err
+			}
+		}
+		return shares, nil
+	}
+	if tr.EncShares, err = readShares(int(n)); err != nil {
+		return nil, err
+	}
+	var d uint32
+	if err := binary.Read(r, binary.BigEndian, &d); err != nil || uint64(d) > uint64(r.Len())/uint64(shareSize) {
+		return nil, fmt.Errorf("pvss: decoding number of decrypted shares: %w", ErrEncoding)
+	}
+	if tr.DecShares, err = readShares(int(d)); err != nil {
+		return nil, err
+	}
+	flag, err := r.ReadByte()
+	if err != nil || flag > 1 {
+		return nil, fmt.Errorf("pvss: decoding transcript secret flag: %w", ErrEncoding)
+	}
+	if flag == 1 {
+		tr.Secret = suite.Point()
+		if _, err := tr.Secret.UnmarshalFrom(r); err != nil {
+			return nil, fmt.Errorf("pvss: decoding transcript secret: %w", ErrEncoding)
+		}
+	}
+	if r.Len() != 0 {
+		return nil, fmt.Errorf("pvss: %d trailing bytes after transcript: %w", r.Len(), ErrEncoding)
+	}
+	return tr, nil
+}
@@ -0,0 +1,125 @@
+package pvss
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/dedis/crypto/abstract"
+	"github.com/dedis/crypto/share"
+)
+
+// ErrDuplicateShare is returned when a share arrives twice for the same
+// index.
+var ErrDuplicateShare = errors.New("duplicate share")
+
+// Verifier verifies the shares of one PVSS run one at a time as they arrive,
+// e.g., over the network, instead of requiring complete slices. It tracks the
+// qualified set of trustees, whose encrypted shares are valid, and the valid
+// decrypted shares, and reports when a threshold of them is available.
+type Verifier struct {
+	suite   abstract.Suite
+	H       abstract.Point
+	X       []abstract.Point
+	pubPoly *share.PubPoly
+	enc     map[int]*PubVerShare // Valid encrypted shares
+	dec     map[int]*PubVerShare // Valid decrypted shares
+	bad     map[int]bool         // Indices for which an invalid encrypted share arrived
+}
+
+// NewVerifier creates a verifier for the run with public commitment
+// polynomial pubPoly of base H among the trustees with public keys X.
+func NewVerifier(suite abstract.Suite, H abstract.Point, X []abstract.Point, pubPoly *share.PubPoly) *Verifier {
+	return &Verifier{
+		suite:   suite,
+		H:       H,
+		X:       X,
+		pubPoly: pubPoly,
+		enc:     make(map[int]*PubVerShare),
+		dec:     make(map[int]*PubVerShare),
+		bad:     make(map[int]bool),
+	}
+}
+
+// AddEncShare verifies an encrypted share and, if valid, adds its trustee to
+// the qualified set. It returns whether the qualified set has reached the
+// threshold. An invalid share does not prevent a later valid share for the
+// same index from being added. Since VerifyEncShare recomputes the challenge
+// of the proof, only the dealer can create a valid share for an index, so a
+// share forged by a third party cannot shut out the dealer's genuine share.
+func (v *Verifier) AddEncShare(encShare *PubVerShare) (bool, error) {
+	i := encShare.S.I
+	if i < 0 || i >= len(v.X) {
+		return false, &ShareError{"add encrypted", i, v.suite.String(), nil, ErrInvalidIndex}
+	}
+	if v.enc[i] != nil {
+		return false, &ShareError{"add encrypted", i, v.suite.String(), v.X[i], ErrDuplicateShare}
+	}
+	if err := VerifyEncShare(v.suite, v.H, v.X[i], v.pubPoly.Eval(i).V, encShare); err != nil {
+		v.bad[i] = true
+		return false, err
+	}
+	v.enc[i] = encShare
+	return len(v.enc) >= v.pubPoly.Threshold(), nil
+}
+
+// AddDecShare verifies a decrypted share against the trustee's encrypted
+// share, which must have been added before, and keeps it if valid. It returns
+// whether the secret can be recovered.
+func (v *Verifier) AddDecShare(decShare *PubVerShare) (bool, error) {
+	i := decShare.S.I
+	encShare := v.enc[i]
+	if encShare == nil {
+		return false, &ShareError{"add decrypted", i, v.suite.String(), nil, ErrDecVerification}
+	}
+	if v.dec[i] != nil {
+		return false, &ShareError{"add decrypted", i, v.suite.String(), v.X[i], ErrDuplicateShare}
+	}
+	if err := VerifyDecShare(v.suite, v.suite.Point().Base(), v.X[i], encShare, decShare); err != nil {
+		return false, err
+	}
+	v.dec[i] = decShare
+	return v.Ready(), nil
+}
+
+// Qualified returns the sorted indices of the trustees with a valid encrypted
+// share.
+func (v *Verifier) Qualified() []int {
+	qual := make([]int, 0, len(v.enc))
+	for i := range v.enc {
+		qual = append(qual, i)
+	}
+	sort.Ints(qual)
+	return qual
+}
+
+// Disqualified returns the sorted indices of the trustees for which only
+// invalid encrypted shares arrived.
+func (v *Verifier) Disqualified() []int {
+	disq := make([]int, 0, len(v.bad))
+	for i := range v.bad {
+		if v.enc[i] == nil {
+			disq = append(disq, i)
+		}
+	}
+	sort.Ints(disq)
+	return disq
+}
+
+// Ready reports whether a threshold of valid decrypted shares is available.
+func (v *Verifier) Ready() bool {
+	return len(v.dec) >= v.pubPoly.Threshold()
+}
+
+// RecoverSecret recovers the secret from the valid decrypted shares.
+func (v *Verifier) RecoverSecret() (abstract.Point, error) {
+	t := v.pubPoly.Threshold()
+	if !v.Ready() {
+		return nil, fmt.Errorf("pvss: %d of %d required decrypted shares are valid: %w", len(v.dec), t, ErrTooFewShares)
+	}
+	shares := make([]*share.PubShare, 0, len(v.dec))
+	for _, ds := range v.dec {
+		shares = append(shares, &ds.S)
+	}
+	return share.RecoverCommit(v.suite, shares, t, len(v.X))
+}
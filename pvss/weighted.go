@@ -0,0 +1,110 @@
+package pvss
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/dedis/crypto/abstract"
+	"github.com/dedis/crypto/share"
+)
+
+// ErrWeights is returned for weights that are negative or do not match the
+// participants.
+var ErrWeights = errors.New("invalid share weights")
+
+// The *Weighted functions give participant i weights[i] shares, e.g. to
+// reflect its stake in a committee. The shares of participant i have the
+// consecutive indices starting at the sum of the weights of the participants
+// before it, and the threshold t counts shares rather than participants, so
+// any set of participants whose weights sum to at least t recovers the
+// secret.
+
+// WeightedKeys expands the public keys X by the weights, i.e., returns the
+// public key of the owner of every share index, together with the first share
+// index of every participant and, at position len(X), the total number of
+// shares.
+func WeightedKeys(X []abstract.Point, weights []int) ([]abstract.Point, []int, error) {
+	if len(weights) != len(X) {
+		return nil, nil, lengthError("weighted keys", len(X), len(weights))
+	}
+	offsets := make([]int, len(X)+1)
+	var keys []abstract.Point
+	for i, w := range weights {
+		if w < 0 {
+			return nil, nil, fmt.Errorf("pvss: weight %d of participant %d: %w", w, i, ErrWeights)
+		}
+		for k := 0; k < w; k++ {
+			keys = append(keys, X[i])
+		}
+		offsets[i+1] = offsets[i] + w
+	}
+	return keys, offsets, nil
+}
+
+// EncSharesWeighted is like EncShares but creates weights[i] encrypted shares
+// for the participant with public key X[i]. The shares are returned per
+// participant and t is the number of shares required for recovery.
+func EncSharesWeighted(suite abstract.Suite, H abstract.Point, X []abstract.Point, weights []int, secret abstract.Scalar, t int) ([][]*PubVerShare, *share.PubPoly, error) {
+	keys, offsets, err := WeightedKeys(X, weights)
+	if err != nil {
+		return nil, nil, err
+	}
+	if t < 1 || t > len(keys) {
+		return nil, nil, fmt.Errorf("pvss: threshold %d for %d shares: %w", t, len(keys), ErrWeights)
+	}
+	encShares, pubPoly, err := EncShares(suite, H, keys, secret, t)
+	if err != nil {
+		return nil, nil, err
+	}
+	grouped := make([][]*PubVerShare, len(X))
+	for i := range X {
+		grouped[i] = encShares[offsets[i]:offsets[i+1]]
+	}
+	return grouped, pubPoly, nil
+}
+
+// DecSharesWeighted verifies and decrypts all encrypted shares of the
+// participant with key pair (x, X) with DecShare. The commitments of the
+// shares are computed from the dealer's public polynomial.
+func DecSharesWeighted(suite abstract.Suite, H abstract.Point, X abstract.Point, pubPoly *share.PubPoly, x abstract.Scalar, encShares []*PubVerShare) ([]*PubVerShare, error) {
+	decShares := make([]*PubVerShare, len(encShares))
+	for k, es := range encShares {
+		ds, err := DecShare(suite, H, X, pubPoly.Eval(es.S.I).V, x, es)
+		if err != nil {
+			return nil, err
+		}
+		decShares[k] = ds
+	}
+	return decShares, nil
+}
+
+// RecoverSecretWeighted is like RecoverSecret for the shares of
+// EncSharesWeighted, given per participant. The decrypted shares of a
+// participant that did not take part in the recovery are nil. The secret is
+// recovered if the valid decrypted shares number at least t.
+func RecoverSecretWeighted(suite abstract.Suite, G abstract.Point, X []abstract.Point, weights []int, encShares [][]*PubVerShare, decShares [][]*PubVerShare, t int) (abstract.Point, error) {
+	_, offsets, err := WeightedKeys(X, weights)
+	if err != nil {
+		return nil, err
+	}
+	if len(encShares) != len(X) || len(decShares) != len(X) {
+		return nil, lengthError("recover weighted", len(X), len(encShares), len(decShares))
+	}
+	var K []abstract.Point
+	var E, D []*PubVerShare
+	for i := range X {
+		if decShares[i] == nil {
+			continue
+		}
+		w := weights[i]
+		if len(encShares[i]) != w || len(decShares[i]) != w {
+			return nil, lengthError("recover weighted", w, len(encShares[i]), len(decShares[i]))
+		}
+		for k := 0; k < w; k++ {
+			K = append(K, X[i])
+			E = append(E, encShares[i][k])
+			D = append(D, decShares[i][k])
+		}
+	}
+	return RecoverSecret(suite, G, K, E, D, t, offsets[len(X)])
+}
@@ -1,6 +1,12 @@
 // Package rand provides facilities for generating
 // random or pseudorandom cryptographic objects.
 //
+// The randomness of Stream comes from crypto/rand, which uses the operating
+// system's entropy source on every supported platform, including
+// crypto.getRandomValues under js/wasm, random_get under wasip1 and the
+// getrandom system call on Linux and Android. Since Go 1.24, crypto/rand.Read
+// never fails; the process crashes if the entropy source is unavailable.
+//
 // XXX this package might go away and get subsumed by the
 // currently equivalent abstract.Stream type.
 package random
@@ -92,16 +98,14 @@ func Bytes(n int, rand cipher.Stream) []byte {
 // bytes for edwards & ed25519 cipher.
 // XXX Issue reported in https://github.com/dedis/crypto/issues/70
 func NonZeroBytes(n int, rand cipher.Stream) []byte {
-	var randoms []byte
 	for {
-		randoms = Bytes(n, rand)
+		randoms := Bytes(n, rand)
 		for _, b := range randoms {
 			if b != 0x00 {
 				return randoms
 			}
 		}
 	}
-	return randoms
 }
 
 type randstream struct {
@@ -114,13 +118,7 @@ func (r *randstream) XORKeyStream(dst, src []byte) {
 	}
 
 	buf := make([]byte, l)
-	n, err := rand.Read(buf)
-	if err != nil {
-		panic(err)
-	}
-	if n < len(buf) {
-		panic("short read on infinite random stream!?")
-	}
+	rand.Read(buf)
 
 	for i := 0; i < l; i++ {
 		dst[i] = src[i] ^ buf[i]
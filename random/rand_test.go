@@ -0,0 +1,13 @@
+package random
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestStream(t *testing.T) {
+	a := Bytes(32, Stream)
+	if bytes.Equal(a, Bytes(32, Stream)) {
+		t.Fatal("stream returned predictable output")
+	}
+}
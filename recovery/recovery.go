@@ -0,0 +1,296 @@
+// Package recovery implements social recovery of a master secret on top of
+// PVSS. The owner of the secret creates a Vault, which encrypts the secret
+// under a key derived from a random group element sG and distributes
+// publicly verifiable encrypted shares of s to a set of guardians. Anyone can
+// check that the vault is well-formed, so guardians do not need to trust the
+// owner's software.
+//
+// To recover the secret, the owner (typically from a new device) publishes a
+// recovery request together with a fresh requester key R = rG. Each guardian
+// that approves the request decrypts its share s_iG, re-encrypts it towards R
+// and signs its approval, so that the approvals are useless to anybody but
+// the requester. A Collector verifies the approvals and, once a threshold of
+// them is available, lets the requester reconstruct sG with r and decrypt the
+// secret.
+package recovery
+
+import (
+	"bytes"
+	"crypto/cipher"
+	"errors"
+
+	"github.com/dedis/crypto/abstract"
+	"github.com/dedis/crypto/proof"
+	"github.com/dedis/crypto/pvss"
+	"github.com/dedis/crypto/share"
+	"github.com/dedis/crypto/sign"
+)
+
+// Some error definitions
+var errorEmptySecret = errors.New("empty secret")
+var errorVault = errors.New("invalid vault")
+var errorIndex = errors.New("guardian index out of range")
+var errorKey = errors.New("private key does not match guardian key")
+var errorRequester = errors.New("private key does not match requester key")
+var errorApproval = errors.New("invalid approval")
+var errorDuplicate = errors.New("duplicate approval")
+var errorTooFewApprovals = errors.New("not enough approvals")
+var errorDecryption = errors.New("decryption of secret failed")
+
+// Vault holds a secret shared among a set of guardians.
+type Vault struct {
+	H          abstract.Point      // Base point of the share commitments
+	Guardians  []abstract.Point    // Public keys of the guardians
+	T          int                 // Number of approvals needed for recovery
+	Shares     []*pvss.PubVerShare // Encrypted shares, one per guardian
+	Commits    []abstract.Point    // Commitments to the sharing polynomial
+	Ciphertext []byte              // Authenticated encryption of the secret
+}
+
+// Approval is a guardian's consent to a recovery request. With the inverse
+// w = 1/x_i of the guardian's private key and its encrypted share
+// Y_i = s_iX_i, the guardian's decrypted share s_iG = wY_i is re-encrypted
+// towards the requester key R as V = w(Y_i + R) = s_iG + wR. Only the
+// requester can remove wR = rA.
+type Approval struct {
+	S         share.PubShare  // Re-encrypted share V
+	A         abstract.Point  // A = wG
+	P         proof.DLEQProof // Proof that log_G(A) == log_{Y_i+R}(V)
+	PA        proof.DLEQProof // Proof that log_G(A) == log_{X_i}(G)
+	Signature []byte          // Guardian's signature on the request
+}
+
+// NewVault shares the secret among the guardians such that t of them are
+// needed to recover it.
+func NewVault(suite abstract.Suite, guardians []abstract.Point, t int, secret []byte, rand cipher.Stream) (*Vault, error) {
+	if len(secret) == 0 {
+		return nil, errorEmptySecret
+	}
+	if t <= 0 || t > len(guardians) {
+		return nil, errorVault
+	}
+	H, _ := suite.Point().Pick(nil, suite.Cipher([]byte("recovery-H")))
+	s := suite.Scalar().Pick(rand)
+	encShares, pubPoly, err := pvss.EncShares(suite, H, guardians, s, t)
+	if err != nil {
+		return nil, err
+	}
+	key, err := secretKey(suite, suite.Point().Mul(nil, s))
+	if err != nil {
+		return nil, err
+	}
+	_, commits := pubPoly.Info()
+	return &Vault{
+		H:          H,
+		Guardians:  guardians,
+		T:          t,
+		Shares:     encShares,
+		Commits:    commits,
+		Ciphertext: suite.Cipher(key).Seal(nil, secret),
+	}, nil
+}
+
+// Verify checks that every guardian received a valid share.
+func (v *Vault) Verify(suite abstract.Suite) error {
+	n := len(v.Guardians)
+	if v.T <= 0 || v.T > n || len(v.Shares) != n || len(v.Commits) != v.T {
+		return errorVault
+	}
+	pubPoly := share.NewPubPoly(suite, v.H, v.Commits)
+	for i, s := range v.Shares {
+		if s == nil || s.S.I != i {
+			return errorVault
+		}
+		if err := pvss.VerifyEncShare(suite, v.H, v.Guardians[i], pubPoly.Eval(i).V, s); err != nil {
+			return errorVault
+		}
+	}
+	return nil
+}
+
+// ID returns a hash identifying the vault.
+func (v *Vault) ID(suite abstract.Suite) ([]byte, error) {
+	h := suite.Hash()
+	h.Write([]byte("recovery-vault"))
+	points := append([]abstract.Point{v.H}, v.Guardians...)
+	for _, s := range v.Shares {
+		points = append(points, s.S.V)
+	}
+	for _, P := range append(points, v.Commits...) {
+		if _, err := P.MarshalTo(h); err != nil {
+			return nil, err
+		}
+	}
+	h.Write(v.Ciphertext)
+	return h.Sum(nil), nil
+}
+
+// Approve lets the guardian with the given index and private key x approve
+// the recovery request of the requester with public key R.
+func Approve(suite abstract.Suite, v *Vault, index int, x abstract.Scalar, request []byte, R abstract.Point) (*Approval, error) {
+	if index < 0 || index >= len(v.Guardians) || index >= len(v.Shares) {
+		return nil, errorIndex
+	}
+	if !suite.Point().Mul(nil, x).Equal(v.Guardians[index]) {
+		return nil, errorKey
+	}
+	if len(v.Commits) != v.T {
+		return nil, errorVault
+	}
+	pubPoly := share.NewPubPoly(suite, v.H, v.Commits)
+	G := suite.Point().Base()
+	X := v.Guardians[index]
+	Y := v.Shares[index]
+	if Y == nil {
+		return nil, errorVault
+	}
+	if err := pvss.VerifyEncShare(suite, v.H, X, pubPoly.Eval(index).V, Y); err != nil {
+		return nil, err
+	}
+	msg, err := approvalMessage(suite, v, request, R)
+	if err != nil {
+		return nil, err
+	}
+	w := suite.Scalar().Inv(x)
+	P, A, V, err := proof.NewDLEQProofTagged(suite, G, suite.Point().Add(Y.S.V, R), w, msg)
+	if err != nil {
+		return nil, err
+	}
+	PA, _, _, err := proof.NewDLEQProofTagged(suite, G, X, w, msg)
+	if err != nil {
+		return nil, err
+	}
+	sig, err := sign.Schnorr(suite, x, msg)
+	if err != nil {
+		return nil, err
+	}
+	return &Approval{S: share.PubShare{I: index, V: V}, A: A, P: *P, PA: *PA, Signature: sig}, nil
+}
+
+// Collector gathers the approvals of a recovery request.
+type Collector struct {
+	suite     abstract.Suite
+	vault     *Vault
+	R         abstract.Point
+	msg       []byte
+	approvals map[int]*Approval
+}
+
+// NewCollector starts collecting approvals for the recovery request of the
+// secret in the vault by the requester with public key R.
+func NewCollector(suite abstract.Suite, v *Vault, request []byte, R abstract.Point) (*Collector, error) {
+	if err := v.Verify(suite); err != nil {
+		return nil, err
+	}
+	msg, err := approvalMessage(suite, v, request, R)
+	if err != nil {
+		return nil, err
+	}
+	return &Collector{suite: suite, vault: v, R: R, msg: msg, approvals: make(map[int]*Approval)}, nil
+}
+
+// Add verifies a guardian's approval and stores it.
+func (c *Collector) Add(a *Approval) error {
+	if a == nil || a.S.V == nil || a.A == nil {
+		return errorApproval
+	}
+	i := a.S.I
+	if i < 0 || i >= len(c.vault.Guardians) {
+		return errorIndex
+	}
+	if _, ok := c.approvals[i]; ok {
+		return errorDuplicate
+	}
+	X := c.vault.Guardians[i]
+	if err := sign.VerifySchnorr(c.suite, X, c.msg, a.Signature); err != nil {
+		return errorApproval
+	}
+	G := c.suite.Point().Base()
+	YR := c.suite.Point().Add(c.vault.Shares[i].S.V, c.R)
+	if err := a.P.VerifyTagged(c.suite, G, YR, a.A, a.S.V, c.msg); err != nil {
+		return errorApproval
+	}
+	if err := a.PA.VerifyTagged(c.suite, G, X, a.A, G, c.msg); err != nil {
+		return errorApproval
+	}
+	c.approvals[i] = a
+	return nil
+}
+
+// Approvals returns the indices of the guardians whose approvals have been
+// collected.
+func (c *Collector) Approvals() []int {
+	var indices []int
+	for i := range c.vault.Guardians {
+		if _, ok := c.approvals[i]; ok {
+			indices = append(indices, i)
+		}
+	}
+	return indices
+}
+
+// Ready reports whether enough approvals have been collected.
+func (c *Collector) Ready() bool {
+	return len(c.approvals) >= c.vault.T
+}
+
+// Recover reconstructs the secret from the collected approvals with the
+// requester's private key r.
+func (c *Collector) Recover(r abstract.Scalar) ([]byte, error) {
+	if !c.suite.Point().Mul(nil, r).Equal(c.R) {
+		return nil, errorRequester
+	}
+	if !c.Ready() {
+		return nil, errorTooFewApprovals
+	}
+	// s_iG = V - rA
+	var shares []*share.PubShare
+	for _, i := range c.Approvals() {
+		a := c.approvals[i]
+		S := c.suite.Point().Sub(a.S.V, c.suite.Point().Mul(a.A, r))
+		shares = append(shares, &share.PubShare{I: i, V: S})
+	}
+	S, err := share.RecoverCommit(c.suite, shares, c.vault.T, len(c.vault.Guardians))
+	if err != nil {
+		return nil, err
+	}
+	key, err := secretKey(c.suite, S)
+	if err != nil {
+		return nil, err
+	}
+	sym := c.suite.Cipher(key)
+	if len(c.vault.Ciphertext) <= sym.KeySize() {
+		return nil, errorDecryption
+	}
+	// Open checks the authenticator in place, so work on a copy
+	sealed := append([]byte{}, c.vault.Ciphertext...)
+	secret, err := sym.Open(nil, sealed)
+	if err != nil {
+		return nil, errorDecryption
+	}
+	return secret, nil
+}
+
+func secretKey(suite abstract.Suite, S abstract.Point) ([]byte, error) {
+	h := suite.Hash()
+	h.Write([]byte("recovery-key"))
+	if _, err := S.MarshalTo(h); err != nil {
+		return nil, err
+	}
+	return h.Sum(nil), nil
+}
+
+func approvalMessage(suite abstract.Suite, v *Vault, request []byte, R abstract.Point) ([]byte, error) {
+	id, err := v.ID(suite)
+	if err != nil {
+		return nil, err
+	}
+	var b bytes.Buffer
+	b.WriteString("recovery-approval")
+	b.Write(id)
+	if _, err := R.MarshalTo(&b); err != nil {
+		return nil, err
+	}
+	b.Write(request)
+	return b.Bytes(), nil
+}
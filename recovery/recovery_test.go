@@ -0,0 +1,102 @@
+package recovery
+
+import (
+	"testing"
+
+	"github.com/dedis/crypto/abstract"
+	"github.com/dedis/crypto/edwards"
+	"github.com/dedis/crypto/random"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var suite = edwards.NewAES128SHA256Ed25519(false)
+
+func guardians(n int) ([]abstract.Scalar, []abstract.Point) {
+	x := make([]abstract.Scalar, n)
+	X := make([]abstract.Point, n)
+	for i := range x {
+		x[i] = suite.Scalar().Pick(random.Stream)
+		X[i] = suite.Point().Mul(nil, x[i])
+	}
+	return x, X
+}
+
+func TestRecovery(t *testing.T) {
+	n, th := 5, 3
+	x, X := guardians(n)
+	secret := []byte("correct horse battery staple")
+	v, err := NewVault(suite, X, th, secret, random.Stream)
+	require.Nil(t, err)
+	require.Nil(t, v.Verify(suite))
+
+	request := []byte("recover to new device")
+	r := suite.Scalar().Pick(random.Stream)
+	R := suite.Point().Mul(nil, r)
+	c, err := NewCollector(suite, v, request, R)
+	require.Nil(t, err)
+
+	// An approval for another request or requester is rejected
+	a, err := Approve(suite, v, 0, x[0], []byte("other request"), R)
+	require.Nil(t, err)
+	assert.Equal(t, errorApproval, c.Add(a))
+	a, err = Approve(suite, v, 0, x[0], request, suite.Point().Base())
+	require.Nil(t, err)
+	assert.Equal(t, errorApproval, c.Add(a))
+
+	_, err = Approve(suite, v, 0, x[1], request, R)
+	assert.Equal(t, errorKey, err)
+
+	for _, i := range []int{4, 1} {
+		a, err := Approve(suite, v, i, x[i], request, R)
+		require.Nil(t, err)
+		require.Nil(t, c.Add(a))
+		assert.Equal(t, errorDuplicate, c.Add(a))
+	}
+	assert.False(t, c.Ready())
+	_, err = c.Recover(r)
+	assert.Equal(t, errorTooFewApprovals, err)
+
+	a, err = Approve(suite, v, 2, x[2], request, R)
+	require.Nil(t, err)
+	a.S.V = suite.Point().Base()
+	assert.Equal(t, errorApproval, c.Add(a))
+	a, err = Approve(suite, v, 2, x[2], request, R)
+	require.Nil(t, err)
+	a.A = suite.Point().Base()
+	assert.Equal(t, errorApproval, c.Add(a))
+	a, err = Approve(suite, v, 2, x[2], request, R)
+	require.Nil(t, err)
+	require.Nil(t, c.Add(a))
+
+	assert.True(t, c.Ready())
+	assert.Equal(t, []int{1, 2, 4}, c.Approvals())
+	_, err = c.Recover(x[0])
+	assert.Equal(t, errorRequester, err)
+	recovered, err := c.Recover(r)
+	require.Nil(t, err)
+	assert.Equal(t, secret, recovered)
+
+	// The approvals do not reveal the decrypted shares to anybody else: a
+	// collector for another requester key recovers garbage
+	eve := suite.Scalar().Pick(random.Stream)
+	other := *c
+	other.R = suite.Point().Mul(nil, eve)
+	_, err = other.Recover(eve)
+	assert.Equal(t, errorDecryption, err)
+}
+
+func TestVaultInvalid(t *testing.T) {
+	_, X := guardians(4)
+	_, err := NewVault(suite, X, 2, nil, random.Stream)
+	assert.Equal(t, errorEmptySecret, err)
+	_, err = NewVault(suite, X, 5, []byte("secret"), random.Stream)
+	assert.Equal(t, errorVault, err)
+
+	v, err := NewVault(suite, X, 2, []byte("secret"), random.Stream)
+	require.Nil(t, err)
+	v.Shares[3].S.V = suite.Point().Base()
+	assert.Equal(t, errorVault, v.Verify(suite))
+	_, err = NewCollector(suite, v, []byte("request"), suite.Point().Base())
+	assert.Equal(t, errorVault, err)
+}
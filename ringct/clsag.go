@@ -0,0 +1,187 @@
+package ringct
+
+import (
+	"crypto/cipher"
+
+	"github.com/dedis/crypto/abstract"
+	"github.com/dedis/crypto/group"
+)
+
+// Signature is a CLSAG linkable ring signature over a ring of public keys P
+// and amount commitments C. It proves knowledge of the private key p of some
+// P[j] and of z with C[j] - Cout = zG for the pseudo-output commitment Cout,
+// i.e., that Cout commits to the same amount as the spent output.
+type Signature struct {
+	C0 abstract.Scalar   // Challenge of the first ring member
+	S  []abstract.Scalar // Responses, one per ring member
+	I  abstract.Point    // Key image pHp(P[j])
+	D  abstract.Point    // Commitment image zHp(P[j])
+}
+
+// KeyImage returns the key image of the private key p, which is the same for
+// all signatures made with p and thereby reveals double spends.
+func KeyImage(suite abstract.Suite, p abstract.Scalar) (abstract.Point, error) {
+	Hp, err := hashToPoint(suite, suite.Point().Mul(nil, p))
+	if err != nil {
+		return nil, err
+	}
+	return suite.Point().Mul(Hp, p), nil
+}
+
+// Sign creates a CLSAG signature of the message spending the ring member at
+// the given index with private key p, where z is the difference of the
+// blinding factors of the member's commitment C[index] and of Cout.
+func Sign(suite abstract.Suite, msg []byte, P, C []abstract.Point, Cout abstract.Point, index int, p, z abstract.Scalar, rand cipher.Stream) (*Signature, error) {
+	n := len(P)
+	if n == 0 || len(C) != n || index < 0 || index >= n {
+		return nil, errorRing
+	}
+	if !suite.Point().Mul(nil, p).Equal(P[index]) {
+		return nil, errorRing
+	}
+	if !suite.Point().Mul(nil, z).Equal(suite.Point().Sub(C[index], Cout)) {
+		return nil, errorRing
+	}
+	Hp := make([]abstract.Point, n)
+	for i := range P {
+		var err error
+		if Hp[i], err = hashToPoint(suite, P[i]); err != nil {
+			return nil, err
+		}
+	}
+	sig := &Signature{
+		S: make([]abstract.Scalar, n),
+		I: suite.Point().Mul(Hp[index], p),
+		D: suite.Point().Mul(Hp[index], z),
+	}
+	muP, muC, err := coefficients(suite, P, C, Cout, sig)
+	if err != nil {
+		return nil, err
+	}
+	W, WI := aggregate(suite, P, C, Cout, sig, muP, muC)
+	w := suite.Scalar().Add(suite.Scalar().Mul(muP, p), suite.Scalar().Mul(muC, z))
+
+	alpha := suite.Scalar().Pick(rand)
+	L := suite.Point().Mul(nil, alpha)
+	R := suite.Point().Mul(Hp[index], alpha)
+	c, err := roundChallenge(suite, msg, P, C, Cout, L, R)
+	if err != nil {
+		return nil, err
+	}
+	for k := 1; k < n; k++ {
+		i := (index + k) % n
+		if i == 0 {
+			sig.C0 = c
+		}
+		sig.S[i] = suite.Scalar().Pick(rand)
+		L, R = round(suite, W[i], WI, Hp[i], sig.S[i], c)
+		if c, err = roundChallenge(suite, msg, P, C, Cout, L, R); err != nil {
+			return nil, err
+		}
+	}
+	if index == 0 {
+		sig.C0 = c
+	}
+	sig.S[index] = suite.Scalar().Sub(alpha, suite.Scalar().Mul(c, w))
+	return sig, nil
+}
+
+// Verify checks the CLSAG signature of the message for the ring of public
+// keys P, amount commitments C and the pseudo-output commitment Cout.
+func Verify(suite abstract.Suite, msg []byte, P, C []abstract.Point, Cout abstract.Point, sig *Signature) error {
+	n := len(P)
+	if n == 0 || len(C) != n {
+		return errorRing
+	}
+	if sig == nil || sig.C0 == nil || sig.I == nil || sig.D == nil || len(sig.S) != n {
+		return errorSignature
+	}
+	// A small-order component would give the same key another image and
+	// thereby hide a double spend from Linked
+	if sig.I.Equal(suite.Point().Null()) || !group.InSubgroup(suite, sig.I) || !group.InSubgroup(suite, sig.D) {
+		return errorSignature
+	}
+	muP, muC, err := coefficients(suite, P, C, Cout, sig)
+	if err != nil {
+		return err
+	}
+	W, WI := aggregate(suite, P, C, Cout, sig, muP, muC)
+	c := sig.C0
+	for i := 0; i < n; i++ {
+		Hp, err := hashToPoint(suite, P[i])
+		if err != nil {
+			return err
+		}
+		L, R := round(suite, W[i], WI, Hp, sig.S[i], c)
+		if c, err = roundChallenge(suite, msg, P, C, Cout, L, R); err != nil {
+			return err
+		}
+	}
+	if !c.Equal(sig.C0) {
+		return errorSignature
+	}
+	return nil
+}
+
+// Linked reports whether two valid signatures were made with the same
+// private key. It compares the key images with their small-order components
+// cleared, so that they are linked even if one of them was not verified.
+func Linked(suite abstract.Suite, a, b *Signature) bool {
+	h := suite.Scalar().SetInt64(group.Cofactor(suite))
+	return suite.Point().Mul(a.I, h).Equal(suite.Point().Mul(b.I, h))
+}
+
+// round computes L = sG + cW and R = sHp + cWI.
+func round(suite abstract.Suite, W, WI, Hp abstract.Point, s, c abstract.Scalar) (abstract.Point, abstract.Point) {
+	L := suite.Point().Add(suite.Point().Mul(nil, s), suite.Point().Mul(W, c))
+	R := suite.Point().Add(suite.Point().Mul(Hp, s), suite.Point().Mul(WI, c))
+	return L, R
+}
+
+// aggregate computes the aggregated ring keys W[i] = muP*P[i] +
+// muC*(C[i] - Cout) and the aggregated image WI = muP*I + muC*D.
+func aggregate(suite abstract.Suite, P, C []abstract.Point, Cout abstract.Point, sig *Signature, muP, muC abstract.Scalar) ([]abstract.Point, abstract.Point) {
+	W := make([]abstract.Point, len(P))
+	for i := range P {
+		W[i] = suite.Point().Mul(P[i], muP)
+		W[i].Add(W[i], suite.Point().Mul(suite.Point().Sub(C[i], Cout), muC))
+	}
+	WI := suite.Point().Add(suite.Point().Mul(sig.I, muP), suite.Point().Mul(sig.D, muC))
+	return W, WI
+}
+
+// coefficients derives the aggregation coefficients muP and muC from the ring
+// and the images.
+func coefficients(suite abstract.Suite, P, C []abstract.Point, Cout abstract.Point, sig *Signature) (abstract.Scalar, abstract.Scalar, error) {
+	mu := make([]abstract.Scalar, 2)
+	for j, tag := range []string{"ringct-clsag-agg-0", "ringct-clsag-agg-1"} {
+		h := suite.Hash()
+		h.Write([]byte(tag))
+		points := append(append([]abstract.Point{}, P...), C...)
+		for _, X := range append(points, sig.I, sig.D, Cout) {
+			if _, err := X.MarshalTo(h); err != nil {
+				return nil, nil, err
+			}
+		}
+		mu[j] = suite.Scalar().Pick(suite.Cipher(h.Sum(nil)))
+	}
+	return mu[0], mu[1], nil
+}
+
+func roundChallenge(suite abstract.Suite, msg []byte, P, C []abstract.Point, Cout, L, R abstract.Point) (abstract.Scalar, error) {
+	h := suite.Hash()
+	h.Write([]byte("ringct-clsag-round"))
+	points := append(append([]abstract.Point{}, P...), C...)
+	for _, X := range append(points, Cout) {
+		if _, err := X.MarshalTo(h); err != nil {
+			return nil, err
+		}
+	}
+	h.Write(msg)
+	for _, X := range []abstract.Point{L, R} {
+		if _, err := X.MarshalTo(h); err != nil {
+			return nil, err
+		}
+	}
+	return suite.Scalar().Pick(suite.Cipher(h.Sum(nil))), nil
+}
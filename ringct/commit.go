@@ -0,0 +1,74 @@
+// Package ringct provides the building blocks of ring confidential
+// transactions:
+//
+// - Pedersen commitments C = rG + vH hide the amounts of inputs and outputs
+// while still allowing anyone to check that a transaction balances.
+//
+// - Range proofs show that a committed amount lies in [0, 2^bits) so that
+// balancing commitments cannot create money out of negative amounts.
+//
+// - CLSAG linkable ring signatures hide which of a set of outputs is spent,
+// prove that the spent output's commitment matches a pseudo-output
+// commitment and expose a key image that links two spends of the same key.
+//
+// The package is intended for experiments with confidential transactions over
+// the supported curves and does not define a transaction format.
+package ringct
+
+import (
+	"errors"
+
+	"github.com/dedis/crypto/abstract"
+)
+
+// Some error definitions
+var errorRange = errors.New("amount out of range")
+var errorRangeProof = errors.New("invalid range proof")
+var errorRing = errors.New("invalid ring")
+var errorSignature = errors.New("invalid ring signature")
+
+// Generator returns the second generator H used for the amounts of
+// commitments. Its discrete logarithm with respect to the standard base point
+// is unknown.
+func Generator(suite abstract.Suite) abstract.Point {
+	H, _ := suite.Point().Pick(nil, suite.Cipher([]byte("ringct-H")))
+	return H
+}
+
+// Commit returns the Pedersen commitment rG + vH to the amount v.
+func Commit(suite abstract.Suite, v uint64, r abstract.Scalar) abstract.Point {
+	return suite.Point().Add(suite.Point().Mul(nil, r), suite.Point().Mul(Generator(suite), amount(suite, v)))
+}
+
+// VerifyBalance checks that the input commitments open to the same total
+// amount as the output commitments plus the public fee. Since amounts are
+// only balanced modulo the group order, every output must additionally carry
+// a valid range proof.
+func VerifyBalance(suite abstract.Suite, inputs, outputs []abstract.Point, fee uint64) bool {
+	sum := suite.Point().Mul(Generator(suite), amount(suite, fee))
+	for _, C := range outputs {
+		sum.Add(sum, C)
+	}
+	for _, C := range inputs {
+		sum.Sub(sum, C)
+	}
+	return sum.Equal(suite.Point().Null())
+}
+
+// amount converts v into a scalar without losing the top bit.
+func amount(suite abstract.Suite, v uint64) abstract.Scalar {
+	hi := suite.Scalar().SetInt64(int64(v >> 1))
+	return hi.Add(hi, hi).Add(hi, suite.Scalar().SetInt64(int64(v&1)))
+}
+
+// hashToPoint maps a point to a point of unknown discrete logarithm, which
+// serves as the base of key images.
+func hashToPoint(suite abstract.Suite, P abstract.Point) (abstract.Point, error) {
+	h := suite.Hash()
+	h.Write([]byte("ringct-hp"))
+	if _, err := P.MarshalTo(h); err != nil {
+		return nil, err
+	}
+	Hp, _ := suite.Point().Pick(nil, suite.Cipher(h.Sum(nil)))
+	return Hp, nil
+}
@@ -0,0 +1,136 @@
+package ringct
+
+import (
+	"crypto/cipher"
+
+	"github.com/dedis/crypto/abstract"
+)
+
+// RangeProof shows that a commitment opens to an amount in [0, 2^bits). The
+// commitment is split into one commitment per bit, each with a disjunctive
+// proof that it commits to either 0 or 2^i. All bit proofs share a single
+// challenge Ch, the challenges of the second branches are Ch - E[i].
+type RangeProof struct {
+	Ch abstract.Scalar   // Shared challenge
+	C  []abstract.Point  // Commitments to the individual bits
+	E  []abstract.Scalar // Challenges of the first branches
+	Z0 []abstract.Scalar // Responses of the first branches
+	Z1 []abstract.Scalar // Responses of the second branches
+}
+
+// ProveRange proves that the commitment rG + vH opens to an amount that fits
+// into the given number of bits.
+func ProveRange(suite abstract.Suite, v uint64, r abstract.Scalar, bits int, rand cipher.Stream) (*RangeProof, error) {
+	if bits <= 0 || bits > 64 || (bits < 64 && v>>uint(bits) != 0) {
+		return nil, errorRange
+	}
+	p := &RangeProof{
+		C:  make([]abstract.Point, bits),
+		E:  make([]abstract.Scalar, bits),
+		Z0: make([]abstract.Scalar, bits),
+		Z1: make([]abstract.Scalar, bits),
+	}
+	H := Generator(suite)
+	A0 := make([]abstract.Point, bits)
+	A1 := make([]abstract.Point, bits)
+	ri := make([]abstract.Scalar, bits)
+	w := make([]abstract.Scalar, bits)
+	e := make([]abstract.Scalar, bits) // Simulated challenges
+	sum := suite.Scalar().Zero()
+	for i := 0; i < bits; i++ {
+		if i < bits-1 {
+			ri[i] = suite.Scalar().Pick(rand)
+			sum.Add(sum, ri[i])
+		} else {
+			ri[i] = suite.Scalar().Sub(r, sum)
+		}
+		bit := v >> uint(i) & 1
+		Hi := suite.Point().Mul(H, amount(suite, 1<<uint(i)))
+		p.C[i] = suite.Point().Mul(nil, ri[i])
+		if bit == 1 {
+			p.C[i].Add(p.C[i], Hi)
+		}
+
+		// Prove the real branch and simulate the other one
+		w[i] = suite.Scalar().Pick(rand)
+		e[i] = suite.Scalar().Pick(rand)
+		if bit == 0 {
+			A0[i] = suite.Point().Mul(nil, w[i])
+			p.Z1[i] = suite.Scalar().Pick(rand)
+			A1[i] = branch(suite, p.C[i], Hi, p.Z1[i], e[i])
+		} else {
+			A1[i] = suite.Point().Mul(nil, w[i])
+			p.Z0[i] = suite.Scalar().Pick(rand)
+			A0[i] = branch(suite, p.C[i], nil, p.Z0[i], e[i])
+		}
+	}
+
+	c, err := rangeChallenge(suite, p.C, A0, A1)
+	if err != nil {
+		return nil, err
+	}
+	p.Ch = c
+	for i := 0; i < bits; i++ {
+		if v>>uint(i)&1 == 0 {
+			p.E[i] = suite.Scalar().Sub(c, e[i])
+			p.Z0[i] = suite.Scalar().Sub(w[i], suite.Scalar().Mul(p.E[i], ri[i]))
+		} else {
+			p.E[i] = e[i]
+			e1 := suite.Scalar().Sub(c, e[i])
+			p.Z1[i] = suite.Scalar().Sub(w[i], suite.Scalar().Mul(e1, ri[i]))
+		}
+	}
+	return p, nil
+}
+
+// VerifyRange checks the range proof for the commitment C.
+func VerifyRange(suite abstract.Suite, C abstract.Point, p *RangeProof) error {
+	bits := len(p.C)
+	if p.Ch == nil || bits == 0 || bits > 64 || len(p.E) != bits || len(p.Z0) != bits || len(p.Z1) != bits {
+		return errorRangeProof
+	}
+	H := Generator(suite)
+	A0 := make([]abstract.Point, bits)
+	A1 := make([]abstract.Point, bits)
+	sum := suite.Point().Null()
+	for i := 0; i < bits; i++ {
+		Hi := suite.Point().Mul(H, amount(suite, 1<<uint(i)))
+		A0[i] = branch(suite, p.C[i], nil, p.Z0[i], p.E[i])
+		A1[i] = branch(suite, p.C[i], Hi, p.Z1[i], suite.Scalar().Sub(p.Ch, p.E[i]))
+		sum.Add(sum, p.C[i])
+	}
+	if !sum.Equal(C) {
+		return errorRangeProof
+	}
+	c, err := rangeChallenge(suite, p.C, A0, A1)
+	if err != nil {
+		return err
+	}
+	if !c.Equal(p.Ch) {
+		return errorRangeProof
+	}
+	return nil
+}
+
+// branch recomputes the commitment zG + e(C - Hi) of one branch of a bit
+// proof, where a nil Hi denotes the branch of bit 0.
+func branch(suite abstract.Suite, C, Hi abstract.Point, z, e abstract.Scalar) abstract.Point {
+	D := C
+	if Hi != nil {
+		D = suite.Point().Sub(C, Hi)
+	}
+	return suite.Point().Add(suite.Point().Mul(nil, z), suite.Point().Mul(D, e))
+}
+
+func rangeChallenge(suite abstract.Suite, C, A0, A1 []abstract.Point) (abstract.Scalar, error) {
+	h := suite.Hash()
+	h.Write([]byte("ringct-range"))
+	for _, points := range [][]abstract.Point{C, A0, A1} {
+		for _, P := range points {
+			if _, err := P.MarshalTo(h); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return suite.Scalar().Pick(suite.Cipher(h.Sum(nil))), nil
+}
@@ -0,0 +1,157 @@
+package ringct
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/dedis/crypto/abstract"
+	"github.com/dedis/crypto/ed25519"
+	"github.com/dedis/crypto/random"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var suite = ed25519.NewAES128SHA256Ed25519(false)
+
+func TestRangeProof(t *testing.T) {
+	r := suite.Scalar().Pick(random.Stream)
+	for _, v := range []uint64{0, 1, 1000, 1<<16 - 1} {
+		C := Commit(suite, v, r)
+		p, err := ProveRange(suite, v, r, 16, random.Stream)
+		require.Nil(t, err)
+		require.Nil(t, VerifyRange(suite, C, p))
+		assert.Error(t, VerifyRange(suite, Commit(suite, v+1, r), p))
+	}
+	_, err := ProveRange(suite, 1<<16, r, 16, random.Stream)
+	assert.Equal(t, errorRange, err)
+
+	// The full 64-bit range
+	v := ^uint64(0)
+	p, err := ProveRange(suite, v, r, 64, random.Stream)
+	require.Nil(t, err)
+	require.Nil(t, VerifyRange(suite, Commit(suite, v, r), p))
+
+	// Tampered bit commitments are detected even if they still sum up to C
+	p, err = ProveRange(suite, 5, r, 8, random.Stream)
+	require.Nil(t, err)
+	D := suite.Point().Mul(nil, suite.Scalar().One())
+	p.C[0].Add(p.C[0], D)
+	p.C[1].Sub(p.C[1], D)
+	assert.Equal(t, errorRangeProof, VerifyRange(suite, Commit(suite, 5, r), p))
+}
+
+func TestTransaction(t *testing.T) {
+	// A ring of five outputs, the third of which belongs to the spender
+	n, index := 5, 2
+	P := make([]abstract.Point, n)
+	C := make([]abstract.Point, n)
+	for i := range P {
+		P[i] = suite.Point().Mul(nil, suite.Scalar().Pick(random.Stream))
+		C[i] = Commit(suite, uint64(100*i), suite.Scalar().Pick(random.Stream))
+	}
+	p := suite.Scalar().Pick(random.Stream)
+	rIn := suite.Scalar().Pick(random.Stream)
+	P[index] = suite.Point().Mul(nil, p)
+	C[index] = Commit(suite, 50, rIn)
+
+	// Spend 50 as outputs of 30 and 15 with a fee of 5
+	r1 := suite.Scalar().Pick(random.Stream)
+	r2 := suite.Scalar().Pick(random.Stream)
+	out1, out2 := Commit(suite, 30, r1), Commit(suite, 15, r2)
+	rOut := suite.Scalar().Add(r1, r2)
+	Cout := Commit(suite, 50, rOut)
+	assert.True(t, VerifyBalance(suite, []abstract.Point{Cout}, []abstract.Point{out1, out2}, 5))
+	assert.False(t, VerifyBalance(suite, []abstract.Point{Cout}, []abstract.Point{out1, out2}, 4))
+
+	msg := []byte("transaction")
+	z := suite.Scalar().Sub(rIn, rOut)
+	sig, err := Sign(suite, msg, P, C, Cout, index, p, z, random.Stream)
+	require.Nil(t, err)
+	require.Nil(t, Verify(suite, msg, P, C, Cout, sig))
+	assert.Equal(t, errorSignature, Verify(suite, []byte("other"), P, C, Cout, sig))
+	assert.Equal(t, errorSignature, Verify(suite, msg, P, C, Commit(suite, 51, rOut), sig))
+
+	I, err := KeyImage(suite, p)
+	require.Nil(t, err)
+	assert.True(t, I.Equal(sig.I))
+
+	// A second spend of the same output is linked to the first one
+	sig2, err := Sign(suite, msg, P, C, Cout, index, p, z, random.Stream)
+	require.Nil(t, err)
+	assert.True(t, Linked(suite, sig, sig2))
+
+	// A key image with a small-order component, which would unlink a second
+	// spend of the same output, is rejected
+	T := torsion(t)
+	forged := forge(t, msg, P, C, Cout, index, p, z, T)
+	assert.Equal(t, errorSignature, Verify(suite, msg, P, C, Cout, forged))
+	assert.True(t, Linked(suite, sig, forged))
+
+	// Signing with a mismatching amount is impossible
+	_, err = Sign(suite, msg, P, C, Commit(suite, 51, rOut), index, p, z, random.Stream)
+	assert.Equal(t, errorRing, err)
+
+	// Signing at the first ring position exercises the wrap-around
+	P[0], C[0] = P[index], C[index]
+	sig, err = Sign(suite, msg, P, C, Cout, 0, p, z, random.Stream)
+	require.Nil(t, err)
+	require.Nil(t, Verify(suite, msg, P, C, Cout, sig))
+}
+
+// forge signs like Sign but adds the small-order point T to the key image. The
+// verifier's round at index is then off by c(muP*T), so forge retries until
+// that term vanishes.
+func forge(t *testing.T, msg []byte, P, C []abstract.Point, Cout abstract.Point, index int, p, z abstract.Scalar, T abstract.Point) *Signature {
+	n := len(P)
+	Hp := make([]abstract.Point, n)
+	for i := range P {
+		var err error
+		Hp[i], err = hashToPoint(suite, P[i])
+		require.Nil(t, err)
+	}
+	sig := &Signature{
+		S: make([]abstract.Scalar, n),
+		I: suite.Point().Add(suite.Point().Mul(Hp[index], p), T),
+		D: suite.Point().Mul(Hp[index], z),
+	}
+	muP, muC, err := coefficients(suite, P, C, Cout, sig)
+	require.Nil(t, err)
+	W, WI := aggregate(suite, P, C, Cout, sig, muP, muC)
+	w := suite.Scalar().Add(suite.Scalar().Mul(muP, p), suite.Scalar().Mul(muC, z))
+	for try := 0; try < 1000; try++ {
+		alpha := suite.Scalar().Pick(random.Stream)
+		c, err := roundChallenge(suite, msg, P, C, Cout, suite.Point().Mul(nil, alpha), suite.Point().Mul(Hp[index], alpha))
+		require.Nil(t, err)
+		for k := 1; k < n; k++ {
+			i := (index + k) % n
+			if i == 0 {
+				sig.C0 = c
+			}
+			sig.S[i] = suite.Scalar().Pick(random.Stream)
+			L, R := round(suite, W[i], WI, Hp[i], sig.S[i], c)
+			c, err = roundChallenge(suite, msg, P, C, Cout, L, R)
+			require.Nil(t, err)
+		}
+		if index == 0 {
+			sig.C0 = c
+		}
+		sig.S[index] = suite.Scalar().Sub(alpha, suite.Scalar().Mul(c, w))
+		if suite.Point().Mul(suite.Point().Mul(T, muP), c).Equal(suite.Point().Null()) {
+			return sig
+		}
+	}
+	t.Fatal("no forgery found")
+	return nil
+}
+
+// torsion returns a point of order 8.
+func torsion(t *testing.T) abstract.Point {
+	enc, err := hex.DecodeString("26e8958fc2b227b045c3f489f2ef98f0d5dfac05d3c63339b13802886d53fc05")
+	require.Nil(t, err)
+	T := suite.Point()
+	require.Nil(t, T.UnmarshalBinary(enc))
+	null := suite.Point().Null()
+	require.False(t, suite.Point().Mul(T, suite.Scalar().SetInt64(4)).Equal(null))
+	require.True(t, suite.Point().Mul(T, suite.Scalar().SetInt64(8)).Equal(null))
+	return T
+}
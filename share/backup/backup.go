@@ -0,0 +1,236 @@
+// Package backup defines an encrypted and authenticated at-rest format for
+// private shares and other secret protocol state, such as the state of a
+// distributed key generation, so that nodes can persist it across restarts
+// and migrate it between machines. A backup consists of a header and the
+// sealed payload:
+//
+//	magic "DSBK" | version | KDF | iterations (uint32) | salt (16 bytes) |
+//	suite name (uint16 length) | metadata (uint32 length) | sealed payload
+//
+// The payload is sealed with the suite's message cipher under a key derived
+// either from an operator passphrase with PBKDF2, or from a raw key such as a
+// data key obtained from a key management service. The whole header,
+// including the unencrypted metadata, is absorbed into the cipher state
+// before sealing, so any modification of it makes Open fail.
+package backup
+
+import (
+	"bytes"
+	"crypto/cipher"
+	"encoding/binary"
+	"errors"
+
+	"github.com/dedis/crypto/abstract"
+	"github.com/dedis/crypto/random"
+	"github.com/dedis/crypto/share"
+	"golang.org/x/crypto/pbkdf2"
+)
+
+// Version is the current version of the backup format.
+const Version = 1
+
+// DefaultIterations is the recommended number of PBKDF2 iterations for
+// passphrase-protected backups.
+const DefaultIterations = 600000
+
+const (
+	kdfRaw    = 0
+	kdfPBKDF2 = 1
+	saltLen   = 16
+)
+
+var magic = []byte("DSBK")
+
+// Some error definitions
+var errorFormat = errors.New("malformed backup")
+var errorVersion = errors.New("unsupported backup version")
+var errorSuite = errors.New("backup created with a different suite")
+var errorOpen = errors.New("wrong key or corrupted backup")
+var errorKey = errors.New("invalid backup key")
+
+// Key is the secret protecting backups, see Passphrase and RawKey.
+type Key struct {
+	kdf        byte
+	iterations uint32
+	secret     []byte
+}
+
+// Passphrase returns a key derived from an operator passphrase with PBKDF2
+// and the given number of iterations, see DefaultIterations. The key only
+// opens backups sealed with the same number of iterations, so a forged
+// header cannot make Open run PBKDF2 for an arbitrary number of iterations.
+func Passphrase(passphrase []byte, iterations int) *Key {
+	return &Key{kdfPBKDF2, uint32(iterations), passphrase}
+}
+
+// RawKey returns a key that uses the given high-entropy secret, such as a
+// data key from a key management service, without stretching.
+func RawKey(secret []byte) *Key {
+	return &Key{kdfRaw, 0, secret}
+}
+
+// header is the decoded header of a backup.
+type header struct {
+	kdf        byte
+	iterations uint32
+	salt       []byte
+	suite      string
+	metadata   []byte
+	raw        []byte // encoding of the header
+}
+
+// Seal encrypts the payload under the key and returns the backup, which also
+// carries the metadata in the clear.
+func Seal(suite abstract.Suite, key *Key, metadata, payload []byte, rand cipher.Stream) ([]byte, error) {
+	if len(key.secret) == 0 || (key.kdf == kdfPBKDF2 && key.iterations == 0) {
+		return nil, errorKey
+	}
+	name := suite.String()
+	var b bytes.Buffer
+	b.Write(magic)
+	b.WriteByte(Version)
+	b.WriteByte(key.kdf)
+	binary.Write(&b, binary.BigEndian, key.iterations)
+	b.Write(random.Bytes(saltLen, rand))
+	binary.Write(&b, binary.BigEndian, uint16(len(name)))
+	b.WriteString(name)
+	binary.Write(&b, binary.BigEndian, uint32(len(metadata)))
+	b.Write(metadata)
+	h, err := parseHeader(b.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	c, err := h.cipher(suite, key)
+	if err != nil {
+		return nil, err
+	}
+	return c.Seal(b.Bytes(), payload), nil
+}
+
+// Open decrypts the backup with the key and returns its metadata and payload.
+// Backups whose header names another KDF or iteration count than the key are
+// rejected before any key derivation.
+func Open(suite abstract.Suite, key *Key, backup []byte) (metadata, payload []byte, err error) {
+	h, err := parseHeader(backup)
+	if err != nil {
+		return nil, nil, err
+	}
+	if h.suite != suite.String() {
+		return nil, nil, errorSuite
+	}
+	if h.kdf != key.kdf || h.iterations != key.iterations {
+		return nil, nil, errorOpen
+	}
+	c, err := h.cipher(suite, key)
+	if err != nil {
+		return nil, nil, err
+	}
+	// Open checks the authenticator in place, so work on a copy
+	sealed := append([]byte{}, backup[len(h.raw):]...)
+	payload, err = c.Open(nil, sealed)
+	if err != nil {
+		return nil, nil, errorOpen
+	}
+	return h.metadata, payload, nil
+}
+
+// Metadata returns the metadata of a backup without authenticating it, e.g.,
+// to find out which key is needed to open it.
+func Metadata(backup []byte) ([]byte, error) {
+	h, err := parseHeader(backup)
+	if err != nil {
+		return nil, err
+	}
+	return h.metadata, nil
+}
+
+// SealPriShare creates a backup of a private share.
+func SealPriShare(suite abstract.Suite, key *Key, metadata []byte, s *share.PriShare, rand cipher.Stream) ([]byte, error) {
+	v, err := s.V.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	payload := binary.BigEndian.AppendUint32(nil, uint32(s.I))
+	payload = append(payload, v...)
+	sealed, err := Seal(suite, key, metadata, payload, rand)
+	for i := range payload {
+		payload[i] = 0
+	}
+	return sealed, err
+}
+
+// OpenPriShare restores a private share from a backup created by
+// SealPriShare.
+func OpenPriShare(suite abstract.Suite, key *Key, backup []byte) (*share.PriShare, []byte, error) {
+	metadata, payload, err := Open(suite, key, backup)
+	if err != nil {
+		return nil, nil, err
+	}
+	V := suite.Scalar()
+	if len(payload) != 4+V.MarshalSize() {
+		return nil, nil, errorFormat
+	}
+	if err := V.UnmarshalBinary(payload[4:]); err != nil {
+		return nil, nil, errorFormat
+	}
+	s := &share.PriShare{I: int(binary.BigEndian.Uint32(payload)), V: V}
+	for i := range payload {
+		payload[i] = 0
+	}
+	return s, metadata, nil
+}
+
+func parseHeader(buf []byte) (*header, error) {
+	r := bytes.NewReader(buf)
+	var m [4]byte
+	var version byte
+	h := &header{salt: make([]byte, saltLen)}
+	var nameLen uint16
+	var metaLen uint32
+	if _, err := r.Read(m[:]); err != nil || !bytes.Equal(m[:], magic) {
+		return nil, errorFormat
+	}
+	if err := binary.Read(r, binary.BigEndian, &version); err != nil {
+		return nil, errorFormat
+	}
+	if version != Version {
+		return nil, errorVersion
+	}
+	if err := binary.Read(r, binary.BigEndian, &h.kdf); err != nil {
+		return nil, errorFormat
+	}
+	if h.kdf != kdfRaw && h.kdf != kdfPBKDF2 {
+		return nil, errorVersion
+	}
+	if err := binary.Read(r, binary.BigEndian, &h.iterations); err != nil {
+		return nil, errorFormat
+	}
+	if n, _ := r.Read(h.salt); n != saltLen {
+		return nil, errorFormat
+	}
+	if err := binary.Read(r, binary.BigEndian, &nameLen); err != nil || int(nameLen) > r.Len() {
+		return nil, errorFormat
+	}
+	name := make([]byte, nameLen)
+	r.Read(name)
+	h.suite = string(name)
+	if err := binary.Read(r, binary.BigEndian, &metaLen); err != nil || int64(metaLen) > int64(r.Len()) {
+		return nil, errorFormat
+	}
+	h.metadata = make([]byte, metaLen)
+	r.Read(h.metadata)
+	h.raw = buf[:len(buf)-r.Len()]
+	return h, nil
+}
+
+// cipher derives the payload key and returns the message cipher with the
+// header absorbed.
+func (h *header) cipher(suite abstract.Suite, key *Key) (abstract.Cipher, error) {
+	secret := key.secret
+	if h.kdf == kdfPBKDF2 {
+		secret = pbkdf2.Key(key.secret, h.salt, int(h.iterations), suite.Hash().Size(), suite.Hash)
+	}
+	c := suite.Cipher(secret)
+	c.Message(nil, nil, h.raw)
+	return c, nil
+}
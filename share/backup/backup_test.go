@@ -0,0 +1,75 @@
+package backup
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/dedis/crypto/edwards"
+	"github.com/dedis/crypto/nist"
+	"github.com/dedis/crypto/random"
+	"github.com/dedis/crypto/share"
+)
+
+func TestBackup(test *testing.T) {
+	g := edwards.NewAES128SHA256Ed25519(false)
+	s := share.NewPriPoly(g, 3, nil, random.Stream).Eval(4)
+	meta := []byte("node-7 epoch 3")
+
+	for _, key := range []*Key{Passphrase([]byte("correct horse"), 1000), RawKey(random.Bytes(32, random.Stream))} {
+		blob, err := SealPriShare(g, key, meta, s, random.Stream)
+		if err != nil {
+			test.Fatal(err)
+		}
+		if m, err := Metadata(blob); err != nil || !bytes.Equal(m, meta) {
+			test.Fatal("wrong metadata")
+		}
+		r, m, err := OpenPriShare(g, key, blob)
+		if err != nil {
+			test.Fatal(err)
+		}
+		if !r.Equal(s) || !bytes.Equal(m, meta) {
+			test.Fatal("restored share differs")
+		}
+
+		// Tampering with the metadata is detected
+		tampered := append([]byte{}, blob...)
+		tampered[bytes.Index(tampered, meta)] ^= 1
+		if _, _, err := Open(g, key, tampered); err != errorOpen {
+			test.Fatal("tampered metadata accepted")
+		}
+		if _, _, err := Open(g, key, blob[:len(blob)-1]); err == nil {
+			test.Fatal("truncated backup opened")
+		}
+	}
+
+	blob, err := Seal(g, Passphrase([]byte("pass"), 1000), nil, []byte("dkg state"), random.Stream)
+	if err != nil {
+		test.Fatal(err)
+	}
+	if _, _, err := Open(g, Passphrase([]byte("wrong"), 1000), blob); err != errorOpen {
+		test.Fatal("opened with the wrong passphrase")
+	}
+	if _, _, err := Open(g, RawKey([]byte("pass")), blob); err != errorOpen {
+		test.Fatal("opened with the wrong kind of key")
+	}
+	if _, _, err := Open(g, Passphrase([]byte("pass"), 2000), blob); err != errorOpen {
+		test.Fatal("opened with another iteration count")
+	}
+	forged := append([]byte{}, blob...)
+	binary.BigEndian.PutUint32(forged[6:], 1<<31)
+	if _, _, err := Open(g, Passphrase([]byte("pass"), 1000), forged); err != errorOpen {
+		test.Fatal("opened a header with another iteration count")
+	}
+	other := nist.NewAES128SHA256P256()
+	if _, _, err := Open(other, Passphrase([]byte("pass"), 1000), blob); err != errorSuite {
+		test.Fatal("opened with a different suite")
+	}
+	blob[4] = Version + 1
+	if _, _, err := Open(g, Passphrase([]byte("pass"), 1000), blob); err != errorVersion {
+		test.Fatal("opened unknown version")
+	}
+	if _, err := Seal(g, RawKey(nil), nil, nil, random.Stream); err != errorKey {
+		test.Fatal("sealed with an empty key")
+	}
+}
@@ -0,0 +1,197 @@
+// Package bytes implements Shamir secret sharing of byte strings over the
+// field GF(2^8), independently of any group or suite. Every byte of the
+// secret is shared with its own random polynomial of degree t-1, and share x
+// holds the evaluations of all these polynomials at x, so shares are as long
+// as the secret plus a small overhead:
+//
+//	shares, err := Split(secret, t, n, random.Stream)
+//	buf, err := shares[i].MarshalBinary()   // store or send share i
+//	secret, err := Combine(shares[:t])
+//
+// The SHA-256 digest of the secret is shared along with the secret, so that
+// Combine detects recombinations that do not yield the original secret, and
+// every share carries a tag over its contents, so that Combine skips shares
+// corrupted in storage or transit. Field arithmetic is constant-time.
+package bytes
+
+import (
+	"bytes"
+	"crypto/cipher"
+	"crypto/sha256"
+	"crypto/subtle"
+	"errors"
+
+	"github.com/dedis/crypto/random"
+)
+
+// Some error definitions
+var errorThreshold = errors.New("invalid threshold or number of shares")
+var errorTooFew = errors.New("not enough valid shares")
+var errorIntegrity = errors.New("shares do not recombine to the shared secret")
+var errorEncoding = errors.New("invalid share encoding")
+
+const digestLen = sha256.Size
+const tagLen = 16
+
+// Share is one share of a byte string.
+type Share struct {
+	X    byte   // Evaluation point, in [1, 255]
+	T    byte   // Threshold
+	Data []byte // Evaluations for the secret and its digest
+	Tag  []byte // Integrity tag of the share
+}
+
+// mul multiplies a and b in GF(2^8) with the AES polynomial
+// x^8 + x^4 + x^3 + x + 1, in constant time.
+func mul(a, b byte) byte {
+	var p byte
+	for i := 0; i < 8; i++ {
+		p ^= -(b & 1) & a
+		carry := -(a >> 7)
+		a = a<<1 ^ carry&0x1b
+		b >>= 1
+	}
+	return p
+}
+
+// inv returns the multiplicative inverse a^254 of a non-zero a.
+func inv(a byte) byte {
+	r := byte(1)
+	for e := byte(254); e > 0; e >>= 1 {
+		if e&1 == 1 {
+			r = mul(r, a)
+		}
+		a = mul(a, a)
+	}
+	return r
+}
+
+// tag computes the integrity tag of a share.
+func tag(x, t byte, data []byte) []byte {
+	h := sha256.New()
+	h.Write([]byte("share-bytes-tag"))
+	h.Write([]byte{x, t})
+	h.Write(data)
+	return h.Sum(nil)[:tagLen]
+}
+
+// Split shares the secret among n participants with threshold t, using rand
+// for the random coefficients; 1 <= t <= n <= 255.
+func Split(secret []byte, t, n int, rand cipher.Stream) ([]*Share, error) {
+	if t < 1 || t > n || n > 255 {
+		return nil, errorThreshold
+	}
+	digest := sha256.Sum256(secret)
+	values := append(append([]byte{}, secret...), digest[:]...)
+	shares := make([]*Share, n)
+	for i := range shares {
+		shares[i] = &Share{X: byte(i + 1), T: byte(t), Data: make([]byte, len(values))}
+	}
+	coeffs := make([]byte, t)
+	for k, v := range values {
+		coeffs[0] = v
+		copy(coeffs[1:], random.Bytes(t-1, rand))
+		for _, s := range shares {
+			// Horner's rule
+			var y byte
+			for j := t - 1; j >= 0; j-- {
+				y = mul(y, s.X) ^ coeffs[j]
+			}
+			s.Data[k] = y
+		}
+	}
+	for _, s := range shares {
+		s.Tag = tag(s.X, s.T, s.Data)
+	}
+	return shares, nil
+}
+
+// valid reports whether the share is well-formed and its tag matches.
+func (s *Share) valid() bool {
+	return s != nil && s.X != 0 && s.T != 0 && len(s.Data) >= digestLen &&
+		subtle.ConstantTimeCompare(s.Tag, tag(s.X, s.T, s.Data)) == 1
+}
+
+// Combine recovers the secret from at least a threshold of shares. Shares
+// with a wrong tag, repeated evaluation points or a threshold or length
+// different from the first valid share are skipped. The secret is
+// interpolated from the first threshold of the remaining shares and checked
+// against the shared digest; all further shares must be consistent with it.
+func Combine(shares []*Share) ([]byte, error) {
+	var good []*Share
+	seen := make(map[byte]bool)
+	for _, s := range shares {
+		if !s.valid() || seen[s.X] {
+			continue
+		}
+		if len(good) > 0 && (s.T != good[0].T || len(s.Data) != len(good[0].Data)) {
+			continue
+		}
+		seen[s.X] = true
+		good = append(good, s)
+	}
+	if len(good) == 0 || len(good) < int(good[0].T) {
+		return nil, errorTooFew
+	}
+	t := int(good[0].T)
+	values := make([]byte, len(good[0].Data))
+	for k := range values {
+		values[k] = interpolate(good[:t], 0, k)
+	}
+	secret := values[:len(values)-digestLen]
+	digest := sha256.Sum256(secret)
+	if subtle.ConstantTimeCompare(digest[:], values[len(secret):]) != 1 {
+		return nil, errorIntegrity
+	}
+	for _, s := range good[t:] {
+		for k := range values {
+			if interpolate(good[:t], s.X, k) != s.Data[k] {
+				return nil, errorIntegrity
+			}
+		}
+	}
+	return secret, nil
+}
+
+// interpolate evaluates at x the polynomial through the k-th values of the
+// shares.
+func interpolate(shares []*Share, x byte, k int) byte {
+	var y byte
+	for i, si := range shares {
+		num, den := byte(1), byte(1)
+		for j, sj := range shares {
+			if i == j {
+				continue
+			}
+			// Subtraction is addition in GF(2^8)
+			num = mul(num, x^sj.X)
+			den = mul(den, si.X^sj.X)
+		}
+		y ^= mul(si.Data[k], mul(num, inv(den)))
+	}
+	return y
+}
+
+// MarshalBinary encodes the share as its evaluation point, its threshold,
+// its data and its tag.
+func (s *Share) MarshalBinary() ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteByte(s.X)
+	b.WriteByte(s.T)
+	b.Write(s.Data)
+	b.Write(s.Tag)
+	return b.Bytes(), nil
+}
+
+// UnmarshalBinary decodes a share encoded with MarshalBinary. The tag is not
+// checked; Combine skips shares with a wrong tag.
+func (s *Share) UnmarshalBinary(buf []byte) error {
+	if len(buf) < 2+digestLen+tagLen {
+		return errorEncoding
+	}
+	s.X = buf[0]
+	s.T = buf[1]
+	s.Data = append([]byte{}, buf[2:len(buf)-tagLen]...)
+	s.Tag = append([]byte{}, buf[len(buf)-tagLen:]...)
+	return nil
+}
@@ -0,0 +1,82 @@
+package bytes
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/dedis/crypto/random"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestField(t *testing.T) {
+	for a := 1; a < 256; a++ {
+		assert.Equal(t, byte(1), mul(byte(a), inv(byte(a))))
+	}
+	// Example from FIPS-197, section 4.2
+	assert.Equal(t, byte(0xc1), mul(0x57, 0x83))
+}
+
+func TestSplitCombine(t *testing.T) {
+	secret := random.Bytes(32, random.Stream)
+	shares, err := Split(secret, 3, 5, random.Stream)
+	require.Nil(t, err)
+	for _, sub := range [][]*Share{shares[:3], shares[2:], {shares[4], shares[0], shares[2]}, shares} {
+		s, err := Combine(sub)
+		require.Nil(t, err)
+		assert.True(t, bytes.Equal(secret, s))
+	}
+	_, err = Combine(shares[:2])
+	assert.Equal(t, errorTooFew, err)
+
+	empty, err := Split(nil, 1, 1, random.Stream)
+	require.Nil(t, err)
+	s, err := Combine(empty)
+	require.Nil(t, err)
+	assert.Equal(t, 0, len(s))
+
+	_, err = Split(secret, 3, 256, random.Stream)
+	assert.Equal(t, errorThreshold, err)
+	_, err = Split(secret, 0, 5, random.Stream)
+	assert.Equal(t, errorThreshold, err)
+}
+
+func TestCorruption(t *testing.T) {
+	secret := []byte("the key file")
+	shares, err := Split(secret, 3, 5, random.Stream)
+	require.Nil(t, err)
+
+	// A corrupted share is skipped thanks to its tag
+	bad := *shares[0]
+	bad.Data = append([]byte{}, bad.Data...)
+	bad.Data[0] ^= 1
+	s, err := Combine([]*Share{&bad, shares[1], shares[2], shares[3]})
+	require.Nil(t, err)
+	assert.Equal(t, secret, s)
+	_, err = Combine([]*Share{&bad, shares[1], shares[2]})
+	assert.Equal(t, errorTooFew, err)
+
+	// A forged share with a valid tag is caught by the digest
+	bad.Tag = tag(bad.X, bad.T, bad.Data)
+	_, err = Combine([]*Share{&bad, shares[1], shares[2]})
+	assert.Equal(t, errorIntegrity, err)
+	_, err = Combine([]*Share{shares[1], shares[2], shares[3], &bad})
+	assert.Equal(t, errorIntegrity, err)
+}
+
+func TestEncoding(t *testing.T) {
+	secret := random.Bytes(32, random.Stream)
+	shares, err := Split(secret, 2, 3, random.Stream)
+	require.Nil(t, err)
+	decoded := make([]*Share, len(shares))
+	for i, s := range shares {
+		buf, err := s.MarshalBinary()
+		require.Nil(t, err)
+		decoded[i] = new(Share)
+		require.Nil(t, decoded[i].UnmarshalBinary(buf))
+	}
+	s, err := Combine(decoded[1:])
+	require.Nil(t, err)
+	assert.Equal(t, secret, s)
+	assert.Equal(t, errorEncoding, new(Share).UnmarshalBinary([]byte{1, 2, 3}))
+}
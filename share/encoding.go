@@ -0,0 +1,374 @@
+package share
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/dedis/crypto/abstract"
+)
+
+// The binary encodings of shares and polynomials consist of the binary
+// encodings of their points and scalars, like abstract.Point and
+// abstract.Scalar, and do not encode the group, which is given by the object
+// being decoded. The JSON encodings represent every point and scalar as the
+// hex string of its binary encoding.
+
+// NewPriShare returns an empty private share over g, ready to be decoded.
+func NewPriShare(g abstract.Group) *PriShare {
+	return &PriShare{V: g.Scalar()}
+}
+
+// NewPubShare returns an empty public share over g, ready to be decoded.
+func NewPubShare(g abstract.Group) *PubShare {
+	return &PubShare{V: g.Point()}
+}
+
+// writeIndex writes a share index as a 32-bit big-endian integer.
+func writeIndex(w io.Writer, i int) (int, error) {
+	if int(int32(i)) != i {
+		return 0, fmt.Errorf("share: index %d does not fit 32 bits: %w", i, ErrEncoding)
+	}
+	if err := binary.Write(w, binary.BigEndian, int32(i)); err != nil {
+		return 0, err
+	}
+	return 4, nil
+}
+
+func readIndex(r io.Reader) (int, error) {
+	var i int32
+	if err := binary.Read(r, binary.BigEndian, &i); err != nil {
+		return 0, fmt.Errorf("share: decoding index: %w", ErrEncoding)
+	}
+	return int(i), nil
+}
+
+// marshalShare encodes a share as its index followed by its value.
+func marshalShare(i int, v abstract.Marshaling) ([]byte, error) {
+	var b bytes.Buffer
+	if _, err := writeIndex(&b, i); err != nil {
+		return nil, err
+	}
+	if _, err := v.MarshalTo(&b); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+func unmarshalShare(buf []byte, v abstract.Marshaling) (int, error) {
+	if v == nil {
+		return 0, fmt.Errorf("share: decoding into unallocated share: %w", ErrEncoding)
+	}
+	if len(buf) != 4+v.MarshalSize() {
+		return 0, fmt.Errorf("share: share of %d bytes instead of %d: %w", len(buf), 4+v.MarshalSize(), ErrEncoding)
+	}
+	r := bytes.NewReader(buf)
+	i, _ := readIndex(r)
+	if _, err := v.UnmarshalFrom(r); err != nil {
+		return 0, err
+	}
+	return i, nil
+}
+
+// String returns the index of the share, but not its value.
+func (s *PriShare) String() string {
+	return fmt.Sprintf("PriShare(%d)", s.I)
+}
+
+// MarshalSize returns the length of the encoding of the share.
+func (s *PriShare) MarshalSize() int {
+	return 4 + s.V.MarshalSize()
+}
+
+// MarshalBinary encodes the share as its index as a 32-bit big-endian integer
+// followed by the encoding of its value.
+func (s *PriShare) MarshalBinary() ([]byte, error) {
+	return marshalShare(s.I, s.V)
+}
+
+// UnmarshalBinary decodes a share encoded with MarshalBinary. The share must
+// have been created with NewPriShare for the group of the encoding.
+func (s *PriShare) UnmarshalBinary(buf []byte) error {
+	i, err := unmarshalShare(buf, s.V)
+	if err != nil {
+		return err
+	}
+	s.I = i
+	return nil
+}
+
+// MarshalTo writes the encoding of the share to w.
+func (s *PriShare) MarshalTo(w io.Writer) (int, error) {
+	n, err := writeIndex(w, s.I)
+	if err != nil {
+		return n, err
+	}
+	m, err := s.V.MarshalTo(w)
+	return n + m, err
+}
+
+// UnmarshalFrom reads the encoding of the share from r.
+func (s *PriShare) UnmarshalFrom(r io.Reader) (int, error) {
+	i, err := readIndex(r)
+	if err != nil {
+		return 0, err
+	}
+	n, err := s.V.UnmarshalFrom(r)
+	s.I = i
+	return 4 + n, err
+}
+
+// String returns the index and the value of the share.
+func (s *PubShare) String() string {
+	return fmt.Sprintf("PubShare(%d, %s)", s.I, s.V)
+}
+
+// MarshalSize returns the length of the encoding of the share.
+func (s *PubShare) MarshalSize() int {
+	return 4 + s.V.MarshalSize()
+}
+
+// MarshalBinary encodes the share as its index as a 32-bit big-endian integer
+// followed by the encoding of its value.
+func (s *PubShare) MarshalBinary() ([]byte, error) {
+	return marshalShare(s.I, s.V)
+}
+
+// UnmarshalBinary decodes a share encoded with MarshalBinary. The share must
+// have been created with NewPubShare for the group of the encoding.
+func (s *PubShare) UnmarshalBinary(buf []byte) error {
+	i, err := unmarshalShare(buf, s.V)
+	if err != nil {
+		return err
+	}
+	s.I = i
+	return nil
+}
+
+// MarshalTo writes the encoding of the share to w.
+func (s *PubShare) MarshalTo(w io.Writer) (int, error) {
+	n, err := writeIndex(w, s.I)
+	if err != nil {
+		return n, err
+	}
+	m, err := s.V.MarshalTo(w)
+	return n + m, err
+}
+
+// UnmarshalFrom reads the encoding of the share from r.
+func (s *PubShare) UnmarshalFrom(r io.Reader) (int, error) {
+	i, err := readIndex(r)
+	if err != nil {
+		return 0, err
+	}
+	n, err := s.V.UnmarshalFrom(r)
+	s.I = i
+	return 4 + n, err
+}
+
+// MarshalSize returns the length of the encoding of the polynomial.
+func (p *PriPoly) MarshalSize() int {
+	return 4 + len(p.coeffs)*p.g.ScalarLen()
+}
+
+// MarshalBinary encodes the polynomial as the number of coefficients as a
+// 32-bit big-endian integer followed by the coefficients.
+func (p *PriPoly) MarshalBinary() ([]byte, error) {
+	var b bytes.Buffer
+	binary.Write(&b, binary.BigEndian, uint32(p.Threshold()))
+	for _, c := range p.coeffs {
+		if _, err := c.MarshalTo(&b); err != nil {
+			return nil, err
+		}
+	}
+	return b.Bytes(), nil
+}
+
+// UnmarshalPriPoly decodes a polynomial over the group g encoded with
+// MarshalBinary.
+func UnmarshalPriPoly(g abstract.Group, buf []byte) (*PriPoly, error) {
+	r := bytes.NewReader(buf)
+	var t uint32
+	if err := binary.Read(r, binary.BigEndian, &t); err != nil {
+		return nil, fmt.Errorf("share: decoding threshold: %w", ErrEncoding)
+	}
+	if t == 0 || uint64(r.Len()) != uint64(t)*uint64(g.ScalarLen()) {
+		return nil, fmt.Errorf("share: %d bytes for %d coefficients: %w", r.Len(), t, ErrEncoding)
+	}
+	p := &PriPoly{g: g, coeffs: make([]abstract.Scalar, t)}
+	for i := range p.coeffs {
+		p.coeffs[i] = g.Scalar()
+		if _, err := p.coeffs[i].UnmarshalFrom(r); err != nil {
+			return nil, err
+		}
+	}
+	return p, nil
+}
+
+// MarshalSize returns the length of the encoding of the polynomial.
+func (p *PubPoly) MarshalSize() int {
+	size := 5 + len(p.commits)*p.g.PointLen()
+	if p.b != nil {
+		size += p.g.PointLen()
+	}
+	return size
+}
+
+type shareJSON struct {
+	Index int    `json:"index"`
+	Value string `json:"value"`
+}
+
+type polyJSON struct {
+	Base   string   `json:"base,omitempty"`
+	Coeffs []string `json:"coeffs"`
+}
+
+func toHex(m encoding.BinaryMarshaler) (string, error) {
+	buf, err := m.MarshalBinary()
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+func fromHex(m encoding.BinaryUnmarshaler, s string) error {
+	buf, err := hex.DecodeString(s)
+	if err != nil {
+		return fmt.Errorf("share: decoding hex value: %w", ErrEncoding)
+	}
+	if err := m.UnmarshalBinary(buf); err != nil {
+		return fmt.Errorf("share: decoding value: %w", ErrEncoding)
+	}
+	return nil
+}
+
+func marshalShareJSON(i int, v abstract.Marshaling) ([]byte, error) {
+	value, err := toHex(v)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(&shareJSON{i, value})
+}
+
+func unmarshalShareJSON(buf []byte, v abstract.Marshaling) (int, error) {
+	if v == nil {
+		return 0, fmt.Errorf("share: decoding into unallocated share: %w", ErrEncoding)
+	}
+	var js shareJSON
+	if err := json.Unmarshal(buf, &js); err != nil {
+		return 0, fmt.Errorf("share: decoding share: %w", ErrEncoding)
+	}
+	if err := fromHex(v, js.Value); err != nil {
+		return 0, err
+	}
+	return js.Index, nil
+}
+
+// MarshalJSON encodes the share as a JSON object holding its index and value.
+func (s *PriShare) MarshalJSON() ([]byte, error) {
+	return marshalShareJSON(s.I, s.V)
+}
+
+// UnmarshalJSON decodes a share encoded with MarshalJSON. Like
+// UnmarshalBinary it requires a share created with NewPriShare.
+func (s *PriShare) UnmarshalJSON(buf []byte) error {
+	i, err := unmarshalShareJSON(buf, s.V)
+	if err != nil {
+		return err
+	}
+	s.I = i
+	return nil
+}
+
+// MarshalJSON encodes the share as a JSON object holding its index and value.
+func (s *PubShare) MarshalJSON() ([]byte, error) {
+	return marshalShareJSON(s.I, s.V)
+}
+
+// UnmarshalJSON decodes a share encoded with MarshalJSON. Like
+// UnmarshalBinary it requires a share created with NewPubShare.
+func (s *PubShare) UnmarshalJSON(buf []byte) error {
+	i, err := unmarshalShareJSON(buf, s.V)
+	if err != nil {
+		return err
+	}
+	s.I = i
+	return nil
+}
+
+// MarshalJSON encodes the polynomial as a JSON object holding its
+// coefficients.
+func (p *PriPoly) MarshalJSON() ([]byte, error) {
+	js := &polyJSON{Coeffs: make([]string, len(p.coeffs))}
+	for i, c := range p.coeffs {
+		var err error
+		if js.Coeffs[i], err = toHex(c); err != nil {
+			return nil, err
+		}
+	}
+	return json.Marshal(js)
+}
+
+// UnmarshalPriPolyJSON decodes a polynomial over the group g encoded with
+// MarshalJSON.
+func UnmarshalPriPolyJSON(g abstract.Group, buf []byte) (*PriPoly, error) {
+	var js polyJSON
+	if err := json.Unmarshal(buf, &js); err != nil || len(js.Coeffs) == 0 || js.Base != "" {
+		return nil, fmt.Errorf("share: decoding private polynomial: %w", ErrEncoding)
+	}
+	p := &PriPoly{g: g, coeffs: make([]abstract.Scalar, len(js.Coeffs))}
+	for i, c := range js.Coeffs {
+		p.coeffs[i] = g.Scalar()
+		if err := fromHex(p.coeffs[i], c); err != nil {
+			return nil, err
+		}
+	}
+	return p, nil
+}
+
+// MarshalJSON encodes the polynomial as a JSON object holding the
+// commitments to the coefficients and, unless it is the standard base point,
+// the base point.
+func (p *PubPoly) MarshalJSON() ([]byte, error) {
+	js := &polyJSON{Coeffs: make([]string, len(p.commits))}
+	var err error
+	if p.b != nil {
+		if js.Base, err = toHex(p.b); err != nil {
+			return nil, err
+		}
+	}
+	for i, c := range p.commits {
+		if js.Coeffs[i], err = toHex(c); err != nil {
+			return nil, err
+		}
+	}
+	return json.Marshal(js)
+}
+
+// UnmarshalPubPolyJSON decodes a polynomial over the group g encoded with
+// MarshalJSON.
+func UnmarshalPubPolyJSON(g abstract.Group, buf []byte) (*PubPoly, error) {
+	var js polyJSON
+	if err := json.Unmarshal(buf, &js); err != nil || len(js.Coeffs) == 0 {
+		return nil, fmt.Errorf("share: decoding public polynomial: %w", ErrEncoding)
+	}
+	p := &PubPoly{g: g, commits: make([]abstract.Point, len(js.Coeffs))}
+	if js.Base != "" {
+		p.b = g.Point()
+		if err := fromHex(p.b, js.Base); err != nil {
+			return nil, err
+		}
+	}
+	for i, c := range js.Coeffs {
+		p.commits[i] = g.Point()
+		if err := fromHex(p.commits[i], c); err != nil {
+			return nil, err
+		}
+	}
+	return p, nil
+}
@@ -0,0 +1,487 @@
+// Package feldman implements Feldman's verifiable secret sharing as a
+// synchronous protocol between a dealer and n verifiers, with a complaint and
+// a justification round to resolve accusations against the dealer:
+//
+//	dealer:   d := NewDealer(suite, longterm, secret, verifiers, t)
+//	          broadcast d.Commitment()
+//	          send d.Deals()[i] privately to verifier i
+//	verifier: v.ProcessCommitment(c)
+//	          resp := v.ProcessDeal(deal), or resp := v.Complain() if no
+//	          deal arrived, broadcast resp
+//	dealer:   j := d.ProcessResponse(resp), broadcast j if not nil
+//	verifier: v.ProcessResponse(resp) for the responses of the others
+//	          v.ProcessJustification(j) for the dealer's justifications
+//	dealer:   js := d.SetTimeout() once the response round is over,
+//	          broadcast js
+//	verifier: v.SetTimeout(), v.ProcessJustification(j) for every j in js
+//	all:      Certified()
+//
+// The dealer's public commitment polynomial is broadcast, so all verifiers
+// agree on the session even if the dealer withholds or corrupts some deals.
+// A verifier complains if its deal is missing or its share does not match
+// the commitments, and a response for another session as well as a missing
+// response count as complaints. The dealer answers a complaint by revealing
+// the verifier's deal, which everybody checks against the commitments. The
+// sharing is certified if every verifier either approved its deal or the
+// dealer revealed a valid deal for it, and it fails if the dealer revealed a
+// signed deal that does not match the commitments. Since this only depends on
+// broadcast messages signed by the dealer and the verifiers, all honest
+// verifiers reach the same verdict. All messages are
+// signed with the long-term keys of their senders; deals must be sent over
+// private channels.
+package feldman
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+
+	"github.com/dedis/crypto/abstract"
+	"github.com/dedis/crypto/random"
+	"github.com/dedis/crypto/share"
+	"github.com/dedis/crypto/sign"
+)
+
+// Some error definitions
+var errorThreshold = errors.New("invalid threshold")
+var errorSession = errors.New("message for another session")
+var errorIndex = errors.New("invalid verifier index")
+var errorShare = errors.New("share does not match commitments")
+var errorSignature = errors.New("invalid signature")
+var errorDuplicate = errors.New("duplicate message")
+var errorDealt = errors.New("deal already processed")
+var errorNoCommitment = errors.New("no commitment processed")
+var errorJustification = errors.New("invalid justification")
+var errorNoComplaint = errors.New("justification without complaint")
+
+// Commitment is the dealer's broadcast of its public commitment polynomial,
+// signed by the dealer. It fixes the session for all verifiers.
+type Commitment struct {
+	SessionID []byte
+	Commits   *share.PubPoly
+	Signature []byte
+}
+
+// Deal is the dealer's message to one verifier: its share, signed by the
+// dealer.
+type Deal struct {
+	SessionID []byte
+	Share     *share.PriShare
+	Signature []byte
+}
+
+// Response is a verifier's broadcast approval of, or complaint about, its
+// deal, signed by the verifier.
+type Response struct {
+	SessionID []byte
+	Index     int  // Index of the verifier
+	Approved  bool // False for a complaint
+	Signature []byte
+}
+
+// Justification is the dealer's broadcast answer to a complaint: the deal of
+// the complaining verifier.
+type Justification struct {
+	Index int // Index of the complaining verifier
+	Deal  *Deal
+}
+
+// sessionID binds the dealer's and verifiers' keys and the commitments.
+func sessionID(suite abstract.Suite, dealer abstract.Point, verifiers []abstract.Point, commits *share.PubPoly) ([]byte, error) {
+	h := suite.Hash()
+	h.Write([]byte("feldman-session"))
+	binary.Write(h, binary.BigEndian, uint32(len(verifiers)))
+	_, coeffs := commits.Info()
+	binary.Write(h, binary.BigEndian, uint32(len(coeffs)))
+	points := append([]abstract.Point{dealer}, verifiers...)
+	for _, P := range append(points, coeffs...) {
+		if _, err := P.MarshalTo(h); err != nil {
+			return nil, err
+		}
+	}
+	return h.Sum(nil), nil
+}
+
+func (c *Commitment) message() []byte {
+	return append([]byte("feldman-commitment"), c.SessionID...)
+}
+
+func (d *Deal) message() ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteString("feldman-deal")
+	b.Write(d.SessionID)
+	binary.Write(&b, binary.BigEndian, uint32(d.Share.I))
+	if _, err := d.Share.V.MarshalTo(&b); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+func (r *Response) message() []byte {
+	var b bytes.Buffer
+	b.WriteString("feldman-response")
+	b.Write(r.SessionID)
+	binary.Write(&b, binary.BigEndian, uint32(r.Index))
+	if r.Approved {
+		b.WriteByte(1)
+	} else {
+		b.WriteByte(0)
+	}
+	return b.Bytes()
+}
+
+// aggregator collects the responses and justifications of a session. Both
+// the dealer and the verifiers run one.
+type aggregator struct {
+	suite     abstract.Suite
+	dealer    abstract.Point
+	verifiers []abstract.Point
+	t         int
+	sid       []byte
+	commits   *share.PubPoly
+	responses map[int]*Response
+	justified map[int]bool
+	timeout   bool
+	badDealer bool
+}
+
+func newAggregator(suite abstract.Suite, dealer abstract.Point, verifiers []abstract.Point, t int) *aggregator {
+	return &aggregator{
+		suite:     suite,
+		dealer:    dealer,
+		verifiers: verifiers,
+		t:         t,
+		responses: make(map[int]*Response),
+		justified: make(map[int]bool),
+	}
+}
+
+// setCommits fixes the session to the given commitments.
+func (a *aggregator) setCommits(commits *share.PubPoly) error {
+	sid, err := a.sessionID(commits)
+	if err != nil {
+		return err
+	}
+	a.sid = sid
+	a.commits = commits
+	return nil
+}
+
+func (a *aggregator) sessionID(commits *share.PubPoly) ([]byte, error) {
+	if commits == nil || commits.Threshold() != a.t {
+		return nil, errorThreshold
+	}
+	return sessionID(a.suite, a.dealer, a.verifiers, commits)
+}
+
+// Commits returns the public commitment polynomial of the sharing, or nil if
+// the dealer's commitment has not been processed.
+func (a *aggregator) Commits() *share.PubPoly {
+	return a.commits
+}
+
+// approved reports whether verifier i approved its deal of this session.
+func (a *aggregator) approved(i int) bool {
+	r := a.responses[i]
+	return r != nil && r.Approved && bytes.Equal(r.SessionID, a.sid)
+}
+
+// verifyDeal checks the deal's signature, session and share.
+func (a *aggregator) verifyDeal(d *Deal, index int) error {
+	if d == nil || d.Share == nil || d.Share.I != index {
+		return errorIndex
+	}
+	if !bytes.Equal(d.SessionID, a.sid) {
+		return errorSession
+	}
+	msg, err := d.message()
+	if err != nil {
+		return err
+	}
+	if err := sign.VerifySchnorr(a.suite, a.dealer, msg, d.Signature); err != nil {
+		return errorSignature
+	}
+	if !a.commits.Check(d.Share) {
+		return errorShare
+	}
+	return nil
+}
+
+// addResponse records a response. A response for another session counts as
+// a complaint, since the verifier may have received a deal from the dealer
+// for another sharing.
+func (a *aggregator) addResponse(r *Response) error {
+	if r.Index < 0 || r.Index >= len(a.verifiers) {
+		return errorIndex
+	}
+	if err := sign.VerifySchnorr(a.suite, a.verifiers[r.Index], r.message(), r.Signature); err != nil {
+		return errorSignature
+	}
+	if a.responses[r.Index] != nil {
+		return errorDuplicate
+	}
+	a.responses[r.Index] = r
+	return nil
+}
+
+// addJustification checks the dealer's answer to a complaint or to a
+// missing response.
+func (a *aggregator) addJustification(j *Justification) error {
+	if j.Index < 0 || j.Index >= len(a.verifiers) {
+		return errorIndex
+	}
+	if a.approved(j.Index) {
+		return errorNoComplaint
+	}
+	if a.justified[j.Index] {
+		return errorDuplicate
+	}
+	// Justifications are not authenticated as a whole, so only a deal the
+	// dealer signed for this verifier and session can blame the dealer; any
+	// other deal may come from anybody and is dropped
+	if err := a.verifyDeal(j.Deal, j.Index); err == errorShare {
+		a.badDealer = true
+		return errorJustification
+	} else if err != nil {
+		return err
+	}
+	a.justified[j.Index] = true
+	return nil
+}
+
+func (a *aggregator) certified() bool {
+	if a.badDealer || a.sid == nil {
+		return false
+	}
+	if !a.timeout && len(a.responses) < len(a.verifiers) {
+		return false
+	}
+	for i := range a.verifiers {
+		if !a.approved(i) && !a.justified[i] {
+			// Missing response or complaint not justified (yet)
+			return false
+		}
+	}
+	return true
+}
+
+// Dealer is the dealer of one Feldman VSS session.
+type Dealer struct {
+	*aggregator
+	longterm   abstract.Scalar
+	commitment *Commitment
+	deals      []*Deal
+}
+
+// NewDealer shares the secret with threshold t among the verifiers with the
+// given long-term public keys. The dealer signs its messages with its
+// long-term private key.
+func NewDealer(suite abstract.Suite, longterm, secret abstract.Scalar, verifiers []abstract.Point, t int) (*Dealer, error) {
+	if t < 1 || t > len(verifiers) {
+		return nil, errorThreshold
+	}
+	d := &Dealer{
+		aggregator: newAggregator(suite, suite.Point().Mul(nil, longterm), verifiers, t),
+		longterm:   longterm,
+	}
+	priPoly := share.NewPriPoly(suite, t, secret, random.Stream)
+	if err := d.setCommits(priPoly.Commit(nil)); err != nil {
+		return nil, err
+	}
+	d.commitment = &Commitment{SessionID: d.sid, Commits: d.commits}
+	var err error
+	if d.commitment.Signature, err = sign.Schnorr(suite, longterm, d.commitment.message()); err != nil {
+		return nil, err
+	}
+	for _, s := range priPoly.Shares(len(verifiers)) {
+		deal := &Deal{SessionID: d.sid, Share: s}
+		msg, err := deal.message()
+		if err != nil {
+			return nil, err
+		}
+		if deal.Signature, err = sign.Schnorr(suite, longterm, msg); err != nil {
+			return nil, err
+		}
+		d.deals = append(d.deals, deal)
+	}
+	return d, nil
+}
+
+// Commitment returns the commitment to broadcast to all verifiers.
+func (d *Dealer) Commitment() *Commitment {
+	return d.commitment
+}
+
+// Deals returns the deals of the verifiers, in verifier order.
+func (d *Dealer) Deals() []*Deal {
+	return d.deals
+}
+
+// ProcessResponse records a verifier's response. For a complaint it returns
+// the justification to broadcast.
+func (d *Dealer) ProcessResponse(r *Response) (*Justification, error) {
+	if err := d.addResponse(r); err != nil {
+		return nil, err
+	}
+	if d.approved(r.Index) {
+		return nil, nil
+	}
+	j := &Justification{r.Index, d.deals[r.Index]}
+	d.justified[r.Index] = true
+	return j, nil
+}
+
+// SetTimeout ends the response round. It returns the justifications of the
+// verifiers that did not respond, which the dealer must broadcast like the
+// answers to complaints.
+func (d *Dealer) SetTimeout() []*Justification {
+	d.timeout = true
+	var js []*Justification
+	for i, deal := range d.deals {
+		if d.responses[i] == nil && !d.justified[i] {
+			js = append(js, &Justification{i, deal})
+			d.justified[i] = true
+		}
+	}
+	return js
+}
+
+// Certified reports whether the sharing is certified.
+func (d *Dealer) Certified() bool {
+	return d.certified()
+}
+
+// Verifier is a verifier of one Feldman VSS session.
+type Verifier struct {
+	*aggregator
+	longterm abstract.Scalar
+	index    int
+	deal     *Deal
+}
+
+// NewVerifier creates the verifier with the given long-term private key for
+// the session of the dealer with public key dealer among the verifiers with
+// the given public keys and threshold t.
+func NewVerifier(suite abstract.Suite, longterm abstract.Scalar, dealer abstract.Point, verifiers []abstract.Point, t int) (*Verifier, error) {
+	if t < 1 || t > len(verifiers) {
+		return nil, errorThreshold
+	}
+	pub := suite.Point().Mul(nil, longterm)
+	for i, X := range verifiers {
+		if X.Equal(pub) {
+			return &Verifier{newAggregator(suite, dealer, verifiers, t), longterm, i, nil}, nil
+		}
+	}
+	return nil, errorIndex
+}
+
+// Index returns the verifier's index.
+func (v *Verifier) Index() int {
+	return v.index
+}
+
+// ProcessCommitment checks the dealer's broadcast commitment, which fixes the
+// session. A second, different commitment disqualifies the dealer.
+func (v *Verifier) ProcessCommitment(c *Commitment) error {
+	if c == nil {
+		return errorSession
+	}
+	sid, err := v.sessionID(c.Commits)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(sid, c.SessionID) {
+		return errorSession
+	}
+	if err := sign.VerifySchnorr(v.suite, v.dealer, c.message(), c.Signature); err != nil {
+		return errorSignature
+	}
+	if v.sid != nil {
+		if !bytes.Equal(v.sid, sid) {
+			v.badDealer = true
+		}
+		return errorDuplicate
+	}
+	return v.setCommits(c.Commits)
+}
+
+// ProcessDeal checks the verifier's deal against the dealer's commitment and
+// returns the signed response to broadcast: an approval if the share matches
+// the commitments, a complaint otherwise.
+func (v *Verifier) ProcessDeal(d *Deal) (*Response, error) {
+	if v.sid == nil {
+		return nil, errorNoCommitment
+	}
+	if v.responses[v.index] != nil {
+		return nil, errorDealt
+	}
+	return v.respond(v.verifyDeal(d, v.index) == nil, d)
+}
+
+// Complain returns the signed complaint to broadcast if the verifier's deal
+// did not arrive in time, or nil if the verifier has already responded.
+func (v *Verifier) Complain() (*Response, error) {
+	if v.sid == nil {
+		return nil, errorNoCommitment
+	}
+	if v.responses[v.index] != nil {
+		return nil, nil
+	}
+	return v.respond(false, nil)
+}
+
+func (v *Verifier) respond(approved bool, d *Deal) (*Response, error) {
+	r := &Response{SessionID: v.sid, Index: v.index, Approved: approved}
+	var err error
+	if r.Signature, err = sign.Schnorr(v.suite, v.longterm, r.message()); err != nil {
+		return nil, err
+	}
+	if err := v.addResponse(r); err != nil {
+		return nil, err
+	}
+	if approved {
+		v.deal = d
+	}
+	return r, nil
+}
+
+// ProcessResponse records the response of another verifier.
+func (v *Verifier) ProcessResponse(r *Response) error {
+	if v.sid == nil {
+		return errorNoCommitment
+	}
+	return v.addResponse(r)
+}
+
+// ProcessJustification checks the dealer's answer to a complaint or to a
+// missing response. A valid justification of the verifier's own complaint
+// gives it its share. A deal signed by the dealer whose share does not match
+// the commitments disqualifies the dealer; a deal that is not signed by the
+// dealer for the complaining verifier and this session is dropped.
+func (v *Verifier) ProcessJustification(j *Justification) error {
+	if v.sid == nil {
+		return errorNoCommitment
+	}
+	if err := v.addJustification(j); err != nil {
+		return err
+	}
+	if j.Index == v.index {
+		v.deal = j.Deal
+	}
+	return nil
+}
+
+// SetTimeout ends the response round, see Dealer.SetTimeout.
+func (v *Verifier) SetTimeout() {
+	v.timeout = true
+}
+
+// Certified reports whether the sharing is certified.
+func (v *Verifier) Certified() bool {
+	return v.certified()
+}
+
+// Deal returns the verifier's valid deal, or nil if it has none.
+func (v *Verifier) Deal() *Deal {
+	return v.deal
+}
@@ -0,0 +1,317 @@
+package feldman
+
+import (
+	"testing"
+
+	"github.com/dedis/crypto/abstract"
+	"github.com/dedis/crypto/edwards"
+	"github.com/dedis/crypto/random"
+	"github.com/dedis/crypto/share"
+	"github.com/dedis/crypto/sign"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var suite = edwards.NewAES128SHA256Ed25519(false)
+
+func setup(t *testing.T, n, th int) (*Dealer, []*Verifier, abstract.Scalar) {
+	dealerKey := suite.Scalar().Pick(random.Stream)
+	keys := make([]abstract.Scalar, n)
+	pubs := make([]abstract.Point, n)
+	for i := range keys {
+		keys[i] = suite.Scalar().Pick(random.Stream)
+		pubs[i] = suite.Point().Mul(nil, keys[i])
+	}
+	secret := suite.Scalar().Pick(random.Stream)
+	d, err := NewDealer(suite, dealerKey, secret, pubs, th)
+	require.Nil(t, err)
+	verifiers := make([]*Verifier, n)
+	for i := range verifiers {
+		verifiers[i], err = NewVerifier(suite, keys[i], suite.Point().Mul(nil, dealerKey), pubs, th)
+		require.Nil(t, err)
+		assert.Equal(t, i, verifiers[i].Index())
+		require.Nil(t, verifiers[i].ProcessCommitment(d.Commitment()))
+	}
+	return d, verifiers, secret
+}
+
+// run delivers the deals, where a nil deal is withheld, broadcasts the
+// responses and justifications and ends the response round.
+func run(t *testing.T, d *Dealer, verifiers []*Verifier, deals []*Deal) {
+	var responses []*Response
+	for i, v := range verifiers {
+		var r *Response
+		var err error
+		if deals[i] != nil {
+			r, err = v.ProcessDeal(deals[i])
+		} else {
+			r, err = v.Complain()
+		}
+		require.Nil(t, err)
+		responses = append(responses, r)
+	}
+	for _, r := range responses {
+		j, err := d.ProcessResponse(r)
+		require.Nil(t, err)
+		for _, v := range verifiers {
+			if v.Index() != r.Index {
+				require.Nil(t, v.ProcessResponse(r))
+			}
+		}
+		if j != nil {
+			for _, v := range verifiers {
+				require.Nil(t, v.ProcessJustification(j))
+			}
+		}
+	}
+	js := d.SetTimeout()
+	for _, v := range verifiers {
+		v.SetTimeout()
+		for _, j := range js {
+			require.Nil(t, v.ProcessJustification(j))
+		}
+	}
+}
+
+func TestFeldman(t *testing.T) {
+	n, th := 5, 3
+	d, verifiers, secret := setup(t, n, th)
+	run(t, d, verifiers, d.Deals())
+	assert.True(t, d.Certified())
+	var shares []*share.PriShare
+	for _, v := range verifiers {
+		assert.True(t, v.Certified())
+		require.NotNil(t, v.Deal())
+		shares = append(shares, v.Deal().Share)
+	}
+	recovered, err := share.RecoverSecret(suite, shares, th, n)
+	require.Nil(t, err)
+	assert.True(t, recovered.Equal(secret))
+}
+
+func TestFeldmanComplaint(t *testing.T) {
+	n, th := 5, 3
+	d, verifiers, _ := setup(t, n, th)
+
+	// A deal corrupted in transit is complained about and justified
+	deals := append([]*Deal(nil), d.Deals()...)
+	bad := *deals[1]
+	bad.Share = &share.PriShare{I: 1, V: suite.Scalar().One()}
+	deals[1] = &bad
+	run(t, d, verifiers, deals)
+	assert.True(t, d.Certified())
+	for _, v := range verifiers {
+		assert.True(t, v.Certified())
+	}
+	assert.True(t, verifiers[1].Deal().Share.Equal(d.Deals()[1].Share))
+
+	// Responses are signed and bound to the session
+	_, err := verifiers[0].ProcessDeal(d.Deals()[0])
+	assert.Equal(t, errorDealt, err)
+	r := &Response{SessionID: d.sid, Index: 2, Approved: false, Signature: []byte("bad")}
+	assert.Equal(t, errorSignature, verifiers[0].ProcessResponse(r))
+	r, err = verifiers[0].Complain()
+	require.Nil(t, err)
+	assert.Nil(t, r)
+}
+
+func TestFeldmanWithheldDeal(t *testing.T) {
+	n, th := 5, 3
+	d, verifiers, _ := setup(t, n, th)
+
+	// A withheld deal is complained about and justified, so that all
+	// verifiers certify the sharing and the complaining one gets its share
+	deals := append([]*Deal(nil), d.Deals()...)
+	deals[3] = nil
+	run(t, d, verifiers, deals)
+	assert.True(t, d.Certified())
+	for _, v := range verifiers {
+		assert.True(t, v.Certified())
+	}
+	assert.True(t, verifiers[3].Deal().Share.Equal(d.Deals()[3].Share))
+}
+
+func TestFeldmanMissingResponse(t *testing.T) {
+	n, th := 5, 3
+	d, verifiers, _ := setup(t, n, th)
+	deals := d.Deals()
+	var responses []*Response
+	for i, v := range verifiers {
+		r, err := v.ProcessDeal(deals[i])
+		require.Nil(t, err)
+		responses = append(responses, r)
+	}
+
+	// Faulty verifier 1 does not respond and faulty verifier 2 responds for
+	// another session; both count as complaints for the honest verifiers
+	honest := []*Verifier{verifiers[0], verifiers[3], verifiers[4]}
+	other := *responses[2]
+	other.SessionID = []byte("another session")
+	other.Approved = true
+	other.Signature, _ = sign.Schnorr(suite, verifiers[2].longterm, other.message())
+	responses[2] = &other
+	responses = append(responses[:1], responses[2:]...)
+	for _, v := range honest {
+		for _, r := range responses {
+			if r.Index != v.Index() {
+				require.Nil(t, v.ProcessResponse(r))
+			}
+		}
+	}
+	var j *Justification
+	for _, r := range responses {
+		jr, err := d.ProcessResponse(r)
+		require.Nil(t, err)
+		if jr != nil {
+			j = jr
+		}
+	}
+	require.NotNil(t, j)
+	assert.Equal(t, 2, j.Index)
+	for _, v := range honest {
+		require.Nil(t, v.ProcessJustification(j))
+		v.SetTimeout()
+		assert.False(t, v.Certified())
+	}
+
+	// The missing response is answered once the response round is over
+	js := d.SetTimeout()
+	require.Equal(t, 1, len(js))
+	assert.Equal(t, 1, js[0].Index)
+	for _, v := range honest {
+		require.Nil(t, v.ProcessJustification(js[0]))
+		assert.True(t, v.Certified())
+	}
+}
+
+func TestFeldmanCommitment(t *testing.T) {
+	n, th := 5, 3
+	d, verifiers, _ := setup(t, n, th)
+	v := verifiers[0]
+	assert.True(t, v.Commits().Commit().Equal(d.Commits().Commit()))
+	assert.Equal(t, errorDuplicate, v.ProcessCommitment(d.Commitment()))
+
+	// Deals and responses need the dealer's commitment
+	fresh, err := NewVerifier(suite, v.longterm, v.dealer, v.verifiers, th)
+	require.Nil(t, err)
+	_, err = fresh.ProcessDeal(d.Deals()[0])
+	assert.Equal(t, errorNoCommitment, err)
+	bad := *d.Commitment()
+	bad.Signature = []byte("bad")
+	assert.Equal(t, errorSignature, fresh.ProcessCommitment(&bad))
+
+	// A dealer that broadcasts two commitments is disqualified
+	priPoly := share.NewPriPoly(suite, th, nil, random.Stream)
+	c := &Commitment{Commits: priPoly.Commit(nil)}
+	c.SessionID, err = sessionID(suite, v.dealer, v.verifiers, c.Commits)
+	require.Nil(t, err)
+	c.Signature, err = sign.Schnorr(suite, d.longterm, c.message())
+	require.Nil(t, err)
+	assert.Equal(t, errorDuplicate, v.ProcessCommitment(c))
+	run(t, d, verifiers, d.Deals())
+	assert.True(t, d.Certified())
+	assert.False(t, v.Certified())
+	assert.True(t, verifiers[1].Certified())
+}
+
+func TestFeldmanBadDealer(t *testing.T) {
+	n, th := 5, 3
+	d, verifiers, _ := setup(t, n, th)
+	deals := d.Deals()
+	var responses []*Response
+	for i, v := range verifiers[:4] {
+		r, err := v.ProcessDeal(deals[i])
+		require.Nil(t, err)
+		responses = append(responses, r)
+	}
+
+	// A complaint answered with a wrong deal signed by the dealer
+	// disqualifies the dealer
+	r, err := verifiers[4].Complain()
+	require.Nil(t, err)
+	bad := signedDeal(t, d, &share.PriShare{I: 4, V: suite.Scalar().One()})
+	for _, v := range verifiers[:4] {
+		require.Nil(t, v.ProcessResponse(r))
+		for _, o := range responses {
+			if o.Index != v.Index() {
+				require.Nil(t, v.ProcessResponse(o))
+			}
+		}
+		assert.Equal(t, errorJustification, v.ProcessJustification(&Justification{4, bad}))
+		v.SetTimeout()
+		assert.False(t, v.Certified())
+	}
+
+	assert.Equal(t, errorNoComplaint, verifiers[0].ProcessJustification(&Justification{1, deals[1]}))
+}
+
+func TestFeldmanForgedJustification(t *testing.T) {
+	n, th := 5, 3
+	d, verifiers, _ := setup(t, n, th)
+	deals := d.Deals()
+	var responses []*Response
+	for i, v := range verifiers[:4] {
+		r, err := v.ProcessDeal(deals[i])
+		require.Nil(t, err)
+		responses = append(responses, r)
+	}
+	r, err := verifiers[4].Complain()
+	require.Nil(t, err)
+	unsigned := *deals[0]
+	unsigned.Share = &share.PriShare{I: 4, V: suite.Scalar().One()}
+	other := *deals[4]
+	other.SessionID = []byte("other session")
+	for _, v := range verifiers[:4] {
+		require.Nil(t, v.ProcessResponse(r))
+		for _, o := range responses {
+			if o.Index != v.Index() {
+				require.Nil(t, v.ProcessResponse(o))
+			}
+		}
+		// Deals not signed by the dealer for the verifier and the session
+		// are dropped without disqualifying the dealer
+		assert.Equal(t, errorSignature, v.ProcessJustification(&Justification{4, &unsigned}))
+		assert.Equal(t, errorSession, v.ProcessJustification(&Justification{4, &other}))
+		assert.Equal(t, errorIndex, v.ProcessJustification(&Justification{4, deals[3]}))
+		require.Nil(t, v.ProcessJustification(&Justification{4, deals[4]}))
+		v.SetTimeout()
+		assert.True(t, v.Certified())
+	}
+}
+
+func TestFeldmanUnjustified(t *testing.T) {
+	n, th := 5, 3
+	d, verifiers, _ := setup(t, n, th)
+	deals := d.Deals()
+	var responses []*Response
+	for i, v := range verifiers {
+		deal := deals[i]
+		if i == 2 {
+			deal = deals[0]
+		}
+		r, err := v.ProcessDeal(deal)
+		require.Nil(t, err)
+		responses = append(responses, r)
+	}
+	assert.False(t, responses[2].Approved)
+	for _, v := range verifiers {
+		for _, r := range responses {
+			if r.Index != v.Index() {
+				require.Nil(t, v.ProcessResponse(r))
+			}
+		}
+		assert.False(t, v.Certified())
+		v.SetTimeout()
+		assert.False(t, v.Certified())
+	}
+}
+
+// signedDeal returns the deal of the share signed by the dealer.
+func signedDeal(t *testing.T, d *Dealer, s *share.PriShare) *Deal {
+	deal := &Deal{SessionID: d.sid, Share: s}
+	msg, err := deal.message()
+	require.Nil(t, err)
+	deal.Signature, err = sign.Schnorr(suite, d.longterm, msg)
+	require.Nil(t, err)
+	return deal
+}
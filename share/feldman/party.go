@@ -0,0 +1,154 @@
+package feldman
+
+import (
+	"errors"
+
+	"github.com/dedis/crypto/protocol"
+)
+
+// Some error definitions
+var errorPayload = errors.New("unexpected message payload")
+var errorSender = errors.New("message index does not match its sender")
+
+// DealerParty runs a Dealer as a protocol.ProtocolState. The verifiers take
+// the protocol indices of their public keys and the dealer the index
+// len(verifiers). On creation the dealer broadcasts its commitment and sends
+// the deals privately; it broadcasts the justification of every complaint.
+// It is done once the sharing is certified or, if some verifiers fail, once
+// the caller ended the response round with SetTimeout. The only payload it
+// accepts is *Response.
+type DealerParty struct {
+	protocol.Outbox
+	dealer  *Dealer
+	timeout bool
+}
+
+// NewDealerParty creates the protocol state of the dealer.
+func NewDealerParty(d *Dealer) *DealerParty {
+	p := &DealerParty{dealer: d}
+	index := len(d.verifiers)
+	p.Send(&protocol.Message{From: index, To: protocol.Broadcast, Payload: d.Commitment()})
+	for i, deal := range d.Deals() {
+		p.Send(&protocol.Message{From: index, To: i, Payload: deal})
+	}
+	return p
+}
+
+// ProcessMessage handles a verifier's response.
+func (p *DealerParty) ProcessMessage(msg *protocol.Message) error {
+	r, ok := msg.Payload.(*Response)
+	if !ok {
+		return errorPayload
+	}
+	if r.Index != msg.From {
+		return errorSender
+	}
+	j, err := p.dealer.ProcessResponse(r)
+	if err != nil || j == nil {
+		return err
+	}
+	p.Send(&protocol.Message{From: len(p.dealer.verifiers), To: protocol.Broadcast, Payload: j})
+	return nil
+}
+
+// SetTimeout ends the response round and broadcasts the justifications of
+// the verifiers that did not respond.
+func (p *DealerParty) SetTimeout() {
+	for _, j := range p.dealer.SetTimeout() {
+		p.Send(&protocol.Message{From: len(p.dealer.verifiers), To: protocol.Broadcast, Payload: j})
+	}
+	p.timeout = true
+}
+
+// Done returns true once the sharing is certified or the response round was
+// ended with SetTimeout.
+func (p *DealerParty) Done() bool {
+	return p.timeout || p.dealer.Certified()
+}
+
+// Dealer returns the underlying dealer.
+func (p *DealerParty) Dealer() *Dealer {
+	return p.dealer
+}
+
+// VerifierParty runs a Verifier as a protocol.ProtocolState, see
+// DealerParty for the protocol indices. It broadcasts its response to its
+// deal and keeps the messages that arrive before the dealer's commitment
+// until it does. It is done once the sharing is certified or, if some
+// participants fail, once the caller ended the response round with
+// SetTimeout. Payloads are *Commitment, *Deal, *Response and
+// *Justification.
+type VerifierParty struct {
+	protocol.Outbox
+	verifier *Verifier
+	pending  []*protocol.Message // Messages waiting for the commitment
+	timeout  bool
+}
+
+// NewVerifierParty creates the protocol state of a verifier.
+func NewVerifierParty(v *Verifier) *VerifierParty {
+	return &VerifierParty{verifier: v}
+}
+
+// ProcessMessage handles the dealer's commitment, deal and justifications
+// and the responses of the other verifiers.
+func (p *VerifierParty) ProcessMessage(msg *protocol.Message) error {
+	v := p.verifier
+	dealer := len(v.verifiers)
+	switch payload := msg.Payload.(type) {
+	case *Commitment, *Deal, *Justification:
+		if msg.From != dealer {
+			return errorSender
+		}
+	case *Response:
+		if payload.Index != msg.From {
+			return errorSender
+		}
+	default:
+		return errorPayload
+	}
+	if _, ok := msg.Payload.(*Commitment); !ok && v.sid == nil {
+		p.pending = append(p.pending, msg)
+		return nil
+	}
+	switch payload := msg.Payload.(type) {
+	case *Commitment:
+		if err := v.ProcessCommitment(payload); err != nil {
+			return err
+		}
+		pending := p.pending
+		p.pending = nil
+		for _, m := range pending {
+			// Errors of early messages are not attributable to this one
+			p.ProcessMessage(m)
+		}
+	case *Deal:
+		r, err := v.ProcessDeal(payload)
+		if err != nil {
+			return err
+		}
+		p.Send(&protocol.Message{From: v.index, To: protocol.Broadcast, Payload: r})
+	case *Response:
+		return v.ProcessResponse(payload)
+	case *Justification:
+		return v.ProcessJustification(payload)
+	}
+	return nil
+}
+
+// SetTimeout ends the response round.
+func (p *VerifierParty) SetTimeout() {
+	p.verifier.SetTimeout()
+	p.timeout = true
+}
+
+// Done returns true once the sharing is certified or the response round was
+// ended with SetTimeout.
+func (p *VerifierParty) Done() bool {
+	return p.timeout || p.verifier.Certified()
+}
+
+// Verifier returns the underlying verifier.
+func (p *VerifierParty) Verifier() *Verifier {
+	return p.verifier
+}
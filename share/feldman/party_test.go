@@ -0,0 +1,85 @@
+package feldman
+
+import (
+	"testing"
+
+	"github.com/dedis/crypto/abstract"
+	"github.com/dedis/crypto/protocol"
+	"github.com/dedis/crypto/protocol/protocoltest"
+	"github.com/dedis/crypto/random"
+	"github.com/dedis/crypto/share"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func partySetup(t *testing.T, n, th int) (*DealerParty, []*VerifierParty, []protocol.ProtocolState, abstract.Scalar) {
+	dealerKey := suite.Scalar().Pick(random.Stream)
+	keys := make([]abstract.Scalar, n)
+	pubs := make([]abstract.Point, n)
+	for i := range keys {
+		keys[i] = suite.Scalar().Pick(random.Stream)
+		pubs[i] = suite.Point().Mul(nil, keys[i])
+	}
+	secret := suite.Scalar().Pick(random.Stream)
+	d, err := NewDealer(suite, dealerKey, secret, pubs, th)
+	require.Nil(t, err)
+	verifiers := make([]*VerifierParty, n)
+	states := make([]protocol.ProtocolState, n+1)
+	for i := range verifiers {
+		v, err := NewVerifier(suite, keys[i], suite.Point().Mul(nil, dealerKey), pubs, th)
+		require.Nil(t, err)
+		verifiers[i] = NewVerifierParty(v)
+		states[i] = verifiers[i]
+	}
+	dealer := NewDealerParty(d)
+	states[n] = dealer
+	return dealer, verifiers, states, secret
+}
+
+func TestParty(t *testing.T) {
+	n, th := 5, 3
+	dealer, verifiers, states, secret := partySetup(t, n, th)
+
+	// A corrupted deal is complained about and justified
+	corrupt := protocoltest.BehaviorFunc(func(msg *protocol.Message, to int) []*protocol.Message {
+		if _, ok := msg.Payload.(*Deal); !ok || to != 1 {
+			return []*protocol.Message{msg}
+		}
+		bad := *dealer.Dealer().Deals()[1]
+		bad.Share = &share.PriShare{I: 1, V: suite.Scalar().Pick(random.Stream)}
+		return []*protocol.Message{{From: msg.From, To: msg.To, Payload: &bad}}
+	})
+	net := &protocoltest.Network{States: states, Behaviors: []protocoltest.Behavior{corrupt}}
+	res, err := net.Run()
+	require.Nil(t, err)
+	assert.Empty(t, res.Rejected)
+	assert.True(t, dealer.Dealer().Certified())
+	var shares []*share.PriShare
+	for _, v := range verifiers {
+		require.True(t, v.Verifier().Certified())
+		require.NotNil(t, v.Verifier().Deal())
+		shares = append(shares, v.Verifier().Deal().Share)
+	}
+	recovered, err := share.RecoverSecret(suite, shares, th, n)
+	require.Nil(t, err)
+	assert.True(t, secret.Equal(recovered))
+}
+
+func TestPartyTimeout(t *testing.T) {
+	// A crashed verifier stalls the response round until it times out
+	n, th := 5, 3
+	dealer, verifiers, states, _ := partySetup(t, n, th)
+	net := &protocoltest.Network{States: states, Behaviors: []protocoltest.Behavior{protocoltest.Drop(2)}}
+	_, err := net.Run()
+	require.NotNil(t, err)
+	dealer.SetTimeout()
+	for _, v := range verifiers {
+		v.SetTimeout()
+	}
+	_, err = net.Run()
+	require.Nil(t, err)
+	assert.True(t, dealer.Dealer().Certified())
+	for _, v := range verifiers {
+		assert.True(t, v.Verifier().Certified())
+	}
+}
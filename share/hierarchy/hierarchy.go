@@ -0,0 +1,218 @@
+// Package hierarchy implements secret sharing for hierarchical access
+// structures, such as "2 directors OR (1 director AND 3 managers)". An
+// access structure is a Policy tree whose leaves require a threshold of the
+// members of a level and whose inner nodes require a threshold of their
+// children:
+//
+//	p := Or(Level("director", 2, 3),
+//		And(Level("director", 1, 3), Level("manager", 3, 5)))
+//	shares, err := Deal(suite, p, secret, random.Stream)
+//	s, err := Recover(suite, p, append(shares["director"][0], shares["manager"][1]...))
+//
+// The secret is shared with nested Shamir polynomials: every node shares its
+// secret among its children with a polynomial of degree threshold-1, and
+// every leaf shares its secret among the members of its level. A member of a
+// level receives one share per leaf of that level.
+package hierarchy
+
+import (
+	"crypto/cipher"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/dedis/crypto/abstract"
+	"github.com/dedis/crypto/share"
+)
+
+// Some error definitions
+var errorPolicy = errors.New("invalid access policy")
+var errorTooFew = errors.New("shares do not satisfy the access policy")
+
+// Policy is a node of an access structure. A leaf is satisfied by Threshold
+// of the Size members of the level named Level, an inner node by Threshold of
+// its Children.
+type Policy struct {
+	Threshold int
+	Level     string
+	Size      int
+	Children  []*Policy
+}
+
+// Level returns a leaf satisfied by t of the n members of the named level.
+func Level(name string, t, n int) *Policy {
+	return &Policy{Threshold: t, Level: name, Size: n}
+}
+
+// Threshold returns a node satisfied by t of its children.
+func Threshold(t int, children ...*Policy) *Policy {
+	return &Policy{Threshold: t, Children: children}
+}
+
+// Or returns a node satisfied by any of its children.
+func Or(children ...*Policy) *Policy {
+	return Threshold(1, children...)
+}
+
+// And returns a node satisfied by all of its children.
+func And(children ...*Policy) *Policy {
+	return Threshold(len(children), children...)
+}
+
+// Check returns an error if a threshold of the policy is out of range or if
+// leaves of the same level disagree on its size.
+func (p *Policy) Check() error {
+	return p.check(make(map[string]int))
+}
+
+func (p *Policy) check(sizes map[string]int) error {
+	if p.leaf() {
+		if p.Level == "" || p.Threshold < 1 || p.Size < p.Threshold {
+			return errorPolicy
+		}
+		if n, ok := sizes[p.Level]; ok && n != p.Size {
+			return errorPolicy
+		}
+		sizes[p.Level] = p.Size
+		return nil
+	}
+	if p.Level != "" || p.Threshold < 1 || len(p.Children) < p.Threshold {
+		return errorPolicy
+	}
+	for _, c := range p.Children {
+		if c == nil {
+			return errorPolicy
+		}
+		if err := c.check(sizes); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *Policy) leaf() bool {
+	return len(p.Children) == 0
+}
+
+// Levels returns the size of every level of the policy.
+func (p *Policy) Levels() map[string]int {
+	sizes := make(map[string]int)
+	p.check(sizes)
+	return sizes
+}
+
+// Satisfied returns whether the given members, indexed from 0 within their
+// level, satisfy the policy.
+func (p *Policy) Satisfied(members map[string][]int) bool {
+	if p.leaf() {
+		seen := make(map[int]bool)
+		for _, i := range members[p.Level] {
+			if 0 <= i && i < p.Size {
+				seen[i] = true
+			}
+		}
+		return len(seen) >= p.Threshold
+	}
+	k := 0
+	for _, c := range p.Children {
+		if c.Satisfied(members) {
+			k++
+		}
+	}
+	return k >= p.Threshold
+}
+
+func (p *Policy) String() string {
+	if p.leaf() {
+		return fmt.Sprintf("%d-of-%d %s", p.Threshold, p.Size, p.Level)
+	}
+	s := make([]string, len(p.Children))
+	for i, c := range p.Children {
+		s[i] = c.String()
+	}
+	return fmt.Sprintf("%d-of(%s)", p.Threshold, strings.Join(s, ", "))
+}
+
+// Share is the share of a member of a level for one leaf of the policy.
+// Leaves are numbered from 0 in depth-first order, and the index of the
+// private share is the index of the member within its level.
+type Share struct {
+	Level string
+	Leaf  int
+	Share *share.PriShare
+}
+
+// Deal shares the secret according to the policy. The shares of member i of
+// level l are shares[l][i].
+func Deal(g abstract.Group, p *Policy, secret abstract.Scalar, rand cipher.Stream) (map[string][][]*Share, error) {
+	if err := p.Check(); err != nil {
+		return nil, err
+	}
+	shares := make(map[string][][]*Share)
+	for l, n := range p.Levels() {
+		shares[l] = make([][]*Share, n)
+	}
+	leaf := 0
+	p.deal(g, secret, rand, shares, &leaf)
+	return shares, nil
+}
+
+func (p *Policy) deal(g abstract.Group, s abstract.Scalar, rand cipher.Stream, shares map[string][][]*Share, leaf *int) {
+	poly := share.NewPriPoly(g, p.Threshold, s, rand)
+	if p.leaf() {
+		for i, v := range poly.Shares(p.Size) {
+			shares[p.Level][i] = append(shares[p.Level][i], &Share{p.Level, *leaf, v})
+		}
+		*leaf++
+		return
+	}
+	for i, c := range p.Children {
+		c.deal(g, poly.Eval(i).V, rand, shares, leaf)
+	}
+}
+
+// Recover reconstructs the secret from shares satisfying the policy. Shares
+// of leaves that cannot be reconstructed and repeated shares are ignored.
+func Recover(g abstract.Group, p *Policy, shares []*Share) (abstract.Scalar, error) {
+	if err := p.Check(); err != nil {
+		return nil, err
+	}
+	leaves := make(map[int][]*share.PriShare)
+	seen := make(map[[2]int]bool)
+	for _, s := range shares {
+		if s == nil || s.Share == nil || seen[[2]int{s.Leaf, s.Share.I}] {
+			continue
+		}
+		seen[[2]int{s.Leaf, s.Share.I}] = true
+		leaves[s.Leaf] = append(leaves[s.Leaf], s.Share)
+	}
+	leaf := 0
+	if s := p.recover(g, leaves, &leaf); s != nil {
+		return s, nil
+	}
+	return nil, errorTooFew
+}
+
+// recover returns the secret of the node, or nil if the shares do not
+// satisfy it.
+func (p *Policy) recover(g abstract.Group, leaves map[int][]*share.PriShare, leaf *int) abstract.Scalar {
+	if p.leaf() {
+		s, err := share.RecoverSecret(g, leaves[*leaf], p.Threshold, p.Size)
+		*leaf++
+		if err != nil {
+			return nil
+		}
+		return s
+	}
+	var sub []*share.PriShare
+	for i, c := range p.Children {
+		if s := c.recover(g, leaves, leaf); s != nil {
+			sub = append(sub, &share.PriShare{I: i, V: s})
+		}
+	}
+	s, err := share.RecoverSecret(g, sub, p.Threshold, len(p.Children))
+	if err != nil {
+		return nil
+	}
+	return s
+}
@@ -0,0 +1,66 @@
+package hierarchy
+
+import (
+	"testing"
+
+	"github.com/dedis/crypto/edwards"
+	"github.com/dedis/crypto/random"
+)
+
+func TestHierarchy(test *testing.T) {
+	g := edwards.NewAES128SHA256Ed25519(false)
+	p := Or(Level("director", 2, 3), And(Level("director", 1, 3), Level("manager", 3, 5)))
+	if p.String() != "1-of(2-of-3 director, 2-of(1-of-3 director, 3-of-5 manager))" {
+		test.Fatal("wrong policy string", p)
+	}
+	secret := g.Scalar().Pick(random.Stream)
+	shares, err := Deal(g, p, secret, random.Stream)
+	if err != nil {
+		test.Fatal(err)
+	}
+	if len(shares["director"]) != 3 || len(shares["manager"]) != 5 || len(shares["director"][0]) != 2 {
+		test.Fatal("wrong distribution of shares")
+	}
+
+	collect := func(members map[string][]int) []*Share {
+		var s []*Share
+		for l, is := range members {
+			for _, i := range is {
+				s = append(s, shares[l][i]...)
+			}
+		}
+		return s
+	}
+	for _, c := range []struct {
+		members map[string][]int
+		ok      bool
+	}{
+		{map[string][]int{"director": {0, 2}}, true},
+		{map[string][]int{"director": {1}, "manager": {0, 3, 4}}, true},
+		{map[string][]int{"director": {1}, "manager": {0, 3}}, false},
+		{map[string][]int{"manager": {0, 1, 2, 3, 4}}, false},
+		{map[string][]int{"director": {2, 2}}, false},
+	} {
+		if p.Satisfied(c.members) != c.ok {
+			test.Fatal("wrong satisfaction of", c.members)
+		}
+		s, err := Recover(g, p, collect(c.members))
+		if c.ok && (err != nil || !s.Equal(secret)) {
+			test.Fatal("failed to recover with", c.members, err)
+		}
+		if !c.ok && err != errorTooFew {
+			test.Fatal("recovered with", c.members)
+		}
+	}
+
+	for _, bad := range []*Policy{
+		Level("director", 4, 3),
+		Threshold(3, Level("a", 1, 2), Level("b", 1, 2)),
+		And(Level("a", 1, 2), Level("a", 1, 3)),
+		Or(),
+	} {
+		if _, err := Deal(g, bad, secret, random.Stream); err != errorPolicy {
+			test.Fatal("dealt with invalid policy", bad)
+		}
+	}
+}
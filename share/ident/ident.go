@@ -0,0 +1,292 @@
+// Package ident maps identities, such as node names or the encodings of
+// public keys, to the evaluation points of Shamir shares. Package share
+// evaluates polynomials at x = i+1 for share indices i, which depend on the
+// position of a participant in the committee and change whenever the
+// committee is reconfigured. Here, the evaluation point of an identity is
+// derived by hashing the identity alone, so a participant keeps its
+// evaluation point, and thus the meaning of its share, across committees:
+//
+//	r, err := NewRoster(suite, ids)     // fails on duplicate points
+//	x, _ := r.Point(id)
+//	v := Eval(priPoly, x)               // the share of id
+//	s, err := RecoverSecret(suite, xs, vs, t)
+//
+// A roster sorts its members by evaluation point and commits to them with a
+// Merkle tree (see package translog). A uniqueness proof shows a single
+// member that its evaluation point occurs exactly once in the roster with a
+// given root, without transferring the whole roster.
+package ident
+
+import (
+	"bytes"
+	"errors"
+	"sort"
+
+	"github.com/dedis/crypto/abstract"
+	"github.com/dedis/crypto/share"
+	"github.com/dedis/crypto/translog"
+)
+
+// Some error definitions
+var errorDuplicate = errors.New("duplicate identity")
+var errorCollision = errors.New("identities map to the same evaluation point")
+var errorUnknown = errors.New("identity not in roster")
+var errorProof = errors.New("invalid uniqueness proof")
+var errorTooFew = errors.New("not enough shares")
+
+// Point returns the evaluation point of the identity, a non-zero scalar
+// derived from the hash of the identity.
+func Point(suite abstract.Suite, id []byte) abstract.Scalar {
+	for ctr := byte(0); ; ctr++ {
+		h := suite.Hash()
+		h.Write([]byte("share-ident"))
+		h.Write([]byte{ctr})
+		h.Write(id)
+		x := suite.Scalar().Pick(suite.Cipher(h.Sum(nil)))
+		if !x.Equal(suite.Scalar().Zero()) {
+			return x
+		}
+	}
+}
+
+// Member is an identity together with its evaluation point.
+type Member struct {
+	ID []byte
+	X  abstract.Scalar
+}
+
+// leaf returns the encoding of the member in the roster's Merkle tree: the
+// evaluation point followed by the identity.
+func (m *Member) leaf() ([]byte, error) {
+	buf, err := m.X.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return append(buf, m.ID...), nil
+}
+
+// Roster is a set of identities with distinct evaluation points, sorted by
+// the encoding of their evaluation points.
+type Roster struct {
+	suite   abstract.Suite
+	members []*Member
+	keys    [][]byte // Encodings of the evaluation points, sorted
+	log     *translog.Log
+}
+
+// NewRoster creates the roster of the identities. It fails if an identity is
+// listed twice or if two identities map to the same evaluation point; the
+// latter happens with negligible probability unless the hash is broken.
+func NewRoster(suite abstract.Suite, ids [][]byte) (*Roster, error) {
+	r := &Roster{suite: suite, log: translog.NewLog(suite)}
+	for _, id := range ids {
+		m := &Member{append([]byte(nil), id...), Point(suite, id)}
+		key, err := m.X.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		r.members = append(r.members, m)
+		r.keys = append(r.keys, key)
+	}
+	sort.Sort(byKey{r})
+	for k := 1; k < len(r.keys); k++ {
+		if bytes.Equal(r.keys[k-1], r.keys[k]) {
+			if bytes.Equal(r.members[k-1].ID, r.members[k].ID) {
+				return nil, errorDuplicate
+			}
+			return nil, errorCollision
+		}
+	}
+	for _, m := range r.members {
+		leaf, err := m.leaf()
+		if err != nil {
+			return nil, err
+		}
+		r.log.Append(leaf)
+	}
+	return r, nil
+}
+
+type byKey struct{ r *Roster }
+
+func (b byKey) Len() int { return len(b.r.keys) }
+func (b byKey) Less(i, j int) bool {
+	return bytes.Compare(b.r.keys[i], b.r.keys[j]) < 0
+}
+func (b byKey) Swap(i, j int) {
+	b.r.keys[i], b.r.keys[j] = b.r.keys[j], b.r.keys[i]
+	b.r.members[i], b.r.members[j] = b.r.members[j], b.r.members[i]
+}
+
+// Len returns the number of members.
+func (r *Roster) Len() int {
+	return len(r.members)
+}
+
+// Members returns the members in roster order.
+func (r *Roster) Members() []*Member {
+	return r.members
+}
+
+// Point returns the evaluation point of the identity, if it is a member.
+func (r *Roster) Point(id []byte) (abstract.Scalar, bool) {
+	pos, ok := r.find(id)
+	if !ok {
+		return nil, false
+	}
+	return r.members[pos].X, true
+}
+
+func (r *Roster) find(id []byte) (int, bool) {
+	key, err := Point(r.suite, id).MarshalBinary()
+	if err != nil {
+		return 0, false
+	}
+	pos := sort.Search(len(r.keys), func(k int) bool { return bytes.Compare(r.keys[k], key) >= 0 })
+	if pos < len(r.keys) && bytes.Equal(r.keys[pos], key) && bytes.Equal(r.members[pos].ID, id) {
+		return pos, true
+	}
+	return 0, false
+}
+
+// Root returns the root hash of the roster's Merkle tree.
+func (r *Roster) Root() []byte {
+	root, _ := r.log.Root(uint64(len(r.members)))
+	return root
+}
+
+// UniquenessProof shows that a member's evaluation point occurs exactly
+// once in a roster: it contains the member's position and the leaves of the
+// member and of its neighbours in roster order, with their inclusion proofs.
+// Since the roster is sorted strictly by evaluation point, the neighbours
+// bound the point from both sides.
+type UniquenessProof struct {
+	Size   uint64     // Number of members
+	Pos    uint64     // Position of the member
+	Leaves [][]byte   // Leaves at Pos-1, Pos and Pos+1, nil if out of range
+	Paths  [][][]byte // Inclusion proofs of the leaves
+}
+
+// Prove returns the uniqueness proof of the identity.
+func (r *Roster) Prove(id []byte) (*UniquenessProof, error) {
+	pos, ok := r.find(id)
+	if !ok {
+		return nil, errorUnknown
+	}
+	size := uint64(len(r.members))
+	p := &UniquenessProof{Size: size, Pos: uint64(pos), Leaves: make([][]byte, 3), Paths: make([][][]byte, 3)}
+	for k := 0; k < 3; k++ {
+		q := pos + k - 1
+		if q < 0 || q >= len(r.members) {
+			continue
+		}
+		leaf, err := r.members[q].leaf()
+		if err != nil {
+			return nil, err
+		}
+		path, err := r.log.InclusionProof(uint64(q), size)
+		if err != nil {
+			return nil, err
+		}
+		p.Leaves[k], p.Paths[k] = leaf, path
+	}
+	return p, nil
+}
+
+// VerifyUniqueness checks the proof that the identity is a member of the
+// roster with the given root hash and that its evaluation point occurs only
+// once in it. It returns the evaluation point.
+func VerifyUniqueness(suite abstract.Suite, root, id []byte, p *UniquenessProof) (abstract.Scalar, error) {
+	if p.Pos >= p.Size || len(p.Leaves) != 3 || len(p.Paths) != 3 {
+		return nil, errorProof
+	}
+	x := Point(suite, id)
+	self, err := (&Member{id, x}).leaf()
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(p.Leaves[1], self) {
+		return nil, errorProof
+	}
+	n := suite.ScalarLen()
+	for k := 0; k < 3; k++ {
+		q := p.Pos + uint64(k) - 1
+		if (k == 0 && p.Pos == 0) || (k == 2 && p.Pos+1 == p.Size) {
+			if p.Leaves[k] != nil {
+				return nil, errorProof
+			}
+			continue
+		}
+		if len(p.Leaves[k]) < n {
+			return nil, errorProof
+		}
+		if err := translog.VerifyInclusion(suite, p.Leaves[k], q, p.Size, p.Paths[k], root); err != nil {
+			return nil, errorProof
+		}
+	}
+	if p.Leaves[0] != nil && bytes.Compare(p.Leaves[0][:n], self[:n]) >= 0 {
+		return nil, errorProof
+	}
+	if p.Leaves[2] != nil && bytes.Compare(self[:n], p.Leaves[2][:n]) >= 0 {
+		return nil, errorProof
+	}
+	return x, nil
+}
+
+// Eval returns the share p(x) of the secret sharing polynomial at the
+// evaluation point x.
+func Eval(p *share.PriPoly, x abstract.Scalar) abstract.Scalar {
+	coeffs := p.Coefficients()
+	v := x.Clone().Zero()
+	for k := len(coeffs) - 1; k >= 0; k-- {
+		v.Mul(v, x)
+		v.Add(v, coeffs[k])
+	}
+	return v
+}
+
+// EvalCommit returns the public share p(x)B of the public commitment
+// polynomial at the evaluation point x.
+func EvalCommit(g abstract.Group, p *share.PubPoly, x abstract.Scalar) abstract.Point {
+	_, commits := p.Info()
+	v := g.Point().Null()
+	for k := len(commits) - 1; k >= 0; k-- {
+		v.Mul(v, x)
+		v.Add(v, commits[k])
+	}
+	return v
+}
+
+// RecoverSecret reconstructs the secret from the first t of the shares vs at
+// the distinct evaluation points xs.
+func RecoverSecret(g abstract.Group, xs, vs []abstract.Scalar, t int) (abstract.Scalar, error) {
+	if len(xs) < t || len(vs) < t {
+		return nil, errorTooFew
+	}
+	ip, err := share.NewInterpolatorAt(g, xs[:t])
+	if err != nil {
+		return nil, err
+	}
+	s := g.Scalar().Zero()
+	for i := range xs[:t] {
+		s.Add(s, g.Scalar().Mul(ip.Coefficient(i), vs[i]))
+	}
+	return s, nil
+}
+
+// RecoverCommit reconstructs the secret commitment from the first t of the
+// public shares Vs at the distinct evaluation points xs.
+func RecoverCommit(g abstract.Group, xs []abstract.Scalar, Vs []abstract.Point, t int) (abstract.Point, error) {
+	if len(xs) < t || len(Vs) < t {
+		return nil, errorTooFew
+	}
+	ip, err := share.NewInterpolatorAt(g, xs[:t])
+	if err != nil {
+		return nil, err
+	}
+	S := g.Point().Null()
+	for i := range xs[:t] {
+		S.Add(S, g.Point().Mul(Vs[i], ip.Coefficient(i)))
+	}
+	return S, nil
+}
@@ -0,0 +1,91 @@
+package ident
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/dedis/crypto/abstract"
+	"github.com/dedis/crypto/edwards"
+	"github.com/dedis/crypto/random"
+	"github.com/dedis/crypto/share"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var suite = edwards.NewAES128SHA256Ed25519(false)
+
+func ids(names ...string) [][]byte {
+	out := make([][]byte, len(names))
+	for i, n := range names {
+		out[i] = []byte(n)
+	}
+	return out
+}
+
+func TestRoster(t *testing.T) {
+	r, err := NewRoster(suite, ids("alice", "bob", "carol", "dave", "eve"))
+	require.Nil(t, err)
+	assert.Equal(t, 5, r.Len())
+
+	// Evaluation points do not depend on the committee
+	r2, err := NewRoster(suite, ids("frank", "carol", "bob"))
+	require.Nil(t, err)
+	x1, ok := r.Point([]byte("carol"))
+	require.True(t, ok)
+	x2, ok := r2.Point([]byte("carol"))
+	require.True(t, ok)
+	assert.True(t, x1.Equal(x2))
+	_, ok = r2.Point([]byte("alice"))
+	assert.False(t, ok)
+
+	_, err = NewRoster(suite, ids("alice", "bob", "alice"))
+	assert.Equal(t, errorDuplicate, err)
+
+	for _, m := range r.Members() {
+		p, err := r.Prove(m.ID)
+		require.Nil(t, err)
+		x, err := VerifyUniqueness(suite, r.Root(), m.ID, p)
+		require.Nil(t, err)
+		assert.True(t, x.Equal(m.X))
+		_, err = VerifyUniqueness(suite, r2.Root(), m.ID, p)
+		assert.Equal(t, errorProof, err)
+	}
+	p, err := r.Prove([]byte("bob"))
+	require.Nil(t, err)
+	_, err = VerifyUniqueness(suite, r.Root(), []byte("carol"), p)
+	assert.Equal(t, errorProof, err)
+	p.Leaves[0], p.Leaves[2] = p.Leaves[2], p.Leaves[0]
+	_, err = VerifyUniqueness(suite, r.Root(), []byte("bob"), p)
+	assert.Equal(t, errorProof, err)
+	_, err = r.Prove([]byte("mallory"))
+	assert.Equal(t, errorUnknown, err)
+}
+
+func TestIdentityShares(t *testing.T) {
+	n, th := 6, 4
+	var names []string
+	for i := 0; i < n; i++ {
+		names = append(names, fmt.Sprintf("node-%d", i))
+	}
+	r, err := NewRoster(suite, ids(names...))
+	require.Nil(t, err)
+	priPoly := share.NewPriPoly(suite, th, nil, random.Stream)
+	pubPoly := priPoly.Commit(nil)
+
+	var xs, vs []abstract.Scalar
+	var Vs []abstract.Point
+	for _, m := range r.Members()[1:] {
+		v := Eval(priPoly, m.X)
+		V := EvalCommit(suite, pubPoly, m.X)
+		assert.True(t, V.Equal(suite.Point().Mul(nil, v)))
+		xs, vs, Vs = append(xs, m.X), append(vs, v), append(Vs, V)
+	}
+	s, err := RecoverSecret(suite, xs, vs, th)
+	require.Nil(t, err)
+	assert.True(t, s.Equal(priPoly.Secret()))
+	S, err := RecoverCommit(suite, xs, Vs, th)
+	require.Nil(t, err)
+	assert.True(t, S.Equal(pubPoly.Commit()))
+	_, err = RecoverSecret(suite, xs[:th-1], vs, th)
+	assert.Equal(t, errorTooFew, err)
+}
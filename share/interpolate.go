@@ -0,0 +1,173 @@
+package share
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/dedis/crypto/abstract"
+)
+
+// ErrIndex is returned for share indices that are out of range or repeated.
+var ErrIndex = errors.New("invalid share index")
+
+// Interpolator holds the Lagrange coefficients at 0 of a fixed set of share
+// indices. RecoverSecret and RecoverCommit compute these coefficients, which
+// cost a quadratic number of multiplications and an inversion per share, on
+// every call; an Interpolator computes them once and then recovers from any
+// shares with the same indices at the cost of a linear combination, for
+// instance to threshold-decrypt many ciphertexts with the same trustees.
+type Interpolator struct {
+	g       abstract.Group
+	indices []int
+	x       map[int]abstract.Scalar // Evaluation points
+	coeffs  map[int]abstract.Scalar
+}
+
+// NewInterpolator precomputes the Lagrange coefficients of the distinct
+// share indices, each in [0, n).
+func NewInterpolator(g abstract.Group, indices []int, n int) (*Interpolator, error) {
+	x := make(map[int]abstract.Scalar)
+	for _, i := range indices {
+		if i < 0 || n <= i {
+			return nil, fmt.Errorf("share: interpolating index %d out of %d: %w", i, n, ErrIndex)
+		}
+		if _, ok := x[i]; ok {
+			return nil, fmt.Errorf("share: interpolating index %d twice: %w", i, ErrIndex)
+		}
+		x[i] = g.Scalar().SetInt64(1 + int64(i))
+	}
+	return newInterpolator(g, append([]int{}, indices...), x), nil
+}
+
+// NewInterpolatorAt is like NewInterpolator for shares at arbitrary
+// evaluation points rather than at i+1: the share with index k is the
+// evaluation at xs[k]. The evaluation points must be distinct.
+func NewInterpolatorAt(g abstract.Group, xs []abstract.Scalar) (*Interpolator, error) {
+	indices := make([]int, len(xs))
+	x := make(map[int]abstract.Scalar)
+	for k, xk := range xs {
+		for j := 0; j < k; j++ {
+			if xs[j].Equal(xk) {
+				return nil, fmt.Errorf("share: interpolating evaluation points %d and %d at the same point: %w", j, k, ErrIndex)
+			}
+		}
+		indices[k] = k
+		x[k] = xk
+	}
+	return newInterpolator(g, indices, x), nil
+}
+
+func newInterpolator(g abstract.Group, indices []int, x map[int]abstract.Scalar) *Interpolator {
+	coeffs := make(map[int]abstract.Scalar)
+	den := g.Scalar()
+	tmp := g.Scalar()
+	for i, xi := range x {
+		num := g.Scalar().One()
+		den.One()
+		for j, xj := range x {
+			if i == j {
+				continue
+			}
+			num.Mul(num, xj)
+			den.Mul(den, tmp.Sub(xj, xi))
+		}
+		coeffs[i] = num.Div(num, den)
+	}
+	return &Interpolator{g, indices, x, coeffs}
+}
+
+// Indices returns the share indices of the interpolator.
+func (ip *Interpolator) Indices() []int {
+	return ip.indices
+}
+
+// Coefficient returns the Lagrange coefficient of share index i, or nil if i
+// is not one of the interpolator's indices.
+func (ip *Interpolator) Coefficient(i int) abstract.Scalar {
+	return ip.coeffs[i]
+}
+
+// Basis returns the coefficients, constant term first, of the Lagrange basis
+// polynomial of share index i, which is one at the evaluation point of i and
+// zero at those of the other indices, or nil if i is not one of the
+// interpolator's indices. Its constant term is Coefficient(i). Summing the
+// basis polynomials weighted by the shares yields the whole shared
+// polynomial rather than only p(0).
+func (ip *Interpolator) Basis(i int) []abstract.Scalar {
+	xi, ok := ip.x[i]
+	if !ok {
+		return nil
+	}
+	basis := []abstract.Scalar{ip.g.Scalar().One()}
+	den := ip.g.Scalar().One()
+	tmp := ip.g.Scalar()
+	for _, j := range ip.indices {
+		if j == i {
+			continue
+		}
+		// Multiply by (x - x_j)
+		xj := ip.x[j]
+		next := make([]abstract.Scalar, len(basis)+1)
+		for k := range next {
+			next[k] = ip.g.Scalar().Zero()
+		}
+		for k, c := range basis {
+			next[k+1].Add(next[k+1], c)
+			next[k].Sub(next[k], tmp.Mul(c, xj))
+		}
+		basis = next
+		den.Mul(den, tmp.Sub(xi, xj))
+	}
+	den.Inv(den)
+	for _, c := range basis {
+		c.Mul(c, den)
+	}
+	return basis
+}
+
+// Recover reconstructs the shared secret p(0) from the private shares with
+// the interpolator's indices. Shares of other indices are ignored; a share
+// for every index is required.
+func (ip *Interpolator) Recover(shares []*PriShare) (abstract.Scalar, error) {
+	acc := ip.g.Scalar().Zero()
+	tmp := ip.g.Scalar()
+	seen := make(map[int]bool)
+	for _, s := range shares {
+		if s == nil || s.V == nil || seen[s.I] {
+			continue
+		}
+		c, ok := ip.coeffs[s.I]
+		if !ok {
+			continue
+		}
+		seen[s.I] = true
+		acc.Add(acc, tmp.Mul(c, s.V))
+	}
+	if len(seen) < len(ip.coeffs) {
+		return nil, fmt.Errorf("share: interpolating shared secret from %d of %d private shares: %w", len(seen), len(ip.coeffs), ErrTooFewShares)
+	}
+	return acc, nil
+}
+
+// RecoverCommit reconstructs the secret commitment p(0) from the public
+// shares with the interpolator's indices, like Recover.
+func (ip *Interpolator) RecoverCommit(shares []*PubShare) (abstract.Point, error) {
+	Acc := ip.g.Point().Null()
+	Tmp := ip.g.Point()
+	seen := make(map[int]bool)
+	for _, s := range shares {
+		if s == nil || s.V == nil || seen[s.I] {
+			continue
+		}
+		c, ok := ip.coeffs[s.I]
+		if !ok {
+			continue
+		}
+		seen[s.I] = true
+		Acc.Add(Acc, Tmp.Mul(s.V, c))
+	}
+	if len(seen) < len(ip.coeffs) {
+		return nil, fmt.Errorf("share: interpolating secret commitment from %d of %d public shares: %w", len(seen), len(ip.coeffs), ErrTooFewShares)
+	}
+	return Acc, nil
+}
@@ -0,0 +1,141 @@
+//go:build pbc
+// +build pbc
+
+// Package kzg re-commits Feldman commitments, i.e., the public commitment
+// polynomials of package share, as constant-size KZG polynomial commitments.
+// A Feldman commitment to a polynomial f of threshold t consists of the t
+// points a_kG1, whereas its KZG commitment is the single point
+//
+//	C = f(tau)G1 = sum a_k tau^k G1
+//
+// for the secret tau of a structured reference string. The dealer, who knows
+// the coefficients, computes C with Commit and the evaluation proofs of the
+// public shares with Open. Anybody holding the Feldman commitment checks
+// once with VerifyEquivalence that C commits to the same polynomial, using
+//
+//	e(C, G2) == prod e(a_kG1, tau^k G2),
+//
+// and can then discard the Feldman commitment and check public shares
+// f(i)G1 against C alone with VerifyEval. Polynomials and their commitments
+// live in G1 of the pairing. The package requires the pbc build tag, see
+// package pbc.
+package kzg
+
+import (
+	"errors"
+
+	"github.com/dedis/crypto/abstract"
+	"github.com/dedis/crypto/pbc"
+	"github.com/dedis/crypto/share"
+)
+
+// Some error definitions
+var errorDegree = errors.New("polynomial exceeds reference string")
+var errorEquivalence = errors.New("commitment not equivalent to Feldman commitment")
+var errorEval = errors.New("invalid evaluation proof")
+var errorBase = errors.New("commitments not to the standard base point")
+
+// SRS is a structured reference string for polynomials of threshold up to
+// len(G1): the powers tau^k G1 and tau^k G2 for k < len(G1).
+type SRS struct {
+	G1 []abstract.Point
+	G2 []abstract.Point
+}
+
+// NewSRS creates the reference string for polynomials of threshold up to t
+// from the secret tau. Whoever knows tau can forge commitments, so tau must
+// be erased after the setup, or the setup must be run as a multi-party
+// ceremony instead.
+func NewSRS(suite *pbc.Pairing, t int, tau abstract.Scalar) *SRS {
+	srs := &SRS{make([]abstract.Point, t), make([]abstract.Point, t)}
+	pow := suite.G1().Scalar().One()
+	for k := 0; k < t; k++ {
+		srs.G1[k] = suite.G1().Point().Mul(nil, pow)
+		srs.G2[k] = suite.G2().Point().Mul(nil, pow)
+		pow.Mul(pow, tau)
+	}
+	return srs
+}
+
+// commit returns sum coeffs[k] tau^k G1.
+func commit(suite *pbc.Pairing, srs *SRS, coeffs []abstract.Scalar) (abstract.Point, error) {
+	if len(coeffs) > len(srs.G1) {
+		return nil, errorDegree
+	}
+	C := suite.G1().Point().Null()
+	for k, a := range coeffs {
+		C.Add(C, suite.G1().Point().Mul(srs.G1[k], a))
+	}
+	return C, nil
+}
+
+// Commit returns the KZG commitment to the secret sharing polynomial, whose
+// Feldman commitment is priPoly.Commit(nil) over G1.
+func Commit(suite *pbc.Pairing, srs *SRS, priPoly *share.PriPoly) (abstract.Point, error) {
+	return commit(suite, srs, priPoly.Coefficients())
+}
+
+// Open returns the public share f(i)G1 with index i together with the proof
+// that it is the evaluation of the committed polynomial, i.e., the commitment
+// to the quotient q(x) = (f(x) - f(x_i)) / (x - x_i) with x_i = i+1.
+func Open(suite *pbc.Pairing, srs *SRS, priPoly *share.PriPoly, i int) (*share.PubShare, abstract.Point, error) {
+	coeffs := priPoly.Coefficients()
+	g := suite.G1()
+	xi := g.Scalar().SetInt64(1 + int64(i))
+	// Synthetic division by (x - x_i); the remainder is f(x_i)
+	q := make([]abstract.Scalar, len(coeffs)-1)
+	rem := g.Scalar().Zero()
+	for k := len(coeffs) - 1; k >= 0; k-- {
+		rem.Mul(rem, xi)
+		rem.Add(rem, coeffs[k])
+		if k > 0 {
+			q[k-1] = g.Scalar().Set(rem)
+		}
+	}
+	proof, err := commit(suite, srs, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &share.PubShare{I: i, V: g.Point().Mul(nil, rem)}, proof, nil
+}
+
+// VerifyEval checks that the public share f(i)G1 is the evaluation of the
+// polynomial committed to by C at index i, given the evaluation proof, i.e.,
+// e(C - f(i)G1, G2) == e(proof, (tau - x_i)G2).
+func VerifyEval(suite *pbc.Pairing, srs *SRS, C abstract.Point, pubShare *share.PubShare, proof abstract.Point) error {
+	if len(srs.G2) < 2 {
+		return errorDegree
+	}
+	xi := suite.G2().Scalar().SetInt64(1 + int64(pubShare.I))
+	T := suite.G2().Point().Sub(srs.G2[1], suite.G2().Point().Mul(nil, xi))
+	lhs := pair(suite, suite.G1().Point().Sub(C, pubShare.V), srs.G2[0])
+	if !lhs.Equal(pair(suite, proof, T)) {
+		return errorEval
+	}
+	return nil
+}
+
+// VerifyEquivalence checks that C commits to the same polynomial as the
+// Feldman commitment pubPoly over G1.
+func VerifyEquivalence(suite *pbc.Pairing, srs *SRS, C abstract.Point, pubPoly *share.PubPoly) error {
+	b, commits := pubPoly.Info()
+	if b != nil && !b.Equal(suite.G1().Point().Base()) {
+		return errorBase
+	}
+	if len(commits) > len(srs.G2) {
+		return errorDegree
+	}
+	rhs := suite.GT().Point().Null()
+	for k, A := range commits {
+		rhs.Add(rhs, pair(suite, A, srs.G2[k]))
+	}
+	if !pair(suite, C, srs.G2[0]).Equal(rhs) {
+		return errorEquivalence
+	}
+	return nil
+}
+
+// pair returns e(p1, p2).
+func pair(suite *pbc.Pairing, p1, p2 abstract.Point) abstract.Point {
+	return suite.GT().PairingPoint().Pairing(p1, p2)
+}
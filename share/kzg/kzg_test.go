@@ -0,0 +1,42 @@
+//go:build pbc
+// +build pbc
+
+package kzg
+
+import (
+	"testing"
+
+	"github.com/dedis/crypto/pbc"
+	"github.com/dedis/crypto/random"
+	"github.com/dedis/crypto/share"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKZG(t *testing.T) {
+	suite := new(pbc.Pairing).InitD224()
+	n, th := 6, 4
+	srs := NewSRS(suite, th, suite.G1().Scalar().Pick(random.Stream))
+
+	priPoly := share.NewPriPoly(suite.G1(), th, nil, random.Stream)
+	pubPoly := priPoly.Commit(nil)
+	C, err := Commit(suite, srs, priPoly)
+	require.Nil(t, err)
+	require.Nil(t, VerifyEquivalence(suite, srs, C, pubPoly))
+
+	other := share.NewPriPoly(suite.G1(), th, nil, random.Stream).Commit(nil)
+	assert.Equal(t, errorEquivalence, VerifyEquivalence(suite, srs, C, other))
+
+	for i := 0; i < n; i++ {
+		ps, proof, err := Open(suite, srs, priPoly, i)
+		require.Nil(t, err)
+		assert.True(t, ps.V.Equal(pubPoly.Eval(i).V))
+		assert.Nil(t, VerifyEval(suite, srs, C, ps, proof))
+		ps.I = (i + 1) % n
+		assert.Equal(t, errorEval, VerifyEval(suite, srs, C, ps, proof))
+	}
+
+	big := share.NewPriPoly(suite.G1(), th+1, nil, random.Stream)
+	_, err = Commit(suite, srs, big)
+	assert.Equal(t, errorDegree, err)
+}
@@ -0,0 +1,102 @@
+package share
+
+import (
+	"crypto/cipher"
+	"fmt"
+
+	"github.com/dedis/crypto/abstract"
+)
+
+// Packed secret sharing embeds k secrets into one polynomial, at the
+// evaluation points 0, -1, ..., -(k-1), which are distinct from the
+// evaluation points i+1 of the shares. The polynomial has t+k coefficients,
+// so any t shares reveal nothing about the secrets while any t+k shares
+// recover all of them. Each participant receives a single share for all k
+// secrets instead of k shares, at the price of a larger reconstruction
+// threshold:
+//
+//	poly := NewPackedPriPoly(g, t, secrets, rand)
+//	shares := poly.Shares(n)
+//	secrets, err := RecoverPacked(g, shares, t, k, n)
+
+// packedPoint returns the evaluation point -j of the j-th packed secret.
+func packedPoint(g abstract.Group, j int) abstract.Scalar {
+	return g.Scalar().SetInt64(-int64(j))
+}
+
+// NewPackedPriPoly creates a secret sharing polynomial for the group g that
+// packs the secrets, with privacy threshold t: the polynomial is
+// L(x) + Z(x)r(x), where L interpolates the secrets, Z vanishes at their
+// evaluation points and r is a random polynomial with t coefficients.
+func NewPackedPriPoly(g abstract.Group, t int, secrets []abstract.Scalar, rand cipher.Stream) *PriPoly {
+	k := len(secrets)
+	// Z(x) = x(x+1)...(x+k-1)
+	z := &PriPoly{g, []abstract.Scalar{g.Scalar().One()}}
+	for j := 0; j < k; j++ {
+		root := &PriPoly{g, []abstract.Scalar{g.Scalar().Neg(packedPoint(g, j)), g.Scalar().One()}}
+		z, _ = z.Mul(root)
+	}
+	r := NewPriPoly(g, t, nil, rand)
+	zr, _ := z.Mul(r)
+
+	xs := make([]abstract.Scalar, k)
+	for j := range xs {
+		xs[j] = packedPoint(g, j)
+	}
+	// The evaluation points are distinct
+	ip, _ := NewInterpolatorAt(g, xs)
+	tmp := g.Scalar()
+	for j, s := range secrets {
+		for m, c := range ip.Basis(j) {
+			zr.coeffs[m].Add(zr.coeffs[m], tmp.Mul(c, s))
+		}
+	}
+	return zr
+}
+
+// RecoverPacked reconstructs the k secrets packed with privacy threshold t
+// from at least t+k valid shares with indices in [0, n).
+func RecoverPacked(g abstract.Group, shares []*PriShare, t, k, n int) ([]abstract.Scalar, error) {
+	var good []*PriShare
+	seen := make(map[int]bool)
+	for _, s := range shares {
+		if s == nil || s.V == nil || s.I < 0 || n <= s.I || seen[s.I] {
+			continue
+		}
+		seen[s.I] = true
+		good = append(good, s)
+	}
+	if len(good) < t+k {
+		return nil, fmt.Errorf("share: reconstructing %d packed secrets from %d of %d required private shares: %w", k, len(good), t+k, ErrTooFewShares)
+	}
+	good = good[:t+k]
+
+	indices := make([]int, len(good))
+	for m, s := range good {
+		indices[m] = s.I
+	}
+	ip, err := NewInterpolator(g, indices, n)
+	if err != nil {
+		return nil, err
+	}
+	coeffs := make([]abstract.Scalar, len(good))
+	for m := range coeffs {
+		coeffs[m] = g.Scalar().Zero()
+	}
+	tmp := g.Scalar()
+	for _, s := range good {
+		for m, c := range ip.Basis(s.I) {
+			coeffs[m].Add(coeffs[m], tmp.Mul(c, s.V))
+		}
+	}
+	secrets := make([]abstract.Scalar, k)
+	for j := range secrets {
+		x := packedPoint(g, j)
+		secrets[j] = g.Scalar().Zero()
+		for m := len(coeffs) - 1; m >= 0; m-- {
+			secrets[j].Mul(secrets[j], x)
+			secrets[j].Add(secrets[j], coeffs[m])
+		}
+	}
+	return secrets, nil
+}
@@ -0,0 +1,212 @@
+// Package pedersen implements Pedersen's verifiable secret sharing, whose
+// commitments C_k = a_kG + b_kH to the coefficients of the sharing polynomial
+// f and of a random blinding polynomial r hide the secret f(0)
+// information-theoretically, whereas the commitments of a share.PubPoly
+// reveal f(0)G. The base point H must be chosen such that nobody knows its
+// discrete logarithm with respect to G, e.g., by hashing to a point. The
+// protocol parallels the PubPoly-based flow of package share:
+//
+//	dealer:  d := NewDealer(g, H, t, secret, rand)
+//	         broadcast d.Commits(), send d.Share(i) privately to trustee i
+//	trustee: if d.Commits().Verify(s) fails, broadcast &Complaint{I: i}
+//	dealer:  broadcast d.Justify(c) for every complaint c
+//	anyone:  commits.Qualify(complaints, justifications) decides on the dealer
+//	         RecoverSecret(g, commits, shares, t, n) reconstructs the secret
+package pedersen
+
+import (
+	"crypto/cipher"
+	"errors"
+
+	"github.com/dedis/crypto/abstract"
+	"github.com/dedis/crypto/share"
+)
+
+// Some error definitions
+var errorShare = errors.New("share does not match commitments")
+var errorIndex = errors.New("share index out of range")
+var errorJustification = errors.New("complaint not justified")
+var errorComplaints = errors.New("too many complaints against dealer")
+var errorOpening = errors.New("reconstruction does not open commitment")
+
+// Share is a trustee's share f(i) of the secret together with the share r(i)
+// of the blinding polynomial.
+type Share struct {
+	I int             // Index of the share
+	V abstract.Scalar // Share of the secret, f(i)
+	R abstract.Scalar // Share of the blinding polynomial, r(i)
+}
+
+// Commits are the Pedersen commitments C_k = a_kG + b_kH to the coefficients
+// of the sharing and blinding polynomials.
+type Commits struct {
+	g       abstract.Group
+	h       abstract.Point
+	commits []abstract.Point
+}
+
+// NewCommits creates the commitments over g with respect to the second base
+// point H from their coefficient commitments, e.g., as received from a dealer.
+func NewCommits(g abstract.Group, H abstract.Point, commits []abstract.Point) *Commits {
+	return &Commits{g, H, commits}
+}
+
+// Info returns the second base point and the coefficient commitments.
+func (c *Commits) Info() (abstract.Point, []abstract.Point) {
+	return c.h, c.commits
+}
+
+// Threshold returns the secret sharing threshold.
+func (c *Commits) Threshold() int {
+	return len(c.commits)
+}
+
+// Eval returns the commitment f(i)G + r(i)H to the share with index i.
+func (c *Commits) Eval(i int) abstract.Point {
+	return share.NewPubPoly(c.g, nil, c.commits).Eval(i).V
+}
+
+// Verify checks the share against the commitments.
+func (c *Commits) Verify(s *Share) error {
+	if s.I < 0 {
+		return errorIndex
+	}
+	P := c.g.Point().Add(c.g.Point().Mul(nil, s.V), c.g.Point().Mul(c.h, s.R))
+	if !P.Equal(c.Eval(s.I)) {
+		return errorShare
+	}
+	return nil
+}
+
+// Dealer holds the sharing and blinding polynomials of a Pedersen VSS.
+type Dealer struct {
+	f, r    *share.PriPoly
+	commits *Commits
+}
+
+// NewDealer shares the secret with threshold t over g, blinding the
+// commitments with the base point H. If secret is nil, a random secret is
+// picked from rand.
+func NewDealer(g abstract.Group, H abstract.Point, t int, secret abstract.Scalar, rand cipher.Stream) *Dealer {
+	f := share.NewPriPoly(g, t, secret, rand)
+	r := share.NewPriPoly(g, t, nil, rand)
+	sum, _ := f.Commit(nil).Add(r.Commit(H))
+	_, commits := sum.Info()
+	return &Dealer{f, r, &Commits{g, H, commits}}
+}
+
+// Commits returns the dealer's public commitments.
+func (d *Dealer) Commits() *Commits {
+	return d.commits
+}
+
+// FeldmanCommits returns the Feldman commitments a_kG to the coefficients of
+// the sharing polynomial, which reveal the secret's public key f(0)G, e.g.,
+// for the extraction phase of a distributed key generation.
+func (d *Dealer) FeldmanCommits() *share.PubPoly {
+	return d.f.Commit(nil)
+}
+
+// Share returns the share of the trustee with index i.
+func (d *Dealer) Share(i int) *Share {
+	return &Share{i, d.f.Eval(i).V, d.r.Eval(i).V}
+}
+
+// Shares returns the shares of n trustees.
+func (d *Dealer) Shares(n int) []*Share {
+	shares := make([]*Share, n)
+	for i := range shares {
+		shares[i] = d.Share(i)
+	}
+	return shares
+}
+
+// Complaint is a trustee's public accusation that the share it received does
+// not match the dealer's commitments, or that it received none. Complaints
+// must be authenticated by the broadcast channel they are sent over.
+type Complaint struct {
+	I int // Index of the complaining trustee
+}
+
+// Justification is the dealer's answer to a complaint: it reveals the
+// complaining trustee's share publicly.
+type Justification struct {
+	Share *Share
+}
+
+// Justify answers the complaint by revealing the complainer's share.
+func (d *Dealer) Justify(c *Complaint) *Justification {
+	return &Justification{d.Share(c.I)}
+}
+
+// VerifyJustification checks that the justification answers the complaint
+// with a share matching the commitments. If so, the complaining trustee
+// adopts the revealed share.
+func (c *Commits) VerifyJustification(cp *Complaint, j *Justification) error {
+	if j == nil || j.Share == nil || j.Share.I != cp.I {
+		return errorJustification
+	}
+	if err := c.Verify(j.Share); err != nil {
+		return errorJustification
+	}
+	return nil
+}
+
+// Qualify decides whether the dealer is qualified given the complaints of
+// distinct trustees and the dealer's justifications, aligned with them by
+// position or missing. The dealer is disqualified if a threshold of trustees
+// complained, since then the honest trustees could not reconstruct the secret
+// without the revealed shares, or if it failed to justify any complaint.
+func (c *Commits) Qualify(complaints []*Complaint, justifications []*Justification) error {
+	if len(complaints) >= c.Threshold() {
+		return errorComplaints
+	}
+	for k, cp := range complaints {
+		if k >= len(justifications) {
+			return errorJustification
+		}
+		if err := c.VerifyJustification(cp, justifications[k]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RecoverSecret verifies the shares against the commitments and reconstructs
+// the secret from a threshold t of the valid ones out of n. It also
+// reconstructs the blinding value and checks that both open the commitment
+// C_0 to the secret.
+func RecoverSecret(g abstract.Group, c *Commits, shares []*Share, t, n int) (abstract.Scalar, error) {
+	var fs, rs []*share.PriShare
+	seen := make(map[int]bool)
+	for _, s := range shares {
+		if s == nil || s.I >= n || seen[s.I] || c.Verify(s) != nil {
+			continue
+		}
+		seen[s.I] = true
+		fs = append(fs, &share.PriShare{I: s.I, V: s.V})
+		rs = append(rs, &share.PriShare{I: s.I, V: s.R})
+	}
+	secret, err := share.RecoverSecret(g, fs, t, n)
+	if err != nil {
+		return nil, err
+	}
+	blind, err := share.RecoverSecret(g, rs, t, n)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.Open(secret, blind); err != nil {
+		return nil, err
+	}
+	return secret, nil
+}
+
+// Open checks that the secret and the blinding value open the commitment
+// C_0 = secret*G + blind*H.
+func (c *Commits) Open(secret, blind abstract.Scalar) error {
+	P := c.g.Point().Add(c.g.Point().Mul(nil, secret), c.g.Point().Mul(c.h, blind))
+	if !P.Equal(c.commits[0]) {
+		return errorOpening
+	}
+	return nil
+}
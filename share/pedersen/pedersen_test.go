@@ -0,0 +1,72 @@
+package pedersen
+
+import (
+	"testing"
+
+	"github.com/dedis/crypto/edwards"
+	"github.com/dedis/crypto/random"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var suite = edwards.NewAES128SHA256Ed25519(false)
+
+func TestPedersen(t *testing.T) {
+	n, th := 7, 4
+	H, _ := suite.Point().Pick(nil, suite.Cipher([]byte("H")))
+	secret := suite.Scalar().Pick(random.Stream)
+
+	d := NewDealer(suite, H, th, secret, random.Stream)
+	commits := d.Commits()
+	assert.Equal(t, th, commits.Threshold())
+	shares := d.Shares(n)
+	for _, s := range shares {
+		require.Nil(t, commits.Verify(s))
+	}
+
+	// The commitment to the secret is not sG
+	_, c := commits.Info()
+	assert.False(t, c[0].Equal(suite.Point().Mul(nil, secret)))
+
+	recovered, err := RecoverSecret(suite, commits, shares, th, n)
+	require.Nil(t, err)
+	assert.True(t, recovered.Equal(secret))
+
+	// Bad shares are skipped
+	shares[0].V = suite.Scalar().One()
+	shares[1].R = suite.Scalar().One()
+	assert.Equal(t, errorShare, commits.Verify(shares[0]))
+	assert.Equal(t, errorShare, commits.Verify(shares[1]))
+	recovered, err = RecoverSecret(suite, commits, shares, th, n)
+	require.Nil(t, err)
+	assert.True(t, recovered.Equal(secret))
+	_, err = RecoverSecret(suite, commits, shares[:th+1], th, n)
+	assert.NotNil(t, err)
+}
+
+func TestPedersenComplaints(t *testing.T) {
+	th := 4
+	H, _ := suite.Point().Pick(nil, suite.Cipher([]byte("H")))
+	d := NewDealer(suite, H, th, nil, random.Stream)
+	commits := d.Commits()
+
+	complaints := []*Complaint{{I: 2}, {I: 5}}
+	justs := []*Justification{d.Justify(complaints[0]), d.Justify(complaints[1])}
+	require.Nil(t, commits.Qualify(complaints, justs))
+	assert.True(t, justs[0].Share.V.Equal(d.Share(2).V))
+
+	// Missing, mismatched or wrong justifications disqualify the dealer
+	assert.Equal(t, errorJustification, commits.Qualify(complaints, justs[:1]))
+	assert.Equal(t, errorJustification, commits.Qualify(complaints, []*Justification{justs[1], justs[0]}))
+	bad := &Justification{&Share{2, suite.Scalar().One(), suite.Scalar().One()}}
+	assert.Equal(t, errorJustification, commits.Qualify(complaints, []*Justification{bad, justs[1]}))
+
+	// A threshold of complaints disqualifies the dealer
+	complaints = nil
+	justs = nil
+	for i := 0; i < th; i++ {
+		complaints = append(complaints, &Complaint{I: i})
+		justs = append(justs, d.Justify(complaints[i]))
+	}
+	assert.Equal(t, errorComplaints, commits.Qualify(complaints, justs))
+}
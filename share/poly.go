@@ -0,0 +1,110 @@
+package share
+
+import (
+	"fmt"
+	"math/bits"
+
+	"github.com/dedis/crypto/abstract"
+)
+
+// Mul computes the product of the polynomials p and q and returns it as a new
+// polynomial, whose threshold is p.Threshold()+q.Threshold()-1. Its shares
+// are the products of the shares of p and q, as used to multiply shared
+// secrets, for instance with multiplication triples.
+func (p *PriPoly) Mul(q *PriPoly) (*PriPoly, error) {
+	if p.g.String() != q.g.String() {
+		return nil, fmt.Errorf("share: multiplying private polynomials over %s and %s: %w", p.g.String(), q.g.String(), ErrGroups)
+	}
+	coeffs := make([]abstract.Scalar, p.Threshold()+q.Threshold()-1)
+	for k := range coeffs {
+		coeffs[k] = p.g.Scalar().Zero()
+	}
+	tmp := p.g.Scalar()
+	for i, a := range p.coeffs {
+		for j, b := range q.coeffs {
+			coeffs[i+j].Add(coeffs[i+j], tmp.Mul(a, b))
+		}
+	}
+	return &PriPoly{p.g, coeffs}, nil
+}
+
+// EvalBatch computes the private shares p(i) of the given indices with
+// Horner's rule, sharing the scratch space of all evaluations.
+func (p *PriPoly) EvalBatch(indices []int) []*PriShare {
+	shares := make([]*PriShare, len(indices))
+	xi := p.g.Scalar()
+	for k, i := range indices {
+		xi.SetInt64(1 + int64(i))
+		v := p.g.Scalar().Zero()
+		for j := p.Threshold() - 1; j >= 0; j-- {
+			v.Mul(v, xi)
+			v.Add(v, p.coeffs[j])
+		}
+		shares[k] = &PriShare{i, v}
+	}
+	return shares
+}
+
+// Mul computes the commitments to the product of the polynomial committed to
+// by p and the public polynomial q, i.e., p.Mul(q) committed with the base
+// point of p. Commitments to the product of two secret polynomials cannot be
+// computed without the discrete logarithms of the commitments.
+func (p *PubPoly) Mul(q *PriPoly) (*PubPoly, error) {
+	if p.g.String() != q.g.String() {
+		return nil, fmt.Errorf("share: multiplying public polynomial over %s with private polynomial over %s: %w", p.g.String(), q.g.String(), ErrGroups)
+	}
+	commits := make([]abstract.Point, p.Threshold()+q.Threshold()-1)
+	for k := range commits {
+		commits[k] = p.g.Point().Null()
+	}
+	tmp := p.g.Point()
+	for i, C := range p.commits {
+		for j, b := range q.coeffs {
+			commits[i+j].Add(commits[i+j], tmp.Mul(C, b))
+		}
+	}
+	return &PubPoly{p.g, p.b, commits}, nil
+}
+
+// EvalBatch computes the public shares p(i) of the given indices with
+// Horner's rule. Since the evaluation points x = i+1 are small integers, the
+// multiplications by x are done by doubling and adding with the bits of x,
+// which is much cheaper than a general scalar multiplication. Negative
+// indices, whose evaluation points are not small positive integers, are
+// evaluated like by Eval.
+func (p *PubPoly) EvalBatch(indices []int) []*PubShare {
+	shares := make([]*PubShare, len(indices))
+	tmp := p.g.Point()
+	dbl := p.g.Point()
+	for k, i := range indices {
+		if i < 0 {
+			shares[k] = p.Eval(i)
+			continue
+		}
+		x := uint64(1 + int64(i))
+		v := p.g.Point().Null()
+		for j := p.Threshold() - 1; j >= 0; j-- {
+			// v = x*v + C_j
+			tmp.Set(v)
+			v.Null()
+			for b := bits.Len64(x) - 1; b >= 0; b-- {
+				v.Set(dbl.Add(v, v))
+				if x>>uint(b)&1 == 1 {
+					v.Add(v, tmp)
+				}
+			}
+			v.Add(v, p.commits[j])
+		}
+		shares[k] = &PubShare{i, v}
+	}
+	return shares
+}
+
+// seq returns the indices 0,...,n-1.
+func seq(n int) []int {
+	indices := make([]int, n)
+	for i := range indices {
+		indices[i] = i
+	}
+	return indices
+}
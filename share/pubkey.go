@@ -0,0 +1,97 @@
+package share
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/dedis/crypto/abstract"
+)
+
+// AggregatePubPoly sums the public commitment polynomials of the qualified
+// dealers of a distributed key generation into the collective polynomial. Its
+// constant term Commit() is the collective public key and its evaluation at
+// index i is the verification key of participant i, that is the public key
+// corresponding to the participant's share of the collective private key.
+func AggregatePubPoly(polys []*PubPoly) (*PubPoly, error) {
+	if len(polys) == 0 {
+		return nil, fmt.Errorf("share: aggregating no public polynomials: %w", ErrCoeffs)
+	}
+	acc := polys[0]
+	for _, q := range polys[1:] {
+		var err error
+		if acc, err = acc.Add(q); err != nil {
+			return nil, err
+		}
+	}
+	return acc, nil
+}
+
+// VerificationKeys returns the verification keys p(1),...,p(n) of n
+// participants, i.e., the values of the public shares of Shares(n).
+func (p *PubPoly) VerificationKeys(n int) []abstract.Point {
+	keys := make([]abstract.Point, n)
+	for i := range keys {
+		keys[i] = p.Eval(i).V
+	}
+	return keys
+}
+
+// MarshalBinary encodes the polynomial as the number of coefficients, a flag
+// for a non-standard base point followed by the base point, and the
+// commitments to the coefficients. The group is not encoded.
+func (p *PubPoly) MarshalBinary() ([]byte, error) {
+	var b bytes.Buffer
+	binary.Write(&b, binary.BigEndian, uint32(p.Threshold()))
+	if p.b == nil {
+		b.WriteByte(0)
+	} else {
+		b.WriteByte(1)
+		if _, err := p.b.MarshalTo(&b); err != nil {
+			return nil, err
+		}
+	}
+	for _, c := range p.commits {
+		if _, err := c.MarshalTo(&b); err != nil {
+			return nil, err
+		}
+	}
+	return b.Bytes(), nil
+}
+
+// UnmarshalPubPoly decodes a polynomial over the group g encoded with
+// MarshalBinary, so that verifiers can re-derive the collective public key and
+// the verification keys from a stored polynomial.
+func UnmarshalPubPoly(g abstract.Group, buf []byte) (*PubPoly, error) {
+	r := bytes.NewReader(buf)
+	var t uint32
+	if err := binary.Read(r, binary.BigEndian, &t); err != nil {
+		return nil, fmt.Errorf("share: decoding threshold: %w", ErrEncoding)
+	}
+	pointSize := g.PointLen()
+	flag, err := r.ReadByte()
+	if err != nil || flag > 1 {
+		return nil, fmt.Errorf("share: decoding base point flag: %w", ErrEncoding)
+	}
+	want := int(t) * pointSize
+	if flag == 1 {
+		want += pointSize
+	}
+	if t == 0 || r.Len() != want {
+		return nil, fmt.Errorf("share: %d bytes for %d coefficients: %w", r.Len(), t, ErrEncoding)
+	}
+	p := &PubPoly{g: g, commits: make([]abstract.Point, t)}
+	if flag == 1 {
+		p.b = g.Point()
+		if _, err := p.b.UnmarshalFrom(r); err != nil {
+			return nil, err
+		}
+	}
+	for i := range p.commits {
+		p.commits[i] = g.Point()
+		if _, err := p.commits[i].UnmarshalFrom(r); err != nil {
+			return nil, err
+		}
+	}
+	return p, nil
+}
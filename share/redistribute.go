@@ -0,0 +1,112 @@
+package share
+
+import (
+	"crypto/cipher"
+	"errors"
+	"fmt"
+
+	"github.com/dedis/crypto/abstract"
+)
+
+// ErrRedistribution is returned for redistribution contributions that do not
+// re-share the dealer's share or whose sub-shares do not match their
+// commitments.
+var ErrRedistribution = errors.New("invalid redistribution contribution")
+
+// A redistribution moves a secret shared with threshold t among n holders to
+// a sharing with threshold newT among newN holders, without assembling the
+// secret. At least t old holders re-share their shares with NewRedistribution,
+// send the sub-shares privately to the new holders and broadcast the
+// commitments. Every new holder checks the contributions with
+// VerifyRedistribution against the old public commitment polynomial, which
+// binds each contribution to the dealer's share, and, once the holders agree
+// on the set of valid dealers, combines the sub-shares with
+// RedistributeShare and the commitments with RedistributePubPoly:
+//
+//	commits, subShares := NewRedistribution(g, priShare, newT, newN, rand)
+//	err := VerifyRedistribution(oldPub, dealer, commits, newT, subShare)
+//	newShare, err := RedistributeShare(g, dealers, n, subShares)
+//	newPub, err := RedistributePubPoly(oldPub, dealers, n, commits)
+//
+// The new share of a holder is the Lagrange interpolation at 0 of its
+// sub-shares, weighted as the dealers' shares in the old sharing.
+
+// NewRedistribution re-shares the private share with threshold newT among
+// newN holders and returns the public commitment polynomial for the standard
+// base point and the newN sub-shares.
+func NewRedistribution(g abstract.Group, priShare *PriShare, newT, newN int, rand cipher.Stream) (*PubPoly, []*PriShare) {
+	poly := NewPriPoly(g, newT, g.Scalar().Set(priShare.V), rand)
+	return poly.Commit(nil), poly.Shares(newN)
+}
+
+// VerifyRedistribution checks that commits re-share the share of the old
+// holder dealer, committed to by oldPub, with threshold newT and that the
+// sub-share matches them.
+func VerifyRedistribution(oldPub *PubPoly, dealer int, commits *PubPoly, newT int, subShare *PriShare) error {
+	if commits.Threshold() != newT {
+		return fmt.Errorf("share: redistribution of threshold %d instead of %d: %w", commits.Threshold(), newT, ErrRedistribution)
+	}
+	if !commits.Commit().Equal(oldPub.Eval(dealer).V) {
+		return fmt.Errorf("share: redistribution does not re-share share %d: %w", dealer, ErrRedistribution)
+	}
+	if !commits.Check(subShare) {
+		return fmt.Errorf("share: redistribution sub-share %d does not match commitments: %w", subShare.I, ErrRedistribution)
+	}
+	return nil
+}
+
+// RedistributeShare combines the sub-shares that a new holder received from
+// the old holders dealers, out of n, into its new share. subShares[k] is the
+// sub-share from dealers[k]; all must be for the same index and there must
+// be at least the old threshold of dealers, which the caller checks.
+func RedistributeShare(g abstract.Group, dealers []int, n int, subShares []*PriShare) (*PriShare, error) {
+	if len(dealers) == 0 || len(dealers) != len(subShares) {
+		return nil, fmt.Errorf("share: redistributing %d sub-shares of %d dealers: %w", len(subShares), len(dealers), ErrRedistribution)
+	}
+	ip, err := NewInterpolator(g, dealers, n)
+	if err != nil {
+		return nil, err
+	}
+	index := subShares[0].I
+	v := g.Scalar().Zero()
+	tmp := g.Scalar()
+	for k, s := range subShares {
+		if s.I != index {
+			return nil, fmt.Errorf("share: redistribution sub-share %d for share %d: %w", s.I, index, ErrRedistribution)
+		}
+		v.Add(v, tmp.Mul(ip.Coefficient(dealers[k]), s.V))
+	}
+	return &PriShare{index, v}, nil
+}
+
+// RedistributePubPoly combines the commitments of the old holders dealers,
+// out of n, into the public commitment polynomial of the new sharing and
+// checks that it commits to the same secret as oldPub.
+func RedistributePubPoly(oldPub *PubPoly, dealers []int, n int, commits []*PubPoly) (*PubPoly, error) {
+	if len(dealers) < oldPub.Threshold() || len(dealers) != len(commits) {
+		return nil, fmt.Errorf("share: redistributing %d commitments of %d dealers for threshold %d: %w", len(commits), len(dealers), oldPub.Threshold(), ErrRedistribution)
+	}
+	ip, err := NewInterpolator(oldPub.g, dealers, n)
+	if err != nil {
+		return nil, err
+	}
+	newT := commits[0].Threshold()
+	points := make([]abstract.Point, newT)
+	for m := range points {
+		points[m] = oldPub.g.Point().Null()
+	}
+	tmp := oldPub.g.Point()
+	for k, c := range commits {
+		if c.Threshold() != newT {
+			return nil, fmt.Errorf("share: redistributing commitments of thresholds %d and %d: %w", newT, c.Threshold(), ErrCoeffs)
+		}
+		for m, C := range c.commits {
+			points[m].Add(points[m], tmp.Mul(C, ip.Coefficient(dealers[k])))
+		}
+	}
+	newPub := &PubPoly{oldPub.g, nil, points}
+	if !newPub.Commit().Equal(oldPub.Commit()) {
+		return nil, fmt.Errorf("share: redistribution changes the secret: %w", ErrRedistribution)
+	}
+	return newPub, nil
+}
@@ -0,0 +1,82 @@
+package share
+
+import (
+	"crypto/cipher"
+	"errors"
+	"fmt"
+
+	"github.com/dedis/crypto/abstract"
+)
+
+// ErrRefresh is returned for refresh contributions that are not valid
+// sharings of zero or whose sub-shares do not match their commitments.
+var ErrRefresh = errors.New("invalid refresh contribution")
+
+// A proactive refresh re-randomizes the shares of a long-lived secret without
+// changing it, so that shares leaked in different epochs cannot be combined.
+// Every participant deals a random sharing of zero with NewRefresh, sends the
+// sub-shares privately and broadcasts the commitments. Every participant
+// checks the sub-shares it receives with VerifyRefresh and, once the
+// participants agree on the valid contributions, adds them to its share with
+// RefreshShare and to the public commitment polynomial with RefreshPubPoly:
+//
+//	commits, subShares := NewRefresh(g, t, n, rand)
+//	err := VerifyRefresh(commits, t, subShare)  // for every contribution
+//	newShare, err := RefreshShare(g, priShare, subShares)
+//	newPub, err := RefreshPubPoly(pubPoly, commits)
+//
+// The refreshed shares are shares of the same secret, checked by the
+// refreshed commitment polynomial, whose constant term is unchanged.
+
+// NewRefresh creates a random sharing of zero with threshold t and returns
+// its public commitment polynomial for the standard base point and the n
+// sub-shares.
+func NewRefresh(g abstract.Group, t, n int, rand cipher.Stream) (*PubPoly, []*PriShare) {
+	poly := NewPriPoly(g, t, g.Scalar().Zero(), rand)
+	return poly.Commit(nil), poly.Shares(n)
+}
+
+// VerifyRefresh checks that commits commit to a sharing of zero with
+// threshold t and that the sub-share matches it.
+func VerifyRefresh(commits *PubPoly, t int, subShare *PriShare) error {
+	if commits.Threshold() != t {
+		return fmt.Errorf("share: refresh of threshold %d instead of %d: %w", commits.Threshold(), t, ErrRefresh)
+	}
+	if !commits.Commit().Equal(commits.g.Point().Null()) {
+		return fmt.Errorf("share: refresh does not share zero: %w", ErrRefresh)
+	}
+	if !commits.Check(subShare) {
+		return fmt.Errorf("share: refresh sub-share %d does not match commitments: %w", subShare.I, ErrRefresh)
+	}
+	return nil
+}
+
+// RefreshShare adds the sub-shares of the valid refresh contributions to the
+// private share and returns the refreshed share. The sub-shares must be for
+// the share's index.
+func RefreshShare(g abstract.Group, priShare *PriShare, subShares []*PriShare) (*PriShare, error) {
+	v := g.Scalar().Set(priShare.V)
+	for _, s := range subShares {
+		if s.I != priShare.I {
+			return nil, fmt.Errorf("share: refresh sub-share %d for share %d: %w", s.I, priShare.I, ErrRefresh)
+		}
+		v.Add(v, s.V)
+	}
+	return &PriShare{priShare.I, v}, nil
+}
+
+// RefreshPubPoly adds the commitments of the valid refresh contributions to
+// the public commitment polynomial and returns the refreshed polynomial.
+func RefreshPubPoly(pubPoly *PubPoly, commits []*PubPoly) (*PubPoly, error) {
+	acc := pubPoly
+	for _, c := range commits {
+		if !c.Commit().Equal(c.g.Point().Null()) {
+			return nil, fmt.Errorf("share: refresh does not share zero: %w", ErrRefresh)
+		}
+		var err error
+		if acc, err = acc.Add(c); err != nil {
+			return nil, err
+		}
+	}
+	return acc, nil
+}
@@ -0,0 +1,166 @@
+package rotation
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+
+	"github.com/dedis/crypto/abstract"
+	"github.com/dedis/crypto/share"
+	"github.com/dedis/crypto/sign"
+)
+
+// Some error definitions of the certificates
+var errorCertificate = errors.New("invalid transition certificate")
+var errorSignatures = errors.New("not enough valid certificate signatures")
+var errorChain = errors.New("certificates do not form a chain")
+
+// Certificate records an epoch transition for external auditors: the public
+// commitment polynomials before and after the transition, the transcripts of
+// the qualified dealers, from which the new polynomial follows, and the
+// signatures of participants that took part in the transition. A chain of
+// certificates tracks the custody of a long-lived key across epochs without
+// revealing any share.
+type Certificate struct {
+	Epoch       uint64         // Epoch that is entered
+	Mode        Mode           // Refresh or Reshare
+	Old         *share.PubPoly // Commitments of the previous epoch
+	New         *share.PubPoly // Commitments of the new epoch
+	Transcripts []*Transcript  // Transcripts of the qualified dealers
+	Signatures  []*Signature   // Signatures of the participants
+}
+
+// Signature is a participant's Schnorr signature on a certificate under its
+// long-term key.
+type Signature struct {
+	Signer int // Share index of the participant
+	Sig    []byte
+}
+
+// Certificate returns the unsigned certificate of the last epoch transition
+// of the participant, or nil before the first transition. Every participant
+// of the transition computes the same certificate; the participants sign it
+// with Sign and gather the signatures in Signatures.
+func (d *Driver) Certificate() *Certificate {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.last == nil {
+		return nil
+	}
+	c := *d.last
+	return &c
+}
+
+// message returns the encoding of the certificate without the signatures.
+func (c *Certificate) message() ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteString("rotation-certificate")
+	binary.Write(&b, binary.BigEndian, c.Epoch)
+	binary.Write(&b, binary.BigEndian, uint32(c.Mode))
+	polys := []*share.PubPoly{c.Old, c.New}
+	for _, tr := range c.Transcripts {
+		binary.Write(&b, binary.BigEndian, uint32(tr.Dealer))
+		polys = append(polys, tr.Commits)
+	}
+	for _, p := range polys {
+		enc, err := p.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		binary.Write(&b, binary.BigEndian, uint32(len(enc)))
+		b.Write(enc)
+	}
+	return b.Bytes(), nil
+}
+
+// Sign signs the certificate as the participant with the given share index
+// and long-term private key.
+func (c *Certificate) Sign(suite abstract.Suite, index int, longterm abstract.Scalar) (*Signature, error) {
+	msg, err := c.message()
+	if err != nil {
+		return nil, err
+	}
+	sig, err := sign.Schnorr(suite, longterm, msg)
+	if err != nil {
+		return nil, err
+	}
+	return &Signature{index, sig}, nil
+}
+
+// Verify checks the certificate for the participants with the given
+// long-term public keys, indexed by share index: every transcript must be
+// for the certified epoch and commit to zero in Refresh mode or to the
+// dealer's public share in Reshare mode, the new commitments must follow
+// from the old ones and the transcripts, and at least the new threshold of
+// distinct participants must have signed the certificate.
+func (c *Certificate) Verify(suite abstract.Suite, keys []abstract.Point) error {
+	if c.Old == nil || c.New == nil || len(c.Transcripts) == 0 {
+		return errorCertificate
+	}
+	newT := c.New.Threshold()
+	if c.Mode == Refresh && newT != c.Old.Threshold() || c.Mode == Reshare && len(c.Transcripts) < c.Old.Threshold() {
+		return errorCertificate
+	}
+	qualified := make([]int, len(c.Transcripts))
+	trs := make(map[int]*Transcript)
+	for k, tr := range c.Transcripts {
+		if tr == nil || tr.Epoch != c.Epoch || tr.Dealer < 0 || tr.Dealer >= len(keys) || trs[tr.Dealer] != nil {
+			return errorCertificate
+		}
+		if tr.Commits == nil || tr.Commits.Threshold() != newT {
+			return errorCertificate
+		}
+		want := suite.Point().Null()
+		if c.Mode == Reshare {
+			want = c.Old.Eval(tr.Dealer).V
+		}
+		if !tr.Commits.Commit().Equal(want) {
+			return errorCertificate
+		}
+		qualified[k] = tr.Dealer
+		trs[tr.Dealer] = tr
+	}
+	w, err := weights(suite, c.Mode, len(keys), qualified)
+	if err != nil {
+		return errorCertificate
+	}
+	if !nextPubPoly(suite, c.Mode, c.Old, newT, qualified, w, trs).Equal(c.New) {
+		return errorCertificate
+	}
+
+	msg, err := c.message()
+	if err != nil {
+		return err
+	}
+	signed := make(map[int]bool)
+	for _, s := range c.Signatures {
+		if s == nil || s.Signer < 0 || s.Signer >= len(keys) || signed[s.Signer] {
+			continue
+		}
+		if sign.VerifySchnorr(suite, keys[s.Signer], msg, s.Sig) == nil {
+			signed[s.Signer] = true
+		}
+	}
+	if len(signed) < newT {
+		return errorSignatures
+	}
+	return nil
+}
+
+// VerifyChain checks a sequence of certificates of consecutive epochs, each
+// with Verify, and that each certificate starts from the commitments the
+// previous one ends with. It returns the commitments of the last epoch.
+func VerifyChain(suite abstract.Suite, keys []abstract.Point, certs []*Certificate) (*share.PubPoly, error) {
+	if len(certs) == 0 {
+		return nil, errorChain
+	}
+	for k, c := range certs {
+		if err := c.Verify(suite, keys); err != nil {
+			return nil, err
+		}
+		if k > 0 && (c.Epoch != certs[k-1].Epoch+1 || !c.Old.Equal(certs[k-1].New)) {
+			return nil, errorChain
+		}
+	}
+	return certs[len(certs)-1].New, nil
+}
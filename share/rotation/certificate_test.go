@@ -0,0 +1,101 @@
+package rotation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dedis/crypto/abstract"
+	"github.com/dedis/crypto/random"
+)
+
+// certify signs the certificate of the last transition of every driver.
+func certify(test *testing.T, drivers []*Driver, keys []abstract.Scalar) *Certificate {
+	c := drivers[0].Certificate()
+	for i, d := range drivers {
+		sig, err := d.Certificate().Sign(suite, i, keys[i])
+		if err != nil {
+			test.Fatal(err)
+		}
+		c.Signatures = append(c.Signatures, sig)
+	}
+	return c
+}
+
+func TestCertificate(test *testing.T) {
+	n, t := 5, 3
+	drivers, _, public := setup(Refresh, n, t, 0, time.Unix(0, 0))
+	keys := make([]abstract.Scalar, n)
+	pubs := make([]abstract.Point, n)
+	for i := range keys {
+		keys[i] = suite.Scalar().Pick(random.Stream)
+		pubs[i] = suite.Point().Mul(nil, keys[i])
+	}
+	if drivers[0].Certificate() != nil {
+		test.Fatal("certificate before the first transition")
+	}
+
+	var certs []*Certificate
+	for epoch := 1; epoch <= 3; epoch++ {
+		rotate(test, drivers, []int{0, 2, 3}, time.Unix(int64(epoch)*3600, 0))
+		c := certify(test, drivers, keys)
+		if err := c.Verify(suite, pubs); err != nil {
+			test.Fatal(err)
+		}
+		certs = append(certs, c)
+	}
+	last, err := VerifyChain(suite, pubs, certs)
+	if err != nil {
+		test.Fatal(err)
+	}
+	_, pub := drivers[0].Share()
+	if !last.Equal(pub) || !last.Commit().Equal(public) {
+		test.Fatal("chain does not end with the current commitments")
+	}
+
+	if _, err := VerifyChain(suite, pubs, []*Certificate{certs[0], certs[2]}); err != errorChain {
+		test.Fatal("accepted chain with a gap")
+	}
+	few := *certs[1]
+	few.Signatures = few.Signatures[:t-1]
+	if err := few.Verify(suite, pubs); err != errorSignatures {
+		test.Fatal("accepted certificate with too few signatures")
+	}
+	dup := *certs[1]
+	dup.Signatures = []*Signature{certs[1].Signatures[0], certs[1].Signatures[0], certs[1].Signatures[0]}
+	if err := dup.Verify(suite, pubs); err != errorSignatures {
+		test.Fatal("counted repeated signatures")
+	}
+	forged := *certs[1]
+	forged.New = certs[2].New
+	if err := forged.Verify(suite, pubs); err != errorCertificate {
+		test.Fatal("accepted certificate with wrong new commitments")
+	}
+	dropped := *certs[1]
+	dropped.Transcripts = dropped.Transcripts[1:]
+	if err := dropped.Verify(suite, pubs); err != errorCertificate {
+		test.Fatal("accepted certificate with missing transcript")
+	}
+}
+
+func TestCertificateReshare(test *testing.T) {
+	n, t, newT := 5, 3, 4
+	drivers, _, _ := setup(Reshare, n, t, newT, time.Unix(0, 0))
+	keys := make([]abstract.Scalar, n)
+	pubs := make([]abstract.Point, n)
+	for i := range keys {
+		keys[i] = suite.Scalar().Pick(random.Stream)
+		pubs[i] = suite.Point().Mul(nil, keys[i])
+	}
+	rotate(test, drivers, []int{1, 2, 4}, time.Unix(3600, 0))
+	c := certify(test, drivers, keys)
+	if err := c.Verify(suite, pubs); err != nil {
+		test.Fatal(err)
+	}
+	if c.New.Threshold() != newT || !c.New.Commit().Equal(c.Old.Commit()) {
+		test.Fatal("reshare changed the public key")
+	}
+	c.Signatures = c.Signatures[:newT-1]
+	if err := c.Verify(suite, pubs); err != errorSignatures {
+		test.Fatal("accepted fewer signatures than the new threshold")
+	}
+}
@@ -0,0 +1,135 @@
+package rotation
+
+import (
+	"crypto/cipher"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/dedis/crypto/protocol"
+	"github.com/dedis/crypto/share"
+)
+
+// Some error definitions
+var errorPayload = errors.New("unexpected message payload")
+var errorSender = errors.New("transcript not sent by its dealer")
+var errorDuplicate = errors.New("duplicate contribution")
+
+// Party runs the transition of a Driver to the next epoch as a
+// protocol.ProtocolState, with the share indices as protocol indices. On
+// creation it deals, broadcasts its transcript and sends the sub-shares
+// privately. Once it has heard the contribution of every participant, valid
+// or not, it advances with the dealers whose contributions verified. Since
+// the qualified dealers must be the same for all participants, this relies
+// on a reliable broadcast channel. If some participants fail, the caller
+// advances with SetTimeout. The party is done once the driver entered the
+// next epoch, which starts at the time given to NewParty. Payloads are
+// *Transcript and *share.PriShare.
+type Party struct {
+	protocol.Outbox
+	driver    *Driver
+	now       time.Time
+	trs       map[int]*Transcript
+	subs      map[int]*share.PriShare
+	heard     map[int]bool // Dealers whose contributions were processed
+	qualified []int
+	done      bool
+}
+
+// NewParty starts the transition of the driver to the next epoch at now.
+func NewParty(d *Driver, rand cipher.Stream, now time.Time) (*Party, error) {
+	tr, subShares, err := d.Deal(rand)
+	if err != nil {
+		return nil, err
+	}
+	p := &Party{
+		driver:    d,
+		now:       now,
+		trs:       make(map[int]*Transcript),
+		subs:      make(map[int]*share.PriShare),
+		heard:     map[int]bool{tr.Dealer: true},
+		qualified: []int{tr.Dealer},
+	}
+	p.Send(&protocol.Message{From: tr.Dealer, To: protocol.Broadcast, Payload: tr})
+	for j, sub := range subShares {
+		if j != tr.Dealer {
+			p.Send(&protocol.Message{From: tr.Dealer, To: j, Payload: sub})
+		}
+	}
+	return p, nil
+}
+
+// ProcessMessage handles the transcript or the sub-share of a dealer. Both
+// are processed together once both arrived.
+func (p *Party) ProcessMessage(msg *protocol.Message) error {
+	i := msg.From
+	if i < 0 || i >= p.driver.n {
+		return errorSender
+	}
+	if p.heard[i] || p.done {
+		return errorDuplicate
+	}
+	switch payload := msg.Payload.(type) {
+	case *Transcript:
+		if payload.Dealer != i {
+			return errorSender
+		}
+		if p.trs[i] != nil {
+			return errorDuplicate
+		}
+		p.trs[i] = payload
+	case *share.PriShare:
+		if p.subs[i] != nil {
+			return errorDuplicate
+		}
+		p.subs[i] = payload
+	default:
+		return errorPayload
+	}
+	if p.trs[i] == nil || p.subs[i] == nil {
+		return nil
+	}
+	p.heard[i] = true
+	err := p.driver.Process(p.trs[i], p.subs[i])
+	if err == nil {
+		p.qualified = append(p.qualified, i)
+	}
+	if len(p.heard) == p.driver.n {
+		if aerr := p.advance(); aerr != nil {
+			return aerr
+		}
+	}
+	return err
+}
+
+// SetTimeout advances with the dealers whose contributions verified so far,
+// e.g., once the synchrony bound has passed without a contribution of every
+// participant.
+func (p *Party) SetTimeout() error {
+	if p.done {
+		return nil
+	}
+	return p.advance()
+}
+
+func (p *Party) advance() error {
+	sort.Ints(p.qualified)
+	if err := p.driver.Advance(p.qualified, p.now); err != nil {
+		return err
+	}
+	p.done = true
+	return nil
+}
+
+// Done returns true once the driver entered the next epoch.
+func (p *Party) Done() bool {
+	return p.done
+}
+
+// Qualified returns the sorted indices of the dealers whose contributions
+// verified so far.
+func (p *Party) Qualified() []int {
+	q := append([]int(nil), p.qualified...)
+	sort.Ints(q)
+	return q
+}
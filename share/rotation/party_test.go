@@ -0,0 +1,84 @@
+package rotation
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/dedis/crypto/protocol"
+	"github.com/dedis/crypto/protocol/protocoltest"
+	"github.com/dedis/crypto/random"
+)
+
+func parties(test *testing.T, drivers []*Driver, now time.Time) ([]*Party, []protocol.ProtocolState) {
+	ps := make([]*Party, len(drivers))
+	states := make([]protocol.ProtocolState, len(drivers))
+	for i, d := range drivers {
+		var err error
+		if ps[i], err = NewParty(d, random.Stream, now); err != nil {
+			test.Fatal(err)
+		}
+		states[i] = ps[i]
+	}
+	return ps, states
+}
+
+func TestParty(test *testing.T) {
+	n, t := 5, 3
+	now := time.Unix(5000, 0)
+	drivers, secret, public := setup(Refresh, n, t, 0, time.Unix(1000, 0))
+	ps, states := parties(test, drivers, now)
+	rejected, err := protocol.Run(states)
+	if err != nil || len(rejected) != 0 {
+		test.Fatal("refresh failed:", err, rejected)
+	}
+	for i, p := range ps {
+		if !reflect.DeepEqual(p.Qualified(), []int{0, 1, 2, 3, 4}) || drivers[i].Epoch() != 1 {
+			test.Fatal("wrong transition")
+		}
+	}
+	check(test, drivers, t, secret, public)
+
+	// A transcript for another epoch disqualifies its dealer
+	ps, states = parties(test, drivers, now.Add(time.Hour))
+	corrupt := protocoltest.Corrupt(2, func(payload interface{}) interface{} {
+		tr, ok := payload.(*Transcript)
+		if !ok {
+			return payload
+		}
+		bad := *tr
+		bad.Epoch++
+		return &bad
+	})
+	net := &protocoltest.Network{States: states, Behaviors: []protocoltest.Behavior{corrupt}}
+	res, err := net.Run()
+	if err != nil || len(res.Rejected) != n-1 {
+		test.Fatal("corrupt transcript not rejected:", err, res.Rejected)
+	}
+	for i, p := range ps {
+		if i != 2 && !reflect.DeepEqual(p.Qualified(), []int{0, 1, 3, 4}) {
+			test.Fatal("corrupt dealer qualified")
+		}
+	}
+	check(test, append(drivers[:2:2], drivers[3:]...), t, secret, public)
+}
+
+func TestPartyTimeout(test *testing.T) {
+	// A crashed participant stalls the transition until it times out
+	n, t, newT := 5, 3, 2
+	drivers, secret, public := setup(Reshare, n, t, newT, time.Unix(1000, 0))
+	ps, states := parties(test, drivers, time.Unix(5000, 0))
+	net := &protocoltest.Network{States: states, Behaviors: []protocoltest.Behavior{protocoltest.Drop(4)}}
+	if _, err := net.Run(); err == nil {
+		test.Fatal("transition without all contributions")
+	}
+	for _, p := range ps[:4] {
+		if err := p.SetTimeout(); err != nil {
+			test.Fatal(err)
+		}
+		if !reflect.DeepEqual(p.Qualified(), []int{0, 1, 2, 3}) {
+			test.Fatal("wrong qualified dealers")
+		}
+	}
+	check(test, drivers[:4], newT, secret, public)
+}
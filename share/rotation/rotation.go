@@ -0,0 +1,284 @@
+// Package rotation drives the periodic rotation of Shamir shares of a
+// long-lived secret, so that an adversary has to corrupt a threshold of
+// participants within a single epoch to learn the secret. A Driver keeps the
+// epoch state of one participant and produces and consumes the transcripts of
+// every epoch transition:
+//
+//	if d.Due(now) {
+//		tr, subShares, err := d.Deal(rand)
+//		// broadcast tr, send subShares[j] privately to participant j
+//	}
+//	// for every received transcript and sub-share
+//	err := d.Process(tr, subShare)
+//	// once the participants agree on the qualified dealers
+//	err := d.Advance(qualified, now)
+//
+// In Refresh mode every participant deals a random sharing of zero and adds
+// the sub-shares it receives to its share, which re-randomizes the shares
+// without changing the secret. In Reshare mode every participant re-shares
+// its share with a possibly different threshold and the new shares are
+// obtained by Lagrange interpolation of the sub-shares. In both modes the
+// sub-shares are verified against the dealers' commitments and the
+// participants' public commitment polynomial is updated, so the collective
+// public key stays the same.
+//
+// After every transition, the participants sign the Certificate of the
+// transition, which lets external auditors follow the key across epochs with
+// VerifyChain.
+package rotation
+
+import (
+	"crypto/cipher"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/dedis/crypto/abstract"
+	"github.com/dedis/crypto/share"
+)
+
+// Some error definitions
+var errorDealt = errors.New("already dealt in this epoch")
+var errorEpoch = errors.New("transcript for another epoch")
+var errorTranscript = errors.New("invalid transcript")
+var errorSubShare = errors.New("sub-share does not match commitments")
+var errorQualified = errors.New("not enough qualified dealers")
+var errorMissing = errors.New("no valid transcript from qualified dealer")
+
+// Mode selects how shares are rotated.
+type Mode int
+
+const (
+	// Refresh adds a random sharing of zero to the shares.
+	Refresh Mode = iota
+	// Reshare re-shares the shares, possibly with a new threshold.
+	Reshare
+)
+
+// Transcript is the public part of a participant's contribution to an epoch
+// transition.
+type Transcript struct {
+	Epoch   uint64         // Epoch that is entered
+	Dealer  int            // Share index of the dealer
+	Commits *share.PubPoly // Commitments to the dealer's polynomial
+}
+
+// Driver keeps the rotation state of one participant. It is safe for
+// concurrent use.
+type Driver struct {
+	suite    abstract.Suite
+	mode     Mode
+	n        int
+	newT     int
+	interval time.Duration
+
+	mu       sync.Mutex
+	epoch    uint64
+	start    time.Time
+	priShare *share.PriShare
+	pubPoly  *share.PubPoly
+	dealt    bool
+	received map[int]*received
+	last     *Certificate // Certificate of the last transition
+}
+
+type received struct {
+	tr  *Transcript
+	sub *share.PriShare
+}
+
+// NewDriver creates the driver of the participant holding priShare of a
+// sharing among n participants with public commitment polynomial pubPoly.
+// The current epoch started at start and a rotation is due after every
+// interval. In Reshare mode the rotated sharing has threshold newT; in
+// Refresh mode newT is ignored.
+func NewDriver(suite abstract.Suite, mode Mode, priShare *share.PriShare, pubPoly *share.PubPoly, n, newT int, interval time.Duration, start time.Time) *Driver {
+	if mode == Refresh {
+		newT = pubPoly.Threshold()
+	}
+	return &Driver{
+		suite:    suite,
+		mode:     mode,
+		n:        n,
+		newT:     newT,
+		interval: interval,
+		start:    start,
+		priShare: priShare,
+		pubPoly:  pubPoly,
+		received: make(map[int]*received),
+	}
+}
+
+// Epoch returns the current epoch.
+func (d *Driver) Epoch() uint64 {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.epoch
+}
+
+// Share returns the participant's share and the public commitment polynomial
+// of the current epoch.
+func (d *Driver) Share() (*share.PriShare, *share.PubPoly) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.priShare, d.pubPoly
+}
+
+// NextRotation returns the time at which the next rotation is due.
+func (d *Driver) NextRotation() time.Time {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.start.Add(d.interval)
+}
+
+// Due reports whether the rotation of the current epoch is due at now.
+func (d *Driver) Due(now time.Time) bool {
+	return !now.Before(d.NextRotation())
+}
+
+// Deal creates the participant's transcript for the transition to the next
+// epoch, together with the sub-shares for all participants, indexed by share
+// index, which must be delivered privately. The participant's own sub-share
+// is processed right away.
+func (d *Driver) Deal(rand cipher.Stream) (*Transcript, []*share.PriShare, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.dealt {
+		return nil, nil, errorDealt
+	}
+	var poly *share.PriPoly
+	if d.mode == Refresh {
+		poly = share.NewPriPoly(d.suite, d.newT, d.suite.Scalar().Zero(), rand)
+	} else {
+		poly = share.NewPriPoly(d.suite, d.newT, d.priShare.V, rand)
+	}
+	tr := &Transcript{Epoch: d.epoch + 1, Dealer: d.priShare.I, Commits: poly.Commit(nil)}
+	subShares := poly.Shares(d.n)
+	d.dealt = true
+	d.received[d.priShare.I] = &received{tr, subShares[d.priShare.I]}
+	return tr, subShares, nil
+}
+
+// Process verifies the transcript of another participant and the sub-share
+// it sent to this participant and keeps both for the transition.
+func (d *Driver) Process(tr *Transcript, sub *share.PriShare) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if tr.Epoch != d.epoch+1 {
+		return errorEpoch
+	}
+	if tr.Dealer < 0 || tr.Dealer >= d.n || tr.Commits == nil || tr.Commits.Threshold() != d.newT {
+		return errorTranscript
+	}
+	// The constant term commits to zero, or to the dealer's current share
+	want := d.suite.Point().Null()
+	if d.mode == Reshare {
+		want = d.pubPoly.Eval(tr.Dealer).V
+	}
+	if !tr.Commits.Commit().Equal(want) {
+		return errorTranscript
+	}
+	if sub.I != d.priShare.I || !tr.Commits.Check(sub) {
+		return errorSubShare
+	}
+	d.received[tr.Dealer] = &received{tr, sub}
+	return nil
+}
+
+// Advance enters the next epoch using the transcripts of the qualified
+// dealers, on which all participants must agree. It fails if a transcript of
+// a qualified dealer has not been processed or, in Reshare mode, if fewer
+// than the current threshold of dealers are qualified. The share of the
+// previous epoch is erased.
+func (d *Driver) Advance(qualified []int, now time.Time) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if len(qualified) == 0 || (d.mode == Reshare && len(qualified) < d.pubPoly.Threshold()) {
+		return errorQualified
+	}
+	for _, i := range qualified {
+		if d.received[i] == nil {
+			return errorMissing
+		}
+	}
+
+	w, err := weights(d.suite, d.mode, d.n, qualified)
+	if err != nil {
+		return err
+	}
+	trs := make(map[int]*Transcript)
+	newShare := d.suite.Scalar().Zero()
+	if d.mode == Refresh {
+		newShare.Set(d.priShare.V)
+	}
+	for _, i := range qualified {
+		r := d.received[i]
+		trs[i] = r.tr
+		newShare.Add(newShare, d.suite.Scalar().Mul(w[i], r.sub.V))
+	}
+	newPoly := nextPubPoly(d.suite, d.mode, d.pubPoly, d.newT, qualified, w, trs)
+
+	d.last = &Certificate{
+		Epoch:       d.epoch + 1,
+		Mode:        d.mode,
+		Old:         d.pubPoly,
+		New:         newPoly,
+		Transcripts: make([]*Transcript, len(qualified)),
+	}
+	for k, i := range qualified {
+		d.last.Transcripts[k] = trs[i]
+	}
+
+	d.priShare.V.Zero()
+	d.priShare = &share.PriShare{I: d.priShare.I, V: newShare}
+	d.pubPoly = newPoly
+	d.epoch++
+	d.start = now
+	d.dealt = false
+	d.received = make(map[int]*received)
+	return nil
+}
+
+// weights returns the factors of the contributions of the qualified dealers
+// among n participants: 1 in Refresh mode and their Lagrange coefficients in
+// Reshare mode.
+func weights(suite abstract.Suite, mode Mode, n int, qualified []int) (map[int]abstract.Scalar, error) {
+	w := make(map[int]abstract.Scalar)
+	if mode == Reshare {
+		ip, err := share.NewInterpolator(suite, qualified, n)
+		if err != nil {
+			return nil, errorQualified
+		}
+		for _, i := range qualified {
+			w[i] = ip.Coefficient(i)
+		}
+		return w, nil
+	}
+	for _, i := range qualified {
+		w[i] = suite.Scalar().One()
+	}
+	return w, nil
+}
+
+// nextPubPoly computes the public commitment polynomial of the next epoch
+// from the current one and the transcripts of the qualified dealers, weighted
+// by w.
+func nextPubPoly(suite abstract.Suite, mode Mode, pubPoly *share.PubPoly, newT int, qualified []int, w map[int]abstract.Scalar, trs map[int]*Transcript) *share.PubPoly {
+	newCommits := make([]abstract.Point, newT)
+	for k := range newCommits {
+		newCommits[k] = suite.Point().Null()
+	}
+	if mode == Refresh {
+		_, commits := pubPoly.Info()
+		for k := range newCommits {
+			newCommits[k].Set(commits[k])
+		}
+	}
+	for _, i := range qualified {
+		_, commits := trs[i].Commits.Info()
+		for k := range newCommits {
+			newCommits[k].Add(newCommits[k], suite.Point().Mul(commits[k], w[i]))
+		}
+	}
+	return share.NewPubPoly(suite, nil, newCommits)
+}
@@ -0,0 +1,146 @@
+package rotation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dedis/crypto/abstract"
+	"github.com/dedis/crypto/edwards"
+	"github.com/dedis/crypto/random"
+	"github.com/dedis/crypto/share"
+)
+
+var suite = edwards.NewAES128SHA256Ed25519(false)
+
+func setup(mode Mode, n, t, newT int, start time.Time) ([]*Driver, abstract.Scalar, abstract.Point) {
+	poly := share.NewPriPoly(suite, t, nil, random.Stream)
+	pub := poly.Commit(nil)
+	drivers := make([]*Driver, n)
+	for i, s := range poly.Shares(n) {
+		drivers[i] = NewDriver(suite, mode, s, pub, n, newT, time.Hour, start)
+	}
+	return drivers, poly.Secret().Clone(), pub.Commit()
+}
+
+func rotate(test *testing.T, drivers []*Driver, qualified []int, now time.Time) {
+	for _, i := range qualified {
+		tr, subs, err := drivers[i].Deal(random.Stream)
+		if err != nil {
+			test.Fatal(err)
+		}
+		for j, d := range drivers {
+			if j == i {
+				continue
+			}
+			if err := d.Process(tr, subs[j]); err != nil {
+				test.Fatal(err)
+			}
+		}
+	}
+	for _, d := range drivers {
+		if err := d.Advance(qualified, now); err != nil {
+			test.Fatal(err)
+		}
+	}
+}
+
+func check(test *testing.T, drivers []*Driver, t int, secret abstract.Scalar, public abstract.Point) {
+	shares := make([]*share.PriShare, len(drivers))
+	for i, d := range drivers {
+		s, pub := d.Share()
+		if !pub.Check(s) {
+			test.Fatal("share does not match commitments")
+		}
+		if pub.Threshold() != t || !pub.Commit().Equal(public) {
+			test.Fatal("wrong public polynomial")
+		}
+		shares[i] = s
+	}
+	recovered, err := share.RecoverSecret(suite, shares[:t], t, len(drivers))
+	if err != nil {
+		test.Fatal(err)
+	}
+	if !recovered.Equal(secret) {
+		test.Fatal("secret changed by rotation")
+	}
+}
+
+func TestRefresh(test *testing.T) {
+	n, t := 5, 3
+	start := time.Unix(1000, 0)
+	drivers, secret, public := setup(Refresh, n, t, 0, start)
+
+	if drivers[0].Due(start.Add(time.Minute)) {
+		test.Fatal("rotation due too early")
+	}
+	now := start.Add(time.Hour)
+	if !drivers[0].Due(now) {
+		test.Fatal("rotation not due")
+	}
+	old := drivers[0].priShare.V.Clone()
+	rotate(test, drivers, []int{0, 1, 2, 3, 4}, now)
+	check(test, drivers, t, secret, public)
+
+	s, _ := drivers[0].Share()
+	if s.V.Equal(old) {
+		test.Fatal("share not refreshed")
+	}
+	if drivers[0].Epoch() != 1 || !drivers[0].NextRotation().Equal(now.Add(time.Hour)) {
+		test.Fatal("wrong epoch state")
+	}
+
+	// A subset of qualified dealers is enough to refresh
+	rotate(test, drivers, []int{1, 3}, now.Add(time.Hour))
+	check(test, drivers, t, secret, public)
+}
+
+func TestReshare(test *testing.T) {
+	n, t, newT := 5, 3, 4
+	drivers, secret, public := setup(Reshare, n, t, newT, time.Unix(1000, 0))
+
+	rotate(test, drivers, []int{0, 2, 4}, time.Unix(5000, 0))
+	check(test, drivers, newT, secret, public)
+}
+
+func TestRotationErrors(test *testing.T) {
+	n, t := 4, 2
+	drivers, _, _ := setup(Refresh, n, t, 0, time.Unix(0, 0))
+
+	tr, subs, err := drivers[0].Deal(random.Stream)
+	if err != nil {
+		test.Fatal(err)
+	}
+	if _, _, err := drivers[0].Deal(random.Stream); err != errorDealt {
+		test.Fatal("dealt twice in the same epoch")
+	}
+	if err := drivers[1].Process(tr, subs[2]); err != errorSubShare {
+		test.Fatal("accepted another participant's sub-share")
+	}
+	bad := *subs[1]
+	bad.V = suite.Scalar().Pick(random.Stream)
+	if err := drivers[1].Process(tr, &bad); err != errorSubShare {
+		test.Fatal("accepted wrong sub-share")
+	}
+	stale := *tr
+	stale.Epoch = 5
+	if err := drivers[1].Process(&stale, subs[1]); err != errorEpoch {
+		test.Fatal("accepted transcript for another epoch")
+	}
+
+	// A dealing of a non-zero secret is rejected in Refresh mode
+	poly := share.NewPriPoly(suite, t, nil, random.Stream)
+	forged := &Transcript{Epoch: 1, Dealer: 3, Commits: poly.Commit(nil)}
+	if err := drivers[1].Process(forged, poly.Eval(1)); err != errorTranscript {
+		test.Fatal("accepted sharing of non-zero secret")
+	}
+
+	if err := drivers[1].Process(tr, subs[1]); err != nil {
+		test.Fatal(err)
+	}
+	if err := drivers[1].Advance([]int{0, 2}, time.Unix(10, 0)); err != errorMissing {
+		test.Fatal("advanced without qualified transcript")
+	}
+	if err := drivers[1].Advance(nil, time.Unix(10, 0)); err != errorQualified {
+		test.Fatal("advanced without qualified dealers")
+	}
+}
@@ -10,16 +10,30 @@
 package share
 
 import (
+	"context"
 	"crypto/cipher"
 	"crypto/subtle"
 	"errors"
+	"fmt"
 
 	"github.com/dedis/crypto/abstract"
+	"github.com/dedis/crypto/group"
+	"github.com/dedis/crypto/metrics"
 )
 
-// Some error definitions
-var errorGroups = errors.New("non-matching groups")
-var errorCoeffs = errors.New("different number of coefficients")
+// Errors returned by this package, possibly wrapped with additional context.
+// Use errors.Is to test for them.
+var (
+	// ErrGroups is returned when combining polynomials over different groups.
+	ErrGroups = errors.New("non-matching groups")
+	// ErrCoeffs is returned when combining polynomials of different thresholds.
+	ErrCoeffs = errors.New("different number of coefficients")
+	// ErrTooFewShares is returned when fewer than a threshold of valid shares
+	// are available for reconstruction.
+	ErrTooFewShares = errors.New("not enough good shares")
+	// ErrEncoding is returned when decoding malformed polynomials.
+	ErrEncoding = errors.New("invalid encoding")
+)
 
 // PriShare represents a private share.
 type PriShare struct {
@@ -27,6 +41,14 @@ type PriShare struct {
 	V abstract.Scalar // Value of the private share
 }
 
+// Equal checks equality of two private shares s and o.
+func (s *PriShare) Equal(o *PriShare) bool {
+	if s == nil || o == nil {
+		return s == o
+	}
+	return s.I == o.I && group.ScalarEqual(s.V, o.V)
+}
+
 // PriPoly represents a secret sharing polynomial.
 type PriPoly struct {
 	g      abstract.Group    // Cryptographic group
@@ -57,6 +79,16 @@ func (p *PriPoly) Secret() abstract.Scalar {
 	return p.coeffs[0]
 }
 
+// Coefficients returns a copy of the coefficients of the polynomial,
+// starting with the constant term.
+func (p *PriPoly) Coefficients() []abstract.Scalar {
+	coeffs := make([]abstract.Scalar, len(p.coeffs))
+	for k, c := range p.coeffs {
+		coeffs[k] = c.Clone()
+	}
+	return coeffs
+}
+
 // Eval computes the private share v = p(i).
 func (p *PriPoly) Eval(i int) *PriShare {
 	xi := p.g.Scalar().SetInt64(1 + int64(i))
@@ -70,21 +102,17 @@ func (p *PriPoly) Eval(i int) *PriShare {
 
 // Shares creates a list of n private shares p(1),...,p(n).
 func (p *PriPoly) Shares(n int) []*PriShare {
-	shares := make([]*PriShare, n)
-	for i := range shares {
-		shares[i] = p.Eval(i)
-	}
-	return shares
+	return p.EvalBatch(seq(n))
 }
 
 // Add computes the component-wise sum of the polynomials p and q and returns it
 // as a new polynomial.
 func (p *PriPoly) Add(q *PriPoly) (*PriPoly, error) {
 	if p.g.String() != q.g.String() {
-		return nil, errorGroups
+		return nil, fmt.Errorf("share: adding private polynomials over %s and %s: %w", p.g.String(), q.g.String(), ErrGroups)
 	}
 	if p.Threshold() != q.Threshold() {
-		return nil, errorCoeffs
+		return nil, fmt.Errorf("share: adding private polynomials of thresholds %d and %d: %w", p.Threshold(), q.Threshold(), ErrCoeffs)
 	}
 	coeffs := make([]abstract.Scalar, p.Threshold())
 	for i := range coeffs {
@@ -95,7 +123,10 @@ func (p *PriPoly) Add(q *PriPoly) (*PriPoly, error) {
 
 // Equal checks equality of two secret sharing polynomials p and q.
 func (p *PriPoly) Equal(q *PriPoly) bool {
-	if p.g.String() != q.g.String() {
+	if p == nil || q == nil {
+		return p == q
+	}
+	if p.g.String() != q.g.String() || p.Threshold() != q.Threshold() {
 		return false
 	}
 	b := 1
@@ -120,6 +151,14 @@ func (p *PriPoly) Commit(b abstract.Point) *PubPoly {
 // RecoverSecret reconstructs the shared secret p(0) from a list of private
 // shares using Lagrange interpolation.
 func RecoverSecret(g abstract.Group, shares []*PriShare, t, n int) (abstract.Scalar, error) {
+	return RecoverSecretContext(context.Background(), g, shares, t, n)
+}
+
+// RecoverSecretContext is like RecoverSecret but aborts with the context's
+// error once the context is done.
+func RecoverSecretContext(ctx context.Context, g abstract.Group, shares []*PriShare, t, n int) (_ abstract.Scalar, err error) {
+	defer metrics.Start("share.RecoverSecret").End(&err)
+
 	x := make(map[int]abstract.Scalar)
 	for i, s := range shares {
 		if s == nil || s.V == nil || s.I < 0 || n <= s.I {
@@ -129,7 +168,7 @@ func RecoverSecret(g abstract.Group, shares []*PriShare, t, n int) (abstract.Sca
 	}
 
 	if len(x) < t {
-		return nil, errors.New("not enough good private shares to reconstruct shared secret")
+		return nil, fmt.Errorf("share: reconstructing shared secret from %d of %d required private shares: %w", len(x), t, ErrTooFewShares)
 	}
 
 	acc := g.Scalar().Zero()
@@ -138,6 +177,9 @@ func RecoverSecret(g abstract.Group, shares []*PriShare, t, n int) (abstract.Sca
 	tmp := g.Scalar()
 
 	for i, xi := range x {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		num.Set(shares[i].V)
 		den.One()
 		for j, xj := range x {
@@ -159,6 +201,14 @@ type PubShare struct {
 	V abstract.Point // Value of the public share
 }
 
+// Equal checks equality of two public shares s and o.
+func (s *PubShare) Equal(o *PubShare) bool {
+	if s == nil || o == nil {
+		return s == o
+	}
+	return s.I == o.I && group.PointEqual(s.V, o.V)
+}
+
 // PubPoly represents a public commitment polynomial to a secret sharing polynomial.
 type PubPoly struct {
 	g       abstract.Group   // Cryptographic group
@@ -171,9 +221,18 @@ func NewPubPoly(g abstract.Group, b abstract.Point, commits []abstract.Point) *P
 	return &PubPoly{g, b, commits}
 }
 
-// Info returns the base point and the commitments to the polynomial coefficients.
+// Info returns copies of the base point and the commitments to the polynomial
+// coefficients.
 func (p *PubPoly) Info() (abstract.Point, []abstract.Point) {
-	return p.b, p.commits
+	var b abstract.Point
+	if p.b != nil {
+		b = p.b.Clone()
+	}
+	commits := make([]abstract.Point, len(p.commits))
+	for k, C := range p.commits {
+		commits[k] = C.Clone()
+	}
+	return b, commits
 }
 
 // Threshold returns the secret sharing threshold.
@@ -199,11 +258,7 @@ func (p *PubPoly) Eval(i int) *PubShare {
 
 // Shares creates a list of n public commitment shares p(1),...,p(n).
 func (p *PubPoly) Shares(n int) []*PubShare {
-	shares := make([]*PubShare, n)
-	for i := range shares {
-		shares[i] = p.Eval(i)
-	}
-	return shares
+	return p.EvalBatch(seq(n))
 }
 
 // Add computes the component-wise sum of the polynomials p and q and returns it
@@ -214,11 +269,11 @@ func (p *PubPoly) Shares(n int) []*PubShare {
 // base point and thus should not be used in further computations.
 func (p *PubPoly) Add(q *PubPoly) (*PubPoly, error) {
 	if p.g.String() != q.g.String() {
-		return nil, errorGroups
+		return nil, fmt.Errorf("share: adding public polynomials over %s and %s: %w", p.g.String(), q.g.String(), ErrGroups)
 	}
 
 	if p.Threshold() != q.Threshold() {
-		return nil, errorCoeffs
+		return nil, fmt.Errorf("share: adding public polynomials of thresholds %d and %d: %w", p.Threshold(), q.Threshold(), ErrCoeffs)
 	}
 
 	commits := make([]abstract.Point, p.Threshold())
@@ -229,9 +284,13 @@ func (p *PubPoly) Add(q *PubPoly) (*PubPoly, error) {
 	return &PubPoly{p.g, p.b, commits}, nil
 }
 
-// Equal checks equality of two public commitment polynomials p and q.
+// Equal checks equality of two public commitment polynomials p and q,
+// including their base points.
 func (p *PubPoly) Equal(q *PubPoly) bool {
-	if p.g.String() != q.g.String() {
+	if p == nil || q == nil {
+		return p == q
+	}
+	if p.g.String() != q.g.String() || p.Threshold() != q.Threshold() || !group.PointEqual(p.b, q.b) {
 		return false
 	}
 	b := 1
@@ -253,6 +312,14 @@ func (p *PubPoly) Check(s *PriShare) bool {
 // RecoverCommit reconstructs the secret commitment p(0) from a list of public
 // shares using Lagrange interpolation.
 func RecoverCommit(g abstract.Group, shares []*PubShare, t, n int) (abstract.Point, error) {
+	return RecoverCommitContext(context.Background(), g, shares, t, n)
+}
+
+// RecoverCommitContext is like RecoverCommit but aborts with the context's
+// error once the context is done.
+func RecoverCommitContext(ctx context.Context, g abstract.Group, shares []*PubShare, t, n int) (_ abstract.Point, err error) {
+	defer metrics.Start("share.RecoverCommit").End(&err)
+
 	x := make(map[int]abstract.Scalar)
 	for i, s := range shares {
 		if s == nil || s.V == nil || s.I < 0 || n <= s.I {
@@ -262,7 +329,7 @@ func RecoverCommit(g abstract.Group, shares []*PubShare, t, n int) (abstract.Poi
 	}
 
 	if len(x) < t {
-		return nil, errors.New("not enough good public shares to reconstruct secret commitment")
+		return nil, fmt.Errorf("share: reconstructing secret commitment from %d of %d required public shares: %w", len(x), t, ErrTooFewShares)
 	}
 
 	num := g.Scalar()
@@ -272,6 +339,9 @@ func RecoverCommit(g abstract.Group, shares []*PubShare, t, n int) (abstract.Poi
 	Tmp := g.Point()
 
 	for i, xi := range x {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		num.One()
 		den.One()
 		for j, xj := range x {
@@ -1,8 +1,13 @@
 package share
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
 	"testing"
 
+	"github.com/dedis/crypto/abstract"
 	"github.com/dedis/crypto/edwards"
 	"github.com/dedis/crypto/random"
 )
@@ -63,7 +68,7 @@ func TestSecretRecoveryDeleteFail(test *testing.T) {
 	shares[8] = nil
 
 	_, err := RecoverSecret(g, shares, t, n)
-	if err == nil {
+	if !errors.Is(err, ErrTooFewShares) {
 		test.Fatal("recovered secret unexpectably")
 	}
 }
@@ -165,11 +170,28 @@ func TestPublicRecoveryDeleteFail(test *testing.T) {
 	shares[8] = nil
 
 	_, err := RecoverCommit(g, shares, t, n)
-	if err == nil {
+	if !errors.Is(err, ErrTooFewShares) {
 		test.Fatal("recovered commit unexpectably")
 	}
 }
 
+func TestRecoveryContext(test *testing.T) {
+	g := new(edwards.ExtendedCurve).Init(edwards.Param25519(), false)
+	n := 10
+	t := n/2 + 1
+
+	priPoly := NewPriPoly(g, t, nil, random.Stream)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := RecoverSecretContext(ctx, g, priPoly.Shares(n), t, n); err != context.Canceled {
+		test.Fatal("recovered secret despite cancellation")
+	}
+	if _, err := RecoverCommitContext(ctx, g, priPoly.Commit(nil).Shares(n), t, n); err != context.Canceled {
+		test.Fatal("recovered commit despite cancellation")
+	}
+}
+
 func TestPrivateAdd(test *testing.T) {
 	g := new(edwards.ExtendedCurve).Init(edwards.Param25519(), false)
 	n := 10
@@ -251,3 +273,503 @@ func TestPublicPolyEqual(test *testing.T) {
 		test.Fatal("public polynomials not equal")
 	}
 }
+
+func TestPolyCopies(test *testing.T) {
+	g := new(edwards.ExtendedCurve).Init(edwards.Param25519(), false)
+	G, _ := g.Point().Pick([]byte("G"), random.Stream)
+	p := NewPriPoly(g, 3, nil, random.Stream)
+	P := p.Commit(G)
+	secret := p.Secret().Clone()
+	commit := P.Commit().Clone()
+
+	coeffs := p.Coefficients()
+	coeffs[0].Zero()
+	b, commits := P.Info()
+	b.Null()
+	commits[0].Null()
+	if !p.Secret().Equal(secret) || !P.Commit().Equal(commit) || !P.Check(p.Eval(1)) {
+		test.Fatal("polynomial changed through its returned coefficients")
+	}
+}
+
+func TestShareEqual(test *testing.T) {
+	g := new(edwards.ExtendedCurve).Init(edwards.Param25519(), false)
+	p := NewPriPoly(g, 3, nil, random.Stream)
+	q := NewPriPoly(g, 4, p.Secret(), random.Stream)
+
+	if !p.Eval(1).Equal(p.Eval(1)) || p.Eval(1).Equal(p.Eval(2)) {
+		test.Fatal("wrong private share equality")
+	}
+	if !p.Commit(nil).Eval(1).Equal(p.Commit(nil).Eval(1)) || p.Commit(nil).Eval(1).Equal(q.Commit(nil).Eval(1)) {
+		test.Fatal("wrong public share equality")
+	}
+	if p.Equal(q) || p.Commit(nil).Equal(q.Commit(nil)) {
+		test.Fatal("polynomials of different thresholds are equal")
+	}
+	if p.Commit(nil).Equal(p.Commit(g.Point().Base())) {
+		test.Fatal("public polynomials with different bases are equal")
+	}
+	var nilShare *PriShare
+	if !nilShare.Equal(nil) || nilShare.Equal(p.Eval(0)) {
+		test.Fatal("wrong nil share equality")
+	}
+}
+
+func TestAggregatePubPoly(test *testing.T) {
+	g := new(edwards.ExtendedCurve).Init(edwards.Param25519(), false)
+	n, t, dealers := 5, 3, 4
+
+	// Each dealer shares a random secret; the collective key is their sum
+	var pubPolys []*PubPoly
+	var priPolys []*PriPoly
+	for i := 0; i < dealers; i++ {
+		p := NewPriPoly(g, t, nil, random.Stream)
+		priPolys = append(priPolys, p)
+		pubPolys = append(pubPolys, p.Commit(nil))
+	}
+	pub, err := AggregatePubPoly(pubPolys)
+	if err != nil {
+		test.Fatal(err)
+	}
+	x := g.Scalar().Zero()
+	for _, p := range priPolys {
+		x.Add(x, p.Secret())
+	}
+	if !pub.Commit().Equal(g.Point().Mul(nil, x)) {
+		test.Fatal("wrong collective public key")
+	}
+
+	// Verification keys match the participants' combined shares
+	for i, key := range pub.VerificationKeys(n) {
+		xi := g.Scalar().Zero()
+		for _, p := range priPolys {
+			xi.Add(xi, p.Eval(i).V)
+		}
+		if !key.Equal(g.Point().Mul(nil, xi)) {
+			test.Fatal("wrong verification key")
+		}
+	}
+
+	// Serialization round trip, also with a non-standard base point
+	H, _ := g.Point().Pick([]byte("H"), random.Stream)
+	for _, p := range []*PubPoly{pub, priPolys[0].Commit(H)} {
+		buf, err := p.MarshalBinary()
+		if err != nil {
+			test.Fatal(err)
+		}
+		q, err := UnmarshalPubPoly(g, buf)
+		if err != nil {
+			test.Fatal(err)
+		}
+		if !p.Equal(q) {
+			test.Fatal("decoded polynomial differs")
+		}
+		if _, err := UnmarshalPubPoly(g, buf[:len(buf)-1]); !errors.Is(err, ErrEncoding) {
+			test.Fatal("truncated polynomial decoded")
+		}
+	}
+
+	if _, err := AggregatePubPoly(nil); err == nil {
+		test.Fatal("aggregated no polynomials")
+	}
+}
+
+func TestRefresh(test *testing.T) {
+	g := new(edwards.ExtendedCurve).Init(edwards.Param25519(), false)
+	n, t := 7, 4
+	poly := NewPriPoly(g, t, nil, random.Stream)
+	pub := poly.Commit(nil)
+	shares := poly.Shares(n)
+
+	commits := make([]*PubPoly, n)
+	subShares := make([][]*PriShare, n) // subShares[j][i] from dealer i to j
+	for j := range subShares {
+		subShares[j] = make([]*PriShare, n)
+	}
+	for i := range commits {
+		var subs []*PriShare
+		commits[i], subs = NewRefresh(g, t, n, random.Stream)
+		for j, s := range subs {
+			if err := VerifyRefresh(commits[i], t, s); err != nil {
+				test.Fatal(err)
+			}
+			subShares[j][i] = s
+		}
+	}
+	newPub, err := RefreshPubPoly(pub, commits)
+	if err != nil {
+		test.Fatal(err)
+	}
+	if !newPub.Commit().Equal(pub.Commit()) {
+		test.Fatal("refresh changed the public key")
+	}
+	refreshed := make([]*PriShare, n)
+	for j := range refreshed {
+		if refreshed[j], err = RefreshShare(g, shares[j], subShares[j]); err != nil {
+			test.Fatal(err)
+		}
+		if !newPub.Check(refreshed[j]) || refreshed[j].V.Equal(shares[j].V) {
+			test.Fatal("refreshed share invalid or unchanged")
+		}
+	}
+	secret, err := RecoverSecret(g, refreshed, t, n)
+	if err != nil {
+		test.Fatal(err)
+	}
+	if !secret.Equal(poly.Secret()) {
+		test.Fatal("refresh changed the secret")
+	}
+
+	// A contribution that shares a non-zero value
+	bad := NewPriPoly(g, t, nil, random.Stream)
+	if err := VerifyRefresh(bad.Commit(nil), t, bad.Eval(0)); !errors.Is(err, ErrRefresh) {
+		test.Fatal("accepted sharing of non-zero value")
+	}
+	if _, err := RefreshPubPoly(pub, []*PubPoly{bad.Commit(nil)}); !errors.Is(err, ErrRefresh) {
+		test.Fatal("accepted commitments of non-zero value")
+	}
+	if err := VerifyRefresh(commits[0], t, subShares[1][1]); !errors.Is(err, ErrRefresh) {
+		test.Fatal("accepted sub-share of another dealer")
+	}
+	if _, err := RefreshShare(g, shares[0], subShares[1]); !errors.Is(err, ErrRefresh) {
+		test.Fatal("accepted sub-shares for another index")
+	}
+}
+
+func TestInterpolator(test *testing.T) {
+	g := new(edwards.ExtendedCurve).Init(edwards.Param25519(), false)
+	n, t := 10, 4
+	indices := []int{7, 2, 5, 0}
+	ip, err := NewInterpolator(g, indices, n)
+	if err != nil {
+		test.Fatal(err)
+	}
+	for k := 0; k < 3; k++ {
+		poly := NewPriPoly(g, t, nil, random.Stream)
+		shares := poly.Shares(n)
+		secret, err := ip.Recover(shares)
+		if err != nil {
+			test.Fatal(err)
+		}
+		if !secret.Equal(poly.Secret()) {
+			test.Fatal("interpolated secret does not match")
+		}
+		commit, err := ip.RecoverCommit(poly.Commit(nil).Shares(n))
+		if err != nil {
+			test.Fatal(err)
+		}
+		if !commit.Equal(poly.Commit(nil).Commit()) {
+			test.Fatal("interpolated commitment does not match")
+		}
+		if _, err := ip.Recover(shares[1:]); !errors.Is(err, ErrTooFewShares) {
+			test.Fatal("recovered without share 0")
+		}
+	}
+
+	// The basis polynomials weighted by the shares sum up to the polynomial
+	poly := NewPriPoly(g, t, nil, random.Stream)
+	coeffs := make([]abstract.Scalar, t)
+	for k := range coeffs {
+		coeffs[k] = g.Scalar().Zero()
+	}
+	for _, i := range indices {
+		basis := ip.Basis(i)
+		if !basis[0].Equal(ip.Coefficient(i)) {
+			test.Fatal("basis polynomial does not match the coefficient")
+		}
+		for k, c := range basis {
+			coeffs[k].Add(coeffs[k], g.Scalar().Mul(c, poly.Eval(i).V))
+		}
+	}
+	for k, c := range poly.Coefficients() {
+		if !c.Equal(coeffs[k]) {
+			test.Fatal("interpolated polynomial does not match")
+		}
+	}
+	if ip.Basis(1) != nil || ip.Coefficient(1) != nil {
+		test.Fatal("interpolated index not among the indices")
+	}
+
+	// Arbitrary evaluation points
+	xs := []abstract.Scalar{g.Scalar().SetInt64(3), g.Scalar().SetInt64(-5), g.Scalar().SetInt64(11), g.Scalar().SetInt64(42)}
+	ipAt, err := NewInterpolatorAt(g, xs)
+	if err != nil {
+		test.Fatal(err)
+	}
+	secret := g.Scalar().Zero()
+	for k, x := range xs {
+		v := g.Scalar().Zero()
+		coeffs := poly.Coefficients()
+		for m := len(coeffs) - 1; m >= 0; m-- {
+			v.Mul(v, x)
+			v.Add(v, coeffs[m])
+		}
+		secret.Add(secret, v.Mul(v, ipAt.Coefficient(k)))
+	}
+	if !secret.Equal(poly.Secret()) {
+		test.Fatal("interpolated secret at arbitrary points does not match")
+	}
+	if _, err := NewInterpolatorAt(g, []abstract.Scalar{xs[0], xs[1], xs[0]}); !errors.Is(err, ErrIndex) {
+		test.Fatal("accepted repeated evaluation point")
+	}
+	if _, err := NewInterpolator(g, []int{1, 1}, n); !errors.Is(err, ErrIndex) {
+		test.Fatal("accepted repeated index")
+	}
+	if _, err := NewInterpolator(g, []int{1, n}, n); !errors.Is(err, ErrIndex) {
+		test.Fatal("accepted index out of range")
+	}
+}
+
+func BenchmarkRecoverSecret(b *testing.B) {
+	g := new(edwards.ExtendedCurve).Init(edwards.Param25519(), false)
+	n, t := 30, 20
+	shares := NewPriPoly(g, t, nil, random.Stream).Shares(n)
+	for i := 0; i < b.N; i++ {
+		RecoverSecret(g, shares[:t], t, n)
+	}
+}
+
+func BenchmarkInterpolatorRecover(b *testing.B) {
+	g := new(edwards.ExtendedCurve).Init(edwards.Param25519(), false)
+	n, t := 30, 20
+	shares := NewPriPoly(g, t, nil, random.Stream).Shares(n)
+	indices := make([]int, t)
+	for i := range indices {
+		indices[i] = i
+	}
+	ip, _ := NewInterpolator(g, indices, n)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ip.Recover(shares)
+	}
+}
+
+func TestPolyMul(test *testing.T) {
+	g := new(edwards.ExtendedCurve).Init(edwards.Param25519(), false)
+	n := 10
+	p := NewPriPoly(g, 3, nil, random.Stream)
+	q := NewPriPoly(g, 4, nil, random.Stream)
+	pq, err := p.Mul(q)
+	if err != nil {
+		test.Fatal(err)
+	}
+	if pq.Threshold() != 6 {
+		test.Fatal("wrong threshold of product", pq.Threshold())
+	}
+	ps, qs, pqs := p.Shares(n), q.Shares(n), pq.Shares(n)
+	for i := range pqs {
+		if !pqs[i].V.Equal(g.Scalar().Mul(ps[i].V, qs[i].V)) {
+			test.Fatal("share of product is not product of shares")
+		}
+	}
+	secret, err := RecoverSecret(g, pqs, 6, n)
+	if err != nil {
+		test.Fatal(err)
+	}
+	if !secret.Equal(g.Scalar().Mul(p.Secret(), q.Secret())) {
+		test.Fatal("product does not share product of secrets")
+	}
+	C, err := p.Commit(nil).Mul(q)
+	if err != nil {
+		test.Fatal(err)
+	}
+	if !C.Equal(pq.Commit(nil)) {
+		test.Fatal("commitments of product do not match")
+	}
+}
+
+func TestEvalBatch(test *testing.T) {
+	g := new(edwards.ExtendedCurve).Init(edwards.Param25519(), false)
+	p := NewPriPoly(g, 5, nil, random.Stream)
+	P := p.Commit(nil)
+	indices := []int{0, 3, 17, 1000, -1, -5}
+	priShares := p.EvalBatch(indices)
+	pubShares := P.EvalBatch(indices)
+	for k, i := range indices {
+		if !priShares[k].Equal(p.Eval(i)) || !pubShares[k].Equal(P.Eval(i)) {
+			test.Fatal("batched evaluation differs at index", i)
+		}
+		if !P.Check(priShares[k]) {
+			test.Fatal("batched shares do not match")
+		}
+	}
+}
+
+func TestPacked(test *testing.T) {
+	g := new(edwards.ExtendedCurve).Init(edwards.Param25519(), false)
+	n, t, k := 12, 3, 4
+	secrets := make([]abstract.Scalar, k)
+	for j := range secrets {
+		secrets[j] = g.Scalar().Pick(random.Stream)
+	}
+	poly := NewPackedPriPoly(g, t, secrets, random.Stream)
+	if poly.Threshold() != t+k {
+		test.Fatal("wrong number of coefficients", poly.Threshold())
+	}
+	if !poly.Secret().Equal(secrets[0]) {
+		test.Fatal("first secret not at 0")
+	}
+	shares := poly.Shares(n)
+	recovered, err := RecoverPacked(g, shares[n-t-k:], t, k, n)
+	if err != nil {
+		test.Fatal(err)
+	}
+	for j := range secrets {
+		if !recovered[j].Equal(secrets[j]) {
+			test.Fatal("packed secret", j, "not recovered")
+		}
+	}
+	if _, err := RecoverPacked(g, shares[:t+k-1], t, k, n); !errors.Is(err, ErrTooFewShares) {
+		test.Fatal("recovered from too few shares")
+	}
+	pub := poly.Commit(nil)
+	for _, s := range shares {
+		if !pub.Check(s) {
+			test.Fatal("packed share does not match commitments")
+		}
+	}
+}
+
+func TestRedistribution(test *testing.T) {
+	g := new(edwards.ExtendedCurve).Init(edwards.Param25519(), false)
+	n, t := 5, 3
+	for _, newN := range []int{4, 9} {
+		newT := newN - 1
+		poly := NewPriPoly(g, t, nil, random.Stream)
+		oldPub := poly.Commit(nil)
+		shares := poly.Shares(n)
+
+		dealers := []int{4, 1, 2}
+		commits := make([]*PubPoly, len(dealers))
+		subShares := make([][]*PriShare, newN) // subShares[j][k] from dealers[k] to j
+		for j := range subShares {
+			subShares[j] = make([]*PriShare, len(dealers))
+		}
+		for k, i := range dealers {
+			var subs []*PriShare
+			commits[k], subs = NewRedistribution(g, shares[i], newT, newN, random.Stream)
+			for j, s := range subs {
+				if err := VerifyRedistribution(oldPub, i, commits[k], newT, s); err != nil {
+					test.Fatal(err)
+				}
+				subShares[j][k] = s
+			}
+		}
+		newPub, err := RedistributePubPoly(oldPub, dealers, n, commits)
+		if err != nil {
+			test.Fatal(err)
+		}
+		newShares := make([]*PriShare, newN)
+		for j := range newShares {
+			if newShares[j], err = RedistributeShare(g, dealers, n, subShares[j]); err != nil {
+				test.Fatal(err)
+			}
+			if !newPub.Check(newShares[j]) {
+				test.Fatal("redistributed share does not match commitments")
+			}
+		}
+		secret, err := RecoverSecret(g, newShares[:newT], newT, newN)
+		if err != nil {
+			test.Fatal(err)
+		}
+		if !secret.Equal(poly.Secret()) {
+			test.Fatal("redistribution changed the secret")
+		}
+
+		// Contributions must re-share the dealer's own share
+		if err := VerifyRedistribution(oldPub, 0, commits[0], newT, subShares[0][0]); !errors.Is(err, ErrRedistribution) {
+			test.Fatal("accepted contribution for another dealer")
+		}
+		if _, err := RedistributePubPoly(oldPub, dealers[:2], n, commits[:2]); !errors.Is(err, ErrRedistribution) {
+			test.Fatal("accepted fewer dealers than the old threshold")
+		}
+	}
+}
+
+func TestEncoding(test *testing.T) {
+	g := new(edwards.ExtendedCurve).Init(edwards.Param25519(), false)
+	H, _ := g.Point().Pick(nil, random.Stream)
+	poly := NewPriPoly(g, 4, nil, random.Stream)
+	for _, pub := range []*PubPoly{poly.Commit(nil), poly.Commit(H)} {
+		buf, err := pub.MarshalBinary()
+		if err != nil || len(buf) != pub.MarshalSize() {
+			test.Fatal("wrong size of public polynomial encoding")
+		}
+		js, err := pub.MarshalJSON()
+		if err != nil {
+			test.Fatal(err)
+		}
+		dec, err := UnmarshalPubPolyJSON(g, js)
+		if err != nil || !dec.Equal(pub) {
+			test.Fatal("public polynomial JSON round trip failed", err)
+		}
+	}
+
+	buf, err := poly.MarshalBinary()
+	if err != nil || len(buf) != poly.MarshalSize() {
+		test.Fatal("wrong size of private polynomial encoding")
+	}
+	dec, err := UnmarshalPriPoly(g, buf)
+	if err != nil || !dec.Equal(poly) {
+		test.Fatal("private polynomial round trip failed", err)
+	}
+	if _, err := UnmarshalPriPoly(g, buf[:len(buf)-1]); !errors.Is(err, ErrEncoding) {
+		test.Fatal("decoded truncated polynomial")
+	}
+	js, err := poly.MarshalJSON()
+	if err != nil {
+		test.Fatal(err)
+	}
+	if dec, err = UnmarshalPriPolyJSON(g, js); err != nil || !dec.Equal(poly) {
+		test.Fatal("private polynomial JSON round trip failed", err)
+	}
+
+	priShare := poly.Eval(7)
+	pubShare := poly.Commit(nil).Eval(7)
+	var shares []abstract.Marshaling
+	shares = append(shares, priShare, pubShare)
+	for i, s := range shares {
+		buf, err := s.MarshalBinary()
+		if err != nil || len(buf) != s.MarshalSize() {
+			test.Fatal("wrong size of share encoding")
+		}
+		var d abstract.Marshaling = NewPriShare(g)
+		if i == 1 {
+			d = NewPubShare(g)
+		}
+		if err := d.UnmarshalBinary(buf); err != nil {
+			test.Fatal(err)
+		}
+		if d.String() != s.String() {
+			test.Fatal("share round trip failed")
+		}
+		if err := d.UnmarshalBinary(buf[1:]); !errors.Is(err, ErrEncoding) {
+			test.Fatal("decoded truncated share")
+		}
+	}
+	var b bytes.Buffer
+	if _, err := priShare.MarshalTo(&b); err != nil {
+		test.Fatal(err)
+	}
+	d := NewPriShare(g)
+	if _, err := d.UnmarshalFrom(&b); err != nil || !d.Equal(priShare) {
+		test.Fatal("share stream round trip failed")
+	}
+
+	js, err = json.Marshal([]*PriShare{priShare})
+	if err != nil {
+		test.Fatal(err)
+	}
+	ds := []*PriShare{NewPriShare(g)}
+	if err := json.Unmarshal(js, &ds); err != nil || !ds[0].Equal(priShare) {
+		test.Fatal("share JSON round trip failed", err)
+	}
+	js, err = pubShare.MarshalJSON()
+	if err != nil {
+		test.Fatal(err)
+	}
+	dp := NewPubShare(g)
+	if err := dp.UnmarshalJSON(js); err != nil || !dp.Equal(pubShare) {
+		test.Fatal("public share JSON round trip failed", err)
+	}
+}
@@ -0,0 +1,124 @@
+// Package adaptor implements Schnorr adaptor signatures. A pre-signature on a
+// message is locked to a statement T = tG: anybody can check that it is a
+// valid pre-signature for T, but only the holder of the witness t can adapt
+// it into a regular Schnorr signature, which verifies with sign.VerifySchnorr.
+// Conversely, given the pre-signature and the adapted signature, anybody
+// learns t. This makes the publication of a signature, e.g. to claim a
+// payment, reveal a secret in an atomic way:
+//
+//	signer:  pre := PreSign(suite, x, T, msg, rand)
+//	holder:  PreVerify(suite, X, T, msg, pre); sig := Adapt(suite, pre, t)
+//	signer:  t := Extract(suite, T, pre, sig)
+package adaptor
+
+import (
+	"bytes"
+	"crypto/cipher"
+	"errors"
+
+	"github.com/dedis/crypto/abstract"
+	"github.com/dedis/crypto/group"
+	"github.com/dedis/crypto/sign"
+)
+
+// Some error definitions
+var errorEncoding = errors.New("invalid signature encoding")
+var errorInvalidPreSignature = errors.New("invalid pre-signature")
+var errorWitness = errors.New("witness does not match statement")
+
+// PreSign creates a pre-signature on msg under the private key x locked to
+// the statement T. The pre-signature (e, s') consists of the challenge
+// e = H(R + T, X, msg) for R = kG and the response s' = k - xe. It is encoded
+// as the two scalars, like the signatures of sign.Schnorr.
+func PreSign(suite abstract.Suite, private abstract.Scalar, T abstract.Point, msg []byte, rand cipher.Stream) ([]byte, error) {
+	k := suite.Scalar().Pick(rand)
+	R := suite.Point().Mul(nil, k)
+	R.Add(R, T)
+	public := suite.Point().Mul(nil, private)
+	e, err := sign.Challenge(suite, public, R, msg)
+	if err != nil {
+		return nil, err
+	}
+	s := suite.Scalar().Mul(private, e)
+	s.Sub(k, s)
+	return encode(e, s)
+}
+
+// PreVerify checks that pre is a pre-signature on msg under the public key X
+// locked to the statement T, i.e., that e == H(s'G + eX + T, X, msg).
+func PreVerify(suite abstract.Suite, public abstract.Point, T abstract.Point, msg, pre []byte) error {
+	e, s, err := decode(suite, pre)
+	if err != nil {
+		return err
+	}
+	R := suite.Point().Mul(nil, s)
+	R.Add(R, suite.Point().Mul(public, e))
+	R.Add(R, T)
+	c, err := sign.Challenge(suite, public, R, msg)
+	if err != nil {
+		return err
+	}
+	if !c.Equal(e) {
+		return errorInvalidPreSignature
+	}
+	return nil
+}
+
+// Adapt turns the pre-signature into a Schnorr signature (e, s' + t) using
+// the witness t of its statement. The pre-signature must have been checked
+// with PreVerify.
+func Adapt(suite abstract.Suite, pre []byte, witness abstract.Scalar) ([]byte, error) {
+	e, s, err := decode(suite, pre)
+	if err != nil {
+		return nil, err
+	}
+	return encode(e, s.Add(s, witness))
+}
+
+// Extract recovers the witness t = s - s' of the statement T from a
+// pre-signature and the signature adapted from it.
+func Extract(suite abstract.Suite, T abstract.Point, pre, sig []byte) (abstract.Scalar, error) {
+	e1, s1, err := decode(suite, pre)
+	if err != nil {
+		return nil, err
+	}
+	e2, s2, err := decode(suite, sig)
+	if err != nil {
+		return nil, err
+	}
+	if !e1.Equal(e2) {
+		return nil, errorWitness
+	}
+	t := suite.Scalar().Sub(s2, s1)
+	if !suite.Point().Mul(nil, t).Equal(T) {
+		return nil, errorWitness
+	}
+	return t, nil
+}
+
+func encode(e, s abstract.Scalar) ([]byte, error) {
+	var b bytes.Buffer
+	if _, err := e.MarshalTo(&b); err != nil {
+		return nil, err
+	}
+	if _, err := s.MarshalTo(&b); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+func decode(suite abstract.Suite, buf []byte) (abstract.Scalar, abstract.Scalar, error) {
+	e := suite.Scalar()
+	s := suite.Scalar()
+	size := e.MarshalSize()
+	if len(buf) != 2*size {
+		return nil, nil, errorEncoding
+	}
+	if err := group.UnmarshalScalar(e, buf[:size], group.Strict); err != nil {
+		return nil, nil, errorEncoding
+	}
+	if err := group.UnmarshalScalar(s, buf[size:], group.Strict); err != nil {
+		return nil, nil, errorEncoding
+	}
+	return e, s, nil
+}
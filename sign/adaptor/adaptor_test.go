@@ -0,0 +1,46 @@
+package adaptor
+
+import (
+	"testing"
+
+	"github.com/dedis/crypto/edwards"
+	"github.com/dedis/crypto/random"
+	"github.com/dedis/crypto/sign"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var suite = edwards.NewAES128SHA256Ed25519(false)
+
+func TestAdaptor(t *testing.T) {
+	x := suite.Scalar().Pick(random.Stream)
+	X := suite.Point().Mul(nil, x)
+	w := suite.Scalar().Pick(random.Stream)
+	T := suite.Point().Mul(nil, w)
+	msg := []byte("pay 1 coin to the seller")
+
+	pre, err := PreSign(suite, x, T, msg, random.Stream)
+	require.Nil(t, err)
+	require.Nil(t, PreVerify(suite, X, T, msg, pre))
+	assert.NotNil(t, sign.VerifySchnorr(suite, X, msg, pre))
+	assert.NotNil(t, PreVerify(suite, X, T, []byte("other"), pre))
+	assert.NotNil(t, PreVerify(suite, X, suite.Point().Base(), msg, pre))
+
+	sig, err := Adapt(suite, pre, w)
+	require.Nil(t, err)
+	require.Nil(t, sign.VerifySchnorr(suite, X, msg, sig))
+
+	extracted, err := Extract(suite, T, pre, sig)
+	require.Nil(t, err)
+	assert.True(t, extracted.Equal(w))
+
+	// Adapting with a wrong witness yields an invalid signature
+	bad, err := Adapt(suite, pre, suite.Scalar().Pick(random.Stream))
+	require.Nil(t, err)
+	assert.NotNil(t, sign.VerifySchnorr(suite, X, msg, bad))
+	_, err = Extract(suite, T, pre, bad)
+	assert.Equal(t, errorWitness, err)
+
+	_, err = Adapt(suite, pre[1:], w)
+	assert.Equal(t, errorEncoding, err)
+}
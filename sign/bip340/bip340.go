@@ -0,0 +1,237 @@
+// Package bip340 implements Schnorr signatures over secp256k1 as specified
+// in BIP-340, the signature scheme of Bitcoin's Taproot. Public keys are the
+// 32-byte x-coordinates of points with even y-coordinate and all hashes are
+// tagged SHA-256 hashes, so keys and signatures interoperate with Bitcoin
+// tooling and hardware signers. Private keys are 32-byte big-endian integers
+// in [1, n-1].
+//
+// Unlike the Schnorr signatures of package sign, which work over any
+// abstract.Suite, the scheme is bound to secp256k1, for which this library
+// has no suite; the package therefore carries its own curve arithmetic.
+//
+// This arithmetic is NOT constant-time: it is built on math/big, and the
+// scalar multiplication branches on the bits of the scalar. The time taken
+// to sign thus leaks information about the private key and the nonce. Use
+// the package only where an attacker cannot time signing operations, e.g.,
+// for verification, tests and offline signing, and a constant-time
+// secp256k1 implementation otherwise.
+package bip340
+
+import (
+	"bytes"
+	"crypto/cipher"
+	"crypto/sha256"
+	"errors"
+	"math/big"
+
+	"github.com/dedis/crypto/random"
+)
+
+// Some error definitions
+var errorPrivateKey = errors.New("private key out of range")
+var errorPublicKey = errors.New("invalid public key")
+var errorSignature = errors.New("invalid signature")
+var errorAuxLength = errors.New("auxiliary randomness must be 32 bytes")
+
+// secp256k1 parameters: y^2 = x^3 + 7 over GF(p), base point G of order n.
+var (
+	p, _  = new(big.Int).SetString("fffffffffffffffffffffffffffffffffffffffffffffffffffffffefffffc2f", 16)
+	n, _  = new(big.Int).SetString("fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364141", 16)
+	gx, _ = new(big.Int).SetString("79be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798", 16)
+	gy, _ = new(big.Int).SetString("483ada7726a3c4655da4fbfc0e1108a8fd17b448a68554199c47d08ffb10d4b8", 16)
+)
+
+// point is an affine point of secp256k1; the point at infinity has x == nil.
+type point struct {
+	x, y *big.Int
+}
+
+func (P *point) infinity() bool {
+	return P.x == nil
+}
+
+func add(P, Q *point) *point {
+	if P.infinity() {
+		return Q
+	}
+	if Q.infinity() {
+		return P
+	}
+	var lambda *big.Int
+	if P.x.Cmp(Q.x) == 0 {
+		if P.y.Cmp(Q.y) != 0 || P.y.Sign() == 0 {
+			return &point{}
+		}
+		// lambda = 3x^2 / 2y
+		num := new(big.Int).Mul(P.x, P.x)
+		num.Mul(num, big.NewInt(3))
+		den := new(big.Int).Lsh(P.y, 1)
+		lambda = num.Mul(num, den.ModInverse(den, p))
+	} else {
+		// lambda = (y2 - y1) / (x2 - x1)
+		num := new(big.Int).Sub(Q.y, P.y)
+		den := new(big.Int).Sub(Q.x, P.x)
+		den.Mod(den, p)
+		lambda = num.Mul(num, den.ModInverse(den, p))
+	}
+	lambda.Mod(lambda, p)
+	x := new(big.Int).Mul(lambda, lambda)
+	x.Sub(x, P.x).Sub(x, Q.x).Mod(x, p)
+	y := new(big.Int).Sub(P.x, x)
+	y.Mul(y, lambda).Sub(y, P.y).Mod(y, p)
+	return &point{x, y}
+}
+
+// mul computes kP by double-and-add, which is not constant-time.
+func mul(P *point, k *big.Int) *point {
+	R := &point{}
+	for i := k.BitLen() - 1; i >= 0; i-- {
+		R = add(R, R)
+		if k.Bit(i) == 1 {
+			R = add(R, P)
+		}
+	}
+	return R
+}
+
+func base() *point {
+	return &point{gx, gy}
+}
+
+// liftX returns the point with x-coordinate x and even y-coordinate.
+func liftX(x *big.Int) (*point, error) {
+	if x.Cmp(p) >= 0 {
+		return nil, errorPublicKey
+	}
+	c := new(big.Int).Exp(x, big.NewInt(3), p)
+	c.Add(c, big.NewInt(7)).Mod(c, p)
+	// p = 3 mod 4, so sqrt(c) = c^((p+1)/4)
+	e := new(big.Int).Add(p, big.NewInt(1))
+	y := new(big.Int).Exp(c, e.Rsh(e, 2), p)
+	if new(big.Int).Exp(y, big.NewInt(2), p).Cmp(c) != 0 {
+		return nil, errorPublicKey
+	}
+	if y.Bit(0) == 1 {
+		y.Sub(p, y)
+	}
+	return &point{x, y}, nil
+}
+
+// bytes32 returns the 32-byte big-endian encoding of x.
+func bytes32(x *big.Int) []byte {
+	return x.FillBytes(make([]byte, 32))
+}
+
+// taggedHash returns SHA256(SHA256(tag) || SHA256(tag) || data...).
+func taggedHash(tag string, data ...[]byte) []byte {
+	t := sha256.Sum256([]byte(tag))
+	h := sha256.New()
+	h.Write(t[:])
+	h.Write(t[:])
+	for _, d := range data {
+		h.Write(d)
+	}
+	return h.Sum(nil)
+}
+
+func privateKey(secret []byte) (*big.Int, error) {
+	d := new(big.Int).SetBytes(secret)
+	if len(secret) != 32 || d.Sign() == 0 || d.Cmp(n) >= 0 {
+		return nil, errorPrivateKey
+	}
+	return d, nil
+}
+
+// NewKey returns a fresh private key picked from rand, or from random.Stream
+// if rand is nil.
+func NewKey(rand cipher.Stream) []byte {
+	if rand == nil {
+		rand = random.Stream
+	}
+	max := new(big.Int).Sub(n, big.NewInt(1))
+	d := random.Int(max, rand)
+	return bytes32(d.Add(d, big.NewInt(1)))
+}
+
+// PublicKey returns the x-only public key of the private key.
+func PublicKey(secret []byte) ([]byte, error) {
+	d, err := privateKey(secret)
+	if err != nil {
+		return nil, err
+	}
+	return bytes32(mul(base(), d).x), nil
+}
+
+// Sign signs msg with the private key using fresh auxiliary randomness.
+func Sign(secret, msg []byte) ([]byte, error) {
+	return SignAux(secret, msg, random.Bytes(32, random.Stream))
+}
+
+// SignAux signs msg with the private key and the 32 bytes of auxiliary
+// randomness aux as specified by BIP-340. Signing is deterministic for a
+// fixed aux, which makes it testable against the BIP-340 test vectors.
+func SignAux(secret, msg, aux []byte) ([]byte, error) {
+	d, err := privateKey(secret)
+	if err != nil {
+		return nil, err
+	}
+	if len(aux) != 32 {
+		return nil, errorAuxLength
+	}
+	P := mul(base(), d)
+	if P.y.Bit(0) == 1 {
+		d.Sub(n, d)
+	}
+	pub := bytes32(P.x)
+	t := taggedHash("BIP0340/aux", aux)
+	for i, b := range bytes32(d) {
+		t[i] ^= b
+	}
+	k := new(big.Int).SetBytes(taggedHash("BIP0340/nonce", t, pub, msg))
+	k.Mod(k, n)
+	if k.Sign() == 0 {
+		return nil, errorSignature
+	}
+	R := mul(base(), k)
+	if R.y.Bit(0) == 1 {
+		k.Sub(n, k)
+	}
+	rx := bytes32(R.x)
+	e := new(big.Int).SetBytes(taggedHash("BIP0340/challenge", rx, pub, msg))
+	e.Mod(e, n)
+	// s = k + ed mod n
+	s := e.Mul(e, d)
+	s.Add(s, k).Mod(s, n)
+	sig := append(rx, bytes32(s)...)
+	if err := Verify(pub, msg, sig); err != nil {
+		return nil, err
+	}
+	return sig, nil
+}
+
+// Verify checks the BIP-340 signature sig on msg under the x-only public key.
+func Verify(public, msg, sig []byte) error {
+	if len(public) != 32 {
+		return errorPublicKey
+	}
+	P, err := liftX(new(big.Int).SetBytes(public))
+	if err != nil {
+		return err
+	}
+	if len(sig) != 64 {
+		return errorSignature
+	}
+	r := new(big.Int).SetBytes(sig[:32])
+	s := new(big.Int).SetBytes(sig[32:])
+	if r.Cmp(p) >= 0 || s.Cmp(n) >= 0 {
+		return errorSignature
+	}
+	e := new(big.Int).SetBytes(taggedHash("BIP0340/challenge", sig[:32], public, msg))
+	e.Mod(e, n)
+	// R = sG - eP
+	R := add(mul(base(), s), mul(P, new(big.Int).Sub(n, e)))
+	if R.infinity() || R.y.Bit(0) == 1 || !bytes.Equal(bytes32(R.x), sig[:32]) {
+		return errorSignature
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package bip340
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func unhex(t *testing.T, s string) []byte {
+	b, err := hex.DecodeString(strings.ToLower(s))
+	require.Nil(t, err)
+	return b
+}
+
+// Test vectors 0 to 3 of BIP-340
+var vectors = []struct {
+	secret, public, aux, msg, sig string
+}{
+	{
+		"0000000000000000000000000000000000000000000000000000000000000003",
+		"F9308A019258C31049344F85F89D5229B531C845836F99B08601F113BCE036F9",
+		"0000000000000000000000000000000000000000000000000000000000000000",
+		"0000000000000000000000000000000000000000000000000000000000000000",
+		"E907831F80848D1069A5371B402410364BDF1C5F8307B0084C55F1CE2DCA821525F66A4A85EA8B71E482A74F382D2CE5EBEEE8FDB2172F477DF4900D310536C0",
+	},
+	{
+		"B7E151628AED2A6ABF7158809CF4F3C762E7160F38B4DA56A784D9045190CFEF",
+		"DFF1D77F2A671C5F36183726DB2341BE58FEAE1DA2DECED843240F7B502BA659",
+		"0000000000000000000000000000000000000000000000000000000000000001",
+		"243F6A8885A308D313198A2E03707344A4093822299F31D0082EFA98EC4E6C89",
+		"6896BD60EEAE296DB48A229FF71DFE071BDE413E6D43F917DC8DCF8C78DE33418906D11AC976ABCCB20B091292BFF4EA897EFCB639EA871CFA95F6DE339E4B0A",
+	},
+	{
+		"C90FDAA22168C234C4C6628B80DC1CD129024E088A67CC74020BBEA63B14E5C9",
+		"DD308AFEC5777E13121FA72B9CC1B7CC0139715309B086C960E18FD969774EB8",
+		"C87AA53824B4D7AE2EB035A2B5BBBCCC080E76CDC6D1692C4B0B62D798E6D906",
+		"7E2D58D8B3BCDF1ABADEC7829054F90DDA9805AAB56C77333024B9D0A508B75C",
+		"5831AAEED7B44BB74E5EAB94BA9D4294C49BCF2A60728D8B4C200F50DD313C1BAB745879A5AD954A72C45A91C3A51D3C7ADEA98D82F8481E0E1E03674A6F3FB7",
+	},
+	{
+		"0B432B2677937381AEF05BB02A66ECD012773062CF3FA2549E44F58ED2401710",
+		"25D1DFF95105F5253C4022F628A996AD3A0D95FBF21D468A1B33F8C160D8F517",
+		"FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFF",
+		"FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFF",
+		"7EB0509757E246F19449885651611CB965ECC1A187DD51B64FDA1EDC9637D5EC97582B9CB13DB3933705B32BA982AF5AF25FD78881EBB32771FC5922EFC66EA3",
+	},
+}
+
+func TestVectors(t *testing.T) {
+	for i, v := range vectors {
+		secret := unhex(t, v.secret)
+		public, err := PublicKey(secret)
+		require.Nil(t, err)
+		assert.Equal(t, unhex(t, v.public), public, "vector", i)
+		sig, err := SignAux(secret, unhex(t, v.msg), unhex(t, v.aux))
+		require.Nil(t, err)
+		assert.Equal(t, unhex(t, v.sig), sig, "vector", i)
+		assert.Nil(t, Verify(public, unhex(t, v.msg), unhex(t, v.sig)))
+	}
+}
+
+func TestSignVerify(t *testing.T) {
+	secret := NewKey(nil)
+	public, err := PublicKey(secret)
+	require.Nil(t, err)
+	msg := []byte("taproot")
+	sig, err := Sign(secret, msg)
+	require.Nil(t, err)
+	require.Nil(t, Verify(public, msg, sig))
+
+	assert.Equal(t, errorSignature, Verify(public, []byte("other"), sig))
+	bad := append([]byte{}, sig...)
+	bad[63] ^= 1
+	assert.Equal(t, errorSignature, Verify(public, msg, bad))
+	assert.Equal(t, errorSignature, Verify(public, msg, sig[:63]))
+	assert.NotNil(t, Verify(public[:31], msg, sig))
+
+	// Public keys not on the curve are rejected (vector 5 of BIP-340)
+	offCurve := unhex(t, "EEFDEA4CDB677750A420FEE807EACF21EB9898AE79B9768766E4FAA04A2D4A34")
+	assert.Equal(t, errorPublicKey, Verify(offCurve, msg, sig))
+
+	_, err = PublicKey(make([]byte, 32))
+	assert.Equal(t, errorPrivateKey, err)
+	_, err = SignAux(secret, msg, nil)
+	assert.Equal(t, errorAuxLength, err)
+}
@@ -0,0 +1,179 @@
+package blind
+
+import (
+	"testing"
+
+	"github.com/dedis/crypto/abstract"
+	"github.com/dedis/crypto/edwards"
+	"github.com/dedis/crypto/random"
+	"github.com/dedis/crypto/share"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var suite = edwards.NewAES128SHA256Ed25519(false)
+
+func TestBlindSchnorr(t *testing.T) {
+	signer := NewSigner(suite, suite.Scalar().Pick(random.Stream))
+	msg := []byte("token nonce")
+
+	R := signer.Commit(random.Stream)
+	requester := NewRequester(suite, signer.Public, msg)
+	c, err := requester.Challenge(R, random.Stream)
+	require.Nil(t, err)
+	s, err := signer.Respond(c)
+	require.Nil(t, err)
+	sig, err := requester.Unblind(s)
+	require.Nil(t, err)
+	assert.Nil(t, Verify(suite, signer.Public, msg, sig))
+	assert.Error(t, Verify(suite, signer.Public, []byte("other"), sig))
+
+	// The signer's view is unlinkable to the signature
+	assert.False(t, c.Equal(sig.C))
+	assert.False(t, s.Equal(sig.S))
+
+	// The nonce cannot be used twice
+	_, err = signer.Respond(c)
+	assert.Error(t, err)
+}
+
+func TestBlindSchnorrBadResponse(t *testing.T) {
+	signer := NewSigner(suite, suite.Scalar().Pick(random.Stream))
+	R := signer.Commit(random.Stream)
+	requester := NewRequester(suite, signer.Public, []byte("msg"))
+	_, err := requester.Challenge(R, random.Stream)
+	require.Nil(t, err)
+	_, err = requester.Unblind(suite.Scalar().Pick(random.Stream))
+	assert.Error(t, err)
+}
+
+func TestBlindThreshold(t *testing.T) {
+	n, th := 7, 4
+	priPoly := share.NewPriPoly(suite, th, nil, random.Stream)
+	shares := priPoly.Shares(n)
+	pubPoly := priPoly.Commit(nil)
+	X := pubPoly.Commit()
+	msg := []byte("token nonce")
+
+	// A subset of the committee runs the session
+	var signers []*ThresholdSigner
+	var commits []*PartialCommitment
+	for _, i := range []int{1, 2, 4, 6} {
+		s := NewThresholdSigner(suite, shares[i])
+		signers = append(signers, s)
+		commits = append(commits, s.Commit(random.Stream))
+	}
+	R, err := AggregateCommitments(suite, commits)
+	require.Nil(t, err)
+
+	requester := NewRequester(suite, X, msg)
+	c, err := requester.Challenge(R, random.Stream)
+	require.Nil(t, err)
+
+	set := Signers(commits)
+	partials := make([]*PartialResponse, len(signers))
+	for i, s := range signers {
+		partials[i], err = s.Respond(c, set)
+		require.Nil(t, err)
+		assert.Nil(t, VerifyPartial(suite, pubPoly, c, set, commits[i], partials[i]))
+	}
+
+	s, bad, err := CombinePartials(suite, pubPoly, c, commits, partials)
+	require.Nil(t, err)
+	require.Nil(t, bad)
+	sig, err := requester.Unblind(s)
+	require.Nil(t, err)
+	assert.Nil(t, Verify(suite, X, msg, sig))
+
+	// A corrupted partial response is blamed on its signer
+	partials[2] = &PartialResponse{partials[2].I, suite.Scalar().Pick(random.Stream)}
+	_, bad, err = CombinePartials(suite, pubPoly, c, commits, partials)
+	assert.Error(t, err)
+	assert.Equal(t, []int{4}, bad)
+}
+
+func TestBlindOkamoto(t *testing.T) {
+	x1 := suite.Scalar().Pick(random.Stream)
+	x2 := suite.Scalar().Pick(random.Stream)
+	signer := NewOkamotoSigner(suite, x1, x2)
+	msg := []byte("token nonce")
+
+	R := signer.Commit(random.Stream)
+	requester := NewOkamotoRequester(suite, signer.Public, msg)
+	c, err := requester.Challenge(R, random.Stream)
+	require.Nil(t, err)
+	s1, s2, err := signer.Respond(c)
+	require.Nil(t, err)
+	sig, err := requester.Unblind(s1, s2)
+	require.Nil(t, err)
+	assert.Nil(t, VerifyOkamoto(suite, signer.Public, msg, sig))
+	assert.Error(t, VerifyOkamoto(suite, signer.Public, []byte("other"), sig))
+	assert.False(t, c.Equal(sig.C))
+
+	// Swapped responses are rejected
+	_, err = requester.Unblind(s2, s1)
+	assert.Error(t, err)
+	_, _, err = signer.Respond(c)
+	assert.Error(t, err)
+}
+
+func TestIssuer(t *testing.T) {
+	msg := []byte("token nonce")
+	for _, x := range []struct {
+		scheme Scheme
+		max    int
+	}{{Schnorr, 1}, {OkamotoSchnorr, SafeSessions}} {
+		is, err := NewIssuer(suite, x.scheme, random.Stream, x.max)
+		require.Nil(t, err)
+
+		// Up to max sessions may run concurrently, one more is refused
+		ids := make([]uint64, x.max)
+		reqs := make([]*Request, x.max)
+		cs := make([]abstract.Scalar, x.max)
+		for k := range ids {
+			var R abstract.Point
+			ids[k], R, err = is.Commit(random.Stream)
+			require.Nil(t, err)
+			reqs[k], err = NewRequest(suite, x.scheme, is.Public(), msg)
+			require.Nil(t, err)
+			cs[k], err = reqs[k].Challenge(R, random.Stream)
+			require.Nil(t, err)
+		}
+		_, _, err = is.Commit(random.Stream)
+		assert.Equal(t, errorTooManySessions, err)
+
+		// Sessions may be answered in any order but only once
+		for k := len(ids) - 1; k >= 0; k-- {
+			resp, err := is.Respond(ids[k], cs[k])
+			require.Nil(t, err)
+			_, err = is.Respond(ids[k], cs[k])
+			assert.Equal(t, errorSession, err)
+			tok, err := reqs[k].Unblind(resp)
+			require.Nil(t, err)
+			assert.Nil(t, VerifyToken(suite, x.scheme, is.Public(), msg, tok))
+			assert.Error(t, VerifyToken(suite, x.scheme, is.Public(), []byte("other"), tok))
+		}
+
+		// Aborted sessions free their slot
+		id, _, err := is.Commit(random.Stream)
+		require.Nil(t, err)
+		is.Abort(id)
+		_, err = is.Respond(id, cs[0])
+		assert.Equal(t, errorSession, err)
+		for range ids {
+			_, _, err = is.Commit(random.Stream)
+			require.Nil(t, err)
+		}
+	}
+
+	// Bounds that allow ROS-style attacks are rejected
+	for _, x := range []struct {
+		scheme Scheme
+		max    int
+	}{{Schnorr, 0}, {Schnorr, 2}, {OkamotoSchnorr, 0}, {OkamotoSchnorr, SafeSessions + 1}} {
+		_, err := NewIssuer(suite, x.scheme, random.Stream, x.max)
+		assert.Equal(t, errorUnsafeSessions, err)
+	}
+	_, err := NewIssuer(suite, Scheme(7), random.Stream, 1)
+	assert.Equal(t, errorScheme, err)
+}
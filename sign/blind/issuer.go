@@ -0,0 +1,221 @@
+package blind
+
+import (
+	"crypto/cipher"
+	"errors"
+	"sync"
+
+	"github.com/dedis/crypto/abstract"
+)
+
+// Some error definitions
+var errorScheme = errors.New("unknown blind signature scheme")
+var errorTooManySessions = errors.New("too many concurrent issuance sessions")
+var errorSession = errors.New("unknown or closed issuance session")
+var errorUnsafeSessions = errors.New("number of concurrent sessions is unsafe for the scheme")
+
+// Scheme selects the blind signature scheme of an issuance.
+type Scheme int
+
+const (
+	// Schnorr is plain blind Schnorr, see Signer.
+	Schnorr Scheme = iota
+	// OkamotoSchnorr is Okamoto–Schnorr, see OkamotoSigner.
+	OkamotoSchnorr
+)
+
+// SafeSessions bounds the number of concurrent Okamoto–Schnorr sessions. With
+// l concurrent sessions, the scheme is proven secure as long as the ROS
+// problem with l sessions is hard (Hauck, Kiltz and Loss, "A Modular
+// Treatment of Blind Signatures from Identification Schemes", EUROCRYPT
+// 2019). Wagner's k-tree algorithm solves ROS from k ≤ l+1 lists of candidate
+// challenges in time about 2^(λ/(1+⌊log2 k⌋)) for λ-bit scalars (Wagner, "A
+// Generalized Birthday Problem", CRYPTO 2002), and Benhamouda et al. solve it
+// in polynomial time once l exceeds λ ("On the (in)security of ROS",
+// EUROCRYPT 2021). For l ≤ 2 at most three lists are available, so the
+// k-tree search is a plain birthday search in time 2^(λ/2), which is no
+// cheaper than computing a discrete logarithm in the group.
+const SafeSessions = 2
+
+// Response is the issuer's answer to a blinded challenge. S2 is only set by
+// Okamoto–Schnorr.
+type Response struct {
+	S  abstract.Scalar
+	S2 abstract.Scalar
+}
+
+// Token is a blind signature obtained through an issuance. S2 is only set by
+// Okamoto–Schnorr.
+type Token struct {
+	C  abstract.Scalar
+	S  abstract.Scalar
+	S2 abstract.Scalar
+}
+
+// Issuer holds an issuing key of a scheme and runs issuance sessions with it.
+// It is safe for concurrent use.
+type Issuer struct {
+	suite  abstract.Suite
+	scheme Scheme
+	x1, x2 abstract.Scalar
+	public abstract.Point
+	max    int
+
+	mu       sync.Mutex
+	sessions map[uint64]*session
+	next     uint64
+}
+
+// session is the signer state of one open issuance.
+type session struct {
+	schnorr *Signer
+	okamoto *OkamotoSigner
+}
+
+// NewIssuer creates an issuer with a fresh key for the given scheme. At most
+// maxSessions sessions may be open at the same time. Plain blind Schnorr is
+// only safe with sequential sessions, so it requires maxSessions 1, whereas
+// OkamotoSchnorr allows up to SafeSessions concurrent sessions. Other values
+// are rejected.
+func NewIssuer(suite abstract.Suite, scheme Scheme, rand cipher.Stream, maxSessions int) (*Issuer, error) {
+	is := &Issuer{suite: suite, scheme: scheme, max: maxSessions, sessions: make(map[uint64]*session)}
+	switch scheme {
+	case Schnorr:
+		if maxSessions != 1 {
+			return nil, errorUnsafeSessions
+		}
+		is.x1 = suite.Scalar().Pick(rand)
+		is.public = suite.Point().Mul(nil, is.x1)
+	case OkamotoSchnorr:
+		if maxSessions < 1 || maxSessions > SafeSessions {
+			return nil, errorUnsafeSessions
+		}
+		is.x1 = suite.Scalar().Pick(rand)
+		is.x2 = suite.Scalar().Pick(rand)
+		is.public = OkamotoPublic(suite, is.x1, is.x2)
+	default:
+		return nil, errorScheme
+	}
+	return is, nil
+}
+
+// Public returns the public key of the issuer.
+func (is *Issuer) Public() abstract.Point {
+	return is.public
+}
+
+// Commit opens a new session and returns its identifier together with the
+// commitment R for the requester. It refuses to open a session while the
+// maximum number of sessions given to NewIssuer is open, which is at most
+// SafeSessions; a session stays open until it is answered or aborted.
+func (is *Issuer) Commit(rand cipher.Stream) (uint64, abstract.Point, error) {
+	is.mu.Lock()
+	defer is.mu.Unlock()
+	if len(is.sessions) >= is.max {
+		return 0, nil, errorTooManySessions
+	}
+	s := new(session)
+	var R abstract.Point
+	if is.scheme == Schnorr {
+		s.schnorr = NewSigner(is.suite, is.x1)
+		R = s.schnorr.Commit(rand)
+	} else {
+		s.okamoto = NewOkamotoSigner(is.suite, is.x1, is.x2)
+		R = s.okamoto.Commit(rand)
+	}
+	id := is.next
+	is.next++
+	is.sessions[id] = s
+	return id, R, nil
+}
+
+// Respond answers the blinded challenge c of session id and closes it.
+func (is *Issuer) Respond(id uint64, c abstract.Scalar) (*Response, error) {
+	is.mu.Lock()
+	s, ok := is.sessions[id]
+	delete(is.sessions, id)
+	is.mu.Unlock()
+	if !ok {
+		return nil, errorSession
+	}
+	if s.schnorr != nil {
+		r, err := s.schnorr.Respond(c)
+		if err != nil {
+			return nil, err
+		}
+		return &Response{S: r}, nil
+	}
+	s1, s2, err := s.okamoto.Respond(c)
+	if err != nil {
+		return nil, err
+	}
+	return &Response{S: s1, S2: s2}, nil
+}
+
+// Abort closes session id without responding, freeing its slot.
+func (is *Issuer) Abort(id uint64) {
+	is.mu.Lock()
+	delete(is.sessions, id)
+	is.mu.Unlock()
+}
+
+// Request is the requester side of an issuance.
+type Request struct {
+	schnorr *Requester
+	okamoto *OkamotoRequester
+}
+
+// NewRequest creates an issuance requesting a token on msg from the issuer
+// with public key X using the given scheme.
+func NewRequest(suite abstract.Suite, scheme Scheme, X abstract.Point, msg []byte) (*Request, error) {
+	switch scheme {
+	case Schnorr:
+		return &Request{schnorr: NewRequester(suite, X, msg)}, nil
+	case OkamotoSchnorr:
+		return &Request{okamoto: NewOkamotoRequester(suite, X, msg)}, nil
+	}
+	return nil, errorScheme
+}
+
+// Challenge blinds the issuer's commitment R and returns the blinded
+// challenge for the issuer.
+func (r *Request) Challenge(R abstract.Point, rand cipher.Stream) (abstract.Scalar, error) {
+	if r.schnorr != nil {
+		return r.schnorr.Challenge(R, rand)
+	}
+	return r.okamoto.Challenge(R, rand)
+}
+
+// Unblind checks the issuer's response and turns it into a token.
+func (r *Request) Unblind(resp *Response) (*Token, error) {
+	if r.schnorr != nil {
+		sig, err := r.schnorr.Unblind(resp.S)
+		if err != nil {
+			return nil, err
+		}
+		return &Token{C: sig.C, S: sig.S}, nil
+	}
+	if resp.S2 == nil {
+		return nil, errorInvalidResponse
+	}
+	sig, err := r.okamoto.Unblind(resp.S, resp.S2)
+	if err != nil {
+		return nil, err
+	}
+	return &Token{C: sig.C, S: sig.S1, S2: sig.S2}, nil
+}
+
+// VerifyToken checks a token on msg issued under the public key X with the
+// given scheme.
+func VerifyToken(suite abstract.Suite, scheme Scheme, X abstract.Point, msg []byte, tok *Token) error {
+	switch scheme {
+	case Schnorr:
+		return Verify(suite, X, msg, &Signature{tok.C, tok.S})
+	case OkamotoSchnorr:
+		if tok.S2 == nil {
+			return errorInvalidSignature
+		}
+		return VerifyOkamoto(suite, X, msg, &OkamotoSignature{tok.C, tok.S, tok.S2})
+	}
+	return errorScheme
+}
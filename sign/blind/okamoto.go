@@ -0,0 +1,151 @@
+package blind
+
+import (
+	"crypto/cipher"
+
+	"github.com/dedis/crypto/abstract"
+)
+
+// Okamoto–Schnorr blind signatures use a second generator H with unknown
+// discrete logarithm to G and a key pair (x1, x2), X = x1G + x2H. The issuance
+// runs in the same three moves as plain blind Schnorr:
+//
+//	signer:    R := signer.Commit(rand)                  // R = k1G + k2H
+//	requester: c := requester.Challenge(R, rand)
+//	signer:    s1, s2 := signer.Respond(c)               // si = ki + c*xi
+//	requester: sig := requester.Unblind(s1, s2)
+//
+// Since the signer's key has many representations, the scheme is proven
+// secure for a bounded number of concurrent sessions, unlike plain blind
+// Schnorr which is only proven secure for sequential sessions. See Issuer for
+// a session manager that enforces such a bound.
+
+// OkamotoSignature is a (unblinded) Okamoto–Schnorr signature (c, s1, s2)
+// satisfying c == H(s1G + s2H - cX, X, msg).
+type OkamotoSignature struct {
+	C  abstract.Scalar // Challenge
+	S1 abstract.Scalar // Response with respect to G
+	S2 abstract.Scalar // Response with respect to H
+}
+
+// OkamotoBase returns the second generator H of Okamoto–Schnorr signatures.
+func OkamotoBase(suite abstract.Suite) abstract.Point {
+	H, _ := suite.Point().Pick(nil, suite.Cipher([]byte("blind-okamoto-H")))
+	return H
+}
+
+// OkamotoPublic returns the public key x1G + x2H of the key pair (x1, x2).
+func OkamotoPublic(suite abstract.Suite, x1, x2 abstract.Scalar) abstract.Point {
+	X := suite.Point().Mul(nil, x1)
+	return X.Add(X, suite.Point().Mul(OkamotoBase(suite), x2))
+}
+
+// OkamotoSigner is the issuer side of a single Okamoto–Schnorr blind
+// signature session.
+type OkamotoSigner struct {
+	suite  abstract.Suite
+	x1, x2 abstract.Scalar
+	Public abstract.Point
+	k1, k2 abstract.Scalar
+}
+
+// NewOkamotoSigner creates a new signing session for the private key
+// (x1, x2). An OkamotoSigner must be used for one session only.
+func NewOkamotoSigner(suite abstract.Suite, x1, x2 abstract.Scalar) *OkamotoSigner {
+	return &OkamotoSigner{suite: suite, x1: x1, x2: x2, Public: OkamotoPublic(suite, x1, x2)}
+}
+
+// Commit picks the session nonces k1, k2 and returns the commitment
+// R = k1G + k2H.
+func (s *OkamotoSigner) Commit(rand cipher.Stream) abstract.Point {
+	s.k1 = s.suite.Scalar().Pick(rand)
+	s.k2 = s.suite.Scalar().Pick(rand)
+	R := s.suite.Point().Mul(nil, s.k1)
+	return R.Add(R, s.suite.Point().Mul(OkamotoBase(s.suite), s.k2))
+}
+
+// Respond computes the responses k1 + cx1 and k2 + cx2 to the blinded
+// challenge c. The nonces are erased afterwards so that a second call fails.
+func (s *OkamotoSigner) Respond(c abstract.Scalar) (abstract.Scalar, abstract.Scalar, error) {
+	if s.k1 == nil {
+		return nil, nil, errorState
+	}
+	s1 := s.suite.Scalar().Mul(c, s.x1)
+	s1.Add(s1, s.k1)
+	s2 := s.suite.Scalar().Mul(c, s.x2)
+	s2.Add(s2, s.k2)
+	s.k1.Zero()
+	s.k2.Zero()
+	s.k1, s.k2 = nil, nil
+	return s1, s2, nil
+}
+
+// OkamotoRequester is the user side of an Okamoto–Schnorr blind signature
+// session.
+type OkamotoRequester struct {
+	suite   abstract.Suite
+	X       abstract.Point // Public key of the issuer
+	msg     []byte
+	R       abstract.Point
+	a, b, d abstract.Scalar
+	c, cb   abstract.Scalar
+}
+
+// NewOkamotoRequester creates a session requesting a blind signature on msg
+// under the issuer's public key X.
+func NewOkamotoRequester(suite abstract.Suite, X abstract.Point, msg []byte) *OkamotoRequester {
+	return &OkamotoRequester{suite: suite, X: X, msg: msg}
+}
+
+// Challenge blinds the signer's commitment R to R' = R + aG + bH + dX,
+// computes the signature challenge c' = H(R', X, msg) and returns the blinded
+// challenge c = c' + d that is sent to the signer.
+func (r *OkamotoRequester) Challenge(R abstract.Point, rand cipher.Stream) (abstract.Scalar, error) {
+	r.R = R
+	r.a = r.suite.Scalar().Pick(rand)
+	r.b = r.suite.Scalar().Pick(rand)
+	r.d = r.suite.Scalar().Pick(rand)
+	Rb := r.suite.Point().Add(R, r.suite.Point().Mul(nil, r.a))
+	Rb.Add(Rb, r.suite.Point().Mul(OkamotoBase(r.suite), r.b))
+	Rb.Add(Rb, r.suite.Point().Mul(r.X, r.d))
+	c, err := challenge(r.suite, Rb, r.X, r.msg)
+	if err != nil {
+		return nil, err
+	}
+	r.c = c
+	r.cb = r.suite.Scalar().Add(c, r.d)
+	return r.cb, nil
+}
+
+// Unblind checks the signer's responses against the commitment and the
+// blinded challenge and turns them into a signature (c', s1 + a, s2 + b).
+func (r *OkamotoRequester) Unblind(s1, s2 abstract.Scalar) (*OkamotoSignature, error) {
+	if r.cb == nil {
+		return nil, errorState
+	}
+	// s1G + s2H == R + cX
+	lhs := r.suite.Point().Mul(nil, s1)
+	lhs.Add(lhs, r.suite.Point().Mul(OkamotoBase(r.suite), s2))
+	rhs := r.suite.Point().Add(r.R, r.suite.Point().Mul(r.X, r.cb))
+	if !lhs.Equal(rhs) {
+		return nil, errorInvalidResponse
+	}
+	return &OkamotoSignature{r.c, r.suite.Scalar().Add(s1, r.a), r.suite.Scalar().Add(s2, r.b)}, nil
+}
+
+// VerifyOkamoto checks an Okamoto–Schnorr signature on msg under the public
+// key X.
+func VerifyOkamoto(suite abstract.Suite, X abstract.Point, msg []byte, sig *OkamotoSignature) error {
+	// R' = s1G + s2H - cX
+	R := suite.Point().Mul(nil, sig.S1)
+	R.Add(R, suite.Point().Mul(OkamotoBase(suite), sig.S2))
+	R.Sub(R, suite.Point().Mul(X, sig.C))
+	c, err := challenge(suite, R, X, msg)
+	if err != nil {
+		return err
+	}
+	if !c.Equal(sig.C) {
+		return errorInvalidSignature
+	}
+	return nil
+}
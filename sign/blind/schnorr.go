@@ -0,0 +1,149 @@
+// Package blind implements blind Schnorr signatures for the issuance of
+// anonymous tokens (e-cash, privacy-pass style). The signer (issuer) signs a
+// message chosen by the requester without learning the message or being able
+// to link the resulting signature to the issuance session. Besides the single
+// signer variant, the package provides a threshold variant in which a
+// committee holding Shamir shares of the issuing key jointly produces a blind
+// signature, see ThresholdSigner.
+//
+// Note that plain blind Schnorr signatures are vulnerable to ROS-style attacks
+// when an issuer runs many sessions concurrently, so issuers should either
+// serialize sessions or use Okamoto–Schnorr blind signatures with a bounded
+// number of concurrent sessions. Issuer runs either scheme behind one API.
+//
+// The issuance runs in three moves:
+//
+//	signer:    R := signer.Commit(rand)
+//	requester: c := requester.Challenge(R, rand)
+//	signer:    s := signer.Respond(c)
+//	requester: sig := requester.Unblind(s)
+//
+// and the resulting signature is checked with Verify.
+package blind
+
+import (
+	"crypto/cipher"
+	"errors"
+
+	"github.com/dedis/crypto/abstract"
+)
+
+// Some error definitions
+var errorState = errors.New("protocol step called out of order")
+var errorInvalidResponse = errors.New("invalid blind signature response")
+var errorInvalidSignature = errors.New("invalid signature")
+
+// Signature is a (unblinded) Schnorr signature (c, s) satisfying
+// c == H(sG - cX, X, msg).
+type Signature struct {
+	C abstract.Scalar // Challenge
+	S abstract.Scalar // Response
+}
+
+// Signer is the issuer side of a single blind signature session.
+type Signer struct {
+	suite  abstract.Suite
+	secret abstract.Scalar
+	Public abstract.Point
+	k      abstract.Scalar
+}
+
+// NewSigner creates a new signing session for the given private key. A Signer
+// must be used for one session only.
+func NewSigner(suite abstract.Suite, secret abstract.Scalar) *Signer {
+	return &Signer{suite: suite, secret: secret, Public: suite.Point().Mul(nil, secret)}
+}
+
+// Commit picks the session nonce k and returns the commitment R = kG.
+func (s *Signer) Commit(rand cipher.Stream) abstract.Point {
+	s.k = s.suite.Scalar().Pick(rand)
+	return s.suite.Point().Mul(nil, s.k)
+}
+
+// Respond computes the response k + cx to the blinded challenge c. The nonce
+// is erased afterwards so that a second call fails instead of leaking the key.
+func (s *Signer) Respond(c abstract.Scalar) (abstract.Scalar, error) {
+	if s.k == nil {
+		return nil, errorState
+	}
+	r := s.suite.Scalar().Mul(c, s.secret)
+	r.Add(r, s.k)
+	s.k.Zero()
+	s.k = nil
+	return r, nil
+}
+
+// Requester is the user side of a blind signature session.
+type Requester struct {
+	suite abstract.Suite
+	X     abstract.Point // Public key of the issuer
+	msg   []byte
+	R     abstract.Point
+	a, b  abstract.Scalar
+	c, cb abstract.Scalar
+}
+
+// NewRequester creates a session requesting a blind signature on msg under the
+// issuer's public key X.
+func NewRequester(suite abstract.Suite, X abstract.Point, msg []byte) *Requester {
+	return &Requester{suite: suite, X: X, msg: msg}
+}
+
+// Challenge blinds the signer's commitment R to R' = R + aG + bX, computes
+// the signature challenge c' = H(R', X, msg) and returns the blinded
+// challenge c = c' + b that is sent to the signer.
+func (r *Requester) Challenge(R abstract.Point, rand cipher.Stream) (abstract.Scalar, error) {
+	r.R = R
+	r.a = r.suite.Scalar().Pick(rand)
+	r.b = r.suite.Scalar().Pick(rand)
+	Rb := r.suite.Point().Add(R, r.suite.Point().Mul(nil, r.a))
+	Rb.Add(Rb, r.suite.Point().Mul(r.X, r.b))
+	c, err := challenge(r.suite, Rb, r.X, r.msg)
+	if err != nil {
+		return nil, err
+	}
+	r.c = c
+	r.cb = r.suite.Scalar().Add(c, r.b)
+	return r.cb, nil
+}
+
+// Unblind checks the signer's response s against the commitment and the
+// blinded challenge and turns it into a signature (c', s + a) on the message.
+func (r *Requester) Unblind(s abstract.Scalar) (*Signature, error) {
+	if r.cb == nil {
+		return nil, errorState
+	}
+	// sG == R + cX
+	lhs := r.suite.Point().Mul(nil, s)
+	rhs := r.suite.Point().Add(r.R, r.suite.Point().Mul(r.X, r.cb))
+	if !lhs.Equal(rhs) {
+		return nil, errorInvalidResponse
+	}
+	return &Signature{r.c, r.suite.Scalar().Add(s, r.a)}, nil
+}
+
+// Verify checks a signature on msg under the public key X.
+func Verify(suite abstract.Suite, X abstract.Point, msg []byte, sig *Signature) error {
+	// R' = sG - cX
+	R := suite.Point().Sub(suite.Point().Mul(nil, sig.S), suite.Point().Mul(X, sig.C))
+	c, err := challenge(suite, R, X, msg)
+	if err != nil {
+		return err
+	}
+	if !c.Equal(sig.C) {
+		return errorInvalidSignature
+	}
+	return nil
+}
+
+func challenge(suite abstract.Suite, R, X abstract.Point, msg []byte) (abstract.Scalar, error) {
+	h := suite.Hash()
+	if _, err := R.MarshalTo(h); err != nil {
+		return nil, err
+	}
+	if _, err := X.MarshalTo(h); err != nil {
+		return nil, err
+	}
+	h.Write(msg)
+	return suite.Scalar().Pick(suite.Cipher(h.Sum(nil))), nil
+}
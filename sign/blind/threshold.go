@@ -0,0 +1,175 @@
+package blind
+
+import (
+	"crypto/cipher"
+	"errors"
+	"fmt"
+
+	"github.com/dedis/crypto/abstract"
+	"github.com/dedis/crypto/share"
+)
+
+// Some error definitions
+var errorSignerSet = errors.New("invalid signer set")
+var errorTooFewPartials = errors.New("not enough valid partial responses")
+
+// PartialCommitment is a signer's share R_i = k_iG of the joint commitment.
+type PartialCommitment struct {
+	I int            // Index of the signer's share
+	R abstract.Point // Commitment of the signer
+}
+
+// PartialResponse is a signer's share s_i = k_i + c*l_i*x_i of the joint
+// response, where l_i is the signer's Lagrange coefficient within the
+// signer set.
+type PartialResponse struct {
+	I int             // Index of the signer's share
+	S abstract.Scalar // Partial response
+}
+
+// ThresholdSigner is one committee member of a threshold blind signature
+// session. The committee's public key is the constant term of the public
+// commitment polynomial of the key shares.
+type ThresholdSigner struct {
+	suite abstract.Suite
+	share *share.PriShare
+	k     abstract.Scalar
+}
+
+// NewThresholdSigner creates a new signing session for the holder of the
+// private key share.
+func NewThresholdSigner(suite abstract.Suite, share *share.PriShare) *ThresholdSigner {
+	return &ThresholdSigner{suite: suite, share: share}
+}
+
+// Commit picks the session nonce and returns the partial commitment.
+func (s *ThresholdSigner) Commit(rand cipher.Stream) *PartialCommitment {
+	s.k = s.suite.Scalar().Pick(rand)
+	return &PartialCommitment{s.share.I, s.suite.Point().Mul(nil, s.k)}
+}
+
+// Respond computes the partial response to the blinded challenge c for the
+// given set of signer indices, which must contain the signer's own index.
+func (s *ThresholdSigner) Respond(c abstract.Scalar, signers []int) (*PartialResponse, error) {
+	if s.k == nil {
+		return nil, errorState
+	}
+	l, err := lagrange(s.suite, s.share.I, signers)
+	if err != nil {
+		return nil, err
+	}
+	r := s.suite.Scalar().Mul(c, l)
+	r.Mul(r, s.share.V)
+	r.Add(r, s.k)
+	s.k.Zero()
+	s.k = nil
+	return &PartialResponse{s.share.I, r}, nil
+}
+
+// Signers returns the indices of the given partial commitments.
+func Signers(commits []*PartialCommitment) []int {
+	signers := make([]int, len(commits))
+	for i, c := range commits {
+		signers[i] = c.I
+	}
+	return signers
+}
+
+// AggregateCommitments combines the partial commitments into the joint
+// commitment R which the requester blinds.
+func AggregateCommitments(suite abstract.Suite, commits []*PartialCommitment) (abstract.Point, error) {
+	if err := checkSigners(Signers(commits)); err != nil {
+		return nil, err
+	}
+	R := suite.Point().Null()
+	for _, c := range commits {
+		R.Add(R, c.R)
+	}
+	return R, nil
+}
+
+// VerifyPartial checks a partial response against the signer's partial
+// commitment and its public key share taken from pubPoly, i.e., it checks
+// s_iG == R_i + c*l_i*X_i. This lets an aggregator identify misbehaving
+// signers before combining the responses.
+func VerifyPartial(suite abstract.Suite, pubPoly *share.PubPoly, c abstract.Scalar, signers []int, commit *PartialCommitment, partial *PartialResponse) error {
+	if commit.I != partial.I {
+		return errorSignerSet
+	}
+	l, err := lagrange(suite, partial.I, signers)
+	if err != nil {
+		return err
+	}
+	Xi := pubPoly.Eval(partial.I).V
+	cl := suite.Scalar().Mul(c, l)
+	rhs := suite.Point().Add(commit.R, suite.Point().Mul(Xi, cl))
+	if !suite.Point().Mul(nil, partial.S).Equal(rhs) {
+		return fmt.Errorf("invalid partial response from signer %d", partial.I)
+	}
+	return nil
+}
+
+// CombinePartials verifies the partial responses and sums them up into the
+// joint response to be unblinded by the requester. commits and partials must
+// be given in the same order. It returns the indices of all signers whose
+// partial response failed to verify together with an error if any did, since
+// a missing response cannot be compensated for within a fixed signer set.
+func CombinePartials(suite abstract.Suite, pubPoly *share.PubPoly, c abstract.Scalar, commits []*PartialCommitment, partials []*PartialResponse) (abstract.Scalar, []int, error) {
+	if len(commits) != len(partials) {
+		return nil, nil, errorTooFewPartials
+	}
+	signers := Signers(commits)
+	if err := checkSigners(signers); err != nil {
+		return nil, nil, err
+	}
+	var bad []int
+	s := suite.Scalar().Zero()
+	for i, p := range partials {
+		if p == nil || VerifyPartial(suite, pubPoly, c, signers, commits[i], p) != nil {
+			bad = append(bad, commits[i].I)
+			continue
+		}
+		s.Add(s, p.S)
+	}
+	if len(bad) > 0 {
+		return nil, bad, errorTooFewPartials
+	}
+	return s, nil, nil
+}
+
+func checkSigners(signers []int) error {
+	if len(signers) == 0 {
+		return errorSignerSet
+	}
+	seen := make(map[int]bool)
+	for _, i := range signers {
+		if i < 0 || seen[i] {
+			return errorSignerSet
+		}
+		seen[i] = true
+	}
+	return nil
+}
+
+// lagrange returns the Lagrange coefficient of the signer with index i in
+// the signing set.
+func lagrange(suite abstract.Suite, i int, signers []int) (abstract.Scalar, error) {
+	if err := checkSigners(signers); err != nil {
+		return nil, err
+	}
+	n := 0
+	for _, j := range signers {
+		if j >= n {
+			n = j + 1
+		}
+	}
+	ip, err := share.NewInterpolator(suite, signers, n)
+	if err != nil {
+		return nil, err
+	}
+	l := ip.Coefficient(i)
+	if l == nil {
+		return nil, errorSignerSet
+	}
+	return l, nil
+}
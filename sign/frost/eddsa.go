@@ -0,0 +1,31 @@
+package frost
+
+import (
+	"github.com/dedis/crypto/abstract"
+	"github.com/dedis/crypto/share"
+)
+
+// The *EdDSA functions run the same protocol but produce signatures in the
+// Ed25519 format of RFC 8032, i.e., the encoding of R followed by the
+// encoding of the response, with the challenge derived by SHA-512. With an
+// Ed25519 suite the signatures verify with eddsa.Verify and with any standard
+// Ed25519 implementation, so that a committee can sign wherever Ed25519 is
+// accepted, e.g. in X.509 certificates. Nonces and commitments are the same as
+// for the default variant, but must not be reused across variants either.
+
+// SignEdDSA is like Sign for Ed25519 signatures.
+func SignEdDSA(suite abstract.Suite, Y abstract.Point, priShare *share.PriShare, nonce *Nonce, msg []byte, commits []*Commitment) (*Partial, error) {
+	return signPartial(suite, Y, priShare, nonce, msg, commits, eddsaVariant)
+}
+
+// VerifyPartialEdDSA is like VerifyPartial for partial signatures created
+// with SignEdDSA.
+func VerifyPartialEdDSA(suite abstract.Suite, pubPoly *share.PubPoly, msg []byte, commits []*Commitment, partial *Partial) error {
+	return verifyPartial(suite, pubPoly, msg, commits, partial, eddsaVariant)
+}
+
+// AggregateEdDSA is like Aggregate for partial signatures created with
+// SignEdDSA and returns an Ed25519 signature.
+func AggregateEdDSA(suite abstract.Suite, pubPoly *share.PubPoly, msg []byte, commits []*Commitment, partials []*Partial) ([]byte, error) {
+	return aggregate(suite, pubPoly, msg, commits, partials, eddsaVariant)
+}
@@ -0,0 +1,297 @@
+// Package frost implements FROST, the two-round threshold Schnorr signature
+// scheme of Komlo and Goldberg. Holders of Shamir shares of a signing key
+// jointly produce a signature that verifies with sign.VerifySchnorr against the
+// group's public key:
+//
+//	each signer:  nonce, commit := NewNonce(suite, share.I, rand)  // round 1
+//	each signer:  partial := Sign(suite, Y, share, nonce, msg, commits) // round 2
+//	aggregator:   sig := Aggregate(suite, pubPoly, msg, commits, partials)
+//
+// The aggregator checks every partial signature with VerifyPartial before
+// combining them; misbehaving signers are reported through a BlameError.
+//
+// SignEdDSA, VerifyPartialEdDSA and AggregateEdDSA produce standard Ed25519
+// signatures instead.
+//
+// A Manager runs the signing sessions of one signer: it keeps the nonces of
+// concurrent sessions apart, persists them such that they are never used
+// twice, even across restarts, and aborts sessions that time out.
+package frost
+
+import (
+	"bytes"
+	"crypto/cipher"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/dedis/crypto/abstract"
+	"github.com/dedis/crypto/share"
+	"github.com/dedis/crypto/sign"
+)
+
+// Errors returned by this package, possibly wrapped in a BlameError. Use
+// errors.Is to test for them and errors.As to retrieve the blamed signers.
+var (
+	// ErrInvalidPartial is returned for partial signatures that fail to
+	// verify.
+	ErrInvalidPartial = errors.New("invalid partial signature")
+	// ErrMissingCommitment is returned for signers without a nonce
+	// commitment in the signing set.
+	ErrMissingCommitment = errors.New("missing nonce commitment")
+	// ErrSignerSet is returned for empty signing sets or sets with
+	// duplicate or negative indices.
+	ErrSignerSet = errors.New("invalid signer set")
+	// ErrNonceUsed is returned when a nonce is used a second time.
+	ErrNonceUsed = errors.New("nonce already used")
+)
+
+// BlameError identifies the signers that caused an error.
+type BlameError struct {
+	Signers []int // Indices of the misbehaving signers
+	Err     error // Underlying error
+}
+
+func (e *BlameError) Error() string {
+	return fmt.Sprintf("frost: signers %v: %v", e.Signers, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *BlameError) Unwrap() error {
+	return e.Err
+}
+
+// Nonce is the secret nonce pair (d, e) of a signer for one signature. It must
+// never be used twice; Sign erases it.
+type Nonce struct {
+	I    int // Index of the signer
+	d, e abstract.Scalar
+}
+
+// Commitment is the public commitment (D, E) = (dG, eG) to a signer's nonce.
+type Commitment struct {
+	I int            // Index of the signer
+	D abstract.Point // Commitment to the hiding nonce
+	E abstract.Point // Commitment to the binding nonce
+}
+
+// Partial is a signer's share z_i of the signature's response.
+type Partial struct {
+	I int             // Index of the signer
+	Z abstract.Scalar // Partial response
+}
+
+// NewNonce picks a fresh nonce for the signer with the given share index and
+// returns it together with the commitment to publish in round one.
+func NewNonce(suite abstract.Suite, index int, rand cipher.Stream) (*Nonce, *Commitment) {
+	n := &Nonce{I: index, d: suite.Scalar().Pick(rand), e: suite.Scalar().Pick(rand)}
+	return n, &Commitment{index, suite.Point().Mul(nil, n.d), suite.Point().Mul(nil, n.e)}
+}
+
+// Sign computes the partial signature on msg under the group's public key Y
+// of the holder of the private key share, given the commitments of all
+// signers including its own. The nonce is erased afterwards.
+func Sign(suite abstract.Suite, Y abstract.Point, priShare *share.PriShare, nonce *Nonce, msg []byte, commits []*Commitment) (*Partial, error) {
+	return signPartial(suite, Y, priShare, nonce, msg, commits, schnorrVariant)
+}
+
+func signPartial(suite abstract.Suite, Y abstract.Point, priShare *share.PriShare, nonce *Nonce, msg []byte, commits []*Commitment, v variant) (*Partial, error) {
+	if nonce.d == nil {
+		return nil, ErrNonceUsed
+	}
+	sess, err := newSession(suite, Y, msg, commits, v)
+	if err != nil {
+		return nil, err
+	}
+	C, ok := sess.commits[priShare.I]
+	if !ok || nonce.I != priShare.I || !C.D.Equal(suite.Point().Mul(nil, nonce.d)) || !C.E.Equal(suite.Point().Mul(nil, nonce.e)) {
+		return nil, &BlameError{[]int{priShare.I}, ErrMissingCommitment}
+	}
+	// z_i = d_i + rho_i*e_i - c*l_i*x_i
+	z := suite.Scalar().Mul(nonce.e, sess.rho[priShare.I])
+	z.Add(z, nonce.d)
+	clx := suite.Scalar().Mul(sess.c, sess.ip.Coefficient(priShare.I))
+	clx.Mul(clx, priShare.V)
+	z.Sub(z, clx)
+	nonce.d.Zero()
+	nonce.e.Zero()
+	nonce.d, nonce.e = nil, nil
+	return &Partial{priShare.I, z}, nil
+}
+
+// VerifyPartial checks the partial signature of a signer on msg against its
+// public key share taken from pubPoly and the commitments of the signing set,
+// i.e., it checks z_iG + c*l_i*X_i == D_i + rho_i*E_i. On failure it returns a
+// BlameError naming the signer.
+func VerifyPartial(suite abstract.Suite, pubPoly *share.PubPoly, msg []byte, commits []*Commitment, partial *Partial) error {
+	return verifyPartial(suite, pubPoly, msg, commits, partial, schnorrVariant)
+}
+
+func verifyPartial(suite abstract.Suite, pubPoly *share.PubPoly, msg []byte, commits []*Commitment, partial *Partial, v variant) error {
+	sess, err := newSession(suite, pubPoly.Commit(), msg, commits, v)
+	if err != nil {
+		return err
+	}
+	return sess.verify(pubPoly, partial)
+}
+
+// Aggregate verifies the partial signatures of the signing set and combines
+// them into a Schnorr signature on msg that verifies under the group's public
+// key pubPoly.Commit() with sign.VerifySchnorr. If any partial signature is
+// invalid or missing, it returns a BlameError naming all culprits.
+func Aggregate(suite abstract.Suite, pubPoly *share.PubPoly, msg []byte, commits []*Commitment, partials []*Partial) ([]byte, error) {
+	return aggregate(suite, pubPoly, msg, commits, partials, schnorrVariant)
+}
+
+func aggregate(suite abstract.Suite, pubPoly *share.PubPoly, msg []byte, commits []*Commitment, partials []*Partial, v variant) ([]byte, error) {
+	sess, err := newSession(suite, pubPoly.Commit(), msg, commits, v)
+	if err != nil {
+		return nil, err
+	}
+	byIndex := make(map[int]*Partial)
+	for _, p := range partials {
+		if p != nil {
+			byIndex[p.I] = p
+		}
+	}
+	var bad []int
+	z := suite.Scalar().Zero()
+	for _, i := range sess.signers {
+		p, ok := byIndex[i]
+		if !ok || sess.verify(pubPoly, p) != nil {
+			bad = append(bad, i)
+			continue
+		}
+		z.Add(z, p.Z)
+	}
+	if len(bad) > 0 {
+		return nil, &BlameError{bad, ErrInvalidPartial}
+	}
+	var b bytes.Buffer
+	first := abstract.Marshaling(sess.c)
+	if v == eddsaVariant {
+		first = sess.R
+	}
+	if _, err := first.MarshalTo(&b); err != nil {
+		return nil, err
+	}
+	if _, err := z.MarshalTo(&b); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// variant selects the signature format.
+type variant int
+
+const (
+	// schnorrVariant produces (c, z) signatures with c = H(R, Y, msg) and
+	// R = zG + cY as checked by sign.VerifySchnorr.
+	schnorrVariant variant = iota
+	// eddsaVariant produces (R, z) signatures with c = SHA-512(R, Y, msg)
+	// and zG = R + cY as checked by Ed25519 verifiers.
+	eddsaVariant
+)
+
+// session holds the values of a signing set that all signers and the
+// aggregator derive from the group key, the message and the commitments.
+type session struct {
+	suite   abstract.Suite
+	commits map[int]*Commitment
+	signers []int // sorted signer indices
+	ip      *share.Interpolator
+	rho     map[int]abstract.Scalar
+	R       abstract.Point  // group commitment
+	c       abstract.Scalar // challenge, negated for eddsaVariant
+}
+
+func newSession(suite abstract.Suite, Y abstract.Point, msg []byte, commits []*Commitment, v variant) (*session, error) {
+	if len(commits) == 0 {
+		return nil, ErrSignerSet
+	}
+	s := &session{suite: suite, commits: make(map[int]*Commitment), rho: make(map[int]abstract.Scalar)}
+	for _, C := range commits {
+		if C == nil || C.I < 0 || s.commits[C.I] != nil {
+			return nil, ErrSignerSet
+		}
+		s.commits[C.I] = C
+		s.signers = append(s.signers, C.I)
+	}
+	sort.Ints(s.signers)
+	ip, err := share.NewInterpolator(suite, s.signers, s.signers[len(s.signers)-1]+1)
+	if err != nil {
+		return nil, err
+	}
+	s.ip = ip
+
+	// Encoding of the group key and the commitment list bound into every
+	// binding factor
+	var B bytes.Buffer
+	if _, err := Y.MarshalTo(&B); err != nil {
+		return nil, err
+	}
+	for _, i := range s.signers {
+		binary.Write(&B, binary.BigEndian, uint32(i))
+		if _, err := s.commits[i].D.MarshalTo(&B); err != nil {
+			return nil, err
+		}
+		if _, err := s.commits[i].E.MarshalTo(&B); err != nil {
+			return nil, err
+		}
+	}
+
+	// Binding factors rho_i = H(i, msg, Y, B) and group commitment
+	// R = sum D_i + rho_i*E_i
+	R := suite.Point().Null()
+	for _, i := range s.signers {
+		h := suite.Hash()
+		h.Write([]byte("frost-rho"))
+		binary.Write(h, binary.BigEndian, uint32(i))
+		binary.Write(h, binary.BigEndian, uint64(len(msg)))
+		h.Write(msg)
+		h.Write(B.Bytes())
+		s.rho[i] = suite.Scalar().SetBytes(h.Sum(nil))
+		R.Add(R, s.commits[i].D)
+		R.Add(R, suite.Point().Mul(s.commits[i].E, s.rho[i]))
+	}
+
+	// Challenge c = H(R, Y, msg) as in sign.Schnorr. Ed25519 uses SHA-512
+	// and adds rather than subtracts c*x in the response, which amounts to
+	// negating the challenge.
+	h := suite.Hash()
+	if v == eddsaVariant {
+		h = sha512.New()
+	}
+	c, err := sign.ChallengeHash(suite, h, Y, R, msg)
+	if err != nil {
+		return nil, err
+	}
+	s.R = R
+	s.c = c
+	if v == eddsaVariant {
+		s.c.Neg(s.c)
+	}
+	return s, nil
+}
+
+func (s *session) verify(pubPoly *share.PubPoly, p *Partial) error {
+	if p == nil {
+		return ErrInvalidPartial
+	}
+	C, ok := s.commits[p.I]
+	if !ok {
+		return &BlameError{[]int{p.I}, ErrMissingCommitment}
+	}
+	// z_iG + c*l_i*X_i == D_i + rho_i*E_i
+	Xi := pubPoly.Eval(p.I).V
+	cl := s.suite.Scalar().Mul(s.c, s.ip.Coefficient(p.I))
+	lhs := s.suite.Point().Mul(nil, p.Z)
+	lhs.Add(lhs, s.suite.Point().Mul(Xi, cl))
+	rhs := s.suite.Point().Add(C.D, s.suite.Point().Mul(C.E, s.rho[p.I]))
+	if !lhs.Equal(rhs) {
+		return &BlameError{[]int{p.I}, ErrInvalidPartial}
+	}
+	return nil
+}
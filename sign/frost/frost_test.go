@@ -0,0 +1,257 @@
+package frost
+
+import (
+	stded25519 "crypto/ed25519"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dedis/crypto/ed25519"
+	"github.com/dedis/crypto/eddsa"
+	"github.com/dedis/crypto/edwards"
+	"github.com/dedis/crypto/random"
+	"github.com/dedis/crypto/share"
+	"github.com/dedis/crypto/sign"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var suite = edwards.NewAES128SHA256Ed25519(false)
+
+func setup(n, t int) ([]*share.PriShare, *share.PubPoly) {
+	priPoly := share.NewPriPoly(suite, t, nil, random.Stream)
+	return priPoly.Shares(n), priPoly.Commit(nil)
+}
+
+func round(signers []int, shares []*share.PriShare, pubPoly *share.PubPoly, msg []byte) ([]*Commitment, []*Partial, error) {
+	nonces := make([]*Nonce, len(signers))
+	commits := make([]*Commitment, len(signers))
+	for k, i := range signers {
+		nonces[k], commits[k] = NewNonce(suite, i, random.Stream)
+	}
+	partials := make([]*Partial, len(signers))
+	for k, i := range signers {
+		p, err := Sign(suite, pubPoly.Commit(), shares[i], nonces[k], msg, commits)
+		if err != nil {
+			return nil, nil, err
+		}
+		partials[k] = p
+	}
+	return commits, partials, nil
+}
+
+func TestFROST(t *testing.T) {
+	n, th := 7, 4
+	shares, pubPoly := setup(n, th)
+	msg := []byte("hello frost")
+
+	commits, partials, err := round([]int{6, 0, 3, 2}, shares, pubPoly, msg)
+	require.Nil(t, err)
+	for _, p := range partials {
+		assert.Nil(t, VerifyPartial(suite, pubPoly, msg, commits, p))
+	}
+	sig, err := Aggregate(suite, pubPoly, msg, commits, partials)
+	require.Nil(t, err)
+	assert.Nil(t, sign.VerifySchnorr(suite, pubPoly.Commit(), msg, sig))
+	assert.NotNil(t, sign.VerifySchnorr(suite, pubPoly.Commit(), []byte("other"), sig))
+
+	// Too few signers produce an invalid signature
+	commits, partials, err = round([]int{1, 5, 3}, shares, pubPoly, msg)
+	require.Nil(t, err)
+	sig, err = Aggregate(suite, pubPoly, msg, commits, partials)
+	require.Nil(t, err)
+	assert.NotNil(t, sign.VerifySchnorr(suite, pubPoly.Commit(), msg, sig))
+}
+
+func TestFROSTBlame(t *testing.T) {
+	n, th := 5, 3
+	shares, pubPoly := setup(n, th)
+	msg := []byte("hello frost")
+
+	commits, partials, err := round([]int{0, 1, 2, 4}, shares, pubPoly, msg)
+	require.Nil(t, err)
+	partials[1].Z = suite.Scalar().Pick(random.Stream)
+	partials[3].Z = partials[2].Z
+
+	var blame *BlameError
+	err = VerifyPartial(suite, pubPoly, msg, commits, partials[1])
+	require.True(t, errors.As(err, &blame))
+	assert.Equal(t, []int{1}, blame.Signers)
+	assert.True(t, errors.Is(err, ErrInvalidPartial))
+	assert.Nil(t, VerifyPartial(suite, pubPoly, msg, commits, partials[0]))
+	assert.NotNil(t, VerifyPartial(suite, pubPoly, []byte("other"), commits, partials[0]))
+
+	_, err = Aggregate(suite, pubPoly, msg, commits, partials)
+	require.True(t, errors.As(err, &blame))
+	assert.Equal(t, []int{1, 4}, blame.Signers)
+
+	// A missing partial signature is blamed on its signer
+	_, err = Aggregate(suite, pubPoly, msg, commits, partials[:1])
+	require.True(t, errors.As(err, &blame))
+	assert.Equal(t, []int{1, 2, 4}, blame.Signers)
+
+	// A partial from outside the signing set has no commitment
+	p := &Partial{3, suite.Scalar().Zero()}
+	err = VerifyPartial(suite, pubPoly, msg, commits, p)
+	assert.True(t, errors.Is(err, ErrMissingCommitment))
+}
+
+func TestFROSTNonce(t *testing.T) {
+	shares, pubPoly := setup(3, 2)
+	msg := []byte("msg")
+	n0, c0 := NewNonce(suite, 0, random.Stream)
+	_, c1 := NewNonce(suite, 1, random.Stream)
+	commits := []*Commitment{c0, c1}
+	_, err := Sign(suite, pubPoly.Commit(), shares[0], n0, msg, commits)
+	require.Nil(t, err)
+	_, err = Sign(suite, pubPoly.Commit(), shares[0], n0, msg, commits)
+	assert.Equal(t, ErrNonceUsed, err)
+
+	// Duplicate signers and foreign nonces are rejected
+	n2, _ := NewNonce(suite, 2, random.Stream)
+	_, err = Sign(suite, pubPoly.Commit(), shares[2], n2, msg, commits)
+	assert.True(t, errors.Is(err, ErrMissingCommitment))
+	_, err = Aggregate(suite, pubPoly, msg, []*Commitment{c0, c0}, nil)
+	assert.Equal(t, ErrSignerSet, err)
+
+	// A commitment whose binding nonce differs from the signer's is rejected
+	n0, c0 = NewNonce(suite, 0, random.Stream)
+	bad := &Commitment{0, c0.D, suite.Point().Mul(nil, suite.Scalar().Pick(random.Stream))}
+	_, err = Sign(suite, pubPoly.Commit(), shares[0], n0, msg, []*Commitment{bad, c1})
+	assert.True(t, errors.Is(err, ErrMissingCommitment))
+}
+
+func TestFROSTEdDSA(t *testing.T) {
+	suite := ed25519.NewAES128SHA256Ed25519(false)
+	n, th := 5, 3
+	priPoly := share.NewPriPoly(suite, th, nil, random.Stream)
+	shares, pubPoly := priPoly.Shares(n), priPoly.Commit(nil)
+	Y := pubPoly.Commit()
+	msg := []byte("hello ed25519")
+
+	signers := []int{4, 1, 2}
+	nonces := make([]*Nonce, len(signers))
+	commits := make([]*Commitment, len(signers))
+	for k, i := range signers {
+		nonces[k], commits[k] = NewNonce(suite, i, random.Stream)
+	}
+	partials := make([]*Partial, len(signers))
+	for k, i := range signers {
+		p, err := SignEdDSA(suite, Y, shares[i], nonces[k], msg, commits)
+		require.Nil(t, err)
+		require.Nil(t, VerifyPartialEdDSA(suite, pubPoly, msg, commits, p))
+		assert.NotNil(t, VerifyPartial(suite, pubPoly, msg, commits, p))
+		partials[k] = p
+	}
+	sig, err := AggregateEdDSA(suite, pubPoly, msg, commits, partials)
+	require.Nil(t, err)
+	assert.Nil(t, eddsa.Verify(Y, msg, sig))
+	assert.NotNil(t, eddsa.Verify(Y, []byte("other"), sig))
+
+	pub, err := Y.MarshalBinary()
+	require.Nil(t, err)
+	assert.True(t, stded25519.Verify(stded25519.PublicKey(pub), msg, sig))
+}
+
+type memStore map[string][]byte
+
+func (s memStore) Save(id string, state []byte) error {
+	s[id] = append([]byte(nil), state...)
+	return nil
+}
+
+func (s memStore) Delete(id string) error {
+	delete(s, id)
+	return nil
+}
+
+func (s memStore) Load() (map[string][]byte, error) {
+	return s, nil
+}
+
+func TestManager(t *testing.T) {
+	n, th := 5, 3
+	shares, pubPoly := setup(n, th)
+	Y := pubPoly.Commit()
+	now := time.Unix(1000, 0)
+	signers := []int{0, 2, 3}
+	stores := make([]memStore, n)
+	managers := make([]*Manager, n)
+	for _, i := range signers {
+		stores[i] = make(memStore)
+		m, err := NewManager(suite, Y, shares[i], stores[i], time.Minute)
+		require.Nil(t, err)
+		managers[i] = m
+	}
+
+	// Two concurrent sessions with independent nonces
+	ids := []string{"a", "b"}
+	for _, id := range ids {
+		var commits []*Commitment
+		for _, i := range signers {
+			c, err := managers[i].Open(id, now, random.Stream)
+			require.Nil(t, err)
+			commits = append(commits, c)
+		}
+		for _, i := range signers {
+			for _, c := range commits {
+				require.Nil(t, managers[i].Commit(id, c))
+			}
+		}
+	}
+	_, err := managers[0].Open("a", now, random.Stream)
+	assert.Equal(t, ErrSessionExists, err)
+
+	// A commitment of one session is refused in another
+	ca, err := managers[2].Commitments("a")
+	require.Nil(t, err)
+	for _, c := range ca {
+		if c.I == 3 {
+			err = managers[0].Commit("b", c)
+		}
+	}
+	assert.True(t, errors.Is(err, ErrConflict))
+
+	for _, id := range ids {
+		msg := []byte("message " + id)
+		var partials []*Partial
+		for _, i := range signers {
+			p, err := managers[i].Sign(id, msg, now)
+			require.Nil(t, err)
+			partials = append(partials, p)
+		}
+		commits, err := managers[0].Commitments(id)
+		require.Nil(t, err)
+		sig, err := Aggregate(suite, pubPoly, msg, commits, partials)
+		require.Nil(t, err)
+		assert.Nil(t, sign.VerifySchnorr(suite, Y, msg, sig))
+	}
+
+	// A nonce is used once, also after a restart
+	_, err = managers[0].Sign("a", []byte("other"), now)
+	assert.Equal(t, ErrNonceUsed, err)
+	restarted, err := NewManager(suite, Y, shares[0], stores[0], time.Minute)
+	require.Nil(t, err)
+	_, err = restarted.Sign("a", []byte("other"), now)
+	assert.Equal(t, ErrNonceUsed, err)
+	require.Nil(t, restarted.Close("a"))
+	assert.Equal(t, 1, len(stores[0]))
+
+	// An open session survives a restart but times out
+	_, err = restarted.Open("c", now, random.Stream)
+	require.Nil(t, err)
+	restarted, err = NewManager(suite, Y, shares[0], stores[0], time.Minute)
+	require.Nil(t, err)
+	commits, err := restarted.Commitments("c")
+	require.Nil(t, err)
+	assert.Equal(t, 1, len(commits))
+	_, err = restarted.Sign("c", []byte("late"), now.Add(2*time.Minute))
+	assert.Equal(t, ErrTimeout, err)
+	_, err = restarted.Sign("c", []byte("late"), now)
+	assert.Equal(t, ErrUnknownSession, err)
+
+	expired, err := restarted.Expire(now.Add(2 * time.Minute))
+	require.Nil(t, err)
+	assert.Equal(t, []string{"b"}, expired)
+	assert.Equal(t, 0, len(stores[0]))
+}
@@ -0,0 +1,286 @@
+package frost
+
+import (
+	"bytes"
+	"crypto/cipher"
+	"encoding/binary"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/dedis/crypto/abstract"
+	"github.com/dedis/crypto/share"
+)
+
+// Errors of the session manager.
+var (
+	// ErrSessionExists is returned when a session identifier is reused.
+	ErrSessionExists = errors.New("session already exists")
+	// ErrUnknownSession is returned for sessions that were never opened or
+	// have been closed or aborted.
+	ErrUnknownSession = errors.New("unknown session")
+	// ErrTimeout is returned for sessions whose deadline has passed; they
+	// are aborted.
+	ErrTimeout = errors.New("session timed out")
+	// ErrConflict is returned for commitments that contradict a commitment
+	// already recorded for the same signer or that are recorded in another
+	// session.
+	ErrConflict = errors.New("conflicting commitment")
+)
+
+// Store persists the state of a signer's sessions across restarts. Save must
+// not return before the state is durable. The manager saves a session when
+// it is opened and again, marking its nonce as used, before it releases the
+// partial signature, so a restarted signer never signs twice with a nonce.
+type Store interface {
+	Save(id string, state []byte) error
+	Delete(id string) error
+	Load() (map[string][]byte, error)
+}
+
+// Manager runs the concurrent signing sessions of one signer. It keeps a
+// fresh nonce for every session, records the commitments of the other
+// signers, uses every nonce at most once and aborts sessions whose
+// participants do not respond before the deadline:
+//
+//	commit, err := m.Open(id, now, rand)  // broadcast commit
+//	err = m.Commit(id, c)                 // for the commitments received
+//	partial, err := m.Sign(id, msg, now)  // send partial to the aggregator
+//	m.Close(id)
+//
+// Commitments of the session, including the signer's own, are available with
+// Commitments for the aggregator. The commitments of the other signers are
+// not persisted and must be received again after a restart. It is safe for
+// concurrent use.
+type Manager struct {
+	suite    abstract.Suite
+	y        abstract.Point
+	priShare *share.PriShare
+	store    Store
+	timeout  time.Duration
+
+	mu       sync.Mutex
+	sessions map[string]*managed
+}
+
+type managed struct {
+	nonce    *Nonce // nil once used
+	commits  map[int]*Commitment
+	deadline time.Time
+}
+
+// NewManager creates the session manager of the holder of priShare of the
+// group key Y. Sessions time out after the given duration. If store is not
+// nil, the sessions it holds are restored; sessions whose nonce was used
+// before remain closed to signing.
+func NewManager(suite abstract.Suite, Y abstract.Point, priShare *share.PriShare, store Store, timeout time.Duration) (*Manager, error) {
+	m := &Manager{
+		suite:    suite,
+		y:        Y,
+		priShare: priShare,
+		store:    store,
+		timeout:  timeout,
+		sessions: make(map[string]*managed),
+	}
+	if store == nil {
+		return m, nil
+	}
+	states, err := store.Load()
+	if err != nil {
+		return nil, err
+	}
+	for id, state := range states {
+		s, err := m.decode(state)
+		if err != nil {
+			return nil, err
+		}
+		m.sessions[id] = s
+	}
+	return m, nil
+}
+
+// Open starts the session with the given identifier at now and returns the
+// commitment to the signer's fresh nonce. Identifiers must be unique among
+// the sessions that have not been closed.
+func (m *Manager) Open(id string, now time.Time, rand cipher.Stream) (*Commitment, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.sessions[id] != nil {
+		return nil, ErrSessionExists
+	}
+	nonce, commit := NewNonce(m.suite, m.priShare.I, rand)
+	s := &managed{nonce, map[int]*Commitment{commit.I: commit}, now.Add(m.timeout)}
+	if err := m.save(id, s); err != nil {
+		return nil, err
+	}
+	m.sessions[id] = s
+	return commit, nil
+}
+
+// Commit records the commitment of another signer for the session. A signer
+// can commit only once per session and a commitment can only be used in one
+// session.
+func (m *Manager) Commit(id string, c *Commitment) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s := m.sessions[id]
+	if s == nil {
+		return ErrUnknownSession
+	}
+	if c == nil || c.I < 0 {
+		return ErrSignerSet
+	}
+	if old := s.commits[c.I]; old != nil {
+		if old.D.Equal(c.D) && old.E.Equal(c.E) {
+			return nil
+		}
+		return &BlameError{[]int{c.I}, ErrConflict}
+	}
+	for other, o := range m.sessions {
+		if other == id {
+			continue
+		}
+		for _, oc := range o.commits {
+			if oc.D.Equal(c.D) || oc.E.Equal(c.E) {
+				return &BlameError{[]int{c.I}, ErrConflict}
+			}
+		}
+	}
+	s.commits[c.I] = c
+	return nil
+}
+
+// Commitments returns the commitments recorded for the session, including
+// the signer's own.
+func (m *Manager) Commitments(id string) ([]*Commitment, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s := m.sessions[id]
+	if s == nil {
+		return nil, ErrUnknownSession
+	}
+	commits := make([]*Commitment, 0, len(s.commits))
+	for _, c := range s.commits {
+		commits = append(commits, c)
+	}
+	return commits, nil
+}
+
+// Sign computes the signer's partial signature on msg for the session, whose
+// signing set consists of the signers whose commitments were recorded. The
+// nonce is marked as used, and the mark persisted, before the partial
+// signature is returned. A session past its deadline is aborted instead.
+func (m *Manager) Sign(id string, msg []byte, now time.Time) (*Partial, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s := m.sessions[id]
+	if s == nil {
+		return nil, ErrUnknownSession
+	}
+	if now.After(s.deadline) {
+		m.abort(id)
+		return nil, ErrTimeout
+	}
+	if s.nonce == nil {
+		return nil, ErrNonceUsed
+	}
+	commits := make([]*Commitment, 0, len(s.commits))
+	for _, c := range s.commits {
+		commits = append(commits, c)
+	}
+	nonce := s.nonce
+	s.nonce = nil
+	if err := m.save(id, s); err != nil {
+		s.nonce = nonce
+		return nil, err
+	}
+	return Sign(m.suite, m.y, m.priShare, nonce, msg, commits)
+}
+
+// Abort ends the session and erases its nonce.
+func (m *Manager) Abort(id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.sessions[id] == nil {
+		return ErrUnknownSession
+	}
+	return m.abort(id)
+}
+
+// Close ends a session after signing; it is the same as Abort.
+func (m *Manager) Close(id string) error {
+	return m.Abort(id)
+}
+
+// Expire aborts all sessions whose deadline lies before now and returns their
+// identifiers.
+func (m *Manager) Expire(now time.Time) ([]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var expired []string
+	for id, s := range m.sessions {
+		if !now.After(s.deadline) {
+			continue
+		}
+		if err := m.abort(id); err != nil {
+			return expired, err
+		}
+		expired = append(expired, id)
+	}
+	return expired, nil
+}
+
+func (m *Manager) abort(id string) error {
+	s := m.sessions[id]
+	if s.nonce != nil {
+		s.nonce.d.Zero()
+		s.nonce.e.Zero()
+		s.nonce = nil
+	}
+	delete(m.sessions, id)
+	if m.store != nil {
+		return m.store.Delete(id)
+	}
+	return nil
+}
+
+// save persists the session as its deadline followed, while the nonce is
+// unused, by the nonce pair (d, e).
+func (m *Manager) save(id string, s *managed) error {
+	if m.store == nil {
+		return nil
+	}
+	var b bytes.Buffer
+	binary.Write(&b, binary.BigEndian, s.deadline.UnixNano())
+	if s.nonce != nil {
+		if _, err := s.nonce.d.MarshalTo(&b); err != nil {
+			return err
+		}
+		if _, err := s.nonce.e.MarshalTo(&b); err != nil {
+			return err
+		}
+	}
+	return m.store.Save(id, b.Bytes())
+}
+
+func (m *Manager) decode(state []byte) (*managed, error) {
+	r := bytes.NewReader(state)
+	var deadline int64
+	if err := binary.Read(r, binary.BigEndian, &deadline); err != nil {
+		return nil, err
+	}
+	s := &managed{commits: make(map[int]*Commitment), deadline: time.Unix(0, deadline)}
+	if r.Len() == 0 {
+		return s, nil
+	}
+	n := &Nonce{I: m.priShare.I, d: m.suite.Scalar(), e: m.suite.Scalar()}
+	if _, err := n.d.UnmarshalFrom(r); err != nil {
+		return nil, err
+	}
+	if _, err := n.e.UnmarshalFrom(r); err != nil {
+		return nil, err
+	}
+	s.nonce = n
+	s.commits[n.I] = &Commitment{n.I, m.suite.Point().Mul(nil, n.d), m.suite.Point().Mul(nil, n.e)}
+	return s, nil
+}
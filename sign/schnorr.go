@@ -2,10 +2,14 @@ package sign
 
 import (
 	"bytes"
+	"crypto/cipher"
 	"errors"
 	"fmt"
+	"hash"
 
 	"github.com/dedis/crypto/abstract"
+	"github.com/dedis/crypto/group"
+	"github.com/dedis/crypto/proof"
 	"github.com/dedis/crypto/random"
 )
 
@@ -13,14 +17,30 @@ import (
 // signature can be verified with VerifySchnorr. It's also a valid EdDSA
 // signature.
 func Schnorr(suite abstract.Suite, private abstract.Scalar, msg []byte) ([]byte, error) {
+	return schnorr(suite, private, msg, random.Stream)
+}
+
+// SchnorrDerandomized is like Schnorr but derives the secret k from the device
+// key, the private key and the message with proof.NonceStream instead of
+// picking it at random, so that a broken random number generator cannot leak
+// the private key.
+func SchnorrDerandomized(suite abstract.Suite, key []byte, private abstract.Scalar, msg []byte) ([]byte, error) {
+	rand, err := proof.NonceStream(suite, key, private, msg)
+	if err != nil {
+		return nil, err
+	}
+	return schnorr(suite, private, msg, rand)
+}
+
+func schnorr(suite abstract.Suite, private abstract.Scalar, msg []byte, rand cipher.Stream) ([]byte, error) {
 	// using notation from https://en.wikipedia.org/wiki/Schnorr_signature
 	// create random secret k and public point commitment r
-	k := suite.Scalar().Pick(random.Stream)
+	k := suite.Scalar().Pick(rand)
 	r := suite.Point().Mul(nil, k)
 
 	// create challenge e based on message and r
 	public := suite.Point().Mul(nil, private)
-	e, err := hash(suite, public, r, msg)
+	e, err := Challenge(suite, public, r, msg)
 	if err != nil {
 		return nil, err
 	}
@@ -40,10 +60,17 @@ func Schnorr(suite abstract.Suite, private abstract.Scalar, msg []byte) ([]byte,
 }
 
 // VerifySchnorr verifies a given Schnorr signature. It returns nil iff the
-// given signature is valid.  NOTE: this signature scheme is malleable because
-// the response's unmarshalling is done directly into a big.Int modulo (see
-// nist.Int).
+// given signature is valid. The scalars are decoded with group.Lenient; use
+// VerifySchnorrMode with group.Strict to also require canonical encodings, so
+// that a valid signature cannot be altered into another valid one.
 func VerifySchnorr(suite abstract.Suite, public abstract.Point, msg, sig []byte) error {
+	return VerifySchnorrMode(suite, public, msg, sig, group.Lenient)
+}
+
+// VerifySchnorrMode is like VerifySchnorr but decodes the signature according
+// to mode. In Strict mode both scalars must be canonically encoded, so that a
+// valid signature cannot be altered into another valid one.
+func VerifySchnorrMode(suite abstract.Suite, public abstract.Point, msg, sig []byte, mode group.Strictness) error {
 	challenge := suite.Scalar()
 	response := suite.Scalar()
 	scalarSize := challenge.MarshalSize()
@@ -51,10 +78,10 @@ func VerifySchnorr(suite abstract.Suite, public abstract.Point, msg, sig []byte)
 	if len(sig) != sigSize {
 		return fmt.Errorf("schnorr: signature of invalid length %d instead of %d", len(sig), sigSize)
 	}
-	if err := challenge.UnmarshalBinary(sig[:scalarSize]); err != nil {
+	if err := group.UnmarshalScalar(challenge, sig[:scalarSize], mode); err != nil {
 		return err
 	}
-	if err := response.UnmarshalBinary(sig[scalarSize:]); err != nil {
+	if err := group.UnmarshalScalar(response, sig[scalarSize:], mode); err != nil {
 		return err
 	}
 	// compute rv = g^s * y^e (where y = g^x)
@@ -63,7 +90,7 @@ func VerifySchnorr(suite abstract.Suite, public abstract.Point, msg, sig []byte)
 	rv := suite.Point().Add(gs, ye)
 
 	// recompute challenge (e) from rv
-	e, err := hash(suite, public, rv, msg)
+	e, err := Challenge(suite, public, rv, msg)
 	if err != nil {
 		return err
 	}
@@ -75,8 +102,18 @@ func VerifySchnorr(suite abstract.Suite, public abstract.Point, msg, sig []byte)
 	return nil
 }
 
-func hash(suite abstract.Suite, public, r abstract.Point, msg []byte) (abstract.Scalar, error) {
-	h := suite.Hash()
+// Challenge computes the challenge e = H(R, public, msg) of a Schnorr
+// signature of msg with nonce commitment R under the suite's hash function,
+// as Schnorr and VerifySchnorr do. Schemes that produce signatures verifying
+// with VerifySchnorr, e.g. threshold or adaptor signatures, use it to derive
+// the same challenge.
+func Challenge(suite abstract.Suite, public, R abstract.Point, msg []byte) (abstract.Scalar, error) {
+	return ChallengeHash(suite, suite.Hash(), public, R, msg)
+}
+
+// ChallengeHash is like Challenge but uses the hash function h, e.g. SHA-512
+// for signatures that verify as Ed25519 signatures.
+func ChallengeHash(suite abstract.Suite, h hash.Hash, public, r abstract.Point, msg []byte) (abstract.Scalar, error) {
 	if _, err := r.MarshalTo(h); err != nil {
 		return nil, err
 	}
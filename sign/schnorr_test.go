@@ -5,6 +5,7 @@ import (
 
 	"github.com/dedis/crypto/config"
 	"github.com/dedis/crypto/ed25519"
+	"github.com/dedis/crypto/group"
 	"github.com/dedis/crypto/random"
 	"github.com/stretchr/testify/assert"
 )
@@ -43,3 +44,48 @@ func TestSchnorrSignature(t *testing.T) {
 	wrKp := config.NewKeyPair(suite)
 	assert.Error(t, VerifySchnorr(suite, wrKp.Public, msg, s))
 }
+
+func TestSchnorrDerandomized(t *testing.T) {
+	suite := ed25519.NewAES128SHA256Ed25519(false)
+	kp := config.NewKeyPair(suite)
+	key := []byte("device key")
+	msg := []byte("Hello Schnorr")
+
+	s1, err := SchnorrDerandomized(suite, key, kp.Secret, msg)
+	assert.Nil(t, err)
+	assert.Nil(t, VerifySchnorr(suite, kp.Public, msg, s1))
+	s2, err := SchnorrDerandomized(suite, key, kp.Secret, msg)
+	assert.Nil(t, err)
+	assert.Equal(t, s1, s2)
+
+	// Nonces differ across messages and device keys
+	s3, err := SchnorrDerandomized(suite, key, kp.Secret, []byte("other"))
+	assert.Nil(t, err)
+	assert.NotEqual(t, s1[:32], s3[:32])
+	s4, err := SchnorrDerandomized(suite, []byte("other device"), kp.Secret, msg)
+	assert.Nil(t, err)
+	assert.NotEqual(t, s1, s4)
+	assert.Nil(t, VerifySchnorr(suite, kp.Public, msg, s4))
+}
+
+func TestSchnorrMode(t *testing.T) {
+	suite := ed25519.NewAES128SHA256Ed25519(false)
+	kp := config.NewKeyPair(suite)
+	msg := []byte("Hello Schnorr")
+	s, err := Schnorr(suite, kp.Secret, msg)
+	assert.Nil(t, err)
+	assert.Nil(t, VerifySchnorrMode(suite, kp.Public, msg, s, group.Strict))
+
+	// response + L is out of range and rejected in both modes
+	L := []byte{0xed, 0xd3, 0xf5, 0x5c, 0x1a, 0x63, 0x12, 0x58, 0xd6, 0x9c, 0xf7, 0xa2, 0xde, 0xf9, 0xde, 0x14,
+		0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0x10}
+	mal := append([]byte{}, s...)
+	var carry uint16
+	for i := range L {
+		carry += uint16(mal[32+i]) + uint16(L[i])
+		mal[32+i] = byte(carry)
+		carry >>= 8
+	}
+	assert.Error(t, VerifySchnorr(suite, kp.Public, msg, mal))
+	assert.Error(t, VerifySchnorrMode(suite, kp.Public, msg, mal, group.Strict))
+}
@@ -0,0 +1,129 @@
+// Package threshold implements threshold Schnorr signatures for holders of
+// shares of a collective key generated with package dkg. Every signature
+// requires a fresh nonce, shared among the same participants with a second
+// run of the DKG:
+//
+//	each signer:  partial, err := Sign(suite, longterm, nonce, msg)
+//	combiner:     sig, err := Combine(suite, longterm.Commits, nonce.Commits, msg, partials)
+//
+// Any t partial signatures combine into a signature that verifies with
+// sign.VerifySchnorr against the collective public key. Each partial
+// signature can be checked against the public commitments of both keys with
+// VerifyPartial, so that signers contributing invalid partial signatures
+// are identified. A nonce must never be used for two messages, as this
+// reveals the collective private key.
+package threshold
+
+import (
+	"bytes"
+	"errors"
+
+	"github.com/dedis/crypto/abstract"
+	"github.com/dedis/crypto/dkg"
+	"github.com/dedis/crypto/share"
+	"github.com/dedis/crypto/sign"
+)
+
+// Some error definitions
+var errorShares = errors.New("longterm and nonce shares of different participants")
+var errorThreshold = errors.New("longterm key and nonce of different thresholds")
+var errorPartial = errors.New("invalid partial signature")
+var errorTooFew = errors.New("not enough valid partial signatures")
+
+// Partial is the partial signature s_i = k_i - x_i*e of participant I, where
+// k_i and x_i are its shares of the nonce and of the private key and e is the
+// challenge of the signature.
+type Partial struct {
+	I int
+	S abstract.Scalar
+}
+
+// Sign returns the partial signature of msg by the holder of the longterm and
+// nonce shares.
+func Sign(suite abstract.Suite, longterm, nonce *dkg.DistKeyShare, msg []byte) (*Partial, error) {
+	if longterm.Share.I != nonce.Share.I {
+		return nil, errorShares
+	}
+	if longterm.Commits.Threshold() != nonce.Commits.Threshold() {
+		return nil, errorThreshold
+	}
+	e, err := sign.Challenge(suite, longterm.Public(), nonce.Public(), msg)
+	if err != nil {
+		return nil, err
+	}
+	s := suite.Scalar().Mul(longterm.Share.V, e)
+	s.Sub(nonce.Share.V, s)
+	return &Partial{longterm.Share.I, s}, nil
+}
+
+// VerifyPartial checks the partial signature of msg against the public
+// commitments of the longterm key and of the nonce, i.e. s_i*G = K_i - e*X_i.
+func VerifyPartial(suite abstract.Suite, longterm, nonce *share.PubPoly, msg []byte, partial *Partial) error {
+	e, err := sign.Challenge(suite, longterm.Commit(), nonce.Commit(), msg)
+	if err != nil {
+		return err
+	}
+	return verify(suite, longterm, nonce, e, partial)
+}
+
+func verify(suite abstract.Suite, longterm, nonce *share.PubPoly, e abstract.Scalar, partial *Partial) error {
+	if partial == nil || partial.S == nil || partial.I < 0 {
+		return errorPartial
+	}
+	sG := suite.Point().Mul(nil, partial.S)
+	eX := suite.Point().Mul(longterm.Eval(partial.I).V, e)
+	K := nonce.Eval(partial.I).V
+	if !sG.Equal(K.Sub(K, eX)) {
+		return errorPartial
+	}
+	return nil
+}
+
+// Combine verifies the partial signatures of msg and combines the first t
+// valid ones, where t is the threshold of the longterm key and of the nonce,
+// into a Schnorr signature. Invalid partial signatures are ignored.
+func Combine(suite abstract.Suite, longterm, nonce *share.PubPoly, msg []byte, partials []*Partial) ([]byte, error) {
+	t := longterm.Threshold()
+	if nonce.Threshold() != t {
+		return nil, errorThreshold
+	}
+	if len(partials) < t {
+		return nil, errorTooFew
+	}
+	e, err := sign.Challenge(suite, longterm.Commit(), nonce.Commit(), msg)
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[int]bool)
+	var shares []*share.PriShare
+	n := 0
+	for _, p := range partials {
+		if len(shares) == t {
+			break
+		}
+		if p == nil || seen[p.I] || verify(suite, longterm, nonce, e, p) != nil {
+			continue
+		}
+		seen[p.I] = true
+		shares = append(shares, &share.PriShare{I: p.I, V: p.S})
+		if p.I >= n {
+			n = p.I + 1
+		}
+	}
+	if len(shares) < t {
+		return nil, errorTooFew
+	}
+	s, err := share.RecoverSecret(suite, shares, t, n)
+	if err != nil {
+		return nil, err
+	}
+
+	var b bytes.Buffer
+	if _, err := e.MarshalTo(&b); err != nil {
+		return nil, err
+	}
+	if _, err := s.MarshalTo(&b); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
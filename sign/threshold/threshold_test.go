@@ -0,0 +1,106 @@
+package threshold
+
+import (
+	"testing"
+
+	"github.com/dedis/crypto/abstract"
+	"github.com/dedis/crypto/dkg"
+	"github.com/dedis/crypto/edwards"
+	"github.com/dedis/crypto/random"
+	"github.com/dedis/crypto/sign"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var suite = edwards.NewAES128SHA256Ed25519(false)
+
+// generate runs a DKG among the holders of keys and returns their shares.
+func generate(t *testing.T, keys []abstract.Scalar, th int) []*dkg.DistKeyShare {
+	pubs := make([]abstract.Point, len(keys))
+	for i := range keys {
+		pubs[i] = suite.Point().Mul(nil, keys[i])
+	}
+	dkgs := make([]*dkg.DistKeyGenerator, len(keys))
+	for i := range dkgs {
+		var err error
+		dkgs[i], err = dkg.NewDistKeyGenerator(suite, keys[i], pubs, th)
+		require.Nil(t, err)
+	}
+	for _, d := range dkgs {
+		c := d.Commitment()
+		for _, o := range dkgs {
+			require.Nil(t, o.ProcessCommitment(c))
+		}
+	}
+	var responses []*dkg.Response
+	for _, d := range dkgs {
+		for j, deal := range d.Deals() {
+			r, err := dkgs[j].ProcessDeal(deal)
+			require.Nil(t, err)
+			responses = append(responses, r)
+		}
+	}
+	for _, r := range responses {
+		for _, d := range dkgs {
+			if d.Index() != r.Response.Index {
+				_, err := d.ProcessResponse(r)
+				require.Nil(t, err)
+			}
+		}
+	}
+	dks := make([]*dkg.DistKeyShare, len(keys))
+	for i, d := range dkgs {
+		var err error
+		dks[i], err = d.DistKeyShare()
+		require.Nil(t, err)
+	}
+	return dks
+}
+
+func TestThreshold(t *testing.T) {
+	n, th := 5, 3
+	keys := make([]abstract.Scalar, n)
+	for i := range keys {
+		keys[i] = suite.Scalar().Pick(random.Stream)
+	}
+	longterm := generate(t, keys, th)
+	nonce := generate(t, keys, th)
+	pub, R := longterm[0].Commits, nonce[0].Commits
+	msg := []byte("threshold schnorr")
+
+	partials := make([]*Partial, n)
+	for i := range partials {
+		var err error
+		partials[i], err = Sign(suite, longterm[i], nonce[i], msg)
+		require.Nil(t, err)
+		require.Nil(t, VerifyPartial(suite, pub, R, msg, partials[i]))
+	}
+
+	sig, err := Combine(suite, pub, R, msg, partials[2:])
+	require.Nil(t, err)
+	require.Nil(t, sign.VerifySchnorr(suite, longterm[0].Public(), msg, sig))
+
+	// A bad contributor is identified and skipped
+	bad := &Partial{partials[1].I, suite.Scalar().Pick(random.Stream)}
+	assert.Equal(t, errorPartial, VerifyPartial(suite, pub, R, msg, bad))
+	assert.Equal(t, errorPartial, VerifyPartial(suite, pub, R, []byte("other"), partials[0]))
+	sig, err = Combine(suite, pub, R, msg, []*Partial{bad, partials[0], partials[0], partials[3], partials[4]})
+	require.Nil(t, err)
+	require.Nil(t, sign.VerifySchnorr(suite, longterm[0].Public(), msg, sig))
+
+	_, err = Combine(suite, pub, R, msg, []*Partial{bad, partials[0], partials[3]})
+	assert.Equal(t, errorTooFew, err)
+
+	_, err = Combine(suite, pub, R, msg, partials[:th-1])
+	assert.Equal(t, errorTooFew, err)
+
+	_, err = Sign(suite, longterm[0], nonce[1], msg)
+	assert.Equal(t, errorShares, err)
+
+	// A nonce of another threshold cannot complete the signature
+	other := generate(t, keys, th+1)
+	_, err = Sign(suite, longterm[0], other[0], msg)
+	assert.Equal(t, errorThreshold, err)
+	_, err = Combine(suite, pub, other[0].Commits, msg, partials)
+	assert.Equal(t, errorThreshold, err)
+}
@@ -0,0 +1,220 @@
+// Package twoparty implements 2-of-2 Schnorr signing between a client, such as
+// a wallet, and a co-signing server. Each party holds an additive share x_i of
+// the joint private key x = x_1 + x_2 and the result is an ordinary Schnorr
+// signature that verifies with sign.VerifySchnorr under the joint public key.
+// With only two parties, no polynomial sharing, Lagrange interpolation or
+// broadcast is needed and a signature costs three messages:
+//
+//	client:  cs, commit := NewClientSession(suite, key, msg, rand)
+//	server:  ss, R2, err := NewServerSession(suite, key, msg, commit, rand)
+//	client:  R1, err := cs.Reveal(R2)
+//	server:  s2, err := ss.Respond(R1)
+//	client:  sig, err := cs.Finish(s2)
+//
+// The client commits to its nonce before seeing the server's, which prevents
+// the server from choosing its nonce as a function of the client's, and both
+// parties prove possession of their key shares when setting up the joint key,
+// which prevents rogue-key attacks.
+//
+// Two-party ECDSA in the style of Lindell is not part of this package and is
+// deferred to a separate change. It needs Paillier encryption together with
+// zero-knowledge proofs that the Paillier modulus is well formed and that the
+// encrypted key share matches its public counterpart; this library provides
+// none of them yet, and without the proofs a malicious party can extract the
+// other party's key share.
+package twoparty
+
+import (
+	"bytes"
+	"crypto/cipher"
+	"crypto/subtle"
+	"errors"
+
+	"github.com/dedis/crypto/abstract"
+	"github.com/dedis/crypto/sign"
+)
+
+// Some error definitions
+var errorPossession = errors.New("invalid proof of possession")
+var errorNotJoined = errors.New("key not joined with a peer")
+var errorCommitment = errors.New("nonce does not match commitment")
+var errorPartial = errors.New("invalid partial signature")
+var errorSessionUsed = errors.New("session already used")
+
+// Key is a party's share of a 2-of-2 signing key.
+type Key struct {
+	x      abstract.Scalar
+	Public abstract.Point // Public key share X_i = x_iG
+	Peer   abstract.Point // Public key share of the other party
+	Joint  abstract.Point // Joint public key X = X_1 + X_2
+}
+
+// Announcement is a party's public key share together with a proof of
+// possession of the corresponding private key.
+type Announcement struct {
+	Public abstract.Point
+	Proof  []byte // Schnorr signature by the key share
+}
+
+// NewKey creates a fresh key share and the announcement to send to the peer.
+func NewKey(suite abstract.Suite, rand cipher.Stream) (*Key, *Announcement, error) {
+	x := suite.Scalar().Pick(rand)
+	k := &Key{x: x, Public: suite.Point().Mul(nil, x)}
+	msg, err := possessionMessage(k.Public)
+	if err != nil {
+		return nil, nil, err
+	}
+	pop, err := sign.Schnorr(suite, x, msg)
+	if err != nil {
+		return nil, nil, err
+	}
+	return k, &Announcement{k.Public, pop}, nil
+}
+
+// Join verifies the peer's announcement and sets up the joint public key.
+func (k *Key) Join(suite abstract.Suite, peer *Announcement) error {
+	msg, err := possessionMessage(peer.Public)
+	if err != nil {
+		return err
+	}
+	if err := sign.VerifySchnorr(suite, peer.Public, msg, peer.Proof); err != nil {
+		return errorPossession
+	}
+	k.Peer = peer.Public
+	k.Joint = suite.Point().Add(k.Public, peer.Public)
+	return nil
+}
+
+func possessionMessage(X abstract.Point) ([]byte, error) {
+	buf, err := X.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte("twoparty-possession"), buf...), nil
+}
+
+// ClientSession is the client's state of one signature.
+type ClientSession struct {
+	suite abstract.Suite
+	key   *Key
+	msg   []byte
+	k     abstract.Scalar
+	R1    abstract.Point
+	R     abstract.Point
+	e     abstract.Scalar
+	s1    abstract.Scalar
+}
+
+// NewClientSession starts signing msg and returns the commitment to the
+// client's nonce for the server.
+func NewClientSession(suite abstract.Suite, key *Key, msg []byte, rand cipher.Stream) (*ClientSession, []byte, error) {
+	if key.Joint == nil {
+		return nil, nil, errorNotJoined
+	}
+	k := suite.Scalar().Pick(rand)
+	cs := &ClientSession{suite: suite, key: key, msg: msg, k: k, R1: suite.Point().Mul(nil, k)}
+	commit, err := commitment(suite, cs.R1)
+	if err != nil {
+		return nil, nil, err
+	}
+	return cs, commit, nil
+}
+
+// Reveal takes the server's nonce R2 and returns the client's nonce R1 to
+// open the commitment.
+func (cs *ClientSession) Reveal(R2 abstract.Point) (abstract.Point, error) {
+	if cs.k == nil || cs.R != nil {
+		return nil, errorSessionUsed
+	}
+	cs.R = cs.suite.Point().Add(cs.R1, R2)
+	e, err := sign.Challenge(cs.suite, cs.key.Joint, cs.R, cs.msg)
+	if err != nil {
+		return nil, err
+	}
+	cs.e = e
+	// s_1 = k_1 - x_1*e
+	cs.s1 = cs.suite.Scalar().Sub(cs.k, cs.suite.Scalar().Mul(cs.key.x, e))
+	cs.k.Zero()
+	cs.k = nil
+	return cs.R1, nil
+}
+
+// Finish checks the server's partial signature s2 and returns the joint
+// Schnorr signature.
+func (cs *ClientSession) Finish(s2 abstract.Scalar) ([]byte, error) {
+	if cs.s1 == nil {
+		return nil, errorSessionUsed
+	}
+	// s_2G + eX_2 == R_2
+	R2 := cs.suite.Point().Sub(cs.R, cs.R1)
+	V := cs.suite.Point().Add(cs.suite.Point().Mul(nil, s2), cs.suite.Point().Mul(cs.key.Peer, cs.e))
+	if !V.Equal(R2) {
+		return nil, errorPartial
+	}
+	s := cs.suite.Scalar().Add(cs.s1, s2)
+	cs.s1 = nil
+	var b bytes.Buffer
+	if _, err := cs.e.MarshalTo(&b); err != nil {
+		return nil, err
+	}
+	if _, err := s.MarshalTo(&b); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// ServerSession is the server's state of one signature.
+type ServerSession struct {
+	suite  abstract.Suite
+	key    *Key
+	msg    []byte
+	commit []byte
+	k      abstract.Scalar
+	R2     abstract.Point
+}
+
+// NewServerSession starts co-signing msg given the client's nonce commitment
+// and returns the server's nonce R2 for the client.
+func NewServerSession(suite abstract.Suite, key *Key, msg []byte, commit []byte, rand cipher.Stream) (*ServerSession, abstract.Point, error) {
+	if key.Joint == nil {
+		return nil, nil, errorNotJoined
+	}
+	k := suite.Scalar().Pick(rand)
+	ss := &ServerSession{suite: suite, key: key, msg: msg, commit: commit, k: k, R2: suite.Point().Mul(nil, k)}
+	return ss, ss.R2, nil
+}
+
+// Respond checks the client's nonce R1 against its commitment and returns the
+// server's partial signature. The session's nonce is erased afterwards, so
+// Respond succeeds at most once.
+func (ss *ServerSession) Respond(R1 abstract.Point) (abstract.Scalar, error) {
+	if ss.k == nil {
+		return nil, errorSessionUsed
+	}
+	commit, err := commitment(ss.suite, R1)
+	if err != nil {
+		return nil, err
+	}
+	if subtle.ConstantTimeCompare(commit, ss.commit) != 1 {
+		return nil, errorCommitment
+	}
+	R := ss.suite.Point().Add(R1, ss.R2)
+	e, err := sign.Challenge(ss.suite, ss.key.Joint, R, ss.msg)
+	if err != nil {
+		return nil, err
+	}
+	// s_2 = k_2 - x_2*e
+	s2 := ss.suite.Scalar().Sub(ss.k, ss.suite.Scalar().Mul(ss.key.x, e))
+	ss.k.Zero()
+	ss.k = nil
+	return s2, nil
+}
+
+func commitment(suite abstract.Suite, R1 abstract.Point) ([]byte, error) {
+	h := suite.Hash()
+	h.Write([]byte("twoparty-commit"))
+	if _, err := R1.MarshalTo(h); err != nil {
+		return nil, err
+	}
+	return h.Sum(nil), nil
+}
@@ -0,0 +1,84 @@
+package twoparty
+
+import (
+	"testing"
+
+	"github.com/dedis/crypto/edwards"
+	"github.com/dedis/crypto/random"
+	"github.com/dedis/crypto/sign"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var suite = edwards.NewAES128SHA256Ed25519(false)
+
+func setup(t *testing.T) (*Key, *Key) {
+	client, ca, err := NewKey(suite, random.Stream)
+	require.Nil(t, err)
+	server, sa, err := NewKey(suite, random.Stream)
+	require.Nil(t, err)
+	require.Nil(t, client.Join(suite, sa))
+	require.Nil(t, server.Join(suite, ca))
+	require.True(t, client.Joint.Equal(server.Joint))
+	return client, server
+}
+
+func TestTwoParty(t *testing.T) {
+	client, server := setup(t)
+	msg := []byte("transfer 10 coins")
+
+	cs, commit, err := NewClientSession(suite, client, msg, random.Stream)
+	require.Nil(t, err)
+	ss, R2, err := NewServerSession(suite, server, msg, commit, random.Stream)
+	require.Nil(t, err)
+	R1, err := cs.Reveal(R2)
+	require.Nil(t, err)
+	s2, err := ss.Respond(R1)
+	require.Nil(t, err)
+	sig, err := cs.Finish(s2)
+	require.Nil(t, err)
+	assert.Nil(t, sign.VerifySchnorr(suite, client.Joint, msg, sig))
+	assert.Error(t, sign.VerifySchnorr(suite, client.Joint, []byte("other"), sig))
+
+	// Sessions cannot be reused
+	_, err = ss.Respond(R1)
+	assert.Equal(t, errorSessionUsed, err)
+	_, err = cs.Finish(s2)
+	assert.Equal(t, errorSessionUsed, err)
+}
+
+func TestTwoPartyMisbehavior(t *testing.T) {
+	client, server := setup(t)
+	msg := []byte("msg")
+
+	// The client opens its commitment to a different nonce
+	cs, commit, err := NewClientSession(suite, client, msg, random.Stream)
+	require.Nil(t, err)
+	ss, R2, err := NewServerSession(suite, server, msg, commit, random.Stream)
+	require.Nil(t, err)
+	_, err = cs.Reveal(R2)
+	require.Nil(t, err)
+	_, err = ss.Respond(suite.Point().Base())
+	assert.Equal(t, errorCommitment, err)
+
+	// The server returns a bad partial signature
+	cs, commit, err = NewClientSession(suite, client, msg, random.Stream)
+	require.Nil(t, err)
+	_, R2, err = NewServerSession(suite, server, msg, commit, random.Stream)
+	require.Nil(t, err)
+	_, err = cs.Reveal(R2)
+	require.Nil(t, err)
+	_, err = cs.Finish(suite.Scalar().Pick(random.Stream))
+	assert.Equal(t, errorPartial, err)
+
+	// Announcements without a valid proof of possession are rejected
+	_, a, err := NewKey(suite, random.Stream)
+	require.Nil(t, err)
+	a.Public = suite.Point().Sub(a.Public, client.Public)
+	k, _, err := NewKey(suite, random.Stream)
+	require.Nil(t, err)
+	assert.Equal(t, errorPossession, k.Join(suite, a))
+
+	_, _, err = NewClientSession(suite, &Key{}, msg, random.Stream)
+	assert.Equal(t, errorNotJoined, err)
+}
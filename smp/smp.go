@@ -0,0 +1,388 @@
+// Package smp implements a variant of the socialist millionaire protocol as
+// used in Off-the-Record messaging. It lets two parties check whether their
+// secrets x and y are equal without revealing anything else about them, not
+// even to an active attacker that can guess a single value per run.
+// A typical use is the out-of-band verification of key fingerprints, where
+// both parties derive their secret from a shared passphrase and the
+// fingerprints of the two long-term keys with Secret.
+//
+// The protocol runs in four messages between an initiator (Alice) and a
+// responder (Bob):
+//
+//  1. Alice sends g2a = a2G and g3a = a3G.
+//  2. Bob sends g2b = b2G, g3b = b3G and, with G2 = b2g2a and G3 = b3g3a,
+//     Pb = rG3 and Qb = rG + yG2.
+//  3. Alice sends Pa = sG3, Qa = sG + xG2 and Ra = a3(Qa - Qb).
+//  4. Bob sends Rb = b3(Qa - Qb).
+//
+// Both parties then check whether a3b3(Qa - Qb) = Pa - Pb, which holds if and
+// only if x = y. Every message carries zero-knowledge proofs of its
+// well-formedness.
+package smp
+
+import (
+	"crypto/cipher"
+	"encoding/binary"
+	"errors"
+
+	"github.com/dedis/crypto/abstract"
+)
+
+// Some error definitions
+var errorProof = errors.New("invalid proof")
+var errorDegenerate = errors.New("degenerate protocol value")
+var errorState = errors.New("message out of order")
+
+// Proof is a non-interactive proof of knowledge of one or two discrete
+// logarithms.
+type Proof struct {
+	C abstract.Scalar   // Challenge
+	R []abstract.Scalar // Responses
+}
+
+// Message1 is sent by the initiator to start the protocol.
+type Message1 struct {
+	G2a, G3a abstract.Point
+	P2, P3   Proof // Proofs of knowledge of a2 and a3
+}
+
+// Message2 is the responder's answer to Message1.
+type Message2 struct {
+	G2b, G3b abstract.Point
+	Pb, Qb   abstract.Point
+	P2, P3   Proof // Proofs of knowledge of b2 and b3
+	PQ       Proof // Proof of well-formedness of Pb and Qb
+}
+
+// Message3 is the initiator's answer to Message2.
+type Message3 struct {
+	Pa, Qa abstract.Point
+	Ra     abstract.Point
+	PQ     Proof // Proof of well-formedness of Pa and Qa
+	R      Proof // Proof of well-formedness of Ra
+}
+
+// Message4 concludes the protocol.
+type Message4 struct {
+	Rb abstract.Point
+	R  Proof // Proof of well-formedness of Rb
+}
+
+// Secret derives the secret scalar to compare from a shared passphrase and
+// the fingerprints of the initiator's and the responder's keys. Both parties
+// must pass the fingerprints in the same order.
+func Secret(suite abstract.Suite, passphrase, initiator, responder []byte) abstract.Scalar {
+	h := suite.Hash()
+	h.Write([]byte("smp-secret"))
+	for _, b := range [][]byte{initiator, responder, passphrase} {
+		binary.Write(h, binary.BigEndian, uint32(len(b)))
+		h.Write(b)
+	}
+	return suite.Scalar().Pick(suite.Cipher(h.Sum(nil)))
+}
+
+// Initiator runs Alice's side of the protocol.
+type Initiator struct {
+	suite  abstract.Suite
+	rand   cipher.Stream
+	x      abstract.Scalar
+	a2, a3 abstract.Scalar
+	G3b    abstract.Point
+	Pab    abstract.Point // Pa - Pb
+	Qab    abstract.Point // Qa - Qb
+	step   int
+}
+
+// NewInitiator creates the initiator of a protocol run comparing the secret x.
+func NewInitiator(suite abstract.Suite, x abstract.Scalar, rand cipher.Stream) *Initiator {
+	return &Initiator{suite: suite, rand: rand, x: x}
+}
+
+// Start returns the first message of the protocol.
+func (a *Initiator) Start() (*Message1, error) {
+	if a.step != 0 {
+		return nil, errorState
+	}
+	s := a.suite
+	a.a2 = s.Scalar().Pick(a.rand)
+	a.a3 = s.Scalar().Pick(a.rand)
+	m := &Message1{G2a: s.Point().Mul(nil, a.a2), G3a: s.Point().Mul(nil, a.a3)}
+	var err error
+	if m.P2, err = proveDlog(s, 1, nil, a.a2, a.rand); err != nil {
+		return nil, err
+	}
+	if m.P3, err = proveDlog(s, 2, nil, a.a3, a.rand); err != nil {
+		return nil, err
+	}
+	a.step = 1
+	return m, nil
+}
+
+// Respond verifies the responder's message and returns the third message of
+// the protocol.
+func (a *Initiator) Respond(m *Message2) (*Message3, error) {
+	if a.step != 1 {
+		return nil, errorState
+	}
+	a.step = -1
+	s := a.suite
+	if err := checkPoints(s, m.G2b, m.G3b, m.Pb, m.Qb); err != nil {
+		return nil, err
+	}
+	if err := verifyDlog(s, 3, nil, m.G2b, m.P2); err != nil {
+		return nil, err
+	}
+	if err := verifyDlog(s, 4, nil, m.G3b, m.P3); err != nil {
+		return nil, err
+	}
+	G2 := s.Point().Mul(m.G2b, a.a2)
+	G3 := s.Point().Mul(m.G3b, a.a3)
+	if err := verifyPQ(s, 5, G2, G3, m.Pb, m.Qb, m.PQ); err != nil {
+		return nil, err
+	}
+
+	r := s.Scalar().Pick(a.rand)
+	out := &Message3{
+		Pa: s.Point().Mul(G3, r),
+		Qa: s.Point().Add(s.Point().Mul(nil, r), s.Point().Mul(G2, a.x)),
+	}
+	var err error
+	if out.PQ, err = provePQ(s, 6, G2, G3, r, a.x, a.rand); err != nil {
+		return nil, err
+	}
+	a.Pab = s.Point().Sub(out.Pa, m.Pb)
+	a.Qab = s.Point().Sub(out.Qa, m.Qb)
+	out.Ra = s.Point().Mul(a.Qab, a.a3)
+	if out.R, err = proveDleq(s, 7, a.Qab, a.a3, a.rand); err != nil {
+		return nil, err
+	}
+	a.G3b = m.G3b
+	a.step = 2
+	return out, nil
+}
+
+// Finish verifies the last message of the protocol and reports whether the
+// two secrets are equal.
+func (a *Initiator) Finish(m *Message4) (bool, error) {
+	if a.step != 2 {
+		return false, errorState
+	}
+	a.step = -1
+	s := a.suite
+	if err := checkPoints(s, m.Rb); err != nil {
+		return false, err
+	}
+	if err := verifyDleq(s, 8, a.Qab, a.G3b, m.Rb, m.R); err != nil {
+		return false, err
+	}
+	Rab := s.Point().Mul(m.Rb, a.a3)
+	return Rab.Equal(a.Pab), nil
+}
+
+// Responder runs Bob's side of the protocol.
+type Responder struct {
+	suite  abstract.Suite
+	rand   cipher.Stream
+	y      abstract.Scalar
+	b3     abstract.Scalar
+	G2, G3 abstract.Point
+	G3a    abstract.Point
+	Pb, Qb abstract.Point
+	step   int
+}
+
+// NewResponder creates the responder of a protocol run comparing the secret
+// y.
+func NewResponder(suite abstract.Suite, y abstract.Scalar, rand cipher.Stream) *Responder {
+	return &Responder{suite: suite, rand: rand, y: y}
+}
+
+// Respond verifies the initiator's first message and returns the second
+// message of the protocol.
+func (b *Responder) Respond(m *Message1) (*Message2, error) {
+	if b.step != 0 {
+		return nil, errorState
+	}
+	b.step = -1
+	s := b.suite
+	if err := checkPoints(s, m.G2a, m.G3a); err != nil {
+		return nil, err
+	}
+	if err := verifyDlog(s, 1, nil, m.G2a, m.P2); err != nil {
+		return nil, err
+	}
+	if err := verifyDlog(s, 2, nil, m.G3a, m.P3); err != nil {
+		return nil, err
+	}
+	b2 := s.Scalar().Pick(b.rand)
+	b.b3 = s.Scalar().Pick(b.rand)
+	out := &Message2{G2b: s.Point().Mul(nil, b2), G3b: s.Point().Mul(nil, b.b3)}
+	var err error
+	if out.P2, err = proveDlog(s, 3, nil, b2, b.rand); err != nil {
+		return nil, err
+	}
+	if out.P3, err = proveDlog(s, 4, nil, b.b3, b.rand); err != nil {
+		return nil, err
+	}
+
+	b.G2 = s.Point().Mul(m.G2a, b2)
+	b.G3 = s.Point().Mul(m.G3a, b.b3)
+	r := s.Scalar().Pick(b.rand)
+	out.Pb = s.Point().Mul(b.G3, r)
+	out.Qb = s.Point().Add(s.Point().Mul(nil, r), s.Point().Mul(b.G2, b.y))
+	if out.PQ, err = provePQ(s, 5, b.G2, b.G3, r, b.y, b.rand); err != nil {
+		return nil, err
+	}
+	b.G3a = m.G3a
+	b.Pb, b.Qb = out.Pb, out.Qb
+	b.step = 1
+	return out, nil
+}
+
+// Finish verifies the initiator's third message, returns the last message of
+// the protocol and reports whether the two secrets are equal.
+func (b *Responder) Finish(m *Message3) (*Message4, bool, error) {
+	if b.step != 1 {
+		return nil, false, errorState
+	}
+	b.step = -1
+	s := b.suite
+	if err := checkPoints(s, m.Pa, m.Qa, m.Ra); err != nil {
+		return nil, false, err
+	}
+	if err := verifyPQ(s, 6, b.G2, b.G3, m.Pa, m.Qa, m.PQ); err != nil {
+		return nil, false, err
+	}
+	Qab := s.Point().Sub(m.Qa, b.Qb)
+	if err := verifyDleq(s, 7, Qab, b.G3a, m.Ra, m.R); err != nil {
+		return nil, false, err
+	}
+	out := &Message4{Rb: s.Point().Mul(Qab, b.b3)}
+	var err error
+	if out.R, err = proveDleq(s, 8, Qab, b.b3, b.rand); err != nil {
+		return nil, false, err
+	}
+	Rab := s.Point().Mul(m.Ra, b.b3)
+	Pab := s.Point().Sub(m.Pa, b.Pb)
+	return out, Rab.Equal(Pab), nil
+}
+
+// checkPoints rejects missing and neutral protocol values, which would allow
+// a party to force the outcome of the comparison.
+func checkPoints(suite abstract.Suite, points ...abstract.Point) error {
+	null := suite.Point().Null()
+	for _, P := range points {
+		if P == nil || P.Equal(null) {
+			return errorDegenerate
+		}
+	}
+	return nil
+}
+
+// challenge hashes the step number and the given points into a challenge.
+func challenge(suite abstract.Suite, step byte, points ...abstract.Point) (abstract.Scalar, error) {
+	h := suite.Hash()
+	h.Write([]byte{'s', 'm', 'p', step})
+	for _, P := range points {
+		if _, err := P.MarshalTo(h); err != nil {
+			return nil, err
+		}
+	}
+	return suite.Scalar().Pick(suite.Cipher(h.Sum(nil))), nil
+}
+
+// proveDlog proves knowledge of x with X = xB, where a nil B denotes the
+// standard base point.
+func proveDlog(suite abstract.Suite, step byte, B abstract.Point, x abstract.Scalar, rand cipher.Stream) (Proof, error) {
+	if B == nil {
+		B = suite.Point().Base()
+	}
+	w := suite.Scalar().Pick(rand)
+	c, err := challenge(suite, step, B, suite.Point().Mul(B, x), suite.Point().Mul(B, w))
+	if err != nil {
+		return Proof{}, err
+	}
+	r := suite.Scalar().Sub(w, suite.Scalar().Mul(c, x))
+	return Proof{c, []abstract.Scalar{r}}, nil
+}
+
+func verifyDlog(suite abstract.Suite, step byte, B, X abstract.Point, p Proof) error {
+	if B == nil {
+		B = suite.Point().Base()
+	}
+	if p.C == nil || len(p.R) != 1 {
+		return errorProof
+	}
+	W := suite.Point().Add(suite.Point().Mul(B, p.R[0]), suite.Point().Mul(X, p.C))
+	c, err := challenge(suite, step, B, X, W)
+	if err != nil {
+		return err
+	}
+	if !c.Equal(p.C) {
+		return errorProof
+	}
+	return nil
+}
+
+// provePQ proves knowledge of r and x with P = rG3 and Q = rG + xG2.
+func provePQ(suite abstract.Suite, step byte, G2, G3 abstract.Point, r, x abstract.Scalar, rand cipher.Stream) (Proof, error) {
+	P := suite.Point().Mul(G3, r)
+	Q := suite.Point().Add(suite.Point().Mul(nil, r), suite.Point().Mul(G2, x))
+	w1 := suite.Scalar().Pick(rand)
+	w2 := suite.Scalar().Pick(rand)
+	T1 := suite.Point().Mul(G3, w1)
+	T2 := suite.Point().Add(suite.Point().Mul(nil, w1), suite.Point().Mul(G2, w2))
+	c, err := challenge(suite, step, G2, G3, P, Q, T1, T2)
+	if err != nil {
+		return Proof{}, err
+	}
+	r1 := suite.Scalar().Sub(w1, suite.Scalar().Mul(c, r))
+	r2 := suite.Scalar().Sub(w2, suite.Scalar().Mul(c, x))
+	return Proof{c, []abstract.Scalar{r1, r2}}, nil
+}
+
+func verifyPQ(suite abstract.Suite, step byte, G2, G3, P, Q abstract.Point, p Proof) error {
+	if p.C == nil || len(p.R) != 2 {
+		return errorProof
+	}
+	T1 := suite.Point().Add(suite.Point().Mul(G3, p.R[0]), suite.Point().Mul(P, p.C))
+	T2 := suite.Point().Add(suite.Point().Mul(nil, p.R[0]), suite.Point().Mul(G2, p.R[1]))
+	T2.Add(T2, suite.Point().Mul(Q, p.C))
+	c, err := challenge(suite, step, G2, G3, P, Q, T1, T2)
+	if err != nil {
+		return err
+	}
+	if !c.Equal(p.C) {
+		return errorProof
+	}
+	return nil
+}
+
+// proveDleq proves knowledge of x with xG = X and xQ = R.
+func proveDleq(suite abstract.Suite, step byte, Q abstract.Point, x abstract.Scalar, rand cipher.Stream) (Proof, error) {
+	w := suite.Scalar().Pick(rand)
+	X := suite.Point().Mul(nil, x)
+	R := suite.Point().Mul(Q, x)
+	c, err := challenge(suite, step, Q, X, R, suite.Point().Mul(nil, w), suite.Point().Mul(Q, w))
+	if err != nil {
+		return Proof{}, err
+	}
+	r := suite.Scalar().Sub(w, suite.Scalar().Mul(c, x))
+	return Proof{c, []abstract.Scalar{r}}, nil
+}
+
+func verifyDleq(suite abstract.Suite, step byte, Q, X, R abstract.Point, p Proof) error {
+	if p.C == nil || len(p.R) != 1 {
+		return errorProof
+	}
+	W1 := suite.Point().Add(suite.Point().Mul(nil, p.R[0]), suite.Point().Mul(X, p.C))
+	W2 := suite.Point().Add(suite.Point().Mul(Q, p.R[0]), suite.Point().Mul(R, p.C))
+	c, err := challenge(suite, step, Q, X, R, W1, W2)
+	if err != nil {
+		return err
+	}
+	if !c.Equal(p.C) {
+		return errorProof
+	}
+	return nil
+}
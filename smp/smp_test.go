@@ -0,0 +1,76 @@
+package smp
+
+import (
+	"testing"
+
+	"github.com/dedis/crypto/abstract"
+	"github.com/dedis/crypto/edwards"
+	"github.com/dedis/crypto/random"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var suite = edwards.NewAES128SHA256Ed25519(false)
+
+func run(t *testing.T, x, y abstract.Scalar) (bool, bool) {
+	alice := NewInitiator(suite, x, random.Stream)
+	bob := NewResponder(suite, y, random.Stream)
+	m1, err := alice.Start()
+	require.Nil(t, err)
+	m2, err := bob.Respond(m1)
+	require.Nil(t, err)
+	m3, err := alice.Respond(m2)
+	require.Nil(t, err)
+	m4, eqB, err := bob.Finish(m3)
+	require.Nil(t, err)
+	eqA, err := alice.Finish(m4)
+	require.Nil(t, err)
+	return eqA, eqB
+}
+
+func TestSMP(t *testing.T) {
+	fa, fb := []byte("alice-fingerprint"), []byte("bob-fingerprint")
+	x := Secret(suite, []byte("correct horse"), fa, fb)
+	y := Secret(suite, []byte("correct horse"), fa, fb)
+	eqA, eqB := run(t, x, y)
+	assert.True(t, eqA)
+	assert.True(t, eqB)
+
+	// A man in the middle substitutes Bob's key
+	y = Secret(suite, []byte("correct horse"), fa, []byte("mallory-fingerprint"))
+	eqA, eqB = run(t, x, y)
+	assert.False(t, eqA)
+	assert.False(t, eqB)
+}
+
+func TestSMPInvalid(t *testing.T) {
+	x := suite.Scalar().Pick(random.Stream)
+	alice := NewInitiator(suite, x, random.Stream)
+	bob := NewResponder(suite, x, random.Stream)
+	m1, err := alice.Start()
+	require.Nil(t, err)
+	_, err = alice.Start()
+	assert.Equal(t, errorState, err)
+
+	// Degenerate values are rejected
+	bad := *m1
+	bad.G2a = suite.Point().Null()
+	_, err = bob.Respond(&bad)
+	assert.Equal(t, errorDegenerate, err)
+
+	// Tampered values are rejected
+	bob = NewResponder(suite, x, random.Stream)
+	bad = *m1
+	bad.G3a = suite.Point().Base()
+	_, err = bob.Respond(&bad)
+	assert.Equal(t, errorProof, err)
+
+	bob = NewResponder(suite, x, random.Stream)
+	m2, err := bob.Respond(m1)
+	require.Nil(t, err)
+	m2.Qb = suite.Point().Add(m2.Qb, suite.Point().Base())
+	_, err = alice.Respond(m2)
+	assert.Equal(t, errorProof, err)
+	_, err = alice.Finish(&Message4{})
+	assert.Equal(t, errorState, err)
+}
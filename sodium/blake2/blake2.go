@@ -1,10 +1,10 @@
+//go:build sodium
 // +build sodium
 
 // This package implements the BLAKE2b cryptographic hash function,
 // described at:
 //
 //	https://blake2.net
-//
 package blake2
 
 // #include "blake2.h"
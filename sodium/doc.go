@@ -1,3 +1,4 @@
+//go:build sodium
 // +build sodium
 
 /*
@@ -1,3 +1,4 @@
+//go:build sodium
 // +build sodium
 
 // Package ed25519 implements Go wrappers for
@@ -0,0 +1,141 @@
+package sortition
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"math/big"
+	"sort"
+
+	"github.com/dedis/crypto/abstract"
+	"github.com/dedis/crypto/random"
+)
+
+// Some error definitions of the roster shuffle
+var errorDuplicateKey = errors.New("duplicate key in roster")
+var errorShuffle = errors.New("invalid shuffle trace")
+
+// Trace records a shuffle of a roster so that anybody holding the roster and
+// the beacon output can recheck it. It binds the beacon output, a digest of
+// the roster and the swaps of the Fisher-Yates shuffle: at step i, position i
+// is swapped with position Swaps[i] >= i of the roster in canonical order.
+type Trace struct {
+	Seed   []byte   // Beacon output
+	Roster []byte   // Digest of the roster in canonical order
+	Swaps  []uint32 // Swap position of every step
+}
+
+// canonical returns the roster sorted by the binary encoding of its keys,
+// which makes the shuffle independent of the order in which the roster is
+// given, together with the digest of the sorted roster.
+func canonical(suite abstract.Suite, roster []abstract.Point) ([]abstract.Point, []byte, error) {
+	type entry struct {
+		key []byte
+		P   abstract.Point
+	}
+	entries := make([]entry, len(roster))
+	for i, P := range roster {
+		key, err := P.MarshalBinary()
+		if err != nil {
+			return nil, nil, err
+		}
+		entries[i] = entry{key, P}
+	}
+	sort.Slice(entries, func(i, j int) bool { return bytes.Compare(entries[i].key, entries[j].key) < 0 })
+	h := suite.Hash()
+	h.Write([]byte("sortition-roster"))
+	binary.Write(h, binary.BigEndian, uint32(len(entries)))
+	sorted := make([]abstract.Point, len(entries))
+	for i, e := range entries {
+		if i > 0 && bytes.Equal(entries[i-1].key, e.key) {
+			return nil, nil, errorDuplicateKey
+		}
+		h.Write(e.key)
+		sorted[i] = e.P
+	}
+	return sorted, h.Sum(nil), nil
+}
+
+// swaps derives the swap positions of the shuffle of n keys from the beacon
+// output and the roster digest.
+func swaps(suite abstract.Suite, seed, digest []byte, n int) []uint32 {
+	h := suite.Hash()
+	h.Write([]byte("sortition-shuffle"))
+	binary.Write(h, binary.BigEndian, uint32(len(seed)))
+	h.Write(seed)
+	h.Write(digest)
+	rand := suite.Cipher(h.Sum(nil))
+	s := make([]uint32, n)
+	for i := range s {
+		s[i] = uint32(int64(i) + random.Int(big.NewInt(int64(n-i)+1), rand).Int64() - 1)
+	}
+	return s
+}
+
+func apply(sorted []abstract.Point, s []uint32) []abstract.Point {
+	out := append([]abstract.Point{}, sorted...)
+	for i, j := range s {
+		out[i], out[j] = out[j], out[i]
+	}
+	return out
+}
+
+// Shuffle permutes the roster of public keys uniformly at random, using the
+// beacon output seed as the source of randomness, for instance to rotate
+// leaders or assign subcommittees. The result only depends on the seed and
+// the set of keys, not on their order in the roster. It returns the shuffled
+// roster and the trace of the shuffle.
+func Shuffle(suite abstract.Suite, seed []byte, roster []abstract.Point) ([]abstract.Point, *Trace, error) {
+	sorted, digest, err := canonical(suite, roster)
+	if err != nil {
+		return nil, nil, err
+	}
+	s := swaps(suite, seed, digest, len(sorted))
+	return apply(sorted, s), &Trace{seed, digest, s}, nil
+}
+
+// Verify checks that the trace is the shuffle of the roster with its beacon
+// output and that it yields the shuffled roster.
+func (t *Trace) Verify(suite abstract.Suite, roster, shuffled []abstract.Point) error {
+	sorted, digest, err := canonical(suite, roster)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(digest, t.Roster) || len(t.Swaps) != len(sorted) || len(shuffled) != len(sorted) {
+		return errorShuffle
+	}
+	for i, j := range swaps(suite, t.Seed, digest, len(sorted)) {
+		if t.Swaps[i] != j {
+			return errorShuffle
+		}
+	}
+	for i, P := range apply(sorted, t.Swaps) {
+		if !P.Equal(shuffled[i]) {
+			return errorShuffle
+		}
+	}
+	return nil
+}
+
+// Leader returns the leader of the given round in the shuffled roster, which
+// rotates through the roster round by round.
+func Leader(shuffled []abstract.Point, round uint64) abstract.Point {
+	return shuffled[round%uint64(len(shuffled))]
+}
+
+// Subcommittees splits the shuffled roster into consecutive subcommittees of
+// the given size; the last one holds the remaining keys and may be smaller.
+func Subcommittees(shuffled []abstract.Point, size int) ([][]abstract.Point, error) {
+	if size < 1 {
+		return nil, errorSize
+	}
+	var committees [][]abstract.Point
+	for i := 0; i < len(shuffled); i += size {
+		end := i + size
+		if end > len(shuffled) {
+			end = len(shuffled)
+		}
+		committees = append(committees, shuffled[i:end])
+	}
+	return committees, nil
+}
@@ -0,0 +1,196 @@
+// Package sortition maps the output of a randomness beacon to committee
+// selections. Public sortition derives the committee deterministically from
+// the beacon output, so everybody computes the same committee:
+//
+//	committee, err := sortition.Select(suite, beaconOutput, n, k)
+//	committee, err := sortition.SelectWeighted(suite, beaconOutput, stakes, k)
+//
+// Private sortition lets every participant find out on its own whether it is
+// elected, with a verifiable random function (VRF) evaluated on the beacon
+// output under its private key. Nobody learns the committee before its members
+// reveal their tickets, which keeps the members hidden from an adversary until
+// they act:
+//
+//	ticket, elected, err := sortition.Elect(suite, x, beaconOutput, stake, total, size)
+//	err := sortition.VerifyElected(suite, X, beaconOutput, stake, total, size, ticket)
+//
+// Shuffle permutes a whole roster of public keys with the beacon output, for
+// leader rotation and subcommittee assignment, and returns a trace that
+// anybody holding the roster rechecks with Trace.Verify:
+//
+//	shuffled, trace, err := sortition.Shuffle(suite, beaconOutput, roster)
+//	err := trace.Verify(suite, roster, shuffled)
+package sortition
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/dedis/crypto/abstract"
+	"github.com/dedis/crypto/proof"
+	"github.com/dedis/crypto/random"
+)
+
+// Some error definitions
+var errorSize = errors.New("invalid committee size")
+var errorWeight = errors.New("not enough participants with positive weight")
+var errorTicket = errors.New("invalid sortition ticket")
+var errorNotElected = errors.New("ticket does not elect its holder")
+
+// Select picks a committee of k distinct participants out of n uniformly at
+// random, using the beacon output seed as the source of randomness. It
+// returns the participants' indices in selection order.
+func Select(suite abstract.Suite, seed []byte, n, k int) ([]int, error) {
+	if k < 0 || k > n {
+		return nil, errorSize
+	}
+	rand := suite.Cipher(seed)
+	perm := make([]int, n)
+	for i := range perm {
+		perm[i] = i
+	}
+	// Partial Fisher-Yates shuffle
+	for i := 0; i < k; i++ {
+		j := i + int(random.Int(big.NewInt(int64(n-i)+1), rand).Int64()-1)
+		perm[i], perm[j] = perm[j], perm[i]
+	}
+	return perm[:k], nil
+}
+
+// SelectWeighted picks a committee of k distinct participants, where each
+// draw selects one of the remaining participants with probability
+// proportional to its weight, using the beacon output seed as the source of
+// randomness. Participants with weight zero are never selected. It returns
+// the participants' indices in selection order.
+func SelectWeighted(suite abstract.Suite, seed []byte, weights []uint64, k int) ([]int, error) {
+	if k < 0 {
+		return nil, errorSize
+	}
+	total := new(big.Int)
+	positive := 0
+	for _, w := range weights {
+		if w > 0 {
+			total.Add(total, new(big.Int).SetUint64(w))
+			positive++
+		}
+	}
+	if k > positive {
+		return nil, errorWeight
+	}
+	rand := suite.Cipher(seed)
+	selected := make([]bool, len(weights))
+	committee := make([]int, 0, k)
+	for len(committee) < k {
+		// r is uniform in [0, total)
+		r := random.Int(new(big.Int).Add(total, big.NewInt(1)), rand)
+		r.Sub(r, big.NewInt(1))
+		for i, w := range weights {
+			if selected[i] || w == 0 {
+				continue
+			}
+			bw := new(big.Int).SetUint64(w)
+			if r.Cmp(bw) < 0 {
+				selected[i] = true
+				committee = append(committee, i)
+				total.Sub(total, bw)
+				break
+			}
+			r.Sub(r, bw)
+		}
+	}
+	return committee, nil
+}
+
+// Ticket is the VRF evaluation Gamma = x*H(seed) of a participant with
+// private key x on a beacon output, together with a proof that Gamma is
+// consistent with the participant's public key X = xG.
+type Ticket struct {
+	Gamma abstract.Point  // VRF value
+	Proof proof.DLEQProof // Proof of log_G(X) == log_H(Gamma)
+}
+
+// Draw evaluates the VRF of the private key x on the beacon output seed.
+func Draw(suite abstract.Suite, x abstract.Scalar, seed []byte) (*Ticket, error) {
+	p, _, gamma, err := proof.NewDLEQProofTagged(suite, suite.Point().Base(), seedPoint(suite, seed), x, seed)
+	if err != nil {
+		return nil, err
+	}
+	return &Ticket{gamma, *p}, nil
+}
+
+// Verify checks that the ticket is the VRF evaluation of the private key of X
+// on the beacon output seed.
+func (t *Ticket) Verify(suite abstract.Suite, X abstract.Point, seed []byte) error {
+	if err := t.Proof.VerifyTagged(suite, suite.Point().Base(), seedPoint(suite, seed), X, t.Gamma, seed); err != nil {
+		return errorTicket
+	}
+	return nil
+}
+
+// Output returns the pseudorandom output of the ticket.
+func (t *Ticket) Output(suite abstract.Suite) ([]byte, error) {
+	h := suite.Hash()
+	h.Write([]byte("sortition-output"))
+	if _, err := t.Gamma.MarshalTo(h); err != nil {
+		return nil, err
+	}
+	return h.Sum(nil), nil
+}
+
+// Elect runs private sortition for the participant with private key x and
+// the given weight out of the total weight of all participants. The
+// participant is elected with probability min(1, size*weight/total), so that
+// the expected committee size is size. It returns the ticket, which proves the
+// election to others, and whether the participant is elected.
+func Elect(suite abstract.Suite, x abstract.Scalar, seed []byte, weight, total uint64, size int) (*Ticket, bool, error) {
+	t, err := Draw(suite, x, seed)
+	if err != nil {
+		return nil, false, err
+	}
+	elected, err := t.elects(suite, weight, total, size)
+	if err != nil {
+		return nil, false, err
+	}
+	return t, elected, nil
+}
+
+// VerifyElected checks that the ticket is valid for the participant with
+// public key X and that it elects the participant.
+func VerifyElected(suite abstract.Suite, X abstract.Point, seed []byte, weight, total uint64, size int, t *Ticket) error {
+	if err := t.Verify(suite, X, seed); err != nil {
+		return err
+	}
+	elected, err := t.elects(suite, weight, total, size)
+	if err != nil {
+		return err
+	}
+	if !elected {
+		return errorNotElected
+	}
+	return nil
+}
+
+// elects checks whether the ticket's output y, read as a fraction y/2^L of the
+// hash length L, is below size*weight/total.
+func (t *Ticket) elects(suite abstract.Suite, weight, total uint64, size int) (bool, error) {
+	if size < 0 || total == 0 || weight > total {
+		return false, errorSize
+	}
+	out, err := t.Output(suite)
+	if err != nil {
+		return false, err
+	}
+	// y * total < size * weight * 2^L
+	lhs := new(big.Int).SetBytes(out)
+	lhs.Mul(lhs, new(big.Int).SetUint64(total))
+	rhs := new(big.Int).SetUint64(weight)
+	rhs.Mul(rhs, big.NewInt(int64(size)))
+	rhs.Lsh(rhs, uint(8*len(out)))
+	return lhs.Cmp(rhs) < 0, nil
+}
+
+// seedPoint hashes the beacon output to the VRF base point H(seed).
+func seedPoint(suite abstract.Suite, seed []byte) abstract.Point {
+	H, _ := suite.Point().Pick(nil, suite.Cipher(append([]byte("sortition-vrf"), seed...)))
+	return H
+}
@@ -0,0 +1,133 @@
+package sortition
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/dedis/crypto/abstract"
+	"github.com/dedis/crypto/edwards"
+	"github.com/dedis/crypto/random"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var suite = edwards.NewAES128SHA256Ed25519(false)
+
+func TestSelect(t *testing.T) {
+	seed := []byte("beacon round 1")
+	c1, err := Select(suite, seed, 20, 7)
+	require.Nil(t, err)
+	c2, err := Select(suite, seed, 20, 7)
+	require.Nil(t, err)
+	assert.Equal(t, c1, c2)
+	c3, err := Select(suite, []byte("beacon round 2"), 20, 7)
+	require.Nil(t, err)
+	assert.NotEqual(t, c1, c3)
+
+	seen := make(map[int]bool)
+	for _, i := range c1 {
+		assert.True(t, i >= 0 && i < 20)
+		assert.False(t, seen[i])
+		seen[i] = true
+	}
+
+	all, err := Select(suite, seed, 5, 5)
+	require.Nil(t, err)
+	sort.Ints(all)
+	assert.Equal(t, []int{0, 1, 2, 3, 4}, all)
+	_, err = Select(suite, seed, 5, 6)
+	assert.Equal(t, errorSize, err)
+}
+
+func TestSelectWeighted(t *testing.T) {
+	weights := []uint64{0, 1, 1000, 0, 1}
+	counts := make(map[int]int)
+	for i := 0; i < 50; i++ {
+		c, err := SelectWeighted(suite, []byte{byte(i)}, weights, 1)
+		require.Nil(t, err)
+		counts[c[0]]++
+	}
+	assert.Equal(t, 0, counts[0]+counts[3])
+	assert.True(t, counts[2] > 45)
+
+	c, err := SelectWeighted(suite, []byte("seed"), weights, 3)
+	require.Nil(t, err)
+	sort.Ints(c)
+	assert.Equal(t, []int{1, 2, 4}, c)
+	_, err = SelectWeighted(suite, []byte("seed"), weights, 4)
+	assert.Equal(t, errorWeight, err)
+}
+
+func TestElect(t *testing.T) {
+	n, size := 40, 10
+	seed := []byte("beacon round 1")
+	x := make([]abstract.Scalar, n)
+	X := make([]abstract.Point, n)
+	elected := 0
+	for i := range x {
+		x[i] = suite.Scalar().Pick(random.Stream)
+		X[i] = suite.Point().Mul(nil, x[i])
+		ticket, ok, err := Elect(suite, x[i], seed, 1, uint64(n), size)
+		require.Nil(t, err)
+		err = VerifyElected(suite, X[i], seed, 1, uint64(n), size, ticket)
+		if ok {
+			elected++
+			assert.Nil(t, err)
+			assert.Equal(t, errorTicket, VerifyElected(suite, X[(i+1)%n], seed, 1, uint64(n), size, ticket))
+			assert.Equal(t, errorTicket, VerifyElected(suite, X[i], []byte("other"), 1, uint64(n), size, ticket))
+		} else {
+			assert.Equal(t, errorNotElected, err)
+		}
+
+		// Drawing is deterministic
+		again, err := Draw(suite, x[i], seed)
+		require.Nil(t, err)
+		assert.True(t, again.Gamma.Equal(ticket.Gamma))
+	}
+	assert.True(t, elected > 0 && elected < n)
+
+	// A participant holding all weight is always elected
+	_, ok, err := Elect(suite, x[0], seed, 5, 5, 1)
+	require.Nil(t, err)
+	assert.True(t, ok)
+}
+
+func TestShuffle(t *testing.T) {
+	seed := []byte("beacon round 1")
+	roster := make([]abstract.Point, 10)
+	for i := range roster {
+		roster[i] = suite.Point().Mul(nil, suite.Scalar().Pick(random.Stream))
+	}
+	shuffled, trace, err := Shuffle(suite, seed, roster)
+	require.Nil(t, err)
+	require.Nil(t, trace.Verify(suite, roster, shuffled))
+
+	// The shuffle does not depend on the order of the roster
+	reversed := make([]abstract.Point, len(roster))
+	for i, P := range roster {
+		reversed[len(roster)-1-i] = P
+	}
+	again, _, err := Shuffle(suite, seed, reversed)
+	require.Nil(t, err)
+	for i := range shuffled {
+		assert.True(t, shuffled[i].Equal(again[i]))
+	}
+	require.Nil(t, trace.Verify(suite, reversed, shuffled))
+
+	other, _, err := Shuffle(suite, []byte("beacon round 2"), roster)
+	require.Nil(t, err)
+	assert.Equal(t, errorShuffle, trace.Verify(suite, roster, other))
+	bad := *trace
+	bad.Seed = []byte("beacon round 2")
+	assert.Equal(t, errorShuffle, bad.Verify(suite, roster, shuffled))
+	assert.Equal(t, errorShuffle, trace.Verify(suite, roster[1:], shuffled[1:]))
+
+	_, _, err = Shuffle(suite, seed, append(roster, roster[3]))
+	assert.Equal(t, errorDuplicateKey, err)
+
+	assert.True(t, Leader(shuffled, 13).Equal(shuffled[3]))
+	committees, err := Subcommittees(shuffled, 4)
+	require.Nil(t, err)
+	require.Equal(t, 3, len(committees))
+	assert.Equal(t, 2, len(committees[2]))
+}
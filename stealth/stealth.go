@@ -0,0 +1,115 @@
+// Package stealth implements dual-key stealth addresses. A recipient
+// publishes a single address (A, B) = (aG, bG) made of a scan key and a spend
+// key. For every payment the sender derives a fresh one-time public key
+// P = H(rA)G + B together with the transaction key R = rG, so that payments to
+// the same address are unlinkable for outsiders. The holder of the scan
+// private key a detects payments by checking whether P = H(aR)G + B, which
+// allows delegating detection to a semi-trusted party without giving it the
+// ability to spend. Only the holder of the spend private key b can compute
+// the one-time private key x = H(aR) + b.
+package stealth
+
+import (
+	"crypto/cipher"
+	"errors"
+
+	"github.com/dedis/crypto/abstract"
+)
+
+// Some error definitions
+var errorNotMine = errors.New("one-time key does not belong to this address")
+
+// Keys are the private keys of a stealth address.
+type Keys struct {
+	Scan  abstract.Scalar // Private scan key a
+	Spend abstract.Scalar // Private spend key b
+}
+
+// Address is a stealth address published by a recipient.
+type Address struct {
+	Scan  abstract.Point // Public scan key A = aG
+	Spend abstract.Point // Public spend key B = bG
+}
+
+// ViewKey allows detecting the payments to an address without being able to
+// spend them.
+type ViewKey struct {
+	Scan  abstract.Scalar // Private scan key a
+	Spend abstract.Point  // Public spend key B
+}
+
+// OneTime is the per-transaction data of a payment to a stealth address.
+type OneTime struct {
+	R abstract.Point // Transaction public key rG
+	P abstract.Point // One-time public key H(rA)G + B
+}
+
+// NewKeys picks fresh stealth address keys.
+func NewKeys(suite abstract.Suite, rand cipher.Stream) *Keys {
+	return &Keys{
+		Scan:  suite.Scalar().Pick(rand),
+		Spend: suite.Scalar().Pick(rand),
+	}
+}
+
+// Address returns the public stealth address of the keys.
+func (k *Keys) Address(suite abstract.Suite) *Address {
+	return &Address{
+		Scan:  suite.Point().Mul(nil, k.Scan),
+		Spend: suite.Point().Mul(nil, k.Spend),
+	}
+}
+
+// ViewKey returns the view key of the keys.
+func (k *Keys) ViewKey(suite abstract.Suite) *ViewKey {
+	return &ViewKey{Scan: k.Scan, Spend: suite.Point().Mul(nil, k.Spend)}
+}
+
+// NewOneTime derives a fresh one-time public key for a payment to the
+// address.
+func NewOneTime(suite abstract.Suite, addr *Address, rand cipher.Stream) (*OneTime, error) {
+	r := suite.Scalar().Pick(rand)
+	h, err := sharedScalar(suite, suite.Point().Mul(addr.Scan, r))
+	if err != nil {
+		return nil, err
+	}
+	return &OneTime{
+		R: suite.Point().Mul(nil, r),
+		P: suite.Point().Add(suite.Point().Mul(nil, h), addr.Spend),
+	}, nil
+}
+
+// Detect reports whether the one-time key was derived for the address of the
+// view key.
+func (v *ViewKey) Detect(suite abstract.Suite, ot *OneTime) (bool, error) {
+	h, err := sharedScalar(suite, suite.Point().Mul(ot.R, v.Scan))
+	if err != nil {
+		return false, err
+	}
+	P := suite.Point().Add(suite.Point().Mul(nil, h), v.Spend)
+	return P.Equal(ot.P), nil
+}
+
+// OneTimeKey returns the private key of the one-time public key, which must
+// have been derived for the address of the keys.
+func (k *Keys) OneTimeKey(suite abstract.Suite, ot *OneTime) (abstract.Scalar, error) {
+	h, err := sharedScalar(suite, suite.Point().Mul(ot.R, k.Scan))
+	if err != nil {
+		return nil, err
+	}
+	x := suite.Scalar().Add(h, k.Spend)
+	if !suite.Point().Mul(nil, x).Equal(ot.P) {
+		return nil, errorNotMine
+	}
+	return x, nil
+}
+
+// sharedScalar hashes the Diffie-Hellman secret rA = aR to a scalar.
+func sharedScalar(suite abstract.Suite, S abstract.Point) (abstract.Scalar, error) {
+	h := suite.Hash()
+	h.Write([]byte("stealth"))
+	if _, err := S.MarshalTo(h); err != nil {
+		return nil, err
+	}
+	return suite.Scalar().Pick(suite.Cipher(h.Sum(nil))), nil
+}
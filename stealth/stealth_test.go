@@ -0,0 +1,38 @@
+package stealth
+
+import (
+	"testing"
+
+	"github.com/dedis/crypto/edwards"
+	"github.com/dedis/crypto/random"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var suite = edwards.NewAES128SHA256Ed25519(false)
+
+func TestStealth(t *testing.T) {
+	alice := NewKeys(suite, random.Stream)
+	bob := NewKeys(suite, random.Stream)
+	addr := alice.Address(suite)
+
+	ot1, err := NewOneTime(suite, addr, random.Stream)
+	require.Nil(t, err)
+	ot2, err := NewOneTime(suite, addr, random.Stream)
+	require.Nil(t, err)
+	assert.False(t, ot1.P.Equal(ot2.P))
+
+	// Detection only needs the view key
+	mine, err := alice.ViewKey(suite).Detect(suite, ot1)
+	require.Nil(t, err)
+	assert.True(t, mine)
+	mine, err = bob.ViewKey(suite).Detect(suite, ot1)
+	require.Nil(t, err)
+	assert.False(t, mine)
+
+	x, err := alice.OneTimeKey(suite, ot2)
+	require.Nil(t, err)
+	assert.True(t, suite.Point().Mul(nil, x).Equal(ot2.P))
+	_, err = bob.OneTimeKey(suite, ot2)
+	assert.Equal(t, errorNotMine, err)
+}
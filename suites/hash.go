@@ -0,0 +1,80 @@
+package suites
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"fmt"
+	"hash"
+	"strings"
+
+	"github.com/dedis/crypto/abstract"
+	"github.com/dedis/crypto/cipher/blake2b"
+	"github.com/dedis/crypto/cipher/sha3"
+)
+
+// Hash names a hash function that a suite can be instantiated with.
+type Hash string
+
+// Hash functions available for WithHash.
+const (
+	SHA256     Hash = "SHA256"
+	SHA512     Hash = "SHA512"
+	SHA3_256   Hash = "SHA3-256"
+	SHA3_512   Hash = "SHA3-512"
+	BLAKE2b256 Hash = "BLAKE2b-256"
+	BLAKE2b512 Hash = "BLAKE2b-512"
+)
+
+var hashes = map[Hash]func() hash.Hash{
+	SHA256:     sha256.New,
+	SHA512:     sha512.New,
+	SHA3_256:   sha3.New256,
+	SHA3_512:   sha3.New512,
+	BLAKE2b256: blake2b.New256,
+	BLAKE2b512: blake2b.New512,
+}
+
+// hashSuite replaces the hash function of a suite.
+type hashSuite struct {
+	abstract.Suite
+	name    Hash
+	newHash func() hash.Hash
+}
+
+func (s *hashSuite) Hash() hash.Hash {
+	return s.newHash()
+}
+
+func (s *hashSuite) String() string {
+	return s.Suite.String() + "-" + string(s.name)
+}
+
+// WithHash returns suite with its hash function replaced by h. The hash
+// function is used by all challenge derivations and key derivations that are
+// based on Suite.Hash. The name of the returned suite is the name of suite
+// followed by a dash and the name of h, e.g. "Ed25519-SHA3-256", and can be
+// looked up with StringToSuite.
+func WithHash(suite abstract.Suite, h Hash) (abstract.Suite, error) {
+	newHash, ok := hashes[h]
+	if !ok {
+		return nil, fmt.Errorf("Unknown hash %s", h)
+	}
+	if hs, ok := suite.(*hashSuite); ok {
+		suite = hs.Suite
+	}
+	return &hashSuite{suite, h, newHash}, nil
+}
+
+// lookupWithHash resolves names of the form "<suite>-<hash>".
+func lookupWithHash(name string) (abstract.Suite, bool) {
+	for n, suite := range All() {
+		if !strings.HasPrefix(name, n+"-") {
+			continue
+		}
+		s, err := WithHash(suite, Hash(strings.TrimPrefix(name, n+"-")))
+		if err == nil {
+			return s, true
+		}
+	}
+	return nil, false
+}
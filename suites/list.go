@@ -24,9 +24,23 @@ func All() Suites {
 	return s
 }
 
-// StringToSuite returns the suite for a string, or an error.
+// Insecure returns a map of suites that are fast but insecure and therefore
+// only suitable for tests. They are not part of All and cannot be looked up
+// with StringToSuite.
+func Insecure() Suites {
+	s := make(Suites)
+	s.add(nist.NewInsecureQR64())
+	return s
+}
+
+// StringToSuite returns the suite for a string, or an error. Besides the names
+// of All, it accepts the names of suites with a replaced hash function, see
+// WithHash.
 func StringToSuite(s string) (abstract.Suite, error) {
 	suite, ok := All()[s]
+	if !ok {
+		suite, ok = lookupWithHash(s)
+	}
 	if !ok {
 		return nil, fmt.Errorf("Didn't find suite %s", s)
 	}
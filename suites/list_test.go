@@ -13,6 +13,12 @@ func TestSuites(t *testing.T) {
 	}
 }
 
+func TestSuitesConcurrent(t *testing.T) {
+	for _, suite := range All() {
+		test.TestSuiteConcurrent(suite, 8)
+	}
+}
+
 func TestString(t *testing.T) {
 	_, err := StringToSuite("unknown")
 	if err == nil {
@@ -26,3 +32,49 @@ func TestString(t *testing.T) {
 		}
 	}
 }
+
+func TestInsecure(t *testing.T) {
+	for name, suite := range Insecure() {
+		test.TestSuite(suite)
+		if _, err := StringToSuite(name); err == nil {
+			t.Fatal("Shouldn't find insecure suite", name)
+		}
+	}
+}
+
+func TestWithHash(t *testing.T) {
+	for name, suite := range All() {
+		for h := range hashes {
+			s, err := WithHash(suite, h)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if s.Hash().Size() != hashes[h]().Size() {
+				t.Fatal("Suite", s.String(), "uses the wrong hash")
+			}
+			if h == SHA3_256 {
+				test.TestSuite(s)
+			}
+
+			found, err := StringToSuite(name + "-" + string(h))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if found.String() != s.String() {
+				t.Fatal("Suite", s.String(), "returned", found.String())
+			}
+
+			// Replacing the hash again does not stack names
+			s2, _ := WithHash(s, SHA512)
+			if s2.String() != name+"-"+string(SHA512) {
+				t.Fatal("Unexpected suite name", s2.String())
+			}
+		}
+	}
+	if _, err := WithHash(All()["P256"], "MD5"); err == nil {
+		t.Fatal("Shouldn't accept hash MD5")
+	}
+	if _, err := StringToSuite("P256-MD5"); err == nil {
+		t.Fatal("Shouldn't find suite P256-MD5")
+	}
+}
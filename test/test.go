@@ -3,6 +3,7 @@ package test
 import (
 	"bytes"
 	"crypto/cipher"
+	"sync"
 
 	"github.com/dedis/crypto/abstract"
 	"github.com/dedis/crypto/random"
@@ -108,7 +109,6 @@ func testScalarClone(g abstract.Group, rand cipher.Stream) {
 // Returns a log of the pseudorandom Points produced in the test,
 // for comparison across alternative implementations
 // that are supposed to be equivalent.
-//
 func testGroup(g abstract.Group, rand cipher.Stream) []abstract.Point {
 	//	fmt.Printf("\nTesting group '%s': %d-byte Point, %d-byte Scalar\n",
 	//			g.String(), g.PointLen(), g.ScalarLen())
@@ -364,3 +364,68 @@ func TestSuite(suite abstract.Suite) {
 	// Test the public-key group arithmetic
 	TestGroup(suite)
 }
+
+// TestSuiteConcurrent checks that a single suite can be used concurrently by
+// several goroutines: each goroutine performs the group arithmetic of
+// TestGroup as well as hashing and encoding, and must obtain the same results
+// as a sequential run. Run it with the race detector enabled to also catch
+// unsynchronized access to shared state.
+func TestSuiteConcurrent(suite abstract.Suite, goroutines int) {
+	// Every goroutine compares against its own copies of the expected
+	// points, so that only the suite's state is shared
+	points := testGroup(suite, suite.Cipher(abstract.NoKey))
+	encoded := make([][]byte, len(points))
+	for i, P := range points {
+		var err error
+		if encoded[i], err = P.MarshalBinary(); err != nil {
+			panic(err)
+		}
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan string, goroutines)
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			want := make([]abstract.Point, len(encoded))
+			for j, buf := range encoded {
+				want[j] = suite.Point()
+				if err := want[j].UnmarshalBinary(buf); err != nil {
+					errs <- err.Error()
+					return
+				}
+			}
+			got := testGroup(suite, suite.Cipher(abstract.NoKey))
+			for j := range want {
+				if !want[j].Equal(got[j]) {
+					errs <- "concurrent group operations yield different results"
+					return
+				}
+			}
+
+			h := suite.Hash()
+			h.Write([]byte("abc"))
+			hb := h.Sum(nil)
+			s := suite.Scalar().Pick(suite.Cipher(hb))
+			var b bytes.Buffer
+			if err := suite.Write(&b, want[0], s); err != nil {
+				errs <- err.Error()
+				return
+			}
+			P, x := suite.Point(), suite.Scalar()
+			if err := suite.Read(&b, P, x); err != nil {
+				errs <- err.Error()
+				return
+			}
+			if !P.Equal(want[0]) || !x.Equal(s) {
+				errs <- "concurrent encoding yields different results"
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		panic(err)
+	}
+}
@@ -0,0 +1,257 @@
+package testvectors
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	"github.com/dedis/crypto/abstract"
+	"github.com/dedis/crypto/eddsa"
+	"github.com/dedis/crypto/proof"
+	"github.com/dedis/crypto/pvss"
+	"github.com/dedis/crypto/share"
+	"github.com/dedis/crypto/sign"
+)
+
+// Parameters of the generated vectors
+const (
+	threshold = 3
+	parties   = 5
+)
+
+// message is signed by the signature schemes.
+var message = []byte("dedis/crypto test vector")
+
+// Shamir secret sharing: the secret, all shares and the commitments to the
+// polynomial with respect to the standard base point.
+
+func shareValues(suite abstract.Suite, rand abstract.Cipher) (map[string]string, error) {
+	vs := newValues(suite)
+	s := suite.Scalar().Pick(rand)
+	poly := share.NewPriPoly(suite, threshold, s, rand)
+	vs.set("secret", s)
+	for i, sh := range poly.Shares(parties) {
+		vs.set(fmt.Sprintf("share%d", i), sh.V)
+	}
+	_, commits := poly.Commit(nil).Info()
+	for i, C := range commits {
+		vs.set(fmt.Sprintf("commit%d", i), C)
+	}
+	return vs.result()
+}
+
+func genShare(suite abstract.Suite, rand abstract.Cipher) (map[string]string, error) {
+	return shareValues(suite, rand)
+}
+
+func checkShare(suite abstract.Suite, rand abstract.Cipher, v *Vector) error {
+	m, err := shareValues(suite, rand)
+	if err != nil {
+		return err
+	}
+	return compare(v, m)
+}
+
+// DLEQ proofs: the base point H, the secret x, the encrypted base points xG and
+// xH and a proof for them.
+
+func dleqValues(suite abstract.Suite, rand abstract.Cipher) (*values, abstract.Point, abstract.Scalar) {
+	vs := newValues(suite)
+	H, _ := suite.Point().Pick(nil, rand)
+	x := suite.Scalar().Pick(rand)
+	vs.set("H", H)
+	vs.set("x", x)
+	vs.set("xG", suite.Point().Mul(nil, x))
+	vs.set("xH", suite.Point().Mul(H, x))
+	return vs, H, x
+}
+
+func genDLEQ(suite abstract.Suite, rand abstract.Cipher) (map[string]string, error) {
+	vs, H, x := dleqValues(suite, rand)
+	p, _, _, err := proof.NewDLEQProof(suite, suite.Point().Base(), H, x)
+	if err != nil {
+		return nil, err
+	}
+	vs.set("proof", p)
+	return vs.result()
+}
+
+func checkDLEQ(suite abstract.Suite, rand abstract.Cipher, v *Vector) error {
+	vs, H, x := dleqValues(suite, rand)
+	m, err := vs.result()
+	if err != nil {
+		return err
+	}
+	if err := compare(v, m); err != nil {
+		return err
+	}
+	var p proof.DLEQProof
+	if err := decode(suite, v, "proof", &p); err != nil {
+		return err
+	}
+	G := suite.Point().Base()
+	if err := p.Verify(suite, G, H, suite.Point().Mul(G, x), suite.Point().Mul(H, x)); err != nil {
+		return fmt.Errorf("proof: %s", errorVerify)
+	}
+	return nil
+}
+
+// PVSS: the base point H, the key pairs of the trustees, the secret sG and a
+// deal consisting of the commitments and encrypted shares. Checking decrypts
+// the shares with the trustees' keys and recovers the secret.
+
+type pvssInputs struct {
+	H abstract.Point
+	x []abstract.Scalar
+	X []abstract.Point
+	s abstract.Scalar
+}
+
+func pvssValues(suite abstract.Suite, rand abstract.Cipher) (*values, *pvssInputs) {
+	vs := newValues(suite)
+	in := &pvssInputs{}
+	in.H, _ = suite.Point().Pick(nil, rand)
+	vs.set("H", in.H)
+	for i := 0; i < parties; i++ {
+		x := suite.Scalar().Pick(rand)
+		X := suite.Point().Mul(nil, x)
+		in.x = append(in.x, x)
+		in.X = append(in.X, X)
+		vs.set(fmt.Sprintf("x%d", i), x)
+		vs.set(fmt.Sprintf("X%d", i), X)
+	}
+	in.s = suite.Scalar().Pick(rand)
+	vs.set("secret", suite.Point().Mul(nil, in.s))
+	return vs, in
+}
+
+func genPVSS(suite abstract.Suite, rand abstract.Cipher) (map[string]string, error) {
+	vs, in := pvssValues(suite, rand)
+	encShares, pubPoly, err := pvss.EncShares(suite, in.H, in.X, in.s, threshold)
+	if err != nil {
+		return nil, err
+	}
+	_, commits := pubPoly.Info()
+	for i, C := range commits {
+		vs.set(fmt.Sprintf("commit%d", i), C)
+	}
+	for i, s := range encShares {
+		vs.set(fmt.Sprintf("encshare%d", i), s)
+	}
+	return vs.result()
+}
+
+func checkPVSS(suite abstract.Suite, rand abstract.Cipher, v *Vector) error {
+	vs, in := pvssValues(suite, rand)
+	m, err := vs.result()
+	if err != nil {
+		return err
+	}
+	if err := compare(v, m); err != nil {
+		return err
+	}
+	commits := make([]abstract.Point, threshold)
+	for i := range commits {
+		commits[i] = suite.Point()
+		if err := decode(suite, v, fmt.Sprintf("commit%d", i), commits[i]); err != nil {
+			return err
+		}
+	}
+	pubPoly := share.NewPubPoly(suite, in.H, commits)
+	var E, D []*pvss.PubVerShare
+	for i := 0; i < parties; i++ {
+		e := new(pvss.PubVerShare)
+		if err := decode(suite, v, fmt.Sprintf("encshare%d", i), e); err != nil {
+			return err
+		}
+		d, err := pvss.DecShare(suite, in.H, in.X[i], pubPoly.Eval(i).V, in.x[i], e)
+		if err != nil {
+			return fmt.Errorf("encshare%d: %s", i, errorVerify)
+		}
+		E = append(E, e)
+		D = append(D, d)
+	}
+	G := suite.Point().Base()
+	S, err := pvss.RecoverSecret(suite, G, in.X, E, D, threshold, parties)
+	if err != nil {
+		return err
+	}
+	if !S.Equal(suite.Point().Mul(G, in.s)) {
+		return fmt.Errorf("recovered secret: %s", errorMismatch)
+	}
+	return nil
+}
+
+// Schnorr signatures: the key pair and a signature on message.
+
+func schnorrValues(suite abstract.Suite, rand abstract.Cipher) (*values, abstract.Scalar, abstract.Point) {
+	vs := newValues(suite)
+	x := suite.Scalar().Pick(rand)
+	X := suite.Point().Mul(nil, x)
+	vs.set("private", x)
+	vs.set("public", X)
+	return vs, x, X
+}
+
+func genSchnorr(suite abstract.Suite, rand abstract.Cipher) (map[string]string, error) {
+	vs, x, _ := schnorrValues(suite, rand)
+	sig, err := sign.Schnorr(suite, x, message)
+	if err != nil {
+		return nil, err
+	}
+	m, err := vs.result()
+	if err != nil {
+		return nil, err
+	}
+	m["signature"] = hex.EncodeToString(sig)
+	return m, nil
+}
+
+func checkSchnorr(suite abstract.Suite, rand abstract.Cipher, v *Vector) error {
+	vs, _, X := schnorrValues(suite, rand)
+	m, err := vs.result()
+	if err != nil {
+		return err
+	}
+	if err := compare(v, m); err != nil {
+		return err
+	}
+	sig, err := hex.DecodeString(v.Values["signature"])
+	if err != nil {
+		return fmt.Errorf("signature: %s", err)
+	}
+	if err := sign.VerifySchnorr(suite, X, message, sig); err != nil {
+		return fmt.Errorf("signature: %s", errorVerify)
+	}
+	return nil
+}
+
+// EdDSA signatures are deterministic and always use Ed25519, whatever the
+// suite of the vector: the public key and the signature on message.
+
+func eddsaValues(rand abstract.Cipher) (map[string]string, error) {
+	ed := eddsa.NewEdDSA(rand)
+	pub, err := ed.Public.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	sig, err := ed.Sign(message)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]string{
+		"public":    hex.EncodeToString(pub),
+		"signature": hex.EncodeToString(sig),
+	}, nil
+}
+
+func genEdDSA(suite abstract.Suite, rand abstract.Cipher) (map[string]string, error) {
+	return eddsaValues(rand)
+}
+
+func checkEdDSA(suite abstract.Suite, rand abstract.Cipher, v *Vector) error {
+	m, err := eddsaValues(rand)
+	if err != nil {
+		return err
+	}
+	return compare(v, m)
+}
@@ -0,0 +1,191 @@
+// Package testvectors generates and checks golden test vectors for the secret
+// sharing, proof and signature schemes of this library. A vector is derived
+// from a fixed seed: every input is picked from a stream cipher keyed with
+// the seed, so generating a vector twice yields the same inputs and
+// deterministic outputs.
+//
+// Some schemes draw nonces from random.Stream internally (DLEQ proofs, PVSS
+// and Schnorr signatures). Their vectors record one proof or signature
+// produced from the seeded inputs; checking such a vector compares all
+// deterministic values exactly and verifies the recorded proof, so an
+// independent implementation can check both its outputs and its verifier
+// against the vectors.
+package testvectors
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/dedis/crypto/abstract"
+	"github.com/dedis/crypto/suites"
+)
+
+// Some error definitions
+var errorScheme = errors.New("unknown scheme")
+var errorMissing = errors.New("missing value")
+var errorMismatch = errors.New("value mismatch")
+var errorVerify = errors.New("verification failed")
+
+// Vector is a single test vector. All values are hex-encoded binary
+// encodings under the vector's suite.
+type Vector struct {
+	Scheme string            `json:"scheme"`
+	Suite  string            `json:"suite"`
+	Seed   string            `json:"seed"`
+	Values map[string]string `json:"values"`
+}
+
+// scheme generates the values of a vector from the seeded stream and checks
+// a vector against freshly generated values.
+type scheme struct {
+	generate func(suite abstract.Suite, rand abstract.Cipher) (map[string]string, error)
+	check    func(suite abstract.Suite, rand abstract.Cipher, v *Vector) error
+}
+
+var schemes = map[string]scheme{
+	"share":   {genShare, checkShare},
+	"dleq":    {genDLEQ, checkDLEQ},
+	"pvss":    {genPVSS, checkPVSS},
+	"schnorr": {genSchnorr, checkSchnorr},
+	"eddsa":   {genEdDSA, checkEdDSA},
+}
+
+// Schemes returns the names of all supported schemes in sorted order.
+func Schemes() []string {
+	var names []string
+	for name := range schemes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Generate creates the test vector of the given scheme for suite and seed.
+func Generate(suite abstract.Suite, name string, seed []byte) (*Vector, error) {
+	s, ok := schemes[name]
+	if !ok {
+		return nil, errorScheme
+	}
+	values, err := s.generate(suite, suite.Cipher(seed))
+	if err != nil {
+		return nil, err
+	}
+	return &Vector{name, suite.String(), hex.EncodeToString(seed), values}, nil
+}
+
+// GenerateAll creates the test vectors of all schemes for suite and seed.
+func GenerateAll(suite abstract.Suite, seed []byte) ([]*Vector, error) {
+	var vectors []*Vector
+	for _, name := range Schemes() {
+		v, err := Generate(suite, name, seed)
+		if err != nil {
+			return nil, fmt.Errorf("testvectors: %s: %s", name, err)
+		}
+		vectors = append(vectors, v)
+	}
+	return vectors, nil
+}
+
+// Check verifies the test vector v. It returns nil iff all deterministic
+// values match and all recorded proofs and signatures verify.
+func Check(v *Vector) error {
+	s, ok := schemes[v.Scheme]
+	if !ok {
+		return errorScheme
+	}
+	suite, err := suites.StringToSuite(v.Suite)
+	if err != nil {
+		return err
+	}
+	seed, err := hex.DecodeString(v.Seed)
+	if err != nil {
+		return err
+	}
+	if err := s.check(suite, suite.Cipher(seed), v); err != nil {
+		return fmt.Errorf("testvectors: %s on %s: %s", v.Scheme, v.Suite, err)
+	}
+	return nil
+}
+
+// Write encodes the vectors as indented JSON to w.
+func Write(w io.Writer, vectors []*Vector) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "\t")
+	return enc.Encode(vectors)
+}
+
+// Read decodes vectors written by Write from r.
+func Read(r io.Reader) ([]*Vector, error) {
+	var vectors []*Vector
+	if err := json.NewDecoder(r).Decode(&vectors); err != nil {
+		return nil, err
+	}
+	return vectors, nil
+}
+
+// encode returns the hex encoding of obj under the suite's binary encoding.
+func encode(suite abstract.Suite, obj interface{}) (string, error) {
+	var b bytes.Buffer
+	if err := suite.Write(&b, obj); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b.Bytes()), nil
+}
+
+// values collects encoded values of a vector.
+type values struct {
+	suite abstract.Suite
+	m     map[string]string
+	err   error
+}
+
+func newValues(suite abstract.Suite) *values {
+	return &values{suite: suite, m: make(map[string]string)}
+}
+
+func (vs *values) set(key string, obj interface{}) {
+	if vs.err != nil {
+		return
+	}
+	vs.m[key], vs.err = encode(vs.suite, obj)
+}
+
+func (vs *values) result() (map[string]string, error) {
+	return vs.m, vs.err
+}
+
+// compare checks that the values of v that are present in generated are
+// equal to the generated ones.
+func compare(v *Vector, generated map[string]string) error {
+	for key, want := range generated {
+		got, ok := v.Values[key]
+		if !ok {
+			return fmt.Errorf("%s: %s", key, errorMissing)
+		}
+		if got != want {
+			return fmt.Errorf("%s: %s", key, errorMismatch)
+		}
+	}
+	return nil
+}
+
+// decode decodes the value key of v into obj.
+func decode(suite abstract.Suite, v *Vector, key string, obj interface{}) error {
+	s, ok := v.Values[key]
+	if !ok {
+		return fmt.Errorf("%s: %s", key, errorMissing)
+	}
+	buf, err := hex.DecodeString(s)
+	if err != nil {
+		return fmt.Errorf("%s: %s", key, err)
+	}
+	if err := suite.Read(bytes.NewReader(buf), obj); err != nil {
+		return fmt.Errorf("%s: %s", key, err)
+	}
+	return nil
+}
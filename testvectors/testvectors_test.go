@@ -0,0 +1,67 @@
+package testvectors
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/dedis/crypto/abstract"
+	"github.com/dedis/crypto/edwards"
+	"github.com/dedis/crypto/nist"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVectors(t *testing.T) {
+	seed := []byte("golden")
+	for _, suite := range []abstract.Suite{edwards.NewAES128SHA256Ed25519(false), nist.NewAES128SHA256P256()} {
+		vectors, err := GenerateAll(suite, seed)
+		require.Nil(t, err)
+		require.Len(t, vectors, len(Schemes()))
+
+		var b bytes.Buffer
+		require.Nil(t, Write(&b, vectors))
+		read, err := Read(&b)
+		require.Nil(t, err)
+		for _, v := range read {
+			assert.Nil(t, Check(v))
+		}
+
+		// Deterministic schemes yield the same vector every time
+		v1, err := Generate(suite, "share", seed)
+		require.Nil(t, err)
+		v2, err := Generate(suite, "share", seed)
+		require.Nil(t, err)
+		assert.Equal(t, v1, v2)
+	}
+}
+
+func TestVectorsTampered(t *testing.T) {
+	suite := edwards.NewAES128SHA256Ed25519(false)
+	seed := []byte("golden")
+
+	// A proof for other inputs does not verify
+	v, err := Generate(suite, "dleq", seed)
+	require.Nil(t, err)
+	w, err := Generate(suite, "dleq", []byte("other"))
+	require.Nil(t, err)
+	v.Values["proof"] = w.Values["proof"]
+	assert.NotNil(t, Check(v))
+
+	v, err = Generate(suite, "pvss", seed)
+	require.Nil(t, err)
+	v.Values["encshare1"] = v.Values["encshare2"]
+	assert.NotNil(t, Check(v))
+
+	v, err = Generate(suite, "eddsa", seed)
+	require.Nil(t, err)
+	delete(v.Values, "signature")
+	assert.NotNil(t, Check(v))
+
+	v, err = Generate(suite, "schnorr", seed)
+	require.Nil(t, err)
+	v.Seed = "00"
+	assert.NotNil(t, Check(v))
+
+	_, err = Generate(suite, "unknown", seed)
+	assert.Equal(t, errorScheme, err)
+}
@@ -0,0 +1,259 @@
+// Package translog implements an authenticated append-only log in the style
+// of Certificate Transparency (RFC 6962). Entries, e.g., committee keys or
+// PVSS transcripts, are stored as leaves of a Merkle tree built with the
+// suite's hash function. The log operator periodically publishes signed tree
+// heads, and clients check with inclusion proofs that an entry is part of a
+// tree head and with consistency proofs that a newer tree head extends an
+// older one, i.e., that the log is append-only.
+package translog
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+
+	"github.com/dedis/crypto/abstract"
+	"github.com/dedis/crypto/sign"
+)
+
+// Some error definitions
+var errorIndex = errors.New("index out of range")
+var errorSize = errors.New("tree size out of range")
+var errorProof = errors.New("invalid proof")
+
+const (
+	leafPrefix = 0x00
+	nodePrefix = 0x01
+)
+
+// LeafHash returns the hash of a log entry.
+func LeafHash(suite abstract.Suite, data []byte) []byte {
+	h := suite.Hash()
+	h.Write([]byte{leafPrefix})
+	h.Write(data)
+	return h.Sum(nil)
+}
+
+func nodeHash(suite abstract.Suite, left, right []byte) []byte {
+	h := suite.Hash()
+	h.Write([]byte{nodePrefix})
+	h.Write(left)
+	h.Write(right)
+	return h.Sum(nil)
+}
+
+// split returns the largest power of two smaller than n, for n > 1.
+func split(n uint64) uint64 {
+	k := uint64(1)
+	for k<<1 < n {
+		k <<= 1
+	}
+	return k
+}
+
+// Log is an append-only list of entries.
+type Log struct {
+	suite  abstract.Suite
+	leaves [][]byte // Leaf hashes
+}
+
+// NewLog creates an empty log.
+func NewLog(suite abstract.Suite) *Log {
+	return &Log{suite: suite}
+}
+
+// Append adds an entry to the log and returns its index.
+func (l *Log) Append(data []byte) uint64 {
+	l.leaves = append(l.leaves, LeafHash(l.suite, data))
+	return uint64(len(l.leaves) - 1)
+}
+
+// Size returns the number of entries in the log.
+func (l *Log) Size() uint64 {
+	return uint64(len(l.leaves))
+}
+
+// Root returns the Merkle tree hash of the first size entries of the log.
+func (l *Log) Root(size uint64) ([]byte, error) {
+	if size > l.Size() {
+		return nil, errorSize
+	}
+	return l.root(l.leaves[:size]), nil
+}
+
+func (l *Log) root(leaves [][]byte) []byte {
+	switch n := uint64(len(leaves)); n {
+	case 0:
+		return l.suite.Hash().Sum(nil)
+	case 1:
+		return leaves[0]
+	default:
+		k := split(n)
+		return nodeHash(l.suite, l.root(leaves[:k]), l.root(leaves[k:]))
+	}
+}
+
+// InclusionProof returns the audit path proving that the entry with the given
+// index is part of the tree of the given size.
+func (l *Log) InclusionProof(index, size uint64) ([][]byte, error) {
+	if size > l.Size() {
+		return nil, errorSize
+	}
+	if index >= size {
+		return nil, errorIndex
+	}
+	return l.path(index, l.leaves[:size]), nil
+}
+
+func (l *Log) path(m uint64, leaves [][]byte) [][]byte {
+	n := uint64(len(leaves))
+	if n <= 1 {
+		return nil
+	}
+	k := split(n)
+	if m < k {
+		return append(l.path(m, leaves[:k]), l.root(leaves[k:]))
+	}
+	return append(l.path(m-k, leaves[k:]), l.root(leaves[:k]))
+}
+
+// ConsistencyProof returns the proof that the tree of size m is a prefix of
+// the tree of size n.
+func (l *Log) ConsistencyProof(m, n uint64) ([][]byte, error) {
+	if n > l.Size() || m > n {
+		return nil, errorSize
+	}
+	if m == 0 {
+		return nil, nil
+	}
+	return l.subproof(m, l.leaves[:n], true), nil
+}
+
+func (l *Log) subproof(m uint64, leaves [][]byte, complete bool) [][]byte {
+	n := uint64(len(leaves))
+	if m == n {
+		if complete {
+			return nil
+		}
+		return [][]byte{l.root(leaves)}
+	}
+	k := split(n)
+	if m <= k {
+		return append(l.subproof(m, leaves[:k], complete), l.root(leaves[k:]))
+	}
+	return append(l.subproof(m-k, leaves[k:], false), l.root(leaves[:k]))
+}
+
+// VerifyInclusion checks that the entry data with the given index is part of
+// the tree of the given size and root hash.
+func VerifyInclusion(suite abstract.Suite, data []byte, index, size uint64, proof [][]byte, root []byte) error {
+	if index >= size {
+		return errorIndex
+	}
+	fn, sn := index, size-1
+	r := LeafHash(suite, data)
+	for _, p := range proof {
+		if sn == 0 {
+			return errorProof
+		}
+		if fn&1 == 1 || fn == sn {
+			r = nodeHash(suite, p, r)
+			for fn&1 == 0 && fn != 0 {
+				fn >>= 1
+				sn >>= 1
+			}
+		} else {
+			r = nodeHash(suite, r, p)
+		}
+		fn >>= 1
+		sn >>= 1
+	}
+	if sn != 0 || !bytes.Equal(r, root) {
+		return errorProof
+	}
+	return nil
+}
+
+// VerifyConsistency checks that the tree of size m and root hash rootM is a
+// prefix of the tree of size n and root hash rootN.
+func VerifyConsistency(suite abstract.Suite, m, n uint64, rootM, rootN []byte, proof [][]byte) error {
+	if m > n {
+		return errorSize
+	}
+	if m == 0 || m == n {
+		if len(proof) != 0 || (m == n && !bytes.Equal(rootM, rootN)) {
+			return errorProof
+		}
+		return nil
+	}
+	if m&(m-1) == 0 {
+		// The old tree is a complete subtree of the new one
+		proof = append([][]byte{rootM}, proof...)
+	}
+	if len(proof) == 0 {
+		return errorProof
+	}
+	fn, sn := m-1, n-1
+	for fn&1 == 1 {
+		fn >>= 1
+		sn >>= 1
+	}
+	fr, sr := proof[0], proof[0]
+	for _, c := range proof[1:] {
+		if sn == 0 {
+			return errorProof
+		}
+		if fn&1 == 1 || fn == sn {
+			fr = nodeHash(suite, c, fr)
+			sr = nodeHash(suite, c, sr)
+			for fn&1 == 0 && fn != 0 {
+				fn >>= 1
+				sn >>= 1
+			}
+		} else {
+			sr = nodeHash(suite, sr, c)
+		}
+		fn >>= 1
+		sn >>= 1
+	}
+	if sn != 0 || !bytes.Equal(fr, rootM) || !bytes.Equal(sr, rootN) {
+		return errorProof
+	}
+	return nil
+}
+
+// TreeHead is a signed commitment of the log operator to the state of the log.
+type TreeHead struct {
+	Size      uint64 // Number of entries
+	Root      []byte // Merkle tree hash
+	Signature []byte // Schnorr signature of the log operator
+}
+
+func (th *TreeHead) message() []byte {
+	var b bytes.Buffer
+	b.WriteString("translog-tree-head")
+	binary.Write(&b, binary.BigEndian, th.Size)
+	b.Write(th.Root)
+	return b.Bytes()
+}
+
+// SignTreeHead returns the tree head of the current state of the log signed
+// with the operator's private key.
+func (l *Log) SignTreeHead(private abstract.Scalar) (*TreeHead, error) {
+	root, err := l.Root(l.Size())
+	if err != nil {
+		return nil, err
+	}
+	th := &TreeHead{Size: l.Size(), Root: root}
+	th.Signature, err = sign.Schnorr(l.suite, private, th.message())
+	if err != nil {
+		return nil, err
+	}
+	return th, nil
+}
+
+// VerifyTreeHead checks the signature of the log operator with the given
+// public key on the tree head.
+func VerifyTreeHead(suite abstract.Suite, public abstract.Point, th *TreeHead) error {
+	return sign.VerifySchnorr(suite, public, th.message(), th.Signature)
+}
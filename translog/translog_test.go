@@ -0,0 +1,91 @@
+package translog
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/dedis/crypto/edwards"
+	"github.com/dedis/crypto/random"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var suite = edwards.NewAES128SHA256Ed25519(false)
+
+func entry(i uint64) []byte {
+	return []byte(fmt.Sprintf("entry %d", i))
+}
+
+func newLog(n uint64) *Log {
+	l := NewLog(suite)
+	for i := uint64(0); i < n; i++ {
+		l.Append(entry(i))
+	}
+	return l
+}
+
+func TestInclusion(t *testing.T) {
+	n := uint64(13)
+	l := newLog(n)
+	for size := uint64(1); size <= n; size++ {
+		root, err := l.Root(size)
+		require.Nil(t, err)
+		for i := uint64(0); i < size; i++ {
+			proof, err := l.InclusionProof(i, size)
+			require.Nil(t, err)
+			require.Nil(t, VerifyInclusion(suite, entry(i), i, size, proof, root))
+			assert.Error(t, VerifyInclusion(suite, entry(i+1), i, size, proof, root))
+			if size > 1 {
+				assert.Error(t, VerifyInclusion(suite, entry(i), i, size, proof[1:], root))
+			}
+		}
+	}
+	_, err := l.InclusionProof(n, n)
+	assert.Equal(t, errorIndex, err)
+	_, err = l.Root(n + 1)
+	assert.Equal(t, errorSize, err)
+}
+
+func TestConsistency(t *testing.T) {
+	n := uint64(13)
+	l := newLog(n)
+	for size := uint64(0); size <= n; size++ {
+		rootN, err := l.Root(size)
+		require.Nil(t, err)
+		for m := uint64(0); m <= size; m++ {
+			rootM, err := l.Root(m)
+			require.Nil(t, err)
+			proof, err := l.ConsistencyProof(m, size)
+			require.Nil(t, err)
+			require.Nil(t, VerifyConsistency(suite, m, size, rootM, rootN, proof))
+			if m > 0 && m < size {
+				assert.Error(t, VerifyConsistency(suite, m, size, rootN, rootN, proof))
+			}
+		}
+	}
+
+	// A log that rewrites history cannot prove consistency
+	forked := NewLog(suite)
+	forked.Append([]byte("forged"))
+	for i := uint64(1); i < n; i++ {
+		forked.Append(entry(i))
+	}
+	rootM, _ := l.Root(5)
+	rootN, _ := forked.Root(n)
+	proof, err := forked.ConsistencyProof(5, n)
+	require.Nil(t, err)
+	assert.Error(t, VerifyConsistency(suite, 5, n, rootM, rootN, proof))
+}
+
+func TestTreeHead(t *testing.T) {
+	private := suite.Scalar().Pick(random.Stream)
+	public := suite.Point().Mul(nil, private)
+	l := newLog(5)
+	th, err := l.SignTreeHead(private)
+	require.Nil(t, err)
+	assert.Equal(t, uint64(5), th.Size)
+	require.Nil(t, VerifyTreeHead(suite, public, th))
+
+	th.Size++
+	assert.Error(t, VerifyTreeHead(suite, public, th))
+}
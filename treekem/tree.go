@@ -0,0 +1,57 @@
+package treekem
+
+// The ratchet tree is a complete binary tree stored in an array. Leaf i is at
+// node index 2i and parent nodes are at the odd indices in between, so that
+// the level of a node equals the number of trailing one bits of its index and
+// a tree with n leaves (a power of two) has its root at index n-1.
+
+func level(x int) uint {
+	k := uint(0)
+	for (x>>k)&1 == 1 {
+		k++
+	}
+	return k
+}
+
+func root(n int) int {
+	return n - 1
+}
+
+func left(x int) int {
+	return x ^ (1 << (level(x) - 1))
+}
+
+func right(x int) int {
+	return x ^ (3 << (level(x) - 1))
+}
+
+func parent(x int) int {
+	k := level(x)
+	b := (x >> (k + 1)) & 1
+	return (x | (1 << k)) ^ (b << (k + 1))
+}
+
+func sibling(x int) int {
+	p := parent(x)
+	if x < p {
+		return right(p)
+	}
+	return left(p)
+}
+
+// directPath returns the ancestors of x from its parent up to the root of a
+// tree with n leaves.
+func directPath(x, n int) []int {
+	var path []int
+	for r := root(n); x != r; {
+		x = parent(x)
+		path = append(path, x)
+	}
+	return path
+}
+
+// covers reports whether the subtree rooted at a contains the leaf node x.
+func covers(a, x int) bool {
+	k := level(a)
+	return x>>(k+1) == a>>(k+1)
+}
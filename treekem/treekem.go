@@ -0,0 +1,483 @@
+// Package treekem implements a TreeKEM-style continuous group key agreement.
+// The members of a group are the leaves of a ratchet tree in which every
+// non-blank node holds a Diffie-Hellman key pair whose private key is known to
+// exactly the members below it. A member changes the group by sending a
+// Commit, which replaces all keys on its path to the root with fresh keys
+// derived from a chain of path secrets and encrypts each path secret to the
+// sibling subtree, so that every other member learns the secrets from the
+// lowest common ancestor upwards with a single decryption. The secret beyond
+// the root advances the group to a new epoch with a new group key.
+//
+// Three operations are supported, each carried by a Commit:
+//
+// - Add places the key package of a new member at an empty leaf. The new
+// member joins with the Welcome message returned alongside the commit.
+//
+// - Remove blanks the leaf of a member together with its path, so that the
+// removed member cannot decrypt any later epoch.
+//
+// - Update refreshes the committer's own path to heal from a compromise.
+//
+// Commits must be processed by all members in the same order. Members learn
+// about each other's keys only through this package, authentication of the
+// commit senders is left to the caller.
+package treekem
+
+import (
+	"crypto/cipher"
+	"crypto/hmac"
+	"encoding/binary"
+	"errors"
+
+	"github.com/dedis/crypto/abstract"
+)
+
+// Kinds of operations carried by a commit.
+const (
+	Update = iota
+	Add
+	Remove
+)
+
+// Some error definitions
+var errorCapacity = errors.New("capacity must be a power of two")
+var errorFull = errors.New("group is full")
+var errorLeaf = errors.New("invalid leaf")
+var errorEpoch = errors.New("commit is not for the current epoch")
+var errorOperation = errors.New("invalid operation")
+var errorPath = errors.New("invalid update path")
+var errorRemoved = errors.New("member was removed from the group")
+var errorWelcome = errors.New("invalid welcome")
+var errorDecryption = errors.New("decryption failed")
+
+// Ciphertext is a path secret encrypted to a node's public key.
+type Ciphertext struct {
+	K abstract.Point // Ephemeral public key
+	C []byte         // Authenticated encryption of the secret
+}
+
+// PathNode is the new public key of a node on the committer's path together
+// with the encryptions of the node's path secret to the resolution of the
+// sibling subtree.
+type PathNode struct {
+	Public      abstract.Point
+	Ciphertexts []*Ciphertext
+}
+
+// Commit changes the group and advances it to the next epoch.
+type Commit struct {
+	Epoch  uint64         // Epoch the commit applies to
+	Sender int            // Leaf of the committer
+	Op     int            // Kind of operation
+	Target int            // Leaf of the added or removed member
+	Key    abstract.Point // Leaf key of the added member
+	Path   []*PathNode    // Committer's leaf followed by its direct path
+}
+
+// Welcome lets a new member join the group.
+type Welcome struct {
+	Leaf    int              // Leaf of the new member
+	Epoch   uint64           // Epoch the new member joins in
+	Tree    []abstract.Point // Public keys of all nodes, nil for blank ones
+	Secrets *Ciphertext      // Epoch secret and path secret of the common ancestor
+}
+
+// KeyPackage is the leaf key pair of a prospective member.
+type KeyPackage struct {
+	Private abstract.Scalar
+	Public  abstract.Point
+}
+
+// NewKeyPackage creates a fresh key package.
+func NewKeyPackage(suite abstract.Suite, rand cipher.Stream) *KeyPackage {
+	x := suite.Scalar().Pick(rand)
+	return &KeyPackage{Private: x, Public: suite.Point().Mul(nil, x)}
+}
+
+// Member is a member's view of the group.
+type Member struct {
+	suite       abstract.Suite
+	leaf        int
+	n           int                     // Number of leaves
+	pub         []abstract.Point        // Public keys of the nodes
+	priv        map[int]abstract.Scalar // Private keys of the nodes on the member's path
+	epoch       uint64
+	epochSecret []byte
+}
+
+// NewGroup creates a group with room for the given number of members, which
+// must be a power of two, with the caller as its only member.
+func NewGroup(suite abstract.Suite, capacity int, rand cipher.Stream) (*Member, error) {
+	if capacity < 2 || capacity&(capacity-1) != 0 {
+		return nil, errorCapacity
+	}
+	kp := NewKeyPackage(suite, rand)
+	m := &Member{
+		suite:       suite,
+		n:           capacity,
+		pub:         make([]abstract.Point, 2*capacity-1),
+		priv:        map[int]abstract.Scalar{0: kp.Private},
+		epochSecret: randomBytes(suite, rand),
+	}
+	m.pub[0] = kp.Public
+	return m, nil
+}
+
+// Join creates the member of the given key package from a welcome message.
+func Join(suite abstract.Suite, kp *KeyPackage, w *Welcome) (*Member, error) {
+	n := (len(w.Tree) + 1) / 2
+	if n < 2 || n&(n-1) != 0 || len(w.Tree) != 2*n-1 {
+		return nil, errorWelcome
+	}
+	if w.Leaf < 0 || w.Leaf >= n || w.Tree[2*w.Leaf] == nil || !w.Tree[2*w.Leaf].Equal(kp.Public) {
+		return nil, errorWelcome
+	}
+	secrets, err := decrypt(suite, kp.Private, w.Secrets)
+	if err != nil {
+		return nil, err
+	}
+	h := suite.Hash().Size()
+	if len(secrets) != 2*h {
+		return nil, errorWelcome
+	}
+	m := &Member{
+		suite:       suite,
+		leaf:        w.Leaf,
+		n:           n,
+		pub:         append([]abstract.Point{}, w.Tree...),
+		priv:        map[int]abstract.Scalar{2 * w.Leaf: kp.Private},
+		epoch:       w.Epoch,
+		epochSecret: secrets[:h],
+	}
+
+	// Derive the keys from the lowest non-blank ancestor upwards
+	path := directPath(2*w.Leaf, n)
+	i := 0
+	for i < len(path) && m.pub[path[i]] == nil {
+		i++
+	}
+	ps := secrets[h:]
+	for _, v := range path[i:] {
+		sk, pk := nodeKey(suite, ps)
+		if m.pub[v] == nil || !pk.Equal(m.pub[v]) {
+			return nil, errorWelcome
+		}
+		m.priv[v] = sk
+		ps = kdf(suite, ps, "path")
+	}
+	return m, nil
+}
+
+// Leaf returns the member's leaf index.
+func (m *Member) Leaf() int {
+	return m.leaf
+}
+
+// Epoch returns the current epoch.
+func (m *Member) Epoch() uint64 {
+	return m.epoch
+}
+
+// GroupKey returns the group key of the current epoch.
+func (m *Member) GroupKey() []byte {
+	return kdf(m.suite, m.epochSecret, "group key")
+}
+
+// Members returns the leaves of all current members.
+func (m *Member) Members() []int {
+	var leaves []int
+	for i := 0; i < m.n; i++ {
+		if m.pub[2*i] != nil {
+			leaves = append(leaves, i)
+		}
+	}
+	return leaves
+}
+
+// Update refreshes the member's keys.
+func (m *Member) Update(rand cipher.Stream) (*Commit, error) {
+	c, _, err := m.commit(&Commit{Op: Update}, rand)
+	return c, err
+}
+
+// Add adds the owner of the key package with the given public key to the
+// group. The welcome message is to be sent to the new member.
+func (m *Member) Add(key abstract.Point, rand cipher.Stream) (*Commit, *Welcome, error) {
+	target := -1
+	for i := 0; i < m.n; i++ {
+		if m.pub[2*i] == nil {
+			target = i
+			break
+		}
+	}
+	if target < 0 {
+		return nil, nil, errorFull
+	}
+	c, secrets, err := m.commit(&Commit{Op: Add, Target: target, Key: key}, rand)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// The new member learns the path secrets from the lowest common ancestor
+	var ps []byte
+	for _, v := range directPath(2*m.leaf, m.n) {
+		if covers(v, 2*target) {
+			ps = secrets[v]
+			break
+		}
+	}
+	ct, err := encrypt(m.suite, key, append(append([]byte{}, m.epochSecret...), ps...), rand)
+	if err != nil {
+		return nil, nil, err
+	}
+	w := &Welcome{
+		Leaf:    target,
+		Epoch:   m.epoch,
+		Tree:    append([]abstract.Point{}, m.pub...),
+		Secrets: ct,
+	}
+	return c, w, nil
+}
+
+// Remove removes the member at the given leaf from the group.
+func (m *Member) Remove(leaf int, rand cipher.Stream) (*Commit, error) {
+	if leaf < 0 || leaf >= m.n || leaf == m.leaf || m.pub[2*leaf] == nil {
+		return nil, errorLeaf
+	}
+	c, _, err := m.commit(&Commit{Op: Remove, Target: leaf}, rand)
+	return c, err
+}
+
+// commit applies the operation, refreshes the member's path and advances to
+// the next epoch. It returns the commit and the path secrets of the nodes on
+// the member's direct path.
+func (m *Member) commit(c *Commit, rand cipher.Stream) (*Commit, map[int][]byte, error) {
+	c.Epoch = m.epoch
+	c.Sender = m.leaf
+	pub, priv := m.clone()
+	if err := applyOp(pub, priv, m.n, c); err != nil {
+		return nil, nil, err
+	}
+
+	x := 2 * m.leaf
+	ps := randomBytes(m.suite, rand)
+	sk, pk := nodeKey(m.suite, ps)
+	pub[x], priv[x] = pk, sk
+	c.Path = []*PathNode{{Public: pk}}
+	secrets := make(map[int][]byte)
+	child := x
+	for _, v := range directPath(x, m.n) {
+		ps = kdf(m.suite, ps, "path")
+		sk, pk := nodeKey(m.suite, ps)
+		pub[v], priv[v] = pk, sk
+		node := &PathNode{Public: pk}
+		for _, r := range resolution(pub, sibling(child)) {
+			ct, err := encrypt(m.suite, pub[r], ps, rand)
+			if err != nil {
+				return nil, nil, err
+			}
+			node.Ciphertexts = append(node.Ciphertexts, ct)
+		}
+		c.Path = append(c.Path, node)
+		secrets[v] = ps
+		child = v
+	}
+	m.pub, m.priv = pub, priv
+	m.advance(kdf(m.suite, ps, "path"))
+	return c, secrets, nil
+}
+
+// Process applies a commit of another member and advances to the next epoch.
+func (m *Member) Process(c *Commit) error {
+	if c.Epoch != m.epoch {
+		return errorEpoch
+	}
+	if c.Sender < 0 || c.Sender >= m.n || c.Sender == m.leaf || m.pub[2*c.Sender] == nil {
+		return errorLeaf
+	}
+	if c.Op == Remove && c.Target == m.leaf {
+		return errorRemoved
+	}
+	pub, priv := m.clone()
+	if err := applyOp(pub, priv, m.n, c); err != nil {
+		return err
+	}
+
+	s := 2 * c.Sender
+	path := directPath(s, m.n)
+	if len(c.Path) != len(path)+1 {
+		return errorPath
+	}
+	for _, node := range c.Path {
+		if node == nil || node.Public == nil {
+			return errorPath
+		}
+	}
+
+	// Decrypt the path secret of the lowest common ancestor
+	i := 0
+	for !covers(path[i], 2*m.leaf) {
+		i++
+	}
+	child := s
+	if i > 0 {
+		child = path[i-1]
+	}
+	res := resolution(pub, sibling(child))
+	cts := c.Path[i+1].Ciphertexts
+	if len(cts) != len(res) {
+		return errorPath
+	}
+	var ps []byte
+	for j, r := range res {
+		if sk, ok := priv[r]; ok {
+			var err error
+			if ps, err = decrypt(m.suite, sk, cts[j]); err != nil {
+				return err
+			}
+			break
+		}
+	}
+	if ps == nil {
+		return errorPath
+	}
+
+	// Derive and check the keys from the common ancestor upwards
+	pub[s] = c.Path[0].Public
+	for k, v := range path {
+		pub[v] = c.Path[k+1].Public
+		if k < i {
+			continue
+		}
+		sk, pk := nodeKey(m.suite, ps)
+		if !pk.Equal(pub[v]) {
+			return errorPath
+		}
+		priv[v] = sk
+		ps = kdf(m.suite, ps, "path")
+	}
+	m.pub, m.priv = pub, priv
+	m.advance(ps)
+	return nil
+}
+
+// advance derives the secret of the next epoch from the current one and the
+// commit secret.
+func (m *Member) advance(commitSecret []byte) {
+	var e [8]byte
+	binary.BigEndian.PutUint64(e[:], m.epoch)
+	m.epochSecret = kdf(m.suite, m.epochSecret, "epoch", e[:], commitSecret)
+	m.epoch++
+}
+
+func (m *Member) clone() ([]abstract.Point, map[int]abstract.Scalar) {
+	pub := append([]abstract.Point{}, m.pub...)
+	priv := make(map[int]abstract.Scalar, len(m.priv))
+	for k, v := range m.priv {
+		priv[k] = v
+	}
+	return pub, priv
+}
+
+// applyOp applies the operation of the commit to the tree.
+func applyOp(pub []abstract.Point, priv map[int]abstract.Scalar, n int, c *Commit) error {
+	switch c.Op {
+	case Update:
+		return nil
+	case Add:
+		if c.Target < 0 || c.Target >= n || pub[2*c.Target] != nil || c.Key == nil {
+			return errorOperation
+		}
+		blank(pub, priv, n, 2*c.Target)
+		pub[2*c.Target] = c.Key
+		return nil
+	case Remove:
+		if c.Target < 0 || c.Target >= n || c.Target == c.Sender || pub[2*c.Target] == nil {
+			return errorOperation
+		}
+		blank(pub, priv, n, 2*c.Target)
+		return nil
+	}
+	return errorOperation
+}
+
+// blank clears the node x and its direct path.
+func blank(pub []abstract.Point, priv map[int]abstract.Scalar, n, x int) {
+	for _, v := range append([]int{x}, directPath(x, n)...) {
+		pub[v] = nil
+		delete(priv, v)
+	}
+}
+
+// resolution returns the minimal set of non-blank nodes covering all
+// non-blank leaves below x.
+func resolution(pub []abstract.Point, x int) []int {
+	if pub[x] != nil {
+		return []int{x}
+	}
+	if level(x) == 0 {
+		return nil
+	}
+	return append(resolution(pub, left(x)), resolution(pub, right(x))...)
+}
+
+func kdf(suite abstract.Suite, secret []byte, label string, data ...[]byte) []byte {
+	mac := hmac.New(suite.Hash, secret)
+	mac.Write([]byte(label))
+	for _, d := range data {
+		mac.Write(d)
+	}
+	return mac.Sum(nil)
+}
+
+func nodeKey(suite abstract.Suite, ps []byte) (abstract.Scalar, abstract.Point) {
+	sk := suite.Scalar().Pick(suite.Cipher(kdf(suite, ps, "node")))
+	return sk, suite.Point().Mul(nil, sk)
+}
+
+func randomBytes(suite abstract.Suite, rand cipher.Stream) []byte {
+	b := make([]byte, suite.Hash().Size())
+	rand.XORKeyStream(b, b)
+	return b
+}
+
+func encrypt(suite abstract.Suite, X abstract.Point, msg []byte, rand cipher.Stream) (*Ciphertext, error) {
+	e := suite.Scalar().Pick(rand)
+	K := suite.Point().Mul(nil, e)
+	key, err := sharedKey(suite, K, suite.Point().Mul(X, e))
+	if err != nil {
+		return nil, err
+	}
+	return &Ciphertext{K: K, C: suite.Cipher(key).Seal(nil, msg)}, nil
+}
+
+func decrypt(suite abstract.Suite, x abstract.Scalar, ct *Ciphertext) ([]byte, error) {
+	if ct == nil || ct.K == nil {
+		return nil, errorDecryption
+	}
+	key, err := sharedKey(suite, ct.K, suite.Point().Mul(ct.K, x))
+	if err != nil {
+		return nil, err
+	}
+	sym := suite.Cipher(key)
+	if len(ct.C) <= sym.KeySize() {
+		return nil, errorDecryption
+	}
+	// Open checks the authenticator in place, so work on a copy
+	msg, err := sym.Open(nil, append([]byte{}, ct.C...))
+	if err != nil {
+		return nil, errorDecryption
+	}
+	return msg, nil
+}
+
+func sharedKey(suite abstract.Suite, K, S abstract.Point) ([]byte, error) {
+	h := suite.Hash()
+	h.Write([]byte("treekem"))
+	for _, P := range []abstract.Point{K, S} {
+		if _, err := P.MarshalTo(h); err != nil {
+			return nil, err
+		}
+	}
+	return h.Sum(nil), nil
+}
@@ -0,0 +1,117 @@
+package treekem
+
+import (
+	"testing"
+
+	"github.com/dedis/crypto/edwards"
+	"github.com/dedis/crypto/random"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var suite = edwards.NewAES128SHA256Ed25519(false)
+
+func TestTree(t *testing.T) {
+	assert.Equal(t, 7, root(8))
+	assert.Equal(t, []int{1, 3, 7}, directPath(0, 8))
+	assert.Equal(t, []int{9, 11, 7}, directPath(8, 8))
+	assert.Equal(t, 5, sibling(1))
+	assert.Equal(t, 3, left(7))
+	assert.Equal(t, 11, right(7))
+	assert.True(t, covers(3, 6))
+	assert.False(t, covers(3, 8))
+	assert.True(t, covers(7, 14))
+}
+
+// commit sends c to all members except the sender and checks that the group
+// agrees on the new key.
+func commit(t *testing.T, c *Commit, sender *Member, members ...*Member) {
+	for _, m := range members {
+		require.Nil(t, m.Process(c))
+		assert.Equal(t, sender.Epoch(), m.Epoch())
+		assert.Equal(t, sender.GroupKey(), m.GroupKey())
+	}
+}
+
+func TestGroup(t *testing.T) {
+	alice, err := NewGroup(suite, 8, random.Stream)
+	require.Nil(t, err)
+
+	// Alice adds Bob
+	kpB := NewKeyPackage(suite, random.Stream)
+	c, w, err := alice.Add(kpB.Public, random.Stream)
+	require.Nil(t, err)
+	assert.Equal(t, uint64(0), c.Epoch)
+	bob, err := Join(suite, kpB, w)
+	require.Nil(t, err)
+	assert.Equal(t, alice.GroupKey(), bob.GroupKey())
+
+	// Bob adds Carol
+	kpC := NewKeyPackage(suite, random.Stream)
+	c, w, err = bob.Add(kpC.Public, random.Stream)
+	require.Nil(t, err)
+	commit(t, c, bob, alice)
+	carol, err := Join(suite, kpC, w)
+	require.Nil(t, err)
+	assert.Equal(t, alice.GroupKey(), carol.GroupKey())
+	assert.Equal(t, []int{0, 1, 2}, carol.Members())
+
+	// Carol updates her keys
+	c, err = carol.Update(random.Stream)
+	require.Nil(t, err)
+	commit(t, c, carol, alice, bob)
+	assert.Equal(t, errorEpoch, alice.Process(c))
+
+	// Alice removes Bob, who is locked out of the new epoch
+	old := bob.GroupKey()
+	c, err = alice.Remove(bob.Leaf(), random.Stream)
+	require.Nil(t, err)
+	commit(t, c, alice, carol)
+	assert.Equal(t, errorRemoved, bob.Process(c))
+	assert.Equal(t, old, bob.GroupKey())
+	assert.NotEqual(t, old, alice.GroupKey())
+	assert.Equal(t, []int{0, 2}, alice.Members())
+
+	// Dave takes Bob's leaf
+	kpD := NewKeyPackage(suite, random.Stream)
+	c, w, err = carol.Add(kpD.Public, random.Stream)
+	require.Nil(t, err)
+	commit(t, c, carol, alice)
+	dave, err := Join(suite, kpD, w)
+	require.Nil(t, err)
+	assert.Equal(t, 1, dave.Leaf())
+	c, err = dave.Update(random.Stream)
+	require.Nil(t, err)
+	commit(t, c, dave, alice, carol)
+}
+
+func TestGroupInvalid(t *testing.T) {
+	_, err := NewGroup(suite, 6, random.Stream)
+	assert.Equal(t, errorCapacity, err)
+
+	alice, err := NewGroup(suite, 2, random.Stream)
+	require.Nil(t, err)
+	kpB := NewKeyPackage(suite, random.Stream)
+	c, w, err := alice.Add(kpB.Public, random.Stream)
+	require.Nil(t, err)
+	_, _, err = alice.Add(kpB.Public, random.Stream)
+	assert.Equal(t, errorFull, err)
+	_, err = alice.Remove(alice.Leaf(), random.Stream)
+	assert.Equal(t, errorLeaf, err)
+
+	// A welcome cannot be used with another key package
+	_, err = Join(suite, NewKeyPackage(suite, random.Stream), w)
+	assert.Equal(t, errorWelcome, err)
+	bob, err := Join(suite, kpB, w)
+	require.Nil(t, err)
+
+	// A commit with a tampered path is rejected without changing the state
+	c, err = alice.Update(random.Stream)
+	require.Nil(t, err)
+	pk := c.Path[1].Public
+	c.Path[1].Public = suite.Point().Base()
+	assert.Equal(t, errorPath, bob.Process(c))
+	c.Path[1].Public = pk
+	require.Nil(t, bob.Process(c))
+	assert.Equal(t, alice.GroupKey(), bob.GroupKey())
+}
@@ -0,0 +1,227 @@
+// Package typed provides generic wrappers around the dynamically typed points
+// and scalars of the abstract package. A Point[S] or Scalar[S] carries its
+// ciphersuite S in its type, so combining elements of different suites is
+// rejected by the compiler instead of panicking at run time:
+//
+//	x := typed.PickScalar[typed.Ed25519](random.Stream)
+//	X := typed.Base[typed.Ed25519]().Mul(x)
+//	Y := typed.Base[typed.P256]()
+//	X.Add(Y) // compile-time error
+//
+// Points and scalars are immutable values: every operation returns a new
+// element. The zero Point is the neutral element and the zero Scalar is zero.
+package typed
+
+import (
+	"crypto/cipher"
+
+	"github.com/dedis/crypto/abstract"
+	"github.com/dedis/crypto/ed25519"
+	"github.com/dedis/crypto/nist"
+)
+
+// Suite is implemented by marker types that tie a ciphersuite to a Go type.
+// Suite must return the same ciphersuite on every call.
+type Suite interface {
+	Suite() abstract.Suite
+}
+
+var (
+	ed25519Suite = ed25519.NewAES128SHA256Ed25519(false)
+	p256Suite    = nist.NewAES128SHA256P256()
+)
+
+// Ed25519 is the marker type of the Ed25519 suite.
+type Ed25519 struct{}
+
+// Suite returns the Ed25519 ciphersuite.
+func (Ed25519) Suite() abstract.Suite { return ed25519Suite }
+
+// P256 is the marker type of the NIST P-256 suite.
+type P256 struct{}
+
+// Suite returns the P-256 ciphersuite.
+func (P256) Suite() abstract.Suite { return p256Suite }
+
+func suiteOf[S Suite]() abstract.Suite {
+	var s S
+	return s.Suite()
+}
+
+// Point is a point of the group of suite S.
+type Point[S Suite] struct {
+	p abstract.Point
+}
+
+// Scalar is a scalar of the group of suite S.
+type Scalar[S Suite] struct {
+	s abstract.Scalar
+}
+
+// WrapPoint wraps p, which must be a point of suite S.
+func WrapPoint[S Suite](p abstract.Point) Point[S] {
+	return Point[S]{p.Clone()}
+}
+
+// WrapScalar wraps s, which must be a scalar of suite S.
+func WrapScalar[S Suite](s abstract.Scalar) Scalar[S] {
+	return Scalar[S]{s.Clone()}
+}
+
+// Null returns the neutral element of suite S.
+func Null[S Suite]() Point[S] {
+	return Point[S]{suiteOf[S]().Point().Null()}
+}
+
+// Base returns the standard base point of suite S.
+func Base[S Suite]() Point[S] {
+	return Point[S]{suiteOf[S]().Point().Base()}
+}
+
+// PickPoint returns a random point of suite S.
+func PickPoint[S Suite](rand cipher.Stream) Point[S] {
+	p, _ := suiteOf[S]().Point().Pick(nil, rand)
+	return Point[S]{p}
+}
+
+// UnmarshalPoint decodes a point of suite S.
+func UnmarshalPoint[S Suite](buf []byte) (Point[S], error) {
+	p := suiteOf[S]().Point()
+	if err := p.UnmarshalBinary(buf); err != nil {
+		return Point[S]{}, err
+	}
+	return Point[S]{p}, nil
+}
+
+func (p Point[S]) get() abstract.Point {
+	if p.p == nil {
+		return suiteOf[S]().Point().Null()
+	}
+	return p.p
+}
+
+// Unwrap returns a copy of the underlying point.
+func (p Point[S]) Unwrap() abstract.Point {
+	return p.get().Clone()
+}
+
+// Equal reports whether p and q are equal.
+func (p Point[S]) Equal(q Point[S]) bool {
+	return p.get().Equal(q.get())
+}
+
+// Add returns p + q.
+func (p Point[S]) Add(q Point[S]) Point[S] {
+	return Point[S]{suiteOf[S]().Point().Add(p.get(), q.get())}
+}
+
+// Sub returns p - q.
+func (p Point[S]) Sub(q Point[S]) Point[S] {
+	return Point[S]{suiteOf[S]().Point().Sub(p.get(), q.get())}
+}
+
+// Neg returns -p.
+func (p Point[S]) Neg() Point[S] {
+	return Point[S]{suiteOf[S]().Point().Neg(p.get())}
+}
+
+// Mul returns s * p.
+func (p Point[S]) Mul(s Scalar[S]) Point[S] {
+	return Point[S]{suiteOf[S]().Point().Mul(p.get(), s.get())}
+}
+
+// MarshalBinary encodes p.
+func (p Point[S]) MarshalBinary() ([]byte, error) {
+	return p.get().MarshalBinary()
+}
+
+// String returns the string representation of p.
+func (p Point[S]) String() string {
+	return p.get().String()
+}
+
+// Zero returns the zero scalar of suite S.
+func Zero[S Suite]() Scalar[S] {
+	return Scalar[S]{suiteOf[S]().Scalar().Zero()}
+}
+
+// One returns the scalar one of suite S.
+func One[S Suite]() Scalar[S] {
+	return Scalar[S]{suiteOf[S]().Scalar().One()}
+}
+
+// Int64 returns the scalar v of suite S.
+func Int64[S Suite](v int64) Scalar[S] {
+	return Scalar[S]{suiteOf[S]().Scalar().SetInt64(v)}
+}
+
+// PickScalar returns a random scalar of suite S.
+func PickScalar[S Suite](rand cipher.Stream) Scalar[S] {
+	return Scalar[S]{suiteOf[S]().Scalar().Pick(rand)}
+}
+
+// UnmarshalScalar decodes a scalar of suite S.
+func UnmarshalScalar[S Suite](buf []byte) (Scalar[S], error) {
+	s := suiteOf[S]().Scalar()
+	if err := s.UnmarshalBinary(buf); err != nil {
+		return Scalar[S]{}, err
+	}
+	return Scalar[S]{s}, nil
+}
+
+func (s Scalar[S]) get() abstract.Scalar {
+	if s.s == nil {
+		return suiteOf[S]().Scalar().Zero()
+	}
+	return s.s
+}
+
+// Unwrap returns a copy of the underlying scalar.
+func (s Scalar[S]) Unwrap() abstract.Scalar {
+	return s.get().Clone()
+}
+
+// Equal reports whether s and t are equal.
+func (s Scalar[S]) Equal(t Scalar[S]) bool {
+	return s.get().Equal(t.get())
+}
+
+// Add returns s + t.
+func (s Scalar[S]) Add(t Scalar[S]) Scalar[S] {
+	return Scalar[S]{suiteOf[S]().Scalar().Add(s.get(), t.get())}
+}
+
+// Sub returns s - t.
+func (s Scalar[S]) Sub(t Scalar[S]) Scalar[S] {
+	return Scalar[S]{suiteOf[S]().Scalar().Sub(s.get(), t.get())}
+}
+
+// Neg returns -s.
+func (s Scalar[S]) Neg() Scalar[S] {
+	return Scalar[S]{suiteOf[S]().Scalar().Neg(s.get())}
+}
+
+// Mul returns s * t.
+func (s Scalar[S]) Mul(t Scalar[S]) Scalar[S] {
+	return Scalar[S]{suiteOf[S]().Scalar().Mul(s.get(), t.get())}
+}
+
+// Div returns s / t.
+func (s Scalar[S]) Div(t Scalar[S]) Scalar[S] {
+	return Scalar[S]{suiteOf[S]().Scalar().Div(s.get(), t.get())}
+}
+
+// Inv returns 1 / s.
+func (s Scalar[S]) Inv() Scalar[S] {
+	return Scalar[S]{suiteOf[S]().Scalar().Inv(s.get())}
+}
+
+// MarshalBinary encodes s.
+func (s Scalar[S]) MarshalBinary() ([]byte, error) {
+	return s.get().MarshalBinary()
+}
+
+// String returns the string representation of s.
+func (s Scalar[S]) String() string {
+	return s.get().String()
+}
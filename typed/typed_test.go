@@ -0,0 +1,61 @@
+package typed
+
+import (
+	"testing"
+
+	"github.com/dedis/crypto/random"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// dh checks the Diffie-Hellman identity for any suite.
+func dh[S Suite](t *testing.T) {
+	a := PickScalar[S](random.Stream)
+	b := PickScalar[S](random.Stream)
+	A := Base[S]().Mul(a)
+	B := Base[S]().Mul(b)
+	assert.True(t, A.Mul(b).Equal(B.Mul(a)))
+	assert.True(t, Base[S]().Mul(a.Mul(b)).Equal(A.Mul(b)))
+	assert.True(t, A.Add(B).Sub(B).Equal(A))
+	assert.True(t, A.Add(A.Neg()).Equal(Null[S]()))
+	assert.True(t, a.Div(b).Mul(b).Equal(a))
+	assert.True(t, a.Mul(a.Inv()).Equal(One[S]()))
+	assert.True(t, a.Add(a).Equal(Int64[S](2).Mul(a)))
+
+	buf, err := A.MarshalBinary()
+	require.Nil(t, err)
+	A2, err := UnmarshalPoint[S](buf)
+	require.Nil(t, err)
+	assert.True(t, A.Equal(A2))
+	buf, err = a.MarshalBinary()
+	require.Nil(t, err)
+	a2, err := UnmarshalScalar[S](buf)
+	require.Nil(t, err)
+	assert.True(t, a.Equal(a2))
+
+	// Zero values are the neutral elements
+	var O Point[S]
+	var z Scalar[S]
+	assert.True(t, O.Equal(Null[S]()))
+	assert.True(t, z.Equal(Zero[S]()))
+	assert.True(t, A.Add(O).Equal(A))
+	assert.True(t, A.Mul(z).Equal(O))
+}
+
+func TestTyped(t *testing.T) {
+	dh[Ed25519](t)
+	dh[P256](t)
+}
+
+func TestWrap(t *testing.T) {
+	suite := Ed25519{}.Suite()
+	x := suite.Scalar().Pick(random.Stream)
+	X := suite.Point().Mul(nil, x)
+	tx := WrapScalar[Ed25519](x)
+	tX := WrapPoint[Ed25519](X)
+	assert.True(t, Base[Ed25519]().Mul(tx).Equal(tX))
+
+	// Wrapped values are copies
+	X.Null()
+	assert.False(t, tX.Unwrap().Equal(X))
+}
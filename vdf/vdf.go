@@ -0,0 +1,203 @@
+// Package vdf implements Wesolowski's verifiable delay function over an RSA
+// group of unknown order. Evaluating the function requires T sequential
+// squarings, whereas the output comes with a succinct proof that can be
+// verified with two small exponentiations. A typical use is to post-process
+// the output of a randomness beacon so that no participant, including the last
+// one to reveal its contribution, can predict the result in time to bias it.
+//
+// Security relies on nobody knowing the factorization of the modulus N, so N
+// should either stem from a trusted setup whose factors have been destroyed or
+// be a well-known modulus like the RSA-2048 challenge number. Since -1 is an
+// element of known order, the function works in the quotient group
+// Z_N^*/{1, -1}, whose elements are represented by min(a, N-a). Otherwise,
+// (-y, -pi) would be a second valid output for every output (y, pi).
+//
+// For further background see:
+//
+//	"Efficient verifiable delay functions" by Benjamin Wesolowski
+//	https://eprint.iacr.org/2018/623.pdf
+package vdf
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math/big"
+)
+
+// Some error definitions
+var errorInvalidOutput = errors.New("invalid VDF output")
+var errorInvalidProof = errors.New("invalid VDF proof")
+
+// primeBits is the bit length of the Fiat-Shamir challenge prime.
+const primeBits = 128
+
+var one = big.NewInt(1)
+var two = big.NewInt(2)
+
+// VDF represents a verifiable delay function with modulus N and delay
+// parameter T, the number of sequential squarings.
+type VDF struct {
+	N *big.Int // RSA modulus of unknown factorization
+	T uint64   // Number of squarings
+}
+
+// Output holds the result y = g^(2^T) of an evaluation together with the
+// Wesolowski proof pi, both in canonical form.
+type Output struct {
+	Y  *big.Int // Output of the evaluation
+	Pi *big.Int // Proof of correct evaluation
+}
+
+// GenerateModulus creates an RSA modulus of the given bit length from two
+// random primes read from r, which defaults to crypto/rand.Reader if nil. The
+// primes are discarded, but the caller has to trust that this was the case.
+func GenerateModulus(r io.Reader, bits int) (*big.Int, error) {
+	if r == nil {
+		r = rand.Reader
+	}
+	p, err := rand.Prime(r, bits/2)
+	if err != nil {
+		return nil, err
+	}
+	q, err := rand.Prime(r, bits-bits/2)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).Mul(p, q), nil
+}
+
+// New creates a VDF with modulus N and delay parameter T.
+func New(N *big.Int, T uint64) *VDF {
+	return &VDF{N, T}
+}
+
+// Eval evaluates the VDF on input x, which is first hashed into the group,
+// and returns the output together with its proof.
+func (v *VDF) Eval(x []byte) *Output {
+	g := v.hashToGroup(x)
+
+	// y = g^(2^T)
+	y := new(big.Int).Set(g)
+	for i := uint64(0); i < v.T; i++ {
+		y.Mul(y, y).Mod(y, v.N)
+	}
+	v.canonicalize(y)
+
+	// pi = g^floor(2^T / l), computed by long division on the fly
+	l := hashPrime(v.N, g, y)
+	pi := big.NewInt(1)
+	r := big.NewInt(1)
+	b := new(big.Int)
+	for i := uint64(0); i < v.T; i++ {
+		r.Lsh(r, 1)
+		b.Div(r, l)
+		r.Mod(r, l)
+		pi.Mul(pi, pi).Mod(pi, v.N)
+		if b.Sign() != 0 {
+			pi.Mul(pi, g).Mod(pi, v.N)
+		}
+	}
+	v.canonicalize(pi)
+	return &Output{y, pi}
+}
+
+// Verify checks that out is the correct evaluation of the VDF on input x by
+// checking pi^l * g^r == y where r = 2^T mod l, up to sign. Outputs whose y
+// or pi are not in canonical form are rejected.
+func (v *VDF) Verify(x []byte, out *Output) error {
+	if out == nil || out.Y == nil || out.Pi == nil {
+		return errorInvalidOutput
+	}
+	if !v.inGroup(out.Y) || !v.inGroup(out.Pi) || !v.canonical(out.Y) || !v.canonical(out.Pi) {
+		return errorInvalidOutput
+	}
+	g := v.hashToGroup(x)
+	l := hashPrime(v.N, g, out.Y)
+	T := new(big.Int).SetUint64(v.T)
+	r := new(big.Int).Exp(two, T, l)
+
+	lhs := new(big.Int).Exp(out.Pi, l, v.N)
+	lhs.Mul(lhs, new(big.Int).Exp(g, r, v.N)).Mod(lhs, v.N)
+	v.canonicalize(lhs)
+	if lhs.Cmp(out.Y) != 0 {
+		return errorInvalidProof
+	}
+	return nil
+}
+
+// Bytes returns a digest of the VDF output suitable as random seed.
+func (o *Output) Bytes() []byte {
+	h := sha256.Sum256(o.Y.Bytes())
+	return h[:]
+}
+
+func (v *VDF) inGroup(a *big.Int) bool {
+	if a.Sign() <= 0 || a.Cmp(v.N) >= 0 {
+		return false
+	}
+	return new(big.Int).GCD(nil, nil, a, v.N).Cmp(one) == 0
+}
+
+// canonical reports whether a is the representative min(a, N-a) of its class
+// in Z_N^*/{1, -1}.
+func (v *VDF) canonical(a *big.Int) bool {
+	return new(big.Int).Sub(v.N, a).Cmp(a) > 0
+}
+
+// canonicalize replaces a by its representative min(a, N-a).
+func (v *VDF) canonicalize(a *big.Int) {
+	if !v.canonical(a) {
+		a.Sub(v.N, a)
+	}
+}
+
+// hashToGroup maps x to an element of Z_N^* by expanding its hash to
+// slightly more bits than N and reducing modulo N.
+func (v *VDF) hashToGroup(x []byte) *big.Int {
+	size := (v.N.BitLen()+7)/8 + 16
+	buf := expand([]byte("vdf-h2g"), size, v.N.Bytes(), x)
+	g := new(big.Int).SetBytes(buf)
+	g.Mod(g, v.N)
+	for !v.inGroup(g) {
+		g.Add(g, one).Mod(g, v.N)
+	}
+	return g
+}
+
+// hashPrime derives the Fiat-Shamir challenge prime from the statement.
+func hashPrime(N, g, y *big.Int) *big.Int {
+	l := new(big.Int)
+	var ctr [8]byte
+	for i := uint64(0); ; i++ {
+		binary.BigEndian.PutUint64(ctr[:], i)
+		buf := expand([]byte("vdf-prime"), primeBits/8, N.Bytes(), g.Bytes(), y.Bytes(), ctr[:])
+		buf[0] |= 0x80
+		l.SetBytes(buf)
+		if l.ProbablyPrime(20) {
+			return l
+		}
+	}
+}
+
+// expand hashes the length-prefixed inputs with SHA-256 in counter mode to
+// produce size bytes of output.
+func expand(tag []byte, size int, data ...[]byte) []byte {
+	out := make([]byte, 0, size+sha256.Size)
+	var ctr, ln [4]byte
+	for i := uint32(0); len(out) < size; i++ {
+		h := sha256.New()
+		binary.BigEndian.PutUint32(ctr[:], i)
+		h.Write(ctr[:])
+		h.Write(tag)
+		for _, d := range data {
+			binary.BigEndian.PutUint32(ln[:], uint32(len(d)))
+			h.Write(ln[:])
+			h.Write(d)
+		}
+		out = h.Sum(out)
+	}
+	return out[:size]
+}
@@ -0,0 +1,60 @@
+package vdf
+
+import (
+	"math/big"
+	"testing"
+)
+
+func newTestVDF(t *testing.T) *VDF {
+	N, err := GenerateModulus(nil, 512)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return New(N, 1000)
+}
+
+func TestVDF(t *testing.T) {
+	v := newTestVDF(t)
+	x := []byte("beacon round 1")
+	out := v.Eval(x)
+	if err := v.Verify(x, out); err != nil {
+		t.Fatal(err)
+	}
+
+	// Expected output computed the slow way
+	g := v.hashToGroup(x)
+	e := new(big.Int).Lsh(one, uint(v.T))
+	y := new(big.Int).Exp(g, e, v.N)
+	v.canonicalize(y)
+	if y.Cmp(out.Y) != 0 {
+		t.Fatal("wrong VDF output")
+	}
+}
+
+func TestVDFInvalid(t *testing.T) {
+	v := newTestVDF(t)
+	x := []byte("beacon round 2")
+	out := v.Eval(x)
+
+	if err := v.Verify([]byte("other input"), out); err == nil {
+		t.Fatal("proof verified for a different input")
+	}
+	bad := &Output{new(big.Int).Add(out.Y, one), out.Pi}
+	if err := v.Verify(x, bad); err == nil {
+		t.Fatal("wrong output verified")
+	}
+	bad = &Output{out.Y, new(big.Int).Add(out.Pi, one)}
+	if err := v.Verify(x, bad); err == nil {
+		t.Fatal("wrong proof verified")
+	}
+	if err := v.Verify(x, &Output{out.Y, v.N}); err == nil {
+		t.Fatal("out of range proof verified")
+	}
+
+	// (-y, -pi) satisfies the verification equation as well but is not in
+	// canonical form
+	neg := &Output{new(big.Int).Sub(v.N, out.Y), new(big.Int).Sub(v.N, out.Pi)}
+	if err := v.Verify(x, neg); err != errorInvalidOutput {
+		t.Fatal("negated output verified")
+	}
+}
@@ -0,0 +1,124 @@
+// Package vote composes ElGamal encryption, verifiable shuffles and threshold
+// decryption into a verifiable voting toolkit. An election runs in three
+// phases:
+//
+// 1. Voting: each voter encrypts its choice v among a number of options as an
+// exponential ElGamal ciphertext (K, C) = (rG, vG + rX) under the election key
+// X, held in shares by a set of trustees, and attaches a disjunctive proof
+// that the ciphertext encrypts one of the valid options. The proof is bound to
+// the voter's identifier so that ballots cannot be copied.
+//
+// 2. Mixing: one or more mixers shuffle and re-randomize the list of valid
+// ballots with a Neff shuffle and publish a proof of correct shuffling, which
+// breaks the link between voters and ciphertexts.
+//
+// 3. Tallying: each trustee publishes partial decryptions of the mixed
+// ciphertexts together with proofs of correct decryption. Any threshold of
+// valid partial decryptions suffices to decrypt and count the ballots.
+//
+// Every step is publicly verifiable.
+package vote
+
+import (
+	"crypto/cipher"
+	"errors"
+
+	"github.com/dedis/crypto/abstract"
+)
+
+// Some error definitions
+var errorChoice = errors.New("choice out of range")
+var errorBallotProof = errors.New("invalid ballot proof")
+
+// Ballot is an encrypted vote together with its well-formedness proof.
+type Ballot struct {
+	K abstract.Point    // ElGamal ephemeral key rG
+	C abstract.Point    // ElGamal ciphertext vG + rX
+	E []abstract.Scalar // Per-option challenges of the disjunctive proof
+	Z []abstract.Scalar // Per-option responses of the disjunctive proof
+}
+
+// EncryptBallot encrypts the given choice out of the number of options under
+// the election key X and proves that the ballot is well-formed. The voter
+// identifier is bound into the proof.
+func EncryptBallot(suite abstract.Suite, X abstract.Point, voter []byte, choice, options int, rand cipher.Stream) (*Ballot, error) {
+	if choice < 0 || choice >= options {
+		return nil, errorChoice
+	}
+	r := suite.Scalar().Pick(rand)
+	b := &Ballot{
+		K: suite.Point().Mul(nil, r),
+		C: suite.Point().Add(suite.Point().Mul(nil, suite.Scalar().SetInt64(int64(choice))), suite.Point().Mul(X, r)),
+		E: make([]abstract.Scalar, options),
+		Z: make([]abstract.Scalar, options),
+	}
+
+	// Simulate the proofs for all other options, prove the real one
+	A := make([]abstract.Point, options)
+	B := make([]abstract.Point, options)
+	w := suite.Scalar().Pick(rand)
+	sum := suite.Scalar().Zero()
+	for j := 0; j < options; j++ {
+		if j == choice {
+			A[j] = suite.Point().Mul(nil, w)
+			B[j] = suite.Point().Mul(X, w)
+			continue
+		}
+		b.E[j] = suite.Scalar().Pick(rand)
+		b.Z[j] = suite.Scalar().Pick(rand)
+		A[j], B[j] = b.commitments(suite, X, j)
+		sum.Add(sum, b.E[j])
+	}
+	c, err := b.challenge(suite, X, voter, A, B)
+	if err != nil {
+		return nil, err
+	}
+	b.E[choice] = suite.Scalar().Sub(c, sum)
+	b.Z[choice] = suite.Scalar().Sub(w, suite.Scalar().Mul(b.E[choice], r))
+	return b, nil
+}
+
+// commitments recomputes the commitments A_j = z_jG + e_jK and
+// B_j = z_jX + e_j(C - jG) of option j.
+func (b *Ballot) commitments(suite abstract.Suite, X abstract.Point, j int) (abstract.Point, abstract.Point) {
+	A := suite.Point().Add(suite.Point().Mul(nil, b.Z[j]), suite.Point().Mul(b.K, b.E[j]))
+	M := suite.Point().Sub(b.C, suite.Point().Mul(nil, suite.Scalar().SetInt64(int64(j))))
+	B := suite.Point().Add(suite.Point().Mul(X, b.Z[j]), suite.Point().Mul(M, b.E[j]))
+	return A, B
+}
+
+func (b *Ballot) challenge(suite abstract.Suite, X abstract.Point, voter []byte, A, B []abstract.Point) (abstract.Scalar, error) {
+	h := suite.Hash()
+	h.Write([]byte("vote-ballot"))
+	h.Write(voter)
+	points := append([]abstract.Point{X, b.K, b.C}, A...)
+	for _, P := range append(points, B...) {
+		if _, err := P.MarshalTo(h); err != nil {
+			return nil, err
+		}
+	}
+	return suite.Scalar().Pick(suite.Cipher(h.Sum(nil))), nil
+}
+
+// VerifyBallot checks that the ballot of the given voter encrypts one of the
+// valid options under the election key X.
+func VerifyBallot(suite abstract.Suite, X abstract.Point, voter []byte, options int, b *Ballot) error {
+	if b == nil || b.K == nil || b.C == nil || len(b.E) != options || len(b.Z) != options {
+		return errorBallotProof
+	}
+	A := make([]abstract.Point, options)
+	B := make([]abstract.Point, options)
+	sum := suite.Scalar().Zero()
+	for j := 0; j < options; j++ {
+		A[j], B[j] = b.commitments(suite, X, j)
+		sum.Add(sum, b.E[j])
+	}
+	c, err := b.challenge(suite, X, voter, A, B)
+	if err != nil {
+		return err
+	}
+	if !c.Equal(sum) {
+		return errorBallotProof
+	}
+	return nil
+}
@@ -0,0 +1,174 @@
+package vote
+
+import (
+	"crypto/cipher"
+	"encoding/binary"
+	"errors"
+
+	"github.com/dedis/crypto/abstract"
+	"github.com/dedis/crypto/proof"
+	"github.com/dedis/crypto/share"
+	"github.com/dedis/crypto/shuffle"
+)
+
+// Some error definitions
+var errorDifferentLengths = errors.New("inputs of different lengths")
+var errorDecVerification = errors.New("verification of partial decryption failed")
+var errorTooFewShares = errors.New("not enough valid partial decryptions")
+var errorInvalidVote = errors.New("decrypted vote is not a valid option")
+
+const mixProtocol = "vote-mix"
+
+// Mix is the output of a verifiable shuffle of ElGamal ciphertexts.
+type Mix struct {
+	K     []abstract.Point // Shuffled ephemeral keys
+	C     []abstract.Point // Shuffled ciphertexts
+	Proof []byte           // Non-interactive shuffle proof
+}
+
+// Ciphertexts extracts the ElGamal pairs of the ballots, which are typically
+// fed into the first mix.
+func Ciphertexts(ballots []*Ballot) ([]abstract.Point, []abstract.Point) {
+	K := make([]abstract.Point, len(ballots))
+	C := make([]abstract.Point, len(ballots))
+	for i, b := range ballots {
+		K[i] = b.K
+		C[i] = b.C
+	}
+	return K, C
+}
+
+// Shuffle shuffles and re-randomizes the ciphertexts (K, C) under the
+// election key X and proves the correctness of the shuffle.
+func Shuffle(suite abstract.Suite, X abstract.Point, K, C []abstract.Point, rand cipher.Stream) (*Mix, error) {
+	if len(K) != len(C) {
+		return nil, errorDifferentLengths
+	}
+	Kbar, Cbar, prover := shuffle.Shuffle(suite, nil, X, K, C, rand)
+	prf, err := proof.HashProve(suite, mixProtocol, suite.Cipher(abstract.RandomKey), prover)
+	if err != nil {
+		return nil, err
+	}
+	return &Mix{Kbar, Cbar, prf}, nil
+}
+
+// VerifyShuffle checks that the mix is a valid shuffle of the ciphertexts
+// (K, C) under the election key X.
+func VerifyShuffle(suite abstract.Suite, X abstract.Point, K, C []abstract.Point, mix *Mix) error {
+	if len(K) != len(C) || len(mix.K) != len(K) || len(mix.C) != len(C) {
+		return errorDifferentLengths
+	}
+	verifier := shuffle.Verifier(suite, nil, X, K, C, mix.K, mix.C)
+	return proof.HashVerify(suite, mixProtocol, verifier, mix.Proof)
+}
+
+// PartialDecryption is a trustee's decryption share x_iK of every ciphertext
+// together with proofs that log_G(X_i) == log_K(x_iK). Each proof is tagged
+// with the trustee's index, the ciphertext's position and its ephemeral key
+// K, so it cannot be reused for another trustee or ciphertext.
+type PartialDecryption struct {
+	I      int                // Index of the trustee's key share
+	D      []abstract.Point   // Decryption shares
+	Proofs []*proof.DLEQProof // Decryption consistency proofs
+}
+
+// PartiallyDecrypt computes the trustee's decryption shares of the ephemeral
+// keys K of the final mix.
+func PartiallyDecrypt(suite abstract.Suite, xi *share.PriShare, K []abstract.Point) (*PartialDecryption, error) {
+	pd := &PartialDecryption{xi.I, make([]abstract.Point, len(K)), make([]*proof.DLEQProof, len(K))}
+	G := suite.Point().Base()
+	for j := range K {
+		tag, err := decryptionTag(xi.I, j, K[j])
+		if err != nil {
+			return nil, err
+		}
+		if pd.Proofs[j], _, pd.D[j], err = proof.NewDLEQProofTagged(suite, G, K[j], xi.V, tag); err != nil {
+			return nil, err
+		}
+	}
+	return pd, nil
+}
+
+// decryptionTag returns the tag of the decryption consistency proof of
+// trustee i for the ciphertext at position j with ephemeral key K.
+func decryptionTag(i, j int, K abstract.Point) ([]byte, error) {
+	buf, err := K.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	tag := binary.BigEndian.AppendUint32([]byte("vote-decrypt"), uint32(i))
+	tag = binary.BigEndian.AppendUint32(tag, uint32(j))
+	return append(tag, buf...), nil
+}
+
+// VerifyPartialDecryption checks a partial decryption against the trustee's
+// public key share taken from the public commitment polynomial.
+func VerifyPartialDecryption(suite abstract.Suite, pubPoly *share.PubPoly, K []abstract.Point, pd *PartialDecryption) error {
+	if len(pd.D) != len(K) || len(pd.Proofs) != len(K) {
+		return errorDifferentLengths
+	}
+	Xi := pubPoly.Eval(pd.I).V
+	G := suite.Point().Base()
+	for j := range K {
+		tag, err := decryptionTag(pd.I, j, K[j])
+		if err != nil {
+			return err
+		}
+		if pd.Proofs[j] == nil || pd.Proofs[j].VerifyTagged(suite, G, K[j], Xi, pd.D[j], tag) != nil {
+			return errorDecVerification
+		}
+	}
+	return nil
+}
+
+// Tally verifies the partial decryptions of the final mix, decrypts every
+// ballot using a threshold t of valid ones and returns the number of votes
+// per option. Only the first valid partial decryption of every trustee
+// index in [0, n) is used.
+func Tally(suite abstract.Suite, pubPoly *share.PubPoly, mix *Mix, partials []*PartialDecryption, options, t, n int) ([]int, error) {
+	var good []*PartialDecryption
+	seen := make(map[int]bool)
+	for _, pd := range partials {
+		if pd == nil || pd.I < 0 || pd.I >= n || seen[pd.I] {
+			continue
+		}
+		if VerifyPartialDecryption(suite, pubPoly, mix.K, pd) == nil {
+			seen[pd.I] = true
+			good = append(good, pd)
+		}
+	}
+	if len(good) < t {
+		return nil, errorTooFewShares
+	}
+
+	// Precompute the encodings of all options
+	encoded := make([]abstract.Point, options)
+	for v := range encoded {
+		encoded[v] = suite.Point().Mul(nil, suite.Scalar().SetInt64(int64(v)))
+	}
+
+	counts := make([]int, options)
+	shares := make([]*share.PubShare, len(good))
+	for j := range mix.K {
+		for i, pd := range good {
+			shares[i] = &share.PubShare{I: pd.I, V: pd.D[j]}
+		}
+		xK, err := share.RecoverCommit(suite, shares, t, n)
+		if err != nil {
+			return nil, err
+		}
+		M := suite.Point().Sub(mix.C[j], xK)
+		v := -1
+		for o, E := range encoded {
+			if M.Equal(E) {
+				v = o
+				break
+			}
+		}
+		if v < 0 {
+			return nil, errorInvalidVote
+		}
+		counts[v]++
+	}
+	return counts, nil
+}
@@ -0,0 +1,118 @@
+package vote
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/dedis/crypto/abstract"
+	"github.com/dedis/crypto/edwards"
+	"github.com/dedis/crypto/proof"
+	"github.com/dedis/crypto/random"
+	"github.com/dedis/crypto/share"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var suite = edwards.NewAES128SHA256Ed25519(false)
+
+func TestBallot(t *testing.T) {
+	X := suite.Point().Mul(nil, suite.Scalar().Pick(random.Stream))
+	options := 3
+	for c := 0; c < options; c++ {
+		b, err := EncryptBallot(suite, X, []byte("alice"), c, options, random.Stream)
+		require.Nil(t, err)
+		assert.Nil(t, VerifyBallot(suite, X, []byte("alice"), options, b))
+		assert.Error(t, VerifyBallot(suite, X, []byte("bob"), options, b))
+	}
+	_, err := EncryptBallot(suite, X, []byte("alice"), options, options, random.Stream)
+	assert.Error(t, err)
+
+	// A ballot for an invalid option cannot be proven
+	b, err := EncryptBallot(suite, X, []byte("alice"), 0, options, random.Stream)
+	require.Nil(t, err)
+	b.C = suite.Point().Add(b.C, suite.Point().Mul(nil, suite.Scalar().SetInt64(5)))
+	assert.Error(t, VerifyBallot(suite, X, []byte("alice"), options, b))
+}
+
+func TestElection(t *testing.T) {
+	n, th := 5, 3
+	priPoly := share.NewPriPoly(suite, th, nil, random.Stream)
+	keys := priPoly.Shares(n)
+	pubPoly := priPoly.Commit(nil)
+	X := pubPoly.Commit()
+
+	options := 3
+	choices := []int{0, 2, 1, 2, 2, 0}
+	var ballots []*Ballot
+	for i, c := range choices {
+		voter := []byte(fmt.Sprintf("voter%d", i))
+		b, err := EncryptBallot(suite, X, voter, c, options, random.Stream)
+		require.Nil(t, err)
+		require.Nil(t, VerifyBallot(suite, X, voter, options, b))
+		ballots = append(ballots, b)
+	}
+
+	// Two mixers in sequence
+	K, C := Ciphertexts(ballots)
+	mix1, err := Shuffle(suite, X, K, C, random.Stream)
+	require.Nil(t, err)
+	require.Nil(t, VerifyShuffle(suite, X, K, C, mix1))
+	mix2, err := Shuffle(suite, X, mix1.K, mix1.C, random.Stream)
+	require.Nil(t, err)
+	require.Nil(t, VerifyShuffle(suite, X, mix1.K, mix1.C, mix2))
+	assert.Error(t, VerifyShuffle(suite, X, K, C, mix2))
+
+	partials := make([]*PartialDecryption, n)
+	for i, xi := range keys {
+		partials[i], err = PartiallyDecrypt(suite, xi, mix2.K)
+		require.Nil(t, err)
+		require.Nil(t, VerifyPartialDecryption(suite, pubPoly, mix2.K, partials[i]))
+	}
+	// One cheating and one absent trustee
+	partials[0].D[0] = suite.Point().Base()
+	partials[3] = nil
+
+	counts, err := Tally(suite, pubPoly, mix2, partials, options, th, n)
+	require.Nil(t, err)
+	assert.Equal(t, []int{2, 1, 3}, counts)
+
+	// A trustee replaying its partial decryption neither counts twice nor
+	// blocks the tally
+	replayed := []*PartialDecryption{partials[1], partials[1], partials[2]}
+	_, err = Tally(suite, pubPoly, mix2, replayed, options, th, n)
+	assert.Equal(t, errorTooFewShares, err)
+	counts, err = Tally(suite, pubPoly, mix2, append(replayed, partials[4]), options, th, n)
+	require.Nil(t, err)
+	assert.Equal(t, []int{2, 1, 3}, counts)
+}
+
+func TestForgedPartialDecryption(t *testing.T) {
+	n, th := 3, 2
+	priPoly := share.NewPriPoly(suite, th, nil, random.Stream)
+	keys := priPoly.Shares(n)
+	pubPoly := priPoly.Commit(nil)
+	K := []abstract.Point{
+		suite.Point().Mul(nil, suite.Scalar().Pick(random.Stream)),
+		suite.Point().Mul(nil, suite.Scalar().Pick(random.Stream)),
+	}
+	pd, err := PartiallyDecrypt(suite, keys[1], K)
+	require.Nil(t, err)
+	require.Nil(t, VerifyPartialDecryption(suite, pubPoly, K, pd))
+
+	// A decryption share of garbage with a proof whose challenge is chosen
+	// instead of computed passes DLEQProof.Verify but not the tagged check
+	G := suite.Point().Base()
+	Xi := pubPoly.Eval(1).V
+	D := suite.Point().Mul(nil, suite.Scalar().Pick(random.Stream))
+	c := suite.Scalar().Pick(random.Stream)
+	r := suite.Scalar().Pick(random.Stream)
+	forged := &proof.DLEQProof{
+		C:  c,
+		R:  r,
+		VG: suite.Point().Add(suite.Point().Mul(G, r), suite.Point().Mul(Xi, c)),
+		VH: suite.Point().Add(suite.Point().Mul(K[0], r), suite.Point().Mul(D, c)),
+	}
+	require.Nil(t, forged.Verify(suite, G, K[0], Xi, D))
+	bad := &PartialDecryption{pd.I, []abstract.Point{D, pd.D[1]}, []*proof.DLEQProof{forged, pd.Proofs[1]}}
+	assert.Equal(t, errorDecVerification, VerifyPartialDecryption(suite, pubPoly, K, bad))
+}